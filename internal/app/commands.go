@@ -80,6 +80,24 @@ type FocusPluginByIDMsg struct {
 	PluginID string
 }
 
+// OpenFileHistoryMsg is broadcast to plugins to open the git history of a
+// file. Path is relative to the project directory.
+type OpenFileHistoryMsg struct {
+	Path string
+}
+
+// ApplyPatchMsg is broadcast to plugins to preview and apply a patch or
+// mailbox file. Path is relative to the project directory.
+type ApplyPatchMsg struct {
+	Path string
+}
+
+// OpenSubmoduleMsg is broadcast to plugins to show a submodule as a nested
+// repository in git status. Path is relative to the project directory.
+type OpenSubmoduleMsg struct {
+	Path string
+}
+
 // SwitchWorktreeMsg requests switching to a different worktree.
 // Used by the worktree switcher modal and workspace plugin "Open in Git Tab" command.
 type SwitchWorktreeMsg struct {
@@ -9,6 +9,7 @@ type Config struct {
 	Keymap   KeymapConfig   `json:"keymap"`
 	UI       UIConfig       `json:"ui"`
 	Features FeaturesConfig `json:"features"`
+	Forges   ForgesConfig   `json:"forges"`
 }
 
 // FeaturesConfig holds feature flag settings.
@@ -37,12 +38,16 @@ type PluginsConfig struct {
 	Conversations ConversationsPluginConfig `json:"conversations"`
 	Workspace     WorkspacePluginConfig     `json:"workspace"`
 	Notes         NotesPluginConfig         `json:"notes"`
+	FileBrowser   FileBrowserPluginConfig   `json:"file-browser"`
 }
 
 // GitStatusPluginConfig configures the git status plugin.
 type GitStatusPluginConfig struct {
 	Enabled         bool          `json:"enabled"`
 	RefreshInterval time.Duration `json:"refreshInterval"`
+	// ConventionalCommits lints commit subjects in the commit modal against
+	// the Conventional Commits format. Default: false.
+	ConventionalCommits bool `json:"conventionalCommits"`
 }
 
 // TDMonitorPluginConfig configures the TD monitor plugin.
@@ -89,6 +94,24 @@ type NotesPluginConfig struct {
 	DefaultEditor string `json:"defaultEditor,omitempty"`
 }
 
+// FileBrowserPluginConfig configures the file browser plugin.
+type FileBrowserPluginConfig struct {
+	// Trash moves deleted files to .sidecar/trash in the project so they can
+	// be restored. When false, deletes are permanent. Default: true.
+	Trash bool `json:"trash"`
+	// TrashRetention is how long trashed items are kept; older items are
+	// purged when the plugin starts. Zero keeps them until purged by hand.
+	// Default: 720h (30 days).
+	TrashRetention time.Duration `json:"trashRetention"`
+}
+
+// ForgesConfig configures code forge detection for git remotes.
+type ForgesConfig struct {
+	// Hosts maps self-hosted forge hosts to their type: "github", "gitlab",
+	// "gitea" or "forgejo". Example: {"git.example.com": "gitlab"}
+	Hosts map[string]string `json:"hosts"`
+}
+
 // KeymapConfig holds key binding overrides.
 type KeymapConfig struct {
 	Overrides map[string]string `json:"overrides"`
@@ -133,6 +156,10 @@ func Default() *Config {
 				DirPrefix:           true,
 				TmuxCaptureMaxBytes: 2 * 1024 * 1024,
 			},
+			FileBrowser: FileBrowserPluginConfig{
+				Trash:          true,
+				TrashRetention: 30 * 24 * time.Hour,
+			},
 		},
 		Keymap: KeymapConfig{
 			Overrides: make(map[string]string),
@@ -147,6 +174,9 @@ func Default() *Config {
 		Features: FeaturesConfig{
 			Flags: make(map[string]bool),
 		},
+		Forges: ForgesConfig{
+			Hosts: make(map[string]string),
+		},
 	}
 }
 
@@ -158,6 +188,9 @@ func (c *Config) Validate() error {
 	if c.Plugins.TDMonitor.RefreshInterval < 0 {
 		c.Plugins.TDMonitor.RefreshInterval = 2 * time.Second
 	}
+	if c.Plugins.FileBrowser.TrashRetention < 0 {
+		c.Plugins.FileBrowser.TrashRetention = 30 * 24 * time.Hour
+	}
 	if c.Plugins.Workspace.TmuxCaptureMaxBytes <= 0 {
 		c.Plugins.Workspace.TmuxCaptureMaxBytes = 2 * 1024 * 1024
 	}
@@ -35,6 +35,7 @@ type rawConfig struct {
 	Keymap   KeymapConfig      `json:"keymap"`
 	UI       rawUIConfig       `json:"ui"`
 	Features FeaturesConfig    `json:"features"`
+	Forges   ForgesConfig      `json:"forges"`
 }
 
 type rawUIConfig struct {
@@ -60,6 +61,7 @@ type rawPluginsConfig struct {
 	TDMonitor     rawTDMonitorConfig     `json:"td-monitor"`
 	Conversations rawConversationsConfig `json:"conversations"`
 	Workspace     rawWorkspaceConfig      `json:"workspace"`
+	FileBrowser   rawFileBrowserConfig   `json:"file-browser"`
 }
 
 type rawWorkspaceConfig struct {
@@ -71,9 +73,15 @@ type rawWorkspaceConfig struct {
 	InteractivePasteKey  string `json:"interactivePasteKey"`
 }
 
+type rawFileBrowserConfig struct {
+	Trash          *bool  `json:"trash"`
+	TrashRetention string `json:"trashRetention"`
+}
+
 type rawGitStatusConfig struct {
-	Enabled         *bool  `json:"enabled"`
-	RefreshInterval string `json:"refreshInterval"`
+	Enabled             *bool  `json:"enabled"`
+	RefreshInterval     string `json:"refreshInterval"`
+	ConventionalCommits *bool  `json:"conventionalCommits"`
 }
 
 type rawTDMonitorConfig struct {
@@ -165,6 +173,9 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 			cfg.Plugins.GitStatus.RefreshInterval = d
 		}
 	}
+	if raw.Plugins.GitStatus.ConventionalCommits != nil {
+		cfg.Plugins.GitStatus.ConventionalCommits = *raw.Plugins.GitStatus.ConventionalCommits
+	}
 
 	// TD Monitor
 	if raw.Plugins.TDMonitor.Enabled != nil {
@@ -207,6 +218,16 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 		cfg.Plugins.Workspace.InteractivePasteKey = raw.Plugins.Workspace.InteractivePasteKey
 	}
 
+	// File Browser
+	if raw.Plugins.FileBrowser.Trash != nil {
+		cfg.Plugins.FileBrowser.Trash = *raw.Plugins.FileBrowser.Trash
+	}
+	if raw.Plugins.FileBrowser.TrashRetention != "" {
+		if d, err := time.ParseDuration(raw.Plugins.FileBrowser.TrashRetention); err == nil {
+			cfg.Plugins.FileBrowser.TrashRetention = d
+		}
+	}
+
 	// Keymap
 	if raw.Keymap.Overrides != nil {
 		for k, v := range raw.Keymap.Overrides {
@@ -249,6 +270,11 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 			cfg.Features.Flags[k] = v
 		}
 	}
+
+	// Forges
+	for k, v := range raw.Forges.Hosts {
+		cfg.Forges.Hosts[k] = v
+	}
 }
 
 // ExpandPath expands ~ to home directory.
@@ -39,8 +39,15 @@ func TestLoadFrom_ValidJSON(t *testing.T) {
 		"plugins": {
 			"git-status": {
 				"enabled": false,
-				"refreshInterval": "5s"
+				"refreshInterval": "5s",
+				"conventionalCommits": true
+			},
+			"file-browser": {
+				"trashRetention": "168h"
 			}
+		},
+		"forges": {
+			"hosts": {"git.example.com": "gitlab"}
 		}
 	}`)
 
@@ -59,6 +66,15 @@ func TestLoadFrom_ValidJSON(t *testing.T) {
 	if cfg.Plugins.GitStatus.RefreshInterval != 5*time.Second {
 		t.Errorf("got refresh %v, want 5s", cfg.Plugins.GitStatus.RefreshInterval)
 	}
+	if !cfg.Plugins.GitStatus.ConventionalCommits {
+		t.Error("git-status conventionalCommits should be enabled")
+	}
+	if cfg.Plugins.FileBrowser.TrashRetention != 7*24*time.Hour || !cfg.Plugins.FileBrowser.Trash {
+		t.Errorf("file-browser = %+v, want 168h retention with trash on", cfg.Plugins.FileBrowser)
+	}
+	if got := cfg.Forges.Hosts["git.example.com"]; got != "gitlab" {
+		t.Errorf("forge host = %q, want gitlab", got)
+	}
 	// Default values should still be present
 	if !cfg.Plugins.TDMonitor.Enabled {
 		t.Error("td-monitor should still be enabled (default)")
@@ -28,11 +28,13 @@ type savePluginsConfig struct {
 	TDMonitor     saveTDMonitorConfig     `json:"td-monitor,omitempty"`
 	Conversations saveConversationsConfig `json:"conversations,omitempty"`
 	Workspace     saveWorkspaceConfig      `json:"workspace,omitempty"`
+	FileBrowser   saveFileBrowserConfig   `json:"file-browser,omitempty"`
 }
 
 type saveGitStatusConfig struct {
-	Enabled         *bool  `json:"enabled,omitempty"`
-	RefreshInterval string `json:"refreshInterval,omitempty"`
+	Enabled             *bool  `json:"enabled,omitempty"`
+	RefreshInterval     string `json:"refreshInterval,omitempty"`
+	ConventionalCommits bool   `json:"conventionalCommits,omitempty"`
 }
 
 type saveTDMonitorConfig struct {
@@ -55,6 +57,11 @@ type saveWorkspaceConfig struct {
 	InteractivePasteKey  string `json:"interactivePasteKey,omitempty"`
 }
 
+type saveFileBrowserConfig struct {
+	Trash          *bool  `json:"trash,omitempty"`
+	TrashRetention string `json:"trashRetention,omitempty"`
+}
+
 // toSaveConfig converts Config to the JSON-serializable format.
 func toSaveConfig(cfg *Config) saveConfig {
 	return saveConfig{
@@ -65,8 +72,9 @@ func toSaveConfig(cfg *Config) saveConfig {
 		},
 		Plugins: savePluginsConfig{
 			GitStatus: saveGitStatusConfig{
-				Enabled:         &cfg.Plugins.GitStatus.Enabled,
-				RefreshInterval: cfg.Plugins.GitStatus.RefreshInterval.String(),
+				Enabled:             &cfg.Plugins.GitStatus.Enabled,
+				RefreshInterval:     cfg.Plugins.GitStatus.RefreshInterval.String(),
+				ConventionalCommits: cfg.Plugins.GitStatus.ConventionalCommits,
 			},
 			TDMonitor: saveTDMonitorConfig{
 				Enabled:         &cfg.Plugins.TDMonitor.Enabled,
@@ -85,6 +93,10 @@ func toSaveConfig(cfg *Config) saveConfig {
 				InteractiveCopyKey:   cfg.Plugins.Workspace.InteractiveCopyKey,
 				InteractivePasteKey:  cfg.Plugins.Workspace.InteractivePasteKey,
 			},
+			FileBrowser: saveFileBrowserConfig{
+				Trash:          &cfg.Plugins.FileBrowser.Trash,
+				TrashRetention: cfg.Plugins.FileBrowser.TrashRetention.String(),
+			},
 		},
 		Keymap:   cfg.Keymap,
 		UI:       cfg.UI,
@@ -0,0 +1,5 @@
+// Package forge recognizes the code forge hosting a repository (GitHub,
+// GitLab, or Gitea/Forgejo) from its remote URL and maps commit links and
+// pull/merge request operations onto the forge's web UI and CLI (gh, glab,
+// tea).
+package forge
@@ -0,0 +1,208 @@
+package forge
+
+import (
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// Kind identifies a forge implementation.
+type Kind string
+
+const (
+	GitHub Kind = "github"
+	GitLab Kind = "gitlab"
+	Gitea  Kind = "gitea" // Also covers Forgejo, which shares Gitea's API and CLI
+)
+
+// knownHosts maps public forge hosts to their kind.
+var knownHosts = map[string]Kind{
+	"github.com":   GitHub,
+	"gitlab.com":   GitLab,
+	"gitea.com":    Gitea,
+	"codeberg.org": Gitea,
+}
+
+// Remote is a repository hosted on a forge.
+type Remote struct {
+	Kind  Kind
+	Host  string // Web host, including a port for http(s) remotes
+	Owner string // User or organization; GitLab groups may be nested ("group/sub")
+	Repo  string
+
+	scheme string // Web URL scheme, "https" unless the remote uses http
+}
+
+// ParseKind converts a configured forge name to a Kind. "forgejo" maps to
+// Gitea. Returns "" for unknown names.
+func ParseKind(name string) Kind {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "github":
+		return GitHub
+	case "gitlab":
+		return GitLab
+	case "gitea", "forgejo":
+		return Gitea
+	}
+	return ""
+}
+
+// kindForHost returns the forge kind for a host. Configured hosts win over
+// the well-known public hosts, which win over guessing from the host name.
+func kindForHost(host string, hosts map[string]string) Kind {
+	host = strings.ToLower(host)
+	for h, name := range hosts {
+		if strings.ToLower(h) == host {
+			if k := ParseKind(name); k != "" {
+				return k
+			}
+		}
+	}
+	if k, ok := knownHosts[host]; ok {
+		return k
+	}
+	switch {
+	case strings.Contains(host, "github"):
+		return GitHub
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"):
+		return Gitea
+	}
+	return ""
+}
+
+// ParseRemote parses a git remote URL into a Remote. hosts maps self-hosted
+// host names to forge names ("github", "gitlab", "gitea" or "forgejo").
+// Returns nil if the URL can't be parsed or the host isn't a known forge.
+//
+// Supported forms: git@host:owner/repo.git, ssh://git@host[:port]/owner/repo.git
+// and http(s)://host[:port]/owner/repo.git.
+func ParseRemote(remoteURL string, hosts map[string]string) *Remote {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if remoteURL == "" {
+		return nil
+	}
+
+	var hostname, webHost, path string
+	scheme := "https"
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.Hostname() == "" {
+			return nil
+		}
+		hostname = u.Hostname()
+		webHost = hostname
+		switch u.Scheme {
+		case "http", "https":
+			// The port of a web remote is the web UI's port; an ssh port isn't
+			webHost = u.Host
+			scheme = u.Scheme
+		}
+		path = u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		hostPart, p, ok := strings.Cut(remoteURL, ":")
+		if !ok || strings.Contains(hostPart, "/") {
+			return nil
+		}
+		if i := strings.LastIndex(hostPart, "@"); i >= 0 {
+			hostPart = hostPart[i+1:]
+		}
+		hostname, webHost, path = hostPart, hostPart, p
+	}
+
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	i := strings.LastIndex(path, "/")
+	if i <= 0 || i == len(path)-1 {
+		return nil
+	}
+
+	kind := kindForHost(hostname, hosts)
+	if kind == "" {
+		return nil
+	}
+	return &Remote{
+		Kind:   kind,
+		Host:   webHost,
+		Owner:  path[:i],
+		Repo:   path[i+1:],
+		scheme: scheme,
+	}
+}
+
+// RemoteURL returns the URL of the origin remote, or "" if there is none.
+func RemoteURL(workDir string) string {
+	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Detect returns the forge hosting workDir's origin remote, or nil if there
+// is no origin or its host isn't a recognized forge.
+func Detect(workDir string, hosts map[string]string) *Remote {
+	return ParseRemote(RemoteURL(workDir), hosts)
+}
+
+// Name returns the display name of the forge.
+func (r *Remote) Name() string {
+	switch r.Kind {
+	case GitLab:
+		return "GitLab"
+	case Gitea:
+		return "Gitea"
+	default:
+		return "GitHub"
+	}
+}
+
+// CLI returns the command-line client used for pull request operations.
+func (r *Remote) CLI() string {
+	switch r.Kind {
+	case GitLab:
+		return "glab"
+	case Gitea:
+		return "tea"
+	default:
+		return "gh"
+	}
+}
+
+// RequestName returns the forge's short name for a pull request: "MR" on
+// GitLab, "PR" elsewhere.
+func (r *Remote) RequestName() string {
+	if r.Kind == GitLab {
+		return "MR"
+	}
+	return "PR"
+}
+
+// WebURL returns the repository's web page. Empty for remotes without a
+// parsed host, such as the GitHub fallback for unrecognized hosts.
+func (r *Remote) WebURL() string {
+	if r.Host == "" {
+		return ""
+	}
+	scheme := r.scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/" + r.Owner + "/" + r.Repo
+}
+
+// CommitURL returns the web page of a commit.
+func (r *Remote) CommitURL(hash string) string {
+	base := r.WebURL()
+	if base == "" {
+		return ""
+	}
+	if r.Kind == GitLab {
+		return base + "/-/commit/" + hash
+	}
+	return base + "/commit/" + hash
+}
@@ -0,0 +1,93 @@
+package forge
+
+import "testing"
+
+func TestParseRemote(t *testing.T) {
+	hosts := map[string]string{"git.example.com": "gitlab", "code.example.org": "Forgejo"}
+	tests := []struct {
+		url   string
+		kind  Kind
+		host  string
+		owner string
+		repo  string
+	}{
+		{"git@github.com:marcus/sidecar.git", GitHub, "github.com", "marcus", "sidecar"},
+		{"https://github.com/marcus/sidecar", GitHub, "github.com", "marcus", "sidecar"},
+		{"https://gitlab.com/group/sub/project.git", GitLab, "gitlab.com", "group/sub", "project"},
+		{"ssh://git@git.example.com:2222/team/app.git", GitLab, "git.example.com", "team", "app"},
+		{"https://GIT.example.com:8443/team/app/", GitLab, "GIT.example.com:8443", "team", "app"},
+		{"gitea@code.example.org:ops/infra.git", Gitea, "code.example.org", "ops", "infra"},
+		{"https://codeberg.org/forgejo/forgejo.git", Gitea, "codeberg.org", "forgejo", "forgejo"},
+		{"git@gitlab.internal.corp:platform/api.git", GitLab, "gitlab.internal.corp", "platform", "api"},
+	}
+	for _, tt := range tests {
+		r := ParseRemote(tt.url, hosts)
+		if r == nil {
+			t.Errorf("%s: not parsed", tt.url)
+			continue
+		}
+		if r.Kind != tt.kind || r.Host != tt.host || r.Owner != tt.owner || r.Repo != tt.repo {
+			t.Errorf("%s = %+v", tt.url, r)
+		}
+	}
+
+	for _, url := range []string{
+		"",
+		"git@bitbucket.org:team/repo.git",
+		"https://github.com/marcus",
+		"/srv/git/repo.git",
+		"file:///srv/git/repo.git",
+	} {
+		if r := ParseRemote(url, hosts); r != nil {
+			t.Errorf("%q parsed as %+v", url, r)
+		}
+	}
+}
+
+func TestConfiguredHostOverridesGuess(t *testing.T) {
+	// A host name that looks like GitLab but is configured as Gitea
+	r := ParseRemote("git@gitlab-mirror.example.com:a/b.git", map[string]string{"gitlab-mirror.example.com": "gitea"})
+	if r == nil || r.Kind != Gitea {
+		t.Fatalf("got %+v", r)
+	}
+	// Unknown forge names in config are ignored
+	r = ParseRemote("git@gitlab.example.com:a/b.git", map[string]string{"gitlab.example.com": "svn"})
+	if r == nil || r.Kind != GitLab {
+		t.Fatalf("got %+v", r)
+	}
+}
+
+func TestCommitURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:o/r.git", "https://github.com/o/r/commit/abc123"},
+		{"git@gitlab.com:g/sub/r.git", "https://gitlab.com/g/sub/r/-/commit/abc123"},
+		{"http://gitea.local:3000/o/r.git", "http://gitea.local:3000/o/r/commit/abc123"},
+	}
+	for _, tt := range tests {
+		r := ParseRemote(tt.url, nil)
+		if r == nil {
+			t.Fatalf("%s: not parsed", tt.url)
+		}
+		if got := r.CommitURL("abc123"); got != tt.want {
+			t.Errorf("%s: CommitURL = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+
+	if got := (&Remote{Kind: GitHub}).CommitURL("abc"); got != "" {
+		t.Errorf("hostless remote CommitURL = %q", got)
+	}
+}
+
+func TestRemoteNames(t *testing.T) {
+	gl := &Remote{Kind: GitLab}
+	if gl.Name() != "GitLab" || gl.CLI() != "glab" || gl.RequestName() != "MR" {
+		t.Errorf("gitlab names = %s %s %s", gl.Name(), gl.CLI(), gl.RequestName())
+	}
+	gt := &Remote{Kind: Gitea}
+	if gt.Name() != "Gitea" || gt.CLI() != "tea" || gt.RequestName() != "PR" {
+		t.Errorf("gitea names = %s %s %s", gt.Name(), gt.CLI(), gt.RequestName())
+	}
+}
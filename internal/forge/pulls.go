@@ -0,0 +1,401 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PullRequest is an open pull request (merge request on GitLab).
+type PullRequest struct {
+	Number    int
+	Title     string
+	Branch    string // Source branch
+	Author    string
+	URL       string
+	CreatedAt time.Time // Zero if the forge didn't report it
+	Draft     bool
+}
+
+// CommandError is a failed forge CLI invocation.
+type CommandError struct {
+	Command string // e.g. "glab mr list"
+	Output  string // Trimmed stderr, or stdout if stderr was empty
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("%s: %v", e.Command, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Command, e.Output)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// run runs a forge CLI subcommand in dir and returns its stdout. The first
+// two arguments name the subcommand in errors.
+func run(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		out := strings.TrimSpace(stderr.String())
+		if out == "" {
+			out = strings.TrimSpace(string(output))
+		}
+		command := name
+		if len(args) >= 2 {
+			command += " " + args[0] + " " + args[1]
+		}
+		return output, &CommandError{Command: command, Output: out, Err: err}
+	}
+	return output, nil
+}
+
+// ListPullRequests returns up to limit open pull requests of the
+// repository checked out in dir.
+func (r *Remote) ListPullRequests(dir string, limit int) ([]PullRequest, error) {
+	n := strconv.Itoa(limit)
+	switch r.Kind {
+	case GitLab:
+		output, err := run(dir, "glab", "mr", "list", "--output", "json", "--per-page", n)
+		if err != nil {
+			return nil, err
+		}
+		return parseGitLabMRs(output)
+	case Gitea:
+		output, err := run(dir, "tea", "pulls", "list", "--output", "json", "--state", "open",
+			"--fields", "index,title,head,url,created,author", "--limit", n)
+		if err != nil {
+			return nil, err
+		}
+		return parseGiteaPulls(output)
+	default:
+		output, err := run(dir, "gh", "pr", "list",
+			"--json", "number,title,headRefName,url,isDraft,createdAt,author", "--limit", n)
+		if err != nil {
+			return nil, err
+		}
+		return parseGitHubPRs(output)
+	}
+}
+
+func parseGitHubPRs(data []byte) ([]PullRequest, error) {
+	var items []struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		HeadRefName string `json:"headRefName"`
+		URL         string `json:"url"`
+		IsDraft     bool   `json:"isDraft"`
+		CreatedAt   string `json:"createdAt"`
+		Author      struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("parse pr list: %w", err)
+	}
+	prs := make([]PullRequest, 0, len(items))
+	for _, it := range items {
+		prs = append(prs, PullRequest{
+			Number:    it.Number,
+			Title:     it.Title,
+			Branch:    it.HeadRefName,
+			Author:    it.Author.Login,
+			URL:       it.URL,
+			CreatedAt: parseTime(it.CreatedAt),
+			Draft:     it.IsDraft,
+		})
+	}
+	return prs, nil
+}
+
+func parseGitLabMRs(data []byte) ([]PullRequest, error) {
+	var items []struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		SourceBranch string `json:"source_branch"`
+		WebURL       string `json:"web_url"`
+		Draft        bool   `json:"draft"`
+		WorkInProg   bool   `json:"work_in_progress"`
+		CreatedAt    string `json:"created_at"`
+		Author       struct {
+			Username string `json:"username"`
+		} `json:"author"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("parse mr list: %w", err)
+	}
+	prs := make([]PullRequest, 0, len(items))
+	for _, it := range items {
+		prs = append(prs, PullRequest{
+			Number:    it.IID,
+			Title:     it.Title,
+			Branch:    it.SourceBranch,
+			Author:    it.Author.Username,
+			URL:       it.WebURL,
+			CreatedAt: parseTime(it.CreatedAt),
+			Draft:     it.Draft || it.WorkInProg,
+		})
+	}
+	return prs, nil
+}
+
+// giteaPull is a row of tea's JSON output, which renders every field as a
+// string.
+type giteaPull struct {
+	Index   string `json:"index"`
+	Title   string `json:"title"`
+	Head    string `json:"head"`
+	URL     string `json:"url"`
+	Created string `json:"created"`
+	Author  string `json:"author"`
+	State   string `json:"state"`
+}
+
+func parseGiteaRows(data []byte) ([]giteaPull, error) {
+	var rows []giteaPull
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("parse pull list: %w", err)
+	}
+	return rows, nil
+}
+
+func parseGiteaPulls(data []byte) ([]PullRequest, error) {
+	rows, err := parseGiteaRows(data)
+	if err != nil {
+		return nil, err
+	}
+	prs := make([]PullRequest, 0, len(rows))
+	for _, row := range rows {
+		number, _ := strconv.Atoi(strings.TrimPrefix(row.Index, "#"))
+		title := row.Title
+		// Gitea marks drafts with a work-in-progress title prefix
+		draft := false
+		for _, prefix := range []string{"WIP:", "[WIP]"} {
+			if strings.HasPrefix(strings.ToUpper(title), prefix) {
+				draft = true
+			}
+		}
+		prs = append(prs, PullRequest{
+			Number:    number,
+			Title:     title,
+			Branch:    row.Head,
+			Author:    row.Author,
+			URL:       row.URL,
+			CreatedAt: parseTime(row.Created),
+			Draft:     draft,
+		})
+	}
+	return prs, nil
+}
+
+// timeLayouts are the timestamp formats the forge CLIs emit.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04",
+}
+
+func parseTime(s string) time.Time {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// urlPattern matches the first web URL in CLI output.
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// CreatePullRequest opens a pull request from the branch checked out in dir
+// into base and returns its URL. If one already exists for the branch, its
+// URL is returned with existing set.
+func (r *Remote) CreatePullRequest(dir, title, body, base string) (prURL string, existing bool, err error) {
+	var args []string
+	switch r.Kind {
+	case GitLab:
+		args = []string{"mr", "create", "--title", title, "--description", body,
+			"--target-branch", base, "--yes"}
+	case Gitea:
+		args = []string{"pulls", "create", "--title", title, "--description", body, "--base", base}
+	default:
+		args = []string{"pr", "create", "--title", title, "--body", body, "--base", base}
+	}
+
+	output, err := run(dir, r.CLI(), args...)
+	if err != nil {
+		var out string
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) {
+			out = cmdErr.Output
+		}
+		if !strings.Contains(strings.ToLower(out), "already exists") {
+			return "", false, err
+		}
+		// gh names the existing PR in its error; the others need a lookup
+		if u, ok := parseExistingURL(out); ok {
+			return u, true, nil
+		}
+		if u := r.currentPullRequestURL(dir); u != "" {
+			return u, true, nil
+		}
+		return "", false, err
+	}
+
+	// gh prints just the URL; glab and tea print it among other details
+	if u := urlPattern.FindString(string(output)); u != "" {
+		return strings.TrimRight(u, ".,:)"), false, nil
+	}
+	return strings.TrimSpace(string(output)), false, nil
+}
+
+// parseExistingURL extracts the URL of an existing pull request from gh's
+// "already exists" error output.
+func parseExistingURL(output string) (string, bool) {
+	// Error format: "a pull request for branch X into branch Y already exists: <URL>: exit status 1"
+	const marker = "already exists:"
+	idx := strings.Index(output, marker)
+	if idx == -1 {
+		return "", false
+	}
+
+	// Extract URL after marker
+	rest := strings.TrimSpace(output[idx+len(marker):])
+
+	// Find the URL - it starts with http and ends before ": exit" or end of string
+	if !strings.HasPrefix(rest, "http") {
+		return "", false
+	}
+
+	// Find where URL ends - look for ": exit" pattern which follows the URL
+	endIdx := strings.Index(rest, ": exit")
+	if endIdx == -1 {
+		// No ": exit" suffix, URL goes to end (trim whitespace)
+		endIdx = strings.IndexAny(rest, " \t\n")
+		if endIdx == -1 {
+			endIdx = len(rest)
+		}
+	}
+
+	u := strings.TrimSpace(rest[:endIdx])
+	if u == "" {
+		return "", false
+	}
+	return u, true
+}
+
+// currentPullRequestURL returns the URL of the pull request for the branch
+// checked out in dir, or "" if it can't be found.
+func (r *Remote) currentPullRequestURL(dir string) string {
+	switch r.Kind {
+	case GitLab:
+		output, err := run(dir, "glab", "mr", "view", "--output", "json")
+		if err != nil {
+			return ""
+		}
+		var mr struct {
+			WebURL string `json:"web_url"`
+		}
+		if json.Unmarshal(output, &mr) != nil {
+			return ""
+		}
+		return mr.WebURL
+	case Gitea:
+		row := r.giteaPullForBranch(dir, "open")
+		if row == nil {
+			return ""
+		}
+		return row.URL
+	default:
+		output, err := run(dir, "gh", "pr", "view", "--json", "url")
+		if err != nil {
+			return ""
+		}
+		var pr struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(output, &pr) != nil {
+			return ""
+		}
+		return pr.URL
+	}
+}
+
+// giteaPullForBranch finds the pull request in state whose head is the
+// branch checked out in dir. tea has no "view current branch" command.
+func (r *Remote) giteaPullForBranch(dir, state string) *giteaPull {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	branch := strings.TrimSpace(string(out))
+
+	output, err := run(dir, "tea", "pulls", "list", "--output", "json", "--state", state,
+		"--fields", "index,head,url,state")
+	if err != nil {
+		return nil
+	}
+	rows, err := parseGiteaRows(output)
+	if err != nil {
+		return nil
+	}
+	for i := range rows {
+		if rows[i].Head == branch {
+			return &rows[i]
+		}
+	}
+	return nil
+}
+
+// PullRequestMerged reports whether the pull request for the branch
+// checked out in dir has been merged.
+func (r *Remote) PullRequestMerged(dir string) (bool, error) {
+	switch r.Kind {
+	case GitLab:
+		output, err := run(dir, "glab", "mr", "view", "--output", "json")
+		if err != nil {
+			return false, err
+		}
+		var mr struct {
+			State    string `json:"state"`
+			MergedAt string `json:"merged_at"`
+		}
+		if err := json.Unmarshal(output, &mr); err != nil {
+			return false, nil
+		}
+		return mr.MergedAt != "" || mr.State == "merged", nil
+	case Gitea:
+		// Merged pulls are listed as closed with a "merged" state
+		row := r.giteaPullForBranch(dir, "closed")
+		return row != nil && strings.EqualFold(row.State, "merged"), nil
+	default:
+		output, err := run(dir, "gh", "pr", "view", "--json", "state,mergedAt")
+		if err != nil {
+			return false, err
+		}
+		var pr struct {
+			State    string `json:"state"`
+			MergedAt string `json:"mergedAt"`
+		}
+		if err := json.Unmarshal(output, &pr); err != nil {
+			return false, nil
+		}
+		return pr.MergedAt != "" || pr.State == "MERGED", nil
+	}
+}
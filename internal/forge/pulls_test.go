@@ -0,0 +1,248 @@
+package forge
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeCLI installs a shell script named name on PATH that logs its
+// arguments to the returned file and then runs body.
+func fakeCLI(t *testing.T, name, body string) (logPath string) {
+	t.Helper()
+	binDir := t.TempDir()
+	logPath = filepath.Join(binDir, name+".log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + logPath + "'\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// initBranchRepo creates a repo with branch checked out.
+func initBranchRepo(t *testing.T, branch string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", branch},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	return dir
+}
+
+func TestListPullRequests(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("github", func(t *testing.T) {
+		log := fakeCLI(t, "gh", `echo '[{"number":7,"title":"Fix","headRefName":"fix","url":"https://github.com/o/r/pull/7","isDraft":true,"createdAt":"2024-05-01T10:00:00Z","author":{"login":"ann"}}]'`)
+		prs, err := (&Remote{Kind: GitHub}).ListPullRequests(dir, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prs) != 1 || prs[0].Number != 7 || prs[0].Branch != "fix" || prs[0].Author != "ann" || !prs[0].Draft || prs[0].CreatedAt.IsZero() {
+			t.Errorf("prs = %+v", prs)
+		}
+		if got := readLog(t, log); !strings.HasPrefix(got, "pr list --json") || !strings.Contains(got, "--limit 30") {
+			t.Errorf("gh args = %q", got)
+		}
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		log := fakeCLI(t, "glab", `echo '[{"iid":12,"title":"Add API","source_branch":"api","web_url":"https://git.example.com/t/a/-/merge_requests/12","draft":false,"created_at":"2024-05-02T08:30:00.000Z","author":{"username":"bo"}}]'`)
+		prs, err := (&Remote{Kind: GitLab}).ListPullRequests(dir, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prs) != 1 || prs[0].Number != 12 || prs[0].Branch != "api" || prs[0].Author != "bo" || prs[0].URL == "" || prs[0].CreatedAt.IsZero() {
+			t.Errorf("mrs = %+v", prs)
+		}
+		if got := readLog(t, log); !strings.HasPrefix(got, "mr list --output json --per-page 10") {
+			t.Errorf("glab args = %q", got)
+		}
+	})
+
+	t.Run("gitea", func(t *testing.T) {
+		fakeCLI(t, "tea", `echo '[{"index":"3","title":"WIP: docs","head":"docs","url":"https://codeberg.org/o/r/pulls/3","created":"2024-05-03 09:15","author":"cy"}]'`)
+		prs, err := (&Remote{Kind: Gitea}).ListPullRequests(dir, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prs) != 1 || prs[0].Number != 3 || prs[0].Branch != "docs" || prs[0].Author != "cy" || !prs[0].Draft || prs[0].CreatedAt.IsZero() {
+			t.Errorf("pulls = %+v", prs)
+		}
+	})
+
+	t.Run("cli error", func(t *testing.T) {
+		fakeCLI(t, "glab", `echo "glab: not authenticated" >&2; exit 1`)
+		_, err := (&Remote{Kind: GitLab}).ListPullRequests(dir, 30)
+		if err == nil || err.Error() != "glab mr list: glab: not authenticated" {
+			t.Errorf("err = %v", err)
+		}
+	})
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	dir := initBranchRepo(t, "feature")
+
+	t.Run("gitlab", func(t *testing.T) {
+		log := fakeCLI(t, "glab", `echo "Creating merge request for feature into main in team/app"
+echo
+echo "!5 Add feature (feature)"
+echo " https://git.example.com/team/app/-/merge_requests/5"`)
+		url, existing, err := (&Remote{Kind: GitLab}).CreatePullRequest(dir, "Add feature", "Body", "main")
+		if err != nil || existing || url != "https://git.example.com/team/app/-/merge_requests/5" {
+			t.Errorf("got %q, %v, %v", url, existing, err)
+		}
+		got := readLog(t, log)
+		if !strings.Contains(got, "--title Add feature --description Body --target-branch main --yes") {
+			t.Errorf("glab args = %q", got)
+		}
+	})
+
+	t.Run("gitlab existing", func(t *testing.T) {
+		fakeCLI(t, "glab", `case "$1 $2" in
+"mr create") echo "409 Another open merge request already exists for this source branch: !4" >&2; exit 1 ;;
+"mr view") echo '{"iid":4,"web_url":"https://gitlab.com/t/a/-/merge_requests/4","state":"opened"}' ;;
+esac`)
+		url, existing, err := (&Remote{Kind: GitLab}).CreatePullRequest(dir, "t", "b", "main")
+		if err != nil || !existing || url != "https://gitlab.com/t/a/-/merge_requests/4" {
+			t.Errorf("got %q, %v, %v", url, existing, err)
+		}
+	})
+
+	t.Run("github existing", func(t *testing.T) {
+		fakeCLI(t, "gh", `echo 'a pull request for branch "feature" into branch "main" already exists:' >&2
+echo 'https://github.com/o/r/pull/9' >&2; exit 1`)
+		url, existing, err := (&Remote{Kind: GitHub}).CreatePullRequest(dir, "t", "b", "main")
+		if err != nil || !existing || url != "https://github.com/o/r/pull/9" {
+			t.Errorf("got %q, %v, %v", url, existing, err)
+		}
+	})
+
+	t.Run("gitea existing", func(t *testing.T) {
+		fakeCLI(t, "tea", `case "$1 $2" in
+"pulls create") echo "pull request already exists for these targets" >&2; exit 1 ;;
+"pulls list") echo '[{"index":"2","head":"other","url":"https://codeberg.org/o/r/pulls/2"},{"index":"8","head":"feature","url":"https://codeberg.org/o/r/pulls/8"}]' ;;
+esac`)
+		url, existing, err := (&Remote{Kind: Gitea}).CreatePullRequest(dir, "t", "b", "main")
+		if err != nil || !existing || url != "https://codeberg.org/o/r/pulls/8" {
+			t.Errorf("got %q, %v, %v", url, existing, err)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		fakeCLI(t, "tea", `echo "no login found" >&2; exit 1`)
+		_, _, err := (&Remote{Kind: Gitea}).CreatePullRequest(dir, "t", "b", "main")
+		if err == nil || !strings.Contains(err.Error(), "no login found") {
+			t.Errorf("err = %v", err)
+		}
+	})
+}
+
+func TestPullRequestMerged(t *testing.T) {
+	dir := initBranchRepo(t, "feature")
+
+	tests := []struct {
+		name   string
+		kind   Kind
+		cli    string
+		output string
+		want   bool
+	}{
+		{"github merged", GitHub, "gh", `{"state":"MERGED","mergedAt":"2024-05-01T00:00:00Z"}`, true},
+		{"github open", GitHub, "gh", `{"state":"OPEN","mergedAt":""}`, false},
+		{"gitlab merged", GitLab, "glab", `{"state":"merged","merged_at":"2024-05-01T00:00:00Z"}`, true},
+		{"gitlab open", GitLab, "glab", `{"state":"opened","merged_at":null}`, false},
+		{"gitea merged", Gitea, "tea", `[{"index":"8","head":"feature","state":"merged"}]`, true},
+		{"gitea closed", Gitea, "tea", `[{"index":"8","head":"feature","state":"closed"}]`, false},
+		{"gitea other branch", Gitea, "tea", `[{"index":"2","head":"other","state":"merged"}]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCLI(t, tt.cli, "echo '"+tt.output+"'")
+			merged, err := (&Remote{Kind: tt.kind}).PullRequestMerged(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if merged != tt.want {
+				t.Errorf("merged = %v, want %v", merged, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExistingURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		wantURL   string
+		wantFound bool
+	}{
+		{
+			name:      "standard error with PR URL",
+			output:    `a pull request for branch "workspace-improvements" into branch "main" already exists: https://github.com/marcus/sidecar/pull/30: exit status 1`,
+			wantURL:   "https://github.com/marcus/sidecar/pull/30",
+			wantFound: true,
+		},
+		{
+			name:      "error without exit status suffix",
+			output:    `a pull request for branch "feature" into branch "main" already exists: https://github.com/owner/repo/pull/123`,
+			wantURL:   "https://github.com/owner/repo/pull/123",
+			wantFound: true,
+		},
+		{
+			name:      "different error message",
+			output:    `GraphQL: Could not resolve to a Repository with the name 'owner/repo'.`,
+			wantURL:   "",
+			wantFound: false,
+		},
+		{
+			name:      "empty output",
+			output:    ``,
+			wantURL:   "",
+			wantFound: false,
+		},
+		{
+			name:      "already exists but no URL",
+			output:    `a pull request already exists: `,
+			wantURL:   "",
+			wantFound: false,
+		},
+		{
+			name:      "URL with trailing newline",
+			output:    "a pull request already exists: https://github.com/o/r/pull/1\n",
+			wantURL:   "https://github.com/o/r/pull/1",
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, gotFound := parseExistingURL(tt.output)
+			if gotURL != tt.wantURL {
+				t.Errorf("parseExistingURL() url = %q, want %q", gotURL, tt.wantURL)
+			}
+			if gotFound != tt.wantFound {
+				t.Errorf("parseExistingURL() found = %v, want %v", gotFound, tt.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,500 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned for operations a forge's CLI can't perform.
+var ErrUnsupported = errors.New("not supported by this forge's CLI")
+
+// Review decisions, normalized across forges.
+const (
+	DecisionApproved         = "approved"
+	DecisionChangesRequested = "changes_requested"
+	DecisionReviewRequired   = "review_required"
+)
+
+// Check states, normalized across forges.
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckPending = "pending"
+	CheckSkipped = "skipped"
+)
+
+// Check is a CI check or pipeline job on a pull request.
+type Check struct {
+	Name  string
+	State string // CheckPass, CheckFail, CheckPending or CheckSkipped
+	URL   string
+}
+
+// ReviewComment is one comment of a review thread.
+type ReviewComment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// ReviewThread is a review comment with its replies. Path is empty for
+// comments on the pull request as a whole, such as review summaries.
+type ReviewThread struct {
+	Path     string
+	Line     int    // Line in the new file, 0 if unknown or outdated
+	DiffHunk string // Diff context ending at the commented line, if the forge provides it
+	Resolved bool
+	Outdated bool // The commented line no longer exists in the latest diff
+	Comments []ReviewComment
+}
+
+// Review is the review state of a pull request: its decision, checks and
+// comment threads.
+type Review struct {
+	Number   int
+	Title    string
+	URL      string
+	State    string // e.g. "open", "merged", "closed"
+	Decision string // One of the Decision constants, "" if none is required
+	Checks   []Check
+	Threads  []ReviewThread
+}
+
+// PullRequestReview loads the review of the pull request for the branch
+// checked out in dir.
+func (r *Remote) PullRequestReview(dir string) (*Review, error) {
+	switch r.Kind {
+	case GitLab:
+		return gitLabReview(dir)
+	case Gitea:
+		return nil, fmt.Errorf("pull request reviews: %w", ErrUnsupported)
+	default:
+		return gitHubReview(dir)
+	}
+}
+
+// sortThreads orders threads by file and line, with pull request level
+// comments first.
+func sortThreads(threads []ReviewThread) {
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].Path != threads[j].Path {
+			return threads[i].Path < threads[j].Path
+		}
+		return threads[i].Line < threads[j].Line
+	})
+}
+
+// joinPages merges the pages of a paginated API listing into one JSON
+// array. It takes the array of pages from `gh api --paginate --slurp` as
+// well as the pages printed one after another by `glab api --paginate`.
+func joinPages(data []byte) ([]byte, error) {
+	items := []json.RawMessage{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var page []json.RawMessage
+		if err := dec.Decode(&page); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("parse pages: %w", err)
+		}
+		for _, item := range page {
+			if trimmed := bytes.TrimSpace(item); len(trimmed) > 0 && trimmed[0] == '[' {
+				// A slurped page; its items are the entries
+				var entries []json.RawMessage
+				if err := json.Unmarshal(trimmed, &entries); err != nil {
+					return nil, fmt.Errorf("parse pages: %w", err)
+				}
+				items = append(items, entries...)
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return json.Marshal(items)
+}
+
+// --- GitHub ---
+
+func gitHubReview(dir string) (*Review, error) {
+	output, err := run(dir, "gh", "pr", "view",
+		"--json", "number,title,url,state,reviewDecision,statusCheckRollup,reviews")
+	if err != nil {
+		return nil, err
+	}
+	review, err := parseGitHubReview(output)
+	if err != nil {
+		return nil, err
+	}
+
+	// Line comments are only available from the REST API
+	output, err = run(dir, "gh", "api",
+		fmt.Sprintf("repos/{owner}/{repo}/pulls/%d/comments?per_page=100", review.Number),
+		"--paginate", "--slurp")
+	if err == nil {
+		output, err = joinPages(output)
+	}
+	if err != nil {
+		return nil, err
+	}
+	comments := output
+
+	// Whether a thread is resolved is only available from the GraphQL API
+	output, err = run(dir, "gh", "api", "graphql", "--paginate", "--slurp",
+		"-F", "owner={owner}", "-F", "repo={repo}", "-F", fmt.Sprintf("number=%d", review.Number),
+		"-f", "query="+gitHubThreadsQuery)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := parseGitHubResolved(output)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := parseGitHubComments(comments, resolved)
+	if err != nil {
+		return nil, err
+	}
+	review.Threads = append(review.Threads, threads...)
+	sortThreads(review.Threads)
+	return review, nil
+}
+
+func parseGitHubReview(data []byte) (*Review, error) {
+	var pr struct {
+		Number            int    `json:"number"`
+		Title             string `json:"title"`
+		URL               string `json:"url"`
+		State             string `json:"state"`
+		ReviewDecision    string `json:"reviewDecision"`
+		StatusCheckRollup []struct {
+			Typename   string `json:"__typename"`
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			DetailsURL string `json:"detailsUrl"`
+			Context    string `json:"context"`
+			State      string `json:"state"`
+			TargetURL  string `json:"targetUrl"`
+		} `json:"statusCheckRollup"`
+		Reviews []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			Body        string `json:"body"`
+			SubmittedAt string `json:"submittedAt"`
+		} `json:"reviews"`
+	}
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return nil, fmt.Errorf("parse pr view: %w", err)
+	}
+
+	review := &Review{
+		Number:   pr.Number,
+		Title:    pr.Title,
+		URL:      pr.URL,
+		State:    strings.ToLower(pr.State),
+		Decision: strings.ToLower(pr.ReviewDecision),
+	}
+	for _, c := range pr.StatusCheckRollup {
+		if c.Typename == "StatusContext" {
+			review.Checks = append(review.Checks, Check{Name: c.Context, State: gitHubStatusState(c.State), URL: c.TargetURL})
+			continue
+		}
+		state := CheckPending
+		if c.Status == "COMPLETED" {
+			state = gitHubConclusionState(c.Conclusion)
+		}
+		review.Checks = append(review.Checks, Check{Name: c.Name, State: state, URL: c.DetailsURL})
+	}
+	// Review summaries are comments on the pull request as a whole
+	for _, rv := range pr.Reviews {
+		if strings.TrimSpace(rv.Body) == "" {
+			continue
+		}
+		review.Threads = append(review.Threads, ReviewThread{Comments: []ReviewComment{{
+			Author:    rv.Author.Login,
+			Body:      rv.Body,
+			CreatedAt: parseTime(rv.SubmittedAt),
+		}}})
+	}
+	return review, nil
+}
+
+func gitHubConclusionState(conclusion string) string {
+	switch conclusion {
+	case "SUCCESS", "NEUTRAL":
+		return CheckPass
+	case "SKIPPED", "STALE":
+		return CheckSkipped
+	case "":
+		return CheckPending
+	default:
+		return CheckFail
+	}
+}
+
+func gitHubStatusState(state string) string {
+	switch state {
+	case "SUCCESS":
+		return CheckPass
+	case "FAILURE", "ERROR":
+		return CheckFail
+	default:
+		return CheckPending
+	}
+}
+
+// gitHubThreadsQuery lists the review threads of a pull request with
+// whether they are resolved, identified by their first comment.
+const gitHubThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $endCursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $endCursor) {
+        nodes { isResolved comments(first: 1) { nodes { databaseId } } }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// parseGitHubResolved reads the pages of gitHubThreadsQuery, slurped into
+// an array, into the resolved state of each thread by its first comment's ID.
+func parseGitHubResolved(data []byte) (map[int64]bool, error) {
+	var pages []struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							IsResolved bool `json:"isResolved"`
+							Comments   struct {
+								Nodes []struct {
+									DatabaseID int64 `json:"databaseId"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil, fmt.Errorf("parse review threads: %w", err)
+	}
+	resolved := make(map[int64]bool)
+	for _, page := range pages {
+		for _, n := range page.Data.Repository.PullRequest.ReviewThreads.Nodes {
+			if len(n.Comments.Nodes) > 0 {
+				resolved[n.Comments.Nodes[0].DatabaseID] = n.IsResolved
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// parseGitHubComments groups REST review comments into threads by the
+// comment they reply to. resolved gives the state of threads by the ID of
+// their first comment.
+func parseGitHubComments(data []byte, resolved map[int64]bool) ([]ReviewThread, error) {
+	var comments []struct {
+		ID           int64  `json:"id"`
+		InReplyToID  int64  `json:"in_reply_to_id"`
+		Path         string `json:"path"`
+		Line         *int   `json:"line"`
+		OriginalLine *int   `json:"original_line"`
+		DiffHunk     string `json:"diff_hunk"`
+		Body         string `json:"body"`
+		CreatedAt    string `json:"created_at"`
+		User         struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(data, &comments); err != nil {
+		return nil, fmt.Errorf("parse review comments: %w", err)
+	}
+
+	var threads []ReviewThread
+	byID := make(map[int64]int) // Root comment ID -> thread index
+	for _, c := range comments {
+		comment := ReviewComment{Author: c.User.Login, Body: c.Body, CreatedAt: parseTime(c.CreatedAt)}
+		if c.InReplyToID != 0 {
+			if i, ok := byID[c.InReplyToID]; ok {
+				threads[i].Comments = append(threads[i].Comments, comment)
+				byID[c.ID] = i
+				continue
+			}
+		}
+		t := ReviewThread{Path: c.Path, DiffHunk: c.DiffHunk, Resolved: resolved[c.ID], Comments: []ReviewComment{comment}}
+		switch {
+		case c.Line != nil:
+			t.Line = *c.Line
+		case c.OriginalLine != nil:
+			t.Line = *c.OriginalLine
+			t.Outdated = true
+		default:
+			t.Outdated = true
+		}
+		byID[c.ID] = len(threads)
+		threads = append(threads, t)
+	}
+	return threads, nil
+}
+
+// --- GitLab ---
+
+func gitLabReview(dir string) (*Review, error) {
+	output, err := run(dir, "glab", "mr", "view", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var mr struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		WebURL       string `json:"web_url"`
+		State        string `json:"state"`
+		HeadPipeline *struct {
+			ID int `json:"id"`
+		} `json:"head_pipeline"`
+	}
+	if err := json.Unmarshal(output, &mr); err != nil {
+		return nil, fmt.Errorf("parse mr view: %w", err)
+	}
+	state := mr.State
+	if state == "opened" {
+		state = "open"
+	}
+	review := &Review{Number: mr.IID, Title: mr.Title, URL: mr.WebURL, State: state}
+	iid := strconv.Itoa(mr.IID)
+
+	output, err = run(dir, "glab", "api", "projects/:id/merge_requests/"+iid+"/discussions?per_page=100", "--paginate")
+	if err == nil {
+		output, err = joinPages(output)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.Threads, err = parseGitLabDiscussions(output); err != nil {
+		return nil, err
+	}
+	sortThreads(review.Threads)
+
+	// Approvals and pipelines are optional; older or restricted
+	// instances may not expose them.
+	if output, err := run(dir, "glab", "api", "projects/:id/merge_requests/"+iid+"/approvals"); err == nil {
+		review.Decision = parseGitLabApprovals(output)
+	}
+	if mr.HeadPipeline != nil {
+		path := "projects/:id/pipelines/" + strconv.Itoa(mr.HeadPipeline.ID) + "/jobs?per_page=100"
+		if output, err := run(dir, "glab", "api", path, "--paginate"); err == nil {
+			if output, err = joinPages(output); err == nil {
+				review.Checks = parseGitLabJobs(output)
+			}
+		}
+	}
+	return review, nil
+}
+
+func parseGitLabDiscussions(data []byte) ([]ReviewThread, error) {
+	var discussions []struct {
+		Notes []struct {
+			Body      string `json:"body"`
+			System    bool   `json:"system"`
+			Resolved  bool   `json:"resolved"`
+			CreatedAt string `json:"created_at"`
+			Author    struct {
+				Username string `json:"username"`
+			} `json:"author"`
+			Position *struct {
+				NewPath string `json:"new_path"`
+				OldPath string `json:"old_path"`
+				NewLine *int   `json:"new_line"`
+				OldLine *int   `json:"old_line"`
+			} `json:"position"`
+		} `json:"notes"`
+	}
+	if err := json.Unmarshal(data, &discussions); err != nil {
+		return nil, fmt.Errorf("parse discussions: %w", err)
+	}
+
+	var threads []ReviewThread
+	for _, d := range discussions {
+		var t ReviewThread
+		for _, n := range d.Notes {
+			if n.System {
+				continue
+			}
+			if len(t.Comments) == 0 {
+				t.Resolved = n.Resolved
+				if pos := n.Position; pos != nil {
+					t.Path = pos.NewPath
+					switch {
+					case pos.NewLine != nil:
+						t.Line = *pos.NewLine
+					case pos.OldLine != nil:
+						// Comment on a removed line
+						t.Path = pos.OldPath
+						t.Line = *pos.OldLine
+					}
+				}
+			}
+			t.Comments = append(t.Comments, ReviewComment{
+				Author:    n.Author.Username,
+				Body:      n.Body,
+				CreatedAt: parseTime(n.CreatedAt),
+			})
+		}
+		if len(t.Comments) > 0 {
+			threads = append(threads, t)
+		}
+	}
+	return threads, nil
+}
+
+func parseGitLabApprovals(data []byte) string {
+	var approvals struct {
+		Approved      bool `json:"approved"`
+		ApprovalsLeft int  `json:"approvals_left"`
+	}
+	if json.Unmarshal(data, &approvals) != nil {
+		return ""
+	}
+	switch {
+	case approvals.ApprovalsLeft > 0:
+		return DecisionReviewRequired
+	case approvals.Approved:
+		return DecisionApproved
+	}
+	return ""
+}
+
+func parseGitLabJobs(data []byte) []Check {
+	var jobs []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		WebURL string `json:"web_url"`
+	}
+	if json.Unmarshal(data, &jobs) != nil {
+		return nil
+	}
+	checks := make([]Check, 0, len(jobs))
+	for _, j := range jobs {
+		state := CheckPending
+		switch j.Status {
+		case "success":
+			state = CheckPass
+		case "failed":
+			state = CheckFail
+		case "skipped", "canceled", "manual":
+			state = CheckSkipped
+		}
+		checks = append(checks, Check{Name: j.Name, State: state, URL: j.WebURL})
+	}
+	return checks
+}
@@ -0,0 +1,119 @@
+package forge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGitHubReview(t *testing.T) {
+	dir := t.TempDir()
+	log := fakeCLI(t, "gh", `case "$1 $2" in
+"pr view") cat <<'JSON'
+{"number":7,"title":"Add API","url":"https://github.com/o/r/pull/7","state":"OPEN","reviewDecision":"CHANGES_REQUESTED",
+ "statusCheckRollup":[
+  {"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"},
+  {"__typename":"CheckRun","name":"lint","status":"COMPLETED","conclusion":"FAILURE"},
+  {"__typename":"CheckRun","name":"e2e","status":"IN_PROGRESS","conclusion":""},
+  {"__typename":"StatusContext","context":"ci/legacy","state":"PENDING"}],
+ "reviews":[{"author":{"login":"ann"},"body":"Needs tests","submittedAt":"2024-05-01T10:00:00Z"},{"author":{"login":"bo"},"body":""}]}
+JSON
+;;
+"api repos/{owner}/{repo}/pulls/7/comments?per_page=100")
+[ "$*" = "api repos/{owner}/{repo}/pulls/7/comments?per_page=100 --paginate --slurp" ] || exit 1
+cat <<'JSON'
+[[{"id":1,"path":"b.go","line":12,"diff_hunk":"@@ -10,2 +10,3 @@\n a\n+b","body":"Rename this","user":{"login":"ann"},"created_at":"2024-05-01T10:00:00Z"},
+  {"id":2,"path":"a.go","line":null,"original_line":5,"body":"Old note","user":{"login":"ann"}},
+  {"id":3,"in_reply_to_id":1,"path":"b.go","line":12,"body":"Done?","user":{"login":"cy"}}],
+ [{"id":4,"in_reply_to_id":3,"path":"b.go","line":12,"body":"Yes","user":{"login":"ann"}}]]
+JSON
+;;
+"api graphql")
+case "$*" in *"--paginate --slurp -F owner={owner} -F repo={repo} -F number=7 -f query="*) ;; *) exit 1 ;; esac
+cat <<'JSON'
+[{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[{"isResolved":false,"comments":{"nodes":[{"databaseId":2}]}}],"pageInfo":{"hasNextPage":true,"endCursor":"x"}}}}}},
+ {"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[{"isResolved":true,"comments":{"nodes":[{"databaseId":1}]}}],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}}}]
+JSON
+;;
+*) exit 1 ;;
+esac`)
+
+	review, err := (&Remote{Kind: GitHub}).PullRequestReview(dir)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, readLog(t, log))
+	}
+	if review.Number != 7 || review.State != "open" || review.Decision != DecisionChangesRequested {
+		t.Errorf("review = %+v", review)
+	}
+
+	want := []string{CheckPass, CheckFail, CheckPending, CheckPending}
+	if len(review.Checks) != len(want) {
+		t.Fatalf("checks = %+v", review.Checks)
+	}
+	for i, c := range review.Checks {
+		if c.State != want[i] {
+			t.Errorf("check %s = %s, want %s", c.Name, c.State, want[i])
+		}
+	}
+
+	// Review summary first, then threads by path
+	if len(review.Threads) != 3 {
+		t.Fatalf("threads = %+v", review.Threads)
+	}
+	general, old, thread := review.Threads[0], review.Threads[1], review.Threads[2]
+	if general.Path != "" || general.Comments[0].Body != "Needs tests" {
+		t.Errorf("general = %+v", general)
+	}
+	if old.Path != "a.go" || old.Line != 5 || !old.Outdated || old.Resolved {
+		t.Errorf("outdated = %+v", old)
+	}
+	if thread.Path != "b.go" || thread.Line != 12 || thread.Outdated || !thread.Resolved || len(thread.Comments) != 3 ||
+		thread.Comments[2].Author != "ann" || !strings.HasSuffix(thread.DiffHunk, "+b") {
+		t.Errorf("thread = %+v", thread)
+	}
+}
+
+func TestGitLabReview(t *testing.T) {
+	dir := t.TempDir()
+	fakeCLI(t, "glab", `case "$*" in
+"mr view --output json") echo '{"iid":4,"title":"Fix","web_url":"https://gitlab.com/g/p/-/merge_requests/4","state":"opened","head_pipeline":{"id":99}}' ;;
+"api projects/:id/merge_requests/4/discussions?per_page=100 --paginate") cat <<'JSON'
+[{"notes":[{"body":"added 1 commit","system":true}]},
+ {"notes":[{"body":"Use a constant","resolved":true,"author":{"username":"dee"},"position":{"new_path":"x.go","new_line":8}},
+           {"body":"Fixed","author":{"username":"eve"}}]}]
+[{"notes":[{"body":"Why remove this?","author":{"username":"dee"},"position":{"new_path":"y.go","old_path":"y.go","new_line":null,"old_line":3}}]},
+ {"notes":[{"body":"LGTM overall","author":{"username":"fay"}}]}]
+JSON
+;;
+"api projects/:id/merge_requests/4/approvals") echo '{"approved":false,"approvals_left":1}' ;;
+"api projects/:id/pipelines/99/jobs?per_page=100 --paginate") echo '[{"name":"test","status":"failed"},{"name":"deploy","status":"manual"}]' ;;
+*) exit 1 ;;
+esac`)
+
+	review, err := (&Remote{Kind: GitLab}).PullRequestReview(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Number != 4 || review.State != "open" || review.Decision != DecisionReviewRequired {
+		t.Errorf("review = %+v", review)
+	}
+	if len(review.Checks) != 2 || review.Checks[0].State != CheckFail || review.Checks[1].State != CheckSkipped {
+		t.Errorf("checks = %+v", review.Checks)
+	}
+	if len(review.Threads) != 3 {
+		t.Fatalf("threads = %+v", review.Threads)
+	}
+	if th := review.Threads[1]; th.Path != "x.go" || th.Line != 8 || !th.Resolved || len(th.Comments) != 2 {
+		t.Errorf("x.go thread = %+v", th)
+	}
+	if th := review.Threads[2]; th.Path != "y.go" || th.Line != 3 {
+		t.Errorf("removed line thread = %+v", th)
+	}
+}
+
+func TestGiteaReviewUnsupported(t *testing.T) {
+	_, err := (&Remote{Kind: Gitea}).PullRequestReview(t.TempDir())
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("err = %v", err)
+	}
+}
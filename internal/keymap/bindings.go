@@ -67,11 +67,22 @@ func DefaultBindings() []Binding {
 		{Key: "z", Command: "stash", Context: "git-status"},
 		{Key: "Z", Command: "stash-pop", Context: "git-status"},
 		{Key: "ctrl+z", Command: "stash-apply", Context: "git-status"},
+		{Key: "alt+z", Command: "show-stashes", Context: "git-status"},
+		{Key: "=", Command: "compare-branches", Context: "git-status"},
+		{Key: "E", Command: "export-patches", Context: "git-status"},
+		{Key: "M", Command: "show-submodules", Context: "git-status"},
+		{Key: "esc", Command: "leave-submodule", Context: "git-status"},
 		{Key: "O", Command: "open-in-file-browser", Context: "git-status"},
 		{Key: "o", Command: "open-in-github", Context: "git-status"},
 		{Key: "y", Command: "yank-file", Context: "git-status"},
 		{Key: "Y", Command: "yank-path", Context: "git-status"},
 		{Key: "D", Command: "discard-changes", Context: "git-status"},
+		{Key: "H", Command: "show-reflog", Context: "git-status"},
+		{Key: "m", Command: "resolve-conflicts", Context: "git-status"},
+		{Key: "T", Command: "show-tags", Context: "git-status"},
+		{Key: "W", Command: "show-release", Context: "git-status"},
+		{Key: "e", Command: "file-history", Context: "git-status"},
+		{Key: "B", Command: "show-bisect", Context: "git-status"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-status"},
 
 		// Git status commits context (sidebar)
@@ -92,6 +103,16 @@ func DefaultBindings() []Binding {
 		{Key: "v", Command: "toggle-graph", Context: "git-status-commits"},
 		{Key: "P", Command: "push", Context: "git-status-commits"},
 		{Key: "L", Command: "pull", Context: "git-status-commits"},
+		{Key: "i", Command: "interactive-rebase", Context: "git-status-commits"},
+		{Key: "C", Command: "cherry-pick", Context: "git-status-commits"},
+		{Key: "R", Command: "revert-commit", Context: "git-status-commits"},
+		{Key: "X", Command: "reset-to-commit", Context: "git-status-commits"},
+		{Key: "H", Command: "show-reflog", Context: "git-status-commits"},
+		{Key: "t", Command: "create-tag", Context: "git-status-commits"},
+		{Key: "T", Command: "show-tags", Context: "git-status-commits"},
+		{Key: "W", Command: "show-release", Context: "git-status-commits"},
+		{Key: "s", Command: "pickaxe-search", Context: "git-status-commits"},
+		{Key: "B", Command: "bisect", Context: "git-status-commits"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-status-commits"},
 
 		// Git history search modal context
@@ -114,8 +135,12 @@ func DefaultBindings() []Binding {
 		{Key: "ctrl+d", Command: "page-down", Context: "git-status-diff"},
 		{Key: "ctrl+u", Command: "page-up", Context: "git-status-diff"},
 		{Key: "enter", Command: "full-diff", Context: "git-status-diff"},
-		{Key: "s", Command: "stage-file", Context: "git-status-diff"},
-		{Key: "u", Command: "unstage-file", Context: "git-status-diff"},
+		{Key: "s", Command: "stage-selection", Context: "git-status-diff"},
+		{Key: "u", Command: "unstage-selection", Context: "git-status-diff"},
+		{Key: "D", Command: "discard-selection", Context: "git-status-diff"},
+		{Key: "V", Command: "select-lines", Context: "git-status-diff"},
+		{Key: "]", Command: "next-hunk", Context: "git-status-diff"},
+		{Key: "[", Command: "prev-hunk", Context: "git-status-diff"},
 		{Key: "v", Command: "toggle-diff-view", Context: "git-status-diff"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-status-diff"},
 		{Key: "w", Command: "toggle-wrap", Context: "git-status-diff"},
@@ -129,6 +154,7 @@ func DefaultBindings() []Binding {
 		{Key: "Y", Command: "yank-id", Context: "git-commit-preview"},
 		{Key: "o", Command: "open-in-github", Context: "git-commit-preview"},
 		{Key: "b", Command: "open-in-file-browser", Context: "git-commit-preview"},
+		{Key: "e", Command: "file-history", Context: "git-commit-preview"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-commit-preview"},
 
 		// Git diff context (full screen)
@@ -176,12 +202,132 @@ func DefaultBindings() []Binding {
 		// Git error modal context
 		{Key: "L", Command: "pull-from-error", Context: "git-error"},
 		{Key: "y", Command: "yank-error", Context: "git-error"},
+		{Key: "r", Command: "resolve-conflicts", Context: "git-error"},
+		{Key: "c", Command: "continue-sequencer", Context: "git-error"},
+		{Key: "a", Command: "abort-sequencer", Context: "git-error"},
 		{Key: "esc", Command: "dismiss", Context: "git-error"},
 
+		// Git cherry-pick/revert/reset context
+		{Key: "enter", Command: "preview-cherry-pick", Context: "git-cherry-pick-input"},
+		{Key: "esc", Command: "cancel", Context: "git-cherry-pick-input"},
+		{Key: "y", Command: "confirm-commit-action", Context: "git-commit-action"},
+		{Key: "esc", Command: "cancel", Context: "git-commit-action"},
+
+		// Git reflog and undo context
+		{Key: "u", Command: "undo-last-operation", Context: "git-reflog"},
+		{Key: "enter", Command: "restore-reflog-entry", Context: "git-reflog"},
+		{Key: "esc", Command: "cancel", Context: "git-reflog"},
+		{Key: "y", Command: "confirm-undo", Context: "git-undo"},
+		{Key: "esc", Command: "cancel", Context: "git-undo"},
+
+		// Git conflict resolver context
+		{Key: "o", Command: "take-ours", Context: "git-conflicts"},
+		{Key: "t", Command: "take-theirs", Context: "git-conflicts"},
+		{Key: "b", Command: "take-both", Context: "git-conflicts"},
+		{Key: "s", Command: "mark-resolved", Context: "git-conflicts"},
+		{Key: "n", Command: "next-conflict", Context: "git-conflicts"},
+		{Key: "N", Command: "prev-conflict", Context: "git-conflicts"},
+		{Key: "e", Command: "edit-conflict", Context: "git-conflicts"},
+		{Key: "c", Command: "continue-operation", Context: "git-conflicts"},
+		{Key: "a", Command: "abort-operation", Context: "git-conflicts"},
+		{Key: "esc", Command: "cancel", Context: "git-conflicts"},
+
+		// Git tags and release context
+		{Key: "enter", Command: "create-tag", Context: "git-tag-create"},
+		{Key: "esc", Command: "cancel", Context: "git-tag-create"},
+		{Key: "enter", Command: "show-release", Context: "git-tags"},
+		{Key: "n", Command: "create-tag", Context: "git-tags"},
+		{Key: "p", Command: "push-tag", Context: "git-tags"},
+		{Key: "P", Command: "push-all-tags", Context: "git-tags"},
+		{Key: "d", Command: "delete-tag", Context: "git-tags"},
+		{Key: "esc", Command: "cancel", Context: "git-tags"},
+		{Key: "y", Command: "delete-tag", Context: "git-tag-delete"},
+		{Key: "r", Command: "delete-remote-tag", Context: "git-tag-delete"},
+		{Key: "esc", Command: "cancel", Context: "git-tag-delete"},
+		{Key: "y", Command: "yank-release-notes", Context: "git-release"},
+		{Key: "t", Command: "create-tag", Context: "git-release"},
+		{Key: "esc", Command: "cancel", Context: "git-release"},
+
+		// Git file history and pickaxe context
+		{Key: "s", Command: "pickaxe-search", Context: "git-file-history"},
+		{Key: "v", Command: "toggle-diff-view", Context: "git-file-history"},
+		{Key: "y", Command: "yank-id", Context: "git-file-history"},
+		{Key: "esc", Command: "cancel", Context: "git-file-history"},
+		{Key: "enter", Command: "pickaxe-search", Context: "git-pickaxe"},
+		{Key: "alt+r", Command: "toggle-regex", Context: "git-pickaxe"},
+		{Key: "esc", Command: "cancel", Context: "git-pickaxe"},
+
+		// Git bisect context
+		{Key: "b", Command: "bisect-bad", Context: "git-bisect-menu"},
+		{Key: "g", Command: "bisect-good", Context: "git-bisect-menu"},
+		{Key: "s", Command: "bisect-skip", Context: "git-bisect-menu"},
+		{Key: "esc", Command: "cancel", Context: "git-bisect-menu"},
+		{Key: "g", Command: "bisect-good", Context: "git-bisect"},
+		{Key: "b", Command: "bisect-bad", Context: "git-bisect"},
+		{Key: "s", Command: "bisect-skip", Context: "git-bisect"},
+		{Key: "r", Command: "bisect-run", Context: "git-bisect"},
+		{Key: "x", Command: "bisect-reset", Context: "git-bisect"},
+		{Key: "esc", Command: "cancel", Context: "git-bisect"},
+		{Key: "esc", Command: "cancel-bisect-run", Context: "git-bisect-running"},
+		{Key: "x", Command: "cancel-bisect-run", Context: "git-bisect-running"},
+		{Key: "enter", Command: "bisect-run", Context: "git-bisect-run"},
+		{Key: "esc", Command: "cancel", Context: "git-bisect-run"},
+
+		// Git stash context
+		{Key: "enter", Command: "open-stash", Context: "git-stashes"},
+		{Key: "a", Command: "stash-apply", Context: "git-stashes"},
+		{Key: "p", Command: "stash-pop", Context: "git-stashes"},
+		{Key: "b", Command: "stash-branch", Context: "git-stashes"},
+		{Key: "d", Command: "stash-drop", Context: "git-stashes"},
+		{Key: "esc", Command: "cancel", Context: "git-stashes"},
+		{Key: "a", Command: "stash-apply-file", Context: "git-stash"},
+		{Key: "A", Command: "stash-apply", Context: "git-stash"},
+		{Key: "b", Command: "stash-branch", Context: "git-stash"},
+		{Key: "v", Command: "toggle-diff-view", Context: "git-stash"},
+		{Key: "esc", Command: "cancel", Context: "git-stash"},
+		{Key: "enter", Command: "create-branch", Context: "git-stash-branch"},
+		{Key: "esc", Command: "cancel", Context: "git-stash-branch"},
+		{Key: "y", Command: "stash-drop", Context: "git-stash-drop"},
+		{Key: "esc", Command: "cancel", Context: "git-stash-drop"},
+		{Key: "enter", Command: "compare", Context: "git-compare-prompt"},
+		{Key: "esc", Command: "cancel", Context: "git-compare-prompt"},
+		{Key: "tab", Command: "compare-next-tab", Context: "git-compare"},
+		{Key: "s", Command: "compare-swap", Context: "git-compare"},
+		{Key: "t", Command: "compare-direct", Context: "git-compare"},
+		{Key: "e", Command: "compare-edit", Context: "git-compare"},
+		{Key: "v", Command: "toggle-diff-view", Context: "git-compare"},
+		{Key: "esc", Command: "cancel", Context: "git-compare"},
+		{Key: "enter", Command: "export-patches", Context: "git-patch-export"},
+		{Key: "esc", Command: "cancel", Context: "git-patch-export"},
+		{Key: "a", Command: "apply-patch", Context: "git-patch-apply"},
+		{Key: "v", Command: "toggle-diff-view", Context: "git-patch-apply"},
+		{Key: "esc", Command: "cancel", Context: "git-patch-apply"},
+		{Key: "enter", Command: "open-submodule", Context: "git-submodules"},
+		{Key: "u", Command: "update-submodule", Context: "git-submodules"},
+		{Key: "s", Command: "sync-submodule", Context: "git-submodules"},
+		{Key: "U", Command: "update-all-submodules", Context: "git-submodules"},
+		{Key: "esc", Command: "cancel", Context: "git-submodules"},
+
 		// Git pull conflict context
+		{Key: "r", Command: "resolve-conflicts", Context: "git-pull-conflict"},
 		{Key: "a", Command: "abort-pull", Context: "git-pull-conflict"},
+		{Key: "c", Command: "continue-rebase", Context: "git-pull-conflict"},
+		{Key: "s", Command: "skip-rebase", Context: "git-pull-conflict"},
 		{Key: "esc", Command: "dismiss", Context: "git-pull-conflict"},
 
+		// Git interactive rebase context
+		{Key: "enter", Command: "preview-rebase", Context: "git-rebase"},
+		{Key: "p", Command: "rebase-pick", Context: "git-rebase"},
+		{Key: "r", Command: "rebase-reword", Context: "git-rebase"},
+		{Key: "s", Command: "rebase-squash", Context: "git-rebase"},
+		{Key: "f", Command: "rebase-fixup", Context: "git-rebase"},
+		{Key: "d", Command: "rebase-drop", Context: "git-rebase"},
+		{Key: "J", Command: "rebase-move-down", Context: "git-rebase"},
+		{Key: "K", Command: "rebase-move-up", Context: "git-rebase"},
+		{Key: "esc", Command: "cancel", Context: "git-rebase"},
+		{Key: "ctrl+s", Command: "save-reword", Context: "git-rebase-reword"},
+		{Key: "esc", Command: "cancel", Context: "git-rebase-reword"},
+
 		// Git stash pop context
 		{Key: "y", Command: "confirm-pop", Context: "git-stash-pop"},
 		{Key: "esc", Command: "dismiss", Context: "git-stash-pop"},
@@ -190,6 +336,12 @@ func DefaultBindings() []Binding {
 		{Key: "ctrl+s", Command: "execute-commit", Context: "git-commit"},
 		{Key: "ctrl+enter", Command: "execute-commit", Context: "git-commit"},
 		{Key: "esc", Command: "cancel", Context: "git-commit"},
+		{Key: "ctrl+g", Command: "generate-commit-message", Context: "git-commit"},
+		{Key: "ctrl+t", Command: "add-trailer", Context: "git-commit"},
+		{Key: "ctrl+l", Command: "toggle-commit-lint", Context: "git-commit"},
+		{Key: "ctrl+o", Command: "toggle-signoff", Context: "git-commit"},
+		{Key: "enter", Command: "add-trailer", Context: "git-commit-trailers"},
+		{Key: "esc", Command: "cancel", Context: "git-commit-trailers"},
 
 		// Git history context
 		{Key: "esc", Command: "close-history", Context: "git-history"},
@@ -245,10 +397,13 @@ func DefaultBindings() []Binding {
 		{Key: "shift+tab", Command: "switch-pane", Context: "file-browser-tree"},
 		{Key: "/", Command: "search", Context: "file-browser-tree"},
 		{Key: "ctrl+p", Command: "quick-open", Context: "file-browser-tree"},
+		{Key: "ctrl+e", Command: "recent-files", Context: "file-browser-tree"},
 		{Key: "f", Command: "project-search", Context: "file-browser-tree"},
 		{Key: "t", Command: "new-tab", Context: "file-browser-tree"},
 		{Key: "[", Command: "prev-tab", Context: "file-browser-tree"},
 		{Key: "]", Command: "next-tab", Context: "file-browser-tree"},
+		{Key: "ctrl+o", Command: "jump-back", Context: "file-browser-tree"},
+		{Key: "alt+i", Command: "jump-forward", Context: "file-browser-tree"},
 		{Key: "x", Command: "close-tab", Context: "file-browser-tree"},
 		{Key: "a", Command: "create-file", Context: "file-browser-tree"},
 		{Key: "A", Command: "create-dir", Context: "file-browser-tree"},
@@ -265,17 +420,33 @@ func DefaultBindings() []Binding {
 		{Key: "e", Command: "edit", Context: "file-browser-tree"},
 		{Key: "E", Command: "edit-external", Context: "file-browser-tree"},
 		{Key: "B", Command: "blame", Context: "file-browser-tree"},
+		{Key: "L", Command: "file-history", Context: "file-browser-tree"},
+		{Key: "P", Command: "apply-patch", Context: "file-browser-tree"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "file-browser-tree"},
 		{Key: "H", Command: "toggle-ignored", Context: "file-browser-tree"},
+		{Key: "space", Command: "toggle-select", Context: "file-browser-tree"},
+		{Key: "v", Command: "select-range", Context: "file-browser-tree"},
+		{Key: "esc", Command: "clear-selection", Context: "file-browser-tree"},
+		{Key: "S", Command: "stage", Context: "file-browser-tree"},
+		{Key: "c", Command: "toggle-changed", Context: "file-browser-tree"},
+		{Key: "d", Command: "diff", Context: "file-browser-tree"},
+		{Key: "u", Command: "undo", Context: "file-browser-tree"},
+		{Key: "T", Command: "trash", Context: "file-browser-tree"},
+		{Key: "O", Command: "outline", Context: "file-browser-tree"},
+		{Key: "ctrl+t", Command: "symbol-search", Context: "file-browser-tree"},
+		{Key: "X", Command: "extract", Context: "file-browser-tree"},
 
 		// File browser preview context
 		{Key: "tab", Command: "switch-pane", Context: "file-browser-preview"},
 		{Key: "shift+tab", Command: "switch-pane", Context: "file-browser-preview"},
 		{Key: "/", Command: "search-content", Context: "file-browser-preview"},
 		{Key: "ctrl+p", Command: "quick-open", Context: "file-browser-preview"},
+		{Key: "ctrl+e", Command: "recent-files", Context: "file-browser-preview"},
 		{Key: "f", Command: "project-search", Context: "file-browser-preview"},
 		{Key: "[", Command: "prev-tab", Context: "file-browser-preview"},
 		{Key: "]", Command: "next-tab", Context: "file-browser-preview"},
+		{Key: "ctrl+o", Command: "jump-back", Context: "file-browser-preview"},
+		{Key: "alt+i", Command: "jump-forward", Context: "file-browser-preview"},
 		{Key: "x", Command: "close-tab", Context: "file-browser-preview"},
 		{Key: "r", Command: "refresh", Context: "file-browser-preview"},
 		{Key: "R", Command: "rename", Context: "file-browser-preview"},
@@ -284,6 +455,8 @@ func DefaultBindings() []Binding {
 		{Key: "e", Command: "edit", Context: "file-browser-preview"},
 		{Key: "E", Command: "edit-external", Context: "file-browser-preview"},
 		{Key: "B", Command: "blame", Context: "file-browser-preview"},
+		{Key: "L", Command: "file-history", Context: "file-browser-preview"},
+		{Key: "P", Command: "apply-patch", Context: "file-browser-preview"},
 		{Key: "m", Command: "toggle-markdown", Context: "file-browser-preview"},
 		{Key: "esc", Command: "back", Context: "file-browser-preview"},
 		{Key: "h", Command: "back", Context: "file-browser-preview"},
@@ -291,6 +464,59 @@ func DefaultBindings() []Binding {
 		{Key: "Y", Command: "yank-path", Context: "file-browser-preview"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "file-browser-preview"},
 		{Key: "w", Command: "toggle-wrap", Context: "file-browser-preview"},
+		{Key: "O", Command: "outline", Context: "file-browser-preview"},
+		{Key: "ctrl+t", Command: "symbol-search", Context: "file-browser-preview"},
+		{Key: "ctrl+]", Command: "go-to-definition", Context: "file-browser-preview"},
+		{Key: "X", Command: "extract", Context: "file-browser-preview"},
+		{Key: "F", Command: "follow", Context: "file-browser-preview"},
+		{Key: "d", Command: "diff", Context: "file-browser-preview"},
+
+		// File browser data view context
+		{Key: "/", Command: "filter-data", Context: "file-browser-data"},
+		{Key: "enter", Command: "toggle-node", Context: "file-browser-data"},
+		{Key: "s", Command: "sort-column", Context: "file-browser-data"},
+		{Key: "-", Command: "hide-column", Context: "file-browser-data"},
+		{Key: "=", Command: "show-columns", Context: "file-browser-data"},
+		{Key: "m", Command: "toggle-raw", Context: "file-browser-data"},
+		{Key: "ctrl+p", Command: "quick-open", Context: "file-browser-data"},
+		{Key: "esc", Command: "back", Context: "file-browser-data"},
+
+		// File browser data view filter context
+		{Key: "enter", Command: "confirm", Context: "file-browser-data-filter"},
+		{Key: "esc", Command: "cancel", Context: "file-browser-data-filter"},
+
+		// File browser hex view context
+		{Key: "/", Command: "search-bytes", Context: "file-browser-hex"},
+		{Key: ":", Command: "jump-offset", Context: "file-browser-hex"},
+		{Key: "n", Command: "next-match", Context: "file-browser-hex"},
+		{Key: "N", Command: "prev-match", Context: "file-browser-hex"},
+		{Key: "X", Command: "extract", Context: "file-browser-hex"},
+		{Key: "ctrl+p", Command: "quick-open", Context: "file-browser-hex"},
+		{Key: "esc", Command: "back", Context: "file-browser-hex"},
+
+		// File browser hex view search and offset input context
+		{Key: "enter", Command: "confirm", Context: "file-browser-hex-input"},
+		{Key: "esc", Command: "cancel", Context: "file-browser-hex-input"},
+
+		// File browser stream view context (large files)
+		{Key: "/", Command: "search-content", Context: "file-browser-stream"},
+		{Key: "F", Command: "follow", Context: "file-browser-stream"},
+		{Key: "n", Command: "next-match", Context: "file-browser-stream"},
+		{Key: "N", Command: "prev-match", Context: "file-browser-stream"},
+		{Key: "ctrl+p", Command: "quick-open", Context: "file-browser-stream"},
+		{Key: "esc", Command: "back", Context: "file-browser-stream"},
+
+		// File browser stream view search input context
+		{Key: "enter", Command: "confirm", Context: "file-browser-stream-input"},
+		{Key: "esc", Command: "cancel", Context: "file-browser-stream-input"},
+
+		// File browser diff view context
+		{Key: "n", Command: "next-hunk", Context: "file-browser-diff"},
+		{Key: "N", Command: "prev-hunk", Context: "file-browser-diff"},
+		{Key: "d", Command: "diff", Context: "file-browser-diff"},
+		{Key: "B", Command: "blame", Context: "file-browser-diff"},
+		{Key: "ctrl+p", Command: "quick-open", Context: "file-browser-diff"},
+		{Key: "esc", Command: "diff", Context: "file-browser-diff"},
 
 		// File browser tree search context
 		{Key: "esc", Command: "cancel", Context: "file-browser-search"},
@@ -311,6 +537,15 @@ func DefaultBindings() []Binding {
 		{Key: "down", Command: "cursor-down", Context: "file-browser-quick-open"},
 		{Key: "ctrl+n", Command: "cursor-down", Context: "file-browser-quick-open"},
 		{Key: "ctrl+p", Command: "cursor-up", Context: "file-browser-quick-open"},
+		{Key: "ctrl+e", Command: "toggle-recent", Context: "file-browser-quick-open"},
+
+		// File browser symbol outline / quick open context
+		{Key: "esc", Command: "cancel", Context: "file-browser-symbols"},
+		{Key: "enter", Command: "select", Context: "file-browser-symbols"},
+		{Key: "up", Command: "cursor-up", Context: "file-browser-symbols"},
+		{Key: "down", Command: "cursor-down", Context: "file-browser-symbols"},
+		{Key: "ctrl+n", Command: "cursor-down", Context: "file-browser-symbols"},
+		{Key: "ctrl+p", Command: "cursor-up", Context: "file-browser-symbols"},
 
 		// File browser project search context
 		{Key: "esc", Command: "cancel", Context: "file-browser-project-search"},
@@ -327,6 +562,19 @@ func DefaultBindings() []Binding {
 		{Key: "ctrl+e", Command: "open-in-editor", Context: "file-browser-project-search"},
 		{Key: "ctrl+d", Command: "page-down", Context: "file-browser-project-search"},
 		{Key: "ctrl+u", Command: "page-up", Context: "file-browser-project-search"},
+		{Key: "ctrl+r", Command: "toggle-replace", Context: "file-browser-project-search"},
+		{Key: "alt+x", Command: "exclude-match", Context: "file-browser-project-search"},
+		{Key: "alt+a", Command: "replace-all", Context: "file-browser-project-search"},
+
+		// File browser trash view
+		{Key: "enter", Command: "restore", Context: "file-browser-trash"},
+		{Key: "d", Command: "purge", Context: "file-browser-trash"},
+		{Key: "D", Command: "empty-trash", Context: "file-browser-trash"},
+		{Key: "esc", Command: "close", Context: "file-browser-trash"},
+
+		// File browser batch result modal
+		{Key: "esc", Command: "close", Context: "file-browser-batch-result"},
+		{Key: "enter", Command: "close", Context: "file-browser-batch-result"},
 
 		// File browser file operation context
 		{Key: "esc", Command: "cancel", Context: "file-browser-file-op"},
@@ -364,11 +612,19 @@ func DefaultBindings() []Binding {
 		{Key: "[", Command: "prev-tab", Context: "workspace-list"},
 		{Key: "]", Command: "next-tab", Context: "workspace-list"},
 		{Key: "F", Command: "fetch-pr", Context: "workspace-list"},
+		{Key: "P", Command: "pr-review", Context: "workspace-list"},
 
 		// Workspace fetch PR context
 		{Key: "esc", Command: "cancel", Context: "workspace-fetch-pr"},
 		{Key: "enter", Command: "fetch", Context: "workspace-fetch-pr"},
 
+		// Workspace PR review context
+		{Key: "esc", Command: "cancel", Context: "workspace-pr-review"},
+		{Key: "s", Command: "send-review", Context: "workspace-pr-review"},
+		{Key: "x", Command: "toggle-comment", Context: "workspace-pr-review"},
+		{Key: "o", Command: "open-pr", Context: "workspace-pr-review"},
+		{Key: "r", Command: "refresh", Context: "workspace-pr-review"},
+
 		// Workspace preview context
 		{Key: "h", Command: "focus-left", Context: "workspace-preview"},
 		{Key: "left", Command: "focus-left", Context: "workspace-preview"},
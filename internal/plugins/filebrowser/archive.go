@@ -0,0 +1,486 @@
+package filebrowser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+)
+
+// maxArchiveEntries caps how many entries of an archive are listed.
+const maxArchiveEntries = 50000
+
+// archiveFormat is a kind of archive browsable in the tree.
+type archiveFormat int
+
+const (
+	archiveNone archiveFormat = iota
+	archiveZip
+	archiveTar
+	archiveTarGz
+)
+
+// archiveFormatFor returns the archive format of a file name.
+func archiveFormatFor(name string) archiveFormat {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return archiveTarGz
+	case strings.HasSuffix(lower, ".tar"):
+		return archiveTar
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".jar"):
+		return archiveZip
+	}
+	return archiveNone
+}
+
+// Label returns a short name for the tree.
+func (f archiveFormat) Label() string {
+	switch f {
+	case archiveZip:
+		return "zip"
+	case archiveTar:
+		return "tar"
+	case archiveTarGz:
+		return "tgz"
+	}
+	return ""
+}
+
+// archiveEntry is a file or directory inside an archive.
+type archiveEntry struct {
+	Name    string // Slash-separated path, without a trailing slash
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+	Mode    os.FileMode
+}
+
+// archiveFile is an entry met while walking an archive. open returns its
+// content and is only valid during the walk callback.
+type archiveFile struct {
+	archiveEntry
+	open func() (io.Reader, error)
+}
+
+// errStopWalk ends a walk early without error.
+var errStopWalk = errors.New("stop walk")
+
+// walkArchive calls fn for every file and directory in an archive, in
+// archive order. Links and other special entries are skipped, as are
+// names that would escape the archive.
+func walkArchive(fullPath string, fn func(f archiveFile) error) error {
+	format := archiveFormatFor(fullPath)
+	if format == archiveZip {
+		return walkZip(fullPath, fn)
+	}
+	if format == archiveNone {
+		return fmt.Errorf("not an archive: %s", filepath.Base(fullPath))
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	var r io.Reader = file
+	if format == archiveTarGz {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		info := hdr.FileInfo()
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			continue
+		}
+		name, ok := cleanEntryName(hdr.Name)
+		if !ok {
+			continue
+		}
+		f := archiveFile{
+			archiveEntry: archiveEntry{Name: name, IsDir: info.IsDir(), Size: hdr.Size, ModTime: hdr.ModTime, Mode: info.Mode()},
+			open:         func() (io.Reader, error) { return tr, nil },
+		}
+		if f.IsDir {
+			f.Size = 0
+		}
+		if err := fn(f); err != nil {
+			if err == errStopWalk {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func walkZip(fullPath string, fn func(f archiveFile) error) error {
+	zr, err := zip.OpenReader(fullPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }()
+
+	for _, zf := range zr.File {
+		info := zf.FileInfo()
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			continue
+		}
+		name, ok := cleanEntryName(zf.Name)
+		if !ok {
+			continue
+		}
+		var rc io.ReadCloser
+		f := archiveFile{
+			archiveEntry: archiveEntry{Name: name, IsDir: info.IsDir(), Size: int64(zf.UncompressedSize64), ModTime: zf.Modified, Mode: info.Mode()},
+			open: func() (io.Reader, error) {
+				var err error
+				rc, err = zf.Open()
+				return rc, err
+			},
+		}
+		err := fn(f)
+		if rc != nil {
+			_ = rc.Close()
+		}
+		if err == errStopWalk {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanEntryName normalises an entry name to a clean relative slash
+// path. It reports false for names that are absolute or climb out of
+// the archive.
+func cleanEntryName(name string) (string, bool) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(name, "/") || (len(name) > 1 && name[1] == ':') {
+		return "", false
+	}
+	name = path.Clean(name)
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return name, true
+}
+
+// listArchive returns the entries of an archive, adding the directories
+// that are only implied by file names. It reports whether the listing
+// stopped at maxArchiveEntries.
+func listArchive(fullPath string) ([]archiveEntry, bool, error) {
+	var entries []archiveEntry
+	seen := make(map[string]bool)
+	limited := false
+	err := walkArchive(fullPath, func(f archiveFile) error {
+		if len(entries) >= maxArchiveEntries {
+			limited = true
+			return errStopWalk
+		}
+		if seen[f.Name] {
+			return nil
+		}
+		// Add implied parents outermost first so they precede their children
+		var dirs []string
+		for dir := path.Dir(f.Name); dir != "." && !seen[dir]; dir = path.Dir(dir) {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+		for _, dir := range slices.Backward(dirs) {
+			entries = append(entries, archiveEntry{Name: dir, IsDir: true, Mode: os.ModeDir | 0755})
+		}
+		seen[f.Name] = true
+		entries = append(entries, f.archiveEntry)
+		return nil
+	})
+	return entries, limited, err
+}
+
+// readArchiveEntry reads up to limit bytes of a file in an archive and
+// reports whether there was more.
+func readArchiveEntry(fullPath, name string, limit int64) ([]byte, archiveEntry, bool, error) {
+	var data []byte
+	var entry archiveEntry
+	found := false
+	err := walkArchive(fullPath, func(f archiveFile) error {
+		if f.Name != name || f.IsDir {
+			return nil
+		}
+		found = true
+		entry = f.archiveEntry
+		r, err := f.open()
+		if err != nil {
+			return err
+		}
+		data, err = io.ReadAll(io.LimitReader(r, limit+1))
+		if err != nil {
+			return err
+		}
+		return errStopWalk
+	})
+	if err != nil {
+		return nil, entry, false, err
+	}
+	if !found {
+		return nil, entry, false, fmt.Errorf("%s not found in %s", name, filepath.Base(fullPath))
+	}
+	truncated := int64(len(data)) > limit
+	if truncated {
+		data = data[:limit]
+	}
+	return data, entry, truncated, nil
+}
+
+// extractTarget maps an archive entry under entry ("" for the whole
+// archive) to its path under destDir. Extracting an entry recreates it by
+// its base name inside destDir.
+func extractTarget(entry, name, destDir string) (string, bool) {
+	var rel string
+	switch {
+	case entry == "":
+		rel = name
+	case name == entry:
+		rel = path.Base(entry)
+	case strings.HasPrefix(name, entry+"/"):
+		rel = path.Join(path.Base(entry), strings.TrimPrefix(name, entry+"/"))
+	default:
+		return "", false
+	}
+	target := filepath.Join(destDir, filepath.FromSlash(rel))
+	return target, isWithin(destDir, target)
+}
+
+// extractArchive extracts entry ("" for everything) into destDir and
+// returns the number of files written. Nothing is written if any file
+// would be overwritten.
+func extractArchive(fullPath, entry, destDir string) (int, error) {
+	// Check for conflicts first so a failed extraction leaves no debris
+	matched := false
+	err := walkArchive(fullPath, func(f archiveFile) error {
+		target, ok := extractTarget(entry, f.Name, destDir)
+		if !ok {
+			return nil
+		}
+		matched = true
+		if info, err := os.Stat(target); err == nil && (!f.IsDir || !info.IsDir()) {
+			return fmt.Errorf("%s already exists", target)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !matched {
+		return 0, fmt.Errorf("%s not found in %s", entry, filepath.Base(fullPath))
+	}
+
+	count := 0
+	err = walkArchive(fullPath, func(f archiveFile) error {
+		target, ok := extractTarget(entry, f.Name, destDir)
+		if !ok {
+			return nil
+		}
+		if f.IsDir {
+			return os.MkdirAll(target, 0755)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		r, err := f.open()
+		if err != nil {
+			return err
+		}
+		perm := f.Mode.Perm()
+		if perm == 0 {
+			perm = 0644
+		}
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm|0600)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, r); err != nil {
+			_ = out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// splitArchivePath splits a relative path that points inside an archive
+// into the archive's relative path and the slash-separated entry name.
+func splitArchivePath(rootDir, relPath string) (archive, entry string, ok bool) {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if archiveFormatFor(parts[i]) == archiveNone {
+			continue
+		}
+		archive = filepath.FromSlash(strings.Join(parts[:i+1], "/"))
+		info, err := os.Stat(filepath.Join(rootDir, archive))
+		if err != nil || !info.Mode().IsRegular() {
+			return "", "", false
+		}
+		return archive, strings.Join(parts[i+1:], "/"), true
+	}
+	return "", "", false
+}
+
+// loadArchiveChildren lists an archive as virtual children of its node.
+func (t *FileTree) loadArchiveChildren(node *FileNode) error {
+	entries, _, err := listArchive(filepath.Join(t.RootDir, node.Path))
+	if err != nil {
+		return err
+	}
+
+	nodes := map[string]*FileNode{".": node}
+	node.Children = nil
+	for _, e := range entries {
+		parent := nodes[path.Dir(e.Name)]
+		if parent == nil {
+			continue // listArchive adds parents first
+		}
+		child := &FileNode{
+			Name:      path.Base(e.Name),
+			Path:      filepath.Join(node.Path, filepath.FromSlash(e.Name)),
+			IsDir:     e.IsDir,
+			IsIgnored: node.IsIgnored,
+			Archive:   node.Path,
+			Entry:     e.Name,
+			Parent:    parent,
+			Depth:     parent.Depth + 1,
+			Size:      e.Size,
+			ModTime:   e.ModTime,
+		}
+		if e.IsDir {
+			nodes[e.Name] = child
+		}
+		parent.Children = append(parent.Children, child)
+	}
+	t.resortNode(node)
+	return nil
+}
+
+// archiveReadOnlyKeys are tree and preview keys that would change or hand
+// off a file, refused for entries inside archives.
+var archiveReadOnlyKeys = map[string]bool{
+	"e": true, "o": true, "E": true, "R": true, "m": true, "a": true, "A": true,
+	"D": true, "y": true, "p": true, "B": true, "L": true, "P": true, "S": true,
+	"I": true, "ctrl+r": true, " ": true, "v": true,
+}
+
+// archivePreviewReadOnlyKeys are the preview keys refused for entries
+// inside archives.
+var archivePreviewReadOnlyKeys = map[string]bool{
+	"e": true, "E": true, "R": true, "B": true, "L": true, "P": true, "I": true, "ctrl+r": true,
+}
+
+// handleArchivePreviewKey refuses edits to a previewed archive entry and
+// starts extraction with X. It reports false for other keys.
+func (p *Plugin) handleArchivePreviewKey(key string) (tea.Cmd, bool) {
+	if key != "X" && !archivePreviewReadOnlyKeys[key] {
+		return nil, false
+	}
+	node := p.archiveNodeAt(p.previewFile)
+	if node == nil && key == "X" {
+		if n := p.tree.FindByPath(p.previewFile); n != nil && n.IsArchive {
+			node = n
+		}
+	}
+	switch {
+	case node == nil:
+		return nil, false
+	case key == "X":
+		p.startExtract(node)
+		return nil, true
+	}
+	return appmsg.ShowToast("Archive entries are read-only (X to extract)", 2*time.Second), true
+}
+
+// archiveNodeAt returns a node for a previewed path inside an archive,
+// or nil if the path is not in one.
+func (p *Plugin) archiveNodeAt(path string) *FileNode {
+	archive, entry, ok := splitArchivePath(p.ctx.WorkDir, path)
+	if !ok {
+		return nil
+	}
+	return &FileNode{Name: filepath.Base(path), Path: path, Archive: archive, Entry: entry}
+}
+
+// startExtract asks where to extract an archive, or an entry of one. The
+// whole archive goes to a directory named after it; an entry goes next
+// to the archive.
+func (p *Plugin) startExtract(node *FileNode) {
+	dest := filepath.Dir(node.Archive)
+	if node.IsArchive {
+		name := strings.TrimSuffix(node.Name, filepath.Ext(node.Name))
+		if strings.HasSuffix(strings.ToLower(name), ".tar") {
+			name = name[:len(name)-len(".tar")]
+		}
+		dest = filepath.Join(filepath.Dir(node.Path), name)
+	}
+	if dest == "." {
+		dest = ""
+	}
+	p.fileOpMode = FileOpExtract
+	p.fileOpTarget = node
+	p.fileOpBatch = nil
+	p.fileOpTextInput = textinput.New()
+	p.fileOpTextInput.Placeholder = "directory (empty for project root)"
+	p.fileOpTextInput.SetValue(dest)
+	p.fileOpTextInput.Focus()
+	p.fileOpTextInput.CursorEnd()
+	p.fileOpError = ""
+	p.fileOpButtonFocus = 0
+	p.fileOpShowSuggestions = false
+}
+
+// doExtract extracts the file op target into dest, relative to the
+// project.
+func (p *Plugin) doExtract(dest string) tea.Cmd {
+	node := p.fileOpTarget
+	archive, entry := node.Archive, node.Entry
+	if node.IsArchive {
+		archive, entry = node.Path, ""
+	}
+	archivePath := filepath.Join(p.ctx.WorkDir, archive)
+	destDir := filepath.Join(p.ctx.WorkDir, dest)
+	return func() tea.Msg {
+		count, err := extractArchive(archivePath, entry, destDir)
+		if err != nil {
+			return FileOpErrorMsg{Err: fmt.Errorf("extract failed: %w", err)}
+		}
+		return ExtractSuccessMsg{Dest: dest, Count: count}
+	}
+}
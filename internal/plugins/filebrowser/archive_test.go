@@ -0,0 +1,194 @@
+package filebrowser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a zip of name/content pairs to path.
+func writeZip(t *testing.T, path string, files ...string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for i := 0; i < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+}
+
+// writeTarGz writes a gzipped tar of name/content pairs to path.
+func writeTarGz(t *testing.T, path string, files ...string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for i := 0; i < len(files); i += 2 {
+		hdr := &tar.Header{Name: files[i], Mode: 0644, Size: int64(len(files[i+1])), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(files[i], "/") {
+			hdr = &tar.Header{Name: files[i], Mode: 0755, Typeflag: tar.TypeDir}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	_ = f.Close()
+}
+
+func TestListArchive(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "a.zip")
+	writeZip(t, zipPath, "lib/x/one.txt", "one", "top.txt", "top", "../evil.txt", "evil", "/abs.txt", "abs")
+	entries, limited, err := listArchive(zipPath)
+	if err != nil || limited {
+		t.Fatal(err, limited)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir {
+			names = append(names, e.Name+"/")
+		} else {
+			names = append(names, e.Name)
+		}
+	}
+	if got := strings.Join(names, " "); got != "lib/ lib/x/ lib/x/one.txt top.txt" {
+		t.Errorf("zip entries = %s", got)
+	}
+
+	tgzPath := filepath.Join(dir, "b.tgz")
+	writeTarGz(t, tgzPath, "pkg/", "", "pkg/bin", "\x7fELF\x00\x01")
+	data, entry, truncated, err := readArchiveEntry(tgzPath, "pkg/bin", 4)
+	if err != nil || string(data) != "\x7fELF" || !truncated || entry.Size != 6 {
+		t.Errorf("read = %q %+v truncated=%v %v", data, entry, truncated, err)
+	}
+	if _, _, _, err := readArchiveEntry(tgzPath, "pkg/missing", 10); err == nil {
+		t.Error("missing entry should fail")
+	}
+
+	if archive, entry, ok := splitArchivePath(dir, filepath.Join("b.tgz", "pkg", "bin")); !ok || archive != "b.tgz" || entry != "pkg/bin" {
+		t.Errorf("split = %q %q %v", archive, entry, ok)
+	}
+	if _, _, ok := splitArchivePath(dir, "b.tgz"); ok {
+		t.Error("the archive itself is not inside an archive")
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "a.zip")
+	writeZip(t, zipPath, "lib/one.txt", "one", "lib/sub/two.txt", "two", "top.txt", "top")
+
+	out := filepath.Join(dir, "out")
+	n, err := extractArchive(zipPath, "lib", out)
+	if err != nil || n != 2 {
+		t.Fatalf("extract lib = %d, %v", n, err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(out, "lib", "sub", "two.txt")); string(b) != "two" {
+		t.Errorf("two.txt = %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(out, "top.txt")); err == nil {
+		t.Error("only the entry should be extracted")
+	}
+
+	// Conflicts abort before anything is written
+	if err := os.WriteFile(filepath.Join(out, "top.txt"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	all := filepath.Join(dir, "all")
+	if err := os.MkdirAll(all, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(all, "top.txt"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := extractArchive(zipPath, "", all); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("conflict err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(all, "lib")); err == nil {
+		t.Error("a conflicting extraction should write nothing")
+	}
+
+	if _, err := extractArchive(zipPath, "nope", out); err == nil {
+		t.Error("missing entry should fail")
+	}
+}
+
+func TestArchiveInTree(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "readme.md")
+	writeZip(t, filepath.Join(dir, "dist.zip"), "bin/tool", "\x00\x01\x02", "notes.txt", "hello")
+	if err := p.tree.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	node := p.tree.FindByPath("dist.zip")
+	if node == nil || !node.IsArchive || !node.Expandable() {
+		t.Fatalf("archive node = %+v", node)
+	}
+	if err := p.tree.Expand(node); err != nil {
+		t.Fatal(err)
+	}
+	_ = p.tree.Expand(p.tree.FindByPath(filepath.Join("dist.zip", "bin")))
+	entry := p.tree.FindByPath(filepath.Join("dist.zip", "bin", "tool"))
+	if entry == nil || !entry.InArchive() || entry.Entry != "bin/tool" {
+		t.Fatalf("entry = %+v", entry)
+	}
+
+	// Entries preview from the archive
+	msg := LoadPreview(dir, entry.Path, p.ctx.Epoch)().(PreviewLoadedMsg)
+	if msg.Result.Error != nil || !msg.Result.IsBinary || string(msg.Result.Bytes) != "\x00\x01\x02" || msg.Result.HexPath != "" {
+		t.Fatalf("binary entry = %+v", msg.Result)
+	}
+	msg = LoadPreview(dir, filepath.Join("dist.zip", "notes.txt"), p.ctx.Epoch)().(PreviewLoadedMsg)
+	if msg.Result.Error != nil || strings.Join(msg.Result.Lines, "\n") != "hello" {
+		t.Errorf("text entry = %+v", msg.Result)
+	}
+
+	// Entries are read-only; X extracts next to the archive
+	p.treeCursor = p.tree.IndexOf(entry)
+	p.handleTreeKey("D")
+	if p.fileOpMode != FileOpNone {
+		t.Errorf("delete inside an archive should be refused, mode = %v", p.fileOpMode)
+	}
+	p.handleTreeKey("X")
+	if p.fileOpMode != FileOpExtract || p.fileOpTextInput.Value() != "" {
+		t.Fatalf("extract mode=%v dest=%q", p.fileOpMode, p.fileOpTextInput.Value())
+	}
+	result := p.doExtract("")().(ExtractSuccessMsg)
+	if result.Count != 1 {
+		t.Errorf("extracted %d", result.Count)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "tool")); string(b) != "\x00\x01\x02" {
+		t.Errorf("tool = %q", b)
+	}
+
+	// The whole archive defaults to a directory named after it
+	p.treeCursor = p.tree.IndexOf(node)
+	p.handleTreeKey("X")
+	if p.fileOpTextInput.Value() != "dist" {
+		t.Errorf("archive dest = %q", p.fileOpTextInput.Value())
+	}
+}
@@ -0,0 +1,455 @@
+package filebrowser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+// BatchOp identifies an operation applied to every selected item.
+type BatchOp int
+
+const (
+	BatchMove BatchOp = iota
+	BatchCopy
+	BatchDelete
+	BatchStage
+	BatchRestore // Undo, or restore from the trash view
+	BatchReplace // Project-wide replace; journaled for undo only
+)
+
+// Progressive returns the verb shown while the operation runs.
+func (op BatchOp) Progressive() string {
+	switch op {
+	case BatchMove:
+		return "Moving"
+	case BatchCopy:
+		return "Copying"
+	case BatchDelete:
+		return "Deleting"
+	case BatchRestore:
+		return "Restoring"
+	case BatchReplace:
+		return "Replacing"
+	default:
+		return "Staging"
+	}
+}
+
+// Past returns the verb used in the completion summary.
+func (op BatchOp) Past() string {
+	switch op {
+	case BatchMove:
+		return "Moved"
+	case BatchCopy:
+		return "Copied"
+	case BatchDelete:
+		return "Deleted"
+	case BatchRestore:
+		return "Restored"
+	case BatchReplace:
+		return "Replaced"
+	default:
+		return "Staged"
+	}
+}
+
+// BatchFailure records an item a batch operation could not process.
+type BatchFailure struct {
+	Path string
+	Err  error
+}
+
+// batchState tracks a batch operation that runs one item at a time so
+// progress can be shown and a failure doesn't stop the remaining items.
+type batchState struct {
+	Op      BatchOp
+	Items   []string      // Relative paths, in processing order
+	DestDir string        // Absolute destination for move/copy
+	Trash   bool          // Deletes go to the project trash
+	Undo    []journalItem // For BatchRestore: the change each item reverses
+	Next    int           // Index of the item in flight
+	Done    []string      // Items that succeeded
+	Failed  []BatchFailure
+	Journal []journalItem // Reversible changes made, for the undo journal
+}
+
+// batchJob is the work for a single item, copied out of batchState so it
+// can run off the update loop.
+type batchJob struct {
+	Op      BatchOp
+	Path    string
+	DestDir string
+	Trash   bool
+	Undo    journalItem
+}
+
+// BatchStepMsg reports the result of one item of a batch operation.
+type BatchStepMsg struct {
+	Epoch  uint64
+	Index  int
+	Change journalItem // Reversible change made, if any
+	Err    error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m BatchStepMsg) GetEpoch() uint64 { return m.Epoch }
+
+// hasSelection reports whether any items are selected or a range is being
+// drawn.
+func (p *Plugin) hasSelection() bool {
+	return len(p.selectedPaths) > 0 || p.visualMode
+}
+
+// isSelected reports whether the tree row at index is part of the selection.
+func (p *Plugin) isSelected(index int, node *FileNode) bool {
+	if p.selectedPaths[node.Path] {
+		return true
+	}
+	if !p.visualMode {
+		return false
+	}
+	lo, hi := min(p.visualAnchor, p.treeCursor), max(p.visualAnchor, p.treeCursor)
+	return index >= lo && index <= hi
+}
+
+// toggleSelected adds or removes a node from the selection.
+func (p *Plugin) toggleSelected(node *FileNode) {
+	if node == nil || node == p.tree.Root {
+		return
+	}
+	if p.selectedPaths[node.Path] {
+		delete(p.selectedPaths, node.Path)
+		return
+	}
+	if p.selectedPaths == nil {
+		p.selectedPaths = make(map[string]bool)
+	}
+	p.selectedPaths[node.Path] = true
+}
+
+// commitVisualRange adds the rows between the range anchor and the cursor
+// to the selection and ends range mode.
+func (p *Plugin) commitVisualRange() {
+	if !p.visualMode {
+		return
+	}
+	p.visualMode = false
+	lo, hi := min(p.visualAnchor, p.treeCursor), max(p.visualAnchor, p.treeCursor)
+	for i := lo; i <= hi; i++ {
+		if node := p.tree.GetNode(i); node != nil && !p.selectedPaths[node.Path] {
+			p.toggleSelected(node)
+		}
+	}
+}
+
+// clearSelection drops the selection and any range in progress.
+func (p *Plugin) clearSelection() {
+	p.selectedPaths = nil
+	p.visualMode = false
+}
+
+// takeSelection commits any range in progress and returns the selected
+// paths ready for a batch operation.
+func (p *Plugin) takeSelection() []string {
+	p.commitVisualRange()
+	paths := make([]string, 0, len(p.selectedPaths))
+	for path := range p.selectedPaths {
+		paths = append(paths, path)
+	}
+	return normalizeSelection(paths)
+}
+
+// normalizeSelection sorts paths and drops the project root and any path
+// inside another selected directory, which is handled along with it.
+func normalizeSelection(paths []string) []string {
+	sorted := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path != "" && path != "." {
+			sorted = append(sorted, filepath.Clean(path))
+		}
+	}
+	sort.Strings(sorted)
+
+	var result []string
+	for _, path := range sorted {
+		covered := false
+		for _, kept := range result {
+			if path == kept || strings.HasPrefix(path, kept+string(filepath.Separator)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// startBatch begins running op over items.
+func (p *Plugin) startBatch(op BatchOp, items []string, destDir string) tea.Cmd {
+	if p.batch != nil {
+		return appmsg.ShowToast(p.batch.Op.Progressive()+" is still running", 2*time.Second)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	p.batch = &batchState{Op: op, Items: items, DestDir: destDir, Trash: p.trashEnabled()}
+	return p.batchStep()
+}
+
+// batchStep runs the next item of the current batch.
+func (p *Plugin) batchStep() tea.Cmd {
+	b := p.batch
+	if b == nil || b.Next >= len(b.Items) {
+		return p.finishBatch()
+	}
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	index := b.Next
+	job := batchJob{Op: b.Op, Path: b.Items[index], DestDir: b.DestDir, Trash: b.Trash}
+	if index < len(b.Undo) {
+		job.Undo = b.Undo[index]
+	}
+	return func() tea.Msg {
+		change, err := runBatchItem(workDir, job)
+		return BatchStepMsg{Epoch: epoch, Index: index, Change: change, Err: err}
+	}
+}
+
+// handleBatchStep records one item's result and moves on to the next.
+func (p *Plugin) handleBatchStep(msg BatchStepMsg) tea.Cmd {
+	b := p.batch
+	if b == nil || msg.Index != b.Next {
+		return nil
+	}
+	path := b.Items[msg.Index]
+	if msg.Err != nil {
+		b.Failed = append(b.Failed, BatchFailure{Path: path, Err: msg.Err})
+	} else {
+		b.Done = append(b.Done, path)
+		if msg.Change != (journalItem{}) {
+			b.Journal = append(b.Journal, msg.Change)
+		}
+		if b.Op == BatchDelete {
+			p.closeTabsForPath(path)
+		}
+	}
+	b.Next++
+	return p.batchStep()
+}
+
+// finishBatch reports the outcome of the batch and refreshes the tree. Items
+// that failed stay selected so they can be retried.
+func (p *Plugin) finishBatch() tea.Cmd {
+	b := p.batch
+	p.batch = nil
+	if b == nil {
+		return nil
+	}
+
+	if b.Op == BatchMove || b.Op == BatchDelete || b.Op == BatchStage {
+		p.selectedPaths = nil
+		for _, f := range b.Failed {
+			p.toggleSelected(&FileNode{Path: f.Path})
+		}
+	}
+
+	summary := fmt.Sprintf("%s %s", b.Op.Past(), pluralItems(len(b.Done)))
+	if b.Op == BatchMove || b.Op == BatchCopy {
+		if rel, err := filepath.Rel(p.ctx.WorkDir, b.DestDir); err == nil && rel != "." {
+			summary += " to " + rel
+		}
+	}
+	if len(b.Journal) > 0 {
+		p.recordUndo(b.Op, b.Journal...)
+		summary += " (u to undo)"
+	}
+	cmds := []tea.Cmd{p.refresh()}
+	if b.Op == BatchRestore && p.trashMode {
+		cmds = append(cmds, p.loadTrash())
+	}
+	if len(b.Failed) > 0 {
+		p.batchResult = b
+		p.clearBatchResultModal()
+		summary = fmt.Sprintf("%s %d of %s, %d failed", b.Op.Past(), len(b.Done), pluralItems(len(b.Items)), len(b.Failed))
+	}
+	cmds = append(cmds, appmsg.ShowToast(summary, 3*time.Second))
+	return tea.Batch(cmds...)
+}
+
+// batchProgress returns the progress label for the tree header, or "" when
+// no batch is running.
+func (p *Plugin) batchProgress() string {
+	if p.batch == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %d/%d…", p.batch.Op.Progressive(), p.batch.Next+1, len(p.batch.Items))
+}
+
+// pluralItems formats an item count.
+func pluralItems(n int) string {
+	if n == 1 {
+		return "1 item"
+	}
+	return fmt.Sprintf("%d items", n)
+}
+
+// runBatchItem applies a batch operation to a single path relative to
+// workDir and returns the reversible change it made, if any.
+func runBatchItem(workDir string, job batchJob) (journalItem, error) {
+	switch job.Op {
+	case BatchMove:
+		dst, err := moveInto(workDir, job.Path, job.DestDir)
+		if err != nil {
+			return journalItem{}, err
+		}
+		to, err := filepath.Rel(workDir, dst)
+		if err != nil {
+			return journalItem{}, nil
+		}
+		return journalItem{From: job.Path, To: to}, nil
+	case BatchCopy:
+		_, err := copyInto(workDir, job.Path, job.DestDir)
+		return journalItem{}, err
+	case BatchDelete:
+		if !job.Trash {
+			_, err := deletePath(workDir, job.Path)
+			return journalItem{}, err
+		}
+		entry, err := moveToTrash(workDir, job.Path)
+		if err != nil {
+			return journalItem{}, err
+		}
+		return journalItem{From: entry.Path, TrashID: entry.ID}, nil
+	case BatchStage:
+		return journalItem{}, stagePath(workDir, job.Path)
+	case BatchRestore:
+		return journalItem{}, undoChange(workDir, job.Undo)
+	}
+	return journalItem{}, nil
+}
+
+// moveInto moves the file or directory at path (relative to workDir) into
+// destDir, keeping its name, and returns the destination path.
+func moveInto(workDir, path, destDir string) (string, error) {
+	src := filepath.Join(workDir, path)
+	dst := filepath.Join(destDir, filepath.Base(path))
+
+	if !isWithin(workDir, dst) {
+		return "", fmt.Errorf("cannot move files outside project directory")
+	}
+	if dst == src {
+		return "", fmt.Errorf("already in destination")
+	}
+	if isWithin(src, destDir) {
+		return "", fmt.Errorf("cannot move a directory into itself")
+	}
+	if _, err := os.Lstat(src); err != nil {
+		return "", fmt.Errorf("source not found: %s", filepath.Base(path))
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return "", fmt.Errorf("destination already exists: %s", filepath.Base(dst))
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// stagePath stages a file or directory with git add. Deletions inside a
+// directory are staged too.
+func stagePath(workDir, path string) error {
+	cmd := exec.Command("git", "add", "-A", "--", path)
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s", firstLine(msg))
+		}
+		return err
+	}
+	return nil
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// executeBatchMove validates the destination directory typed in the move
+// prompt and starts moving the selection into it.
+func (p *Plugin) executeBatchMove(input string) (plugin.Plugin, tea.Cmd) {
+	input = strings.TrimSpace(input)
+	if filepath.IsAbs(input) {
+		p.fileOpError = "absolute paths not allowed"
+		return p, nil
+	}
+	destDir := filepath.Join(p.ctx.WorkDir, input)
+	if err := p.validateDestPath(destDir); err != nil {
+		p.fileOpError = err.Error()
+		return p, nil
+	}
+
+	info, err := os.Stat(destDir)
+	if os.IsNotExist(err) {
+		// Ask before creating the directory, as single moves do
+		p.fileOpConfirmCreate = true
+		p.fileOpConfirmPath = destDir
+		return p, nil
+	}
+	if err != nil {
+		p.fileOpError = err.Error()
+		return p, nil
+	}
+	if !info.IsDir() {
+		p.fileOpError = "destination is not a directory"
+		return p, nil
+	}
+
+	items := p.fileOpBatch
+	p.resetFileOp()
+	return p, p.startBatch(BatchMove, items, destDir)
+}
+
+// resetFileOp leaves file operation mode.
+func (p *Plugin) resetFileOp() {
+	p.fileOpMode = FileOpNone
+	p.fileOpTarget = nil
+	p.fileOpBatch = nil
+	p.fileOpError = ""
+	p.fileOpConfirmDelete = false
+	p.fileOpConfirmCreate = false
+	p.fileOpShowSuggestions = false
+}
+
+// openSelectionInTabs opens every selected file in its own tab. Only the
+// last tab becomes active, so only its preview is loaded now.
+func (p *Plugin) openSelectionInTabs() tea.Cmd {
+	var cmd tea.Cmd
+	opened := 0
+	for _, path := range p.takeSelection() {
+		if info, err := os.Stat(filepath.Join(p.ctx.WorkDir, path)); err != nil || info.IsDir() {
+			continue
+		}
+		cmd = p.openTab(path, TabOpenNew)
+		opened++
+	}
+	if opened == 0 {
+		return appmsg.ShowToast("No files selected", 2*time.Second)
+	}
+	p.clearSelection()
+	p.activePane = PanePreview
+	return cmd
+}
@@ -0,0 +1,211 @@
+package filebrowser
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+// newBatchTestPlugin creates a plugin over a temp dir holding the given
+// files, with every directory expanded.
+func newBatchTestPlugin(t *testing.T, files ...string) (*Plugin, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tree := NewFileTree(dir)
+	if err := tree.Build(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < tree.Len(); i++ {
+		if node := tree.GetNode(i); node.IsDir {
+			_ = tree.Expand(node)
+		}
+	}
+	return &Plugin{ctx: &plugin.Context{WorkDir: dir}, tree: tree}, dir
+}
+
+// runBatch drives a batch to completion the way Update would.
+func runBatch(p *Plugin, cmd tea.Cmd) {
+	for cmd != nil {
+		step, ok := cmd().(BatchStepMsg)
+		if !ok {
+			return
+		}
+		cmd = p.handleBatchStep(step)
+	}
+}
+
+func TestNormalizeSelection(t *testing.T) {
+	got := normalizeSelection([]string{"src/b.go", "", "docs", "src", "docs/readme.md", "src2/a.go"})
+	want := []string{"docs", "src", "src2/a.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeSelection() = %v, want %v", got, want)
+	}
+}
+
+func TestSelectionToggleAndRange(t *testing.T) {
+	p, _ := newBatchTestPlugin(t, "a.txt", "b.txt", "c.txt", "d.txt")
+
+	p.toggleSelected(p.tree.GetNode(0))
+	p.toggleSelected(p.tree.GetNode(3))
+	p.toggleSelected(p.tree.GetNode(3))
+	p.toggleSelected(p.tree.Root)
+
+	p.visualMode = true
+	p.visualAnchor = 2
+	p.treeCursor = 1
+	if !p.isSelected(1, p.tree.GetNode(1)) || p.isSelected(3, p.tree.GetNode(3)) {
+		t.Error("range should cover rows 1-2 only")
+	}
+
+	got := p.takeSelection()
+	want := []string{"a.txt", "b.txt", "c.txt"}
+	if !reflect.DeepEqual(got, want) || p.visualMode {
+		t.Errorf("takeSelection() = %v (visual=%v), want %v", got, p.visualMode, want)
+	}
+}
+
+func TestBatchMovePartialFailure(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "b.txt", "dest/b.txt")
+	p.selectedPaths = map[string]bool{"a.txt": true, "b.txt": true, "missing.txt": true}
+
+	runBatch(p, p.startBatch(BatchMove, p.takeSelection(), filepath.Join(dir, "dest")))
+
+	if p.batch != nil {
+		t.Fatal("batch should be finished")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dest", "a.txt")); err != nil {
+		t.Errorf("a.txt not moved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Errorf("b.txt should stay put: %v", err)
+	}
+	r := p.batchResult
+	if r == nil || !reflect.DeepEqual(r.Done, []string{"a.txt"}) || len(r.Failed) != 2 {
+		t.Fatalf("result = %+v", r)
+	}
+	if !strings.Contains(r.Failed[0].Err.Error(), "already exists") {
+		t.Errorf("b.txt error = %v", r.Failed[0].Err)
+	}
+	want := map[string]bool{"b.txt": true, "missing.txt": true}
+	if !reflect.DeepEqual(p.selectedPaths, want) {
+		t.Errorf("failed items should stay selected, got %v", p.selectedPaths)
+	}
+}
+
+func TestBatchResetOnProjectSwitch(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "b.txt")
+	p.ctx.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	step := p.startBatch(BatchDelete, []string{"a.txt", "b.txt"}, "")
+	p.batchResult = &batchState{Op: BatchMove}
+
+	// The step in flight belongs to the old project
+	ctx := *p.ctx
+	ctx.Epoch++
+	if err := p.Init(&ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p.batch != nil || p.batchResult != nil || p.batchProgress() != "" {
+		t.Fatal("project switch should drop the running batch")
+	}
+	if msg := step(); !plugin.IsStale(p.ctx, msg.(BatchStepMsg)) {
+		t.Error("old step should be stale")
+	}
+
+	runBatch(p, p.startBatch(BatchDelete, []string{"b.txt"}, ""))
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); !os.IsNotExist(err) {
+		t.Error("a new batch should run after the switch")
+	}
+}
+
+func TestBatchCopyAndDelete(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "pkg/x.go", "out/a.txt")
+
+	runBatch(p, p.startBatch(BatchCopy, []string{"a.txt", "pkg"}, filepath.Join(dir, "out")))
+	for _, f := range []string{"out/a_copy.txt", "out/pkg/x.go", "a.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("%s: %v", f, err)
+		}
+	}
+	if p.batchResult != nil {
+		t.Errorf("unexpected failures: %+v", p.batchResult.Failed)
+	}
+
+	runBatch(p, p.startBatch(BatchDelete, []string{"a.txt", "pkg"}, ""))
+	for _, f := range []string{"a.txt", "pkg"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); !os.IsNotExist(err) {
+			t.Errorf("%s should be deleted", f)
+		}
+	}
+}
+
+func TestMoveIntoItself(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "pkg", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := moveInto(dir, "pkg", filepath.Join(dir, "pkg", "sub")); err == nil {
+		t.Error("moving a directory into itself should fail")
+	}
+	if _, err := copyInto(dir, "pkg", filepath.Join(dir, "pkg", "sub")); err == nil {
+		t.Error("copying a directory into itself should fail")
+	}
+}
+
+func TestExecuteBatchMoveValidation(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "file.txt")
+	p.fileOpMode = FileOpMove
+	p.fileOpBatch = []string{"a.txt"}
+
+	p.fileOpTextInput.SetValue("file.txt")
+	p.executeFileOp()
+	if p.fileOpError != "destination is not a directory" {
+		t.Errorf("error = %q", p.fileOpError)
+	}
+
+	p.fileOpError = ""
+	p.fileOpTextInput.SetValue("new/dir")
+	p.executeFileOp()
+	if !p.fileOpConfirmCreate || p.fileOpConfirmPath != filepath.Join(dir, "new", "dir") {
+		t.Errorf("should confirm creating new/dir, got %v %q", p.fileOpConfirmCreate, p.fileOpConfirmPath)
+	}
+}
+
+func TestStagePath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	p, dir := newBatchTestPlugin(t, "a.txt", "pkg/x.go", "other.txt")
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v (%s)", err, out)
+	}
+
+	runBatch(p, p.startBatch(BatchStage, []string{"a.txt", "pkg", "missing.txt"}, ""))
+
+	out, err := exec.Command("git", "-C", dir, "diff", "--cached", "--name-only").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(out)); !reflect.DeepEqual(got, []string{"a.txt", "pkg/x.go"}) {
+		t.Errorf("staged = %v", got)
+	}
+	if p.batchResult == nil || len(p.batchResult.Failed) != 1 || p.batchResult.Failed[0].Path != "missing.txt" {
+		t.Errorf("result = %+v", p.batchResult)
+	}
+}
@@ -0,0 +1,535 @@
+package filebrowser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dataFormat identifies files shown in a structured data view.
+type dataFormat int
+
+const (
+	dataNone dataFormat = iota
+	dataJSON
+	dataYAML
+	dataCSV
+	dataSQLite
+)
+
+// sqliteMagic starts every SQLite database file.
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+// dataFormatFor picks the structured view for a file from its extension,
+// or from its header for SQLite databases with any extension.
+func dataFormatFor(path string, head []byte) dataFormat {
+	if bytes.HasPrefix(head, sqliteMagic) {
+		return dataSQLite
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson", ".geojson":
+		return dataJSON
+	case ".yaml", ".yml":
+		return dataYAML
+	case ".csv", ".tsv", ".tab":
+		return dataCSV
+	}
+	return dataNone
+}
+
+// dataDoc is a parsed data file.
+type dataDoc struct {
+	Format    dataFormat
+	Root      *dataNode  // JSON and YAML
+	Table     *dataTable // CSV and TSV
+	Tables    []sqliteTable
+	Path      string // Absolute path, for SQLite queries
+	Truncated bool   // Parsed from the start of a larger file
+	Err       error  // Parse error; the raw text view still works
+}
+
+// loadDataDoc parses the content of a data file. SQLite databases are
+// read from fullPath instead.
+func loadDataDoc(format dataFormat, fullPath string, data []byte, truncated bool) *dataDoc {
+	doc := &dataDoc{Format: format, Path: fullPath, Truncated: truncated}
+	switch format {
+	case dataJSON:
+		doc.Root, doc.Err = parseJSONData(data)
+	case dataYAML:
+		doc.Root, doc.Err = parseYAMLData(data)
+	case dataCSV:
+		comma := ','
+		if ext := strings.ToLower(filepath.Ext(fullPath)); ext == ".tsv" || ext == ".tab" {
+			comma = '\t'
+		}
+		doc.Table, doc.Err = parseCSVData(data, comma, truncated)
+	case dataSQLite:
+		doc.Tables, doc.Err = sqliteListTables(fullPath)
+	}
+	if doc.Err != nil && truncated && format != dataSQLite {
+		doc.Err = fmt.Errorf("%w (file truncated at %s)", doc.Err, formatSize(maxPreviewSize))
+	}
+	return doc
+}
+
+// dataNodeKind is the type of a JSON or YAML value.
+type dataNodeKind int
+
+const (
+	nodeObject dataNodeKind = iota
+	nodeArray
+	nodeString
+	nodeNumber
+	nodeBool
+	nodeNull
+)
+
+// dataNode is one value of a JSON or YAML document. Object keys keep
+// their file order.
+type dataNode struct {
+	Key      string // Key in the parent object
+	Index    int    // Index in the parent array, -1 otherwise
+	Kind     dataNodeKind
+	Value    string // Scalar text
+	Children []*dataNode
+}
+
+// isContainer reports whether the node is an object or array.
+func (n *dataNode) isContainer() bool {
+	return n.Kind == nodeObject || n.Kind == nodeArray
+}
+
+// child returns the object member called key.
+func (n *dataNode) child(key string) *dataNode {
+	if n.Kind != nodeObject {
+		return nil
+	}
+	for _, c := range n.Children {
+		if c.Key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+// pathSegment returns the jq path step that selects n from its parent.
+func (n *dataNode) pathSegment() string {
+	if n.Index >= 0 {
+		return fmt.Sprintf("[%d]", n.Index)
+	}
+	if identRe.MatchString(n.Key) {
+		return "." + n.Key
+	}
+	return "[" + strconv.Quote(n.Key) + "]"
+}
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseJSONData parses a JSON document. Several top-level values, as in
+// JSON Lines files, become the elements of a root array.
+func parseJSONData(data []byte) (*dataNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values []*dataNode
+	for {
+		node, err := parseJSONValue(dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON at byte %d: %w", dec.InputOffset(), err)
+		}
+		node.Index = len(values)
+		values = append(values, node)
+	}
+	switch len(values) {
+	case 0:
+		return nil, errors.New("empty document")
+	case 1:
+		values[0].Index = -1
+		return values[0], nil
+	}
+	return &dataNode{Kind: nodeArray, Index: -1, Children: values}, nil
+}
+
+func parseJSONValue(dec *json.Decoder) (*dataNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &dataNode{Index: -1}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			node.Kind = nodeObject
+		} else if v == '[' {
+			node.Kind = nodeArray
+		} else {
+			return nil, fmt.Errorf("unexpected %q", rune(v))
+		}
+		for dec.More() {
+			key := ""
+			if node.Kind == nodeObject {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ = keyTok.(string)
+			}
+			child, err := parseJSONValue(dec)
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return nil, err
+			}
+			child.Key = key
+			if node.Kind == nodeArray {
+				child.Index = len(node.Children)
+			}
+			node.Children = append(node.Children, child)
+		}
+		if _, err := dec.Token(); err != nil { // Closing delimiter
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	case string:
+		node.Kind, node.Value = nodeString, v
+	case json.Number:
+		node.Kind, node.Value = nodeNumber, v.String()
+	case bool:
+		node.Kind, node.Value = nodeBool, strconv.FormatBool(v)
+	case nil:
+		node.Kind, node.Value = nodeNull, "null"
+	}
+	return node, nil
+}
+
+// parseYAMLData parses a YAML stream. Several documents become the
+// elements of a root array.
+func parseYAMLData(data []byte) (*dataNode, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var values []*dataNode
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		node := convertYAML(&doc, 0)
+		node.Index = len(values)
+		values = append(values, node)
+	}
+	switch len(values) {
+	case 0:
+		return nil, errors.New("empty document")
+	case 1:
+		values[0].Index = -1
+		return values[0], nil
+	}
+	return &dataNode{Kind: nodeArray, Index: -1, Children: values}, nil
+}
+
+// maxYAMLAliasDepth bounds alias expansion so recursive anchors terminate.
+const maxYAMLAliasDepth = 32
+
+func convertYAML(n *yaml.Node, aliasDepth int) *dataNode {
+	node := &dataNode{Index: -1}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			node.Kind, node.Value = nodeNull, "null"
+			return node
+		}
+		return convertYAML(n.Content[0], aliasDepth)
+	case yaml.AliasNode:
+		if n.Alias == nil || aliasDepth >= maxYAMLAliasDepth {
+			node.Kind, node.Value = nodeString, "*"+n.Value
+			return node
+		}
+		return convertYAML(n.Alias, aliasDepth+1)
+	case yaml.MappingNode:
+		node.Kind = nodeObject
+		local := make(map[string]bool)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			local[n.Content[i].Value] = true
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" && key.ShortTag() == "!!merge" {
+				// Merge keys inline the merged mappings' members; keys set
+				// locally and by earlier merges win
+				merged := []*dataNode{convertYAML(value, aliasDepth)}
+				if merged[0].Kind == nodeArray {
+					merged = merged[0].Children
+				}
+				for _, m := range merged {
+					for _, c := range m.Children {
+						if c.Key != "" && !local[c.Key] && node.child(c.Key) == nil {
+							c.Index = -1
+							node.Children = append(node.Children, c)
+						}
+					}
+				}
+				continue
+			}
+			child := convertYAML(value, aliasDepth)
+			child.Key = key.Value
+			child.Index = -1
+			node.Children = append(node.Children, child)
+		}
+	case yaml.SequenceNode:
+		node.Kind = nodeArray
+		for i, item := range n.Content {
+			child := convertYAML(item, aliasDepth)
+			child.Key = ""
+			child.Index = i
+			node.Children = append(node.Children, child)
+		}
+	default:
+		node.Value = n.Value
+		switch n.ShortTag() {
+		case "!!int", "!!float":
+			node.Kind = nodeNumber
+		case "!!bool":
+			node.Kind = nodeBool
+		case "!!null":
+			node.Kind, node.Value = nodeNull, "null"
+		default:
+			node.Kind = nodeString
+		}
+	}
+	return node
+}
+
+// dataResult is a value selected by a filter, with its path.
+type dataResult struct {
+	Path string
+	Node *dataNode
+}
+
+// filterStep is one step of a parsed filter path.
+type filterStep struct {
+	op    byte // '.' member, '[' index, '*' iterate, 'r' recurse
+	key   string
+	index int
+}
+
+// parseDataFilter parses a jq-style path: .key, ."key", ["key"], [N] and
+// [-N], [] to iterate, and .. for recursive descent. Steps may be joined
+// with |.
+func parseDataFilter(expr string) ([]filterStep, error) {
+	s := strings.TrimSpace(expr)
+	var steps []filterStep
+	for len(s) > 0 {
+		switch {
+		case s[0] == ' ' || s[0] == '|':
+			s = s[1:]
+		case strings.HasPrefix(s, ".."):
+			steps = append(steps, filterStep{op: 'r'})
+			s = s[2:]
+			if len(s) > 0 && s[0] != '.' && s[0] != '[' && s[0] != ' ' && s[0] != '|' {
+				s = "." + s // ..name is short for .. | .name
+			}
+		case s[0] == '.':
+			s = s[1:]
+			switch {
+			case strings.HasPrefix(s, `"`):
+				key, rest, err := cutQuoted(s)
+				if err != nil {
+					return nil, err
+				}
+				steps = append(steps, filterStep{op: '.', key: key})
+				s = rest
+			default:
+				end := strings.IndexAny(s, ".[| ")
+				if end < 0 {
+					end = len(s)
+				}
+				if end > 0 {
+					steps = append(steps, filterStep{op: '.', key: s[:end]})
+				}
+				s = s[end:]
+			}
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, errors.New("missing ]")
+			}
+			inner := strings.TrimSpace(s[1:end])
+			switch {
+			case inner == "":
+				steps = append(steps, filterStep{op: '*'})
+			case strings.HasPrefix(inner, `"`):
+				key, rest, err := cutQuoted(inner)
+				if err != nil || strings.TrimSpace(rest) != "" {
+					return nil, fmt.Errorf("bad key %s", inner)
+				}
+				steps = append(steps, filterStep{op: '.', key: key})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("bad index %s", inner)
+				}
+				steps = append(steps, filterStep{op: '[', index: n})
+			}
+			s = s[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q; paths start with .", s[0])
+		}
+	}
+	return steps, nil
+}
+
+// cutQuoted splits a leading JSON string literal from s.
+func cutQuoted(s string) (string, string, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			key, err := strconv.Unquote(s[:i+1])
+			return key, s[i+1:], err
+		}
+	}
+	return "", "", errors.New("unterminated string")
+}
+
+// evalDataFilter applies a filter to root. Like jq's ? operator, steps
+// that don't apply to a value (a missing key, an index into an object)
+// drop it instead of failing.
+func evalDataFilter(root *dataNode, expr string) ([]dataResult, error) {
+	steps, err := parseDataFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	results := []dataResult{{Node: root}}
+	for _, step := range steps {
+		var next []dataResult
+		for _, r := range results {
+			switch step.op {
+			case '.':
+				if c := r.Node.child(step.key); c != nil {
+					next = append(next, dataResult{Path: r.Path + c.pathSegment(), Node: c})
+				}
+			case '[':
+				if r.Node.Kind != nodeArray {
+					continue
+				}
+				i := step.index
+				if i < 0 {
+					i += len(r.Node.Children)
+				}
+				if i >= 0 && i < len(r.Node.Children) {
+					c := r.Node.Children[i]
+					next = append(next, dataResult{Path: r.Path + c.pathSegment(), Node: c})
+				}
+			case '*':
+				for _, c := range r.Node.Children {
+					next = append(next, dataResult{Path: r.Path + c.pathSegment(), Node: c})
+				}
+			case 'r':
+				next = appendDescendants(next, r)
+			}
+		}
+		results = next
+	}
+	return results, nil
+}
+
+// appendDescendants appends r and every value below it, depth first.
+func appendDescendants(results []dataResult, r dataResult) []dataResult {
+	results = append(results, r)
+	for _, c := range r.Node.Children {
+		results = appendDescendants(results, dataResult{Path: r.Path + c.pathSegment(), Node: c})
+	}
+	return results
+}
+
+// dataTable is tabular data: a CSV file or a page of SQLite rows.
+type dataTable struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// parseCSVData reads CSV or TSV data. The first record is the header;
+// short rows are padded. A truncated file loses its partial last line.
+func parseCSVData(data []byte, comma rune, truncated bool) (*dataTable, error) {
+	if truncated {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty file")
+	}
+	t := &dataTable{Columns: records[0]}
+	for _, rec := range records[1:] {
+		for len(rec) > len(t.Columns) {
+			t.Columns = append(t.Columns, fmt.Sprintf("column%d", len(t.Columns)+1))
+		}
+		t.Rows = append(t.Rows, rec)
+	}
+	for i, row := range t.Rows {
+		for len(row) < len(t.Columns) {
+			row = append(row, "")
+		}
+		t.Rows[i] = row
+	}
+	return t, nil
+}
+
+// sortRowOrder sorts row indices by column col. Numbers sort numerically
+// and before text; text sorts case-insensitively.
+func sortRowOrder(rows [][]string, order []int, col int, desc bool) {
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := rows[order[i]][col], rows[order[j]][col]
+		if desc {
+			a, b = b, a
+		}
+		return compareCells(a, b) < 0
+	})
+}
+
+func compareCells(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,155 @@
+package filebrowser
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqlitePageSize is how many rows are fetched from a table at a time.
+const sqlitePageSize = 200
+
+// sqliteTable is a table or view in a SQLite database.
+type sqliteTable struct {
+	Name string
+	Type string // "table" or "view"
+}
+
+// sqliteQuery selects a page of rows from a table.
+type sqliteQuery struct {
+	Table    string
+	Columns  []string // Known columns, needed to filter
+	OrderBy  string   // Column to sort by, "" for table order
+	Desc     bool
+	Contains string // Keep rows where any column contains this text
+	Offset   int
+}
+
+// openSQLite opens a database read-only. The file: URI form is needed for
+// the driver to honour mode=ro.
+func openSQLite(path string) (*sql.DB, error) {
+	u := url.URL{Scheme: "file", Path: path, RawQuery: "mode=ro"}
+	db, err := sql.Open("sqlite", u.String())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(0)
+	return db, nil
+}
+
+// sqliteListTables lists the tables and views of a database.
+func sqliteListTables(path string) ([]sqliteTable, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.Query(`SELECT name, type FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tables []sqliteTable
+	for rows.Next() {
+		var t sqliteTable
+		if err := rows.Scan(&t.Name, &t.Type); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// sqliteSelect runs q and returns the page of rows with the number of
+// rows matching the filter.
+func sqliteSelect(path string, q sqliteQuery) (*dataTable, int, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = db.Close() }()
+
+	where, args := "", []any(nil)
+	if q.Contains != "" && len(q.Columns) > 0 {
+		conds := make([]string, len(q.Columns))
+		for i, col := range q.Columns {
+			conds[i] = fmt.Sprintf("instr(lower(CAST(%s AS TEXT)), ?) > 0", quoteIdent(col))
+			args = append(args, strings.ToLower(q.Contains))
+		}
+		where = " WHERE " + strings.Join(conds, " OR ")
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*) FROM "+quoteIdent(q.Table)+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT * FROM " + quoteIdent(q.Table) + where
+	if q.OrderBy != "" {
+		query += " ORDER BY " + quoteIdent(q.OrderBy)
+		if q.Desc {
+			query += " DESC"
+		}
+	}
+	query += fmt.Sprintf(" LIMIT %d OFFSET %d", sqlitePageSize, q.Offset)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+	t := &dataTable{Columns: cols}
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, 0, err
+		}
+		row := make([]string, len(cols))
+		for i, v := range values {
+			row[i] = formatSQLiteValue(v)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, total, rows.Err()
+}
+
+// formatSQLiteValue renders a column value for the table view.
+func formatSQLiteValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return fmt.Sprintf("<blob %s>", formatSize(int64(len(v))))
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// quoteIdent quotes a SQLite identifier.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,365 @@
+package filebrowser
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// treeLines describes the visible tree rows as "path=value".
+func treeLines(v *dataView) []string {
+	var out []string
+	for _, r := range v.rows {
+		val := r.node.Value
+		if r.node.isContainer() {
+			val = fmt.Sprintf("%d", len(r.node.Children))
+		}
+		out = append(out, cmp.Or(r.path, ".")+"="+val)
+	}
+	return out
+}
+
+func TestParseJSONData(t *testing.T) {
+	root, err := parseJSONData([]byte(`{"z": 1, "a": [true, null, "x"], "m": {"k": 2.5}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, c := range root.Children {
+		keys = append(keys, c.Key)
+	}
+	if strings.Join(keys, ",") != "z,a,m" {
+		t.Errorf("keys = %v, want file order", keys)
+	}
+	a := root.child("a")
+	if a.Kind != nodeArray || a.Children[0].Kind != nodeBool || a.Children[1].Kind != nodeNull || a.Children[2].Index != 2 {
+		t.Errorf("array = %+v", a.Children)
+	}
+	if n := root.child("m").child("k"); n.Kind != nodeNumber || n.Value != "2.5" {
+		t.Errorf("m.k = %+v", n)
+	}
+
+	// JSON Lines become an array of records
+	root, err = parseJSONData([]byte("{\"id\": 1}\n{\"id\": 2}\n"))
+	if err != nil || root.Kind != nodeArray || len(root.Children) != 2 {
+		t.Fatalf("jsonl = %+v, %v", root, err)
+	}
+
+	if _, err := parseJSONData([]byte(`{"a": }`)); err == nil {
+		t.Error("invalid JSON should fail")
+	}
+}
+
+func TestParseYAMLData(t *testing.T) {
+	src := `base: &base
+  image: app
+  port: 80
+web:
+  <<: *base
+  port: 8080
+tags: [a, b]
+empty: ~
+---
+second: true
+`
+	root, err := parseYAMLData([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Kind != nodeArray || len(root.Children) != 2 {
+		t.Fatalf("documents = %+v", root)
+	}
+	doc := root.Children[0]
+	web := doc.child("web")
+	if web == nil || web.child("image") == nil || web.child("image").Value != "app" {
+		t.Fatalf("merge key not applied: %+v", web)
+	}
+	if web.child("port").Value != "8080" || web.child("port").Kind != nodeNumber {
+		t.Errorf("port = %+v, want the local 8080", web.child("port"))
+	}
+	if doc.child("empty").Kind != nodeNull || doc.child("tags").Kind != nodeArray {
+		t.Errorf("empty/tags = %+v %+v", doc.child("empty"), doc.child("tags"))
+	}
+	if root.Children[1].child("second").Kind != nodeBool {
+		t.Error("second document should parse")
+	}
+}
+
+func TestEvalDataFilter(t *testing.T) {
+	root, err := parseJSONData([]byte(`{
+		"items": [{"name": "a", "meta": {"name": "inner"}}, {"name": "b"}, {"id": 3}],
+		"odd key": 1
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{".", []string{""}},
+		{".items[0].name", []string{".items[0].name"}},
+		{".items[].name", []string{".items[0].name", ".items[1].name"}},
+		{".items[-1]", []string{".items[2]"}},
+		{`.["odd key"]`, []string{`["odd key"]`}},
+		{"..name", []string{".items[0].name", ".items[0].meta.name", ".items[1].name"}},
+		{".items | .[1]", []string{".items[1]"}},
+		{".missing.deeper", nil},
+	}
+	for _, tt := range tests {
+		results, err := evalDataFilter(root, tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Path)
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"items", ".items[", `.["open`, ".a..b["} {
+		if _, err := evalDataFilter(root, expr); err == nil {
+			t.Errorf("%q should be rejected", expr)
+		}
+	}
+}
+
+func TestParseCSVData(t *testing.T) {
+	table, err := parseCSVData([]byte("name,size\nb,10\na,9\n\"c, d\",100,extra\n"), ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(table.Columns, ",") != "name,size,column3" {
+		t.Errorf("columns = %v", table.Columns)
+	}
+	if len(table.Rows) != 3 || table.Rows[2][0] != "c, d" || table.Rows[0][2] != "" {
+		t.Errorf("rows = %q", table.Rows)
+	}
+
+	order := []int{0, 1, 2}
+	sortRowOrder(table.Rows, order, 1, false)
+	if fmt.Sprint(order) != "[1 0 2]" {
+		t.Errorf("numeric sort = %v", order)
+	}
+	sortRowOrder(table.Rows, order, 0, true)
+	if fmt.Sprint(order) != "[2 0 1]" {
+		t.Errorf("text sort desc = %v", order)
+	}
+}
+
+func TestDataViewTreeKeys(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	path := filepath.Join(dir, "data.json")
+	if err := os.WriteFile(path, []byte(`{"a": {"b": [1, 2]}, "c": "x"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.previewFile = "data.json"
+	p.activePane = PanePreview
+	msg := LoadPreview(dir, "data.json", p.ctx.Epoch)().(PreviewLoadedMsg)
+	if msg.Result.Data == nil || msg.Result.Data.Format != dataJSON {
+		t.Fatalf("data = %+v", msg.Result.Data)
+	}
+	p.applyPreviewResult(msg.Result)
+	if !p.dataViewActive() || p.FocusContext() != "file-browser-data" {
+		t.Fatalf("data view active=%v context=%s", p.dataViewActive(), p.FocusContext())
+	}
+	v := p.dataView
+
+	// Top level opens expanded
+	if got := strings.Join(treeLines(v), " "); got != ".a=1 .a.b=2 .c=x" {
+		t.Errorf("rows = %s", got)
+	}
+	p.handlePreviewKey("j")
+	p.handlePreviewKey("l")
+	if got := strings.Join(treeLines(v), " "); got != ".a=1 .a.b=2 .a.b[0]=1 .a.b[1]=2 .c=x" {
+		t.Errorf("after expand = %s", got)
+	}
+	p.handlePreviewKey("l")
+	p.handlePreviewKey("h")
+	if v.cursor != 1 {
+		t.Errorf("h from a child should go to its parent, cursor = %d", v.cursor)
+	}
+	p.handlePreviewKey("<")
+	if len(v.rows) != 2 || v.cursor != 0 {
+		t.Errorf("collapse all rows=%d cursor=%d", len(v.rows), v.cursor)
+	}
+
+	// Filter
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if p.FocusContext() != "file-browser-data-filter" || !p.ConsumesTextInput() {
+		t.Fatalf("filter context = %s", p.FocusContext())
+	}
+	for _, r := range "..b[]" {
+		p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	p.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if v.filtering || v.filter != "..b[]" || len(v.rows) != 2 || v.rows[0].path != ".a.b[0]" {
+		t.Errorf("filter=%q rows=%q", v.filter, treeLines(v))
+	}
+	p.handlePreviewKey("esc")
+	if v.filter != "" || len(v.rows) != 2 {
+		t.Errorf("esc should clear the filter, rows=%q", treeLines(v))
+	}
+
+	// m shows the raw text and back
+	p.handlePreviewKey("m")
+	if p.dataViewActive() {
+		t.Error("m should show raw text")
+	}
+	p.handlePreviewKey("m")
+
+	// Reloading the file keeps the view state
+	v.cursor = 1
+	p.applyPreviewResult(msg.Result)
+	if p.dataView != v || v.cursor != 1 {
+		t.Error("reload should keep the data view")
+	}
+}
+
+func TestDataViewTableKeys(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	if err := os.WriteFile(filepath.Join(dir, "t.tsv"), []byte("id\tname\n2\tbob\n10\tann\n1\tcy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.previewFile = "t.tsv"
+	p.activePane = PanePreview
+	p.applyPreviewResult(LoadPreview(dir, "t.tsv", p.ctx.Epoch)().(PreviewLoadedMsg).Result)
+	v := p.dataView
+	if v == nil || v.table == nil || len(v.table.Columns) != 2 {
+		t.Fatalf("table = %+v", v)
+	}
+
+	column := func(c int) string {
+		var out []string
+		for _, r := range v.order {
+			out = append(out, v.table.Rows[r][c])
+		}
+		return strings.Join(out, ",")
+	}
+	p.handlePreviewKey("s")
+	if column(0) != "1,2,10" {
+		t.Errorf("sorted ids = %s", column(0))
+	}
+	p.handlePreviewKey("s")
+	if column(0) != "10,2,1" {
+		t.Errorf("desc ids = %s", column(0))
+	}
+	p.handlePreviewKey("s")
+	if column(0) != "2,10,1" || v.sortCol != "" {
+		t.Errorf("file order ids = %s", column(0))
+	}
+
+	p.handlePreviewKey("-")
+	if !v.hidden["id"] || v.colCursor != 1 {
+		t.Errorf("hidden=%v colCursor=%d", v.hidden, v.colCursor)
+	}
+	p.handlePreviewKey("-")
+	if len(v.visibleColumns()) != 1 {
+		t.Error("the last visible column should stay")
+	}
+	p.handlePreviewKey("=")
+	if len(v.visibleColumns()) != 2 {
+		t.Error("= should show all columns")
+	}
+
+	v.filter = "AN"
+	v.rebuildOrder()
+	if column(1) != "ann" {
+		t.Errorf("filtered = %s", column(1))
+	}
+	if out := p.renderDataView(60, 10); !strings.Contains(out, "ann") || strings.Contains(out, "bob") {
+		t.Errorf("render = %q", out)
+	}
+}
+
+func TestSQLiteDataView(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	path := filepath.Join(dir, "app.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmts := []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, avatar BLOB)`,
+		`CREATE VIEW named AS SELECT name FROM users`,
+	}
+	for _, s := range stmts {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range sqlitePageSize + 50 {
+		if _, err := db.Exec(`INSERT INTO users (name, avatar) VALUES (?, ?)`, fmt.Sprintf("user%03d", i), []byte{0xff, 0x00}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = db.Close()
+
+	tables, err := sqliteListTables(path)
+	if err != nil || len(tables) != 2 || tables[0].Name != "named" || tables[1].Type != "table" {
+		t.Fatalf("tables = %+v, %v", tables, err)
+	}
+
+	// Opened read-only
+	ro, err := openSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ro.Exec(`DELETE FROM users`); err == nil {
+		t.Error("database should be read-only")
+	}
+	_ = ro.Close()
+
+	table, total, err := sqliteSelect(path, sqliteQuery{
+		Table: "users", Columns: []string{"id", "name", "avatar"},
+		OrderBy: "id", Desc: true, Contains: "USER00",
+	})
+	if err != nil || total != 10 || len(table.Rows) != 10 || table.Rows[0][1] != "user009" || table.Rows[0][2] != "<blob 2B>" {
+		t.Fatalf("select = %+v, %d, %v", table, total, err)
+	}
+
+	// Browse through the plugin, paging rows in
+	p.previewFile = "app.db"
+	p.activePane = PanePreview
+	result := LoadPreview(dir, "app.db", p.ctx.Epoch)().(PreviewLoadedMsg).Result
+	if !result.IsBinary || result.Data == nil || result.Data.Format != dataSQLite {
+		t.Fatalf("result = %+v", result)
+	}
+	p.applyPreviewResult(result)
+	p.handlePreviewKey("j")
+	cmd, _ := p.handleDataViewKey("enter")
+	p.Update(cmd())
+	v := p.dataView
+	if v.sqlTable != "users" || len(v.table.Rows) != sqlitePageSize || v.sqlTotal != sqlitePageSize+50 {
+		t.Fatalf("table=%s rows=%d total=%d", v.sqlTable, len(v.table.Rows), v.sqlTotal)
+	}
+	cmd, _ = p.handleDataViewKey("G")
+	if cmd == nil {
+		t.Fatal("reaching the end should load the next page")
+	}
+	p.Update(cmd())
+	if len(v.table.Rows) != sqlitePageSize+50 {
+		t.Errorf("rows after paging = %d", len(v.table.Rows))
+	}
+
+	// A reload keeps the paged-in rows
+	p.Update(p.reloadDataTable()())
+	if len(v.table.Rows) != sqlitePageSize+50 {
+		t.Errorf("rows after reload = %d", len(v.table.Rows))
+	}
+
+	p.handlePreviewKey("esc")
+	if v.sqlTable != "" || v.cursor != 1 {
+		t.Errorf("esc should return to the table list, table=%q cursor=%d", v.sqlTable, v.cursor)
+	}
+}
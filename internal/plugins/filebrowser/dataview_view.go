@@ -0,0 +1,894 @@
+package filebrowser
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// dataColumnMaxWidth caps the width of a table column.
+const dataColumnMaxWidth = 40
+
+// dataView is the state of the structured preview of a data file.
+type dataView struct {
+	path string // Previewed file, relative
+	doc  *dataDoc
+
+	cursor int
+	offset int // First visible row
+
+	// Filter: a jq path for trees, a text match for tables
+	filter    string
+	filterErr string
+	filtering bool // Typing a filter
+	input     string
+
+	// Tree view (JSON, YAML)
+	expanded map[string]bool // Expanded node paths
+	results  []dataResult    // Filter results shown as roots
+	rows     []dataRow
+
+	// Table view (CSV, open SQLite table)
+	table     *dataTable
+	order     []int           // Table rows after filtering and sorting
+	hidden    map[string]bool // Hidden columns by name
+	sortCol   string          // "" for file order
+	sortDesc  bool
+	colCursor int
+
+	// SQLite
+	sqlTable   string // Open table, "" shows the table list
+	sqlTotal   int    // Rows matching the filter
+	sqlLoading bool
+}
+
+// dataRow is a visible row of the tree view.
+type dataRow struct {
+	node  *dataNode
+	path  string
+	depth int
+	root  bool // Filter result; labelled with its path
+}
+
+// DataRowsLoadedMsg carries a page of rows from a SQLite table.
+type DataRowsLoadedMsg struct {
+	Epoch uint64
+	Path  string
+	Query sqliteQuery
+	Table *dataTable
+	Total int
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m DataRowsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// dataViewActive reports whether the preview shows the structured view.
+func (p *Plugin) dataViewActive() bool {
+	return p.dataView != nil && !p.dataViewRaw
+}
+
+// applyDataDoc shows a freshly loaded data document. Reloading the same
+// file keeps the expanded nodes, filter, sort and position.
+func (p *Plugin) applyDataDoc(doc *dataDoc) {
+	if doc == nil {
+		p.dataView = nil
+		return
+	}
+	v := p.dataView
+	if v == nil || v.path != p.previewFile || v.doc.Format != doc.Format {
+		v = &dataView{path: p.previewFile, expanded: make(map[string]bool), hidden: make(map[string]bool)}
+		if doc.Root != nil && doc.Root.isContainer() {
+			// Open the top level
+			for _, c := range doc.Root.Children {
+				v.expanded[c.pathSegment()] = true
+			}
+		}
+	}
+	v.doc = doc
+	v.filtering = false
+	switch doc.Format {
+	case dataJSON, dataYAML:
+		v.applyTreeFilter()
+	case dataCSV:
+		v.table = doc.Table
+		v.rebuildOrder()
+	}
+	v.clamp()
+	p.dataView = v
+}
+
+// reloadDataTable re-reads the open SQLite table after the database
+// changed, keeping the rows already paged in.
+func (p *Plugin) reloadDataTable() tea.Cmd {
+	v := p.dataView
+	if v == nil || v.doc.Format != dataSQLite || v.sqlTable == "" {
+		return nil
+	}
+	keep := 0
+	if v.table != nil {
+		keep = len(v.table.Rows)
+	}
+	return p.loadSQLiteRows(v.query(0), keep)
+}
+
+// query builds the SQLite query for the open table.
+func (v *dataView) query(offset int) sqliteQuery {
+	q := sqliteQuery{Table: v.sqlTable, OrderBy: v.sortCol, Desc: v.sortDesc, Contains: v.filter, Offset: offset}
+	if v.table != nil {
+		q.Columns = v.table.Columns
+	}
+	return q
+}
+
+// loadSQLiteRows fetches a page of rows. When reloading from the start,
+// keep pages in at least that many rows so a refresh keeps the position.
+func (p *Plugin) loadSQLiteRows(q sqliteQuery, keep int) tea.Cmd {
+	v := p.dataView
+	v.sqlLoading = true
+	epoch := p.ctx.Epoch
+	path, dbPath := v.path, v.doc.Path
+	return func() tea.Msg {
+		t, total, err := sqliteSelect(dbPath, q)
+		next := q
+		for err == nil && len(t.Rows) < keep && len(t.Rows) < total {
+			next.Offset = q.Offset + len(t.Rows)
+			var more *dataTable
+			if more, _, err = sqliteSelect(dbPath, next); err == nil {
+				if len(more.Rows) == 0 {
+					break
+				}
+				t.Rows = append(t.Rows, more.Rows...)
+			}
+		}
+		return DataRowsLoadedMsg{Epoch: epoch, Path: path, Query: q, Table: t, Total: total, Err: err}
+	}
+}
+
+// handleDataRowsLoaded shows or appends a page of SQLite rows.
+func (p *Plugin) handleDataRowsLoaded(msg DataRowsLoadedMsg) {
+	v := p.dataView
+	if v == nil || v.path != msg.Path || v.sqlTable != msg.Query.Table {
+		return
+	}
+	v.sqlLoading = false
+	if msg.Err != nil {
+		v.filterErr = msg.Err.Error()
+		return
+	}
+	v.filterErr = ""
+	v.sqlTotal = msg.Total
+	if msg.Query.Offset > 0 && v.table != nil {
+		v.table.Rows = append(v.table.Rows, msg.Table.Rows...)
+	} else {
+		v.table = msg.Table
+	}
+	v.order = v.order[:0]
+	for i := range v.table.Rows {
+		v.order = append(v.order, i)
+	}
+	v.colCursor = min(v.colCursor, max(len(v.table.Columns)-1, 0))
+	v.clamp()
+}
+
+// openSQLiteTable browses the table under the cursor.
+func (p *Plugin) openSQLiteTable() tea.Cmd {
+	v := p.dataView
+	if v.cursor >= len(v.doc.Tables) {
+		return nil
+	}
+	v.sqlTable = v.doc.Tables[v.cursor].Name
+	v.table = nil
+	v.order = nil
+	v.filter, v.filterErr = "", ""
+	v.sortCol, v.sortDesc = "", false
+	v.hidden = make(map[string]bool)
+	v.cursor, v.offset, v.colCursor = 0, 0, 0
+	return p.loadSQLiteRows(v.query(0), 0)
+}
+
+// closeSQLiteTable returns to the table list with the cursor on the table.
+func (v *dataView) closeSQLiteTable() {
+	name := v.sqlTable
+	v.sqlTable = ""
+	v.table = nil
+	v.order = nil
+	v.filter, v.filterErr = "", ""
+	v.cursor, v.offset = 0, 0
+	for i, t := range v.doc.Tables {
+		if t.Name == name {
+			v.cursor = i
+		}
+	}
+}
+
+// isTable reports whether the view shows a table.
+func (v *dataView) isTable() bool {
+	return v.doc.Format == dataCSV || (v.doc.Format == dataSQLite && v.sqlTable != "")
+}
+
+// rowCount returns the number of rows the cursor moves over.
+func (v *dataView) rowCount() int {
+	switch {
+	case v.isTable():
+		return len(v.order)
+	case v.doc.Format == dataSQLite:
+		return len(v.doc.Tables)
+	}
+	return len(v.rows)
+}
+
+func (v *dataView) clamp() {
+	v.cursor = max(0, min(v.cursor, v.rowCount()-1))
+	v.offset = max(0, min(v.offset, v.cursor))
+}
+
+// ensureVisible scrolls so the cursor is within height rows.
+func (v *dataView) ensureVisible(height int) {
+	height = max(height, 1)
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	} else if v.cursor >= v.offset+height {
+		v.offset = v.cursor - height + 1
+	}
+}
+
+// headerLines is the number of lines above the rows.
+func (v *dataView) headerLines() int {
+	if v.isTable() {
+		return 3 // Info, column headers, rule
+	}
+	return 1 // Breadcrumb or table count
+}
+
+// applyTreeFilter evaluates the filter and rebuilds the visible rows.
+func (v *dataView) applyTreeFilter() {
+	v.results = nil
+	v.filterErr = ""
+	if v.filter != "" && v.doc.Root != nil {
+		results, err := evalDataFilter(v.doc.Root, v.filter)
+		if err != nil {
+			v.filterErr = err.Error()
+		} else {
+			v.results = results
+			if v.results == nil {
+				v.results = []dataResult{}
+			}
+		}
+	}
+	v.rebuildRows()
+}
+
+// rebuildRows flattens the expanded tree into rows.
+func (v *dataView) rebuildRows() {
+	v.rows = v.rows[:0]
+	root := v.doc.Root
+	switch {
+	case root == nil:
+	case v.results != nil:
+		for _, r := range v.results {
+			v.rows = v.appendRows(v.rows, r.Node, r.Path, 0, true)
+		}
+	case root.isContainer():
+		for _, c := range root.Children {
+			v.rows = v.appendRows(v.rows, c, c.pathSegment(), 0, false)
+		}
+	default:
+		v.rows = append(v.rows, dataRow{node: root, root: true})
+	}
+}
+
+func (v *dataView) appendRows(rows []dataRow, n *dataNode, path string, depth int, root bool) []dataRow {
+	rows = append(rows, dataRow{node: n, path: path, depth: depth, root: root})
+	if n.isContainer() && v.expanded[path] {
+		for _, c := range n.Children {
+			rows = v.appendRows(rows, c, path+c.pathSegment(), depth+1, false)
+		}
+	}
+	return rows
+}
+
+// setExpanded expands or collapses the node at path, and with all set,
+// every node below it.
+func (v *dataView) setExpanded(n *dataNode, path string, open, all bool) {
+	if !n.isContainer() {
+		return
+	}
+	if open {
+		v.expanded[path] = true
+	} else {
+		delete(v.expanded, path)
+	}
+	if all {
+		for _, c := range n.Children {
+			v.setExpanded(c, path+c.pathSegment(), open, true)
+		}
+	}
+}
+
+// rebuildOrder filters and sorts the CSV rows.
+func (v *dataView) rebuildOrder() {
+	v.order = v.order[:0]
+	t := v.table
+	if t == nil {
+		return
+	}
+	needle := strings.ToLower(v.filter)
+	for i, row := range t.Rows {
+		if needle == "" || rowContains(row, needle) {
+			v.order = append(v.order, i)
+		}
+	}
+	if col := columnIndex(t.Columns, v.sortCol); col >= 0 {
+		sortRowOrder(t.Rows, v.order, col, v.sortDesc)
+	}
+	v.colCursor = min(v.colCursor, max(len(t.Columns)-1, 0))
+}
+
+func rowContains(row []string, needle string) bool {
+	for _, cell := range row {
+		if strings.Contains(strings.ToLower(cell), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func columnIndex(columns []string, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, c := range columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// visibleColumns returns the indices of columns that are not hidden.
+func (v *dataView) visibleColumns() []int {
+	var cols []int
+	for i, c := range v.table.Columns {
+		if !v.hidden[c] {
+			cols = append(cols, i)
+		}
+	}
+	return cols
+}
+
+// handleDataViewKey handles keys for the structured preview. It reports
+// false for keys the preview should handle as usual.
+func (p *Plugin) handleDataViewKey(key string) (tea.Cmd, bool) {
+	v := p.dataView
+	height := max(p.visibleContentHeight()-v.headerLines(), 1)
+
+	switch key {
+	case "j", "down":
+		v.cursor++
+	case "k", "up":
+		v.cursor--
+	case "g":
+		v.cursor = 0
+	case "G":
+		v.cursor = v.rowCount() - 1
+	case "ctrl+d":
+		v.cursor += height / 2
+	case "ctrl+u":
+		v.cursor -= height / 2
+	case "ctrl+f", "pgdown":
+		v.cursor += height
+	case "ctrl+b", "pgup":
+		v.cursor -= height
+	case "/":
+		v.filtering = true
+		v.input = v.filter
+		return nil, true
+	case "esc":
+		switch {
+		case v.filter != "":
+			return p.setDataFilter(""), true
+		case v.doc.Format == dataSQLite && v.sqlTable != "":
+			v.closeSQLiteTable()
+			return nil, true
+		}
+		return nil, false
+	default:
+		var cmd tea.Cmd
+		var handled bool
+		switch {
+		case v.isTable():
+			cmd, handled = p.handleDataTableKey(key)
+		case v.doc.Format == dataSQLite:
+			if key == "enter" || key == "l" || key == "right" {
+				return p.openSQLiteTable(), true
+			}
+		default:
+			handled = v.handleTreeKey(key)
+		}
+		if !handled {
+			return nil, false
+		}
+		v.clamp()
+		v.ensureVisible(height)
+		return cmd, true
+	}
+	v.clamp()
+	v.ensureVisible(height)
+	return p.pageSQLiteRows(), true
+}
+
+// pageSQLiteRows loads the next page when the cursor reaches the last
+// loaded row of a SQLite table.
+func (p *Plugin) pageSQLiteRows() tea.Cmd {
+	v := p.dataView
+	if v.doc.Format != dataSQLite || v.table == nil || v.sqlLoading {
+		return nil
+	}
+	if v.cursor < len(v.order)-1 || len(v.table.Rows) >= v.sqlTotal {
+		return nil
+	}
+	return p.loadSQLiteRows(v.query(len(v.table.Rows)), 0)
+}
+
+// handleTreeKey handles expand and collapse keys in the tree view.
+func (v *dataView) handleTreeKey(key string) bool {
+	if v.cursor >= len(v.rows) {
+		return false
+	}
+	row := v.rows[v.cursor]
+	switch key {
+	case "enter", " ", "space":
+		v.setExpanded(row.node, row.path, !v.expanded[row.path], false)
+	case "l", "right":
+		if !row.node.isContainer() {
+			return true
+		}
+		if !v.expanded[row.path] {
+			v.setExpanded(row.node, row.path, true, false)
+		} else if len(row.node.Children) > 0 {
+			v.cursor++ // Step into the first child
+		}
+	case "h", "left":
+		if row.node.isContainer() && v.expanded[row.path] {
+			v.setExpanded(row.node, row.path, false, false)
+			break
+		}
+		if row.depth == 0 {
+			return false // Back to the file tree
+		}
+		for i := v.cursor - 1; i >= 0; i-- {
+			if v.rows[i].depth < row.depth {
+				v.cursor = i
+				break
+			}
+		}
+	case ">":
+		v.setExpanded(row.node, row.path, true, true)
+	case "<":
+		// Collapse everything, keeping the cursor on the top-level row
+		for i := v.cursor; i >= 0; i-- {
+			if v.rows[i].depth == 0 {
+				v.cursor = i
+				break
+			}
+		}
+		v.expanded = make(map[string]bool)
+	default:
+		return false
+	}
+	v.rebuildRows()
+	return true
+}
+
+// handleDataTableKey handles column, sort and hide keys in table views.
+func (p *Plugin) handleDataTableKey(key string) (tea.Cmd, bool) {
+	v := p.dataView
+	if v.table == nil {
+		return nil, false
+	}
+	cols := v.visibleColumns()
+	pos := 0
+	for i, c := range cols {
+		if c == v.colCursor {
+			pos = i
+		}
+	}
+	switch key {
+	case "h", "left":
+		if pos == 0 {
+			return nil, false // Back to the file tree
+		}
+		v.colCursor = cols[pos-1]
+	case "l", "right":
+		if pos < len(cols)-1 {
+			v.colCursor = cols[pos+1]
+		}
+	case "s":
+		// Cycle ascending, descending, file order
+		name := v.table.Columns[v.colCursor]
+		switch {
+		case v.sortCol != name:
+			v.sortCol, v.sortDesc = name, false
+		case !v.sortDesc:
+			v.sortDesc = true
+		default:
+			v.sortCol, v.sortDesc = "", false
+		}
+		return p.refilterTable(), true
+	case "-":
+		if len(cols) > 1 {
+			v.hidden[v.table.Columns[v.colCursor]] = true
+			if pos < len(cols)-1 {
+				v.colCursor = cols[pos+1]
+			} else {
+				v.colCursor = cols[pos-1]
+			}
+		}
+	case "=":
+		v.hidden = make(map[string]bool)
+	case "backspace":
+		if v.doc.Format == dataSQLite {
+			v.closeSQLiteTable()
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// refilterTable applies the filter and sort: in memory for CSV, by
+// querying again for SQLite.
+func (p *Plugin) refilterTable() tea.Cmd {
+	v := p.dataView
+	v.cursor, v.offset = 0, 0
+	if v.doc.Format == dataSQLite {
+		return p.loadSQLiteRows(v.query(0), 0)
+	}
+	v.rebuildOrder()
+	return nil
+}
+
+// setDataFilter applies a filter typed in the filter bar.
+func (p *Plugin) setDataFilter(filter string) tea.Cmd {
+	v := p.dataView
+	v.filter = strings.TrimSpace(filter)
+	if v.isTable() {
+		return p.refilterTable()
+	}
+	v.cursor, v.offset = 0, 0
+	v.applyTreeFilter()
+	return nil
+}
+
+// handleDataFilterKey handles typing in the data view filter bar.
+func (p *Plugin) handleDataFilterKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	v := p.dataView
+	key := msg.String()
+	switch key {
+	case "esc":
+		v.filtering = false
+		v.input = ""
+	case "enter":
+		cmd := p.setDataFilter(v.input)
+		if v.filterErr == "" {
+			v.filtering = false
+		}
+		return p, cmd
+	case "backspace":
+		if len(v.input) > 0 {
+			runes := []rune(v.input)
+			v.input = string(runes[:len(runes)-1])
+		}
+	case "ctrl+u":
+		v.input = ""
+	default:
+		if msg.Type == tea.KeyRunes || key == " " {
+			v.input += string(msg.Runes)
+			if key == " " && len(msg.Runes) == 0 {
+				v.input += " "
+			}
+		}
+	}
+	return p, nil
+}
+
+// label names the structured view in the preview header.
+func (v *dataView) label() string {
+	switch v.doc.Format {
+	case dataCSV:
+		return "table"
+	case dataSQLite:
+		return "database"
+	}
+	return "tree"
+}
+
+// renderDataView renders the structured preview in height lines.
+func (p *Plugin) renderDataView(width, height int) string {
+	v := p.dataView
+	if v.doc.Err != nil {
+		return styles.StatusDeleted.Render(ansi.Wrap(v.doc.Err.Error(), width, "")) + "\n" +
+			styles.Muted.Render("Press m for the raw text")
+	}
+	rowsHeight := max(height-v.headerLines(), 1)
+	v.ensureVisible(rowsHeight)
+
+	var lines []string
+	switch {
+	case v.isTable():
+		lines = p.renderDataTable(width, rowsHeight)
+	case v.doc.Format == dataSQLite:
+		lines = p.renderSQLiteTables(width, rowsHeight)
+	default:
+		lines = p.renderDataTree(width, rowsHeight)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderInfo renders the first line: the filter being typed, a filter
+// error, or info about the view.
+func (v *dataView) renderInfo(info string, width int) string {
+	switch {
+	case v.filtering:
+		hint := "jq path, e.g. .items[].name"
+		if v.isTable() {
+			hint = "text to match in any column"
+		}
+		line := styles.StatusModified.Render("Filter: ") + v.input + "█"
+		if v.filterErr != "" {
+			return ansi.Truncate(line+"  "+styles.StatusDeleted.Render(v.filterErr), width, "…")
+		}
+		if v.input == "" {
+			line += "  " + styles.Muted.Render(hint)
+		}
+		return ansi.Truncate(line, width, "…")
+	case v.filterErr != "":
+		return styles.StatusDeleted.Render(ansi.Truncate(v.filterErr, width, "…"))
+	}
+	return styles.Muted.Render(ansi.Truncate(info, width, "…"))
+}
+
+func (p *Plugin) renderDataTree(width, height int) []string {
+	v := p.dataView
+	breadcrumb := "."
+	if v.cursor < len(v.rows) {
+		breadcrumb = cmp.Or(v.rows[v.cursor].path, ".")
+	}
+	if v.filter != "" {
+		breadcrumb = fmt.Sprintf("%s  · filter %s: %s", breadcrumb, v.filter, pluralResults(len(v.results)))
+	}
+	lines := []string{v.renderInfo(breadcrumb, width)}
+	if len(v.rows) == 0 {
+		return append(lines, styles.Muted.Render("No values"))
+	}
+	end := min(len(v.rows), v.offset+height)
+	for i := v.offset; i < end; i++ {
+		lines = append(lines, p.renderDataTreeRow(v.rows[i], i == v.cursor, width))
+	}
+	return lines
+}
+
+func pluralResults(n int) string {
+	if n == 1 {
+		return "1 result"
+	}
+	return fmt.Sprintf("%d results", n)
+}
+
+// renderDataTreeRow renders a key and its value, or a container summary.
+func (p *Plugin) renderDataTreeRow(row dataRow, selected bool, width int) string {
+	n := row.node
+	indent := strings.Repeat("  ", row.depth)
+	marker := "  "
+	if n.isContainer() {
+		marker = "▸ "
+		if p.dataView.expanded[row.path] {
+			marker = "▾ "
+		}
+	}
+
+	label := n.Key
+	switch {
+	case row.root:
+		label = cmp.Or(row.path, ".")
+	case n.Index >= 0:
+		label = fmt.Sprintf("[%d]", n.Index)
+	}
+
+	var value string
+	var valueStyle lipgloss.Style
+	switch n.Kind {
+	case nodeObject:
+		value, valueStyle = fmt.Sprintf("{} %d keys", len(n.Children)), styles.Muted
+		if len(n.Children) == 1 {
+			value = "{} 1 key"
+		}
+	case nodeArray:
+		value, valueStyle = fmt.Sprintf("[] %d items", len(n.Children)), styles.Muted
+		if len(n.Children) == 1 {
+			value = "[] 1 item"
+		}
+	case nodeString:
+		value, valueStyle = strconv.Quote(n.Value), lipgloss.NewStyle().Foreground(styles.Success)
+	case nodeNumber:
+		value, valueStyle = n.Value, lipgloss.NewStyle().Foreground(styles.Accent)
+	case nodeBool:
+		value, valueStyle = n.Value, lipgloss.NewStyle().Foreground(styles.Primary)
+	default:
+		value, valueStyle = n.Value, styles.Muted
+	}
+
+	prefix := indent + marker
+	sep := ": "
+	if label == "" {
+		sep = ""
+	}
+	avail := max(width-ansi.StringWidth(prefix), 4)
+	label = ansi.Truncate(label, max(avail*2/3, 4), "…")
+	value = ansi.Truncate(value, max(avail-ansi.StringWidth(label)-len(sep), 1), "…")
+
+	if selected {
+		plain := prefix + label + sep + value
+		return styles.ListItemSelected.Render(plain + strings.Repeat(" ", max(width-ansi.StringWidth(plain), 0)))
+	}
+	keyStyle := lipgloss.NewStyle().Foreground(styles.Secondary)
+	if n.Index >= 0 && !row.root {
+		keyStyle = styles.Muted
+	}
+	return styles.Muted.Render(prefix) + keyStyle.Render(label) + sep + valueStyle.Render(value)
+}
+
+func (p *Plugin) renderSQLiteTables(width, height int) []string {
+	v := p.dataView
+	info := fmt.Sprintf("%d tables and views · enter to browse", len(v.doc.Tables))
+	lines := []string{v.renderInfo(info, width)}
+	if len(v.doc.Tables) == 0 {
+		return append(lines, styles.Muted.Render("No tables"))
+	}
+	end := min(len(v.doc.Tables), v.offset+height)
+	for i := v.offset; i < end; i++ {
+		t := v.doc.Tables[i]
+		name := ansi.Truncate(t.Name, max(width-10, 4), "…")
+		pad := strings.Repeat(" ", max(width-ansi.StringWidth(name)-len(t.Type), 1))
+		if i == v.cursor {
+			lines = append(lines, styles.ListItemSelected.Render(name+pad+t.Type))
+		} else {
+			lines = append(lines, name+pad+styles.Muted.Render(t.Type))
+		}
+	}
+	return lines
+}
+
+// renderDataTable renders the info line, column headers and the rows,
+// scrolled sideways to keep the column cursor in view.
+func (p *Plugin) renderDataTable(width, height int) []string {
+	v := p.dataView
+	t := v.table
+	if t == nil {
+		return []string{v.renderInfo("table "+v.sqlTable, width), "", styles.Muted.Render("Loading...")}
+	}
+	lines := []string{v.renderInfo(v.tableInfo(), width)}
+
+	cols := v.visibleColumns()
+	end := min(len(v.order), v.offset+height)
+	widths := make(map[int]int, len(cols))
+	for _, c := range cols {
+		w := ansi.StringWidth(t.Columns[c]) + 2 // Room for the sort arrow
+		for _, r := range v.order[v.offset:end] {
+			w = max(w, ansi.StringWidth(cellText(t.Rows[r][c])))
+		}
+		widths[c] = min(w, dataColumnMaxWidth)
+	}
+
+	// First column shown: scroll right until the column cursor fits
+	first := 0
+	for i, c := range cols {
+		if c != v.colCursor {
+			continue
+		}
+		used := 0
+		first = i
+		for j := i; j >= 0; j-- {
+			used += widths[cols[j]] + 3
+			if used > width {
+				break
+			}
+			first = j
+		}
+	}
+	shown := cols[first:]
+
+	var header, rule []string
+	for _, c := range shown {
+		name := t.Columns[c]
+		if name == v.sortCol {
+			name += map[bool]string{false: " ↑", true: " ↓"}[v.sortDesc]
+		}
+		cell := padCell(name, widths[c])
+		if c == v.colCursor {
+			cell = lipgloss.NewStyle().Bold(true).Underline(true).Render(cell)
+		} else {
+			cell = lipgloss.NewStyle().Bold(true).Render(cell)
+		}
+		header = append(header, cell)
+		rule = append(rule, strings.Repeat("─", widths[c]))
+	}
+	sep := styles.Muted.Render(" │ ")
+	lines = append(lines,
+		ansi.Truncate(strings.Join(header, sep), width, "…"),
+		styles.Muted.Render(ansi.Truncate(strings.Join(rule, "─┼─"), width, "")))
+
+	if len(v.order) == 0 {
+		return append(lines, styles.Muted.Render("No rows"))
+	}
+	for i := v.offset; i < end; i++ {
+		row := t.Rows[v.order[i]]
+		cells := make([]string, len(shown))
+		for j, c := range shown {
+			cells[j] = padCell(cellText(row[c]), widths[c])
+		}
+		if i == v.cursor {
+			line := ansi.Truncate(strings.Join(cells, " │ "), width, "…")
+			lines = append(lines, styles.ListItemSelected.Render(line+strings.Repeat(" ", max(width-ansi.StringWidth(line), 0))))
+		} else {
+			lines = append(lines, ansi.Truncate(strings.Join(cells, sep), width, "…"))
+		}
+	}
+	return lines
+}
+
+// tableInfo summarises the table: rows, sort, hidden columns and filter.
+func (v *dataView) tableInfo() string {
+	t := v.table
+	total := len(t.Rows)
+	if v.doc.Format == dataSQLite {
+		total = v.sqlTotal
+	}
+	parts := []string{fmt.Sprintf("%d rows × %d columns", total, len(t.Columns))}
+	if v.doc.Format == dataSQLite {
+		parts[0] = v.sqlTable + " · " + parts[0]
+		if len(t.Rows) < total {
+			parts = append(parts, fmt.Sprintf("%d loaded", len(t.Rows)))
+		}
+	}
+	if v.doc.Truncated {
+		parts = append(parts, "first "+formatSize(maxPreviewSize))
+	}
+	if v.filter != "" {
+		matched := len(v.order)
+		if v.doc.Format == dataSQLite {
+			matched = v.sqlTotal
+		}
+		parts = append(parts, fmt.Sprintf("filter %q: %d", v.filter, matched))
+	}
+	if v.sortCol != "" {
+		dir := "↑"
+		if v.sortDesc {
+			dir = "↓"
+		}
+		parts = append(parts, "sorted by "+v.sortCol+" "+dir)
+	}
+	if n := len(t.Columns) - len(v.visibleColumns()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d hidden", n))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// cellText flattens a cell onto one line.
+func cellText(s string) string {
+	return strings.NewReplacer("\r\n", "↵", "\n", "↵", "\t", " ").Replace(s)
+}
+
+// padCell truncates or pads s to width columns.
+func padCell(s string, width int) string {
+	s = ansi.Truncate(s, width, "…")
+	return s + strings.Repeat(" ", max(width-ansi.StringWidth(s), 0))
+}
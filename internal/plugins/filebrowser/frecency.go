@@ -0,0 +1,198 @@
+package filebrowser
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/state"
+)
+
+const (
+	maxFileVisits    = 500 // Visited files remembered per project
+	maxJumpHistory   = 100 // Entries in the jump history
+	frecencyMaxBonus = 40  // Most a file's frecency adds to its fuzzy score
+)
+
+// fileVisit tracks how often and how recently a file was opened.
+type fileVisit struct {
+	count int
+	last  time.Time
+}
+
+// frecency weights the visit count by how recently the file was opened,
+// so a file opened twice today outranks one opened ten times last month.
+func (v fileVisit) frecency(now time.Time) float64 {
+	age := now.Sub(v.last)
+	weight := 0.25
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 0.5
+	}
+	return float64(v.count) * weight
+}
+
+// jumpEntry is a file in the jump history and where it was scrolled to.
+type jumpEntry struct {
+	path   string
+	scroll int
+}
+
+// noteOpen records a file the user opened, as opposed to previewed while
+// browsing the tree, in the jump history and counts the visit. Coming
+// back to the current history entry, as jumps do, is not a new visit.
+func (p *Plugin) noteOpen(path string) {
+	if cur := p.currentJump(); path == "" || cur != nil && cur.path == path {
+		return
+	}
+	p.recordVisit(path, time.Now())
+
+	// Opening a file drops the entries ahead, as in a browser
+	p.jumps = append(p.jumps[:min(p.jumpPos+1, len(p.jumps))], jumpEntry{path: path})
+	if len(p.jumps) > maxJumpHistory {
+		p.jumps = p.jumps[len(p.jumps)-maxJumpHistory:]
+	}
+	p.jumpPos = len(p.jumps) - 1
+}
+
+// currentJump returns the jump history entry of the last opened file.
+func (p *Plugin) currentJump() *jumpEntry {
+	if p.jumpPos < 0 || p.jumpPos >= len(p.jumps) {
+		return nil
+	}
+	return &p.jumps[p.jumpPos]
+}
+
+// jumpBack returns to the previously opened file. After browsing away
+// with previews it first returns to the last opened file.
+func (p *Plugin) jumpBack() tea.Cmd {
+	target := p.jumpPos - 1
+	if cur := p.currentJump(); cur != nil && cur.path != p.previewFile {
+		target = p.jumpPos
+	}
+	return p.jumpTo(target, "No earlier file in history")
+}
+
+// jumpForward undoes a jumpBack.
+func (p *Plugin) jumpForward() tea.Cmd {
+	return p.jumpTo(p.jumpPos+1, "No later file in history")
+}
+
+func (p *Plugin) jumpTo(idx int, none string) tea.Cmd {
+	if idx < 0 || idx >= len(p.jumps) {
+		return appmsg.ShowToast(none, 2*time.Second)
+	}
+	p.saveActiveTabState() // Remember where we leave the current file
+	p.jumpPos = idx
+	entry := p.jumps[idx]
+	cmd := p.openTabAtLine(entry.path, entry.scroll+1, TabOpenReplace)
+	p.pinTab(p.activeTab)
+	return cmd
+}
+
+// recordVisit counts an open of path for frecency ranking.
+func (p *Plugin) recordVisit(path string, now time.Time) {
+	if p.visits == nil {
+		p.visits = make(map[string]fileVisit)
+	}
+	v := p.visits[path]
+	v.count++
+	v.last = now
+	p.visits[path] = v
+
+	if len(p.visits) > maxFileVisits {
+		// Forget the file that matters least
+		var weakest string
+		for candidate, v := range p.visits {
+			if weakest == "" || v.frecency(now) < p.visits[weakest].frecency(now) {
+				weakest = candidate
+			}
+		}
+		delete(p.visits, weakest)
+	}
+}
+
+// frecencyBonus returns how much a file's frecency adds to its quick open
+// score. It grows slowly with use so that a frequently opened file wins
+// between similar matches without burying a clearly better match.
+func (p *Plugin) frecencyBonus(path string, now time.Time) int {
+	v, ok := p.visits[path]
+	if !ok {
+		return 0
+	}
+	return min(int(10*math.Log2(1+v.frecency(now))), frecencyMaxBonus)
+}
+
+// rankByFrecency adds the frecency bonus to fuzzy matches and sorts them
+// again, keeping the best maxResults.
+func (p *Plugin) rankByFrecency(matches []QuickOpenMatch, maxResults int) []QuickOpenMatch {
+	now := time.Now()
+	for i := range matches {
+		matches[i].Score += p.frecencyBonus(matches[i].Path, now)
+	}
+	FuzzySort(matches)
+	if len(matches) > maxResults {
+		matches = matches[:maxResults]
+	}
+	return matches
+}
+
+// recentFiles returns the visited files that still exist, most recently
+// opened first, leaving out the previewed file.
+func (p *Plugin) recentFiles() []string {
+	files := make([]string, 0, len(p.visits))
+	for path := range p.visits {
+		if path == p.previewFile {
+			continue
+		}
+		// Archive entries are checked through their archive
+		if _, _, inArchive := splitArchivePath(p.ctx.WorkDir, path); !inArchive {
+			if _, err := os.Stat(filepath.Join(p.ctx.WorkDir, path)); err != nil {
+				continue // Deleted or moved since
+			}
+		}
+		files = append(files, path)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return p.visits[files[i]].last.After(p.visits[files[j]].last)
+	})
+	return files
+}
+
+// visitStates returns the visited files for saving, most recent first.
+func (p *Plugin) visitStates() []state.FileVisitState {
+	visits := make([]state.FileVisitState, 0, len(p.visits))
+	for path, v := range p.visits {
+		visits = append(visits, state.FileVisitState{Path: path, Count: v.count, Last: v.last})
+	}
+	sort.Slice(visits, func(i, j int) bool {
+		return visits[i].Last.After(visits[j].Last)
+	})
+	return visits
+}
+
+// restoreVisits merges saved visits with those recorded since startup.
+func (p *Plugin) restoreVisits(saved []state.FileVisitState) {
+	if p.visits == nil {
+		p.visits = make(map[string]fileVisit, len(saved))
+	}
+	for _, s := range saved {
+		if s.Path == "" || s.Count <= 0 {
+			continue
+		}
+		v := p.visits[s.Path]
+		v.count += s.Count
+		if s.Last.After(v.last) {
+			v.last = s.Last
+		}
+		p.visits[s.Path] = v
+	}
+}
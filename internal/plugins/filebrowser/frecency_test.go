@@ -0,0 +1,170 @@
+package filebrowser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/mouse"
+	"github.com/marcus/sidecar/internal/state"
+)
+
+func TestQuickOpenFrecency(t *testing.T) {
+	p, _ := newBatchTestPlugin(t, "web/index.ts", "src/app/index.ts", "README.md")
+	p.buildFileCache()
+	now := time.Now()
+
+	_, _ = p.openQuickOpen()
+	p.quickOpenQuery = "index"
+	p.updateQuickOpenMatches()
+	if got := p.quickOpenMatches[0].Path; got != filepath.Join("web", "index.ts") {
+		t.Fatalf("unvisited best match = %s", got)
+	}
+
+	// Opening a file often and recently ranks it first
+	deep := filepath.Join("src", "app", "index.ts")
+	p.recordVisit(deep, now.Add(-time.Minute))
+	p.recordVisit(deep, now.Add(-time.Minute))
+	p.updateQuickOpenMatches()
+	if got := p.quickOpenMatches[0].Path; got != deep {
+		t.Errorf("visited best match = %s, want %s", got, deep)
+	}
+
+	// Old visits count for less, and the bonus never beats a better match
+	old := fileVisit{count: 2, last: now.Add(-30 * 24 * time.Hour)}
+	if old.frecency(now) >= p.visits[deep].frecency(now) {
+		t.Error("recent visits should outweigh old ones")
+	}
+	for range 50 {
+		p.recordVisit("README.md", now)
+	}
+	if bonus := p.frecencyBonus("README.md", now); bonus != frecencyMaxBonus {
+		t.Errorf("bonus = %d, want capped at %d", bonus, frecencyMaxBonus)
+	}
+	p.quickOpenQuery = "ind"
+	p.updateQuickOpenMatches()
+	for _, m := range p.quickOpenMatches {
+		if m.Path == "README.md" {
+			t.Error("frecency must not add files that do not match")
+		}
+	}
+
+	// An empty query lists recently opened files first
+	p.quickOpenQuery = ""
+	p.updateQuickOpenMatches()
+	if got := p.quickOpenMatches[0].Path; got != "README.md" {
+		t.Errorf("empty query first = %s, want README.md", got)
+	}
+	if n := len(p.quickOpenMatches); n != 3 {
+		t.Errorf("empty query lists %d files, want 3", n)
+	}
+}
+
+func TestRecentFilesModal(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.go", "b.go", "c.go", "d.go")
+	now := time.Now()
+	p.recordVisit("a.go", now.Add(-3*time.Hour))
+	p.recordVisit("b.go", now.Add(-2*time.Hour))
+	p.recordVisit("c.go", now.Add(-time.Hour))
+	p.previewFile = "c.go"
+	if err := os.Remove(filepath.Join(dir, "a.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	p.mouseHandler = mouse.NewHandler()
+	_, _ = p.openRecentFiles()
+	var paths []string
+	for _, m := range p.quickOpenMatches {
+		paths = append(paths, m.Path)
+	}
+	if got := strings.Join(paths, " "); got != "b.go" {
+		t.Errorf("recent files = %q, want the existing files other than the previewed one", got)
+	}
+	if !strings.Contains(p.renderQuickOpenModalContent(), "Recent Files:") {
+		t.Error("modal should be titled Recent Files")
+	}
+
+	// ctrl+e switches to all files and back, keeping the query
+	p.quickOpenQuery = "go"
+	p.handleQuickOpenKey(tea.KeyMsg{Type: tea.KeyCtrlE})
+	if p.quickOpenRecent != nil || len(p.quickOpenMatches) != 3 {
+		t.Errorf("all files = %+v", p.quickOpenMatches)
+	}
+	p.handleQuickOpenKey(tea.KeyMsg{Type: tea.KeyCtrlE})
+	if p.quickOpenRecent == nil || len(p.quickOpenMatches) != 1 {
+		t.Errorf("recent files = %+v", p.quickOpenMatches)
+	}
+
+	_, _ = p.selectQuickOpenMatch()
+	if p.previewFile != "b.go" || p.quickOpenMode || p.quickOpenRecent != nil {
+		t.Errorf("select opened %s", p.previewFile)
+	}
+	if v := p.visits["b.go"]; v.count != 2 {
+		t.Errorf("b.go visits = %d, want 2", v.count)
+	}
+}
+
+func TestJumpHistory(t *testing.T) {
+	p, _ := newBatchTestPlugin(t, "a.go", "b.go", "c.go", "d.go")
+	for _, path := range []string{"a.go", "b.go", "c.go"} {
+		p.openTab(path, TabOpenReplace)
+	}
+	p.previewScroll = 7
+
+	p.jumpBack()
+	if p.previewFile != "b.go" {
+		t.Fatalf("back = %s, want b.go", p.previewFile)
+	}
+	p.jumpBack()
+	p.jumpForward()
+	p.jumpForward()
+	if p.previewFile != "c.go" || p.previewScroll != 7 {
+		t.Errorf("forward = %s at %d, want c.go at 7", p.previewFile, p.previewScroll)
+	}
+	if v := p.visits["b.go"]; v.count != 1 {
+		t.Errorf("jumps counted as visits: b.go = %d", v.count)
+	}
+
+	// Opening a file from the middle drops the forward history
+	p.jumpBack()
+	p.openTab("d.go", TabOpenReplace)
+	if cmd := p.jumpForward(); cmd == nil || p.previewFile != "d.go" {
+		t.Errorf("forward after open = %s, want a toast and d.go", p.previewFile)
+	}
+
+	// Previews while browsing are not history; back returns to the opened file
+	p.openTab("a.go", TabOpenPreview)
+	p.jumpBack()
+	if p.previewFile != "d.go" {
+		t.Errorf("back from preview = %s, want d.go", p.previewFile)
+	}
+
+	// Pinning a preview opens it
+	p.openTab("c.go", TabOpenPreview)
+	p.pinTab(p.activeTab)
+	p.jumpBack()
+	if p.previewFile != "d.go" {
+		t.Errorf("back from pinned preview = %s, want d.go", p.previewFile)
+	}
+}
+
+func TestVisitsPersist(t *testing.T) {
+	if err := state.InitWithDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	p, dir := newBatchTestPlugin(t, "a.go")
+	p.ctx.ProjectRoot = dir
+	p.stateRestored = true
+	p.openTab("a.go", TabOpenReplace)
+	p.saveState()
+
+	restored, _ := newBatchTestPlugin(t)
+	restored.restoreVisits(state.GetFileBrowserState(dir).Visits)
+	restored.restoreVisits([]state.FileVisitState{{Path: "a.go", Count: 2}})
+	if v := restored.visits["a.go"]; v.count != 3 || v.last.IsZero() {
+		t.Errorf("restored visit = %+v", v)
+	}
+}
@@ -0,0 +1,329 @@
+package filebrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// diffView shows the previewed file's changes against HEAD in place of
+// its contents.
+type diffView struct {
+	path    string
+	loading bool
+	lines   []diffLine // Hunk headers and lines, empty without changes
+	binary  bool
+	err     error
+	scroll  int // First diff line shown
+}
+
+// diffLine is a line of a unified diff.
+type diffLine struct {
+	kind  byte // '@' for a hunk header, '+', '-' or ' '
+	oldNo int  // Line number in HEAD, 0 for added lines
+	newNo int  // Line number in the working tree, 0 for removed lines
+	text  string
+}
+
+// parseDiffLines parses the hunks of a single-file unified diff.
+func parseDiffLines(diff string) (lines []diffLine, binary bool) {
+	var oldNo, newNo int
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			// @@ -old[,count] +new[,count] @@ context
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			oldNo = hunkStart(fields[1])
+			newNo = hunkStart(fields[2])
+			inHunk = true
+			lines = append(lines, diffLine{kind: '@', text: line})
+		case !inHunk:
+			if strings.HasPrefix(line, "Binary files ") {
+				binary = true
+			}
+		case strings.HasPrefix(line, "+"):
+			lines = append(lines, diffLine{kind: '+', newNo: newNo, text: line[1:]})
+			newNo++
+		case strings.HasPrefix(line, "-"):
+			lines = append(lines, diffLine{kind: '-', oldNo: oldNo, text: line[1:]})
+			oldNo++
+		case strings.HasPrefix(line, " "):
+			lines = append(lines, diffLine{kind: ' ', oldNo: oldNo, newNo: newNo, text: line[1:]})
+			oldNo++
+			newNo++
+		}
+	}
+	return lines, binary
+}
+
+// hunkStart parses the start line from a hunk range like "-12,7".
+func hunkStart(r string) int {
+	r, _, _ = strings.Cut(r[1:], ",")
+	n, _ := strconv.Atoi(r)
+	return n
+}
+
+// GitDiffLoadedMsg carries the diff of a file against HEAD.
+type GitDiffLoadedMsg struct {
+	Epoch uint64
+	Path  string
+	Diff  string
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m GitDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// loadGitDiff diffs a file against HEAD. Untracked files are diffed
+// against nothing, so every line shows as added.
+func loadGitDiff(workDir, path string, untracked bool, epoch uint64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		args := []string{"diff", "--no-color", "--no-ext-diff", "HEAD", "--", path}
+		if untracked {
+			args = []string{"diff", "--no-color", "--no-ext-diff", "--no-index", "--", os.DevNull, path}
+		}
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = workDir
+		out, err := cmd.Output()
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if untracked && exitErr.ExitCode() == 1 {
+				err = nil // --no-index exits 1 when the files differ
+			} else if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				err = errors.New(msg)
+			}
+		}
+		return GitDiffLoadedMsg{Epoch: epoch, Path: path, Diff: string(out), Err: err}
+	}
+}
+
+// diffViewActive reports whether the preview shows the diff of the
+// previewed file.
+func (p *Plugin) diffViewActive() bool {
+	return p.diffView != nil && p.diffView.path == p.previewFile
+}
+
+// toggleGitDiff turns diff mode on or off. While it is on, every file
+// previewed shows its diff against HEAD.
+func (p *Plugin) toggleGitDiff() tea.Cmd {
+	if p.diffMode {
+		p.diffMode = false
+		p.diffView = nil
+		return nil
+	}
+	if p.tree.Git == nil {
+		return appmsg.ShowToast("Not a git repository", 2*time.Second)
+	}
+	p.diffMode = true
+	return p.ensureGitDiff()
+}
+
+// ensureGitDiff starts loading the diff of the previewed file when diff
+// mode is on.
+func (p *Plugin) ensureGitDiff() tea.Cmd {
+	if !p.diffMode || p.previewFile == "" || p.diffViewActive() {
+		return nil
+	}
+	p.diffView = &diffView{path: p.previewFile, loading: true}
+	return p.gitDiffCmd(p.diffView)
+}
+
+// reloadGitDiff diffs the previewed file again, keeping the position.
+func (p *Plugin) reloadGitDiff() tea.Cmd {
+	if !p.diffViewActive() {
+		return nil
+	}
+	return p.gitDiffCmd(p.diffView)
+}
+
+func (p *Plugin) gitDiffCmd(v *diffView) tea.Cmd {
+	if _, _, ok := splitArchivePath(p.ctx.WorkDir, v.path); ok {
+		v.loading, v.err = false, errors.New("archive entries are not tracked by git")
+		return nil
+	}
+	untracked := p.tree.Git.state(v.path) == gitUntracked
+	return loadGitDiff(p.ctx.WorkDir, v.path, untracked, p.ctx.Epoch)
+}
+
+// handleGitDiffLoaded parses a loaded diff into the diff view.
+func (p *Plugin) handleGitDiffLoaded(msg GitDiffLoadedMsg) {
+	v := p.diffView
+	if v == nil || v.path != msg.Path {
+		return
+	}
+	v.loading, v.err = false, msg.Err
+	v.lines, v.binary = parseDiffLines(msg.Diff)
+	v.scroll = max(min(v.scroll, p.maxDiffScroll()), 0)
+}
+
+// diffRows returns the number of diff lines that fit below the info line.
+func (p *Plugin) diffRows() int {
+	return max(p.visibleContentHeight()-1, 1)
+}
+
+func (p *Plugin) maxDiffScroll() int {
+	if p.diffView == nil {
+		return 0
+	}
+	return max(len(p.diffView.lines)-p.diffRows(), 0)
+}
+
+// hunkStarts returns the diff line of each hunk header.
+func (v *diffView) hunkStarts() []int {
+	var starts []int
+	for i, line := range v.lines {
+		if line.kind == '@' {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// handleDiffKey handles keys for the diff view. It reports false for keys
+// it leaves to the preview.
+func (p *Plugin) handleDiffKey(key string) (tea.Cmd, bool) {
+	v := p.diffView
+	rows := p.diffRows()
+	scroll := v.scroll
+
+	switch key {
+	case "j", "down":
+		scroll++
+	case "k", "up":
+		scroll--
+	case "ctrl+d":
+		scroll += rows / 2
+	case "ctrl+u":
+		scroll -= rows / 2
+	case "ctrl+f", "pgdown":
+		scroll += rows
+	case "ctrl+b", "pgup":
+		scroll -= rows
+	case "g":
+		scroll = 0
+	case "G":
+		scroll = p.maxDiffScroll()
+	case "n":
+		for _, start := range v.hunkStarts() {
+			if start > v.scroll {
+				scroll = start
+				break
+			}
+		}
+	case "N":
+		for _, start := range v.hunkStarts() {
+			if start >= v.scroll {
+				break
+			}
+			scroll = start
+		}
+	case "d", "esc":
+		return p.toggleGitDiff(), true
+	case "/", ":", "w", "m", "F":
+		return nil, true // Apply to file contents, not the diff
+	default:
+		return nil, false
+	}
+
+	v.scroll = max(min(scroll, p.maxDiffScroll()), 0)
+	return nil, true
+}
+
+// renderDiffView renders the diff of the previewed file below an info line.
+func (p *Plugin) renderDiffView(width, height int) string {
+	v := p.diffView
+	info := p.renderDiffInfo(width)
+	switch {
+	case v.loading && len(v.lines) == 0:
+		return info + "\n" + styles.Muted.Render("Loading diff...")
+	case v.err != nil:
+		return info + "\n" + styles.StatusDeleted.Render(ansi.Truncate(v.err.Error(), width, "…"))
+	case v.binary:
+		return info + "\n" + styles.Muted.Render("Binary file differs")
+	case len(v.lines) == 0:
+		return info + "\n" + styles.Muted.Render("No changes against HEAD")
+	}
+
+	digits := 4
+	for _, line := range v.lines {
+		digits = max(digits, len(strconv.Itoa(max(line.oldNo, line.newNo))))
+	}
+	number := func(n int) string {
+		if n == 0 {
+			return strings.Repeat(" ", digits)
+		}
+		return styles.FileBrowserLineNumber.Width(digits).Render(strconv.Itoa(n))
+	}
+	textWidth := max(width-2*digits-4, 10)
+
+	rows := max(height-1, 1)
+	out := []string{info}
+	for _, line := range v.lines[v.scroll:min(v.scroll+rows, len(v.lines))] {
+		if line.kind == '@' {
+			out = append(out, styles.DiffHeader.Render(ansi.Truncate(line.text, width, "…")))
+			continue
+		}
+		text := ansi.Truncate(string(line.kind)+ui.ExpandTabs(line.text, 8), textWidth, "…")
+		switch line.kind {
+		case '+':
+			text = styles.DiffAdd.Render(text)
+		case '-':
+			text = styles.DiffRemove.Render(text)
+		default:
+			text = styles.DiffContext.Render(text)
+		}
+		out = append(out, number(line.oldNo)+" "+number(line.newNo)+"  "+text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderDiffInfo renders the change counts and position in the diff.
+func (p *Plugin) renderDiffInfo(width int) string {
+	v := p.diffView
+	title := "diff against HEAD"
+	if st := p.tree.Git.state(v.path); st != gitClean {
+		title += " (" + st.label() + ")"
+	}
+	parts := []string{styles.Muted.Render(title)}
+	if starts := v.hunkStarts(); len(starts) > 0 {
+		var added, removed int
+		for _, line := range v.lines {
+			switch line.kind {
+			case '+':
+				added++
+			case '-':
+				removed++
+			}
+		}
+		hunk := 1
+		for i, start := range starts {
+			if start <= v.scroll {
+				hunk = i + 1
+			}
+		}
+		parts = append(parts,
+			styles.StatusStaged.Render(fmt.Sprintf("+%d", added))+" "+styles.StatusDeleted.Render(fmt.Sprintf("-%d", removed)),
+			styles.Muted.Render(fmt.Sprintf("hunk %d of %d (n/N)", hunk, len(starts))))
+	}
+	parts = append(parts, styles.Muted.Render("d to close"))
+	return ansi.Truncate(strings.Join(parts, styles.Muted.Render(" · ")), width, "…")
+}
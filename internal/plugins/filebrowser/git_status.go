@@ -0,0 +1,271 @@
+package filebrowser
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// gitState is the git state of a path. States are ordered so that a
+// directory rolls up the most pressing state of its contents.
+type gitState int
+
+const (
+	gitClean gitState = iota
+	gitUntracked
+	gitAdded
+	gitRenamed
+	gitDeleted
+	gitModified
+	gitConflicted
+)
+
+// badge returns the letter shown next to a node in this state.
+func (s gitState) badge() string {
+	switch s {
+	case gitUntracked:
+		return "?"
+	case gitAdded:
+		return "A"
+	case gitRenamed:
+		return "R"
+	case gitDeleted:
+		return "D"
+	case gitModified:
+		return "M"
+	case gitConflicted:
+		return "U"
+	}
+	return ""
+}
+
+// label returns the state in words.
+func (s gitState) label() string {
+	switch s {
+	case gitUntracked:
+		return "untracked"
+	case gitAdded:
+		return "added"
+	case gitRenamed:
+		return "renamed"
+	case gitDeleted:
+		return "deleted"
+	case gitModified:
+		return "modified"
+	case gitConflicted:
+		return "conflicted"
+	}
+	return "clean"
+}
+
+// style returns the style for badges in this state.
+func (s gitState) style() lipgloss.Style {
+	switch s {
+	case gitUntracked:
+		return styles.StatusUntracked
+	case gitAdded, gitRenamed:
+		return styles.StatusStaged
+	case gitConflicted, gitDeleted:
+		return styles.StatusDeleted
+	}
+	return styles.StatusModified
+}
+
+// gitStatus is the working tree state reported by git status, keyed by
+// path relative to the work dir.
+type gitStatus struct {
+	files map[string]gitState // Changed files, and untracked directories
+	dirs  map[string]gitState // Directories holding changes, rolled up
+}
+
+// GitStatusMsg is sent when git status has been read. Status is nil
+// outside a git repository.
+type GitStatusMsg struct {
+	Epoch  uint64
+	Status *gitStatus
+	Err    error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m GitStatusMsg) GetEpoch() uint64 { return m.Epoch }
+
+// loadGitStatus reads the state of the work dir from git status.
+func loadGitStatus(workDir string, epoch uint64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Status paths are relative to the repo root, not the work dir
+		prefixCmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-prefix")
+		prefixCmd.Dir = workDir
+		prefix, err := prefixCmd.Output()
+		if err != nil {
+			return GitStatusMsg{Epoch: epoch} // Not a repository
+		}
+
+		cmd := exec.CommandContext(ctx, "git", "-c", "core.quotepath=off", "status", "--porcelain=v1", "-z", "--untracked-files=normal", "--", ".")
+		cmd.Dir = workDir
+		out, err := cmd.Output()
+		if err != nil {
+			return GitStatusMsg{Epoch: epoch, Err: err}
+		}
+		return GitStatusMsg{Epoch: epoch, Status: parseGitStatus(out, strings.TrimSpace(string(prefix)))}
+	}
+}
+
+// parseGitStatus parses `git status --porcelain=v1 -z` output. Paths
+// outside prefix, the work dir relative to the repo root, are dropped.
+func parseGitStatus(out []byte, prefix string) *gitStatus {
+	s := &gitStatus{files: make(map[string]gitState), dirs: make(map[string]gitState)}
+	fields := bytes.Split(out, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		field := string(fields[i])
+		if len(field) < 4 {
+			continue
+		}
+		xy, path := field[:2], field[3:]
+		if xy[0] == 'R' || xy[0] == 'C' || xy[1] == 'R' || xy[1] == 'C' {
+			i++ // The original path follows, also for worktree renames
+		}
+		path, ok := strings.CutPrefix(path, prefix)
+		if !ok || path == "" {
+			continue
+		}
+		st := parseXY(xy)
+		if st == gitClean {
+			continue
+		}
+		path = filepath.FromSlash(strings.TrimSuffix(path, "/"))
+		s.files[path] = st
+		for dir := filepath.Dir(path); dir != "."; dir = filepath.Dir(dir) {
+			s.dirs[dir] = max(s.dirs[dir], st)
+		}
+	}
+	return s
+}
+
+// parseXY maps a porcelain status code to a state.
+func parseXY(xy string) gitState {
+	x, y := xy[0], xy[1]
+	switch {
+	case x == 'U' || y == 'U' || xy == "AA" || xy == "DD":
+		return gitConflicted
+	case xy == "??":
+		return gitUntracked
+	case xy == "!!":
+		return gitClean
+	case x == 'R' || x == 'C' || y == 'R' || y == 'C':
+		return gitRenamed
+	case x == 'A':
+		return gitAdded
+	case x == 'D' || y == 'D':
+		return gitDeleted
+	}
+	return gitModified
+}
+
+// state returns the state of a path. Directories report the rolled-up
+// state of their contents; paths in untracked directories are untracked.
+func (s *gitStatus) state(path string) gitState {
+	if s == nil {
+		return gitClean
+	}
+	if st, ok := s.files[path]; ok {
+		return st
+	}
+	if st, ok := s.dirs[path]; ok {
+		return st
+	}
+	for dir := filepath.Dir(path); dir != "."; dir = filepath.Dir(dir) {
+		if s.files[dir] == gitUntracked {
+			return gitUntracked
+		}
+	}
+	return gitClean
+}
+
+// rolledUp reports whether path is a directory whose state comes from
+// changes inside it.
+func (s *gitStatus) rolledUp(path string) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.dirs[path]
+	return ok
+}
+
+// gitBadge returns the badge shown next to a tree node, if any.
+func (p *Plugin) gitBadge(node *FileNode) (string, lipgloss.Style) {
+	if node.InArchive() {
+		return "", styles.Muted
+	}
+	if node.IsIgnored {
+		return "!", styles.Muted
+	}
+	st := p.tree.Git.state(node.Path)
+	if node.IsDir && p.tree.Git.rolledUp(node.Path) {
+		return "●", st.style()
+	}
+	return st.badge(), st.style()
+}
+
+// handleGitStatus applies fresh git status to the tree, keeping the
+// cursor on the same node.
+func (p *Plugin) handleGitStatus(msg GitStatusMsg) tea.Cmd {
+	if msg.Err != nil {
+		p.ctx.Logger.Warn("file browser: git status failed", "error", msg.Err)
+		return nil
+	}
+	p.tree.Git = msg.Status
+	if msg.Status == nil {
+		p.changedOnly, p.tree.ChangedOnly = false, false
+	} else if p.changedOnly {
+		p.tree.ExpandChanged()
+	}
+	p.reflattenTree()
+	return p.reloadGitDiff()
+}
+
+// toggleChangedOnly switches the tree between all files and only the
+// files with git changes.
+func (p *Plugin) toggleChangedOnly() tea.Cmd {
+	if p.tree.Git == nil {
+		return appmsg.ShowToast("Not a git repository", 2*time.Second)
+	}
+	p.changedOnly = !p.changedOnly
+	p.tree.ChangedOnly = p.changedOnly
+	if p.changedOnly {
+		p.tree.ExpandChanged()
+	}
+	p.reflattenTree()
+	if node := p.tree.GetNode(p.treeCursor); node != nil && !node.IsDir && node.Path != p.previewFile {
+		return p.openTab(node.Path, TabOpenReplace)
+	}
+	return nil
+}
+
+// reflattenTree rebuilds the visible nodes, keeping the cursor on the
+// same path when it is still shown.
+func (p *Plugin) reflattenTree() {
+	var selected string
+	if node := p.tree.GetNode(p.treeCursor); node != nil {
+		selected = node.Path
+	}
+	p.tree.Flatten()
+	for i, node := range p.tree.FlatList {
+		if node.Path == selected {
+			p.treeCursor = i
+			break
+		}
+	}
+	p.treeCursor = max(min(p.treeCursor, p.tree.Len()-1), 0)
+	p.ensureTreeCursorVisible()
+}
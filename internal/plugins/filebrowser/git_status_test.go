@@ -0,0 +1,170 @@
+package filebrowser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGitStatus(t *testing.T) {
+	out := strings.Join([]string{
+		"M  web/pkg/a.go",
+		"?? web/new/",
+		"R  web/pkg/b.go", "web/pkg/old.go",
+		"UU web/c.txt",
+		"A  web/pkg/deep/n.go",
+		" M docs/outside.md",
+	}, "\x00") + "\x00"
+	s := parseGitStatus([]byte(out), "web/")
+
+	tests := map[string]gitState{
+		"pkg/a.go":                         gitModified,
+		"pkg/b.go":                         gitRenamed,
+		"pkg/old.go":                       gitClean,
+		"pkg":                              gitModified, // Rolled up from a.go
+		filepath.Join("pkg", "deep"):       gitAdded,
+		"new":                              gitUntracked,
+		filepath.Join("new", "x", "y.txt"): gitUntracked,
+		"c.txt":                            gitConflicted,
+		"docs/outside.md":                  gitClean,
+		"clean.txt":                        gitClean,
+	}
+	for path, want := range tests {
+		if got := s.state(filepath.FromSlash(path)); got != want {
+			t.Errorf("state(%s) = %s, want %s", path, got.label(), want.label())
+		}
+	}
+	if !s.rolledUp("pkg") || s.rolledUp("new") {
+		t.Error("only directories holding changes roll up; untracked directories are changes themselves")
+	}
+
+	// Renames in the worktree, as after `git add -N`, list the source too
+	s = parseGitStatus([]byte(" R new.go\x00old.go\x00 M b.go\x00"), "")
+	want := map[string]gitState{"new.go": gitRenamed, "b.go": gitModified}
+	if !reflect.DeepEqual(s.files, want) {
+		t.Errorf("worktree rename files = %v, want %v", s.files, want)
+	}
+
+	var none *gitStatus
+	if none.state("a") != gitClean {
+		t.Error("no status means clean")
+	}
+}
+
+func TestParseDiffLines(t *testing.T) {
+	diff := "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n" +
+		"@@ -3,3 +3,3 @@ func main() {\n one\n-two\n+TWO\n three\n" +
+		"@@ -20 +20,2 @@\n--- dashes\n+--- dashes\n+more\n\\ No newline at end of file\n"
+	lines, binary := parseDiffLines(diff)
+	if binary || len(lines) != 9 {
+		t.Fatalf("binary=%v lines=%+v", binary, lines)
+	}
+	if l := lines[2]; l.kind != '-' || l.oldNo != 4 || l.newNo != 0 || l.text != "two" {
+		t.Errorf("removed = %+v", l)
+	}
+	if l := lines[4]; l.kind != ' ' || l.oldNo != 5 || l.newNo != 5 {
+		t.Errorf("context = %+v", l)
+	}
+	if l := lines[6]; l.kind != '-' || l.text != "-- dashes" || l.oldNo != 20 {
+		t.Errorf("removed dashes = %+v", l)
+	}
+	if l := lines[8]; l.kind != '+' || l.newNo != 21 {
+		t.Errorf("added = %+v", l)
+	}
+	if _, binary := parseDiffLines("Binary files a/x.png and b/x.png differ\n"); !binary {
+		t.Error("binary diff not detected")
+	}
+}
+
+// gitRun runs git in dir with a fixed identity.
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v (%s)", args, err, out)
+	}
+}
+
+func TestGitDecorations(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	p, dir := newBatchTestPlugin(t, "a.txt", "pkg/x.go", "pkg/y.go")
+	gitRun(t, dir, "init", "-q")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "init")
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "x.go"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "new.go"), []byte("package pkg\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.tree.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	runStreamCmds(p, loadGitStatus(dir, p.ctx.Epoch))
+
+	badges := map[string]string{"pkg": "●", filepath.Join("pkg", "x.go"): "M", filepath.Join("pkg", "new.go"): "?", "a.txt": ""}
+	for path, want := range badges {
+		if got, _ := p.gitBadge(p.tree.FindByPath(path)); got != want {
+			t.Errorf("badge(%s) = %q, want %q", path, got, want)
+		}
+	}
+
+	// Changed-only shows the changed files and their directory
+	all := p.tree.Len()
+	p.treeCursor = 0
+	p.handleTreeKey("c")
+	var shown []string
+	for _, node := range p.tree.FlatList {
+		shown = append(shown, filepath.ToSlash(node.Path))
+	}
+	if got := strings.Join(shown, " "); got != "pkg pkg/new.go pkg/x.go" {
+		t.Errorf("changed only = %s", got)
+	}
+	if !strings.Contains(p.renderTreePane(10), "[changed]") {
+		t.Error("header should show the filter")
+	}
+
+	// Diff mode follows the previewed file
+	p.previewFile = filepath.Join("pkg", "x.go")
+	runStreamCmds(p, p.toggleGitDiff())
+	v := p.diffView
+	if !p.diffViewActive() || v.err != nil || len(v.hunkStarts()) != 1 {
+		t.Fatalf("diff view = %+v", v)
+	}
+	var kinds string
+	for _, line := range v.lines {
+		kinds += string(line.kind)
+	}
+	if kinds != "@-+" || v.lines[1].text != "pkg/x.go" || v.lines[2].text != "changed" {
+		t.Errorf("diff lines = %+v", v.lines)
+	}
+	if out := p.renderDiffView(80, 10); !strings.Contains(out, "+1") || !strings.Contains(out, "-1") {
+		t.Errorf("render = %q", out)
+	}
+
+	p.previewFile = filepath.Join("pkg", "new.go")
+	runStreamCmds(p, p.ensureGitDiff())
+	if v := p.diffView; v.err != nil || len(v.lines) != 2 || v.lines[1].kind != '+' {
+		t.Errorf("untracked diff = %+v", v)
+	}
+
+	p.previewFile = "a.txt"
+	runStreamCmds(p, p.ensureGitDiff())
+	if out := p.renderDiffView(80, 10); !strings.Contains(out, "No changes") {
+		t.Errorf("clean render = %q", out)
+	}
+	p.handlePreviewKey("d")
+	if p.diffMode || p.diffViewActive() {
+		t.Error("d should leave diff mode")
+	}
+
+	p.handleTreeKey("c")
+	if p.tree.Len() != all {
+		t.Errorf("all files = %d nodes, want %d", p.tree.Len(), all)
+	}
+}
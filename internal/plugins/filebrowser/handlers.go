@@ -10,6 +10,7 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
 	appmsg "github.com/marcus/sidecar/internal/msg"
 	"github.com/marcus/sidecar/internal/plugin"
 	"github.com/marcus/sidecar/internal/state"
@@ -28,11 +29,26 @@ func (p *Plugin) handleKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		return p.handleQuickOpenKey(msg)
 	}
 
+	// Handle symbol outline / symbol quick open
+	if p.symbolMode {
+		return p.handleSymbolKey(msg)
+	}
+
 	// Handle info modal
 	if p.infoMode {
 		return p.handleInfoKey(msg)
 	}
 
+	// Handle batch result modal
+	if p.batchResult != nil {
+		return p.handleBatchResultKey(msg)
+	}
+
+	// Handle trash view
+	if p.trashMode {
+		return p.handleTrashKey(msg)
+	}
+
 	// Handle blame mode
 	if p.blameMode {
 		return p.handleBlameKey(msg)
@@ -58,10 +74,37 @@ func (p *Plugin) handleKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		return p.handleSearchKey(msg)
 	}
 
+	// Handle data view filter input
+	if p.dataViewActive() && p.dataView.filtering {
+		return p.handleDataFilterKey(msg)
+	}
+
+	// Handle hex view search and offset input
+	if p.hexViewActive() && p.hexView.prompt != hexPromptNone {
+		return p.handleHexPromptKey(msg)
+	}
+
+	// Handle stream view search input
+	if p.streamViewActive() && p.streamView.prompt {
+		return p.handleStreamPromptKey(msg)
+	}
+
 	// Quick open and project search only from tree/preview (not during text input modes)
 	if key == "ctrl+p" {
 		return p.openQuickOpen()
 	}
+	if key == "ctrl+e" {
+		return p.openRecentFiles()
+	}
+	if key == "ctrl+o" {
+		return p, p.jumpBack()
+	}
+	if key == "alt+i" {
+		return p, p.jumpForward()
+	}
+	if key == "ctrl+t" {
+		return p, p.openSymbolSearch()
+	}
 	if key == "f" {
 		return p.openProjectSearch()
 	}
@@ -74,6 +117,10 @@ func (p *Plugin) handleKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 }
 
 func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
+	if node := p.tree.GetNode(p.treeCursor); node != nil && node.InArchive() && archiveReadOnlyKeys[key] {
+		return p, appmsg.ShowToast("Archive entries are read-only (X to extract)", 2*time.Second)
+	}
+
 	switch key {
 	case "j", "down":
 		if p.treeCursor < p.tree.Len()-1 {
@@ -92,8 +139,10 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 	case "l", "right":
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil {
-			if node.IsDir {
-				_ = p.tree.Expand(node)
+			if node.Expandable() {
+				if err := p.tree.Expand(node); err != nil && node.IsArchive {
+					return p, appmsg.ShowToast("Cannot open archive: "+err.Error(), 3*time.Second)
+				}
 			} else {
 				// Load file preview, switch to preview pane, and pin the tab
 				p.activePane = PanePreview
@@ -106,9 +155,11 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 	case "enter":
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil {
-			if node.IsDir {
+			if node.Expandable() {
 				// Toggle expand/collapse
-				_ = p.tree.Toggle(node)
+				if err := p.tree.Toggle(node); err != nil && node.IsArchive {
+					return p, appmsg.ShowToast("Cannot open archive: "+err.Error(), 3*time.Second)
+				}
 			} else {
 				// Load file preview, switch to preview pane, and pin the tab
 				p.activePane = PanePreview
@@ -121,7 +172,7 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 	case "h", "left":
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil {
-			if node.IsDir && node.IsExpanded {
+			if node.Expandable() && node.IsExpanded {
 				p.tree.Collapse(node)
 			} else if node.Parent != nil && node.Parent != p.tree.Root {
 				if idx := p.tree.IndexOf(node.Parent); idx >= 0 {
@@ -195,6 +246,9 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case "t":
+		if p.hasSelection() {
+			return p, p.openSelectionInTabs()
+		}
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil && !node.IsDir {
 			p.activePane = PanePreview
@@ -240,6 +294,14 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 			return p, p.fetchGitInfo(node.Path)
 		}
 
+	case "c":
+		// Show only files with git changes
+		return p, p.toggleChangedOnly()
+
+	case "d":
+		// Preview files as their changes against HEAD
+		return p, p.toggleGitDiff()
+
 	case "B":
 		// Show git blame for file
 		node := p.tree.GetNode(p.treeCursor)
@@ -247,17 +309,62 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 			return p.openBlameView(node.Path)
 		}
 
+	case "L":
+		// Show git history for file in the git tab, or open a submodule there
+		node := p.tree.GetNode(p.treeCursor)
+		if node != nil && node.IsSubmodule {
+			return p, p.openSubmodule(node.Path)
+		}
+		if node != nil && !node.IsDir {
+			return p, p.openFileHistory(node.Path)
+		}
+
+	case "P":
+		// Preview and apply a patch or mailbox file in the git tab
+		node := p.tree.GetNode(p.treeCursor)
+		if node != nil && !node.IsDir {
+			return p, p.applyPatch(node.Path)
+		}
+
 	case "r":
 		// Refresh file tree
 		p.lastRefresh = time.Now()
 		return p, p.refresh()
 
 	case "m":
-		// Move file/directory
+		// Move file/directory, or every selected item into a directory
+		if p.hasSelection() {
+			items := p.takeSelection()
+			if len(items) == 0 {
+				return p, nil
+			}
+			dir := ""
+			if node := p.tree.GetNode(p.treeCursor); node != nil {
+				dir = filepath.Dir(node.Path)
+				if node.IsDir {
+					dir = node.Path
+				}
+			}
+			p.fileOpMode = FileOpMove
+			p.fileOpTarget = nil
+			p.fileOpBatch = items
+			p.fileOpTextInput = textinput.New()
+			p.fileOpTextInput.Placeholder = "directory (empty for project root)"
+			if dir != "." {
+				p.fileOpTextInput.SetValue(dir)
+			}
+			p.fileOpTextInput.Focus()
+			p.fileOpTextInput.CursorEnd()
+			p.fileOpError = ""
+			p.fileOpButtonFocus = 0
+			p.fileOpShowSuggestions = false
+			return p, nil
+		}
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil && node != p.tree.Root {
 			p.fileOpMode = FileOpMove
 			p.fileOpTarget = node
+			p.fileOpBatch = nil
 			p.fileOpTextInput = textinput.New()
 			p.fileOpTextInput.SetValue(node.Path)
 			p.fileOpTextInput.Focus()
@@ -294,22 +401,45 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case "D":
-		// Delete file/directory (requires confirmation)
+		// Delete file/directory or the selection (requires confirmation)
+		if p.hasSelection() {
+			if items := p.takeSelection(); len(items) > 0 {
+				p.fileOpMode = FileOpDelete
+				p.fileOpTarget = nil
+				p.fileOpBatch = items
+				p.fileOpConfirmDelete = true
+				p.fileOpError = ""
+				p.fileOpButtonFocus = 1
+			}
+			return p, nil
+		}
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil && node != p.tree.Root {
 			p.fileOpMode = FileOpDelete
 			p.fileOpTarget = node
+			p.fileOpBatch = nil
 			p.fileOpConfirmDelete = true
 			p.fileOpError = ""
 			p.fileOpButtonFocus = 1 // Start with confirm button focused
 		}
 
 	case "y":
-		// Yank (mark) file/directory for paste
+		// Yank (mark) file/directory or the selection for paste
+		if p.hasSelection() {
+			items := p.takeSelection()
+			p.clearSelection()
+			if len(items) == 0 {
+				return p, nil
+			}
+			p.clipboardPath = ""
+			p.clipboardBatch = items
+			return p, appmsg.ShowToast(fmt.Sprintf("Marked %s for copy", pluralItems(len(items))), 2*time.Second)
+		}
 		node := p.tree.GetNode(p.treeCursor)
 		if node != nil && node != p.tree.Root {
 			p.clipboardPath = node.Path
 			p.clipboardIsDir = node.IsDir
+			p.clipboardBatch = nil
 			return p, appmsg.ShowToast("Marked for copy: "+node.Path, 2*time.Second)
 		}
 
@@ -325,6 +455,11 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 
 	case "p":
 		// Paste file/directory from clipboard
+		if len(p.clipboardBatch) > 0 {
+			if node := p.tree.GetNode(p.treeCursor); node != nil {
+				return p, p.startBatch(BatchCopy, p.clipboardBatch, pasteDir(p.ctx.WorkDir, node))
+			}
+		}
 		if p.clipboardPath != "" {
 			node := p.tree.GetNode(p.treeCursor)
 			if node != nil {
@@ -332,6 +467,60 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 			}
 		}
 
+	case " ":
+		// Toggle the item under the cursor in the selection and move down
+		p.toggleSelected(p.tree.GetNode(p.treeCursor))
+		if p.treeCursor < p.tree.Len()-1 {
+			p.treeCursor++
+			p.ensureTreeCursorVisible()
+			return p, p.loadPreviewForCursor()
+		}
+
+	case "v":
+		// Start a range selection, or add the range to the selection
+		if p.visualMode {
+			p.commitVisualRange()
+		} else if p.tree.Len() > 0 {
+			p.visualMode = true
+			p.visualAnchor = p.treeCursor
+		}
+
+	case "esc":
+		// Cancel a range in progress, then clear the selection
+		if p.visualMode {
+			p.visualMode = false
+		} else {
+			p.clearSelection()
+		}
+
+	case "u":
+		// Undo the last move, rename or delete
+		return p, p.undoLast()
+
+	case "X":
+		// Extract an archive, or a file or directory inside one
+		if node := p.tree.GetNode(p.treeCursor); node != nil && (node.IsArchive || node.InArchive()) {
+			p.startExtract(node)
+		}
+
+	case "T":
+		// Browse the project trash
+		return p, p.openTrash()
+
+	case "O":
+		// Outline of the previewed file
+		return p, p.openOutline()
+
+	case "S":
+		// Stage the selection, or the item under the cursor, with git add
+		items := p.takeSelection()
+		if len(items) == 0 {
+			if node := p.tree.GetNode(p.treeCursor); node != nil && node != p.tree.Root {
+				items = []string{node.Path}
+			}
+		}
+		return p, p.startBatch(BatchStage, items, "")
+
 	case "s":
 		// Cycle sort mode
 		newMode := p.tree.SortMode.Next()
@@ -413,6 +602,27 @@ func (p *Plugin) handleTreeKey(key string) (plugin.Plugin, tea.Cmd) {
 }
 
 func (p *Plugin) handlePreviewKey(key string) (plugin.Plugin, tea.Cmd) {
+	if p.diffViewActive() {
+		if cmd, ok := p.handleDiffKey(key); ok {
+			return p, cmd
+		}
+	} else if p.dataViewActive() {
+		if cmd, ok := p.handleDataViewKey(key); ok {
+			return p, cmd
+		}
+	} else if p.hexViewActive() {
+		if cmd, ok := p.handleHexKey(key); ok {
+			return p, cmd
+		}
+	} else if p.streamViewActive() {
+		if cmd, ok := p.handleStreamKey(key); ok {
+			return p, cmd
+		}
+	}
+	if cmd, ok := p.handleArchivePreviewKey(key); ok {
+		return p, cmd
+	}
+
 	lines := p.getPreviewLines()
 	visibleHeight := p.visibleContentHeight()
 	maxScroll := len(lines) - visibleHeight
@@ -430,13 +640,22 @@ func (p *Plugin) handlePreviewKey(key string) (plugin.Plugin, tea.Cmd) {
 		if p.previewScroll > 0 {
 			p.previewScroll--
 		}
+		p.previewFollow = false
 
 	case "g":
 		p.previewScroll = 0
+		p.previewFollow = false
 
 	case "G":
 		p.previewScroll = maxScroll
 
+	case "F":
+		// Follow the end of the file as it changes (tail -f)
+		p.previewFollow = !p.previewFollow
+		if p.previewFollow {
+			p.previewScroll = maxScroll
+		}
+
 	case "ctrl+d":
 		p.previewScroll += visibleHeight / 2
 		if p.previewScroll > maxScroll {
@@ -448,6 +667,7 @@ func (p *Plugin) handlePreviewKey(key string) (plugin.Plugin, tea.Cmd) {
 		if p.previewScroll < 0 {
 			p.previewScroll = 0
 		}
+		p.previewFollow = false
 
 	case "ctrl+f", "pgdown":
 		p.previewScroll += visibleHeight
@@ -460,6 +680,7 @@ func (p *Plugin) handlePreviewKey(key string) (plugin.Plugin, tea.Cmd) {
 		if p.previewScroll < 0 {
 			p.previewScroll = 0
 		}
+		p.previewFollow = false
 
 	case "h", "left", "esc":
 		// Restore tree pane if hidden, otherwise return to it
@@ -554,8 +775,10 @@ func (p *Plugin) handlePreviewKey(key string) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case "m":
-		// Toggle markdown rendering for .md files
-		if p.isMarkdownFile() {
+		// Toggle markdown rendering for .md files, or the data view
+		if p.dataView != nil {
+			p.dataViewRaw = !p.dataViewRaw
+		} else if p.isMarkdownFile() {
 			p.toggleMarkdownRender()
 		}
 
@@ -565,12 +788,36 @@ func (p *Plugin) handlePreviewKey(key string) (plugin.Plugin, tea.Cmd) {
 		_ = state.SetLineWrapEnabled(p.previewWrapEnabled)
 		p.previewScroll = 0
 
+	case "d":
+		// Show the file's changes against HEAD
+		return p, p.toggleGitDiff()
+
 	case "B":
 		// Show git blame for current preview file
 		if p.previewFile != "" {
 			return p.openBlameView(p.previewFile)
 		}
 
+	case "L":
+		// Show git history for current preview file in the git tab
+		if p.previewFile != "" {
+			return p, p.openFileHistory(p.previewFile)
+		}
+
+	case "P":
+		// Preview and apply the current file as a patch in the git tab
+		if p.previewFile != "" {
+			return p, p.applyPatch(p.previewFile)
+		}
+
+	case "O":
+		// Outline of the previewed file
+		return p, p.openOutline()
+
+	case "ctrl+]":
+		// Go to the definition of the selected identifier
+		return p, p.goToDefinition(p.definitionName())
+
 	case "[":
 		return p, p.cycleTab(-1)
 
@@ -617,8 +864,7 @@ func (p *Plugin) handleFileOpKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 				p.fileOpConfirmDelete = false
 				return p, nil
 			}
-			p.fileOpConfirmDelete = false
-			return p, p.doDelete()
+			return p.executeFileOp()
 		case "n", "N", "esc":
 			// Cancel delete
 			p.fileOpMode = FileOpNone
@@ -804,6 +1050,28 @@ func (p *Plugin) handleFileOpKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	}
 }
 
+// handleBatchResultKey handles key input while batch failures are shown.
+func (p *Plugin) handleBatchResultKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureBatchResultModal()
+	if p.batchResultModal == nil {
+		p.closeBatchResult()
+		return p, nil
+	}
+
+	switch msg.String() {
+	case "q", "enter":
+		p.closeBatchResult()
+		return p, nil
+	}
+
+	action, cmd := p.batchResultModal.HandleKey(msg)
+	if action == "cancel" {
+		p.closeBatchResult()
+		return p, nil
+	}
+	return p, cmd
+}
+
 // handleInfoKey handles key input during info modal mode.
 func (p *Plugin) handleInfoKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	p.ensureInfoModal()
@@ -934,12 +1202,16 @@ func (p *Plugin) handleQuickOpenKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		p.quickOpenQuery = ""
 		p.quickOpenMatches = nil
 		p.quickOpenCursor = 0
+		p.quickOpenRecent = nil
 
 	case "enter":
 		if len(p.quickOpenMatches) > 0 && p.quickOpenCursor < len(p.quickOpenMatches) {
 			return p.selectQuickOpenMatch()
 		}
 
+	case "ctrl+e":
+		p.toggleQuickOpenRecent()
+
 	case "up", "ctrl+p":
 		if p.quickOpenCursor > 0 {
 			p.quickOpenCursor--
@@ -980,6 +1252,37 @@ func (p *Plugin) handleProjectSearchKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd)
 		return p, nil
 	}
 
+	// A pending replace needs an explicit yes
+	if state != nil && state.ConfirmReplace {
+		state.ConfirmReplace = false
+		if key == "y" || key == "Y" {
+			return p, p.applyProjectReplace()
+		}
+		return p, nil
+	}
+
+	// Replace keys are handled before the modal so tab switches fields
+	if state != nil {
+		switch key {
+		case "ctrl+r":
+			return p.toggleProjectReplace()
+		case "tab", "shift+tab":
+			if state.ReplaceMode {
+				state.ReplaceFocused = !state.ReplaceFocused
+				return p, nil
+			}
+		case "alt+x", "alt+X":
+			if state.ReplaceMode {
+				state.ToggleExcluded(key == "alt+X")
+				return p, nil
+			}
+		case "alt+a":
+			if state.ReplaceMode {
+				return p, p.confirmProjectReplace()
+			}
+		}
+	}
+
 	// Handle enter before modal to ensure it opens the result at state.Cursor
 	// (modal's focus might be on an option button, but we want to open the selected result)
 	if key == "enter" && state != nil && len(state.Results) > 0 {
@@ -1125,7 +1428,11 @@ func (p *Plugin) handleProjectSearchKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd)
 		return p.toggleProjectSearchOption(state, &state.WholeWord)
 
 	case "backspace":
-		if state != nil && len(state.Query) > 0 {
+		if state != nil && state.ReplaceFocused {
+			if runes := []rune(state.Replace); len(runes) > 0 {
+				state.Replace = string(runes[:len(runes)-1])
+			}
+		} else if state != nil && len(state.Query) > 0 {
 			runes := []rune(state.Query)
 			state.Query = string(runes[:len(runes)-1])
 			if state.Query == "" {
@@ -1142,6 +1449,10 @@ func (p *Plugin) handleProjectSearchKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd)
 	default:
 		// Append printable characters
 		if state != nil && len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			if state.ReplaceFocused {
+				state.Replace += key
+				return p, nil
+			}
 			state.Query += key
 			state.IsSearching = true
 			state.DebounceVersion++
@@ -1259,6 +1570,36 @@ func (p *Plugin) openBlameView(path string) (plugin.Plugin, tea.Cmd) {
 	return p, RunGitBlame(p.ctx.WorkDir, path, p.ctx.Epoch)
 }
 
+// openFileHistory shows the file's git history in the git tab.
+func (p *Plugin) openFileHistory(path string) tea.Cmd {
+	return tea.Batch(
+		app.FocusPlugin("git-status"),
+		func() tea.Msg {
+			return app.OpenFileHistoryMsg{Path: path}
+		},
+	)
+}
+
+// applyPatch opens the file in the git tab's patch apply preview.
+func (p *Plugin) applyPatch(path string) tea.Cmd {
+	return tea.Batch(
+		app.FocusPlugin("git-status"),
+		func() tea.Msg {
+			return app.ApplyPatchMsg{Path: path}
+		},
+	)
+}
+
+// openSubmodule shows the submodule as a nested repository in the git tab.
+func (p *Plugin) openSubmodule(path string) tea.Cmd {
+	return tea.Batch(
+		app.FocusPlugin("git-status"),
+		func() tea.Msg {
+			return app.OpenSubmoduleMsg{Path: path}
+		},
+	)
+}
+
 // blameVisibleHeight returns the visible height for blame content.
 func (p *Plugin) blameVisibleHeight() int {
 	h := p.height - blameModalHeaderFooterLines
@@ -1394,7 +1735,14 @@ func (p *Plugin) handleLineJumpKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		// Execute jump (1-based input -> 0-based index)
 		target := lineNum - 1
 
-		if p.activePane == PanePreview {
+		if p.activePane == PanePreview && p.streamViewActive() {
+			// Jump in a streamed file; the window is read from disk
+			p.lineJumpMode = false
+			p.lineJumpBuffer = ""
+			p.previewFollow = false
+			p.previewScroll = max(min(target, p.maxStreamTop()), 0)
+			return p, p.ensureStreamWindow()
+		} else if p.activePane == PanePreview {
 			// Jump in preview pane
 			lines := p.getPreviewLines()
 			if len(lines) > 0 {
@@ -0,0 +1,551 @@
+package filebrowser
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+const (
+	hexChunkSize  = 256 * 1024 // Bytes loaded around the visible rows
+	hexSearchSize = 1 << 20    // Bytes scanned per read when searching
+)
+
+// hexPrompt is the input the hex view is asking for.
+type hexPrompt int
+
+const (
+	hexPromptNone hexPrompt = iota
+	hexPromptSearch
+	hexPromptJump
+)
+
+// hexView is the state of the hex dump of a binary file. Files on disk
+// are paged in as the view scrolls; other sources show the bytes read.
+type hexView struct {
+	path     string // Previewed file, relative
+	fullPath string // Absolute path to read pages from, "" if not on disk
+	size     int64  // Bytes that can be shown
+	fileSize int64  // Size of the file, more than size if only the start was read
+
+	data    []byte // Loaded bytes
+	dataOff int64  // Offset of data[0]
+	loading bool
+
+	top      int64 // Offset of the first visible row
+	rowBytes int   // Bytes per row at the last render
+
+	// Highlighted bytes: the current search match or jump target
+	mark    int64 // -1 for none
+	markLen int
+
+	prompt    hexPrompt
+	input     string
+	inputErr  string
+	pattern   []byte
+	query     string // Search as typed
+	searching bool
+}
+
+// HexChunkLoadedMsg carries bytes paged in for the hex view.
+type HexChunkLoadedMsg struct {
+	Epoch  uint64
+	Path   string
+	Offset int64
+	Data   []byte
+	Err    error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m HexChunkLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// HexSearchMsg carries the result of a byte search.
+type HexSearchMsg struct {
+	Epoch   uint64
+	Path    string
+	Offset  int64 // -1 if not found
+	Wrapped bool
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m HexSearchMsg) GetEpoch() uint64 { return m.Epoch }
+
+// hexViewActive reports whether the preview shows the hex dump.
+func (p *Plugin) hexViewActive() bool {
+	return p.hexView != nil && !p.dataViewActive()
+}
+
+// applyHexResult shows a binary file as a hex dump. Reloading the same
+// file keeps the position and search.
+func (p *Plugin) applyHexResult(result PreviewResult) {
+	if !result.IsBinary || result.IsImage {
+		p.hexView = nil
+		return
+	}
+	v := p.hexView
+	if v == nil || v.path != p.previewFile {
+		v = &hexView{path: p.previewFile, mark: -1, rowBytes: 16}
+	}
+	v.fullPath = result.HexPath
+	v.fileSize = result.TotalSize
+	v.size = result.TotalSize
+	if v.fullPath == "" {
+		v.size = int64(len(result.Bytes))
+	}
+	v.data, v.dataOff, v.loading = result.Bytes, 0, false
+	v.prompt = hexPromptNone
+	p.hexView = v
+	v.clampTop(p.hexRows())
+}
+
+// hexRows is the number of rows the hex view shows.
+func (p *Plugin) hexRows() int {
+	return max(p.visibleContentHeight()-1, 1)
+}
+
+// hexRowBytes returns how many bytes fit on a row of the given width.
+func hexRowBytes(width int) int {
+	if width >= 78 {
+		return 16
+	}
+	return 8
+}
+
+// clampTop keeps top on a row boundary and the last row at the bottom.
+func (v *hexView) clampTop(rows int) {
+	row := int64(v.rowBytes)
+	last := max(v.size-1, 0) / row * row
+	maxTop := max(last-int64(rows-1)*row, 0)
+	v.top = max(0, min(v.top, maxTop)) / row * row
+}
+
+// scrollTo moves the view so offset is visible, a few rows from the top.
+func (v *hexView) scrollTo(offset int64, rows int) {
+	row := int64(v.rowBytes)
+	if offset >= v.top && offset < v.top+int64(rows)*row {
+		return
+	}
+	v.top = offset/row*row - int64(min(rows/3, 4))*row
+	v.clampTop(rows)
+}
+
+// loaded reports whether the bytes in [off, end) are loaded.
+func (v *hexView) loaded(off, end int64) bool {
+	return off >= v.dataOff && min(end, v.size) <= v.dataOff+int64(len(v.data))
+}
+
+// ensureHexWindow pages in the bytes around the visible rows.
+func (p *Plugin) ensureHexWindow() tea.Cmd {
+	v := p.hexView
+	if v == nil || v.fullPath == "" || v.loading {
+		return nil
+	}
+	end := v.top + int64(p.hexRows()*v.rowBytes)
+	if v.loaded(v.top, end) {
+		return nil
+	}
+	v.loading = true
+	off := max(v.top-hexChunkSize/4, 0) / 16 * 16
+	epoch, path, fullPath := p.ctx.Epoch, v.path, v.fullPath
+	return func() tea.Msg {
+		f, err := os.Open(fullPath)
+		if err != nil {
+			return HexChunkLoadedMsg{Epoch: epoch, Path: path, Err: err}
+		}
+		defer func() { _ = f.Close() }()
+		buf := make([]byte, hexChunkSize)
+		n, err := f.ReadAt(buf, off)
+		if err == io.EOF {
+			err = nil
+		}
+		return HexChunkLoadedMsg{Epoch: epoch, Path: path, Offset: off, Data: buf[:n], Err: err}
+	}
+}
+
+// handleHexChunkLoaded stores paged-in bytes.
+func (p *Plugin) handleHexChunkLoaded(msg HexChunkLoadedMsg) tea.Cmd {
+	v := p.hexView
+	if v == nil || v.path != msg.Path {
+		return nil
+	}
+	v.loading = false
+	if msg.Err != nil {
+		v.inputErr = msg.Err.Error()
+		return nil
+	}
+	v.data, v.dataOff = msg.Data, msg.Offset
+	return p.ensureHexWindow()
+}
+
+// handleHexKey handles keys for the hex view. It reports false for keys
+// the preview should handle as usual.
+func (p *Plugin) handleHexKey(key string) (tea.Cmd, bool) {
+	v := p.hexView
+	rows := p.hexRows()
+	row := int64(v.rowBytes)
+
+	switch key {
+	case "j", "down":
+		v.top += row
+	case "k", "up":
+		v.top -= row
+	case "ctrl+d":
+		v.top += int64(rows/2) * row
+	case "ctrl+u":
+		v.top -= int64(rows/2) * row
+	case "ctrl+f", "pgdown":
+		v.top += int64(rows) * row
+	case "ctrl+b", "pgup":
+		v.top -= int64(rows) * row
+	case "g":
+		v.top = 0
+	case "G":
+		v.top = v.size
+	case "/":
+		v.prompt, v.input, v.inputErr = hexPromptSearch, v.query, ""
+		return nil, true
+	case ":":
+		v.prompt, v.input, v.inputErr = hexPromptJump, "", ""
+		return nil, true
+	case "n", "N":
+		if v.pattern == nil {
+			return nil, true
+		}
+		return p.searchHex(key == "N"), true
+	case "esc":
+		if v.mark < 0 {
+			return nil, false
+		}
+		v.mark = -1
+		return nil, true
+	default:
+		return nil, false
+	}
+	v.clampTop(rows)
+	return p.ensureHexWindow(), true
+}
+
+// handleHexPromptKey handles typing a search or offset in the hex view.
+func (p *Plugin) handleHexPromptKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	v := p.hexView
+	switch key := msg.String(); key {
+	case "esc":
+		v.prompt, v.input, v.inputErr = hexPromptNone, "", ""
+	case "enter":
+		return p, p.submitHexPrompt()
+	case "backspace":
+		if runes := []rune(v.input); len(runes) > 0 {
+			v.input = string(runes[:len(runes)-1])
+		}
+		v.inputErr = ""
+	case "ctrl+u":
+		v.input, v.inputErr = "", ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			v.input += string(msg.Runes)
+			v.inputErr = ""
+		}
+	}
+	return p, nil
+}
+
+// submitHexPrompt runs the search or jump that was typed.
+func (p *Plugin) submitHexPrompt() tea.Cmd {
+	v := p.hexView
+	if strings.TrimSpace(v.input) == "" {
+		v.prompt = hexPromptNone
+		return nil
+	}
+	if v.prompt == hexPromptJump {
+		base := v.top
+		if v.mark >= 0 {
+			base = v.mark
+		}
+		off, err := parseHexOffset(v.input, base)
+		if err != nil {
+			v.inputErr = err.Error()
+			return nil
+		}
+		v.prompt = hexPromptNone
+		v.mark, v.markLen = max(0, min(off, v.size-1)), 1
+		v.scrollTo(v.mark, p.hexRows())
+		return p.ensureHexWindow()
+	}
+
+	pattern, err := parseHexPattern(v.input)
+	if err != nil {
+		v.inputErr = err.Error()
+		return nil
+	}
+	v.prompt = hexPromptNone
+	v.pattern, v.query = pattern, v.input
+	v.mark = -1
+	return p.searchHex(false)
+}
+
+// searchHex finds the next (or previous) occurrence of the pattern,
+// starting from the current match or the top of the view.
+func (p *Plugin) searchHex(backward bool) tea.Cmd {
+	v := p.hexView
+	from := v.top
+	if v.mark >= 0 {
+		from = v.mark
+		if !backward {
+			from++
+		}
+	}
+	v.searching = true
+	epoch, path, fullPath, size, pattern := p.ctx.Epoch, v.path, v.fullPath, v.size, v.pattern
+	data := v.data
+	return func() tea.Msg {
+		var r io.ReaderAt = bytes.NewReader(data)
+		if fullPath != "" {
+			f, err := os.Open(fullPath)
+			if err != nil {
+				return HexSearchMsg{Epoch: epoch, Path: path, Offset: -1, Err: err}
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+		off, wrapped, err := findBytes(r, size, pattern, from, backward)
+		return HexSearchMsg{Epoch: epoch, Path: path, Offset: off, Wrapped: wrapped, Err: err}
+	}
+}
+
+// handleHexSearch moves to a search result.
+func (p *Plugin) handleHexSearch(msg HexSearchMsg) tea.Cmd {
+	v := p.hexView
+	if v == nil || v.path != msg.Path {
+		return nil
+	}
+	v.searching = false
+	switch {
+	case msg.Err != nil:
+		v.inputErr = msg.Err.Error()
+		return nil
+	case msg.Offset < 0:
+		v.inputErr = "Not found: " + v.query
+		return nil
+	}
+	v.inputErr = ""
+	v.mark, v.markLen = msg.Offset, len(v.pattern)
+	v.scrollTo(msg.Offset, p.hexRows())
+	return p.ensureHexWindow()
+}
+
+// findBytes finds pattern in the first size bytes of r, searching from
+// offset from and wrapping around the end. Backward searches find the
+// last match before from.
+func findBytes(r io.ReaderAt, size int64, pattern []byte, from int64, backward bool) (int64, bool, error) {
+	n := int64(len(pattern))
+	if backward {
+		off, err := scanBackward(r, 0, min(size, from-1+n), pattern)
+		if off >= 0 || err != nil {
+			return off, false, err
+		}
+		off, err = scanBackward(r, from, size, pattern)
+		return off, true, err
+	}
+	off, err := scanForward(r, from, size, pattern)
+	if off >= 0 || err != nil {
+		return off, false, err
+	}
+	off, err = scanForward(r, 0, min(size, from-1+n), pattern)
+	return off, true, err
+}
+
+// scanForward returns the first match lying within [lo, hi), or -1.
+func scanForward(r io.ReaderAt, lo, hi int64, pattern []byte) (int64, error) {
+	n := int64(len(pattern))
+	buf := make([]byte, hexSearchSize+n-1)
+	for off := max(lo, 0); off+n <= hi; off += hexSearchSize {
+		read, err := r.ReadAt(buf[:min(int64(len(buf)), hi-off)], off)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return -1, err
+		}
+		if i := bytes.Index(buf[:read], pattern); i >= 0 {
+			return off + int64(i), nil
+		}
+	}
+	return -1, nil
+}
+
+// scanBackward returns the last match lying within [lo, hi), or -1.
+func scanBackward(r io.ReaderAt, lo, hi int64, pattern []byte) (int64, error) {
+	n := int64(len(pattern))
+	buf := make([]byte, hexSearchSize+n-1)
+	for end := hi; end-n >= max(lo, 0); end -= hexSearchSize {
+		start := max(lo, end-int64(len(buf)), 0)
+		read, err := r.ReadAt(buf[:end-start], start)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return -1, err
+		}
+		if i := bytes.LastIndex(buf[:read], pattern); i >= 0 {
+			return start + int64(i), nil
+		}
+	}
+	return -1, nil
+}
+
+// parseHexPattern parses a byte search: hex bytes such as "7f 45 4c 46"
+// or "0xcafe", a quoted string with Go escapes, or plain text.
+func parseHexPattern(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, `"`) {
+		text, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated or invalid quoted text")
+		}
+		if text == "" {
+			return nil, fmt.Errorf("empty search")
+		}
+		return []byte(text), nil
+	}
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	digits = strings.Join(strings.Fields(digits), "")
+	if b, err := hex.DecodeString(digits); err == nil && len(b) > 0 {
+		return b, nil
+	}
+	return []byte(s), nil
+}
+
+// parseHexOffset parses an offset to jump to: decimal, 0x-prefixed hex,
+// and either relative to base with a leading + or -.
+func parseHexOffset(s string, base int64) (int64, error) {
+	s = strings.TrimSpace(s)
+	sign := int64(0)
+	switch {
+	case strings.HasPrefix(s, "+"):
+		sign, s = 1, s[1:]
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	}
+	digits, radix := s, 10
+	if strings.HasPrefix(strings.ToLower(s), "0x") {
+		digits, radix = s[2:], 16
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(digits, "_", ""), radix, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	if sign != 0 {
+		return max(base+sign*n, 0), nil
+	}
+	return n, nil
+}
+
+// renderHexView renders the info line and the rows of the dump.
+func (p *Plugin) renderHexView(width, height int) string {
+	v := p.hexView
+	v.rowBytes = hexRowBytes(width)
+	rows := max(height-1, 1)
+	v.clampTop(rows)
+
+	lines := []string{v.renderInfo(width)}
+	if v.size == 0 {
+		return strings.Join(append(lines, styles.Muted.Render("Empty file")), "\n")
+	}
+
+	offsetDigits := 8
+	if v.size > 0xffffffff {
+		offsetDigits = 12
+	}
+	for i := range rows {
+		off := v.top + int64(i*v.rowBytes)
+		if off >= v.size {
+			break
+		}
+		end := min(off+int64(v.rowBytes), v.size)
+		if !v.loaded(off, end) {
+			lines = append(lines, styles.Muted.Render("Loading..."))
+			break
+		}
+		lines = append(lines, v.renderRow(off, v.data[off-v.dataOff:end-v.dataOff], offsetDigits))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderInfo renders the prompt being typed, an error, or the position.
+func (v *hexView) renderInfo(width int) string {
+	switch {
+	case v.prompt == hexPromptSearch:
+		line := styles.StatusModified.Render("Search: ") + v.input + "█"
+		if v.inputErr != "" {
+			line += "  " + styles.StatusDeleted.Render(v.inputErr)
+		} else if v.input == "" {
+			line += "  " + styles.Muted.Render(`hex bytes like 7f 45 4c 46, or "text"`)
+		}
+		return ansi.Truncate(line, width, "…")
+	case v.prompt == hexPromptJump:
+		line := styles.StatusModified.Render("Go to offset: ") + v.input + "█"
+		if v.inputErr != "" {
+			line += "  " + styles.StatusDeleted.Render(v.inputErr)
+		} else if v.input == "" {
+			line += "  " + styles.Muted.Render("0x1f40, 8000, +0x100 or -64")
+		}
+		return ansi.Truncate(line, width, "…")
+	case v.inputErr != "":
+		return styles.StatusDeleted.Render(ansi.Truncate(v.inputErr, width, "…"))
+	}
+
+	parts := []string{fmt.Sprintf("0x%x / 0x%x", v.top, v.size)}
+	if v.size < v.fileSize {
+		parts = append(parts, fmt.Sprintf("first %s of %s", formatSize(v.size), formatSize(v.fileSize)))
+	}
+	switch {
+	case v.searching:
+		parts = append(parts, "searching "+v.query+"...")
+	case v.mark >= 0 && v.pattern != nil && v.markLen == len(v.pattern):
+		parts = append(parts, fmt.Sprintf("%s at 0x%x (n/N)", v.query, v.mark))
+	case v.mark >= 0:
+		parts = append(parts, fmt.Sprintf("at 0x%x", v.mark))
+	}
+	return styles.Muted.Render(ansi.Truncate(strings.Join(parts, " · "), width, "…"))
+}
+
+// renderRow renders the offset, hex bytes and ASCII column of a row.
+func (v *hexView) renderRow(off int64, row []byte, offsetDigits int) string {
+	mark := lipgloss.NewStyle().Reverse(true)
+	zero := styles.Muted
+	var hexCol, asciiCol strings.Builder
+	for i := range v.rowBytes {
+		if i == v.rowBytes/2 {
+			hexCol.WriteString(" ")
+		}
+		if i >= len(row) {
+			hexCol.WriteString("   ")
+			continue
+		}
+		b := row[i]
+		cell := fmt.Sprintf("%02x", b)
+		char := "."
+		if b >= 0x20 && b < 0x7f {
+			char = string(rune(b))
+		}
+		pos := off + int64(i)
+		switch {
+		case v.mark >= 0 && pos >= v.mark && pos < v.mark+int64(v.markLen):
+			cell, char = mark.Render(cell), mark.Render(char)
+		case b == 0:
+			cell, char = zero.Render(cell), zero.Render(char)
+		case char == ".":
+			char = zero.Render(char)
+		}
+		hexCol.WriteString(cell + " ")
+		asciiCol.WriteString(char)
+	}
+	return styles.Muted.Render(fmt.Sprintf("%0*x", offsetDigits, off)) + "  " +
+		hexCol.String() + styles.Muted.Render("│") + asciiCol.String() + styles.Muted.Render("│")
+}
@@ -0,0 +1,170 @@
+package filebrowser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFindBytes(t *testing.T) {
+	data := []byte("..ab....ab..ab.")
+	r := bytes.NewReader(data)
+	size := int64(len(data))
+	tests := []struct {
+		from     int64
+		backward bool
+		want     int64
+		wrapped  bool
+	}{
+		{0, false, 2, false},
+		{3, false, 8, false},
+		{13, false, 2, true},
+		{12, true, 8, false},
+		{8, true, 2, false},
+		{2, true, 12, true},
+	}
+	for _, tt := range tests {
+		off, wrapped, err := findBytes(r, size, []byte("ab"), tt.from, tt.backward)
+		if err != nil || off != tt.want || wrapped != tt.wrapped {
+			t.Errorf("from %d backward=%v = %d wrapped=%v, want %d wrapped=%v (%v)", tt.from, tt.backward, off, wrapped, tt.want, tt.wrapped, err)
+		}
+	}
+	if off, _, _ := findBytes(r, size, []byte("zz"), 0, false); off != -1 {
+		t.Errorf("missing pattern = %d", off)
+	}
+
+	// Matches straddling a read chunk are found
+	big := make([]byte, hexSearchSize+10)
+	copy(big[hexSearchSize-1:], "xy")
+	if off, _, _ := findBytes(bytes.NewReader(big), int64(len(big)), []byte("xy"), 0, false); off != hexSearchSize-1 {
+		t.Errorf("straddling forward = %d", off)
+	}
+	if off, _, _ := findBytes(bytes.NewReader(big), int64(len(big)), []byte("xy"), int64(len(big)), true); off != hexSearchSize-1 {
+		t.Errorf("straddling backward = %d", off)
+	}
+}
+
+func TestParseHexPattern(t *testing.T) {
+	tests := map[string]string{
+		"7f 45 4c 46":  "\x7fELF",
+		"0xCAFEBABE":   "\xca\xfe\xba\xbe",
+		`"PK\x03\x04"`: "PK\x03\x04",
+		"version":      "version",
+		"abc":          "abc", // odd digit count is text
+	}
+	for in, want := range tests {
+		got, err := parseHexPattern(in)
+		if err != nil || string(got) != want {
+			t.Errorf("%q = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{`"open`, `""`} {
+		if _, err := parseHexPattern(in); err == nil {
+			t.Errorf("%q should be rejected", in)
+		}
+	}
+}
+
+func TestParseHexOffset(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"8000", 8000},
+		{"0x1f40", 8000},
+		{"+0x10", 116},
+		{"-64", 36},
+		{"-0x1000", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseHexOffset(tt.in, 100)
+		if err != nil || got != tt.want {
+			t.Errorf("%q = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "0xzz", "12ab", "+"} {
+		if _, err := parseHexOffset(in, 0); err == nil {
+			t.Errorf("%q should be rejected", in)
+		}
+	}
+}
+
+func TestHexViewKeys(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	data := make([]byte, maxPreviewSize*2)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	copy(data[maxPreviewSize+100:], "\x7fELF")
+	if err := os.WriteFile(filepath.Join(dir, "app.bin"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.previewFile = "app.bin"
+	p.activePane = PanePreview
+	p.height = 30
+	p.applyPreviewResult(LoadPreview(dir, "app.bin", p.ctx.Epoch)().(PreviewLoadedMsg).Result)
+	v := p.hexView
+	if !p.hexViewActive() || p.FocusContext() != "file-browser-hex" {
+		t.Fatalf("hex view active=%v context=%s", p.hexViewActive(), p.FocusContext())
+	}
+	if v.size != int64(len(data)) || v.fullPath == "" {
+		t.Fatalf("size=%d fullPath=%q", v.size, v.fullPath)
+	}
+
+	out := p.renderHexView(80, 10)
+	if !strings.Contains(out, "00000010  10 11 12 13") {
+		t.Errorf("render = %q", out)
+	}
+
+	// Search pages in the bytes around the match
+	typeKeys := func(s string) {
+		for _, r := range s {
+			p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if p.FocusContext() != "file-browser-hex-input" || !p.ConsumesTextInput() {
+		t.Fatalf("prompt context = %s", p.FocusContext())
+	}
+	typeKeys("7f454c46")
+	_, cmd := p.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	cmd = p.handleHexSearch(cmd().(HexSearchMsg))
+	if v.mark != maxPreviewSize+100 || v.markLen != 4 {
+		t.Fatalf("mark=%d len=%d err=%q", v.mark, v.markLen, v.inputErr)
+	}
+	if cmd == nil {
+		t.Fatal("jumping past the loaded bytes should page them in")
+	}
+	p.handleHexChunkLoaded(cmd().(HexChunkLoadedMsg))
+	if !v.loaded(v.top, v.top+int64(p.hexRows()*v.rowBytes)) {
+		t.Errorf("window [%d, +%d) not loaded at top %d", v.dataOff, len(v.data), v.top)
+	}
+
+	// Jump relative to the match
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	typeKeys("-100")
+	p.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if v.mark != maxPreviewSize || v.prompt != hexPromptNone {
+		t.Errorf("jump mark = %d", v.mark)
+	}
+
+	p.handlePreviewKey("esc")
+	if v.mark != -1 {
+		t.Error("esc should clear the mark")
+	}
+	p.handlePreviewKey("G")
+	if last := (v.size - 1) / 16 * 16; v.top+int64((p.hexRows()-1)*16) != last {
+		t.Errorf("G top = %d", v.top)
+	}
+
+	// Reloading the file keeps the position
+	top := v.top
+	p.applyPreviewResult(LoadPreview(dir, "app.bin", p.ctx.Epoch)().(PreviewLoadedMsg).Result)
+	if p.hexView != v || v.top != top {
+		t.Error("reload should keep the hex view")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/marcus/sidecar/internal/app"
 	"github.com/marcus/sidecar/internal/features"
 	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
 	"github.com/marcus/sidecar/internal/styles"
 	"github.com/marcus/sidecar/internal/tty"
 	xterm "golang.org/x/term"
@@ -735,6 +736,19 @@ func (p *Plugin) selectTreeItem(idx int) (*Plugin, tea.Cmd) {
 	return p, LoadPreview(p.ctx.WorkDir, node.Path, p.ctx.Epoch)
 }
 
+// editFile navigates to path and opens it in the inline editor, falling back
+// to the external editor when inline editing is unavailable.
+func (p *Plugin) editFile(path string, lineNo int) (plugin.Plugin, tea.Cmd) {
+	_, navCmd := p.navigateToFile(path)
+	if !features.IsEnabled(features.TmuxInlineEdit.Name) {
+		return p, tea.Batch(navCmd, p.openFileAtLine(path, lineNo+1))
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return p, tea.Batch(navCmd, p.openFileAtLine(path, lineNo+1))
+	}
+	return p, tea.Batch(navCmd, p.enterInlineEditMode(path, lineNo))
+}
+
 // enterInlineEditModeAtCurrentLine starts inline editing at the current preview line.
 func (p *Plugin) enterInlineEditModeAtCurrentLine(path string) tea.Cmd {
 	lineNo := p.getCurrentPreviewLine()
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/marcus/sidecar/internal/mouse"
 	"github.com/marcus/sidecar/internal/msg"
 	"github.com/marcus/sidecar/internal/state"
@@ -183,11 +184,34 @@ func (p *Plugin) handleMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 		return p.handleQuickOpenMouse(msg)
 	}
 
+	// Handle symbol picker if active
+	if p.symbolMode {
+		p.ensureSymbolModal()
+		if p.symbolModal == nil || p.symbolModal.HandleMouse(msg, p.mouseHandler) == "cancel" {
+			p.closeSymbols()
+		}
+		return p, nil
+	}
+
 	// Handle info modal if active
 	if p.infoMode {
 		return p.handleInfoModalMouse(msg)
 	}
 
+	// Handle batch result modal if active
+	if p.batchResult != nil {
+		return p.handleBatchResultMouse(msg)
+	}
+
+	// Handle trash view if active
+	if p.trashMode {
+		p.ensureTrashModal()
+		if p.trashModal.HandleMouse(msg, p.mouseHandler) == "cancel" {
+			p.closeTrash()
+		}
+		return p, nil
+	}
+
 	// Handle blame modal if active
 	if p.blameMode {
 		return p.handleBlameModalMouse(msg)
@@ -303,12 +327,7 @@ func (p *Plugin) handleMouseClick(action mouse.MouseAction) (*Plugin, tea.Cmd) {
 	case regionFileOpCancel:
 		// Click on cancel button in file op modal
 		if p.fileOpMode != FileOpNone {
-			p.fileOpMode = FileOpNone
-			p.fileOpTarget = nil
-			p.fileOpError = ""
-			p.fileOpShowSuggestions = false
-			p.fileOpConfirmDelete = false
-			p.fileOpConfirmCreate = false
+			p.resetFileOp()
 			return p, nil
 		}
 		return p, nil
@@ -330,6 +349,19 @@ func (p *Plugin) handleMouseClick(action mouse.MouseAction) (*Plugin, tea.Cmd) {
 
 // handleMouseDoubleClick handles double click actions.
 func (p *Plugin) handleMouseDoubleClick(action mouse.MouseAction) (*Plugin, tea.Cmd) {
+	if action.Region != nil && action.Region.ID == regionPreviewLine {
+		// Go to the definition of the word under the pointer
+		lineIdx, col, ok := p.previewSelectionAtXY(action.X, action.Y)
+		if !ok || lineIdx >= len(p.previewLines) {
+			return p, nil
+		}
+		name := identifierAt(ansi.Strip(p.previewLines[lineIdx]), col, 8)
+		if name == "" {
+			return p, nil
+		}
+		p.selection.Clear()
+		return p, p.goToDefinition(name)
+	}
 	if action.Region == nil || action.Region.ID != regionTreeItem {
 		return p, nil
 	}
@@ -344,14 +376,20 @@ func (p *Plugin) handleMouseDoubleClick(action mouse.MouseAction) (*Plugin, tea.
 		return p, nil
 	}
 
-	if node.IsDir {
-		// Toggle folder expand/collapse
+	if node.Expandable() {
+		// Toggle folder or archive expand/collapse
 		_ = p.tree.Toggle(node)
 		p.treeCursor = idx
 		p.ensureTreeCursorVisible()
 		return p, nil
 	}
 
+	// Archive entries can only be previewed
+	if node.InArchive() {
+		p.treeCursor = idx
+		return p, p.openTab(node.Path, TabOpenReplace)
+	}
+
 	// Open file in editor (same as 'e' key) and pin the tab
 	cmd := p.openTab(node.Path, TabOpenReplace)
 	p.pinTab(p.activeTab)
@@ -388,6 +426,29 @@ func (p *Plugin) handleMouseScroll(action mouse.MouseAction) (*Plugin, tea.Cmd)
 		return p, p.loadPreviewForCursor()
 	}
 
+	if v := p.diffView; p.diffViewActive() {
+		v.scroll = max(min(v.scroll+delta, p.maxDiffScroll()), 0)
+		return p, nil
+	}
+	if v := p.dataView; p.dataViewActive() {
+		v.cursor += delta
+		v.clamp()
+		v.ensureVisible(p.visibleContentHeight() - v.headerLines())
+		return p, p.pageSQLiteRows()
+	}
+	if v := p.hexView; p.hexViewActive() {
+		v.top += int64(delta * v.rowBytes)
+		v.clampTop(p.hexRows())
+		return p, p.ensureHexWindow()
+	}
+	if delta < 0 {
+		p.previewFollow = false
+	}
+	if p.streamViewActive() {
+		p.previewScroll = max(min(p.previewScroll+delta, p.maxStreamTop()), 0)
+		return p, p.ensureStreamWindow()
+	}
+
 	// Scroll preview pane
 	lines := p.getPreviewLines()
 	visibleHeight := p.visibleContentHeight()
@@ -737,6 +798,20 @@ func (p *Plugin) handleInfoModalMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 	return p, nil
 }
 
+// handleBatchResultMouse handles mouse events in the batch result modal.
+func (p *Plugin) handleBatchResultMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureBatchResultModal()
+	if p.batchResultModal == nil {
+		p.closeBatchResult()
+		return p, nil
+	}
+
+	if p.batchResultModal.HandleMouse(msg, p.mouseHandler) == "cancel" {
+		p.closeBatchResult()
+	}
+	return p, nil
+}
+
 // handleBlameModalMouse handles mouse events in the blame modal.
 func (p *Plugin) handleBlameModalMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 	p.ensureBlameModal()
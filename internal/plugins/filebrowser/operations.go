@@ -59,6 +59,9 @@ func (p *Plugin) getCurrentPreviewLine() int {
 
 	// Clamp to valid range
 	maxLine := len(p.previewLines) - 1
+	if p.streamViewActive() {
+		maxLine = p.streamView.index.Lines() - 1
+	}
 	if maxLine < 0 {
 		maxLine = 0
 	}
@@ -166,6 +169,36 @@ func (p *Plugin) executeFileOp() (plugin.Plugin, tea.Cmd) {
 		return p, p.doCreate(input, p.fileOpMode == FileOpCreateDir)
 	}
 
+	if p.fileOpMode == FileOpDelete {
+		p.fileOpConfirmDelete = false
+		if len(p.fileOpBatch) > 0 {
+			items := p.fileOpBatch
+			p.resetFileOp()
+			return p, p.startBatch(BatchDelete, items, "")
+		}
+		return p, p.doDelete()
+	}
+
+	if p.fileOpMode == FileOpMove && len(p.fileOpBatch) > 0 {
+		return p.executeBatchMove(input)
+	}
+
+	if p.fileOpMode == FileOpExtract {
+		if filepath.IsAbs(input) {
+			p.fileOpError = "absolute paths not allowed"
+			return p, nil
+		}
+		if err := p.validateDestPath(filepath.Join(p.ctx.WorkDir, input)); err != nil {
+			p.fileOpError = "cannot extract outside project directory"
+			return p, nil
+		}
+		dest := filepath.Clean(input)
+		if dest == "." {
+			dest = ""
+		}
+		return p, p.doExtract(dest)
+	}
+
 	if p.fileOpTarget == nil || input == "" {
 		p.fileOpMode = FileOpNone
 		return p, nil
@@ -331,36 +364,24 @@ func (p *Plugin) doCreate(name string, isDir bool) tea.Cmd {
 	}
 }
 
-// doDelete deletes the target file or directory.
+// doDelete deletes the target file or directory, moving it to the project
+// trash unless the trash is disabled.
 func (p *Plugin) doDelete() tea.Cmd {
 	return func() tea.Msg {
 		if p.fileOpTarget == nil {
 			return FileOpErrorMsg{Err: fmt.Errorf("no target selected")}
 		}
 
-		fullPath := filepath.Join(p.ctx.WorkDir, p.fileOpTarget.Path)
-
-		// Validate path is within project (safety check)
-		absPath, err := filepath.Abs(fullPath)
-		if err != nil {
-			return FileOpErrorMsg{Err: fmt.Errorf("invalid path")}
-		}
-		absWorkDir, err := filepath.Abs(p.ctx.WorkDir)
-		if err != nil {
-			return FileOpErrorMsg{Err: fmt.Errorf("failed to resolve work directory")}
-		}
-		relPath, err := filepath.Rel(absWorkDir, absPath)
-		if err != nil || strings.HasPrefix(relPath, "..") {
-			return FileOpErrorMsg{Err: fmt.Errorf("cannot delete files outside project directory")}
-		}
-
-		// Don't allow deleting the project root
-		if relPath == "." {
-			return FileOpErrorMsg{Err: fmt.Errorf("cannot delete project root")}
+		if p.trashEnabled() {
+			entry, err := moveToTrash(p.ctx.WorkDir, p.fileOpTarget.Path)
+			if err != nil {
+				return FileOpErrorMsg{Err: err}
+			}
+			return DeleteSuccessMsg{Path: filepath.Join(p.ctx.WorkDir, entry.Path), TrashID: entry.ID}
 		}
 
-		// Remove file or directory (recursively for directories)
-		if err := os.RemoveAll(fullPath); err != nil {
+		fullPath, err := deletePath(p.ctx.WorkDir, p.fileOpTarget.Path)
+		if err != nil {
 			return FileOpErrorMsg{Err: err}
 		}
 
@@ -368,6 +389,21 @@ func (p *Plugin) doDelete() tea.Cmd {
 	}
 }
 
+// deletePath permanently removes a file or directory given relative to
+// workDir and returns its absolute path.
+func deletePath(workDir, path string) (string, error) {
+	fullPath, err := checkDeletable(workDir, path)
+	if err != nil {
+		return "", err
+	}
+
+	// Remove file or directory (recursively for directories)
+	if err := os.RemoveAll(fullPath); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
 // doPaste copies the clipboard file/directory to the target location.
 func (p *Plugin) doPaste(targetNode *FileNode) tea.Cmd {
 	return func() tea.Msg {
@@ -376,76 +412,99 @@ func (p *Plugin) doPaste(targetNode *FileNode) tea.Cmd {
 		}
 
 		srcPath := filepath.Join(p.ctx.WorkDir, p.clipboardPath)
-
-		// Determine destination directory
-		var destDir string
-		if targetNode.IsDir {
-			destDir = filepath.Join(p.ctx.WorkDir, targetNode.Path)
-		} else {
-			// If a file is selected, paste into its parent directory
-			destDir = filepath.Join(p.ctx.WorkDir, filepath.Dir(targetNode.Path))
-		}
-
-		// Check if source exists
-		srcInfo, err := os.Stat(srcPath)
+		destPath, err := copyInto(p.ctx.WorkDir, p.clipboardPath, pasteDir(p.ctx.WorkDir, targetNode))
 		if err != nil {
-			return FileOpErrorMsg{Err: fmt.Errorf("source not found: %s", filepath.Base(p.clipboardPath))}
+			return FileOpErrorMsg{Err: err}
 		}
 
-		// Generate destination path
-		srcName := filepath.Base(p.clipboardPath)
-		destPath := filepath.Join(destDir, srcName)
+		return PasteSuccessMsg{Src: srcPath, Dst: destPath}
+	}
+}
+
+// pasteDir returns the absolute directory a paste onto node lands in: the
+// node itself for directories, its parent for files.
+func pasteDir(workDir string, node *FileNode) string {
+	if node.IsDir {
+		return filepath.Join(workDir, node.Path)
+	}
+	return filepath.Join(workDir, filepath.Dir(node.Path))
+}
+
+// copyInto copies the file or directory at path (relative to workDir) into
+// destDir and returns the destination path. Name conflicts get a _copy or
+// _copyN suffix.
+func copyInto(workDir, path, destDir string) (string, error) {
+	srcPath := filepath.Join(workDir, path)
+
+	// Check if source exists
+	srcInfo, err := os.Stat(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("source not found: %s", filepath.Base(path))
+	}
 
-		// Handle name conflicts by appending _copy or _copy2, etc.
-		if _, err := os.Stat(destPath); err == nil {
-			base := srcName
-			ext := filepath.Ext(srcName)
-			if ext != "" {
-				base = srcName[:len(srcName)-len(ext)]
+	// Generate destination path
+	srcName := filepath.Base(path)
+	destPath := filepath.Join(destDir, srcName)
+
+	// Handle name conflicts by appending _copy or _copy2, etc.
+	if _, err := os.Stat(destPath); err == nil {
+		base := srcName
+		ext := filepath.Ext(srcName)
+		if ext != "" {
+			base = srcName[:len(srcName)-len(ext)]
+		}
+		for i := 1; ; i++ {
+			suffix := "_copy"
+			if i > 1 {
+				suffix = fmt.Sprintf("_copy%d", i)
 			}
-			for i := 1; ; i++ {
-				suffix := "_copy"
-				if i > 1 {
-					suffix = fmt.Sprintf("_copy%d", i)
-				}
-				newName := base + suffix + ext
-				destPath = filepath.Join(destDir, newName)
-				if _, err := os.Stat(destPath); os.IsNotExist(err) {
-					break
-				}
-				if i > 100 {
-					return FileOpErrorMsg{Err: fmt.Errorf("too many copies")}
-				}
+			newName := base + suffix + ext
+			destPath = filepath.Join(destDir, newName)
+			if _, err := os.Stat(destPath); os.IsNotExist(err) {
+				break
+			}
+			if i > 100 {
+				return "", fmt.Errorf("too many copies")
 			}
 		}
+	}
 
-		// Validate destination is within project
-		absDestPath, err := filepath.Abs(destPath)
-		if err != nil {
-			return FileOpErrorMsg{Err: fmt.Errorf("invalid path")}
+	// Validate destination is within project
+	absDestPath, err := filepath.Abs(destPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid path")
+	}
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve work directory")
+	}
+	relPath, err := filepath.Rel(absWorkDir, absDestPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("cannot paste outside project directory")
+	}
+
+	// Copy file or directory
+	if srcInfo.IsDir() {
+		// Copying a directory into itself would never terminate
+		if isWithin(srcPath, destDir) {
+			return "", fmt.Errorf("cannot copy a directory into itself")
 		}
-		absWorkDir, err := filepath.Abs(p.ctx.WorkDir)
-		if err != nil {
-			return FileOpErrorMsg{Err: fmt.Errorf("failed to resolve work directory")}
+		if err := copyDir(srcPath, destPath); err != nil {
+			return "", err
 		}
-		relPath, err := filepath.Rel(absWorkDir, absDestPath)
-		if err != nil || strings.HasPrefix(relPath, "..") {
-			return FileOpErrorMsg{Err: fmt.Errorf("cannot paste outside project directory")}
+	} else {
+		if err := copyFile(srcPath, destPath); err != nil {
+			return "", err
 		}
+	}
 
-		// Copy file or directory
-		if srcInfo.IsDir() {
-			if err := copyDir(srcPath, destPath); err != nil {
-				return FileOpErrorMsg{Err: err}
-			}
-		} else {
-			if err := copyFile(srcPath, destPath); err != nil {
-				return FileOpErrorMsg{Err: err}
-			}
-		}
+	return destPath, nil
+}
 
-		return PasteSuccessMsg{Src: srcPath, Dst: destPath}
-	}
+// isWithin reports whether path is dir or lies inside it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // copyFile copies a single file.
@@ -621,14 +680,69 @@ func (p *Plugin) openQuickOpen() (plugin.Plugin, tea.Cmd) {
 	p.quickOpenMode = true
 	p.quickOpenQuery = ""
 	p.quickOpenCursor = 0
+	p.quickOpenRecent = nil
+	p.updateQuickOpenMatches()
+
+	return p, nil
+}
+
+// openRecentFiles opens the quick open modal over recently opened files.
+func (p *Plugin) openRecentFiles() (plugin.Plugin, tea.Cmd) {
+	p.quickOpenMode = true
+	p.quickOpenQuery = ""
+	p.quickOpenCursor = 0
+	p.quickOpenRecent = p.recentFiles()
 	p.updateQuickOpenMatches()
 
 	return p, nil
 }
 
-// updateQuickOpenMatches filters files using fuzzy matching.
+// toggleQuickOpenRecent switches the open modal between all files and
+// recently opened files, keeping the query.
+func (p *Plugin) toggleQuickOpenRecent() {
+	if p.quickOpenRecent != nil {
+		p.quickOpenRecent = nil
+		if len(p.quickOpenFiles) == 0 {
+			p.buildFileCache()
+		}
+	} else {
+		p.quickOpenRecent = p.recentFiles()
+	}
+	p.quickOpenCursor = 0
+	p.updateQuickOpenMatches()
+}
+
+// updateQuickOpenMatches filters files using fuzzy matching, ranking
+// frequently and recently opened files higher.
 func (p *Plugin) updateQuickOpenMatches() {
-	p.quickOpenMatches = FuzzyFilter(p.quickOpenFiles, p.quickOpenQuery, quickOpenMaxResults)
+	files := p.quickOpenFiles
+	if p.quickOpenRecent != nil {
+		files = p.quickOpenRecent
+	}
+
+	if p.quickOpenQuery == "" {
+		// Most recently opened files first, then the rest by path length
+		recent := p.quickOpenRecent
+		if recent == nil {
+			recent = p.recentFiles()
+		}
+		p.quickOpenMatches = nil
+		seen := make(map[string]bool, len(recent))
+		for _, path := range recent[:min(len(recent), quickOpenMaxResults)] {
+			seen[path] = true
+			p.quickOpenMatches = append(p.quickOpenMatches, QuickOpenMatch{Path: path, Name: filepath.Base(path)})
+		}
+		if p.quickOpenRecent == nil {
+			for _, match := range FuzzyFilter(files, "", quickOpenMaxResults) {
+				if len(p.quickOpenMatches) < quickOpenMaxResults && !seen[match.Path] {
+					p.quickOpenMatches = append(p.quickOpenMatches, match)
+				}
+			}
+		}
+	} else {
+		matches := FuzzyFilter(files, p.quickOpenQuery, len(files))
+		p.quickOpenMatches = p.rankByFrecency(matches, quickOpenMaxResults)
+	}
 
 	// Reset cursor if out of bounds
 	if p.quickOpenCursor >= len(p.quickOpenMatches) {
@@ -653,6 +767,7 @@ func (p *Plugin) selectQuickOpenMatch() (plugin.Plugin, tea.Cmd) {
 	p.quickOpenQuery = ""
 	p.quickOpenMatches = nil
 	p.quickOpenCursor = 0
+	p.quickOpenRecent = nil
 
 	// Find the file in tree by walking down the path (efficient)
 	targetNode := p.findAndExpandPath(match.Path)
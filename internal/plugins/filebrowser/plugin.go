@@ -13,6 +13,7 @@ import (
 	"github.com/marcus/sidecar/internal/markdown"
 	"github.com/marcus/sidecar/internal/modal"
 	"github.com/marcus/sidecar/internal/mouse"
+	appmsg "github.com/marcus/sidecar/internal/msg"
 	"github.com/marcus/sidecar/internal/plugin"
 	"github.com/marcus/sidecar/internal/state"
 	"github.com/marcus/sidecar/internal/tty"
@@ -44,6 +45,7 @@ const (
 	FileOpCreateFile
 	FileOpCreateDir
 	FileOpDelete
+	FileOpExtract
 )
 
 // Message types
@@ -61,6 +63,11 @@ type (
 	NavigateToFileMsg struct {
 		Path string // Relative path from workdir
 	}
+	// EditFileMsg requests opening a file in the inline editor (from other plugins).
+	EditFileMsg struct {
+		Path   string // Relative path from workdir
+		LineNo int    // 0-indexed line to open at
+	}
 	// RevealErrorMsg is sent when reveal in file manager fails.
 	RevealErrorMsg struct {
 		Err error
@@ -74,6 +81,11 @@ type (
 		Src string
 		Dst string
 	}
+	// ExtractSuccessMsg is sent when archive entries are extracted.
+	ExtractSuccessMsg struct {
+		Dest  string // Destination directory, relative
+		Count int    // Files written
+	}
 	// CreateSuccessMsg is sent when a file/directory is created.
 	CreateSuccessMsg struct {
 		Path  string
@@ -81,7 +93,8 @@ type (
 	}
 	// DeleteSuccessMsg is sent when a file/directory is deleted.
 	DeleteSuccessMsg struct {
-		Path string
+		Path    string
+		TrashID string // Set when the item was moved to the trash
 	}
 	// PasteSuccessMsg is sent when a file/directory is pasted.
 	PasteSuccessMsg struct {
@@ -142,6 +155,22 @@ type Plugin struct {
 	markdownRenderMode bool               // true=rendered, false=raw
 	markdownRendered   []string           // Cached rendered lines
 
+	// Structured data view state (JSON, YAML, CSV, SQLite)
+	dataView    *dataView // nil unless the preview is a data file
+	dataViewRaw bool      // Show the raw text instead
+
+	// Hex view state for binary files
+	hexView *hexView // nil unless the preview is a binary file
+
+	// Git decorations and diff against HEAD
+	changedOnly bool      // Show only files with git changes, toggled with c
+	diffMode    bool      // Preview files as their diff against HEAD, toggled with d
+	diffView    *diffView // Diff of the previewed file while diffMode is on
+
+	// Streaming state for large text files
+	streamView    *streamView // nil unless the preview is streamed from disk
+	previewFollow bool        // Keep the end of the file in view as it grows
+
 	// Image preview state
 	imageRenderer *image.Renderer     // Terminal graphics renderer
 	isImage       bool                // True if current preview is an image
@@ -178,6 +207,22 @@ type Plugin struct {
 	quickOpenCursor  int
 	quickOpenFiles   []string // Cached file paths (relative)
 	quickOpenError   string   // Error message if scan failed/limited
+	quickOpenRecent  []string // Recently opened files; non-nil lists only these
+
+	// Frecency and jump history
+	visits  map[string]fileVisit // Opened files by path, for quick open ranking
+	jumps   []jumpEntry          // Opened files, oldest first
+	jumpPos int                  // Index of the current entry in jumps
+
+	// Symbol outline and symbol quick open state
+	symbolMode         bool
+	symbolState        *SymbolPickerState
+	symbolModal        *modal.Modal
+	symbolModalWidth   int
+	symbolIndex        []Symbol  // Cached project symbols, nil until indexed
+	symbolIndexSource  string    // How the index was built
+	symbolIndexLimited bool      // Indexing timed out
+	symbolIndexTime    time.Time // When the index was built
 
 	// Project-wide search state (ctrl+s)
 	projectSearchMode       bool
@@ -208,6 +253,7 @@ type Plugin struct {
 	fileOpConfirmDelete bool            // True when waiting for delete confirmation
 	fileOpButtonFocus   int             // Button focus: 0=input, 1=confirm, 2=cancel
 	fileOpButtonHover   int             // Button hover: 0=none, 1=confirm, 2=cancel
+	fileOpBatch         []string        // Selected paths for a batch move/delete
 
 	// Line jump state (vim-style :<number>)
 	lineJumpMode   bool
@@ -220,8 +266,33 @@ type Plugin struct {
 	fileOpShowSuggestions bool     // Show suggestions dropdown
 
 	// Clipboard state (yank/paste)
-	clipboardPath  string // Relative path of yanked file/directory
-	clipboardIsDir bool   // Whether yanked item is a directory
+	clipboardPath  string   // Relative path of yanked file/directory
+	clipboardIsDir bool     // Whether yanked item is a directory
+	clipboardBatch []string // Relative paths yanked from a multi-selection
+
+	// Multi-select state
+	selectedPaths map[string]bool // Selected relative paths; survive tree rebuilds
+	visualMode    bool            // Range selection in progress
+	visualAnchor  int             // Tree index where the range started
+
+	// Batch operation state
+	batch                 *batchState // Running batch operation, nil when idle
+	batchResult           *batchState // Finished batch shown in the result modal
+	batchResultModal      *modal.Modal
+	batchResultModalWidth int
+
+	// Undo journal for moves, renames and deletes (newest last)
+	undoJournal []journalEntry
+
+	// Trash view state
+	trashMode       bool
+	trashEntries    []TrashEntry
+	trashCursor     int
+	trashLoading    bool
+	trashError      string
+	trashConfirm    string // Pending purge confirmation: trashConfirmPurge or trashConfirmEmpty
+	trashModal      *modal.Modal
+	trashModalWidth int
 
 	// File watcher
 	watcher     *Watcher
@@ -283,6 +354,12 @@ func (p *Plugin) Init(ctx *plugin.Context) error {
 
 	// Reset state flags for reinit support (project switching)
 	p.stateRestored = false
+	p.undoJournal = nil
+	p.visits, p.jumps, p.jumpPos = nil, nil, 0
+	p.clearSelection()
+	// Steps of a running batch arrive stale and are dropped, so forget it
+	p.batch = nil
+	p.closeBatchResult()
 
 	// Initialize markdown renderer
 	renderer, err := markdown.NewRenderer()
@@ -304,6 +381,7 @@ func (p *Plugin) Start() tea.Cmd {
 	return tea.Batch(
 		p.refresh(),
 		p.startWatcher(),
+		p.purgeExpired(),
 	)
 }
 
@@ -374,6 +452,11 @@ func (p *Plugin) saveState() {
 		ShowIgnored:   &p.showIgnored,
 		Tabs:          tabStates,
 		ActiveTab:     activeTab,
+		Visits:        p.visitStates(),
+	}
+	if !p.stateRestored {
+		// Keep the saved visits until they have been merged in
+		fbState.Visits = state.GetFileBrowserState(p.ctx.ProjectRoot).Visits
 	}
 
 	if err := state.SetFileBrowserState(p.ctx.ProjectRoot, fbState); err != nil {
@@ -541,6 +624,8 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if msg.Err != nil {
 			p.ctx.Logger.Error("tree build failed", "error", msg.Err)
 		}
+		// Git decorations are read once the tree is in place
+		statusCmd := loadGitStatus(p.ctx.WorkDir, p.ctx.Epoch)
 		// Handle pending auto-open from file creation
 		if p.pendingOpenFile != "" {
 			path := p.pendingOpenFile
@@ -549,19 +634,33 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			// Restore state after first tree build
 			if !p.stateRestored {
 				p.stateRestored = true
-				return p, tea.Batch(navCmd, p.restoreState())
+				return p, tea.Batch(navCmd, p.restoreState(), statusCmd)
 			}
-			return p, navCmd
+			return p, tea.Batch(navCmd, statusCmd)
 		}
 		// Restore state after first tree build
 		if !p.stateRestored {
 			p.stateRestored = true
-			return p, p.restoreState()
+			return p, tea.Batch(p.restoreState(), statusCmd)
+		}
+		return p, statusCmd
+
+	case GitStatusMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
 		}
+		return p, p.handleGitStatus(msg)
+
+	case GitDiffLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleGitDiffLoaded(msg)
 
 	case StateRestoredMsg:
 		// Apply restored state
 		fbState := msg.State
+		p.restoreVisits(fbState.Visits)
 
 		// Restore expanded directories
 		if len(fbState.ExpandedDirs) > 0 {
@@ -631,6 +730,10 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			p.applyPreviewResult(msg.Result)
 			p.updateActiveTabResult(msg.Result)
 			p.clampPreviewScroll()
+			if p.previewFollow && !p.streamViewActive() {
+				p.previewScroll = max(len(p.getPreviewLines())-p.visibleContentHeight(), 0)
+			}
+			cmd := tea.Batch(p.reloadDataTable(), p.ensureHexWindow(), p.ensureStreamView())
 
 			// Re-run search if still in search mode (e.g., navigating files with j/k)
 			if p.contentSearchMode && p.contentSearchQuery != "" {
@@ -641,7 +744,45 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 					p.scrollToNearestMatch(targetScroll)
 				}
 			}
+			return p, cmd
+		}
+
+	case HexChunkLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleHexChunkLoaded(msg)
+
+	case HexSearchMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleHexSearch(msg)
+
+	case StreamIndexMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
 		}
+		return p, p.handleStreamIndex(msg)
+
+	case StreamLinesMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleStreamLines(msg)
+
+	case StreamSearchMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleStreamSearch(msg)
+
+	case DataRowsLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleDataRowsLoaded(msg)
+		return p, nil
 
 	case RefreshMsg:
 		return p, p.refresh()
@@ -656,11 +797,19 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if p.previewFile != "" {
 			cmds = append(cmds, LoadPreview(p.ctx.WorkDir, p.previewFile, p.ctx.Epoch))
 		}
+		if p.tree.Git != nil && !p.streamViewActive() {
+			// Saving the file changes its git state and diff; streamed
+			// logs change too often to run git status each time
+			cmds = append(cmds, loadGitStatus(p.ctx.WorkDir, p.ctx.Epoch))
+		}
 		return p, tea.Batch(cmds...)
 
 	case NavigateToFileMsg:
 		return p.navigateToFile(msg.Path)
 
+	case EditFileMsg:
+		return p.editFile(msg.Path, msg.LineNo)
+
 	case RevealErrorMsg:
 		p.ctx.Logger.Error("file browser: reveal failed", "error", msg.Err)
 
@@ -668,12 +817,23 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.fileOpError = msg.Err.Error()
 
 	case FileOpSuccessMsg:
+		p.recordMoveUndo(msg.Src, msg.Dst)
 		// Clear file operation state and refresh
 		p.fileOpMode = FileOpNone
 		p.fileOpTarget = nil
 		p.fileOpError = ""
 		return p, p.refresh()
 
+	case ExtractSuccessMsg:
+		p.fileOpMode = FileOpNone
+		p.fileOpTarget = nil
+		p.fileOpError = ""
+		dest := msg.Dest
+		if dest == "" {
+			dest = "project root"
+		}
+		return p, tea.Batch(p.refresh(), appmsg.ShowToast("Extracted "+pluralFiles(msg.Count)+" to "+dest, 3*time.Second))
+
 	case CreateSuccessMsg:
 		// Clear file operation state and refresh
 		p.fileOpMode = FileOpNone
@@ -693,14 +853,42 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.fileOpTarget = nil
 		p.fileOpError = ""
 		p.fileOpConfirmDelete = false
-		// Clean up tabs for the deleted file/directory
+		// Clean up tabs for the deleted file/directory (tabs hold relative paths)
 		p.closeTabsForPath(msg.Path)
-		return p, p.refresh()
+		rel, err := filepath.Rel(p.ctx.WorkDir, msg.Path)
+		if err != nil {
+			return p, p.refresh()
+		}
+		p.closeTabsForPath(rel)
+		if msg.TrashID == "" {
+			return p, p.refresh()
+		}
+		p.recordUndo(BatchDelete, journalItem{From: rel, TrashID: msg.TrashID})
+		return p, tea.Batch(p.refresh(), appmsg.ShowToast("Moved "+rel+" to trash (u to undo)", 3*time.Second))
 
 	case PasteSuccessMsg:
 		// Refresh after paste
 		return p, p.refresh()
 
+	case BatchStepMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleBatchStep(msg)
+
+	case TrashLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleTrashLoaded(msg)
+		return p, nil
+
+	case TrashPurgedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleTrashPurged(msg)
+
 	case GitInfoMsg:
 		p.gitStatus = msg.Status
 		p.gitLastCommit = msg.LastCommit
@@ -738,15 +926,36 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			if msg.Error != nil {
 				p.projectSearchState.Error = msg.Error.Error()
 				p.projectSearchState.Results = nil
+				p.projectSearchState.Truncated = false
 			} else {
 				p.projectSearchState.Error = ""
-				p.projectSearchState.Results = msg.Results
+				p.projectSearchState.SetResults(msg.Results)
+				p.projectSearchState.Truncated = msg.Truncated
 				p.projectSearchState.ScrollOffset = 0
 				// Set cursor to first match (skip file headers)
 				p.projectSearchState.Cursor = p.projectSearchState.FirstMatchIndex()
 			}
 		}
 
+	case SymbolsLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleSymbolsLoaded(msg)
+		return p, nil
+
+	case DefinitionMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleDefinition(msg)
+
+	case ProjectReplaceMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleProjectReplace(msg)
+
 	case InlineEditStartedMsg:
 		return p, p.handleInlineEditStarted(msg)
 
@@ -790,12 +999,15 @@ func (p *Plugin) Commands() []plugin.Command {
 	return []plugin.Command{
 		// Tree pane commands
 		{ID: "quick-open", Name: "Open", Description: "Quick open file by name", Category: plugin.CategorySearch, Context: "file-browser-tree", Priority: 1},
+		{ID: "recent-files", Name: "Recent", Description: "Quick open recently opened file", Category: plugin.CategorySearch, Context: "file-browser-tree", Priority: 2},
 		{ID: "new-tab", Name: "Tab+", Description: "Open file in new tab", Category: plugin.CategoryNavigation, Context: "file-browser-tree", Priority: 2},
 		{ID: "project-search", Name: "Find", Description: "Search in project", Category: plugin.CategorySearch, Context: "file-browser-tree", Priority: 2},
 		{ID: "info", Name: "Info", Description: "Show file info", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 2},
 		{ID: "edit", Name: "Edit", Description: "Edit file inline", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 2},
 		{ID: "edit-external", Name: "Edit+", Description: "Edit in full terminal", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 2},
 		{ID: "blame", Name: "Blame", Description: "Show git blame", Category: plugin.CategoryView, Context: "file-browser-tree", Priority: 3},
+		{ID: "file-history", Name: "History", Description: "Show git history in the git tab", Category: plugin.CategoryView, Context: "file-browser-tree", Priority: 3},
+		{ID: "apply-patch", Name: "Apply", Description: "Apply patch file in the git tab", Category: plugin.CategoryGit, Context: "file-browser-tree", Priority: 4},
 		{ID: "search", Name: "Filter", Description: "Filter files by name", Category: plugin.CategorySearch, Context: "file-browser-tree", Priority: 3},
 		{ID: "close-tab", Name: "Close", Description: "Close active tab", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 4},
 		{ID: "create-file", Name: "New", Description: "Create new file", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 4},
@@ -803,6 +1015,8 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "delete", Name: "Delete", Description: "Delete file or directory", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 4},
 		{ID: "prev-tab", Name: "Tab←", Description: "Previous tab", Category: plugin.CategoryNavigation, Context: "file-browser-tree", Priority: 5},
 		{ID: "next-tab", Name: "Tab→", Description: "Next tab", Category: plugin.CategoryNavigation, Context: "file-browser-tree", Priority: 5},
+		{ID: "jump-back", Name: "Jump←", Description: "Back to previously opened file", Category: plugin.CategoryNavigation, Context: "file-browser-tree", Priority: 5},
+		{ID: "jump-forward", Name: "Jump→", Description: "Forward in opened file history", Category: plugin.CategoryNavigation, Context: "file-browser-tree", Priority: 6},
 		{ID: "yank", Name: "Yank", Description: "Mark file for copy (use p to paste)", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 5},
 		{ID: "copy-path", Name: "CopyPath", Description: "Copy relative path to clipboard", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 5},
 		{ID: "paste", Name: "Paste", Description: "Paste yanked file", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 5},
@@ -813,15 +1027,37 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "reveal", Name: "Reveal", Description: "Reveal in file manager", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 8},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle tree pane visibility", Category: plugin.CategoryView, Context: "file-browser-tree", Priority: 9},
 		{ID: "toggle-ignored", Name: "Ignored", Description: "Toggle git-ignored file visibility", Category: plugin.CategoryView, Context: "file-browser-tree", Priority: 9},
+		{ID: "toggle-select", Name: "Select", Description: "Toggle item in multi-selection", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 5},
+		{ID: "select-range", Name: "Range", Description: "Start or finish a range selection", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 5},
+		{ID: "clear-selection", Name: "Unselect", Description: "Clear multi-selection", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 6},
+		{ID: "stage", Name: "Stage", Description: "Stage selection with git add", Category: plugin.CategoryGit, Context: "file-browser-tree", Priority: 5},
+		{ID: "undo", Name: "Undo", Description: "Undo last move, rename or delete", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 4},
+		{ID: "trash", Name: "Trash", Description: "Browse, restore or purge deleted files", Category: plugin.CategoryView, Context: "file-browser-tree", Priority: 6},
+		{ID: "outline", Name: "Outline", Description: "List symbols in the previewed file", Category: plugin.CategoryNavigation, Context: "file-browser-tree", Priority: 3},
+		{ID: "symbol-search", Name: "Symbol", Description: "Quick open symbol in project", Category: plugin.CategorySearch, Context: "file-browser-tree", Priority: 3},
+		{ID: "extract", Name: "Extract", Description: "Extract archive or archive entry", Category: plugin.CategoryActions, Context: "file-browser-tree", Priority: 6},
+		{ID: "toggle-changed", Name: "Changed", Description: "Show only files with git changes", Category: plugin.CategoryGit, Context: "file-browser-tree", Priority: 4},
+		{ID: "diff", Name: "Diff", Description: "Preview files as their diff against HEAD", Category: plugin.CategoryGit, Context: "file-browser-tree", Priority: 4},
 		// Preview pane commands
 		{ID: "quick-open", Name: "Open", Description: "Quick open file by name", Category: plugin.CategorySearch, Context: "file-browser-preview", Priority: 1},
+		{ID: "recent-files", Name: "Recent", Description: "Quick open recently opened file", Category: plugin.CategorySearch, Context: "file-browser-preview", Priority: 2},
 		{ID: "project-search", Name: "Find", Description: "Search in project", Category: plugin.CategorySearch, Context: "file-browser-preview", Priority: 2},
+		{ID: "outline", Name: "Outline", Description: "List symbols in this file", Category: plugin.CategoryNavigation, Context: "file-browser-preview", Priority: 2},
+		{ID: "symbol-search", Name: "Symbol", Description: "Quick open symbol in project", Category: plugin.CategorySearch, Context: "file-browser-preview", Priority: 3},
+		{ID: "go-to-definition", Name: "Def", Description: "Go to definition of selected identifier", Category: plugin.CategoryNavigation, Context: "file-browser-preview", Priority: 3},
+		{ID: "extract", Name: "Extract", Description: "Extract archive or archive entry", Category: plugin.CategoryActions, Context: "file-browser-preview", Priority: 6},
+		{ID: "follow", Name: "Follow", Description: "Follow the end of the file as it grows", Category: plugin.CategoryView, Context: "file-browser-preview", Priority: 6},
+		{ID: "diff", Name: "Diff", Description: "Show the file's diff against HEAD", Category: plugin.CategoryGit, Context: "file-browser-preview", Priority: 4},
 		{ID: "info", Name: "Info", Description: "Show file info", Category: plugin.CategoryActions, Context: "file-browser-preview", Priority: 2},
 		{ID: "edit", Name: "Edit", Description: "Edit file inline", Category: plugin.CategoryActions, Context: "file-browser-preview", Priority: 2},
 		{ID: "edit-external", Name: "Edit+", Description: "Edit in full terminal", Category: plugin.CategoryActions, Context: "file-browser-preview", Priority: 2},
 		{ID: "prev-tab", Name: "Tab←", Description: "Previous tab", Category: plugin.CategoryNavigation, Context: "file-browser-preview", Priority: 3},
 		{ID: "next-tab", Name: "Tab→", Description: "Next tab", Category: plugin.CategoryNavigation, Context: "file-browser-preview", Priority: 3},
+		{ID: "jump-back", Name: "Jump←", Description: "Back to previously opened file", Category: plugin.CategoryNavigation, Context: "file-browser-preview", Priority: 3},
+		{ID: "jump-forward", Name: "Jump→", Description: "Forward in opened file history", Category: plugin.CategoryNavigation, Context: "file-browser-preview", Priority: 4},
 		{ID: "blame", Name: "Blame", Description: "Show git blame", Category: plugin.CategoryView, Context: "file-browser-preview", Priority: 3},
+		{ID: "file-history", Name: "History", Description: "Show git history in the git tab", Category: plugin.CategoryView, Context: "file-browser-preview", Priority: 3},
+		{ID: "apply-patch", Name: "Apply", Description: "Apply patch file in the git tab", Category: plugin.CategoryGit, Context: "file-browser-preview", Priority: 4},
 		{ID: "search-content", Name: "Search", Description: "Search file content", Category: plugin.CategorySearch, Context: "file-browser-preview", Priority: 3},
 		{ID: "toggle-wrap", Name: "Wrap", Description: "Toggle line wrapping", Category: plugin.CategoryView, Context: "file-browser-preview", Priority: 3},
 		{ID: "toggle-markdown", Name: "Render", Description: "Toggle markdown rendering", Category: plugin.CategoryActions, Context: "file-browser-preview", Priority: 4},
@@ -834,6 +1070,42 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "yank-path", Name: "Path", Description: "Copy file path", Category: plugin.CategoryActions, Context: "file-browser-preview", Priority: 8},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle tree pane visibility", Category: plugin.CategoryView, Context: "file-browser-preview", Priority: 9},
 		{ID: "toggle-ignored", Name: "Ignored", Description: "Toggle git-ignored file visibility", Category: plugin.CategoryView, Context: "file-browser-preview", Priority: 9},
+		// Data view commands (JSON, YAML, CSV and SQLite preview)
+		{ID: "filter-data", Name: "Filter", Description: "Filter with a jq path or matching text", Category: plugin.CategorySearch, Context: "file-browser-data", Priority: 1},
+		{ID: "toggle-node", Name: "Expand", Description: "Expand or collapse value, open table", Category: plugin.CategoryNavigation, Context: "file-browser-data", Priority: 2},
+		{ID: "sort-column", Name: "Sort", Description: "Cycle sort on selected column", Category: plugin.CategoryView, Context: "file-browser-data", Priority: 2},
+		{ID: "hide-column", Name: "Hide", Description: "Hide selected column", Category: plugin.CategoryView, Context: "file-browser-data", Priority: 3},
+		{ID: "show-columns", Name: "Columns", Description: "Show all columns", Category: plugin.CategoryView, Context: "file-browser-data", Priority: 4},
+		{ID: "toggle-raw", Name: "Raw", Description: "Toggle raw text view", Category: plugin.CategoryView, Context: "file-browser-data", Priority: 3},
+		{ID: "quick-open", Name: "Open", Description: "Quick open file by name", Category: plugin.CategorySearch, Context: "file-browser-data", Priority: 4},
+		{ID: "back", Name: "Back", Description: "Clear filter, close table or return to tree", Category: plugin.CategoryNavigation, Context: "file-browser-data", Priority: 5},
+		{ID: "confirm", Name: "Apply", Description: "Apply filter", Category: plugin.CategoryActions, Context: "file-browser-data-filter", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel filter", Category: plugin.CategoryActions, Context: "file-browser-data-filter", Priority: 1},
+		// Hex view commands (binary files)
+		{ID: "search-bytes", Name: "Search", Description: "Search for hex bytes or text", Category: plugin.CategorySearch, Context: "file-browser-hex", Priority: 1},
+		{ID: "jump-offset", Name: "Offset", Description: "Go to byte offset", Category: plugin.CategoryNavigation, Context: "file-browser-hex", Priority: 1},
+		{ID: "next-match", Name: "Next", Description: "Next match", Category: plugin.CategoryNavigation, Context: "file-browser-hex", Priority: 2},
+		{ID: "prev-match", Name: "Prev", Description: "Previous match", Category: plugin.CategoryNavigation, Context: "file-browser-hex", Priority: 2},
+		{ID: "extract", Name: "Extract", Description: "Extract archive or archive entry", Category: plugin.CategoryActions, Context: "file-browser-hex", Priority: 3},
+		{ID: "quick-open", Name: "Open", Description: "Quick open file by name", Category: plugin.CategorySearch, Context: "file-browser-hex", Priority: 4},
+		{ID: "back", Name: "Back", Description: "Clear highlight or return to tree", Category: plugin.CategoryNavigation, Context: "file-browser-hex", Priority: 5},
+		{ID: "confirm", Name: "Go", Description: "Search or jump", Category: plugin.CategoryActions, Context: "file-browser-hex-input", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel input", Category: plugin.CategoryActions, Context: "file-browser-hex-input", Priority: 1},
+		// Stream view commands (large files)
+		{ID: "search-content", Name: "Search", Description: "Search file content", Category: plugin.CategorySearch, Context: "file-browser-stream", Priority: 1},
+		{ID: "follow", Name: "Follow", Description: "Follow the end of the file as it grows", Category: plugin.CategoryView, Context: "file-browser-stream", Priority: 1},
+		{ID: "next-match", Name: "Next", Description: "Next match", Category: plugin.CategoryNavigation, Context: "file-browser-stream", Priority: 2},
+		{ID: "prev-match", Name: "Prev", Description: "Previous match", Category: plugin.CategoryNavigation, Context: "file-browser-stream", Priority: 2},
+		{ID: "quick-open", Name: "Open", Description: "Quick open file by name", Category: plugin.CategorySearch, Context: "file-browser-stream", Priority: 4},
+		{ID: "back", Name: "Back", Description: "Clear search or return to tree", Category: plugin.CategoryNavigation, Context: "file-browser-stream", Priority: 5},
+		{ID: "confirm", Name: "Search", Description: "Run search", Category: plugin.CategoryActions, Context: "file-browser-stream-input", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel search", Category: plugin.CategoryActions, Context: "file-browser-stream-input", Priority: 1},
+		// Diff view commands
+		{ID: "next-hunk", Name: "Next", Description: "Next hunk", Category: plugin.CategoryNavigation, Context: "file-browser-diff", Priority: 1},
+		{ID: "prev-hunk", Name: "Prev", Description: "Previous hunk", Category: plugin.CategoryNavigation, Context: "file-browser-diff", Priority: 1},
+		{ID: "diff", Name: "Contents", Description: "Show file contents instead of the diff", Category: plugin.CategoryGit, Context: "file-browser-diff", Priority: 2},
+		{ID: "blame", Name: "Blame", Description: "Show git blame", Category: plugin.CategoryGit, Context: "file-browser-diff", Priority: 3},
+		{ID: "quick-open", Name: "Open", Description: "Quick open file by name", Category: plugin.CategorySearch, Context: "file-browser-diff", Priority: 4},
 		// Tree search commands
 		{ID: "confirm", Name: "Go", Description: "Jump to match", Category: plugin.CategoryNavigation, Context: "file-browser-search", Priority: 1},
 		{ID: "cancel", Name: "Cancel", Description: "Cancel search", Category: plugin.CategoryActions, Context: "file-browser-search", Priority: 1},
@@ -842,11 +1114,18 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "cancel", Name: "Cancel", Description: "Cancel search", Category: plugin.CategoryActions, Context: "file-browser-content-search", Priority: 1},
 		// Quick open commands
 		{ID: "select", Name: "Open", Description: "Open selected file", Category: plugin.CategoryActions, Context: "file-browser-quick-open", Priority: 1},
+		{ID: "toggle-recent", Name: "Recent", Description: "Switch between all and recently opened files", Category: plugin.CategoryView, Context: "file-browser-quick-open", Priority: 2},
 		{ID: "cancel", Name: "Cancel", Description: "Cancel quick open", Category: plugin.CategoryActions, Context: "file-browser-quick-open", Priority: 1},
+		// Symbol outline / quick open commands
+		{ID: "select", Name: "Jump", Description: "Jump to selected symbol", Category: plugin.CategoryNavigation, Context: "file-browser-symbols", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close symbol list", Category: plugin.CategoryActions, Context: "file-browser-symbols", Priority: 1},
 		// Project search commands
 		{ID: "select", Name: "Open", Description: "Open selected result", Category: plugin.CategoryActions, Context: "file-browser-project-search", Priority: 1},
 		{ID: "toggle", Name: "Toggle", Description: "Expand/collapse file", Category: plugin.CategoryActions, Context: "file-browser-project-search", Priority: 2},
 		{ID: "cancel", Name: "Close", Description: "Close search", Category: plugin.CategoryActions, Context: "file-browser-project-search", Priority: 3},
+		{ID: "toggle-replace", Name: "Replace", Description: "Show or hide the replace field", Category: plugin.CategoryActions, Context: "file-browser-project-search", Priority: 3},
+		{ID: "exclude-match", Name: "Exclude", Description: "Include or exclude match from replace", Category: plugin.CategoryActions, Context: "file-browser-project-search", Priority: 4},
+		{ID: "replace-all", Name: "Apply", Description: "Replace included matches", Category: plugin.CategoryActions, Context: "file-browser-project-search", Priority: 4},
 		// File operation commands (move/rename/create/delete)
 		{ID: "confirm", Name: "Confirm", Description: "Confirm operation", Category: plugin.CategoryActions, Context: "file-browser-file-op", Priority: 1},
 		{ID: "cancel", Name: "Cancel", Description: "Cancel operation", Category: plugin.CategoryActions, Context: "file-browser-file-op", Priority: 1},
@@ -855,6 +1134,13 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "cancel", Name: "Cancel", Description: "Cancel jump", Category: plugin.CategoryActions, Context: "file-browser-line-jump", Priority: 1},
 		// Info modal commands
 		{ID: "close", Name: "Close", Description: "Close info modal", Category: plugin.CategoryActions, Context: "file-browser-info", Priority: 1},
+		// Trash view commands
+		{ID: "restore", Name: "Restore", Description: "Restore item to its original path", Category: plugin.CategoryActions, Context: "file-browser-trash", Priority: 1},
+		{ID: "purge", Name: "Purge", Description: "Permanently delete item", Category: plugin.CategoryActions, Context: "file-browser-trash", Priority: 2},
+		{ID: "empty-trash", Name: "Empty", Description: "Permanently delete all items", Category: plugin.CategoryActions, Context: "file-browser-trash", Priority: 3},
+		{ID: "close", Name: "Close", Description: "Close trash", Category: plugin.CategoryActions, Context: "file-browser-trash", Priority: 1},
+		// Batch result modal commands
+		{ID: "close", Name: "Close", Description: "Close batch results", Category: plugin.CategoryActions, Context: "file-browser-batch-result", Priority: 1},
 		// Blame view commands
 		{ID: "close", Name: "Close", Description: "Close blame view", Category: plugin.CategoryActions, Context: "file-browser-blame", Priority: 1},
 		{ID: "view-commit", Name: "Details", Description: "View commit details", Category: plugin.CategoryActions, Context: "file-browser-blame", Priority: 2},
@@ -873,12 +1159,21 @@ func (p *Plugin) FocusContext() string {
 	if p.quickOpenMode {
 		return "file-browser-quick-open"
 	}
+	if p.symbolMode {
+		return "file-browser-symbols"
+	}
 	if p.infoMode {
 		return "file-browser-info"
 	}
 	if p.blameMode {
 		return "file-browser-blame"
 	}
+	if p.batchResult != nil {
+		return "file-browser-batch-result"
+	}
+	if p.trashMode {
+		return "file-browser-trash"
+	}
 	if p.fileOpMode != FileOpNone {
 		return "file-browser-file-op"
 	}
@@ -891,7 +1186,28 @@ func (p *Plugin) FocusContext() string {
 	if p.searchMode {
 		return "file-browser-search"
 	}
+	if p.dataViewActive() && p.dataView.filtering {
+		return "file-browser-data-filter"
+	}
+	if p.hexViewActive() && p.hexView.prompt != hexPromptNone {
+		return "file-browser-hex-input"
+	}
+	if p.streamViewActive() && p.streamView.prompt {
+		return "file-browser-stream-input"
+	}
 	if p.activePane == PanePreview {
+		if p.diffViewActive() {
+			return "file-browser-diff"
+		}
+		if p.dataViewActive() {
+			return "file-browser-data"
+		}
+		if p.hexViewActive() {
+			return "file-browser-hex"
+		}
+		if p.streamViewActive() {
+			return "file-browser-stream"
+		}
 		return "file-browser-preview"
 	}
 	return "file-browser-tree"
@@ -903,8 +1219,12 @@ func (p *Plugin) ConsumesTextInput() bool {
 	return p.searchMode ||
 		p.contentSearchMode ||
 		p.quickOpenMode ||
+		p.symbolMode ||
 		p.projectSearchMode ||
 		p.fileOpMode != FileOpNone ||
 		p.lineJumpMode ||
-		p.inlineEditMode
+		p.inlineEditMode ||
+		(p.dataViewActive() && p.dataView.filtering) ||
+		(p.hexViewActive() && p.hexView.prompt != hexPromptNone) ||
+		(p.streamViewActive() && p.streamView.prompt)
 }
@@ -3,6 +3,7 @@ package filebrowser
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,6 +32,10 @@ type PreviewResult struct {
 	TotalSize        int64
 	ModTime          time.Time   // File modification time
 	Mode             os.FileMode // File permissions
+	Data             *dataDoc    // Structured view of JSON, YAML, CSV and SQLite files
+	Bytes            []byte      // Start of a binary file, for the hex view
+	HexPath          string      // Absolute path to page the hex view from, "" if not on disk
+	StreamPath       string      // Absolute path of a large text file to stream, "" otherwise
 	Error            error
 }
 
@@ -51,6 +56,10 @@ func LoadPreview(rootDir, path string, epoch uint64) tea.Cmd {
 
 		info, err := os.Stat(fullPath)
 		if err != nil {
+			// Entries inside archives are read from the archive
+			if archive, entry, ok := splitArchivePath(rootDir, path); ok {
+				return PreviewLoadedMsg{Epoch: epoch, Path: path, Result: loadArchivePreview(filepath.Join(rootDir, archive), entry)}
+			}
 			return PreviewLoadedMsg{
 				Epoch:  epoch,
 				Path:   path,
@@ -71,13 +80,6 @@ func LoadPreview(rootDir, path string, epoch uint64) tea.Cmd {
 			return PreviewLoadedMsg{Epoch: epoch, Path: path, Result: result}
 		}
 
-		// Check size limit
-		readSize := info.Size()
-		if readSize > maxPreviewSize {
-			readSize = maxPreviewSize
-			result.IsTruncated = true
-		}
-
 		// Read file
 		f, err := os.Open(fullPath)
 		if err != nil {
@@ -86,34 +88,35 @@ func LoadPreview(rootDir, path string, epoch uint64) tea.Cmd {
 		}
 		defer func() { _ = f.Close() }()
 
+		// Large text files are streamed from disk instead of loaded
+		readSize := info.Size()
+		if readSize > maxPreviewSize {
+			head := make([]byte, 512)
+			n, _ := f.Read(head)
+			if !isBinary(head[:n]) && info.Mode().IsRegular() {
+				result.StreamPath = fullPath
+				// CSV and TSV still open as a table of their first rows;
+				// the raw toggle shows the stream
+				if dataFormatFor(path, head[:n]) == dataCSV {
+					data := make([]byte, maxPreviewSize)
+					n, _ := f.ReadAt(data, 0)
+					result.Data = loadDataDoc(dataCSV, path, data[:n], true)
+				}
+				return PreviewLoadedMsg{Epoch: epoch, Path: path, Result: result}
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				result.Error = err
+				return PreviewLoadedMsg{Epoch: epoch, Path: path, Result: result}
+			}
+			readSize = maxPreviewSize
+			result.IsTruncated = true
+		}
+
 		data := make([]byte, readSize)
 		n, _ := f.Read(data)
 		data = data[:n]
 
-		// Check for binary (fm pattern)
-		if isBinary(data) {
-			result.IsBinary = true
-			return PreviewLoadedMsg{Epoch: epoch, Path: path, Result: result}
-		}
-
-		result.Content = string(data)
-		result.Lines = strings.Split(result.Content, "\n")
-
-		// Apply syntax highlighting using theme-configured style
-		highlighted, err := Highlight(result.Content, filepath.Ext(path), styles.GetSyntaxTheme())
-		if err == nil {
-			result.HighlightedLines = strings.Split(highlighted, "\n")
-		} else {
-			// Fallback to raw lines
-			result.HighlightedLines = result.Lines
-		}
-
-		// Limit lines
-		if len(result.Lines) > maxPreviewLines {
-			result.Lines = result.Lines[:maxPreviewLines]
-			result.HighlightedLines = result.HighlightedLines[:maxPreviewLines]
-			result.IsTruncated = true
-		}
+		fillPreview(&result, path, fullPath, data)
 
 		return PreviewLoadedMsg{
 			Epoch:  epoch,
@@ -123,6 +126,63 @@ func LoadPreview(rootDir, path string, epoch uint64) tea.Cmd {
 	}
 }
 
+// loadArchivePreview reads a file inside an archive for preview. The hex
+// view of a binary entry is limited to the bytes read.
+func loadArchivePreview(archivePath, entry string) PreviewResult {
+	data, info, truncated, err := readArchiveEntry(archivePath, entry, maxPreviewSize)
+	if err != nil {
+		return PreviewResult{Error: err}
+	}
+	result := PreviewResult{
+		TotalSize:   info.Size,
+		ModTime:     info.ModTime,
+		Mode:        info.Mode,
+		IsTruncated: truncated,
+	}
+	fillPreview(&result, entry, "", data)
+	return result
+}
+
+// fillPreview fills in the content of a preview from the bytes read.
+// fullPath is empty for files that are not on disk.
+func fillPreview(result *PreviewResult, path, fullPath string, data []byte) {
+	format := dataFormatFor(path, data)
+
+	// Check for binary (fm pattern)
+	if isBinary(data) {
+		result.IsBinary = true
+		result.Bytes = data
+		result.HexPath = fullPath
+		if format == dataSQLite && fullPath != "" {
+			result.Data = loadDataDoc(format, fullPath, nil, false)
+		}
+		return
+	}
+
+	if format != dataNone && format != dataSQLite {
+		result.Data = loadDataDoc(format, path, data, result.IsTruncated)
+	}
+
+	result.Content = string(data)
+	result.Lines = strings.Split(result.Content, "\n")
+
+	// Apply syntax highlighting using theme-configured style
+	highlighted, err := Highlight(result.Content, filepath.Ext(path), styles.GetSyntaxTheme())
+	if err == nil {
+		result.HighlightedLines = strings.Split(highlighted, "\n")
+	} else {
+		// Fallback to raw lines
+		result.HighlightedLines = result.Lines
+	}
+
+	// Limit lines
+	if len(result.Lines) > maxPreviewLines {
+		result.Lines = result.Lines[:maxPreviewLines]
+		result.HighlightedLines = result.HighlightedLines[:maxPreviewLines]
+		result.IsTruncated = true
+	}
+}
+
 // Highlight returns a syntax highlighted string.
 // Pattern from knipferrc/fm code/code.go
 func Highlight(content, extension, syntaxTheme string) (string, error) {
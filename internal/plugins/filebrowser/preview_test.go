@@ -123,8 +123,37 @@ func TestLoadPreview_LargeFile(t *testing.T) {
 
 	result := msg.(PreviewLoadedMsg)
 
-	if !result.Result.IsTruncated {
-		t.Error("expected truncated file")
+	// Large text files are streamed rather than truncated
+	if result.Result.StreamPath != testFile {
+		t.Errorf("expected streamed file, got StreamPath %q", result.Result.StreamPath)
+	}
+	if result.Result.IsTruncated || len(result.Result.Lines) != 0 {
+		t.Error("streamed files should not be loaded")
+	}
+
+	// Large CSV files are streamed too, with a table of their first rows
+	csvFile := filepath.Join(tmpDir, "large.csv")
+	csv := "id,name\n" + strings.Repeat("1,x\n", maxPreviewSize/4+10)
+	if err := os.WriteFile(csvFile, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = LoadPreview(tmpDir, "large.csv", 0)().(PreviewLoadedMsg)
+	doc := result.Result.Data
+	if result.Result.StreamPath != csvFile || doc == nil || doc.Table == nil || !doc.Truncated {
+		t.Fatalf("csv: stream=%q data=%+v", result.Result.StreamPath, doc)
+	}
+	if n := len(doc.Table.Rows); n != maxPreviewSize/4-2 {
+		t.Errorf("csv rows = %d, want the complete rows of the first %d bytes", n, maxPreviewSize)
+	}
+
+	// Large binary files still load their start for the hex view
+	binFile := filepath.Join(tmpDir, "large.bin")
+	if err := os.WriteFile(binFile, append([]byte{0}, largeContent...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = LoadPreview(tmpDir, "large.bin", 0)().(PreviewLoadedMsg)
+	if !result.Result.IsTruncated || !result.Result.IsBinary || len(result.Result.Bytes) != maxPreviewSize {
+		t.Errorf("binary: truncated=%v binary=%v bytes=%d", result.Result.IsTruncated, result.Result.IsBinary, len(result.Result.Bytes))
 	}
 }
 
@@ -0,0 +1,585 @@
+package filebrowser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+// replacer computes replacement text for the current project search. It
+// mirrors the ripgrep options in buildRipgrepArgs with a Go regexp so each
+// occurrence can be located and replaced.
+type replacer struct {
+	re        *regexp.Regexp
+	wholeWord bool
+	literal   bool // Insert the replacement as-is instead of expanding $1, ${name}
+	template  string
+}
+
+// replacerKey identifies the options a cached replacer was built from.
+type replacerKey struct {
+	query, replace                     string
+	useRegex, caseSensitive, wholeWord bool
+}
+
+// fileEdit is the full-content change made to one file by a replace.
+type fileEdit struct {
+	Path   string // Relative to the project root
+	Before []byte
+	After  []byte
+	Mode   os.FileMode
+}
+
+// ProjectReplaceMsg reports a finished project-wide replace, or its undo.
+type ProjectReplaceMsg struct {
+	Epoch uint64
+	Edits []fileEdit
+	Count int // Occurrences replaced
+	Undo  bool
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ProjectReplaceMsg) GetEpoch() uint64 { return m.Epoch }
+
+// newReplacer compiles the search options of state.
+func newReplacer(state *ProjectSearchState) (*replacer, error) {
+	pattern := state.Query
+	if !state.UseRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !state.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return &replacer{
+		re:        re,
+		wholeWord: state.WholeWord,
+		literal:   !state.UseRegex,
+		template:  state.Replace,
+	}, nil
+}
+
+// Replacer returns the replacer for the current options, reusing the last
+// one while they are unchanged.
+func (s *ProjectSearchState) Replacer() (*replacer, error) {
+	key := replacerKey{s.Query, s.Replace, s.UseRegex, s.CaseSensitive, s.WholeWord}
+	if s.replacer != nil && s.replacerKey == key {
+		return s.replacer, nil
+	}
+	r, err := newReplacer(s)
+	if err != nil {
+		return nil, err
+	}
+	s.replacer, s.replacerKey = r, key
+	return r, nil
+}
+
+// matches returns the submatch indices of every occurrence in line. With
+// whole word enabled, occurrences touching a word character are dropped,
+// as ripgrep's --word-regexp does.
+func (r *replacer) matches(line string) [][]int {
+	all := r.re.FindAllStringSubmatchIndex(line, -1)
+	if !r.wholeWord {
+		return all
+	}
+	kept := all[:0]
+	for _, m := range all {
+		if wordBounded(line, m[0], m[1]) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// wordBounded reports whether line[start:end] is not adjacent to a word
+// character.
+func wordBounded(line string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(line[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(line) {
+		if r, _ := utf8.DecodeRuneInString(line[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// replacement returns the text that replaces occurrence m of line.
+func (r *replacer) replacement(line string, m []int) string {
+	if r.literal {
+		return r.template
+	}
+	return string(r.re.ExpandString(nil, r.template, line, m))
+}
+
+// replaceLine replaces the occurrences in line whose start offset passes
+// keep (every occurrence when keep is nil) and returns the new line and the
+// number of occurrences replaced.
+func (r *replacer) replaceLine(line string, keep func(start int) bool) (string, int) {
+	var sb strings.Builder
+	last, n := 0, 0
+	for _, m := range r.matches(line) {
+		if keep != nil && !keep(m[0]) {
+			continue
+		}
+		sb.WriteString(line[last:m[0]])
+		sb.WriteString(r.replacement(line, m))
+		last = m[1]
+		n++
+	}
+	sb.WriteString(line[last:])
+	return sb.String(), n
+}
+
+// splitOccurrences turns ripgrep's one result per line into one match per
+// occurrence, so each can be included or excluded on its own. Lines the Go
+// pattern doesn't match are dropped since they can't be replaced.
+func splitOccurrences(results []SearchFileResult, r *replacer) []SearchFileResult {
+	out := make([]SearchFileResult, 0, len(results))
+	for _, f := range results {
+		file := SearchFileResult{Path: f.Path, Collapsed: f.Collapsed}
+		for _, m := range f.Matches {
+			for _, occ := range r.matches(m.LineText) {
+				file.Matches = append(file.Matches, SearchMatch{
+					LineNo:   m.LineNo,
+					LineText: m.LineText,
+					ColStart: occ[0],
+					ColEnd:   occ[1],
+				})
+			}
+		}
+		if len(file.Matches) > 0 {
+			out = append(out, file)
+		}
+	}
+	return out
+}
+
+// IncludedMatches returns how many matches, and in how many files, will be
+// replaced.
+func (s *ProjectSearchState) IncludedMatches() (matches, files int) {
+	for _, f := range s.Results {
+		n := f.IncludedCount()
+		matches += n
+		if n > 0 {
+			files++
+		}
+	}
+	return matches, files
+}
+
+// IncludedCount returns how many of the file's matches will be replaced.
+func (f SearchFileResult) IncludedCount() int {
+	n := 0
+	for _, m := range f.Matches {
+		if !m.Excluded {
+			n++
+		}
+	}
+	return n
+}
+
+// ToggleExcluded includes or excludes the match at the cursor. On a file
+// header, or when wholeFile is set, it applies to every match of the file:
+// all are excluded unless none are included yet.
+func (s *ProjectSearchState) ToggleExcluded(wholeFile bool) {
+	fileIdx, matchIdx, isFile := s.FlatItem(s.Cursor)
+	if fileIdx < 0 || fileIdx >= len(s.Results) {
+		return
+	}
+	file := &s.Results[fileIdx]
+	if isFile || wholeFile {
+		exclude := file.IncludedCount() > 0
+		for i := range file.Matches {
+			file.Matches[i].Excluded = exclude
+		}
+		return
+	}
+	file.Matches[matchIdx].Excluded = !file.Matches[matchIdx].Excluded
+	s.Cursor = s.NextMatchIndex()
+}
+
+// previewLine returns a matched line with the included occurrences on it
+// replaced.
+func (s *ProjectSearchState) previewLine(r *replacer, file SearchFileResult, lineNo int, text string) string {
+	included := make(map[int]bool)
+	for _, m := range file.Matches {
+		if m.LineNo == lineNo && !m.Excluded {
+			included[m.ColStart] = true
+		}
+	}
+	out, _ := r.replaceLine(text, func(start int) bool { return included[start] })
+	return out
+}
+
+// replaceTargets returns the included matches of each file.
+func (s *ProjectSearchState) replaceTargets() []SearchFileResult {
+	var targets []SearchFileResult
+	for _, f := range s.Results {
+		target := SearchFileResult{Path: f.Path}
+		for _, m := range f.Matches {
+			if !m.Excluded {
+				target.Matches = append(target.Matches, m)
+			}
+		}
+		if len(target.Matches) > 0 {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// replaceExclusions are the matches and files the user excluded from a
+// replace, for applying them to a new search.
+type replaceExclusions struct {
+	matches map[matchPos]bool
+	files   map[string]bool // Files with every listed match excluded
+}
+
+// matchPos identifies an occurrence by file, line and byte column.
+type matchPos struct {
+	path          string
+	lineNo, start int
+}
+
+// exclusions returns the user's exclusions in the listed results.
+func (s *ProjectSearchState) exclusions() replaceExclusions {
+	ex := replaceExclusions{matches: make(map[matchPos]bool), files: make(map[string]bool)}
+	for _, f := range s.Results {
+		if f.IncludedCount() == 0 {
+			ex.files[f.Path] = true
+			continue
+		}
+		for _, m := range f.Matches {
+			if m.Excluded {
+				ex.matches[matchPos{f.Path, m.LineNo, m.ColStart}] = true
+			}
+		}
+	}
+	return ex
+}
+
+// filter drops the excluded matches and files from results.
+func (ex replaceExclusions) filter(results []SearchFileResult) []SearchFileResult {
+	var targets []SearchFileResult
+	for _, f := range results {
+		if ex.files[f.Path] {
+			continue
+		}
+		target := SearchFileResult{Path: f.Path}
+		for _, m := range f.Matches {
+			if !ex.matches[matchPos{f.Path, m.LineNo, m.ColStart}] {
+				target.Matches = append(target.Matches, m)
+			}
+		}
+		if len(target.Matches) > 0 {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// planReplace reads each target file and computes its replaced contents.
+// It fails if a matched line no longer reads as it did in the search.
+func planReplace(workDir string, targets []SearchFileResult, r *replacer) ([]fileEdit, int, error) {
+	var edits []fileEdit
+	total := 0
+	for _, target := range targets {
+		fullPath := filepath.Join(workDir, target.Path)
+		if !isWithin(workDir, fullPath) {
+			return nil, 0, fmt.Errorf("%s is outside the project directory", target.Path)
+		}
+		info, err := os.Lstat(fullPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !info.Mode().IsRegular() {
+			return nil, 0, fmt.Errorf("%s is not a regular file", target.Path)
+		}
+		before, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		starts := make(map[int]map[int]bool)
+		for _, m := range target.Matches {
+			if starts[m.LineNo] == nil {
+				starts[m.LineNo] = make(map[int]bool)
+			}
+			starts[m.LineNo][m.ColStart] = true
+		}
+		lineNos := make([]int, 0, len(starts))
+		for lineNo := range starts {
+			lineNos = append(lineNos, lineNo)
+		}
+		sort.Ints(lineNos)
+
+		lines := strings.SplitAfter(string(before), "\n")
+		for _, lineNo := range lineNos {
+			idx := lineNo - 1
+			if idx < 0 || idx >= len(lines) {
+				return nil, 0, fmt.Errorf("%s has changed since the search", target.Path)
+			}
+			body, eol := splitLineEnding(lines[idx])
+			want := ""
+			for _, m := range target.Matches {
+				if m.LineNo == lineNo {
+					// Matched text of a CRLF line can keep its "\r"
+					want = strings.TrimSuffix(m.LineText, "\r")
+					break
+				}
+			}
+			if body != want {
+				return nil, 0, fmt.Errorf("%s has changed since the search", target.Path)
+			}
+			replaced, n := r.replaceLine(body, func(start int) bool { return starts[lineNo][start] })
+			lines[idx] = replaced + eol
+			total += n
+		}
+
+		after := []byte(strings.Join(lines, ""))
+		if !bytes.Equal(before, after) {
+			edits = append(edits, fileEdit{Path: target.Path, Before: before, After: after, Mode: info.Mode().Perm()})
+		}
+	}
+	return edits, total, nil
+}
+
+// splitLineEnding splits a line into its content and its "\n" or "\r\n".
+func splitLineEnding(line string) (string, string) {
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2], "\r\n"
+	}
+	if strings.HasSuffix(line, "\n") {
+		return line[:len(line)-1], "\n"
+	}
+	return line, ""
+}
+
+// writeFileEdits replaces the contents of every file, all or nothing. Each
+// file must still hold its Before contents. The new contents are written
+// to temporary files next to the originals and then renamed into place; if
+// a rename fails, the files already replaced are put back.
+func writeFileEdits(workDir string, edits []fileEdit) error {
+	temps := make([]string, 0, len(edits))
+	removeTemps := func(paths []string) {
+		for _, t := range paths {
+			_ = os.Remove(t)
+		}
+	}
+
+	for _, e := range edits {
+		fullPath := filepath.Join(workDir, e.Path)
+		current, err := os.ReadFile(fullPath)
+		if err != nil {
+			removeTemps(temps)
+			return err
+		}
+		if !bytes.Equal(current, e.Before) {
+			removeTemps(temps)
+			return fmt.Errorf("%s has changed on disk", e.Path)
+		}
+		tmp, err := writeTemp(fullPath, e.After, e.Mode)
+		if err != nil {
+			removeTemps(temps)
+			return err
+		}
+		temps = append(temps, tmp)
+	}
+
+	for i, e := range edits {
+		if err := os.Rename(temps[i], filepath.Join(workDir, e.Path)); err != nil {
+			for _, done := range edits[:i] {
+				_ = os.WriteFile(filepath.Join(workDir, done.Path), done.Before, done.Mode)
+			}
+			removeTemps(temps[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTemp writes data to a new temporary file in the directory of path.
+func writeTemp(path string, data []byte, mode os.FileMode) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".sidecar-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), mode)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// toggleProjectReplace shows or hides the replace field. Results are
+// searched again since replace mode lists each occurrence separately.
+func (p *Plugin) toggleProjectReplace() (plugin.Plugin, tea.Cmd) {
+	state := p.projectSearchState
+	if state == nil {
+		return p, nil
+	}
+	state.ReplaceMode = !state.ReplaceMode
+	state.ReplaceFocused = state.ReplaceMode
+	state.ConfirmReplace = false
+	if state.Query != "" {
+		state.IsSearching = true
+		state.DebounceVersion++ // Cancel any pending debounced search
+		return p, RunProjectSearch(p.ctx.WorkDir, state, p.ctx.Epoch)
+	}
+	return p, nil
+}
+
+// confirmProjectReplace asks before replacing the included matches.
+func (p *Plugin) confirmProjectReplace() tea.Cmd {
+	state := p.projectSearchState
+	if state == nil || state.IsSearching {
+		return nil
+	}
+	if _, err := state.Replacer(); err != nil {
+		return appmsg.ShowToast(err.Error(), 3*time.Second)
+	}
+	if n, _ := state.IncludedMatches(); n == 0 {
+		return appmsg.ShowToast("No matches selected", 2*time.Second)
+	}
+	state.ConfirmReplace = true
+	return nil
+}
+
+// applyProjectReplace replaces the included matches in the background.
+func (p *Plugin) applyProjectReplace() tea.Cmd {
+	state := p.projectSearchState
+	if state == nil {
+		return nil
+	}
+	state.ConfirmReplace = false
+	r, err := state.Replacer()
+	if err != nil {
+		return appmsg.ShowToast(err.Error(), 3*time.Second)
+	}
+	targets := state.replaceTargets()
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+
+	// The listed results stop at the search limits, so search again
+	// without them and replace every match the user didn't exclude
+	var args []string
+	var excluded replaceExclusions
+	if state.Truncated {
+		args = uncappedRipgrepArgs(state)
+		excluded = state.exclusions()
+	}
+	queryLen := len(state.Query)
+
+	return func() tea.Msg {
+		if args != nil {
+			results, err := searchAllMatches(workDir, args, queryLen)
+			if err != nil {
+				return ProjectReplaceMsg{Epoch: epoch, Err: err}
+			}
+			targets = excluded.filter(splitOccurrences(results, r))
+		}
+		edits, count, err := planReplace(workDir, targets, r)
+		if err == nil {
+			err = writeFileEdits(workDir, edits)
+		}
+		if err != nil {
+			return ProjectReplaceMsg{Epoch: epoch, Err: err}
+		}
+		return ProjectReplaceMsg{Epoch: epoch, Edits: edits, Count: count}
+	}
+}
+
+// undoReplace restores the files changed by a replace.
+func (p *Plugin) undoReplace(edits []fileEdit) tea.Cmd {
+	reverted := make([]fileEdit, len(edits))
+	for i, e := range edits {
+		reverted[i] = fileEdit{Path: e.Path, Before: e.After, After: e.Before, Mode: e.Mode}
+	}
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	return func() tea.Msg {
+		if err := writeFileEdits(workDir, reverted); err != nil {
+			return ProjectReplaceMsg{Epoch: epoch, Undo: true, Err: err}
+		}
+		return ProjectReplaceMsg{Epoch: epoch, Edits: reverted, Undo: true}
+	}
+}
+
+// handleProjectReplace reports a finished replace. A successful replace
+// closes project search and is added to the undo journal.
+func (p *Plugin) handleProjectReplace(msg ProjectReplaceMsg) tea.Cmd {
+	if msg.Err != nil {
+		if msg.Undo {
+			return appmsg.ShowToast("Undo failed: "+msg.Err.Error(), 3*time.Second)
+		}
+		return appmsg.ShowToast("Replace failed: "+msg.Err.Error(), 3*time.Second)
+	}
+
+	var cmds []tea.Cmd
+	if msg.Undo {
+		cmds = append(cmds, appmsg.ShowToast("Undid replace in "+pluralFiles(len(msg.Edits)), 2*time.Second))
+	} else {
+		p.recordReplaceUndo(msg.Edits)
+		p.projectSearchMode = false
+		p.projectSearchState = nil
+		p.clearProjectSearchModal()
+		text := fmt.Sprintf("Replaced %s in %s", pluralMatches(msg.Count), pluralFiles(len(msg.Edits)))
+		if len(msg.Edits) > 0 {
+			text += " (u to undo)"
+		}
+		cmds = append(cmds, appmsg.ShowToast(text, 3*time.Second))
+	}
+
+	// Reload the preview if it shows an edited file
+	for _, e := range msg.Edits {
+		if e.Path == p.previewFile {
+			cmds = append(cmds, LoadPreview(p.ctx.WorkDir, p.previewFile, p.ctx.Epoch))
+			break
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func pluralMatches(n int) string {
+	if n == 1 {
+		return "1 match"
+	}
+	return fmt.Sprintf("%d matches", n)
+}
+
+func pluralFiles(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
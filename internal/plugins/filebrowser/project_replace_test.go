@@ -0,0 +1,265 @@
+package filebrowser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReplacerReplaceLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		state ProjectSearchState
+		line  string
+		want  string
+	}{
+		{
+			name:  "literal ignores case and $",
+			state: ProjectSearchState{Query: "foo.bar", Replace: "$1x"},
+			line:  "Foo.Bar fooxbar foo.bar",
+			want:  "$1x fooxbar $1x",
+		},
+		{
+			name:  "regex capture groups",
+			state: ProjectSearchState{Query: `(\w+)Name`, Replace: "${1}ID", UseRegex: true, CaseSensitive: true},
+			line:  "userName := fileName",
+			want:  "userID := fileID",
+		},
+		{
+			name:  "whole word",
+			state: ProjectSearchState{Query: "id", Replace: "key", WholeWord: true},
+			line:  "id, idx, (id)",
+			want:  "key, idx, (key)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newReplacer(&tt.state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := r.replaceLine(tt.line, nil); got != tt.want {
+				t.Errorf("replaceLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newReplacer(&ProjectSearchState{Query: "(", UseRegex: true}); err == nil {
+		t.Error("invalid regex should fail")
+	}
+}
+
+func TestSplitOccurrences(t *testing.T) {
+	state := &ProjectSearchState{Query: "ab", ReplaceMode: true}
+	state.SetResults([]SearchFileResult{
+		{Path: "a.txt", Matches: []SearchMatch{{LineNo: 1, LineText: "ab AB ab"}}},
+	})
+	if len(state.Results) != 1 || len(state.Results[0].Matches) != 3 {
+		t.Fatalf("results = %+v", state.Results)
+	}
+	if m := state.Results[0].Matches[1]; m.ColStart != 3 || m.ColEnd != 5 {
+		t.Errorf("second occurrence = %d-%d, want 3-5", m.ColStart, m.ColEnd)
+	}
+}
+
+func TestProjectReplaceApplyAndUndo(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	files := map[string]string{
+		"a.go": "oldName := 1\r\nuse(oldName, oldName)\r\n",
+		"b.go": "oldName\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state := &ProjectSearchState{Query: "oldName", Replace: "newName", ReplaceMode: true}
+	state.SetResults([]SearchFileResult{
+		{Path: "a.go", Matches: []SearchMatch{
+			{LineNo: 1, LineText: "oldName := 1"},
+			{LineNo: 2, LineText: "use(oldName, oldName)"},
+		}},
+		{Path: "b.go", Matches: []SearchMatch{{LineNo: 1, LineText: "oldName"}}},
+	})
+	p.projectSearchMode = true
+	p.projectSearchState = state
+
+	// Leave out the second occurrence on line 2, and all of b.go
+	state.Cursor = 3
+	state.ToggleExcluded(false)
+	state.Cursor = 5
+	state.ToggleExcluded(false)
+	if n, files := state.IncludedMatches(); n != 2 || files != 1 {
+		t.Fatalf("IncludedMatches() = %d, %d", n, files)
+	}
+
+	msg := p.applyProjectReplace()().(ProjectReplaceMsg)
+	if msg.Err != nil || msg.Count != 2 {
+		t.Fatalf("replace = %+v", msg)
+	}
+	p.handleProjectReplace(msg)
+	if p.projectSearchMode || len(p.undoJournal) != 1 {
+		t.Errorf("search should close and journal the replace")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "a.go"))
+	if want := "newName := 1\r\nuse(newName, oldName)\r\n"; string(data) != want {
+		t.Errorf("a.go = %q, want %q", data, want)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, "a.go")); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "b.go")); string(data) != files["b.go"] {
+		t.Errorf("b.go changed: %q", data)
+	}
+
+	undo := p.undoLast()().(ProjectReplaceMsg)
+	if undo.Err != nil {
+		t.Fatal(undo.Err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.go")); string(data) != files["a.go"] {
+		t.Errorf("undo left a.go = %q", data)
+	}
+}
+
+func TestProjectReplaceCRLF(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	content := "oldName := 1\r\nuse(oldName)\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Results as read from ripgrep, and with the "\r" kept on the line
+	out := "a.go:1:1:oldName := 1\r\n"
+	results := parseRipgrepOutput(strings.NewReader(out), projectSearchMaxResults, len("oldName"))
+	results[0].Matches = append(results[0].Matches, SearchMatch{LineNo: 2, LineText: "use(oldName)\r"})
+
+	state := &ProjectSearchState{Query: "oldName", Replace: "newName", ReplaceMode: true}
+	state.SetResults(results)
+	p.projectSearchState = state
+
+	msg := p.applyProjectReplace()().(ProjectReplaceMsg)
+	if msg.Err != nil || msg.Count != 2 {
+		t.Fatalf("replace = %+v", msg)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.go")); string(data) != "newName := 1\r\nuse(newName)\r\n" {
+		t.Errorf("a.go = %q", data)
+	}
+}
+
+func TestProjectReplaceChangedFile(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "b.txt")
+	state := &ProjectSearchState{Query: "txt", Replace: "md", ReplaceMode: true}
+	state.SetResults([]SearchFileResult{
+		{Path: "a.txt", Matches: []SearchMatch{{LineNo: 1, LineText: "a.txt"}}},
+		{Path: "b.txt", Matches: []SearchMatch{{LineNo: 1, LineText: "stale.txt"}}},
+	})
+	p.projectSearchState = state
+
+	msg := p.applyProjectReplace()().(ProjectReplaceMsg)
+	if msg.Err == nil || !strings.Contains(msg.Err.Error(), "b.txt has changed") {
+		t.Fatalf("err = %v", msg.Err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "a.txt" {
+		t.Errorf("a.txt should be untouched, got %q", data)
+	}
+}
+
+func TestProjectReplaceTruncated(t *testing.T) {
+	full := SearchFileResult{Path: "a.go", Matches: make([]SearchMatch, projectSearchMaxPerFile)}
+	if !searchTruncated([]SearchFileResult{full}) {
+		t.Error("a file at the per-file limit should truncate the results")
+	}
+	if searchTruncated([]SearchFileResult{{Path: "b.go", Matches: make([]SearchMatch, 3)}}) {
+		t.Error("results under the limits are complete")
+	}
+
+	state := &ProjectSearchState{Query: "--max-count", ReplaceMode: true}
+	args := strings.Join(uncappedRipgrepArgs(state), " ")
+	if strings.Contains(args, "--max-filesize") || !strings.HasSuffix(args, "--fixed-strings -- --max-count") {
+		t.Errorf("uncapped args = %s", args)
+	}
+
+	// Exclusions carry over to a new search; a file with every listed
+	// match excluded is left out whole
+	state.Results = []SearchFileResult{
+		{Path: "a.go", Matches: []SearchMatch{{LineNo: 1, ColStart: 0, Excluded: true}, {LineNo: 2}}},
+		{Path: "b.go", Matches: []SearchMatch{{LineNo: 1, Excluded: true}}},
+	}
+	targets := state.exclusions().filter([]SearchFileResult{
+		{Path: "a.go", Matches: []SearchMatch{{LineNo: 1}, {LineNo: 2}, {LineNo: 300}}},
+		{Path: "b.go", Matches: []SearchMatch{{LineNo: 1}, {LineNo: 300}}},
+		{Path: "c.go", Matches: []SearchMatch{{LineNo: 5}}},
+	})
+	if len(targets) != 2 || len(targets[0].Matches) != 2 || targets[1].Path != "c.go" {
+		t.Errorf("targets = %+v", targets)
+	}
+}
+
+func TestProjectReplaceBeyondLimits(t *testing.T) {
+	if _, err := exec.LookPath("rg"); err != nil {
+		t.Skip("ripgrep not available")
+	}
+	p, dir := newBatchTestPlugin(t)
+	many := strings.Repeat("oldName\n", projectSearchMaxPerFile+50)
+	for name, content := range map[string]string{"a.go": many, "b.go": "oldName\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state := &ProjectSearchState{Query: "oldName", Replace: "newName", ReplaceMode: true}
+	msg := RunProjectSearch(dir, state, p.ctx.Epoch)().(ProjectSearchResultsMsg)
+	if msg.Error != nil || !msg.Truncated {
+		t.Fatalf("search = %+v", msg)
+	}
+	p.projectSearchMode = true
+	p.projectSearchState = state
+	p.Update(msg)
+
+	// Leave out the first line of a.go, and b.go
+	for idx := range state.FlatLen() {
+		fileIdx, matchIdx, isFile := state.FlatItem(idx)
+		path := state.Results[fileIdx].Path
+		if path == "a.go" && matchIdx == 0 || path == "b.go" && isFile {
+			state.Cursor = idx
+			state.ToggleExcluded(false)
+		}
+	}
+
+	replace := p.applyProjectReplace()().(ProjectReplaceMsg)
+	if replace.Err != nil || replace.Count != projectSearchMaxPerFile+49 {
+		t.Fatalf("replace = %+v", replace)
+	}
+	want := "oldName\n" + strings.Repeat("newName\n", projectSearchMaxPerFile+49)
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.go")); string(data) != want {
+		t.Errorf("a.go = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "b.go")); string(data) != "oldName\n" {
+		t.Errorf("b.go changed: %q", data)
+	}
+}
+
+func TestProjectReplaceKeys(t *testing.T) {
+	p, _ := newBatchTestPlugin(t)
+	p.width, p.height = 100, 40
+	p.projectSearchMode = true
+	p.projectSearchState = NewProjectSearchState()
+
+	p.handleProjectSearchKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	for _, r := range "ab" {
+		p.handleProjectSearchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	p.handleProjectSearchKey(tea.KeyMsg{Type: tea.KeyTab})
+	p.handleProjectSearchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	state := p.projectSearchState
+	if !state.ReplaceMode || state.Replace != "ab" || state.Query != "q" {
+		t.Errorf("replace=%q query=%q mode=%v", state.Replace, state.Query, state.ReplaceMode)
+	}
+}
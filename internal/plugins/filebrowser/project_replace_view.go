@@ -0,0 +1,199 @@
+package filebrowser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// projectReplacePreviewLines is the height of the replace diff preview.
+const projectReplacePreviewLines = 6
+
+// replacementAt returns the replacement for the occurrence of line that
+// starts at byte offset start.
+func (r *replacer) replacementAt(line string, start int) (string, bool) {
+	for _, m := range r.matches(line) {
+		if m[0] == start {
+			return r.replacement(line, m), true
+		}
+	}
+	return "", false
+}
+
+func (p *Plugin) projectReplacePreviewing() bool {
+	state := p.projectSearchState
+	return state != nil && state.ReplaceMode
+}
+
+// projectReplacePreviewSection shows the diff a replace would make: the
+// line under the cursor, or the first changed lines of the file when the
+// cursor is on a file header.
+func (p *Plugin) projectReplacePreviewSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		lines := p.renderReplacePreview(contentWidth)
+		// Pad so the modal doesn't jump in size
+		for len(lines) < projectReplacePreviewLines {
+			lines = append(lines, " ")
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+func (p *Plugin) renderReplacePreview(width int) []string {
+	state := p.projectSearchState
+	if state == nil || state.IsSearching || len(state.Results) == 0 {
+		return nil
+	}
+	r, err := state.Replacer()
+	if err != nil {
+		return []string{styles.StatusDeleted.Render(ansi.Truncate(err.Error(), width, "…"))}
+	}
+	fileIdx, matchIdx, isFile := state.FlatItem(state.Cursor)
+	if fileIdx < 0 || fileIdx >= len(state.Results) {
+		return nil
+	}
+	file := state.Results[fileIdx]
+	matches := file.Matches
+	if !isFile && matchIdx >= 0 && matchIdx < len(file.Matches) {
+		matches = file.Matches[matchIdx : matchIdx+1]
+	}
+
+	// Collect each changed line once, in file order
+	type change struct {
+		lineNo      int
+		before, now string
+	}
+	var changes []change
+	seen := make(map[int]bool)
+	for _, m := range matches {
+		if seen[m.LineNo] {
+			continue
+		}
+		seen[m.LineNo] = true
+		if after := state.previewLine(r, file, m.LineNo, m.LineText); after != m.LineText {
+			changes = append(changes, change{m.LineNo, m.LineText, after})
+		}
+	}
+
+	lines := []string{styles.Muted.Render(ui.TruncateStart(file.Path, width))}
+	if len(changes) == 0 {
+		return append(lines, styles.Muted.Render("No changes (excluded)"))
+	}
+	maxPairs := (projectReplacePreviewLines - 2) / 2
+	for i, c := range changes {
+		if i == maxPairs {
+			lines = append(lines, styles.Muted.Render(fmt.Sprintf("... %d more changed lines", len(changes)-i)))
+			break
+		}
+		lines = append(lines,
+			renderReplaceDiffLine("-", c.lineNo, c.before, c.now, styles.DiffRemove, width),
+			renderReplaceDiffLine("+", c.lineNo, c.now, c.before, styles.DiffAdd, width))
+	}
+	return lines
+}
+
+// renderReplaceDiffLine renders one side of a changed line, scrolled so the
+// first difference from other is visible.
+func renderReplaceDiffLine(sign string, lineNo int, text, other string, style lipgloss.Style, width int) string {
+	prefix := fmt.Sprintf("%s%5d  ", sign, lineNo)
+	text = strings.TrimLeft(text, " \t")
+	other = strings.TrimLeft(other, " \t")
+
+	diff := 0
+	for diff < len(text) && diff < len(other) && text[diff] == other[diff] {
+		diff++
+	}
+	pos := utf8.RuneCountInString(text[:diff])
+	text, _, _ = ui.TruncateMid(text, max(width-len(prefix), 10), pos, pos)
+	return style.Render(prefix + text)
+}
+
+// renderReplaceMatchLine renders one occurrence with its replacement
+// inline: the matched text struck through, followed by the new text.
+// Excluded occurrences are dimmed and show no replacement.
+func (p *Plugin) renderReplaceMatchLine(match SearchMatch, selected bool, width int) string {
+	indent := "    "
+	if match.Excluded {
+		indent = "  x "
+	}
+	lineNum := fmt.Sprintf("%4d: ", match.LineNo)
+
+	text := match.LineText
+	start := min(max(match.ColStart, 0), len(text))
+	end := min(max(match.ColEnd, start), len(text))
+	replacement := ""
+	if !match.Excluded {
+		if r, err := p.projectSearchState.Replacer(); err == nil {
+			replacement, _ = r.replacementAt(text, start)
+		}
+	}
+
+	// Drop leading indentation, as the search results do
+	trim := min(len(text)-len(strings.TrimLeft(text, " \t")), start)
+	display := text[trim:start] + text[start:end] + replacement + text[end:]
+	oldStart := utf8.RuneCountInString(text[trim:start])
+	oldLen := utf8.RuneCountInString(text[start:end])
+	newEnd := oldStart + oldLen + utf8.RuneCountInString(replacement)
+
+	available := max(width-len(indent)-len(lineNum)-2, 10)
+	display, hlStart, hlEnd := ui.TruncateMid(display, available, oldStart, newEnd)
+	runes := []rune(display)
+	hlStart = min(hlStart, len(runes))
+	hlEnd = min(max(hlEnd, hlStart), len(runes))
+	if oldStart == newEnd {
+		hlEnd = hlStart // Empty match with empty replacement
+	}
+	oldEnd := min(hlStart+oldLen, hlEnd)
+
+	before := string(runes[:hlStart])
+	oldText := string(runes[hlStart:oldEnd])
+	newText := string(runes[oldEnd:hlEnd])
+	after := string(runes[hlEnd:])
+
+	if match.Excluded {
+		line := indent + lineNum + display
+		if selected {
+			return styles.ListItemSelected.Render(line + strings.Repeat(" ", max(width-ansi.StringWidth(line), 0)))
+		}
+		return styles.Muted.Render(line)
+	}
+
+	removed := styles.DiffRemove.Strikethrough(true)
+	if selected {
+		plain := indent + lineNum + display
+		pad := strings.Repeat(" ", max(width-ansi.StringWidth(plain), 0))
+		return styles.ListItemSelected.Render(indent+lineNum+before) +
+			removed.Render(oldText) +
+			styles.DiffAdd.Render(newText) +
+			styles.ListItemSelected.Render(after+pad)
+	}
+	return indent +
+		styles.FileBrowserLineNumber.Render(lineNum) +
+		before + removed.Render(oldText) + styles.DiffAdd.Render(newText) + after
+}
+
+// renderProjectReplaceStats renders the stats line in replace mode: how many
+// matches will be replaced, or the pending confirmation.
+func (p *Plugin) renderProjectReplaceStats(position string, width int) string {
+	state := p.projectSearchState
+	matches, files := state.IncludedMatches()
+	if state.ConfirmReplace {
+		if state.Truncated {
+			// More matches exist than are listed; all but the excluded are replaced
+			return styles.StatusModified.Render(ansi.Truncate("Replace all matches in the project, not only the listed ones? y/n", width, "…"))
+		}
+		return styles.StatusModified.Render(fmt.Sprintf("Replace %s in %s? y/n", pluralMatches(matches), pluralFiles(files)))
+	}
+	stats := fmt.Sprintf("%s%d of %d matches in %s", position, matches, state.TotalMatches(), pluralFiles(files))
+	if state.Truncated {
+		stats += " (more not listed)"
+	}
+	hints := "  alt+x exclude  alt+a replace  tab switch field"
+	return styles.Muted.Render(ansi.Truncate(stats+hints, width, "…"))
+}
@@ -2,7 +2,11 @@ package filebrowser
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -13,6 +17,7 @@ import (
 
 const (
 	projectSearchMaxResults  = 1000              // Max total matches to display
+	projectSearchMaxPerFile  = 100               // Max matching lines per file
 	projectSearchTimeout     = 30 * time.Second  // Max time for search
 	projectSearchDebounce    = 200 * time.Millisecond // Debounce delay before searching
 )
@@ -27,6 +32,15 @@ type ProjectSearchState struct {
 	CaseSensitive bool
 	WholeWord     bool
 
+	// Replace (ctrl+r shows the replace field)
+	Replace        string
+	ReplaceMode    bool
+	ReplaceFocused bool // Typing edits Replace instead of Query
+	ConfirmReplace bool // Waiting for y/n before replacing
+	Truncated      bool // Results stopped at a limit; replace searches again
+	replacer       *replacer
+	replacerKey    replacerKey
+
 	// UI state
 	Cursor       int  // Index in flattened results (files + matches)
 	ScrollOffset int  // For scrolling
@@ -59,13 +73,31 @@ type SearchMatch struct {
 	LineText  string // Full line content
 	ColStart  int    // Match start column (0-indexed)
 	ColEnd    int    // Match end column (0-indexed)
+	Excluded  bool   // Left out of a replace
+}
+
+// SetResults stores search results. In replace mode each occurrence
+// becomes its own match.
+func (s *ProjectSearchState) SetResults(results []SearchFileResult) {
+	s.Results = results
+	if !s.ReplaceMode || len(results) == 0 {
+		return
+	}
+	r, err := s.Replacer()
+	if err != nil {
+		s.Error = err.Error()
+		s.Results = nil
+		return
+	}
+	s.Results = splitOccurrences(results, r)
 }
 
 // ProjectSearchResultsMsg contains results from a search.
 type ProjectSearchResultsMsg struct {
-	Epoch   uint64 // Epoch when request was issued (for stale detection)
-	Results []SearchFileResult
-	Error   error
+	Epoch     uint64 // Epoch when request was issued (for stale detection)
+	Results   []SearchFileResult
+	Truncated bool // Stopped at projectSearchMaxResults or projectSearchMaxPerFile
+	Error     error
 }
 
 // GetEpoch implements plugin.EpochMessage.
@@ -275,8 +307,63 @@ func RunProjectSearch(workDir string, state *ProjectSearchState, epoch uint64) t
 		_ = cmd.Process.Kill()
 		_ = cmd.Wait()
 
-		return ProjectSearchResultsMsg{Epoch: epoch, Results: results}
+		return ProjectSearchResultsMsg{Epoch: epoch, Results: results, Truncated: searchTruncated(results)}
+	}
+}
+
+// searchTruncated reports whether results may leave out matches because a
+// limit was reached.
+func searchTruncated(results []SearchFileResult) bool {
+	total := 0
+	for _, f := range results {
+		if len(f.Matches) >= projectSearchMaxPerFile {
+			return true
+		}
+		total += len(f.Matches)
+	}
+	return total >= projectSearchMaxResults
+}
+
+// searchAllMatches runs ripgrep with args from uncappedRipgrepArgs and
+// returns every match, for replacing beyond the listed results.
+func searchAllMatches(workDir string, args []string, queryLen int) ([]SearchFileResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), projectSearchTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "rg", args...)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("search timed out after %s", projectSearchTimeout)
 	}
+	if err != nil {
+		// Exit status 1 means nothing matched
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, &ripgrepNotFoundError{}
+		}
+		return nil, err
+	}
+	return parseRipgrepOutput(bytes.NewReader(out), math.MaxInt, queryLen), nil
+}
+
+// uncappedRipgrepArgs returns buildRipgrepArgs without its --max-* limits.
+func uncappedRipgrepArgs(state *ProjectSearchState) []string {
+	var args []string
+	options := true
+	for _, arg := range buildRipgrepArgs(state) {
+		if options && strings.HasPrefix(arg, "--max-") {
+			continue
+		}
+		if arg == "--" {
+			options = false // The query follows
+		}
+		args = append(args, arg)
+	}
+	return args
 }
 
 // buildRipgrepArgs constructs the ripgrep command arguments.
@@ -286,8 +373,12 @@ func buildRipgrepArgs(state *ProjectSearchState) []string {
 		"--column",         // Include column numbers for match position
 		"--no-heading",     // Don't group by file (simpler parsing)
 		"--with-filename",  // Always include filename
-		"--max-count=100",  // Limit matches per file
-		"--max-filesize=1M", // Skip very large files
+		"--max-count=" + strconv.Itoa(projectSearchMaxPerFile), // Limit matches per file
+	}
+
+	// Skip very large files, except when replacing, which must reach every file
+	if !state.ReplaceMode {
+		args = append(args, "--max-filesize=1M")
 	}
 
 	if !state.CaseSensitive {
@@ -309,16 +400,20 @@ func buildRipgrepArgs(state *ProjectSearchState) []string {
 
 // parseRipgrepOutput reads ripgrep line output (filename:line:col:content) and builds results.
 func parseRipgrepOutput(reader interface{ Read([]byte) (int, error) }, maxMatches int, queryLen int) []SearchFileResult {
-	scanner := bufio.NewScanner(reader)
-	// Increase buffer size for long lines
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	// A bufio.Reader rather than a Scanner: lines have no length limit, so
+	// a minified or generated file can't end the results early
+	br := bufio.NewReader(reader)
 
 	fileMap := make(map[string]*SearchFileResult)
 	var fileOrder []string
 	totalMatches := 0
 
-	for scanner.Scan() && totalMatches < maxMatches {
-		line := scanner.Text()
+	for totalMatches < maxMatches {
+		line, err := br.ReadString('\n')
+		if err != nil && line == "" {
+			break
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
 		if len(line) == 0 {
 			continue
 		}
@@ -1,6 +1,7 @@
 package filebrowser
 
 import (
+	"math"
 	"strings"
 	"testing"
 )
@@ -305,6 +306,24 @@ func TestParseRipgrepOutput_MaxMatches(t *testing.T) {
 	}
 }
 
+func TestParseRipgrepOutput_LongLine(t *testing.T) {
+	// A minified file: one match on a line over 1MB, then more matches
+	long := "min.js:1:5:var x" + strings.Repeat("a", 2*1024*1024)
+	out := long + "\nmin.js:2:1:x = 1\r\nother.go:3:2: x\n"
+
+	results := parseRipgrepOutput(strings.NewReader(out), math.MaxInt, 1)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(results))
+	}
+	if got := results[0].Matches; len(got) != 2 || len(got[0].LineText) != len(long)-len("min.js:1:5:") || got[1].LineText != "x = 1" {
+		t.Errorf("min.js matches = %d, want the long line and line 2", len(got))
+	}
+	if results[1].Path != "other.go" || results[1].Matches[0].LineNo != 3 {
+		t.Errorf("second file = %+v", results[1])
+	}
+}
+
 func TestProjectSearchState_FirstMatchIndex(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -95,6 +95,8 @@ func (p *Plugin) ensureProjectSearchModal() {
 		AddSection(p.projectSearchOptionsSection()).
 		AddSection(modal.Spacer()).
 		AddSection(p.projectSearchResultsSection()).
+		AddSection(modal.When(p.projectReplacePreviewing, modal.Spacer())).
+		AddSection(modal.When(p.projectReplacePreviewing, p.projectReplacePreviewSection())).
 		AddSection(modal.When(p.projectSearchHasResults, modal.Spacer())).
 		AddSection(modal.When(p.projectSearchHasResults, p.projectSearchStatsSection()))
 }
@@ -339,6 +341,9 @@ func (p *Plugin) projectSearchStatsSection() modal.Section {
 		if flatLen > 0 {
 			position = fmt.Sprintf("%d/%d  ", state.Cursor+1, flatLen)
 		}
+		if state.ReplaceMode {
+			return modal.RenderedSection{Content: p.renderProjectReplaceStats(position, contentWidth)}
+		}
 		stats := fmt.Sprintf("%d matches in %d files", state.TotalMatches(), state.FileCount())
 
 		return modal.RenderedSection{Content: styles.Muted.Render(position + stats)}
@@ -353,6 +358,10 @@ func (p *Plugin) projectSearchMaxVisible() int {
 	if height > 30 {
 		height = 30
 	}
+	if p.projectSearchState != nil && p.projectSearchState.ReplaceMode {
+		// Leave room for the replace field and the diff preview
+		height = max(height-projectReplacePreviewLines-2, 3)
+	}
 	return height
 }
 
@@ -362,6 +371,9 @@ func (p *Plugin) renderProjectSearchHeader(width int) string {
 	cursor := "█"
 
 	prefix := "Search: "
+	if state.ReplaceMode {
+		prefix = "Search:  "
+	}
 	available := width - len(prefix) - 1
 	if available < 0 {
 		available = 0
@@ -372,8 +384,20 @@ func (p *Plugin) renderProjectSearchHeader(width int) string {
 		query = ui.TruncateStart(query, available)
 	}
 
-	header := fmt.Sprintf("%s%s%s", prefix, query, cursor)
-	return styles.ModalTitle.Render(header)
+	if !state.ReplaceMode {
+		header := fmt.Sprintf("%s%s%s", prefix, query, cursor)
+		return styles.ModalTitle.Render(header)
+	}
+
+	replace := state.Replace
+	if len(replace) > available {
+		replace = ui.TruncateStart(replace, available)
+	}
+	searchCursor, replaceCursor := cursor, ""
+	if state.ReplaceFocused {
+		searchCursor, replaceCursor = "", cursor
+	}
+	return styles.ModalTitle.Render(prefix + query + searchCursor + "\n" + "Replace: " + replace + replaceCursor)
 }
 
 // renderSearchFileHeader renders a file header line.
@@ -384,6 +408,12 @@ func (p *Plugin) renderSearchFileHeader(file SearchFileResult, fileIdx int, sele
 	}
 
 	matchCount := fmt.Sprintf(" (%d)", len(file.Matches))
+	excluded := false
+	if p.projectSearchState != nil && p.projectSearchState.ReplaceMode {
+		included := file.IncludedCount()
+		matchCount = fmt.Sprintf(" (%d/%d)", included, len(file.Matches))
+		excluded = included == 0
+	}
 	availableWidth := width - len(icon) - len(matchCount) - 2
 
 	path := file.Path
@@ -401,6 +431,9 @@ func (p *Plugin) renderSearchFileHeader(file SearchFileResult, fileIdx int, sele
 		return styles.ListItemSelected.Render(plainLine)
 	}
 
+	if excluded {
+		return styles.Muted.Render(icon + path + matchCount)
+	}
 	return fmt.Sprintf("%s%s%s",
 		styles.FileBrowserIcon.Render(icon),
 		styles.FileBrowserDir.Render(path),
@@ -410,6 +443,10 @@ func (p *Plugin) renderSearchFileHeader(file SearchFileResult, fileIdx int, sele
 
 // renderSearchMatchLine renders a single match line.
 func (p *Plugin) renderSearchMatchLine(match SearchMatch, matchIdx int, selected, hovered bool, width int) string {
+	if p.projectSearchState != nil && p.projectSearchState.ReplaceMode {
+		return p.renderReplaceMatchLine(match, selected || hovered, width)
+	}
+
 	indent := "    "
 	lineNum := fmt.Sprintf("%4d: ", match.LineNo)
 
@@ -0,0 +1,303 @@
+package filebrowser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	streamLineStride  = 1024     // Lines between line index entries
+	streamIndexChunk  = 32 << 20 // Bytes indexed per step
+	streamSearchChunk = 16 << 20 // Bytes searched per step
+	streamMaxLineLen  = 4096     // Bytes of a line kept for display
+)
+
+// lineIndex maps line numbers of a large file to byte offsets. Only every
+// streamLineStride-th line is recorded, so the index stays small; other
+// lines are found by reading forward from the nearest entry.
+type lineIndex struct {
+	offsets  []int64 // offsets[i] is the start of line i*streamLineStride
+	newlines int     // Newlines seen
+	size     int64   // Bytes indexed
+	tail     int64   // Start of the last line
+}
+
+// Lines returns the number of lines indexed, counting a last line without
+// a newline.
+func (idx lineIndex) Lines() int {
+	if idx.size > idx.tail {
+		return idx.newlines + 1
+	}
+	return idx.newlines
+}
+
+// extend indexes up to limit more bytes of the file, stopping at the end.
+// The returned index does not share memory with idx.
+func (idx lineIndex) extend(fullPath string, limit int64) (lineIndex, error) {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return idx, err
+	}
+	defer func() { _ = f.Close() }()
+
+	idx.offsets = slices.Clip(idx.offsets)
+	if len(idx.offsets) == 0 {
+		idx.offsets = []int64{0}
+	}
+	buf := make([]byte, 256*1024)
+	for read := int64(0); read < limit; {
+		n, err := f.ReadAt(buf[:min(int64(len(buf)), limit-read)], idx.size)
+		chunk := buf[:n]
+		for i := 0; ; {
+			j := bytes.IndexByte(chunk[i:], '\n')
+			if j < 0 {
+				break
+			}
+			i += j + 1
+			idx.newlines++
+			idx.tail = idx.size + int64(i)
+			if idx.newlines%streamLineStride == 0 {
+				idx.offsets = append(idx.offsets, idx.tail)
+			}
+		}
+		idx.size += int64(n)
+		read += int64(n)
+		if errors.Is(err, io.EOF) || n == 0 {
+			break
+		}
+		if err != nil {
+			return idx, err
+		}
+	}
+	return idx, nil
+}
+
+// readStreamLines reads count lines starting at line start, cleaned up for
+// display.
+func readStreamLines(fullPath string, idx lineIndex, start, count int) ([]string, error) {
+	count = min(count, idx.Lines()-start)
+	if start < 0 || count <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	block := start / streamLineStride
+	if _, err := f.Seek(idx.offsets[block], io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReaderSize(f, 64*1024)
+	lines := make([]string, 0, count)
+	for line := block * streamLineStride; len(lines) < count; line++ {
+		text, err := readStreamLine(r)
+		if line >= start {
+			lines = append(lines, text)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// readStreamLine reads one line, keeping at most streamMaxLineLen bytes of
+// it, and strips terminal escapes and control characters.
+func readStreamLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		part, err := r.ReadSlice('\n')
+		if room := streamMaxLineLen - len(line); room > 0 {
+			line = append(line, part[:min(len(part), room)]...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		text := strings.TrimRight(string(line), "\r\n")
+		text = ansi.Strip(strings.ToValidUTF8(text, "�"))
+		text = strings.Map(func(r rune) rune {
+			if (r < 0x20 && r != '\t') || r == 0x7f {
+				return -1
+			}
+			return r
+		}, text)
+		return text, err
+	}
+}
+
+// lineAt returns the line containing byte offset off.
+func lineAt(fullPath string, idx lineIndex, off int64) (int, error) {
+	block := sort.Search(len(idx.offsets), func(i int) bool { return idx.offsets[i] > off }) - 1
+	block = max(block, 0)
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	line := block * streamLineStride
+	r := io.NewSectionReader(f, idx.offsets[block], off-idx.offsets[block])
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		line += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return line, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// lineOffset returns the byte offset where line starts.
+func lineOffset(fullPath string, idx lineIndex, line int) (int64, error) {
+	block := min(line/streamLineStride, len(idx.offsets)-1)
+	off := idx.offsets[block]
+	skip := line - block*streamLineStride
+	if skip == 0 {
+		return off, nil
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReaderSize(io.NewSectionReader(f, off, idx.size-off), 64*1024)
+	for ; skip > 0; skip-- {
+		part, err := r.ReadSlice('\n')
+		off += int64(len(part))
+		for err == bufio.ErrBufferFull {
+			part, err = r.ReadSlice('\n')
+			off += int64(len(part))
+		}
+		if err != nil {
+			break
+		}
+	}
+	return off, nil
+}
+
+// foldReader lowercases ASCII letters as they are read, for
+// case-insensitive byte searches.
+type foldReader struct{ r io.ReaderAt }
+
+func (f foldReader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := f.r.ReadAt(p, off)
+	foldASCII(p[:n])
+	return n, err
+}
+
+// foldASCII lowercases ASCII letters in place.
+func foldASCII(b []byte) []byte {
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return b
+}
+
+// streamSearch is an incremental case-insensitive search of a large file.
+// A forward search scans from origin to the end and then wraps to scan
+// the start; a backward search finds matches before origin first, then
+// wraps to the end.
+type streamSearch struct {
+	pattern  []byte // Query folded by foldASCII
+	backward bool
+	origin   int64
+	size     int64 // Bytes searched, the indexed size when the search began
+	pos      int64 // Next offset to scan from, or back to when backward
+	wrapped  bool
+	done     bool
+}
+
+// newStreamSearch starts a search for pattern around origin.
+func newStreamSearch(pattern []byte, backward bool, origin, size int64) streamSearch {
+	s := streamSearch{pattern: pattern, backward: backward, origin: min(origin, size), size: size}
+	s.pos = s.origin
+	if backward {
+		s.pos = min(size, s.origin+int64(len(pattern))-1)
+	}
+	return s
+}
+
+// span returns the byte range of the current leg of the search.
+func (s streamSearch) span() (lo, hi int64) {
+	if s.backward == s.wrapped {
+		return s.origin, s.size
+	}
+	return 0, min(s.size, s.origin+int64(len(s.pattern))-1)
+}
+
+// step scans up to streamSearchChunk bytes and returns the offset of a
+// match, or -1 and the search to continue with.
+func (s streamSearch) step(fullPath string) (int64, streamSearch, error) {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return -1, s, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := foldReader{f}
+	n := int64(len(s.pattern))
+	lo, hi := s.span()
+	var off int64
+	var legDone bool
+	if s.backward {
+		from := max(s.pos-streamSearchChunk, lo)
+		off, err = scanBackward(r, from, s.pos, s.pattern)
+		s.pos = from + n - 1
+		legDone = from == lo
+	} else {
+		to := min(s.pos+streamSearchChunk+n-1, hi)
+		off, err = scanForward(r, s.pos, to, s.pattern)
+		s.pos += streamSearchChunk
+		legDone = to == hi
+	}
+	if err != nil || off >= 0 || !legDone {
+		return off, s, err
+	}
+	switch {
+	case s.wrapped:
+		s.done = true
+	case s.backward:
+		s.wrapped, s.pos = true, s.size
+	default:
+		s.wrapped, s.pos = true, 0
+	}
+	return -1, s, nil
+}
+
+// progress returns the percentage of the file searched.
+func (s streamSearch) progress() int {
+	if s.size == 0 {
+		return 100
+	}
+	var scanned int64
+	switch {
+	case s.backward && !s.wrapped:
+		scanned = s.origin - s.pos
+	case s.backward:
+		scanned = s.origin + s.size - s.pos
+	case !s.wrapped:
+		scanned = s.pos - s.origin
+	default:
+		scanned = s.size - s.origin + s.pos
+	}
+	return int(min(max(scanned, 0)*100/s.size, 100))
+}
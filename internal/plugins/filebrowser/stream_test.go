@@ -0,0 +1,278 @@
+package filebrowser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeNumberedLines writes "line N" for lines 1 to n.
+func writeNumberedLines(t *testing.T, path string, n int) {
+	t.Helper()
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runStreamCmds runs a command and everything it leads to, the way the
+// program loop would.
+func runStreamCmds(p *Plugin, cmd tea.Cmd) {
+	queue := []tea.Cmd{cmd}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == nil {
+			continue
+		}
+		msg := c()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			queue = append(queue, batch...)
+			continue
+		}
+		_, next := p.Update(msg)
+		queue = append(queue, next)
+	}
+}
+
+func TestLineIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.log")
+	writeNumberedLines(t, path, 3*streamLineStride+10)
+
+	// Index in small steps, as if the file were huge
+	var idx lineIndex
+	info, _ := os.Stat(path)
+	for idx.size < info.Size() {
+		next, err := idx.extend(path, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		idx = next
+	}
+	if idx.Lines() != 3*streamLineStride+10 || len(idx.offsets) != 4 {
+		t.Fatalf("lines=%d offsets=%d", idx.Lines(), len(idx.offsets))
+	}
+
+	lines, err := readStreamLines(path, idx, 2*streamLineStride-1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("line %d,line %d,line %d", 2*streamLineStride, 2*streamLineStride+1, 2*streamLineStride+2)
+	if strings.Join(lines, ",") != want {
+		t.Errorf("lines = %q", lines)
+	}
+	if lines, _ := readStreamLines(path, idx, idx.Lines()-1, 5); len(lines) != 1 {
+		t.Errorf("reading past the end = %q", lines)
+	}
+
+	off, err := lineOffset(path, idx, streamLineStride+5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line, _ := lineAt(path, idx, off+2); line != streamLineStride+5 {
+		t.Errorf("lineAt(lineOffset) = %d", line)
+	}
+
+	// A last line without a newline counts
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("partial")
+	_ = f.Close()
+	idx, _ = idx.extend(path, 1<<20)
+	if idx.Lines() != 3*streamLineStride+11 {
+		t.Errorf("lines with partial = %d", idx.Lines())
+	}
+}
+
+func TestReadStreamLineCleansUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raw.log")
+	long := strings.Repeat("y", streamMaxLineLen*3)
+	content := "\x1b[31mred\x1b[0m\r\n" + long + "\n" + "bell\x07\tend\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := lineIndex{}.extend(path, 1<<20)
+	lines, err := readStreamLines(path, idx, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 3 || lines[0] != "red" || len(lines[1]) != streamMaxLineLen || lines[2] != "bell\tend" {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestStreamSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.log")
+	data := "ERROR one\nok\nerror two\nok\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	find := func(origin int64, backward bool) int64 {
+		s := newStreamSearch(foldASCII([]byte("Error")), backward, origin, int64(len(data)))
+		for !s.done {
+			off, next, err := s.step(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if off >= 0 {
+				return off
+			}
+			s = next
+		}
+		return -1
+	}
+	second := int64(strings.Index(data, "error"))
+	tests := []struct {
+		origin   int64
+		backward bool
+		want     int64
+	}{
+		{0, false, 0},
+		{1, false, second},
+		{second + 1, false, 0},
+		{second, true, 0},
+		{0, true, second},
+	}
+	for _, tt := range tests {
+		if got := find(tt.origin, tt.backward); got != tt.want {
+			t.Errorf("from %d backward=%v = %d, want %d", tt.origin, tt.backward, got, tt.want)
+		}
+	}
+}
+
+func TestLargeCSVTableAndStream(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	var sb strings.Builder
+	sb.WriteString("id,name\n")
+	for i := 1; sb.Len() <= maxPreviewSize; i++ {
+		fmt.Fprintf(&sb, "%d,row %d\n", i, i)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "big.csv"), []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.previewFile = "big.csv"
+	p.activePane = PanePreview
+	p.width, p.height = 100, 30
+	runStreamCmds(p, LoadPreview(dir, "big.csv", p.ctx.Epoch))
+	if !p.dataViewActive() || p.streamViewActive() {
+		t.Fatalf("data=%v stream=%v, want the table first", p.dataViewActive(), p.streamViewActive())
+	}
+	if info := p.dataView.tableInfo(); !strings.Contains(info, "first "+formatSize(maxPreviewSize)) {
+		t.Errorf("table info = %q", info)
+	}
+
+	// m switches to the stream of the whole file and back
+	p.handlePreviewKey("m")
+	if !p.streamViewActive() || p.FocusContext() != "file-browser-stream" {
+		t.Fatalf("raw: stream=%v context=%s", p.streamViewActive(), p.FocusContext())
+	}
+	if out := p.renderStreamView(80, 10); !strings.Contains(out, "id,name") {
+		t.Errorf("stream = %q", out)
+	}
+	p.handlePreviewKey("m")
+	if !p.dataViewActive() || p.streamViewActive() {
+		t.Error("m should return to the table")
+	}
+}
+
+func TestStreamView(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	path := filepath.Join(dir, "agent.log")
+	n := maxPreviewSize / 8
+	writeNumberedLines(t, path, n)
+	p.previewFile = "agent.log"
+	p.activePane = PanePreview
+	p.height = 30
+	runStreamCmds(p, LoadPreview(dir, "agent.log", p.ctx.Epoch))
+	v := p.streamView
+	if !p.streamViewActive() || p.FocusContext() != "file-browser-stream" {
+		t.Fatalf("stream view active=%v context=%s", p.streamViewActive(), p.FocusContext())
+	}
+	if v.index.Lines() != n || len(v.lines) == 0 {
+		t.Fatalf("lines=%d window=%d", v.index.Lines(), len(v.lines))
+	}
+	if out := p.renderStreamView(80, 10); !strings.Contains(out, " 1 line 1") || !strings.Contains(out, fmt.Sprintf("of %d", n)) {
+		t.Errorf("render = %q", out)
+	}
+
+	// G reads the window at the end
+	cmd, _ := p.handleStreamKey("G")
+	runStreamCmds(p, cmd)
+	if p.previewScroll != p.maxStreamTop() || v.lines[len(v.lines)-1] != fmt.Sprintf("line %d", n) {
+		t.Errorf("G scroll=%d last=%q", p.previewScroll, v.lines[len(v.lines)-1])
+	}
+
+	// Search wraps from the end to the start
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if p.FocusContext() != "file-browser-stream-input" || !p.ConsumesTextInput() {
+		t.Fatalf("prompt context = %s", p.FocusContext())
+	}
+	for _, r := range "LINE 42\n" {
+		if r == '\n' {
+			_, cmd = p.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+		} else {
+			p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	runStreamCmds(p, cmd)
+	if v.match != 41 || p.previewScroll > 41 || v.search != nil {
+		t.Fatalf("match=%d scroll=%d err=%q", v.match, p.previewScroll, v.inputErr)
+	}
+	cmd, _ = p.handleStreamKey("n")
+	runStreamCmds(p, cmd)
+	if v.match != 419 {
+		t.Errorf("next match = %d, want line 420", v.match+1)
+	}
+	cmd, _ = p.handleStreamKey("N")
+	runStreamCmds(p, cmd)
+	if v.match != 41 {
+		t.Errorf("prev match = %d", v.match+1)
+	}
+
+	// Go to line
+	p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	for _, r := range "5000" {
+		p.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd = p.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	runStreamCmds(p, cmd)
+	if p.previewScroll != 4999 || v.lines[p.previewScroll-v.lineOff] != "line 5000" {
+		t.Errorf("jump scroll = %d", p.previewScroll)
+	}
+
+	// Follow keeps the end in view as the file grows
+	cmd, _ = p.handleStreamKey("F")
+	runStreamCmds(p, cmd)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("appended\n")
+	_ = f.Close()
+	runStreamCmds(p, LoadPreview(dir, "agent.log", p.ctx.Epoch))
+	if p.streamView != v || v.index.Lines() != n+1 || p.previewScroll != p.maxStreamTop() {
+		t.Fatalf("follow lines=%d scroll=%d", v.index.Lines(), p.previewScroll)
+	}
+	if v.lines[len(v.lines)-1] != "appended" {
+		t.Errorf("window not reread, last = %q", v.lines[len(v.lines)-1])
+	}
+	p.handleStreamKey("k")
+	if p.previewFollow {
+		t.Error("scrolling up should stop following")
+	}
+
+	// A truncated file is indexed again
+	if err := os.WriteFile(path, []byte(strings.Repeat("new\n", maxPreviewSize/4+1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runStreamCmds(p, LoadPreview(dir, "agent.log", p.ctx.Epoch))
+	if v.index.Lines() != maxPreviewSize/4+1 || v.match != -1 || v.lines[0] != "new" {
+		t.Errorf("after truncate lines=%d match=%d", v.index.Lines(), v.match)
+	}
+}
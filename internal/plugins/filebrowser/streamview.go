@@ -0,0 +1,449 @@
+package filebrowser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// streamWindowLines is how many lines are kept around the visible ones.
+const streamWindowLines = 400
+
+// streamView is the state of a text file too large to load, read from
+// disk a window of lines at a time. The top line is p.previewScroll.
+type streamView struct {
+	path     string // Previewed file, relative
+	fullPath string
+	size     int64 // File size at the last load
+
+	index    lineIndex
+	indexing bool
+	gen      int // Bumped when the index restarts, to drop stale steps
+
+	lines     []string // Loaded window
+	lineOff   int      // Line number of lines[0]
+	loadedAt  int64    // Index size when the window was read
+	windowEnd bool     // The window reached the end of the file
+	loading   bool
+
+	prompt   bool
+	input    string
+	inputErr string
+	query    string
+	pattern  []byte
+	search   *streamSearch // Search in progress
+	seq      int           // Bumped per search, to drop stale steps
+	match    int           // Line of the current match, -1 for none
+	matchOff int64
+}
+
+// StreamIndexMsg carries the line index extended by one step.
+type StreamIndexMsg struct {
+	Epoch uint64
+	Path  string
+	Gen   int
+	Index lineIndex
+	Size  int64 // File size when the step ran
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m StreamIndexMsg) GetEpoch() uint64 { return m.Epoch }
+
+// StreamLinesMsg carries a window of lines read from a large file.
+type StreamLinesMsg struct {
+	Epoch uint64
+	Path  string
+	Gen   int
+	Start int
+	Lines []string
+	Size  int64 // Index size the lines were read with
+	End   bool  // The lines reach the end of the file
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m StreamLinesMsg) GetEpoch() uint64 { return m.Epoch }
+
+// StreamSearchMsg carries one step of a search of a large file.
+type StreamSearchMsg struct {
+	Epoch  uint64
+	Path   string
+	Seq    int
+	Search streamSearch
+	Offset int64 // -1 if not found yet
+	Line   int
+	Err    error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m StreamSearchMsg) GetEpoch() uint64 { return m.Epoch }
+
+// streamViewActive reports whether the preview streams a large file. The
+// table of a large CSV file takes precedence until switched to raw.
+func (p *Plugin) streamViewActive() bool {
+	return p.streamView != nil && !p.dataViewActive()
+}
+
+// applyStreamResult streams a large text file. Reloading the same file
+// keeps the index, restarting it if the file shrank.
+func (p *Plugin) applyStreamResult(result PreviewResult) {
+	if result.StreamPath == "" {
+		p.streamView = nil
+		return
+	}
+	v := p.streamView
+	if v == nil || v.path != p.previewFile {
+		v = &streamView{path: p.previewFile, match: -1}
+	}
+	v.fullPath = result.StreamPath
+	if result.TotalSize < v.index.size {
+		v.index, v.indexing, v.gen = lineIndex{}, false, v.gen+1
+		v.lines, v.loading, v.match = nil, false, -1
+	}
+	v.size = result.TotalSize
+	v.prompt = false
+	p.streamView = v
+}
+
+// ensureStreamView indexes and reads whatever the stream view is missing.
+func (p *Plugin) ensureStreamView() tea.Cmd {
+	if p.streamView == nil {
+		return nil
+	}
+	return tea.Batch(p.ensureStreamIndex(), p.ensureStreamWindow())
+}
+
+// ensureStreamIndex indexes the next part of the file, if any.
+func (p *Plugin) ensureStreamIndex() tea.Cmd {
+	v := p.streamView
+	if v == nil || v.indexing || v.index.size >= v.size {
+		return nil
+	}
+	v.indexing = true
+	epoch, path, fullPath, gen, idx := p.ctx.Epoch, v.path, v.fullPath, v.gen, v.index
+	return func() tea.Msg {
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return StreamIndexMsg{Epoch: epoch, Path: path, Gen: gen, Index: idx, Err: err}
+		}
+		next, err := idx.extend(fullPath, streamIndexChunk)
+		return StreamIndexMsg{Epoch: epoch, Path: path, Gen: gen, Index: next, Size: info.Size(), Err: err}
+	}
+}
+
+// handleStreamIndex stores an index step and continues until the end.
+func (p *Plugin) handleStreamIndex(msg StreamIndexMsg) tea.Cmd {
+	v := p.streamView
+	if v == nil || v.path != msg.Path || v.gen != msg.Gen {
+		return nil
+	}
+	v.indexing = false
+	if msg.Err != nil {
+		v.inputErr = msg.Err.Error()
+		return nil
+	}
+	v.index = msg.Index
+	v.size = max(v.size, msg.Size)
+	switch {
+	case p.previewFollow:
+		p.previewScroll = p.maxStreamTop()
+	case v.index.size >= v.size:
+		p.previewScroll = min(p.previewScroll, p.maxStreamTop())
+	}
+	return tea.Batch(p.ensureStreamIndex(), p.ensureStreamWindow())
+}
+
+// streamRows is the number of lines the stream view shows.
+func (p *Plugin) streamRows() int {
+	return max(p.visibleContentHeight()-1, 1)
+}
+
+// maxStreamTop is the top line that shows the last line at the bottom.
+func (p *Plugin) maxStreamTop() int {
+	return max(p.streamView.index.Lines()-p.streamRows(), 0)
+}
+
+// ensureStreamWindow reads the lines around the visible ones if they are
+// not loaded, or if the file grew and they include the end.
+func (p *Plugin) ensureStreamWindow() tea.Cmd {
+	v := p.streamView
+	if v == nil || v.loading {
+		return nil
+	}
+	total := v.index.Lines()
+	top := p.previewScroll
+	end := min(top+p.streamRows(), total)
+	loaded := top >= v.lineOff && end <= v.lineOff+len(v.lines)
+	if top >= end || (loaded && (!v.windowEnd || v.loadedAt == v.index.size)) {
+		return nil
+	}
+	v.loading = true
+	start := max(top-streamWindowLines/4, 0)
+	count := max(streamWindowLines, p.streamRows()*2)
+	epoch, path, fullPath, gen, idx := p.ctx.Epoch, v.path, v.fullPath, v.gen, v.index
+	return func() tea.Msg {
+		lines, err := readStreamLines(fullPath, idx, start, count)
+		return StreamLinesMsg{
+			Epoch: epoch, Path: path, Gen: gen, Start: start, Lines: lines,
+			Size: idx.size, End: start+len(lines) >= idx.Lines(), Err: err,
+		}
+	}
+}
+
+// handleStreamLines stores a window of lines.
+func (p *Plugin) handleStreamLines(msg StreamLinesMsg) tea.Cmd {
+	v := p.streamView
+	if v == nil || v.path != msg.Path || v.gen != msg.Gen {
+		return nil
+	}
+	v.loading = false
+	if msg.Err != nil {
+		v.inputErr = msg.Err.Error()
+		return nil
+	}
+	v.lines, v.lineOff, v.loadedAt, v.windowEnd = msg.Lines, msg.Start, msg.Size, msg.End
+	return p.ensureStreamWindow()
+}
+
+// handleStreamKey handles keys for the stream view. It reports false for
+// keys it leaves to the preview.
+func (p *Plugin) handleStreamKey(key string) (tea.Cmd, bool) {
+	v := p.streamView
+	rows := p.streamRows()
+	top := p.previewScroll
+
+	switch key {
+	case "j", "down":
+		top++
+	case "k", "up":
+		top--
+	case "ctrl+d":
+		top += rows / 2
+	case "ctrl+u":
+		top -= rows / 2
+	case "ctrl+f", "pgdown":
+		top += rows
+	case "ctrl+b", "pgup":
+		top -= rows
+	case "g":
+		top = 0
+	case "G":
+		top = p.maxStreamTop()
+	case "F":
+		p.previewFollow = !p.previewFollow
+		if p.previewFollow {
+			top = p.maxStreamTop()
+		}
+	case "/":
+		v.prompt, v.input, v.inputErr = true, v.query, ""
+		return nil, true
+	case "n", "N":
+		if v.pattern == nil {
+			return nil, true
+		}
+		return p.searchStream(key == "N"), true
+	case "esc":
+		if v.pattern == nil {
+			return nil, false
+		}
+		v.query, v.pattern, v.search, v.match, v.inputErr = "", nil, nil, -1, ""
+		return nil, true
+	default:
+		return nil, false
+	}
+
+	if top < p.previewScroll {
+		p.previewFollow = false
+	}
+	p.previewScroll = max(min(top, p.maxStreamTop()), 0)
+	return p.ensureStreamWindow(), true
+}
+
+// handleStreamPromptKey handles typing a search in the stream view.
+func (p *Plugin) handleStreamPromptKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	v := p.streamView
+	switch key := msg.String(); key {
+	case "esc":
+		v.prompt, v.input, v.inputErr = false, "", ""
+	case "enter":
+		v.prompt = false
+		if strings.TrimSpace(v.input) == "" {
+			return p, nil
+		}
+		v.query, v.pattern = v.input, foldASCII([]byte(v.input))
+		v.match = -1
+		return p, p.searchStream(false)
+	case "backspace":
+		if runes := []rune(v.input); len(runes) > 0 {
+			v.input = string(runes[:len(runes)-1])
+		}
+		v.inputErr = ""
+	case "ctrl+u":
+		v.input, v.inputErr = "", ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			v.input += string(msg.Runes)
+			v.inputErr = ""
+		}
+	}
+	return p, nil
+}
+
+// searchStream finds the next (or previous) line containing the query,
+// starting from the current match or the top line.
+func (p *Plugin) searchStream(backward bool) tea.Cmd {
+	v := p.streamView
+	v.seq++
+	v.inputErr = ""
+	epoch, path, fullPath, seq, idx, pattern := p.ctx.Epoch, v.path, v.fullPath, v.seq, v.index, v.pattern
+	top, match, matchOff := p.previewScroll, v.match, v.matchOff
+	s := newStreamSearch(pattern, backward, 0, idx.size)
+	v.search = &s
+	return func() tea.Msg {
+		origin := matchOff
+		if match < 0 {
+			off, err := lineOffset(fullPath, idx, top)
+			if err != nil {
+				return StreamSearchMsg{Epoch: epoch, Path: path, Seq: seq, Offset: -1, Err: err}
+			}
+			origin = off
+		} else if !backward {
+			origin++
+		}
+		return stepStreamSearch(epoch, path, fullPath, seq, idx, newStreamSearch(pattern, backward, origin, idx.size))
+	}
+}
+
+// stepStreamSearch runs one step of a search and finds the line of a match.
+func stepStreamSearch(epoch uint64, path, fullPath string, seq int, idx lineIndex, s streamSearch) StreamSearchMsg {
+	off, next, err := s.step(fullPath)
+	msg := StreamSearchMsg{Epoch: epoch, Path: path, Seq: seq, Search: next, Offset: off, Err: err}
+	if err == nil && off >= 0 {
+		msg.Line, msg.Err = lineAt(fullPath, idx, off)
+	}
+	return msg
+}
+
+// handleStreamSearch moves to a match or continues the search.
+func (p *Plugin) handleStreamSearch(msg StreamSearchMsg) tea.Cmd {
+	v := p.streamView
+	if v == nil || v.path != msg.Path || v.seq != msg.Seq || v.search == nil {
+		return nil
+	}
+	switch {
+	case msg.Err != nil:
+		v.search, v.inputErr = nil, msg.Err.Error()
+		return nil
+	case msg.Offset < 0 && msg.Search.done:
+		v.search, v.inputErr = nil, "Not found: "+v.query
+		return nil
+	case msg.Offset < 0:
+		s := msg.Search
+		v.search = &s
+		epoch, path, fullPath, seq, idx := p.ctx.Epoch, v.path, v.fullPath, v.seq, v.index
+		return func() tea.Msg {
+			return stepStreamSearch(epoch, path, fullPath, seq, idx, s)
+		}
+	}
+
+	v.search = nil
+	v.match, v.matchOff = msg.Line, msg.Offset
+	rows := p.streamRows()
+	if msg.Line < p.previewScroll || msg.Line >= p.previewScroll+rows {
+		p.previewFollow = false
+		p.previewScroll = max(min(msg.Line-min(rows/3, 4), p.maxStreamTop()), 0)
+	}
+	return p.ensureStreamWindow()
+}
+
+// renderStreamView renders the info line and the visible lines.
+func (p *Plugin) renderStreamView(width, height int) string {
+	v := p.streamView
+	rows := max(height-1, 1)
+	total := v.index.Lines()
+
+	lines := []string{p.renderStreamInfo(width, rows)}
+	digits := max(len(strconv.Itoa(total)), 4)
+	for i := range rows {
+		line := p.previewScroll + i
+		if line >= total {
+			break
+		}
+		if line < v.lineOff || line >= v.lineOff+len(v.lines) {
+			lines = append(lines, styles.Muted.Render("Loading..."))
+			break
+		}
+		text := v.highlight(v.lines[line-v.lineOff], line)
+		text = ansi.Truncate(ui.ExpandTabs(text, 8), max(width-digits-1, 10), "")
+		lineNum := styles.FileBrowserLineNumber.Width(digits).Render(strconv.Itoa(line + 1))
+		lines = append(lines, lineNum+" "+text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// highlight marks occurrences of the query in a line, the current match's
+// line in the current-match style.
+func (v *streamView) highlight(text string, line int) string {
+	if v.pattern == nil {
+		return text
+	}
+	folded := foldASCII([]byte(text))
+	var ranges []matchRange
+	for start := 0; ; {
+		i := bytes.Index(folded[start:], v.pattern)
+		if i < 0 {
+			break
+		}
+		start += i
+		ranges = append(ranges, matchRange{start: start, end: start + len(v.pattern)})
+		start += len(v.pattern)
+	}
+	current := -1
+	if line == v.match {
+		current = 0
+	}
+	return injectHighlightsIntoANSI(text, ranges, current)
+}
+
+// renderStreamInfo renders the search prompt, an error, or the position.
+func (p *Plugin) renderStreamInfo(width, rows int) string {
+	v := p.streamView
+	switch {
+	case v.prompt:
+		line := styles.StatusModified.Render("Search: ") + v.input + "█"
+		if v.inputErr != "" {
+			line += "  " + styles.StatusDeleted.Render(v.inputErr)
+		}
+		return ansi.Truncate(line, width, "…")
+	case v.inputErr != "":
+		return styles.StatusDeleted.Render(ansi.Truncate(v.inputErr, width, "…"))
+	}
+
+	total := v.index.Lines()
+	first := min(p.previewScroll+1, total)
+	last := min(p.previewScroll+rows, total)
+	parts := []string{fmt.Sprintf("lines %d-%d of %d", first, last, total)}
+	if v.index.size < v.size {
+		parts[0] += "+"
+		parts = append(parts, fmt.Sprintf("indexing %d%%", v.index.size*100/v.size))
+	}
+	if p.previewFollow {
+		parts = append(parts, "following")
+	}
+	switch {
+	case v.search != nil:
+		parts = append(parts, fmt.Sprintf("searching %s %d%%", v.query, v.search.progress()))
+	case v.match >= 0:
+		parts = append(parts, fmt.Sprintf("%s at line %d (n/N)", v.query, v.match+1))
+	}
+	return styles.Muted.Render(ansi.Truncate(strings.Join(parts, " · "), width, "…"))
+}
@@ -0,0 +1,495 @@
+package filebrowser
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	symbolIndexTimeout = 10 * time.Second // Max time to index project symbols
+	symbolIndexTTL     = time.Minute      // Reuse a project index this long
+	symbolMaxFileSize  = 512 * 1024       // Skip larger files when indexing
+)
+
+// SymbolKind classifies an outline entry.
+type SymbolKind int
+
+const (
+	SymbolFunc SymbolKind = iota
+	SymbolMethod
+	SymbolType
+	SymbolHeading
+)
+
+// Label returns a short label shown before the symbol name.
+func (k SymbolKind) Label() string {
+	switch k {
+	case SymbolMethod:
+		return "method"
+	case SymbolType:
+		return "type"
+	case SymbolHeading:
+		return "#"
+	default:
+		return "func"
+	}
+}
+
+// Symbol is a function, method, type or heading in a file.
+type Symbol struct {
+	Name   string
+	Kind   SymbolKind
+	Path   string // Relative to the project root
+	Line   int    // 1-indexed
+	Parent string // Receiver, enclosing type or scope
+	Depth  int    // Nesting level for the outline
+}
+
+// Display returns the symbol name qualified by its parent.
+func (s Symbol) Display() string {
+	if s.Parent != "" && s.Kind != SymbolHeading {
+		return s.Parent + "." + s.Name
+	}
+	return s.Name
+}
+
+// Symbol sources, shown in the picker footer.
+const (
+	symbolSourceGo        = "go/parser"
+	symbolSourceMarkdown  = "headings"
+	symbolSourceCtags     = "ctags"
+	symbolSourceHeuristic = "syntax heuristics"
+)
+
+// fileSymbols extracts the symbols of one file (path relative to workDir).
+// Go uses go/parser and markdown its headings; other languages use
+// universal-ctags when installed and chroma token heuristics otherwise.
+func fileSymbols(workDir, path string) ([]Symbol, string, error) {
+	data, err := os.ReadFile(filepath.Join(workDir, path))
+	if err != nil {
+		return nil, "", err
+	}
+	if isBinary(data) {
+		return nil, "", nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return goSymbols(path, data), symbolSourceGo, nil
+	case ".md", ".markdown", ".mdx":
+		return markdownSymbols(path, string(data)), symbolSourceMarkdown, nil
+	}
+	if ctags := ctagsPath(); ctags != "" {
+		symbols, err := runCtags(context.Background(), ctags, workDir, []string{path})
+		if err == nil {
+			return symbols, symbolSourceCtags, nil
+		}
+	}
+	return heuristicSymbols(path, string(data)), symbolSourceHeuristic, nil
+}
+
+// goSymbols lists the functions, methods and types of a Go file. Files
+// with syntax errors yield whatever parsed.
+func goSymbols(path string, src []byte) []Symbol {
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if f == nil {
+		return nil
+	}
+	var symbols []Symbol
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			sym := Symbol{Name: d.Name.Name, Kind: SymbolFunc, Path: path, Line: fset.Position(d.Name.Pos()).Line}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				sym.Kind = SymbolMethod
+				sym.Parent = receiverName(d.Recv.List[0].Type)
+			}
+			symbols = append(symbols, sym)
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					symbols = append(symbols, Symbol{Name: ts.Name.Name, Kind: SymbolType, Path: path, Line: fset.Position(ts.Name.Pos()).Line})
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// receiverName returns the type name of a method receiver.
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// markdownSymbols lists ATX headings outside fenced code blocks.
+func markdownSymbols(path, content string) []Symbol {
+	var symbols []Symbol
+	fence := ""
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			switch {
+			case fence == "":
+				fence = trimmed[:3]
+			case strings.HasPrefix(trimmed, fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+		if level > 6 || title == "" || (len(line) > level && line[level] != ' ' && line[level] != '\t') {
+			continue
+		}
+		symbols = append(symbols, Symbol{Name: title, Kind: SymbolHeading, Path: path, Line: i + 1, Depth: level - 1})
+	}
+	return symbols
+}
+
+var (
+	ctagsOnce sync.Once
+	ctagsBin  string
+)
+
+// ctagsPath returns the universal-ctags binary, or "" when it isn't
+// installed. Exuberant and BSD ctags lack JSON output and are ignored.
+func ctagsPath() string {
+	ctagsOnce.Do(func() {
+		path, err := exec.LookPath("ctags")
+		if err != nil {
+			return
+		}
+		out, err := exec.Command(path, "--version").Output()
+		if err == nil && strings.Contains(string(out), "Universal Ctags") {
+			ctagsBin = path
+		}
+	})
+	return ctagsBin
+}
+
+// runCtags runs universal-ctags over files (relative to workDir).
+func runCtags(ctx context.Context, ctags, workDir string, files []string) ([]Symbol, error) {
+	cmd := exec.CommandContext(ctx, ctags, "--output-format=json", "--fields=+nKl", "-f", "-", "-L", "-")
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\n"))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	symbols := parseCtagsOutput(stdout)
+	if err := cmd.Wait(); err != nil && len(symbols) == 0 {
+		return nil, err
+	}
+	return symbols, nil
+}
+
+// ctagsTag is one line of universal-ctags JSON output.
+type ctagsTag struct {
+	Type  string `json:"_type"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Line  int    `json:"line"`
+	Kind  string `json:"kind"`
+	Scope string `json:"scope"`
+	Lang  string `json:"language"`
+}
+
+// ctagsKinds maps ctags kind names to outline kinds. Other kinds
+// (variables, fields, macros, ...) are left out of the outline.
+var ctagsKinds = map[string]SymbolKind{
+	"function":        SymbolFunc,
+	"func":            SymbolFunc,
+	"subroutine":      SymbolFunc,
+	"procedure":       SymbolFunc,
+	"method":          SymbolMethod,
+	"singletonMethod": SymbolMethod,
+	"constructor":     SymbolMethod,
+	"class":           SymbolType,
+	"struct":          SymbolType,
+	"interface":       SymbolType,
+	"trait":           SymbolType,
+	"enum":            SymbolType,
+	"union":           SymbolType,
+	"typedef":         SymbolType,
+	"type":            SymbolType,
+	"module":          SymbolType,
+	"namespace":       SymbolType,
+	"protocol":        SymbolType,
+	"object":          SymbolType,
+}
+
+// parseCtagsOutput reads universal-ctags JSON lines.
+func parseCtagsOutput(r io.Reader) []Symbol {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var symbols []Symbol
+	for scanner.Scan() {
+		var tag ctagsTag
+		if err := json.Unmarshal(scanner.Bytes(), &tag); err != nil || tag.Type != "tag" {
+			continue
+		}
+		kind, ok := ctagsKinds[tag.Kind]
+		if tag.Kind == "member" && tag.Lang == "Python" {
+			// Python methods; elsewhere members are fields
+			kind, ok = SymbolMethod, true
+		}
+		if !ok || tag.Line <= 0 {
+			continue
+		}
+		sym := Symbol{Name: tag.Name, Kind: kind, Path: filepath.ToSlash(tag.Path), Line: tag.Line, Parent: tag.Scope}
+		if tag.Scope != "" {
+			sym.Depth = 1 + strings.Count(tag.Scope, ".") + strings.Count(tag.Scope, "::")
+			if kind == SymbolFunc {
+				sym.Kind = SymbolMethod
+			}
+		}
+		symbols = append(symbols, sym)
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].Path != symbols[j].Path {
+			return symbols[i].Path < symbols[j].Path
+		}
+		return symbols[i].Line < symbols[j].Line
+	})
+	return symbols
+}
+
+// declKeywords introduce a declaration whose name is the next name token.
+var declKeywords = map[string]SymbolKind{
+	"func":      SymbolFunc,
+	"function":  SymbolFunc,
+	"def":       SymbolFunc,
+	"fn":        SymbolFunc,
+	"fun":       SymbolFunc,
+	"sub":       SymbolFunc,
+	"proc":      SymbolFunc,
+	"class":     SymbolType,
+	"struct":    SymbolType,
+	"interface": SymbolType,
+	"trait":     SymbolType,
+	"enum":      SymbolType,
+	"type":      SymbolType,
+	"module":    SymbolType,
+	"namespace": SymbolType,
+	"object":    SymbolType,
+}
+
+// heuristicSymbols finds declarations from chroma tokens: names the lexer
+// marks as function or class names, and names following a declaration
+// keyword. Nesting comes from indentation, so functions indented under a
+// type become its methods.
+func heuristicSymbols(path, content string) []Symbol {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		return nil
+	}
+	iter, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+
+	type scope struct {
+		indent int
+		sym    Symbol
+	}
+	var (
+		symbols     []Symbol
+		stack       []scope
+		line        = 1
+		pendingKind = SymbolKind(-1)
+		lastLine    = 0
+	)
+	for _, tok := range iter.Tokens() {
+		if strings.TrimSpace(tok.Value) == "" {
+			line += strings.Count(tok.Value, "\n")
+			continue
+		}
+		kind := SymbolKind(-1)
+		switch {
+		case tok.Type == chroma.NameFunction:
+			kind = SymbolFunc
+		case tok.Type == chroma.NameClass:
+			kind = SymbolType
+		case pendingKind >= 0 && tok.Type.InCategory(chroma.Name):
+			kind = pendingKind
+		}
+		pendingKind = -1
+		if tok.Type.InCategory(chroma.Keyword) {
+			if k, ok := declKeywords[tok.Value]; ok {
+				pendingKind = k
+			}
+		}
+
+		// One symbol per line: the first declaration on it
+		if kind >= 0 && line != lastLine && line <= len(lines) {
+			lastLine = line
+			text := ui.ExpandTabs(lines[line-1], 4)
+			indent := len(text) - len(strings.TrimLeft(text, " "))
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			sym := Symbol{Name: tok.Value, Kind: kind, Path: path, Line: line, Depth: len(stack)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1].sym
+				sym.Parent = parent.Name
+				if kind == SymbolFunc && parent.Kind == SymbolType {
+					sym.Kind = SymbolMethod
+				}
+			}
+			symbols = append(symbols, sym)
+			stack = append(stack, scope{indent: indent, sym: sym})
+		}
+		line += strings.Count(tok.Value, "\n")
+	}
+	return symbols
+}
+
+// buildSymbolIndex extracts symbols from every file in files (relative to
+// workDir). Go files use go/parser; other languages go through one ctags
+// run when it is installed, or the chroma heuristics otherwise. Markdown
+// headings are left out of the project index. It reports limited when the
+// timeout cut indexing short.
+func buildSymbolIndex(workDir string, files []string) (symbols []Symbol, source string, limited bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), symbolIndexTimeout)
+	defer cancel()
+
+	ctags := ctagsPath()
+	source = symbolSourceGo + ", " + symbolSourceHeuristic
+	if ctags != "" {
+		source = symbolSourceGo + ", " + symbolSourceCtags
+	}
+
+	var others []string
+	for _, path := range files {
+		if ctx.Err() != nil {
+			return symbols, source, true
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".md" || ext == ".markdown" || ext == ".mdx" {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(workDir, path))
+		if err != nil || info.Size() > symbolMaxFileSize {
+			continue
+		}
+		if ext != ".go" {
+			if lexers.Match(filepath.Base(path)) != nil {
+				others = append(others, path)
+			}
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(workDir, path)); err == nil {
+			symbols = append(symbols, goSymbols(path, data)...)
+		}
+	}
+
+	if ctags != "" && len(others) > 0 {
+		tagged, err := runCtags(ctx, ctags, workDir, others)
+		if err == nil {
+			return append(symbols, tagged...), source, ctx.Err() != nil
+		}
+	}
+	for _, path := range others {
+		if ctx.Err() != nil {
+			return symbols, source, true
+		}
+		data, err := os.ReadFile(filepath.Join(workDir, path))
+		if err != nil || isBinary(data) {
+			continue
+		}
+		symbols = append(symbols, heuristicSymbols(path, string(data))...)
+	}
+	return symbols, source, false
+}
+
+// symbolsNamed returns the symbols called name, types and functions before
+// headings.
+func symbolsNamed(symbols []Symbol, name string) []Symbol {
+	var found []Symbol
+	for _, s := range symbols {
+		if s.Name == name && s.Kind != SymbolHeading {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// identifierAt returns the identifier covering visual column col of line,
+// with tabs expanded to tabWidth.
+func identifierAt(line string, col, tabWidth int) string {
+	runes := []rune(ui.ExpandTabs(line, tabWidth))
+	idx, width := -1, 0
+	for i, r := range runes {
+		w := runewidth.RuneWidth(r)
+		if col >= width && col < width+max(w, 1) {
+			idx = i
+			break
+		}
+		width += w
+	}
+	if idx < 0 || !isWordRune(runes[idx]) {
+		return ""
+	}
+	start, end := idx, idx+1
+	for start > 0 && isWordRune(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && isWordRune(runes[end]) {
+		end++
+	}
+	return string(runes[start:end])
+}
+
+// isIdentifier reports whether s is a single identifier.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if !isWordRune(r) {
+			return false
+		}
+		s = s[size:]
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package filebrowser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoSymbols(t *testing.T) {
+	src := `package demo
+
+type Server struct{}
+
+type (
+	Handler func()
+	Option  int
+)
+
+func New() *Server { return nil }
+
+func (s *Server) Start() {}
+
+func (l List[T]) Len() int { return 0 }
+`
+	got := goSymbols("demo.go", []byte(src))
+	want := []Symbol{
+		{Name: "Server", Kind: SymbolType, Line: 3},
+		{Name: "Handler", Kind: SymbolType, Line: 6},
+		{Name: "Option", Kind: SymbolType, Line: 7},
+		{Name: "New", Kind: SymbolFunc, Line: 10},
+		{Name: "Start", Kind: SymbolMethod, Line: 12, Parent: "Server"},
+		{Name: "Len", Kind: SymbolMethod, Line: 14, Parent: "List"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("goSymbols() = %+v", got)
+	}
+	for i, w := range want {
+		w.Path = "demo.go"
+		if got[i] != w {
+			t.Errorf("symbol %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestMarkdownSymbols(t *testing.T) {
+	content := "# Title\n\nText\n\n## Usage ##\n```sh\n# not a heading\n```\n#hashtag\n### Flags\n"
+	got := markdownSymbols("README.md", content)
+	want := []struct {
+		name  string
+		line  int
+		depth int
+	}{{"Title", 1, 0}, {"Usage", 5, 1}, {"Flags", 10, 2}}
+	if len(got) != len(want) {
+		t.Fatalf("markdownSymbols() = %+v", got)
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || got[i].Line != w.line || got[i].Depth != w.depth {
+			t.Errorf("heading %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestHeuristicSymbols(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+		want    []string // Display names with kind labels
+	}{
+		{
+			path:    "app.py",
+			content: "import os\n\nclass Store:\n    def get(self, key):\n        pass\n\ndef main():\n    pass\n",
+			want:    []string{"type Store", "method Store.get", "func main"},
+		},
+		{
+			path:    "lib.rs",
+			content: "struct Point {\n    x: i32,\n}\n\ntrait Shape {}\n\nfn area() -> i32 {\n    0\n}\n",
+			want:    []string{"type Point", "type Shape", "func area"},
+		},
+		{
+			path:    "index.ts",
+			content: "interface Props {}\n\nfunction render(p: Props) {\n  return null\n}\n",
+			want:    []string{"type Props", "func render"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var got []string
+			for _, s := range heuristicSymbols(tt.path, tt.content) {
+				got = append(got, s.Kind.Label()+" "+s.Display())
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("heuristicSymbols() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCtagsOutput(t *testing.T) {
+	out := `{"_type": "ptag", "name": "JSON_OUTPUT_VERSION", "path": "0.0"}
+{"_type": "tag", "name": "Store", "path": "app.py", "line": 3, "kind": "class"}
+{"_type": "tag", "name": "get", "path": "app.py", "line": 4, "kind": "member", "scope": "Store", "language": "Python"}
+{"_type": "tag", "name": "x", "path": "p.h", "line": 2, "kind": "member", "scope": "point", "language": "C"}
+{"_type": "tag", "name": "put", "path": "app.py", "line": 7, "kind": "function", "scope": "Store"}
+{"_type": "tag", "name": "DEBUG", "path": "app.py", "line": 1, "kind": "variable"}
+not json
+{"_type": "tag", "name": "main", "path": "a/main.c", "line": 9, "kind": "function"}
+`
+	got := parseCtagsOutput(strings.NewReader(out))
+	want := []Symbol{
+		{Name: "main", Kind: SymbolFunc, Path: "a/main.c", Line: 9},
+		{Name: "Store", Kind: SymbolType, Path: "app.py", Line: 3},
+		{Name: "get", Kind: SymbolMethod, Path: "app.py", Line: 4, Parent: "Store", Depth: 1},
+		{Name: "put", Kind: SymbolMethod, Path: "app.py", Line: 7, Parent: "Store", Depth: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseCtagsOutput() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("tag %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIdentifierAt(t *testing.T) {
+	tests := []struct {
+		line string
+		col  int
+		want string
+	}{
+		{"return newServer(cfg)", 9, "newServer"},
+		{"return newServer(cfg)", 16, ""},
+		{"\tx := héllo", 14, "héllo"},
+		{"\tx := héllo", 3, ""},
+		{"a", 5, ""},
+	}
+	for _, tt := range tests {
+		if got := identifierAt(tt.line, tt.col, 8); got != tt.want {
+			t.Errorf("identifierAt(%q, %d) = %q, want %q", tt.line, tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestGoToDefinition(t *testing.T) {
+	p, dir := newBatchTestPlugin(t)
+	files := map[string]string{
+		"main.go":        "package main\n\nfunc main() { run() }\n",
+		"run.go":         "package main\n\nfunc run() {}\n\nfunc helper() {}\n",
+		"other/other.go": "package other\n\nfunc helper() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.previewFile = "main.go"
+
+	msg := p.goToDefinition("run")().(DefinitionMsg)
+	if len(msg.Matches) != 1 || msg.Matches[0].Path != "run.go" || msg.Matches[0].Line != 3 {
+		t.Fatalf("run matches = %+v", msg.Matches)
+	}
+	if msg.Index == nil {
+		t.Error("the project index should be built")
+	}
+	p.handleDefinition(msg)
+	if !p.symbolIndexFresh() || p.symbolMode {
+		t.Errorf("index fresh=%v picker open=%v", p.symbolIndexFresh(), p.symbolMode)
+	}
+
+	// The jump previews run.go, whose own helper wins over other packages
+	if p.previewFile != "run.go" {
+		t.Fatalf("previewFile = %q", p.previewFile)
+	}
+	msg = p.goToDefinition("helper")().(DefinitionMsg)
+	if len(msg.Matches) != 1 || msg.Matches[0].Path != "run.go" {
+		t.Errorf("helper from run.go = %+v", msg.Matches)
+	}
+
+	// Two definitions elsewhere open the picker
+	p.previewFile = "main.go"
+	msg = p.goToDefinition("helper")().(DefinitionMsg)
+	if msg.Index != nil || len(msg.Matches) != 2 {
+		t.Fatalf("helper = %+v", msg)
+	}
+	p.handleDefinition(msg)
+	if !p.symbolMode || len(p.symbolState.Matches) != 2 {
+		t.Errorf("picker should list both definitions, got %+v", p.symbolState)
+	}
+}
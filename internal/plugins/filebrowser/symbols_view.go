@@ -0,0 +1,485 @@
+package filebrowser
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// symbolMaxResults caps the matches listed for project symbol search.
+const symbolMaxResults = 200
+
+// SymbolPickerState holds the outline and symbol quick open modal.
+type SymbolPickerState struct {
+	Project bool   // Project-wide symbols rather than one file's outline
+	Path    string // Outlined file
+	Title   string // Overrides the default title
+	Query   string
+	Symbols []Symbol
+	Matches []SymbolMatch
+	Cursor  int
+	Loading bool
+	Error   string
+	Source  string // How the symbols were extracted
+	Limited bool   // Indexing stopped early
+}
+
+// SymbolMatch is a symbol matching the picker query.
+type SymbolMatch struct {
+	Symbol      Symbol
+	Score       int
+	MatchRanges []MatchRange // Ranges within Symbol.Display()
+}
+
+// SymbolsLoadedMsg carries the symbols of one file or of the project.
+type SymbolsLoadedMsg struct {
+	Epoch   uint64
+	Project bool
+	Path    string
+	Symbols []Symbol
+	Source  string
+	Limited bool
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SymbolsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// DefinitionMsg carries the definitions found for an identifier.
+type DefinitionMsg struct {
+	Epoch   uint64
+	Name    string
+	Matches []Symbol
+	Index   *SymbolsLoadedMsg // Set when the project index was rebuilt
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m DefinitionMsg) GetEpoch() uint64 { return m.Epoch }
+
+// updateMatches filters the symbols by the query. With no query the
+// symbols keep their file order.
+func (s *SymbolPickerState) updateMatches() {
+	s.Matches = s.Matches[:0]
+	if s.Query == "" {
+		for _, sym := range s.Symbols {
+			s.Matches = append(s.Matches, SymbolMatch{Symbol: sym})
+		}
+	} else {
+		for _, sym := range s.Symbols {
+			if score, ranges := FuzzyMatch(s.Query, sym.Display()); score > 0 {
+				s.Matches = append(s.Matches, SymbolMatch{Symbol: sym, Score: score, MatchRanges: ranges})
+			}
+		}
+		sort.SliceStable(s.Matches, func(i, j int) bool {
+			return s.Matches[i].Score > s.Matches[j].Score
+		})
+	}
+	if s.Project && len(s.Matches) > symbolMaxResults {
+		s.Matches = s.Matches[:symbolMaxResults]
+	}
+	if s.Cursor >= len(s.Matches) {
+		s.Cursor = max(0, len(s.Matches)-1)
+	}
+}
+
+// openOutline opens the outline of the previewed file.
+func (p *Plugin) openOutline() tea.Cmd {
+	if p.previewFile == "" || p.isBinary || p.isImage {
+		return appmsg.ShowToast("No file to outline", 2*time.Second)
+	}
+	p.openSymbolPicker(&SymbolPickerState{Path: p.previewFile, Loading: true})
+	return p.loadFileSymbols(p.previewFile)
+}
+
+// openSymbolSearch opens project-wide symbol quick open, reusing the
+// project index while it is fresh.
+func (p *Plugin) openSymbolSearch() tea.Cmd {
+	state := &SymbolPickerState{Project: true}
+	p.openSymbolPicker(state)
+	if p.symbolIndexFresh() {
+		p.applySymbols(state, SymbolsLoadedMsg{Project: true, Symbols: p.symbolIndex, Source: p.symbolIndexSource, Limited: p.symbolIndexLimited})
+		return nil
+	}
+	state.Loading = true
+	return p.loadProjectSymbols()
+}
+
+func (p *Plugin) openSymbolPicker(state *SymbolPickerState) {
+	p.symbolMode = true
+	p.symbolState = state
+	p.clearSymbolModal()
+}
+
+// closeSymbols closes the symbol picker.
+func (p *Plugin) closeSymbols() {
+	p.symbolMode = false
+	p.symbolState = nil
+	p.clearSymbolModal()
+}
+
+// loadFileSymbols extracts a file's symbols in the background.
+func (p *Plugin) loadFileSymbols(path string) tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	return func() tea.Msg {
+		symbols, source, err := fileSymbols(workDir, path)
+		return SymbolsLoadedMsg{Epoch: epoch, Path: path, Symbols: symbols, Source: source, Err: err}
+	}
+}
+
+// loadProjectSymbols indexes the project's symbols in the background.
+func (p *Plugin) loadProjectSymbols() tea.Cmd {
+	if len(p.quickOpenFiles) == 0 {
+		p.buildFileCache()
+	}
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	files := slices.Clone(p.quickOpenFiles)
+	return func() tea.Msg {
+		msg := indexSymbols(workDir, files)
+		msg.Epoch = epoch
+		return msg
+	}
+}
+
+func indexSymbols(workDir string, files []string) SymbolsLoadedMsg {
+	symbols, source, limited := buildSymbolIndex(workDir, files)
+	return SymbolsLoadedMsg{Project: true, Symbols: symbols, Source: source, Limited: limited}
+}
+
+func (p *Plugin) symbolIndexFresh() bool {
+	return p.symbolIndex != nil && time.Since(p.symbolIndexTime) < symbolIndexTTL
+}
+
+// storeSymbolIndex caches a freshly built project index.
+func (p *Plugin) storeSymbolIndex(msg SymbolsLoadedMsg) {
+	p.symbolIndex = msg.Symbols
+	if p.symbolIndex == nil {
+		p.symbolIndex = []Symbol{}
+	}
+	p.symbolIndexSource = msg.Source
+	p.symbolIndexLimited = msg.Limited
+	p.symbolIndexTime = time.Now()
+}
+
+// handleSymbolsLoaded fills the open picker with loaded symbols.
+func (p *Plugin) handleSymbolsLoaded(msg SymbolsLoadedMsg) {
+	if msg.Project && msg.Err == nil {
+		p.storeSymbolIndex(msg)
+	}
+	state := p.symbolState
+	if !p.symbolMode || state == nil || state.Project != msg.Project || state.Path != msg.Path {
+		return
+	}
+	p.applySymbols(state, msg)
+}
+
+func (p *Plugin) applySymbols(state *SymbolPickerState, msg SymbolsLoadedMsg) {
+	state.Loading = false
+	state.Symbols = msg.Symbols
+	state.Source = msg.Source
+	state.Limited = msg.Limited
+	state.Error = ""
+	if msg.Err != nil {
+		state.Error = msg.Err.Error()
+	}
+	state.updateMatches()
+
+	// Start the outline at the symbol enclosing the top of the preview
+	if !state.Project {
+		for i, m := range state.Matches {
+			if m.Symbol.Line-1 > p.previewScroll {
+				break
+			}
+			state.Cursor = i
+		}
+	}
+}
+
+// jumpToSymbol closes the picker and shows the symbol at the top of the
+// preview, opening its file when needed.
+func (p *Plugin) jumpToSymbol(sym Symbol) tea.Cmd {
+	p.closeSymbols()
+	p.activePane = PanePreview
+	if sym.Path == p.previewFile {
+		p.previewScroll = max(sym.Line-1, 0)
+		p.clampPreviewScroll()
+		return nil
+	}
+
+	if targetNode := p.findAndExpandPath(sym.Path); targetNode != nil {
+		p.tree.Flatten()
+		if idx := p.tree.IndexOf(targetNode); idx >= 0 {
+			p.treeCursor = idx
+			p.ensureTreeCursorVisible()
+		}
+	}
+	cmd := p.openTabAtLine(sym.Path, sym.Line, TabOpenReplace)
+	p.pinTab(p.activeTab)
+	return cmd
+}
+
+// definitionName returns the identifier to look up from the preview: the
+// selected text, or the committed content search query.
+func (p *Plugin) definitionName() string {
+	if p.selection.HasSelection() && p.selection.Start.Line == p.selection.End.Line {
+		line := p.selection.Start.Line
+		if line >= 0 && line < len(p.previewLines) {
+			text := p.selection.SelectedText(p.previewLines[line:line+1], line, 8)
+			if len(text) == 1 {
+				return strings.TrimSpace(ansi.Strip(text[0]))
+			}
+		}
+	}
+	if p.contentSearchCommitted {
+		return strings.TrimSpace(p.contentSearchQuery)
+	}
+	return ""
+}
+
+// goToDefinition looks up name in the previewed file, then in the project.
+func (p *Plugin) goToDefinition(name string) tea.Cmd {
+	if !isIdentifier(name) {
+		return appmsg.ShowToast("Select an identifier to go to its definition", 2*time.Second)
+	}
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	current := p.previewFile
+	var index []Symbol
+	var files []string
+	if p.symbolIndexFresh() {
+		index = p.symbolIndex
+	} else {
+		if len(p.quickOpenFiles) == 0 {
+			p.buildFileCache()
+		}
+		files = slices.Clone(p.quickOpenFiles)
+	}
+	return func() tea.Msg {
+		msg := DefinitionMsg{Epoch: epoch, Name: name}
+		if current != "" {
+			if symbols, _, err := fileSymbols(workDir, current); err == nil {
+				if msg.Matches = symbolsNamed(symbols, name); len(msg.Matches) > 0 {
+					return msg
+				}
+			}
+		}
+		if index == nil {
+			loaded := indexSymbols(workDir, files)
+			loaded.Epoch = epoch
+			msg.Index = &loaded
+			index = loaded.Symbols
+		}
+		msg.Matches = symbolsNamed(index, name)
+		return msg
+	}
+}
+
+// handleDefinition jumps to a single definition, or lists several.
+func (p *Plugin) handleDefinition(msg DefinitionMsg) tea.Cmd {
+	if msg.Index != nil {
+		p.storeSymbolIndex(*msg.Index)
+	}
+	switch len(msg.Matches) {
+	case 0:
+		return appmsg.ShowToast(fmt.Sprintf("No definition found for %s", msg.Name), 2*time.Second)
+	case 1:
+		return p.jumpToSymbol(msg.Matches[0])
+	}
+	state := &SymbolPickerState{Project: true, Title: "Definitions of " + msg.Name}
+	p.openSymbolPicker(state)
+	p.applySymbols(state, SymbolsLoadedMsg{Project: true, Symbols: msg.Matches})
+	return nil
+}
+
+// handleSymbolKey handles key input in the symbol picker.
+func (p *Plugin) handleSymbolKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	state := p.symbolState
+	if state == nil {
+		p.closeSymbols()
+		return p, nil
+	}
+	key := msg.String()
+	switch key {
+	case "esc":
+		p.closeSymbols()
+	case "enter":
+		if state.Cursor < len(state.Matches) {
+			return p, p.jumpToSymbol(state.Matches[state.Cursor].Symbol)
+		}
+	case "up", "ctrl+p":
+		if state.Cursor > 0 {
+			state.Cursor--
+		}
+	case "down", "ctrl+n":
+		if state.Cursor < len(state.Matches)-1 {
+			state.Cursor++
+		}
+	case "pgup":
+		state.Cursor = max(0, state.Cursor-p.symbolListHeight())
+	case "pgdown":
+		state.Cursor = max(0, min(len(state.Matches)-1, state.Cursor+p.symbolListHeight()))
+	case "backspace":
+		if len(state.Query) > 0 {
+			runes := []rune(state.Query)
+			state.Query = string(runes[:len(runes)-1])
+			state.Cursor = 0
+			state.updateMatches()
+		}
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
+			state.Query += key
+			state.Cursor = 0
+			state.updateMatches()
+		}
+	}
+	return p, nil
+}
+
+// renderSymbolModalContent renders the symbol picker.
+func (p *Plugin) renderSymbolModalContent() string {
+	p.ensureSymbolModal()
+	if p.symbolModal == nil {
+		return ""
+	}
+	return p.symbolModal.Render(p.width, p.height, p.mouseHandler)
+}
+
+// ensureSymbolModal builds/rebuilds the symbol picker modal.
+func (p *Plugin) ensureSymbolModal() {
+	state := p.symbolState
+	if state == nil {
+		return
+	}
+	modalW := min(90, max(p.width-4, 30))
+	if p.symbolModal != nil && p.symbolModalWidth == modalW {
+		return
+	}
+	p.symbolModalWidth = modalW
+
+	title := state.Title
+	switch {
+	case title != "":
+	case state.Project:
+		title = "Go to Symbol"
+	default:
+		title = "Outline: " + filepath.Base(state.Path)
+	}
+	p.symbolModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.symbolQuerySection()).
+		AddSection(modal.Spacer()).
+		AddSection(p.symbolListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(p.symbolFooterSection())
+}
+
+func (p *Plugin) clearSymbolModal() {
+	p.symbolModal = nil
+	p.symbolModalWidth = 0
+}
+
+func (p *Plugin) symbolListHeight() int {
+	return max(min(p.height-12, 20), 5)
+}
+
+func (p *Plugin) symbolQuerySection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		query := ui.TruncateStart(p.symbolState.Query, max(contentWidth-3, 1))
+		return modal.RenderedSection{Content: "> " + query + "█"}
+	}, nil)
+}
+
+// symbolListSection lists matches around the cursor.
+func (p *Plugin) symbolListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		state := p.symbolState
+		switch {
+		case state.Error != "":
+			return modal.RenderedSection{Content: styles.StatusDeleted.Render(ansi.Wrap(state.Error, contentWidth, ""))}
+		case state.Loading:
+			if state.Project {
+				return modal.RenderedSection{Content: styles.Muted.Render("Indexing symbols...")}
+			}
+			return modal.RenderedSection{Content: styles.Muted.Render("Loading...")}
+		case len(state.Symbols) == 0:
+			return modal.RenderedSection{Content: styles.Muted.Render("No symbols found")}
+		case len(state.Matches) == 0:
+			return modal.RenderedSection{Content: styles.Muted.Render("No matches")}
+		}
+
+		height := p.symbolListHeight()
+		start := max(0, min(state.Cursor-height/2, len(state.Matches)-height))
+		end := min(len(state.Matches), start+height)
+		lines := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			lines = append(lines, p.renderSymbolMatch(state.Matches[i], i == state.Cursor, contentWidth))
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+// renderSymbolMatch renders one row: kind, name and, for project symbols,
+// the location.
+func (p *Plugin) renderSymbolMatch(m SymbolMatch, selected bool, width int) string {
+	state := p.symbolState
+	sym := m.Symbol
+	kind := fmt.Sprintf("%-6s ", sym.Kind.Label())
+	indent := ""
+	if !state.Project && state.Query == "" {
+		indent = strings.Repeat("  ", sym.Depth)
+	}
+	name := sym.Display()
+	if indent != "" {
+		name = sym.Name // Nesting already shows the parent
+	}
+
+	location := fmt.Sprintf("%d", sym.Line)
+	if state.Project {
+		location = fmt.Sprintf("%s:%d", sym.Path, sym.Line)
+	}
+	locW := min(ansi.StringWidth(location), max(width/2, 8))
+	location = ui.TruncateStart(location, locW)
+	nameW := max(width-2-len(indent)-len(kind)-locW-1, 8)
+	name = ansi.Truncate(name, nameW, "…")
+	pad := max(width-2-len(kind)-len(indent)-ansi.StringWidth(name)-ansi.StringWidth(location), 1)
+
+	if selected {
+		return styles.ListItemSelected.Render("> " + indent + kind + name + strings.Repeat(" ", pad) + location)
+	}
+	display := name
+	if len(m.MatchRanges) > 0 && name == sym.Display() {
+		display = p.highlightFuzzyMatch(name, m.MatchRanges)
+	}
+	return "  " + indent + styles.Muted.Render(kind) + display + strings.Repeat(" ", pad) + styles.Muted.Render(location)
+}
+
+// symbolFooterSection shows the match count, how symbols were found and
+// the key hints.
+func (p *Plugin) symbolFooterSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		state := p.symbolState
+		info := fmt.Sprintf("%d/%d", min(state.Cursor+1, len(state.Matches)), len(state.Matches))
+		if state.Source != "" {
+			info += " · " + state.Source
+		}
+		if state.Limited {
+			info += " · indexing timed out"
+		}
+		hints := "  enter jump  esc close"
+		return modal.RenderedSection{Content: styles.Muted.Render(ansi.Truncate(info+hints, contentWidth, "…"))}
+	}, nil)
+}
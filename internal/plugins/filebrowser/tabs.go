@@ -48,6 +48,9 @@ func (p *Plugin) findPreviewTab() int {
 
 func (p *Plugin) pinTab(idx int) {
 	if idx >= 0 && idx < len(p.tabs) {
+		if p.tabs[idx].IsPreview && idx == p.activeTab {
+			p.noteOpen(p.tabs[idx].Path)
+		}
 		p.tabs[idx].IsPreview = false
 	}
 }
@@ -77,6 +80,9 @@ func (p *Plugin) saveActiveTabState() {
 		return
 	}
 	p.tabs[p.activeTab].Scroll = p.previewScroll
+	if cur := p.currentJump(); cur != nil && cur.path == p.tabs[p.activeTab].Path {
+		cur.scroll = p.previewScroll
+	}
 }
 
 func (p *Plugin) updateActiveTabResult(result PreviewResult) {
@@ -249,6 +255,9 @@ func (p *Plugin) applyActiveTab() tea.Cmd {
 	p.resetPreviewContent()
 	p.updateWatchedFile()
 	p.syncTreeSelection(tab.Path)
+	if !tab.IsPreview {
+		p.noteOpen(tab.Path)
+	}
 
 	// Check if this tab has a persisted edit session to restore
 	if p.restoreEditStateFromTab() {
@@ -256,13 +265,14 @@ func (p *Plugin) applyActiveTab() tea.Cmd {
 		return p.reattachInlineEditSession()
 	}
 
+	diffCmd := p.ensureGitDiff()
 	if tab.Loaded {
 		p.applyPreviewResult(tab.Result)
 		p.clampPreviewScroll()
-		return nil
+		return tea.Batch(p.ensureStreamView(), diffCmd)
 	}
 
-	return LoadPreview(p.ctx.WorkDir, tab.Path, p.ctx.Epoch)
+	return tea.Batch(LoadPreview(p.ctx.WorkDir, tab.Path, p.ctx.Epoch), diffCmd)
 }
 
 func (p *Plugin) syncTreeSelection(path string) {
@@ -326,9 +336,21 @@ func (p *Plugin) applyPreviewResult(result PreviewResult) {
 	if p.markdownRenderMode && p.isMarkdownFile() {
 		p.renderMarkdownContent()
 	}
+
+	p.applyDataDoc(result.Data)
+	p.applyHexResult(result)
+	p.applyStreamResult(result)
 }
 
 func (p *Plugin) clampPreviewScroll() {
+	if v := p.streamView; v != nil {
+		// Line count is only known once the file is indexed
+		if !v.indexing && v.index.size >= v.size {
+			p.previewScroll = max(min(p.previewScroll, p.maxStreamTop()), 0)
+		}
+		p.saveActiveTabState()
+		return
+	}
 	lines := p.getPreviewLines()
 	visibleHeight := p.visibleContentHeight()
 	maxScroll := len(lines) - visibleHeight
@@ -361,6 +383,7 @@ func (p *Plugin) resetPreviewModes() {
 	p.blameModalWidth = 0
 	p.markdownRendered = nil
 	p.imageResult = nil
+	p.previewFollow = false
 }
 
 func (p *Plugin) resetPreviewContent() {
@@ -373,6 +396,9 @@ func (p *Plugin) resetPreviewContent() {
 	p.previewModTime = time.Time{}
 	p.previewMode = 0
 	p.isImage = false
+	p.dataView = nil
+	p.hexView = nil
+	p.streamView = nil
 }
 
 func (p *Plugin) renderPreviewTabs(width int) string {
@@ -0,0 +1,260 @@
+package filebrowser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// trashDir is the per-project trash, relative to the project root. Each
+// deleted item gets a directory holding meta.json and the item itself
+// under files/.
+const trashDir = ".sidecar/trash"
+
+const trashMetaFile = "meta.json"
+
+// TrashEntry describes one deleted item held in the trash.
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"` // Original path relative to the project
+	IsDir     bool      `json:"isDir"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// trashEntryDir returns the directory holding a trash entry.
+func trashEntryDir(workDir, id string) string {
+	return filepath.Join(workDir, trashDir, id)
+}
+
+// trashPayload returns where a trash entry's item is stored.
+func trashPayload(workDir string, entry TrashEntry) string {
+	return filepath.Join(trashEntryDir(workDir, entry.ID), "files", filepath.Base(entry.Path))
+}
+
+// validTrashID rejects IDs that would point outside the trash.
+func validTrashID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+// checkDeletable validates that path (relative to workDir) may be deleted
+// and returns its absolute path.
+func checkDeletable(workDir, path string) (string, error) {
+	fullPath := filepath.Join(workDir, path)
+
+	// Validate path is within project (safety check)
+	absPath, err := filepath.Abs(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid path")
+	}
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve work directory")
+	}
+	relPath, err := filepath.Rel(absWorkDir, absPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("cannot delete files outside project directory")
+	}
+
+	// Don't allow deleting the project root
+	if relPath == "." {
+		return "", fmt.Errorf("cannot delete project root")
+	}
+	return fullPath, nil
+}
+
+// moveToTrash moves a file or directory (relative to workDir) into the
+// project trash and returns its entry. Items already inside the trash are
+// refused; purge them from the trash view instead.
+func moveToTrash(workDir, path string) (TrashEntry, error) {
+	fullPath, err := checkDeletable(workDir, path)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	if isWithin(filepath.Join(workDir, trashDir), fullPath) || isWithin(fullPath, filepath.Join(workDir, trashDir)) {
+		return TrashEntry{}, fmt.Errorf("cannot move the trash to the trash")
+	}
+	info, err := os.Lstat(fullPath)
+	if err != nil {
+		return TrashEntry{}, fmt.Errorf("source not found: %s", filepath.Base(path))
+	}
+
+	now := time.Now()
+	entry := TrashEntry{
+		ID:        newTrashID(workDir, now),
+		Path:      filepath.Clean(path),
+		IsDir:     info.IsDir(),
+		DeletedAt: now,
+	}
+	payload := trashPayload(workDir, entry)
+	if err := os.MkdirAll(filepath.Dir(payload), 0755); err != nil {
+		return TrashEntry{}, err
+	}
+	ignoreTrash(workDir)
+	if err := moveAcross(fullPath, payload); err != nil {
+		_ = os.RemoveAll(trashEntryDir(workDir, entry.ID))
+		return TrashEntry{}, err
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(trashEntryDir(workDir, entry.ID), trashMetaFile), data, 0644)
+	}
+	if err != nil {
+		// Without metadata the item can't be restored, so put it back
+		_ = moveAcross(payload, fullPath)
+		_ = os.RemoveAll(trashEntryDir(workDir, entry.ID))
+		return TrashEntry{}, fmt.Errorf("failed to write trash metadata: %w", err)
+	}
+	return entry, nil
+}
+
+// ignoreTrash keeps trashed files out of git status, even in repositories
+// that don't ignore .sidecar.
+func ignoreTrash(workDir string) {
+	path := filepath.Join(workDir, trashDir, ".gitignore")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		_ = os.WriteFile(path, []byte("*\n"), 0644)
+	}
+}
+
+// newTrashID returns an unused, time-ordered trash entry ID.
+func newTrashID(workDir string, now time.Time) string {
+	base := now.Format("20060102-150405.000000")
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(trashEntryDir(workDir, id)); os.IsNotExist(err) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// moveAcross renames src to dst, falling back to copy and remove when they
+// are on different filesystems.
+func moveAcross(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		err = copyDir(src, dst)
+	} else {
+		err = copyFile(src, dst)
+	}
+	if err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// listTrash returns the entries in the project trash, newest first.
+// Entries with unreadable metadata are skipped.
+func listTrash(workDir string) ([]TrashEntry, error) {
+	dirs, err := os.ReadDir(filepath.Join(workDir, trashDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []TrashEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(trashEntryDir(workDir, d.Name()), trashMetaFile))
+		if err != nil {
+			continue
+		}
+		var entry TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.ID != d.Name() {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// readTrashEntry loads a single trash entry by ID.
+func readTrashEntry(workDir, id string) (TrashEntry, error) {
+	if !validTrashID(id) {
+		return TrashEntry{}, fmt.Errorf("invalid trash entry")
+	}
+	data, err := os.ReadFile(filepath.Join(trashEntryDir(workDir, id), trashMetaFile))
+	if err != nil {
+		return TrashEntry{}, fmt.Errorf("no longer in trash")
+	}
+	var entry TrashEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return TrashEntry{}, fmt.Errorf("corrupt trash entry: %w", err)
+	}
+	return entry, nil
+}
+
+// restoreFromTrash moves a trash entry back to dest (relative to workDir),
+// normally its original path, and removes the entry.
+func restoreFromTrash(workDir, id, dest string) error {
+	entry, err := readTrashEntry(workDir, id)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(workDir, dest)
+	if !isWithin(workDir, dst) {
+		return fmt.Errorf("cannot restore outside project directory")
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := moveAcross(trashPayload(workDir, entry), dst); err != nil {
+		return err
+	}
+	return os.RemoveAll(trashEntryDir(workDir, id))
+}
+
+// purgeTrashEntry permanently deletes a trash entry.
+func purgeTrashEntry(workDir, id string) error {
+	if !validTrashID(id) {
+		return fmt.Errorf("invalid trash entry")
+	}
+	return os.RemoveAll(trashEntryDir(workDir, id))
+}
+
+// purgeExpiredTrash permanently deletes entries trashed more than retention
+// ago and returns how many were removed. A zero retention keeps everything.
+func purgeExpiredTrash(workDir string, retention time.Duration, now time.Time) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	entries, err := listTrash(workDir)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, entry := range entries {
+		if now.Sub(entry.DeletedAt) <= retention {
+			continue
+		}
+		if err := purgeTrashEntry(workDir, entry.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,153 @@
+package filebrowser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMoveToTrashAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "gen", "out"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gen", "out", "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := moveToTrash(dir, "gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "gen")); !os.IsNotExist(err) {
+		t.Error("gen should be gone")
+	}
+	entries, err := listTrash(dir)
+	if err != nil || len(entries) != 1 || entries[0].Path != "gen" || !entries[0].IsDir {
+		t.Fatalf("listTrash() = %+v, %v", entries, err)
+	}
+
+	if _, err := moveToTrash(dir, filepath.Join(trashDir, entry.ID)); err == nil {
+		t.Error("trashing the trash should fail")
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "gen"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := restoreFromTrash(dir, entry.ID, "gen"); err == nil {
+		t.Error("restore over an existing path should fail")
+	}
+	_ = os.Remove(filepath.Join(dir, "gen"))
+
+	if err := restoreFromTrash(dir, entry.ID, "gen"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "gen", "out", "a.txt")); err != nil || string(data) != "a" {
+		t.Errorf("restored content = %q, %v", data, err)
+	}
+	if entries, _ := listTrash(dir); len(entries) != 0 {
+		t.Errorf("trash should be empty, got %+v", entries)
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := moveToTrash(dir, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := purgeExpiredTrash(dir, 0, time.Now().Add(time.Hour)); n != 0 || err != nil {
+		t.Errorf("zero retention purged %d, %v", n, err)
+	}
+	if n, err := purgeExpiredTrash(dir, 24*time.Hour, time.Now()); n != 0 || err != nil {
+		t.Errorf("fresh entries purged %d, %v", n, err)
+	}
+	if n, err := purgeExpiredTrash(dir, 24*time.Hour, time.Now().Add(48*time.Hour)); n != 2 || err != nil {
+		t.Errorf("expired entries purged %d, %v", n, err)
+	}
+}
+
+func TestUndoBatchDelete(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "pkg/x.go")
+
+	runBatch(p, p.startBatch(BatchDelete, []string{"a.txt", "pkg"}, ""))
+	if len(p.undoJournal) != 1 || len(p.undoJournal[0].Items) != 2 {
+		t.Fatalf("journal = %+v", p.undoJournal)
+	}
+	if entries, _ := listTrash(dir); len(entries) != 2 {
+		t.Fatalf("trash = %+v", entries)
+	}
+
+	runBatch(p, p.undoLast())
+	for _, f := range []string{"a.txt", "pkg/x.go"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("%s not restored: %v", f, err)
+		}
+	}
+	if len(p.undoJournal) != 0 {
+		t.Errorf("journal should be empty, got %+v", p.undoJournal)
+	}
+}
+
+func TestUndoMove(t *testing.T) {
+	p, dir := newBatchTestPlugin(t, "a.txt", "b.txt", "dest/keep.txt")
+
+	runBatch(p, p.startBatch(BatchMove, []string{"a.txt", "b.txt"}, filepath.Join(dir, "dest")))
+	p.recordMoveUndo(filepath.Join(dir, "dest", "keep.txt"), filepath.Join(dir, "kept.txt"))
+	if err := os.Rename(filepath.Join(dir, "dest", "keep.txt"), filepath.Join(dir, "kept.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undo the rename, then the batch move
+	runBatch(p, p.undoLast())
+	runBatch(p, p.undoLast())
+	for _, f := range []string{"a.txt", "b.txt", "dest/keep.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("%s not restored: %v", f, err)
+		}
+	}
+}
+
+func TestDeleteWithoutTrash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	change, err := runBatchItem(dir, batchJob{Op: BatchDelete, Path: "a.txt"})
+	if err != nil || change != (journalItem{}) {
+		t.Fatalf("runBatchItem() = %+v, %v", change, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, trashDir)); !os.IsNotExist(err) {
+		t.Error("permanent delete should not create the trash")
+	}
+}
+
+func TestTrashIgnoredByGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v (%s)", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := moveToTrash(dir, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("trash shows in git status:\n%s", out)
+	}
+}
@@ -0,0 +1,314 @@
+package filebrowser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// Pending confirmations in the trash view.
+const (
+	trashConfirmPurge = "purge"
+	trashConfirmEmpty = "empty"
+)
+
+// TrashLoadedMsg carries the project trash contents.
+type TrashLoadedMsg struct {
+	Epoch   uint64
+	Entries []TrashEntry
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m TrashLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// TrashPurgedMsg reports permanently deleted trash entries.
+type TrashPurgedMsg struct {
+	Epoch  uint64
+	Count  int
+	Err    error
+	Silent bool // Startup retention purge; only errors are reported
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m TrashPurgedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openTrash opens the trash view.
+func (p *Plugin) openTrash() tea.Cmd {
+	p.trashMode = true
+	p.trashCursor = 0
+	p.trashConfirm = ""
+	p.trashError = ""
+	p.clearTrashModal()
+	return p.loadTrash()
+}
+
+// closeTrash closes the trash view.
+func (p *Plugin) closeTrash() {
+	p.trashMode = false
+	p.trashEntries = nil
+	p.trashConfirm = ""
+	p.clearTrashModal()
+}
+
+// loadTrash reads the project trash in the background.
+func (p *Plugin) loadTrash() tea.Cmd {
+	p.trashLoading = true
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	return func() tea.Msg {
+		entries, err := listTrash(workDir)
+		return TrashLoadedMsg{Epoch: epoch, Entries: entries, Err: err}
+	}
+}
+
+// purgeTrash permanently deletes the given entries, or every entry when ids
+// is nil.
+func (p *Plugin) purgeTrash(ids []string) tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	return func() tea.Msg {
+		if ids == nil {
+			entries, err := listTrash(workDir)
+			if err != nil {
+				return TrashPurgedMsg{Epoch: epoch, Err: err}
+			}
+			for _, e := range entries {
+				ids = append(ids, e.ID)
+			}
+		}
+		count := 0
+		for _, id := range ids {
+			if err := purgeTrashEntry(workDir, id); err != nil {
+				return TrashPurgedMsg{Epoch: epoch, Count: count, Err: err}
+			}
+			count++
+		}
+		return TrashPurgedMsg{Epoch: epoch, Count: count}
+	}
+}
+
+// purgeExpired applies the configured trash retention.
+func (p *Plugin) purgeExpired() tea.Cmd {
+	retention := p.trashRetention()
+	if retention <= 0 {
+		return nil
+	}
+	epoch := p.ctx.Epoch
+	workDir := p.ctx.WorkDir
+	return func() tea.Msg {
+		count, err := purgeExpiredTrash(workDir, retention, time.Now())
+		return TrashPurgedMsg{Epoch: epoch, Count: count, Err: err, Silent: true}
+	}
+}
+
+// handleTrashLoaded stores freshly read trash contents.
+func (p *Plugin) handleTrashLoaded(msg TrashLoadedMsg) {
+	p.trashLoading = false
+	p.trashEntries = msg.Entries
+	p.trashError = ""
+	if msg.Err != nil {
+		p.trashError = msg.Err.Error()
+	}
+	if p.trashCursor >= len(p.trashEntries) {
+		p.trashCursor = max(0, len(p.trashEntries)-1)
+	}
+}
+
+// handleTrashPurged reports a purge and reloads the trash view.
+func (p *Plugin) handleTrashPurged(msg TrashPurgedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return appmsg.ShowToast("Trash purge failed: "+msg.Err.Error(), 3*time.Second)
+	}
+	if msg.Silent {
+		return nil
+	}
+	cmds := []tea.Cmd{appmsg.ShowToast(fmt.Sprintf("Permanently deleted %s", pluralItems(msg.Count)), 2*time.Second)}
+	if p.trashMode {
+		cmds = append(cmds, p.loadTrash())
+	}
+	return tea.Batch(cmds...)
+}
+
+// selectedTrashEntry returns the entry under the trash view cursor.
+func (p *Plugin) selectedTrashEntry() *TrashEntry {
+	if p.trashCursor < 0 || p.trashCursor >= len(p.trashEntries) {
+		return nil
+	}
+	return &p.trashEntries[p.trashCursor]
+}
+
+// handleTrashKey handles key input in the trash view.
+func (p *Plugin) handleTrashKey(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	key := msg.String()
+
+	// A pending purge needs an explicit yes
+	if p.trashConfirm != "" {
+		confirm := p.trashConfirm
+		p.trashConfirm = ""
+		if key != "y" && key != "Y" {
+			return p, nil
+		}
+		if confirm == trashConfirmEmpty {
+			return p, p.purgeTrash(nil)
+		}
+		if entry := p.selectedTrashEntry(); entry != nil {
+			return p, p.purgeTrash([]string{entry.ID})
+		}
+		return p, nil
+	}
+
+	switch key {
+	case "esc", "q", "T":
+		p.closeTrash()
+	case "j", "down":
+		if p.trashCursor < len(p.trashEntries)-1 {
+			p.trashCursor++
+		}
+	case "k", "up":
+		if p.trashCursor > 0 {
+			p.trashCursor--
+		}
+	case "g":
+		p.trashCursor = 0
+	case "G":
+		p.trashCursor = max(0, len(p.trashEntries)-1)
+	case "enter", "r":
+		if entry := p.selectedTrashEntry(); entry != nil {
+			return p, p.startRestore([]journalItem{{From: entry.Path, TrashID: entry.ID}})
+		}
+	case "d":
+		if p.selectedTrashEntry() != nil {
+			p.trashConfirm = trashConfirmPurge
+		}
+	case "D":
+		if len(p.trashEntries) > 0 {
+			p.trashConfirm = trashConfirmEmpty
+		}
+	}
+	return p, nil
+}
+
+// renderTrashModalContent renders the trash view.
+func (p *Plugin) renderTrashModalContent() string {
+	p.ensureTrashModal()
+	if p.trashModal == nil {
+		return ""
+	}
+	return p.trashModal.Render(p.width, p.height, p.mouseHandler)
+}
+
+// ensureTrashModal builds/rebuilds the trash modal.
+func (p *Plugin) ensureTrashModal() {
+	modalW := min(90, max(p.width-4, 30))
+	if p.trashModal != nil && p.trashModalWidth == modalW {
+		return
+	}
+	p.trashModalWidth = modalW
+
+	p.trashModal = modal.New("Trash",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.trashListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(p.trashFooterSection())
+}
+
+func (p *Plugin) clearTrashModal() {
+	p.trashModal = nil
+	p.trashModalWidth = 0
+}
+
+// trashListSection lists trashed items around the cursor.
+func (p *Plugin) trashListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.trashError != "" {
+			return modal.RenderedSection{Content: styles.StatusDeleted.Render(ansi.Wrap(p.trashError, contentWidth, ""))}
+		}
+		if len(p.trashEntries) == 0 {
+			if p.trashLoading {
+				return modal.RenderedSection{Content: styles.Muted.Render("Loading...")}
+			}
+			return modal.RenderedSection{Content: styles.Muted.Render("Trash is empty")}
+		}
+
+		header := fmt.Sprintf("%s in %s", pluralItems(len(p.trashEntries)), trashDir)
+		if retention := p.trashRetention(); retention > 0 {
+			header += fmt.Sprintf(" · kept %s", formatRetention(retention))
+		}
+		lines := []string{styles.Muted.Render(header), ""}
+
+		budget := max(p.height-14, 5)
+		start := max(0, min(p.trashCursor-budget/2, len(p.trashEntries)-budget))
+		end := min(len(p.trashEntries), start+budget)
+		for i := start; i < end; i++ {
+			lines = append(lines, p.renderTrashEntry(i, contentWidth))
+		}
+		if end < len(p.trashEntries) {
+			lines = append(lines, styles.Muted.Render(fmt.Sprintf("  ... %d more", len(p.trashEntries)-end)))
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+// renderTrashEntry renders one row: original path and when it was deleted.
+func (p *Plugin) renderTrashEntry(i, width int) string {
+	entry := p.trashEntries[i]
+	path := entry.Path
+	if entry.IsDir {
+		path += string(filepath.Separator)
+	}
+	age := RelativeTime(entry.DeletedAt)
+	pathW := max(width-len(age)-4, 10)
+	path = ansi.Truncate(path, pathW, "…")
+	pad := max(width-2-ansi.StringWidth(path)-len(age), 1)
+
+	if i == p.trashCursor {
+		return styles.ListItemSelected.Render("> " + path + strings.Repeat(" ", pad) + age)
+	}
+	if _, err := os.Lstat(filepath.Join(p.ctx.WorkDir, entry.Path)); err == nil {
+		// Restoring would collide with a file that now exists
+		path = lipgloss.NewStyle().Foreground(styles.Warning).Render(path)
+	}
+	return "  " + path + strings.Repeat(" ", pad) + styles.Muted.Render(age)
+}
+
+// trashFooterSection shows the pending confirmation or the key hints.
+func (p *Plugin) trashFooterSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		switch p.trashConfirm {
+		case trashConfirmEmpty:
+			return modal.RenderedSection{Content: styles.StatusDeleted.Render(fmt.Sprintf("Permanently delete all %s? y/n", pluralItems(len(p.trashEntries))))}
+		case trashConfirmPurge:
+			if entry := p.selectedTrashEntry(); entry != nil {
+				line := fmt.Sprintf("Permanently delete '%s'? y/n", entry.Path)
+				return modal.RenderedSection{Content: styles.StatusDeleted.Render(ansi.Truncate(line, contentWidth, "…"))}
+			}
+		}
+		return modal.RenderedSection{Content: styles.Muted.Render("enter restore  d delete forever  D empty trash  esc close")}
+	}, nil)
+}
+
+// formatRetention formats a retention period in days when it is a whole
+// number of days.
+func formatRetention(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		days := int(d / (24 * time.Hour))
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return d.String()
+}
@@ -41,16 +41,30 @@ func (s SortMode) Next() SortMode {
 
 // FileNode represents a file or directory in the tree.
 type FileNode struct {
-	Name       string
-	Path       string // Relative path from root
-	IsDir      bool
-	IsExpanded bool
-	IsIgnored  bool // Set by gitignore
-	Children   []*FileNode
-	Parent     *FileNode
-	Depth      int
-	Size       int64
-	ModTime    time.Time
+	Name        string
+	Path        string // Relative path from root
+	IsDir       bool
+	IsExpanded  bool
+	IsIgnored   bool   // Set by gitignore
+	IsSubmodule bool   // Directory is a git submodule listed in .gitmodules
+	IsArchive   bool   // Zip or tar file, expandable to its entries
+	Archive     string // Containing archive, for nodes inside one
+	Entry       string // Slash-separated path inside Archive
+	Children    []*FileNode
+	Parent      *FileNode
+	Depth       int
+	Size        int64
+	ModTime     time.Time
+}
+
+// Expandable reports whether the node has children to show.
+func (n *FileNode) Expandable() bool {
+	return n.IsDir || n.IsArchive
+}
+
+// InArchive reports whether the node is an entry inside an archive.
+func (n *FileNode) InArchive() bool {
+	return n.Archive != ""
 }
 
 // FileTree manages the hierarchical file structure.
@@ -59,8 +73,11 @@ type FileTree struct {
 	RootDir     string
 	FlatList    []*FileNode // Flattened visible nodes for cursor navigation
 	gitIgnore   *GitIgnore
-	SortMode    SortMode // Current sort mode
-	ShowIgnored bool     // Whether to include ignored files in FlatList
+	SortMode    SortMode        // Current sort mode
+	ShowIgnored bool            // Whether to include ignored files in FlatList
+	ChangedOnly bool            // Whether FlatList holds only nodes with git changes
+	Git         *gitStatus      // Working tree state from git status, nil outside a repo
+	submodules  map[string]bool // Submodule paths from .gitmodules
 }
 
 // NewFileTree creates a new file tree rooted at the given directory.
@@ -78,6 +95,7 @@ func (t *FileTree) Build() error {
 	// Load .gitignore from root
 	t.gitIgnore = NewGitIgnore()
 	_ = t.gitIgnore.LoadFile(filepath.Join(t.RootDir, ".gitignore"))
+	t.submodules = loadSubmodulePaths(filepath.Join(t.RootDir, ".gitmodules"))
 
 	t.Root = &FileNode{
 		Name:       filepath.Base(t.RootDir),
@@ -113,6 +131,12 @@ func isSystemFile(name string) bool {
 
 // loadChildren populates a node's children from the filesystem.
 func (t *FileTree) loadChildren(node *FileNode) error {
+	if node.IsArchive {
+		return t.loadArchiveChildren(node)
+	}
+	if node.InArchive() {
+		return nil // Listed with the archive
+	}
 	fullPath := filepath.Join(t.RootDir, node.Path)
 
 	entries, err := os.ReadDir(fullPath)
@@ -134,14 +158,16 @@ func (t *FileTree) loadChildren(node *FileNode) error {
 
 		childPath := filepath.Join(node.Path, entry.Name())
 		child := &FileNode{
-			Name:      entry.Name(),
-			Path:      childPath,
-			IsDir:     entry.IsDir(),
-			IsIgnored: t.gitIgnore.IsIgnored(childPath, entry.IsDir()),
-			Parent:    node,
-			Depth:     node.Depth + 1,
-			Size:      info.Size(),
-			ModTime:   info.ModTime(),
+			Name:        entry.Name(),
+			Path:        childPath,
+			IsDir:       entry.IsDir(),
+			IsIgnored:   t.gitIgnore.IsIgnored(childPath, entry.IsDir()),
+			IsSubmodule: entry.IsDir() && t.submodules[filepath.ToSlash(childPath)],
+			IsArchive:   entry.Type().IsRegular() && archiveFormatFor(entry.Name()) != archiveNone,
+			Parent:      node,
+			Depth:       node.Depth + 1,
+			Size:        info.Size(),
+			ModTime:     info.ModTime(),
 		}
 
 		node.Children = append(node.Children, child)
@@ -151,6 +177,22 @@ func (t *FileTree) loadChildren(node *FileNode) error {
 	return nil
 }
 
+// loadSubmodulePaths reads the "path = ..." entries of a .gitmodules file.
+func loadSubmodulePaths(path string) map[string]bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	paths := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && strings.TrimSpace(key) == "path" {
+			paths[strings.Trim(strings.TrimSpace(value), `"`)] = true
+		}
+	}
+	return paths
+}
+
 // sortChildren sorts nodes according to the given mode.
 func sortChildren(children []*FileNode, mode SortMode) {
 	sort.Slice(children, func(i, j int) bool {
@@ -193,7 +235,7 @@ func sortChildren(children []*FileNode, mode SortMode) {
 
 // Expand opens a directory node, loading children if needed.
 func (t *FileTree) Expand(node *FileNode) error {
-	if !node.IsDir {
+	if !node.Expandable() {
 		return nil
 	}
 
@@ -208,6 +250,29 @@ func (t *FileTree) Expand(node *FileNode) error {
 	return nil
 }
 
+// ExpandChanged opens every directory holding git changes, so the changed
+// files under it are visible. Untracked directories stay closed.
+func (t *FileTree) ExpandChanged() {
+	if t.Root != nil {
+		t.expandChanged(t.Root)
+	}
+}
+
+func (t *FileTree) expandChanged(node *FileNode) {
+	for _, child := range node.Children {
+		if !child.IsDir || !t.Git.rolledUp(child.Path) {
+			continue
+		}
+		if len(child.Children) == 0 {
+			if err := t.loadChildren(child); err != nil {
+				continue
+			}
+		}
+		child.IsExpanded = true
+		t.expandChanged(child)
+	}
+}
+
 // Collapse closes a directory node.
 func (t *FileTree) Collapse(node *FileNode) {
 	node.IsExpanded = false
@@ -216,7 +281,7 @@ func (t *FileTree) Collapse(node *FileNode) {
 
 // Toggle expands or collapses a directory node.
 func (t *FileTree) Toggle(node *FileNode) error {
-	if !node.IsDir {
+	if !node.Expandable() {
 		return nil
 	}
 
@@ -242,8 +307,11 @@ func (t *FileTree) flattenNode(node *FileNode) {
 		if !t.ShowIgnored && child.IsIgnored {
 			continue
 		}
+		if t.ChangedOnly && t.Git.state(child.Path) == gitClean {
+			continue
+		}
 		t.FlatList = append(t.FlatList, child)
-		if child.IsDir && child.IsExpanded {
+		if child.Expandable() && child.IsExpanded {
 			t.flattenNode(child)
 		}
 	}
@@ -301,7 +369,7 @@ func (t *FileTree) GetExpandedPaths() map[string]bool {
 
 func (t *FileTree) collectExpanded(node *FileNode, expanded map[string]bool) {
 	for _, child := range node.Children {
-		if child.IsDir && child.IsExpanded {
+		if child.Expandable() && child.IsExpanded {
 			expanded[child.Path] = true
 			t.collectExpanded(child, expanded)
 		}
@@ -319,7 +387,7 @@ func (t *FileTree) RestoreExpandedPaths(paths map[string]bool) {
 
 func (t *FileTree) restoreExpanded(node *FileNode, paths map[string]bool) {
 	for _, child := range node.Children {
-		if child.IsDir && paths[child.Path] {
+		if child.Expandable() && paths[child.Path] {
 			// Load children if needed and expand
 			if len(child.Children) == 0 {
 				_ = t.loadChildren(child)
@@ -359,7 +427,7 @@ func (t *FileTree) resortNode(node *FileNode) {
 	if len(node.Children) > 0 {
 		sortChildren(node.Children, t.SortMode)
 		for _, child := range node.Children {
-			if child.IsDir {
+			if child.Expandable() {
 				t.resortNode(child)
 			}
 		}
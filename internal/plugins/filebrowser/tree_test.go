@@ -358,3 +358,30 @@ func TestFileTree_RestoreExpandedPaths_DeletedDir(t *testing.T) {
 		}
 	}
 }
+
+func TestFileTree_SubmodulesMarked(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitmodules := "[submodule \"lib\"]\n\tpath = lib\n\turl = ../lib\n[submodule \"deps/vendored\"]\n\tpath = deps/vendored\n\turl = ../vendored\n"
+	_ = os.WriteFile(filepath.Join(tmpDir, ".gitmodules"), []byte(gitmodules), 0644)
+	_ = os.MkdirAll(filepath.Join(tmpDir, "lib"), 0755)
+	_ = os.MkdirAll(filepath.Join(tmpDir, "deps", "vendored"), 0755)
+	_ = os.MkdirAll(filepath.Join(tmpDir, "src"), 0755)
+
+	tree := NewFileTree(tmpDir)
+	if err := tree.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Expand(tree.FindByPath("deps")); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]bool{"lib": true, "src": false, "deps": false, filepath.Join("deps", "vendored"): true} {
+		node := tree.FindByPath(path)
+		if node == nil {
+			t.Fatalf("%s not in tree", path)
+		}
+		if node.IsSubmodule != want {
+			t.Errorf("%s IsSubmodule = %v, want %v", path, node.IsSubmodule, want)
+		}
+	}
+}
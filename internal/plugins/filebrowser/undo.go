@@ -0,0 +1,153 @@
+package filebrowser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+)
+
+// maxUndoJournal caps how many operations can be undone.
+const maxUndoJournal = 50
+
+// journalItem is one reversible change: a move of From to To, or a delete
+// that moved From into the trash entry TrashID. Paths are relative to the
+// project root.
+type journalItem struct {
+	From    string
+	To      string
+	TrashID string
+}
+
+// journalEntry is one user operation, which may cover several items.
+type journalEntry struct {
+	Op    BatchOp // BatchMove (including renames), BatchDelete or BatchReplace
+	Items []journalItem
+	Edits []fileEdit // BatchReplace: contents of each file before and after
+}
+
+// trashEnabled reports whether deletes go to the project trash.
+func (p *Plugin) trashEnabled() bool {
+	if p.ctx == nil || p.ctx.Config == nil {
+		return true
+	}
+	return p.ctx.Config.Plugins.FileBrowser.Trash
+}
+
+// trashRetention returns how long trashed items are kept.
+func (p *Plugin) trashRetention() time.Duration {
+	if p.ctx == nil || p.ctx.Config == nil {
+		return 30 * 24 * time.Hour
+	}
+	return p.ctx.Config.Plugins.FileBrowser.TrashRetention
+}
+
+// recordUndo adds an operation to the undo journal.
+func (p *Plugin) recordUndo(op BatchOp, items ...journalItem) {
+	if len(items) == 0 {
+		return
+	}
+	p.pushUndo(journalEntry{Op: op, Items: items})
+}
+
+// recordReplaceUndo journals a project-wide replace.
+func (p *Plugin) recordReplaceUndo(edits []fileEdit) {
+	if len(edits) == 0 {
+		return
+	}
+	p.pushUndo(journalEntry{Op: BatchReplace, Edits: edits})
+}
+
+// pushUndo appends to the undo journal, dropping the oldest entries past
+// maxUndoJournal.
+func (p *Plugin) pushUndo(entry journalEntry) {
+	p.undoJournal = append(p.undoJournal, entry)
+	if len(p.undoJournal) > maxUndoJournal {
+		p.undoJournal = p.undoJournal[len(p.undoJournal)-maxUndoJournal:]
+	}
+}
+
+// recordMoveUndo journals a single move or rename given absolute paths.
+func (p *Plugin) recordMoveUndo(src, dst string) {
+	from, err1 := filepath.Rel(p.ctx.WorkDir, src)
+	to, err2 := filepath.Rel(p.ctx.WorkDir, dst)
+	if err1 == nil && err2 == nil {
+		p.recordUndo(BatchMove, journalItem{From: from, To: to})
+	}
+}
+
+// undoLast reverses the most recent move, rename, delete or project-wide
+// replace. Items are restored newest first.
+func (p *Plugin) undoLast() tea.Cmd {
+	if p.batch != nil {
+		return appmsg.ShowToast(p.batch.Op.Progressive()+" is still running", 2*time.Second)
+	}
+	if len(p.undoJournal) == 0 {
+		return appmsg.ShowToast("Nothing to undo", 2*time.Second)
+	}
+	last := p.undoJournal[len(p.undoJournal)-1]
+	p.undoJournal = p.undoJournal[:len(p.undoJournal)-1]
+	if last.Op == BatchReplace {
+		return p.undoReplace(last.Edits)
+	}
+
+	changes := make([]journalItem, 0, len(last.Items))
+	for i := len(last.Items) - 1; i >= 0; i-- {
+		changes = append(changes, last.Items[i])
+	}
+	return p.startRestore(changes)
+}
+
+// startRestore runs a batch that reverses changes.
+func (p *Plugin) startRestore(changes []journalItem) tea.Cmd {
+	if p.batch != nil {
+		return appmsg.ShowToast(p.batch.Op.Progressive()+" is still running", 2*time.Second)
+	}
+	items := make([]string, len(changes))
+	for i, c := range changes {
+		items[i] = c.From
+	}
+	p.batch = &batchState{Op: BatchRestore, Items: items, Undo: changes}
+	return p.batchStep()
+}
+
+// undoChange reverses one journaled change.
+func undoChange(workDir string, change journalItem) error {
+	if change.TrashID != "" {
+		return restoreFromTrash(workDir, change.TrashID, change.From)
+	}
+
+	src := filepath.Join(workDir, change.To)
+	dst := filepath.Join(workDir, change.From)
+	if !isWithin(workDir, src) || !isWithin(workDir, dst) {
+		return fmt.Errorf("cannot move files outside project directory")
+	}
+	if _, err := os.Lstat(src); err != nil {
+		return fmt.Errorf("%s no longer exists", change.To)
+	}
+
+	// Case-only renames need a two-step rename on case-insensitive filesystems
+	if strings.EqualFold(src, dst) {
+		tempPath := src + ".sidecar-rename-tmp"
+		if err := os.Rename(src, tempPath); err != nil {
+			return err
+		}
+		if err := os.Rename(tempPath, dst); err != nil {
+			_ = os.Rename(tempPath, src)
+			return err
+		}
+		return nil
+	}
+
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", change.From)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
@@ -76,6 +76,13 @@ func (p *Plugin) renderView() string {
 		return ui.OverlayModal(background, modal, p.width, p.height)
 	}
 
+	// Symbol picker is a full overlay - render modal over dimmed background
+	if p.symbolMode {
+		background := p.renderNormalPanes()
+		modal := p.renderSymbolModalContent()
+		return ui.OverlayModal(background, modal, p.width, p.height)
+	}
+
 	// Info modal is a full overlay - render modal over dimmed background
 	if p.infoMode {
 		background := p.renderNormalPanes()
@@ -83,6 +90,20 @@ func (p *Plugin) renderView() string {
 		return ui.OverlayModal(background, modal, p.width, p.height)
 	}
 
+	// Batch failures are a full overlay - render modal over dimmed background
+	if p.batchResult != nil {
+		background := p.renderNormalPanes()
+		modal := p.renderBatchResultModalContent()
+		return ui.OverlayModal(background, modal, p.width, p.height)
+	}
+
+	// Trash view is a full overlay - render modal over dimmed background
+	if p.trashMode {
+		background := p.renderNormalPanes()
+		modal := p.renderTrashModalContent()
+		return ui.OverlayModal(background, modal, p.width, p.height)
+	}
+
 	// Blame view is a full overlay - render modal over dimmed background
 	if p.blameMode {
 		background := p.renderNormalPanes()
@@ -248,7 +269,7 @@ func (p *Plugin) renderNormalPanes() string {
 	}
 
 	// Register individual preview lines for text selection (LAST for highest priority)
-	if p.previewFile != "" && !p.isBinary && len(p.previewLines) > 0 {
+	if p.previewFile != "" && !p.isBinary && len(p.previewLines) > 0 && !p.dataViewActive() && !p.diffViewActive() {
 		previewContentStartY := paneY + 3 // border(1) + header(2 lines)
 		contentStart := p.previewScroll
 		contentEnd := contentStart + innerHeight
@@ -319,9 +340,19 @@ func (p *Plugin) renderFileOpBar() string {
 		if p.fileOpTarget.IsDir {
 			itemType = "directory"
 		}
+		if p.trashEnabled() {
+			return p.renderFileOpConfirmation(fmt.Sprintf("Move %s '%s' to trash?", itemType, p.fileOpTarget.Name))
+		}
 		return p.renderFileOpConfirmation(fmt.Sprintf("Delete %s '%s'?", itemType, p.fileOpTarget.Name))
 	}
 
+	if p.fileOpConfirmDelete && len(p.fileOpBatch) > 0 {
+		if p.trashEnabled() {
+			return p.renderFileOpConfirmation(fmt.Sprintf("Move %s to trash?", pluralItems(len(p.fileOpBatch))))
+		}
+		return p.renderFileOpConfirmation(fmt.Sprintf("Delete %s?", pluralItems(len(p.fileOpBatch))))
+	}
+
 	// Handle confirmation mode for directory creation (during move)
 	if p.fileOpConfirmCreate {
 		return p.renderFileOpConfirmation(fmt.Sprintf("Create '%s'?", p.fileOpConfirmPath))
@@ -333,10 +364,17 @@ func (p *Plugin) renderFileOpBar() string {
 		prompt = "Rename: "
 	case FileOpMove:
 		prompt = "Move to: "
+		if len(p.fileOpBatch) > 0 {
+			prompt = fmt.Sprintf("Move %s into: ", pluralItems(len(p.fileOpBatch)))
+		}
 	case FileOpCreateFile:
 		prompt = "New file: "
 	case FileOpCreateDir:
 		prompt = "New dir: "
+	case FileOpExtract:
+		if p.fileOpTarget != nil {
+			prompt = fmt.Sprintf("Extract %s to: ", p.fileOpTarget.Name)
+		}
 	default:
 		return ""
 	}
@@ -436,6 +474,24 @@ func (p *Plugin) renderTreePane(visibleHeight int) string {
 			sb.WriteString(" ")
 			sb.WriteString(styles.Muted.Render("[ignored: hidden]"))
 		}
+		if p.changedOnly {
+			sb.WriteString(" ")
+			sb.WriteString(styles.StatusModified.Render("[changed]"))
+		}
+		if progress := p.batchProgress(); progress != "" {
+			sb.WriteString(" ")
+			sb.WriteString(styles.StatusModified.Render(progress))
+		} else if n := len(p.selectedPaths); n > 0 || p.visualMode {
+			label := fmt.Sprintf("[%d selected]", n)
+			if p.visualMode {
+				label = "[range]"
+				if n > 0 {
+					label = fmt.Sprintf("[range +%d]", n)
+				}
+			}
+			sb.WriteString(" ")
+			sb.WriteString(styles.StatusModified.Render(label))
+		}
 	}
 	sb.WriteString("\n")
 
@@ -460,6 +516,10 @@ func (p *Plugin) renderTreePane(visibleHeight int) string {
 		// Empty query - fall through to show full tree
 	}
 
+	if p.tree != nil && p.tree.Len() == 0 && p.changedOnly {
+		sb.WriteString(styles.Muted.Render("No changed files (c to show all)"))
+		return sb.String()
+	}
 	if p.tree == nil || p.tree.Len() == 0 {
 		sb.WriteString(styles.Muted.Render("No files"))
 		return sb.String()
@@ -481,7 +541,7 @@ func (p *Plugin) renderTreePane(visibleHeight int) string {
 
 		selected := i == p.treeCursor
 		maxWidth := p.treeWidth - 4 - 1 // Account for border padding and scrollbar column
-		line := p.renderTreeNode(node, selected, maxWidth)
+		line := p.renderTreeNode(node, selected, p.isSelected(i, node), maxWidth)
 
 		treeSB.WriteString(line)
 		// Don't add newline after last line
@@ -559,13 +619,20 @@ func (p *Plugin) renderSearchResults(sb *strings.Builder, visibleHeight int) str
 }
 
 // renderTreeNode renders a single tree node.
-func (p *Plugin) renderTreeNode(node *FileNode, selected bool, maxWidth int) string {
-	// Indentation
+func (p *Plugin) renderTreeNode(node *FileNode, selected, marked bool, maxWidth int) string {
+	// Indentation, with a gutter for selection marks while a selection exists
 	indent := strings.Repeat("  ", node.Depth)
+	gutter := ""
+	if p.hasSelection() {
+		gutter = "  "
+		if marked {
+			gutter = "* "
+		}
+	}
 
-	// Icon for directories
+	// Icon for directories and archives
 	icon := "  "
-	if node.IsDir {
+	if node.Expandable() {
 		if node.IsExpanded {
 			icon = "> "
 		} else {
@@ -573,9 +640,24 @@ func (p *Plugin) renderTreeNode(node *FileNode, selected bool, maxWidth int) str
 		}
 	}
 
-	// Calculate available width for name (after indent and icon)
-	prefixLen := len(indent) + len(icon)
-	availableWidth := maxWidth - prefixLen
+	// Mark submodules so they aren't mistaken for plain directories
+	marker := ""
+	if node.IsSubmodule {
+		marker = " [sub]"
+	} else if node.IsArchive {
+		marker = " [" + archiveFormatFor(node.Name).Label() + "]"
+	}
+
+	// Git badge, right-aligned
+	badge, badgeStyle := p.gitBadge(node)
+	badgeWidth := 0
+	if badge != "" {
+		badgeWidth = ansi.StringWidth(badge) + 1
+	}
+
+	// Calculate available width for name (after indent, icon, marker and badge)
+	prefixLen := len(gutter) + len(indent) + len(icon) + len(marker)
+	availableWidth := maxWidth - prefixLen - badgeWidth
 	if availableWidth < 3 {
 		availableWidth = 3
 	}
@@ -596,17 +678,24 @@ func (p *Plugin) renderTreeNode(node *FileNode, selected bool, maxWidth int) str
 		name = styles.FileBrowserFile.Render(displayName)
 	}
 
-	line := fmt.Sprintf("%s%s%s", indent, styles.FileBrowserIcon.Render(icon), name)
+	line := fmt.Sprintf("%s%s%s%s%s", styles.StatusModified.Render(gutter), indent, styles.FileBrowserIcon.Render(icon), name, styles.Muted.Render(marker))
+	padding := max(maxWidth-prefixLen-len(displayName)-badgeWidth, 0)
 
 	if selected {
 		// Build plain text version for full-width highlight
-		plainLine := indent + icon + displayName
+		plainLine := gutter + indent + icon + displayName + marker
+		if badge != "" {
+			plainLine += strings.Repeat(" ", padding+1) + badge
+		}
 		// Pad to full width
 		if len(plainLine) < maxWidth {
 			plainLine += strings.Repeat(" ", maxWidth-len(plainLine))
 		}
 		return styles.ListItemSelected.Render(plainLine)
 	}
+	if badge != "" {
+		line += strings.Repeat(" ", padding+1) + badgeStyle.Render(badge)
+	}
 	return line
 }
 
@@ -639,6 +728,18 @@ func (p *Plugin) renderPreviewPane(visibleHeight int) string {
 		if p.isMarkdownFile() && p.markdownRenderMode {
 			header += " [rendered]"
 		}
+		if p.diffViewActive() {
+			header += " [diff]"
+		} else if p.dataViewActive() {
+			header += " [" + p.dataView.label() + "]"
+		} else if p.hexViewActive() {
+			header += " [hex]"
+		} else if p.streamViewActive() {
+			header += " [stream]"
+		}
+		if p.previewFollow {
+			header += " [following]"
+		}
 	}
 	sb.WriteString(styles.Title.Render(header))
 
@@ -663,6 +764,11 @@ func (p *Plugin) renderPreviewPane(visibleHeight int) string {
 		return sb.String()
 	}
 
+	if p.diffViewActive() {
+		sb.WriteString(p.renderDiffView(p.previewWidth-4, visibleHeight))
+		return sb.String()
+	}
+
 	if p.previewError != nil {
 		sb.WriteString(styles.StatusDeleted.Render(p.previewError.Error()))
 		return sb.String()
@@ -674,6 +780,21 @@ func (p *Plugin) renderPreviewPane(visibleHeight int) string {
 		return sb.String()
 	}
 
+	if p.dataViewActive() {
+		sb.WriteString(p.renderDataView(p.previewWidth-4, visibleHeight))
+		return sb.String()
+	}
+
+	if p.hexViewActive() {
+		sb.WriteString(p.renderHexView(p.previewWidth-4, visibleHeight))
+		return sb.String()
+	}
+
+	if p.streamViewActive() {
+		sb.WriteString(p.renderStreamView(p.previewWidth-4, visibleHeight))
+		return sb.String()
+	}
+
 	if p.isBinary {
 		sb.WriteString(styles.Muted.Render("Binary file"))
 		return sb.String()
@@ -903,6 +1024,9 @@ func (p *Plugin) wrapPreviewLine(line string, width int) []string {
 }
 
 func (p *Plugin) previewSelectionAtXY(x, y int) (int, int, bool) {
+	if p.dataViewActive() || p.diffViewActive() {
+		return 0, 0, false
+	}
 	lines, showLineNumbers := p.previewRenderLines()
 	if !showLineNumbers || len(lines) == 0 {
 		return 0, 0, false
@@ -1149,7 +1273,11 @@ func (p *Plugin) renderQuickOpenModalContent() string {
 
 	// Header with search input
 	cursor := "█"
-	header := fmt.Sprintf("Quick Open: %s%s", p.quickOpenQuery, cursor)
+	title := "Quick Open"
+	if p.quickOpenRecent != nil {
+		title = "Recent Files"
+	}
+	header := fmt.Sprintf("%s: %s%s", title, p.quickOpenQuery, cursor)
 	sb.WriteString(styles.ModalTitle.Render(header))
 	sb.WriteString("\n\n")
 
@@ -1173,6 +1301,8 @@ func (p *Plugin) renderQuickOpenModalContent() string {
 	if len(p.quickOpenMatches) == 0 {
 		if p.quickOpenQuery != "" {
 			sb.WriteString(styles.Muted.Render("No matches"))
+		} else if p.quickOpenRecent != nil {
+			sb.WriteString(styles.Muted.Render("No recently opened files (ctrl+e for all files)"))
 		} else {
 			sb.WriteString(styles.Muted.Render("Type to search files..."))
 		}
@@ -1218,7 +1348,7 @@ func (p *Plugin) renderQuickOpenModalContent() string {
 	// Footer with match count
 	if len(p.quickOpenMatches) > 0 {
 		sb.WriteString(fmt.Sprintf("\n\n%s", styles.Muted.Render(fmt.Sprintf("(%d/%d)", p.quickOpenCursor+1, len(p.quickOpenMatches)))))
-	} else if len(p.quickOpenFiles) > 0 {
+	} else if p.quickOpenRecent == nil && len(p.quickOpenFiles) > 0 {
 		sb.WriteString(fmt.Sprintf("\n\n%s", styles.Muted.Render(fmt.Sprintf("(%d files)", len(p.quickOpenFiles)))))
 	}
 
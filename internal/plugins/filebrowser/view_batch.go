@@ -0,0 +1,76 @@
+package filebrowser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// batchResultMaxFailures caps the failures listed in the result modal.
+const batchResultMaxFailures = 15
+
+// renderBatchResultModalContent renders the modal listing batch failures.
+func (p *Plugin) renderBatchResultModalContent() string {
+	p.ensureBatchResultModal()
+	if p.batchResultModal == nil {
+		return ""
+	}
+	return p.batchResultModal.Render(p.width, p.height, p.mouseHandler)
+}
+
+// ensureBatchResultModal builds/rebuilds the batch result modal.
+func (p *Plugin) ensureBatchResultModal() {
+	if p.batchResult == nil {
+		return
+	}
+	modalW := min(80, max(p.width-4, 30))
+	if p.batchResultModal != nil && p.batchResultModalWidth == modalW {
+		return
+	}
+	p.batchResultModalWidth = modalW
+
+	b := p.batchResult
+	title := fmt.Sprintf("%s %d of %s", b.Op.Past(), len(b.Done), pluralItems(len(b.Items)))
+	p.batchResultModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantWarning),
+		modal.WithHints(false),
+	).
+		AddSection(p.batchResultSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text(styles.Muted.Render("Failed items stay selected  enter/esc close")))
+}
+
+func (p *Plugin) clearBatchResultModal() {
+	p.batchResultModal = nil
+	p.batchResultModalWidth = 0
+}
+
+// closeBatchResult dismisses the batch result modal.
+func (p *Plugin) closeBatchResult() {
+	p.batchResult = nil
+	p.clearBatchResultModal()
+}
+
+// batchResultSection lists each failed item with its error.
+func (p *Plugin) batchResultSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		b := p.batchResult
+		if b == nil {
+			return modal.RenderedSection{}
+		}
+		lines := []string{styles.StatusDeleted.Render(fmt.Sprintf("%d failed:", len(b.Failed)))}
+		for i, f := range b.Failed {
+			if i == batchResultMaxFailures {
+				lines = append(lines, styles.Muted.Render(fmt.Sprintf("  ... and %d more", len(b.Failed)-i)))
+				break
+			}
+			lines = append(lines, "  "+ansi.Truncate(f.Path, contentWidth-2, "…"))
+			lines = append(lines, styles.Muted.Render("    "+ansi.Truncate(f.Err.Error(), contentWidth-4, "…")))
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
@@ -0,0 +1,206 @@
+package gitstatus
+
+import (
+	"context"
+	"math/bits"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// BisectState is the progress of a git bisect.
+type BisectState struct {
+	Active     bool
+	Bad        string // Newest known bad commit, "" until marked
+	Good       []string
+	Skipped    []string
+	Current    string    // HEAD, the commit being tested
+	Candidates []*Commit // Commits between the good and bad marks, newest first
+	Culprit    string    // First bad commit, once found
+}
+
+// IsGood reports whether hash was marked good.
+func (s *BisectState) IsGood(hash string) bool {
+	return containsHash(s.Good, hash)
+}
+
+// IsSkipped reports whether hash was skipped.
+func (s *BisectState) IsSkipped(hash string) bool {
+	return containsHash(s.Skipped, hash)
+}
+
+// Remaining returns how many candidates are still untested.
+func (s *BisectState) Remaining() int {
+	n := 0
+	for _, c := range s.Candidates {
+		if c.Hash != s.Bad && !s.IsSkipped(c.Hash) {
+			n++
+		}
+	}
+	return n
+}
+
+// Steps estimates how many more marks the bisect needs.
+func (s *BisectState) Steps() int {
+	return bits.Len(uint(s.Remaining()))
+}
+
+func containsHash(hashes []string, hash string) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// BisectError wraps a git bisect error with its output.
+type BisectError struct {
+	Output string
+	Err    error
+}
+
+func (e *BisectError) Error() string {
+	return strings.TrimSpace(e.Output)
+}
+
+// IsBisecting reports whether a bisect is in progress.
+func IsBisecting(workDir string) bool {
+	return gitPathExists(workDir, "BISECT_START")
+}
+
+// GetBisectState reads the marks of the bisect in progress and the commits
+// still in range. Active is false when no bisect is running.
+func GetBisectState(workDir string) (*BisectState, error) {
+	if !IsBisecting(workDir) {
+		return &BisectState{}, nil
+	}
+	state := &BisectState{Active: true, Current: currentHead(workDir).Hash}
+
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)%00%(objectname)", "refs/bisect")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		ref, hash, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		switch {
+		case ref == "refs/bisect/bad":
+			state.Bad = hash
+		case strings.HasPrefix(ref, "refs/bisect/good-"):
+			state.Good = append(state.Good, hash)
+		case strings.HasPrefix(ref, "refs/bisect/skip-"):
+			state.Skipped = append(state.Skipped, hash)
+		}
+	}
+	if state.Bad == "" || len(state.Good) == 0 {
+		return state, nil
+	}
+
+	args := []string{"log", "--format=" + commitLogFormat, state.Bad, "--not"}
+	args = append(args, state.Good...)
+	cmd = exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	state.Candidates = parseCommitLog(output)
+	// Found once the bad commit is the only one left; with untested skipped
+	// commits left git can only narrow it down to a set
+	if len(state.Candidates) == 1 && state.Candidates[0].Hash == state.Bad {
+		state.Culprit = state.Bad
+	}
+	return state, nil
+}
+
+// MarkBisect marks rev, or HEAD when rev is empty, as "good", "bad" or
+// "skip", starting a bisect first if none is running. Returns git's output,
+// which names the next commit to test or the first bad commit.
+func MarkBisect(workDir, term, rev string) (string, error) {
+	if !IsBisecting(workDir) {
+		if output, err := runBisectCmd(workDir, "start"); err != nil {
+			return output, err
+		}
+	}
+	args := []string{term}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	return runBisectCmd(workDir, args...)
+}
+
+// RunBisect lets git test each step with a shell command until the first
+// bad commit is found. The command exits 0 for good, 125 to skip and any
+// other code below 128 for bad. Cancelling ctx stops git and the command;
+// the bisect is then left at the commit being tested, and ctx's error is
+// returned.
+func RunBisect(ctx context.Context, workDir, command string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "bisect", "run", "sh", "-c", command)
+	cmd.Dir = workDir
+	// In a process group of its own, so the test command is killed with git
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return string(output), ctx.Err()
+	}
+	if err != nil {
+		return string(output), &BisectError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// ResetBisect ends the bisect and checks out the commit it started from.
+func ResetBisect(workDir string) (string, error) {
+	return runBisectCmd(workDir, "reset")
+}
+
+func runBisectCmd(workDir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"bisect"}, args...)...)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), &BisectError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+var bisectCulpritRe = regexp.MustCompile(`(?m)^([0-9a-f]{7,64}) is the first bad commit`)
+
+// ParseBisectCulprit returns the first bad commit named in bisect output,
+// or "" if the bisect hasn't finished.
+func ParseBisectCulprit(output string) string {
+	if m := bisectCulpritRe.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// bisectSummary returns the line of bisect output worth showing: the
+// remaining count, the culprit or the first line.
+func bisectSummary(output string) string {
+	var first string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "Bisecting:") || strings.Contains(line, "is the first bad commit") {
+			return line
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return first
+}
@@ -0,0 +1,180 @@
+package gitstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// initBisectRepo creates a repo of eight commits where the fifth adds "bug"
+// to file.txt, and returns the repo, the initial commit and the culprit.
+func initBisectRepo(t *testing.T) (dir, first, culprit string) {
+	t.Helper()
+	dir = initPartialRepo(t, "ok\n")
+	first = gitRun(t, dir, "rev-parse", "HEAD")
+	for i := 1; i <= 7; i++ {
+		content := fmt.Sprintf("ok %d\n", i)
+		if i >= 4 {
+			content += "bug\n"
+		}
+		commitFile(t, dir, "file.txt", content, fmt.Sprintf("change %d", i))
+		if i == 4 {
+			culprit = gitRun(t, dir, "rev-parse", "HEAD")
+		}
+	}
+	return dir, first, culprit
+}
+
+func TestMarkBisectFindsCulprit(t *testing.T) {
+	dir, first, culprit := initBisectRepo(t)
+	branch := gitRun(t, dir, "branch", "--show-current")
+
+	if _, err := MarkBisect(dir, "bad", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !IsBisecting(dir) {
+		t.Fatal("bisect not started")
+	}
+	output, err := MarkBisect(dir, "good", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := GetBisectState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Candidates) != 7 || state.Remaining() != 6 {
+		t.Fatalf("candidates = %d, remaining = %d; want 7, 6", len(state.Candidates), state.Remaining())
+	}
+
+	for range 10 {
+		if found := ParseBisectCulprit(output); found != "" {
+			if found != culprit {
+				t.Fatalf("culprit = %s, want %s", found, culprit)
+			}
+			break
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		term := "good"
+		if strings.Contains(string(data), "bug") {
+			term = "bad"
+		}
+		if output, err = MarkBisect(dir, term, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state, err = GetBisectState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Culprit != culprit {
+		t.Fatalf("state culprit = %q, want %q", state.Culprit, culprit)
+	}
+
+	if _, err := ResetBisect(dir); err != nil {
+		t.Fatal(err)
+	}
+	if IsBisecting(dir) {
+		t.Fatal("bisect still active after reset")
+	}
+	if got := gitRun(t, dir, "branch", "--show-current"); got != branch {
+		t.Fatalf("branch after reset = %q, want %q", got, branch)
+	}
+}
+
+func TestRunBisect(t *testing.T) {
+	dir, first, culprit := initBisectRepo(t)
+	if _, err := MarkBisect(dir, "bad", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MarkBisect(dir, "good", first); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := RunBisect(context.Background(), dir, "! grep -q bug file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ParseBisectCulprit(output); got != culprit {
+		t.Fatalf("culprit = %q, want %q", got, culprit)
+	}
+}
+
+func TestRunBisectCancel(t *testing.T) {
+	dir, first, _ := initBisectRepo(t)
+	if _, err := MarkBisect(dir, "bad", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MarkBisect(dir, "good", first); err != nil {
+		t.Fatal(err)
+	}
+
+	// A test command that never finishes
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := RunBisect(ctx, dir, "sleep 30"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the context's", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("run took %s after cancelling", elapsed)
+	}
+	if !IsBisecting(dir) {
+		t.Fatal("bisect ended by cancelling the run")
+	}
+}
+
+func TestBisectRunCancelKey(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &Plugin{viewMode: ViewModeBisect, bisectRunning: true, bisectRunCancel: cancel, bisectCommand: "make test"}
+	if got := p.FocusContext(); got != "git-bisect-running" {
+		t.Fatalf("context = %q", got)
+	}
+
+	p.updateBisect(tea.KeyMsg{Type: tea.KeyEsc})
+	if ctx.Err() == nil || p.viewMode != ViewModeBisect {
+		t.Fatalf("esc while running: cancelled = %v, view = %v", ctx.Err() != nil, p.viewMode)
+	}
+
+	// Once the run stops, the bisect is reset
+	if cmd := p.handleBisectDone(BisectDoneMsg{Action: "run", Err: context.Canceled}); cmd == nil {
+		t.Fatal("no reset after cancelling")
+	}
+	if p.bisectRunCancel != nil || !p.bisectRunning || p.FocusContext() != "git-bisect" {
+		t.Fatalf("after cancel: cancel set = %v, running = %v", p.bisectRunCancel != nil, p.bisectRunning)
+	}
+}
+
+func TestGetBisectStateInactive(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	state, err := GetBisectState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Active {
+		t.Fatal("state active without a bisect")
+	}
+}
+
+func TestBisectSummary(t *testing.T) {
+	output := "status: waiting for good commit(s), bad commit known\nBisecting: 2 revisions left to test after this (roughly 2 steps)\n[abc] change 3\n"
+	if got := bisectSummary(output); !strings.HasPrefix(got, "Bisecting: 2 revisions") {
+		t.Fatalf("summary = %q", got)
+	}
+	if got := bisectSummary("\nsome error\nmore\n"); got != "some error" {
+		t.Fatalf("summary = %q, want first line", got)
+	}
+}
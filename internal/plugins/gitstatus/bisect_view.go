@@ -0,0 +1,760 @@
+package gitstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	bisectBadID      = "bisect-bad"
+	bisectGoodID     = "bisect-good"
+	bisectSkipID     = "bisect-skip"
+	bisectOpenID     = "bisect-open"
+	bisectResetID    = "bisect-reset"
+	bisectCommandID  = "bisect-command"
+	bisectRunID      = "bisect-run"
+	bisectGraphWidth = 12
+)
+
+// BisectStateLoadedMsg carries the progress of the bisect in progress.
+type BisectStateLoadedMsg struct {
+	Epoch uint64
+	State *BisectState
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m BisectStateLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// BisectDoneMsg is sent when a bisect command finishes.
+type BisectDoneMsg struct {
+	Action string // "mark", "run" or "reset"
+	Output string
+	Err    error
+}
+
+// BisectRunTickMsg redraws the elapsed time of a running test command.
+type BisectRunTickMsg struct{}
+
+// BisectDiffLoadedMsg carries the diff of a commit in the bisect range.
+type BisectDiffLoadedMsg struct {
+	Epoch uint64
+	Hash  string
+	Diff  string
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m BisectDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// bisectActive reports whether the last loaded state has a bisect running.
+func (p *Plugin) bisectActive() bool {
+	return p.bisect != nil && p.bisect.Active
+}
+
+// bisectMarker returns the mark shown next to a commit while bisecting:
+// ✗ bad, ✓ good, ~ skipped, ● being tested. Empty for other commits.
+func (p *Plugin) bisectMarker(hash string) (plain, styled string) {
+	if !p.bisectActive() {
+		return "", ""
+	}
+	s := p.bisect
+	switch {
+	case hash == s.Bad:
+		return "✗", styles.StatusDeleted.Render("✗")
+	case s.IsGood(hash):
+		return "✓", styles.StatusStaged.Render("✓")
+	case s.IsSkipped(hash):
+		return "~", styles.Muted.Render("~")
+	case hash == s.Current:
+		return "●", lipgloss.NewStyle().Foreground(styles.Accent).Render("●")
+	}
+	return "", ""
+}
+
+// loadBisectState reads the bisect progress.
+func (p *Plugin) loadBisectState() tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		state, err := GetBisectState(workDir)
+		return BisectStateLoadedMsg{Epoch: epoch, State: state, Err: err}
+	}
+}
+
+// handleBisectStateLoaded stores the bisect progress and, in the bisect view,
+// moves the cursor to the culprit or the commit being tested.
+func (p *Plugin) handleBisectStateLoaded(m BisectStateLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) {
+		return nil
+	}
+	if m.Err != nil {
+		if p.viewMode == ViewModeBisect {
+			p.closeBisect()
+			p.showErrorModal("Bisect Failed", m.Err)
+		}
+		return nil
+	}
+	prev := p.bisect
+	p.bisect = m.State
+	p.bisectGraph = ComputeGraphForCommits(m.State.Candidates)
+	if p.viewMode != ViewModeBisect {
+		return nil
+	}
+	if !m.State.Active {
+		p.closeBisect()
+		return nil
+	}
+	if prev != nil && p.bisectDiffHash != "" && prev.Current == m.State.Current &&
+		prev.Culprit == m.State.Culprit && len(prev.Candidates) == len(m.State.Candidates) {
+		// Unchanged by a background reload; keep the user's selection
+		return nil
+	}
+
+	target := m.State.Current
+	if m.State.Culprit != "" {
+		target = m.State.Culprit
+	}
+	p.bisectCursor = 0
+	if idx := indexOfCommitHash(m.State.Candidates, target); idx >= 0 {
+		p.bisectCursor = idx
+	}
+	p.clearBisectDiff()
+	return p.loadBisectDiff()
+}
+
+// openBisectMenu opens the good/bad/skip prompt for a commit in the history.
+func (p *Plugin) openBisectMenu(commit *Commit) {
+	p.bisectTarget = commit
+	p.clearBisectMenuModal()
+	p.viewMode = ViewModeBisectMenu
+}
+
+// updateBisectMenu handles key events in the bisect prompt.
+func (p *Plugin) updateBisectMenu(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureBisectMenuModal()
+	if p.bisectMenuModal == nil {
+		return p, nil
+	}
+
+	switch m.String() {
+	case "b":
+		return p, p.handleBisectMenuAction(bisectBadID)
+	case "g":
+		return p, p.handleBisectMenuAction(bisectGoodID)
+	case "s":
+		if p.bisectActive() {
+			return p, p.handleBisectMenuAction(bisectSkipID)
+		}
+	case "o":
+		if p.bisectActive() {
+			return p, p.handleBisectMenuAction(bisectOpenID)
+		}
+	case "x":
+		if p.bisectActive() {
+			return p, p.handleBisectMenuAction(bisectResetID)
+		}
+	case "q":
+		p.closeBisectMenu()
+		return p, nil
+	}
+
+	action, cmd := p.bisectMenuModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleBisectMenuAction(action))
+}
+
+// handleBisectMenuAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleBisectMenuAction(action string) tea.Cmd {
+	target := p.bisectTarget
+	switch action {
+	case "cancel":
+		p.closeBisectMenu()
+	case bisectBadID, bisectGoodID, bisectSkipID:
+		if target == nil {
+			return nil
+		}
+		p.closeBisectMenu()
+		return p.doBisectMark(strings.TrimPrefix(action, "bisect-"), target.Hash)
+	case bisectOpenID:
+		p.closeBisectMenu()
+		return p.openBisect()
+	case bisectResetID:
+		p.closeBisectMenu()
+		return p.doBisectReset()
+	}
+	return nil
+}
+
+func (p *Plugin) closeBisectMenu() {
+	p.viewMode = ViewModeStatus
+	p.bisectTarget = nil
+	p.clearBisectMenuModal()
+}
+
+func (p *Plugin) clearBisectMenuModal() {
+	p.bisectMenuModal = nil
+	p.bisectMenuModalWidth = 0
+}
+
+// ensureBisectMenuModal builds/rebuilds the bisect prompt.
+func (p *Plugin) ensureBisectMenuModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.bisectMenuModal != nil && p.bisectMenuModalWidth == modalW {
+		return
+	}
+	p.bisectMenuModalWidth = modalW
+	if p.bisectTarget == nil {
+		return
+	}
+
+	active := p.bisectActive()
+	buttons := []modal.ButtonDef{
+		modal.Btn(" Bad ", bisectBadID, modal.BtnDanger()),
+		modal.Btn(" Good ", bisectGoodID),
+	}
+	if active {
+		buttons = append(buttons, modal.Btn(" Skip ", bisectSkipID), modal.Btn(" Open ", bisectOpenID), modal.Btn(" Reset ", bisectResetID))
+	}
+	buttons = append(buttons, modal.Btn(" Cancel ", "cancel"))
+
+	c := p.bisectTarget
+	p.bisectMenuModal = modal.New("Bisect at "+c.ShortHash,
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := styles.Code.Render(c.ShortHash) + " " + ansi.Truncate(c.Subject, max(contentWidth-8, 10), "…")
+			content += "\n\n" + styles.Muted.Render(ansi.Truncate(p.bisectMenuHint(), contentWidth, "…"))
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(buttons...))
+}
+
+// bisectMenuHint explains what marking does at this point of the bisect.
+func (p *Plugin) bisectMenuHint() string {
+	s := p.bisect
+	switch {
+	case !p.bisectActive():
+		return "Mark b bad or g good to start bisecting."
+	case s.Bad == "":
+		return "Waiting for a bad commit. b bad, g good, s skip."
+	case len(s.Good) == 0:
+		return "Waiting for a good commit. b bad, g good, s skip."
+	}
+	return "b bad, g good, s skip, o open bisect, x reset"
+}
+
+// renderBisectMenu renders the bisect prompt over the commit list.
+func (p *Plugin) renderBisectMenu() string {
+	background := p.renderThreePaneView()
+
+	p.ensureBisectMenuModal()
+	if p.bisectMenuModal == nil {
+		return background
+	}
+
+	modalContent := p.bisectMenuModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// doBisectMark marks rev as "good", "bad" or "skip", or the commit being
+// tested when rev is empty.
+func (p *Plugin) doBisectMark(term, rev string) tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		output, err := MarkBisect(workDir, term, rev)
+		return BisectDoneMsg{Action: "mark", Output: output, Err: err}
+	}
+}
+
+// doBisectReset ends the bisect.
+func (p *Plugin) doBisectReset() tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		output, err := ResetBisect(workDir)
+		return BisectDoneMsg{Action: "reset", Output: output, Err: err}
+	}
+}
+
+// handleBisectDone reports a bisect step and reloads the state, opening the
+// bisect view once both ends of the range are marked.
+func (p *Plugin) handleBisectDone(m BisectDoneMsg) tea.Cmd {
+	p.bisectRunning = false
+	if m.Output != "" {
+		p.bisectOutput = m.Output
+	}
+	if m.Action == "run" {
+		p.bisectRunCancel = nil
+		if errors.Is(m.Err, context.Canceled) {
+			// Cancelled mid-step: leave the commit being tested
+			p.bisectRunning = true
+			return tea.Batch(p.doBisectReset(), msg.ShowToast("Bisect run cancelled", 2*time.Second))
+		}
+	}
+	if m.Err != nil {
+		p.showErrorModal("Bisect Failed", m.Err)
+	}
+
+	cmds := []tea.Cmd{p.refresh(), p.loadRecentCommits(), p.loadBisectState()}
+	var toast string
+	switch m.Action {
+	case "reset":
+		if m.Err == nil {
+			p.bisect = nil
+			p.bisectGraph = nil
+			if p.viewMode == ViewModeBisect {
+				p.closeBisect()
+			}
+			toast = "Bisect reset"
+		}
+	default:
+		culprit := ParseBisectCulprit(m.Output)
+		if culprit != "" {
+			toast = "Found the first bad commit " + culprit[:min(len(culprit), 7)]
+		} else if m.Err == nil {
+			toast = bisectSummary(m.Output)
+		}
+		if m.Err == nil && p.viewMode == ViewModeStatus && (culprit != "" || strings.Contains(m.Output, "Bisecting:")) {
+			// Both ends are marked; follow the steps in the bisect view
+			p.bisectReturnMode = ViewModeStatus
+			p.viewMode = ViewModeBisect
+		}
+	}
+	if toast != "" && m.Err == nil {
+		cmds = append(cmds, func() tea.Msg {
+			return app.ToastMsg{Message: toast, Duration: 3 * time.Second}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// openBisect opens the full-screen bisect view.
+func (p *Plugin) openBisect() tea.Cmd {
+	if p.viewMode != ViewModeBisect {
+		p.bisectReturnMode = p.viewMode
+	}
+	p.viewMode = ViewModeBisect
+	p.bisectCursor = 0
+	p.bisectListScroll = 0
+	p.clearBisectDiff()
+	return p.loadBisectState()
+}
+
+// closeBisect leaves the bisect view. The bisect itself keeps running.
+func (p *Plugin) closeBisect() {
+	p.viewMode = p.bisectReturnMode
+	if p.viewMode == ViewModeBisect || p.viewMode == ViewModeBisectRun || p.viewMode == ViewModeBisectMenu {
+		p.viewMode = ViewModeStatus
+	}
+	p.clearBisectDiff()
+}
+
+func (p *Plugin) clearBisectDiff() {
+	p.bisectDiff = nil
+	p.bisectDiffRaw = ""
+	p.bisectDiffHash = ""
+	p.bisectDiffScroll = 0
+}
+
+// selectedBisectCommit returns the candidate under the cursor, if any.
+func (p *Plugin) selectedBisectCommit() *Commit {
+	if p.bisect == nil || p.bisectCursor < 0 || p.bisectCursor >= len(p.bisect.Candidates) {
+		return nil
+	}
+	return p.bisect.Candidates[p.bisectCursor]
+}
+
+// loadBisectDiff loads the diff of the selected candidate.
+func (p *Plugin) loadBisectDiff() tea.Cmd {
+	c := p.selectedBisectCommit()
+	if c == nil {
+		return nil
+	}
+	p.clearBisectDiff()
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		diff, err := GetHistoryDiff(workDir, &FileHistoryEntry{Commit: c}, nil)
+		return BisectDiffLoadedMsg{Epoch: epoch, Hash: c.Hash, Diff: diff, Err: err}
+	}
+}
+
+// handleBisectDiffLoaded shows a loaded diff if its commit is still selected.
+func (p *Plugin) handleBisectDiffLoaded(m BisectDiffLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeBisect {
+		return nil
+	}
+	c := p.selectedBisectCommit()
+	if c == nil || c.Hash != m.Hash {
+		return nil
+	}
+	p.bisectDiffHash = m.Hash
+	if m.Err != nil {
+		p.bisectDiffRaw = "Error: " + m.Err.Error()
+		return nil
+	}
+	p.bisectDiffRaw = m.Diff
+	p.bisectDiff = ParseMultiFileDiff(m.Diff)
+	return nil
+}
+
+// moveBisectCursor selects another candidate and loads its diff.
+func (p *Plugin) moveBisectCursor(idx int) tea.Cmd {
+	if p.bisect == nil {
+		return nil
+	}
+	idx = max(min(idx, len(p.bisect.Candidates)-1), 0)
+	if idx == p.bisectCursor {
+		return nil
+	}
+	p.bisectCursor = idx
+	return p.loadBisectDiff()
+}
+
+// updateBisect handles key events in the bisect view. g, b and s mark the
+// commit being tested.
+func (p *Plugin) updateBisect(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	page := max((p.height-4)/2, 1)
+	if p.bisectRunCancel != nil && (m.String() == "esc" || m.String() == "x") {
+		p.cancelBisectRun()
+		return p, nil
+	}
+	switch m.String() {
+	case "esc", "q":
+		p.closeBisect()
+	case "j", "down":
+		return p, p.moveBisectCursor(p.bisectCursor + 1)
+	case "k", "up":
+		return p, p.moveBisectCursor(p.bisectCursor - 1)
+	case "home":
+		return p, p.moveBisectCursor(0)
+	case "end":
+		if p.bisect != nil {
+			return p, p.moveBisectCursor(len(p.bisect.Candidates) - 1)
+		}
+	case "ctrl+d", "J":
+		p.bisectDiffScroll += page
+	case "ctrl+u", "K":
+		p.bisectDiffScroll = max(p.bisectDiffScroll-page, 0)
+	case "v":
+		if p.diffViewMode == DiffViewUnified {
+			p.diffViewMode = DiffViewSideBySide
+		} else {
+			p.diffViewMode = DiffViewUnified
+		}
+	case "g", "b", "s":
+		if p.bisectRunning || p.bisect == nil || p.bisect.Culprit != "" {
+			return p, nil
+		}
+		term := map[string]string{"g": "good", "b": "bad", "s": "skip"}[m.String()]
+		p.bisectRunning = true
+		return p, p.doBisectMark(term, "")
+	case "r":
+		if !p.bisectRunning && p.bisect != nil && p.bisect.Culprit == "" {
+			p.openBisectRun()
+		}
+	case "x":
+		if !p.bisectRunning {
+			return p, p.doBisectReset()
+		}
+	case "y":
+		if c := p.selectedBisectCommit(); c != nil {
+			if err := clipboard.WriteAll(c.Hash); err != nil {
+				return p, msg.ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+			}
+			return p, msg.ShowToast("Yanked "+c.ShortHash, 2*time.Second)
+		}
+	}
+	return p, nil
+}
+
+// openBisectRun opens the test command prompt.
+func (p *Plugin) openBisectRun() {
+	p.bisectCommandInput = textinput.New()
+	p.bisectCommandInput.Placeholder = "go test ./..."
+	p.bisectCommandInput.Prompt = ""
+	p.bisectCommandInput.CharLimit = 500
+	p.bisectCommandInput.Width = 40
+	p.bisectCommandInput.SetValue(p.bisectCommand)
+	p.bisectCommandInput.CursorEnd()
+	p.bisectCommandInput.Focus()
+	p.bisectRunError = ""
+	p.clearBisectRunModal()
+	p.viewMode = ViewModeBisectRun
+}
+
+// updateBisectRun handles key events in the test command prompt.
+func (p *Plugin) updateBisectRun(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureBisectRunModal()
+	if p.bisectRunModal == nil {
+		return p, nil
+	}
+	action, cmd := p.bisectRunModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleBisectRunAction(action))
+}
+
+// handleBisectRunAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleBisectRunAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeBisectRun()
+	case bisectRunID, bisectCommandID:
+		command := strings.TrimSpace(p.bisectCommandInput.Value())
+		if command == "" {
+			p.bisectRunError = "Enter a command to test each commit with"
+			return nil
+		}
+		p.bisectCommand = command
+		p.closeBisectRun()
+		p.bisectRunning = true
+		ctx, cancel := context.WithCancel(context.Background())
+		p.bisectRunCancel = cancel
+		p.bisectRunStart = time.Now()
+		workDir := p.repoRoot
+		return tea.Batch(
+			bisectRunTick(),
+			func() tea.Msg {
+				defer cancel()
+				output, err := RunBisect(ctx, workDir, command)
+				return BisectDoneMsg{Action: "run", Output: output, Err: err}
+			},
+		)
+	}
+	return nil
+}
+
+// cancelBisectRun stops the running test command. The bisect is reset once
+// it has stopped.
+func (p *Plugin) cancelBisectRun() {
+	if p.bisectRunCancel != nil {
+		p.bisectRunCancel()
+	}
+}
+
+// bisectRunTick schedules the next redraw of a running test command.
+func bisectRunTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return BisectRunTickMsg{}
+	})
+}
+
+func (p *Plugin) closeBisectRun() {
+	p.viewMode = ViewModeBisect
+	p.bisectRunError = ""
+	p.clearBisectRunModal()
+}
+
+func (p *Plugin) clearBisectRunModal() {
+	p.bisectRunModal = nil
+	p.bisectRunModalWidth = 0
+}
+
+// ensureBisectRunModal builds/rebuilds the test command prompt.
+func (p *Plugin) ensureBisectRunModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.bisectRunModal != nil && p.bisectRunModalWidth == modalW {
+		return
+	}
+	p.bisectRunModalWidth = modalW
+
+	p.bisectRunModal = modal.New("Run bisect automatically",
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(bisectRunID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.InputWithLabel(bisectCommandID, "Test command:", &p.bisectCommandInput, modal.WithSubmitAction(bisectRunID))).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := styles.Muted.Render("Runs at each step: exit 0 good, 125 skip, other codes bad.")
+			if p.bisectRunError != "" {
+				content += "\n\n" + styles.StatusDeleted.Render(p.bisectRunError)
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Run ", bisectRunID),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// renderBisectRun renders the test command prompt over the bisect view.
+func (p *Plugin) renderBisectRun() string {
+	background := p.renderBisect()
+
+	p.ensureBisectRunModal()
+	if p.bisectRunModal == nil {
+		return background
+	}
+
+	modalContent := p.bisectRunModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// renderBisect renders the full-screen bisect view: the commits left in range
+// with their marks on the left, the selected commit's diff on the right.
+func (p *Plugin) renderBisect() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+	listWidth := p.fileHistoryListWidth(contentWidth)
+	diffWidth := max(contentWidth-listWidth-3, 10)
+
+	lines := []string{
+		ansi.Truncate(p.renderBisectHeader(), contentWidth, "…"),
+		styles.Muted.Render(ansi.Truncate(bisectSummary(p.bisectOutput), contentWidth, "…")),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+	bodyHeight := max(paneHeight-len(lines)-1, 1)
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionBisectDiff, 2+listWidth+3, 1+len(lines), diffWidth, bodyHeight, nil)
+	p.mouseHandler.HitMap.AddRect(regionBisectList, 2, 1+len(lines), listWidth, bodyHeight, nil)
+
+	listLines := p.renderBisectList(listWidth, bodyHeight)
+	diffLines := p.renderBisectDiff(diffWidth, bodyHeight)
+	sep := styles.Muted.Render(" │ ")
+	for i := range bodyHeight {
+		left := ""
+		if i < len(listLines) {
+			left = listLines[i]
+		}
+		right := ""
+		if i < len(diffLines) {
+			right = diffLines[i]
+		}
+		lines = append(lines, padToWidth(left, listWidth)+sep+right)
+	}
+
+	footer := "g good  b bad  s skip  r run command  x reset  j/k commit  ctrl+d/u scroll diff  esc close"
+	switch {
+	case p.bisectRunCancel != nil:
+		elapsed := time.Since(p.bisectRunStart).Truncate(time.Second)
+		footer = fmt.Sprintf("Running %s · %s · esc/x cancel and reset", p.bisectCommand, elapsed)
+	case p.bisectRunning:
+		footer = "Bisecting..."
+	case p.bisect != nil && p.bisect.Culprit != "":
+		footer = "j/k commit  ctrl+d/u scroll diff  v view  y copy hash  x reset  esc close"
+	}
+	lines = append(lines, styles.Muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
+
+// renderBisectHeader summarizes the bisect: the culprit once found, otherwise
+// the commits left to test.
+func (p *Plugin) renderBisectHeader() string {
+	header := styles.Title.Render("Bisect")
+	s := p.bisect
+	switch {
+	case s == nil:
+		return header + styles.Muted.Render(" · loading...")
+	case s.Culprit != "":
+		short := s.Culprit[:min(len(s.Culprit), 7)]
+		return header + styles.Muted.Render(" · first bad commit ") + styles.StatusDeleted.Render(short)
+	case s.Bad == "" || len(s.Good) == 0:
+		return header + styles.Muted.Render(" · mark a good and a bad commit in the history")
+	}
+	return header + styles.Muted.Render(fmt.Sprintf(" · %d commit(s) left to test, roughly %d step(s) · %d good, %d skipped",
+		s.Remaining(), s.Steps(), len(s.Good), len(s.Skipped)))
+}
+
+// renderBisectList renders the visible rows of the range, drawn as a graph.
+func (p *Plugin) renderBisectList(width, height int) []string {
+	if p.bisect == nil {
+		return []string{styles.Muted.Render("Loading bisect...")}
+	}
+	commits := p.bisect.Candidates
+	if len(commits) == 0 {
+		return []string{styles.Muted.Render("No range yet")}
+	}
+
+	// Keep the cursor visible
+	if p.bisectCursor < p.bisectListScroll {
+		p.bisectListScroll = p.bisectCursor
+	}
+	if p.bisectCursor >= p.bisectListScroll+height {
+		p.bisectListScroll = p.bisectCursor - height + 1
+	}
+	p.bisectListScroll = min(p.bisectListScroll, max(len(commits)-height, 0))
+
+	graphWidth := 0
+	for _, gl := range p.bisectGraph {
+		graphWidth = max(graphWidth, gl.Width)
+	}
+	graphWidth = min(graphWidth, bisectGraphWidth)
+
+	var lines []string
+	end := min(p.bisectListScroll+height, len(commits))
+	for i := p.bisectListScroll; i < end; i++ {
+		lines = append(lines, p.renderBisectRow(i, graphWidth, width))
+	}
+	return lines
+}
+
+// renderBisectRow renders one commit in the range: graph, mark, hash and
+// subject.
+func (p *Plugin) renderBisectRow(idx, graphWidth, width int) string {
+	c := p.bisect.Candidates[idx]
+	markPlain, mark := p.bisectMarker(c.Hash)
+	if markPlain == "" {
+		markPlain, mark = " ", " "
+	}
+
+	var graphPlain, graph string
+	if idx < len(p.bisectGraph) && graphWidth > 0 {
+		graphPlain = p.renderGraphLinePlain(p.bisectGraph[idx], graphWidth)
+		graph = p.renderGraphLine(p.bisectGraph[idx], graphWidth)
+	}
+
+	prefix := graphPlain + markPlain + " " + c.ShortHash + " "
+	subject := ansi.Truncate(c.Subject, max(width-ansi.StringWidth(prefix), 5), "…")
+	if idx == p.bisectCursor {
+		return styles.ListItemSelected.Render(padToWidth(prefix+subject, width))
+	}
+	return graph + mark + " " + styles.Code.Render(c.ShortHash) + " " + subject
+}
+
+// renderBisectDiff renders the selected commit's header and diff.
+func (p *Plugin) renderBisectDiff(width, height int) []string {
+	c := p.selectedBisectCommit()
+	if c == nil {
+		return nil
+	}
+	header := styles.Code.Render(c.ShortHash) + " " + styles.Body.Render(c.Subject)
+	if c.Hash == p.bisect.Culprit {
+		header = styles.StatusDeleted.Render("First bad commit ") + header
+	}
+	meta := styles.Muted.Render(c.Author + " · " + RelativeTime(c.Date))
+	lines := []string{ansi.Truncate(header, width, "…"), ansi.Truncate(meta, width, "…"), ""}
+	bodyHeight := max(height-len(lines), 1)
+
+	switch {
+	case p.bisectDiffHash != c.Hash:
+		lines = append(lines, styles.Muted.Render("Loading diff..."))
+	case p.bisectDiff == nil || len(p.bisectDiff.Files) == 0:
+		text := "No changes"
+		if strings.HasPrefix(p.bisectDiffRaw, "Error: ") {
+			text = p.bisectDiffRaw
+		}
+		lines = append(lines, styles.Muted.Render(ansi.Truncate(text, width, "…")))
+	default:
+		lines = append(lines, p.renderCommitDiffLines(p.bisectDiff, &p.bisectDiffScroll, width, bodyHeight)...)
+	}
+	return lines
+}
@@ -66,6 +66,10 @@ func (p *Plugin) updateBranchPicker(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	case "enter":
 		// Switch to selected branch
 		return p, p.switchSelectedBranch()
+
+	case "c":
+		// Compare the selected branch with the current one
+		return p, p.compareSelectedBranch()
 	}
 
 	action, cmd := p.branchPickerModal.HandleKey(msg)
@@ -84,7 +88,9 @@ func (p *Plugin) updateBranchPicker(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 func (p *Plugin) doSwitchBranch(branchName string) tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		before := currentHead(workDir)
 		err := CheckoutBranch(workDir, branchName)
+		logOperation(workDir, "checkout "+branchName, before)
 		if err != nil {
 			return BranchErrorMsg{Err: err}
 		}
@@ -217,7 +223,7 @@ func (p *Plugin) branchPickerListUpdate(msg tea.Msg, focusID string) (string, te
 
 func (p *Plugin) branchPickerHintsSection() modal.Section {
 	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
-		return modal.RenderedSection{Content: styles.Muted.Render("  Enter to switch, j/k to navigate, Esc to cancel")}
+		return modal.RenderedSection{Content: styles.Muted.Render("  Enter to switch, c to compare, j/k to navigate, Esc to cancel")}
 	}, nil)
 }
 
@@ -262,6 +268,27 @@ func (p *Plugin) switchBranchByIndex(idx int) tea.Cmd {
 	return p.doSwitchBranch(branch.Name)
 }
 
+// compareSelectedBranch opens a comparison of the selected branch against
+// the current one. On the current branch it compares against the default
+// branch instead.
+func (p *Plugin) compareSelectedBranch() tea.Cmd {
+	if p.branchCursor < 0 || p.branchCursor >= len(p.branches) {
+		return nil
+	}
+	selected := p.branches[p.branchCursor]
+	base := ""
+	for _, b := range p.branches {
+		if b.IsCurrent {
+			base = b.Name
+		}
+	}
+	if selected.IsCurrent || base == "" {
+		base = DefaultBranch(p.repoRoot)
+	}
+	p.closeBranchPicker()
+	return p.openCompare(base, selected.Name)
+}
+
 func (p *Plugin) closeBranchPicker() {
 	p.viewMode = p.branchReturnMode
 	p.branches = nil
@@ -0,0 +1,552 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	commitActionInputID   = "commit-action-input"
+	commitActionResolveID = "commit-action-resolve"
+	commitActionConfirmID = "commit-action-confirm"
+	commitActionResetList = "commit-action-reset-mode"
+	commitActionMaxFiles  = 8
+)
+
+// commitActionKind identifies an action taken on a commit from the history list.
+type commitActionKind int
+
+const (
+	commitActionCherryPick commitActionKind = iota
+	commitActionRevert
+	commitActionReset
+)
+
+// String returns the git command name for the action.
+func (k commitActionKind) String() string {
+	switch k {
+	case commitActionRevert:
+		return "revert"
+	case commitActionReset:
+		return "reset"
+	default:
+		return "cherry-pick"
+	}
+}
+
+// title returns the capitalized display name for the action.
+func (k commitActionKind) title() string {
+	switch k {
+	case commitActionRevert:
+		return "Revert"
+	case commitActionReset:
+		return "Reset"
+	default:
+		return "Cherry-pick"
+	}
+}
+
+// resetModes lists reset modes in the order shown in the modal.
+var resetModes = []ResetMode{ResetSoft, ResetMixed, ResetHard}
+
+// CommitActionPreviewMsg carries the commits affected by a pending action.
+type CommitActionPreviewMsg struct {
+	Kind    commitActionKind
+	Commits []*Commit
+	Err     error
+}
+
+// CommitActionDoneMsg is sent when a cherry-pick, revert or reset finishes.
+type CommitActionDoneMsg struct {
+	Kind commitActionKind
+	Err  error
+}
+
+// SequencerAbortedMsg is sent when a conflicted cherry-pick or revert is aborted.
+type SequencerAbortedMsg struct {
+	Op  string
+	Err error
+}
+
+// openCherryPick opens the cherry-pick prompt. A cherry-pick or revert that
+// stopped on conflicts is shown again instead.
+func (p *Plugin) openCherryPick() tea.Cmd {
+	if p.showPendingSequencer() {
+		return nil
+	}
+	p.commitActionKind = commitActionCherryPick
+	p.commitActionInput = textinput.New()
+	p.commitActionInput.Placeholder = "commit, branch or main..agent-branch"
+	p.commitActionInput.Prompt = ""
+	p.commitActionInput.CharLimit = 200
+	p.commitActionInput.Width = 40
+	p.commitActionInput.SetValue(p.cherryPickSpec)
+	p.commitActionInput.CursorEnd()
+	p.commitActionInput.Focus()
+	p.commitActionError = ""
+	p.commitActionInputStage = true
+	p.commitActionCommits = nil
+	p.clearCommitActionModal()
+	p.viewMode = ViewModeCommitAction
+	return nil
+}
+
+// openRevert loads the selected commit and opens the revert confirmation.
+func (p *Plugin) openRevert() tea.Cmd {
+	if p.showPendingSequencer() {
+		return nil
+	}
+	target := p.selectedCommit()
+	if target == nil {
+		return nil
+	}
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		c, err := GetCommitDetail(workDir, target.Hash)
+		if err == nil && c == nil {
+			err = fmt.Errorf("could not read commit %s", target.ShortHash)
+		}
+		return CommitActionPreviewMsg{Kind: commitActionRevert, Commits: []*Commit{c}, Err: err}
+	}
+}
+
+// openReset loads the commits that a reset to the selected commit removes.
+func (p *Plugin) openReset() tea.Cmd {
+	target := p.selectedCommit()
+	if target == nil {
+		return nil
+	}
+	p.commitActionTarget = target
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		commits, err := GetCommitRange(workDir, target.Hash)
+		if err == nil {
+			PopulatePushStatus(commits, GetPushStatus(workDir))
+		}
+		return CommitActionPreviewMsg{Kind: commitActionReset, Commits: commits, Err: err}
+	}
+}
+
+// selectedCommit returns the commit under the sidebar cursor, if any.
+func (p *Plugin) selectedCommit() *Commit {
+	if !p.cursorOnCommit() {
+		return nil
+	}
+	commits := p.activeCommits()
+	idx := p.selectedCommitIndex()
+	if idx < 0 || idx >= len(commits) {
+		return nil
+	}
+	return commits[idx]
+}
+
+// resolveCherryPick resolves the prompt input into the commits to apply.
+func (p *Plugin) resolveCherryPick() tea.Cmd {
+	spec := strings.TrimSpace(p.commitActionInput.Value())
+	if spec == "" {
+		return nil
+	}
+	p.cherryPickSpec = spec
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		commits, err := ResolveCherryPickCommits(workDir, spec)
+		return CommitActionPreviewMsg{Kind: commitActionCherryPick, Commits: commits, Err: err}
+	}
+}
+
+// handleCommitActionPreview shows the confirmation modal for a loaded action.
+func (p *Plugin) handleCommitActionPreview(msg CommitActionPreviewMsg) tea.Cmd {
+	if msg.Err != nil {
+		if msg.Kind == commitActionCherryPick && p.viewMode == ViewModeCommitAction {
+			// Keep the prompt open so the input can be corrected
+			p.commitActionError = msg.Err.Error()
+			return nil
+		}
+		p.showErrorModal(msg.Kind.title()+" Failed", msg.Err)
+		return nil
+	}
+	if msg.Kind == commitActionCherryPick && p.viewMode != ViewModeCommitAction {
+		return nil // Prompt was closed while resolving
+	}
+	p.commitActionKind = msg.Kind
+	p.commitActionCommits = msg.Commits
+	p.commitActionInputStage = false
+	p.commitActionError = ""
+	p.resetModeIdx = 1 // Mixed, git's default
+	p.clearCommitActionModal()
+	p.viewMode = ViewModeCommitAction
+	return nil
+}
+
+// closeCommitAction closes the prompt or confirmation modal.
+func (p *Plugin) closeCommitAction() {
+	p.viewMode = ViewModeStatus
+	p.commitActionCommits = nil
+	p.commitActionTarget = nil
+	p.commitActionError = ""
+	p.clearCommitActionModal()
+}
+
+func (p *Plugin) clearCommitActionModal() {
+	p.commitActionModal = nil
+	p.commitActionModalWidth = 0
+}
+
+// updateCommitAction handles key events in the commit action modal.
+func (p *Plugin) updateCommitAction(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureCommitActionModal()
+	if p.commitActionModal == nil {
+		return p, nil
+	}
+
+	if !p.commitActionInputStage && msg.String() == "y" {
+		return p, p.runCommitAction()
+	}
+	if !p.commitActionInputStage && msg.String() == "q" {
+		p.closeCommitAction()
+		return p, nil
+	}
+
+	action, cmd := p.commitActionModal.HandleKey(msg)
+	return p, tea.Batch(cmd, p.handleCommitActionAction(action))
+}
+
+// handleCommitActionAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleCommitActionAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeCommitAction()
+	case commitActionResolveID, commitActionInputID:
+		return p.resolveCherryPick()
+	case commitActionConfirmID:
+		return p.runCommitAction()
+	}
+	return nil
+}
+
+// runCommitAction executes the confirmed action.
+func (p *Plugin) runCommitAction() tea.Cmd {
+	kind := p.commitActionKind
+	commits := p.commitActionCommits
+	target := p.commitActionTarget
+	mode := resetModes[p.resetModeIdx]
+	workDir := p.repoRoot
+	p.closeCommitAction()
+
+	return func() tea.Msg {
+		before := currentHead(workDir)
+		var err error
+		op := kind.String()
+		switch kind {
+		case commitActionCherryPick:
+			hashes := make([]string, len(commits))
+			for i, c := range commits {
+				hashes[i] = c.Hash
+			}
+			_, err = ExecuteCherryPick(workDir, hashes, len(commits) == 1 && commits[0].IsMerge)
+		case commitActionRevert:
+			_, err = ExecuteRevert(workDir, commits[0].Hash, commits[0].IsMerge)
+		case commitActionReset:
+			_, err = ExecuteReset(workDir, target.Hash, mode)
+			op += " --" + string(mode)
+		}
+		logOperation(workDir, op, before)
+		return CommitActionDoneMsg{Kind: kind, Err: err}
+	}
+}
+
+// handleCommitActionDone reports the outcome of an action. Conflicts leave
+// the cherry-pick or revert stopped and are shown in the error modal.
+func (p *Plugin) handleCommitActionDone(msg CommitActionDoneMsg) tea.Cmd {
+	refresh := tea.Batch(p.refresh(), p.loadRecentCommits())
+	if msg.Err != nil {
+		if !p.showPendingSequencer() {
+			p.showErrorModal(msg.Kind.title()+" Failed", msg.Err)
+		}
+		return refresh
+	}
+	var toast string
+	switch msg.Kind {
+	case commitActionCherryPick:
+		toast = "Cherry-pick complete"
+	case commitActionRevert:
+		toast = "Revert committed"
+	case commitActionReset:
+		toast = "Branch reset"
+	}
+	return tea.Batch(refresh, func() tea.Msg {
+		return app.ToastMsg{Message: toast, Duration: 2 * time.Second}
+	})
+}
+
+// showPendingSequencer opens the conflict error modal if a cherry-pick or
+// revert is stopped. Returns false when none is in progress.
+func (p *Plugin) showPendingSequencer() bool {
+	op := SequencerInProgress(p.repoRoot)
+	if op == "" {
+		return false
+	}
+	files := GetConflictedFiles(p.repoRoot)
+
+	var sb strings.Builder
+	if len(files) > 0 {
+		fmt.Fprintf(&sb, "%s stopped with conflicts in %d file(s):\n", strings.ToUpper(op[:1])+op[1:], len(files))
+		for _, f := range files {
+			sb.WriteString("  U " + f + "\n")
+		}
+	} else {
+		fmt.Fprintf(&sb, "A %s is in progress.\n", op)
+	}
+	sb.WriteString("\nResolve the conflicts (r), then continue (c) or abort (a).")
+
+	p.showErrorModal(strings.ToUpper(op[:1])+op[1:]+" Conflicts", fmt.Errorf("%s", sb.String()))
+	p.errorSequencerOp = op
+	return true
+}
+
+// doContinueSequencer continues a stopped cherry-pick or revert.
+func (p *Plugin) doContinueSequencer(op string) tea.Cmd {
+	workDir := p.repoRoot
+	kind := commitActionCherryPick
+	if op == "revert" {
+		kind = commitActionRevert
+	}
+	return func() tea.Msg {
+		before := pendingOrigHead(workDir)
+		_, err := ContinueSequencer(workDir, op)
+		logOperation(workDir, op, before)
+		return CommitActionDoneMsg{Kind: kind, Err: err}
+	}
+}
+
+// doAbortSequencer aborts a stopped cherry-pick or revert.
+func (p *Plugin) doAbortSequencer(op string) tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		return SequencerAbortedMsg{Op: op, Err: AbortSequencer(workDir, op)}
+	}
+}
+
+// commitActionModalWidthForContent returns the modal width for the screen size.
+func (p *Plugin) commitActionModalWidthForContent() int {
+	modalW := ui.ModalWidthMedium
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < 30 {
+		modalW = 30
+	}
+	return modalW
+}
+
+// ensureCommitActionModal builds/rebuilds the prompt or confirmation modal.
+func (p *Plugin) ensureCommitActionModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.commitActionModal != nil && p.commitActionModalWidth == modalW {
+		return
+	}
+	p.commitActionModalWidth = modalW
+
+	kind := p.commitActionKind
+	if p.commitActionInputStage {
+		p.commitActionModal = modal.New("Cherry-pick",
+			modal.WithWidth(modalW),
+			modal.WithPrimaryAction(commitActionResolveID),
+			modal.WithHints(false),
+		).
+			AddSection(modal.Text("Apply commits from another branch or worktree.")).
+			AddSection(modal.Spacer()).
+			AddSection(modal.InputWithLabel(commitActionInputID, "Commit or range:", &p.commitActionInput, modal.WithSubmitAction(commitActionResolveID))).
+			AddSection(p.commitActionErrorSection()).
+			AddSection(modal.Spacer()).
+			AddSection(modal.Buttons(
+				modal.Btn(" Preview ", commitActionResolveID),
+				modal.Btn(" Cancel ", "cancel"),
+			))
+		return
+	}
+
+	opts := []modal.Option{
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(commitActionConfirmID),
+		modal.WithHints(false),
+	}
+	confirmBtn := modal.Btn(" "+kind.title()+" ", commitActionConfirmID)
+	if kind == commitActionReset {
+		opts = append(opts, modal.WithVariant(modal.VariantDanger))
+		confirmBtn = modal.Btn(" Reset ", commitActionConfirmID, modal.BtnDanger())
+	}
+
+	m := modal.New(p.commitActionTitle(), opts...)
+	if kind == commitActionReset {
+		items := []modal.ListItem{
+			{ID: string(ResetSoft), Label: "Soft (keep changes staged)"},
+			{ID: string(ResetMixed), Label: "Mixed (keep changes unstaged)"},
+			{ID: string(ResetHard), Label: "Hard (discard all changes)"},
+		}
+		m.AddSection(modal.List(commitActionResetList, items, &p.resetModeIdx, modal.WithMaxVisible(len(items)))).
+			AddSection(modal.Spacer())
+	}
+	p.commitActionModal = m.
+		AddSection(p.commitActionSummarySection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(confirmBtn, modal.Btn(" Cancel ", "cancel")))
+}
+
+// commitActionTitle returns the confirmation modal title.
+func (p *Plugin) commitActionTitle() string {
+	switch p.commitActionKind {
+	case commitActionRevert:
+		return "Revert " + p.commitActionCommits[0].ShortHash
+	case commitActionReset:
+		return "Reset to " + p.commitActionTarget.ShortHash
+	default:
+		return "Cherry-pick"
+	}
+}
+
+func (p *Plugin) commitActionErrorSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.commitActionError == "" {
+			return modal.RenderedSection{}
+		}
+		return modal.RenderedSection{Content: "\n" + styles.StatusDeleted.Render(ansi.Truncate(p.commitActionError, contentWidth, "…"))}
+	}, nil)
+}
+
+// commitActionSummarySection describes what the pending action will change.
+func (p *Plugin) commitActionSummarySection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		var sb strings.Builder
+		switch p.commitActionKind {
+		case commitActionCherryPick:
+			fmt.Fprintf(&sb, "%s\n", styles.Muted.Render(fmt.Sprintf("Apply %d commit(s) onto the current branch:", len(p.commitActionCommits))))
+			writeCommitLines(&sb, p.commitActionCommits, contentWidth)
+			if len(p.commitActionCommits) == 1 && p.commitActionCommits[0].IsMerge {
+				sb.WriteString(styles.Muted.Render("Merge commit: picked against its first parent.") + "\n")
+			}
+			sb.WriteString("\n")
+			writeCommitFiles(&sb, p.commitActionCommits, contentWidth)
+		case commitActionRevert:
+			c := p.commitActionCommits[0]
+			fmt.Fprintf(&sb, "%s\n", styles.Muted.Render("Create a new commit undoing:"))
+			writeCommitLines(&sb, p.commitActionCommits, contentWidth)
+			if c.IsMerge {
+				sb.WriteString(styles.Muted.Render("Merge commit: reverted against its first parent.") + "\n")
+			}
+			sb.WriteString("\n")
+			writeCommitFiles(&sb, p.commitActionCommits, contentWidth)
+		case commitActionReset:
+			p.writeResetSummary(&sb, contentWidth)
+		}
+		return modal.RenderedSection{Content: strings.TrimRight(sb.String(), "\n")}
+	}, nil)
+}
+
+// writeResetSummary describes the commits and changes a reset affects.
+func (p *Plugin) writeResetSummary(sb *strings.Builder, width int) {
+	removed := p.commitActionCommits
+	mode := resetModes[p.resetModeIdx]
+
+	if len(removed) == 0 {
+		sb.WriteString(styles.Muted.Render("No commits will be removed.") + "\n")
+	} else {
+		fmt.Fprintf(sb, "%s\n", styles.Muted.Render(fmt.Sprintf("%d commit(s) will be removed from the branch:", len(removed))))
+		writeCommitLines(sb, removed, width)
+	}
+
+	pushed := 0
+	for _, c := range removed {
+		if c.Pushed {
+			pushed++
+		}
+	}
+	if pushed > 0 {
+		sb.WriteString(styles.StatusModified.Render(fmt.Sprintf("%d of them are pushed; pushing afterwards needs --force.", pushed)) + "\n")
+	}
+
+	sb.WriteString("\n")
+	switch mode {
+	case ResetSoft:
+		sb.WriteString(styles.Muted.Render("Their changes stay staged."))
+	case ResetMixed:
+		sb.WriteString(styles.Muted.Render("Their changes stay in the working tree, unstaged."))
+	case ResetHard:
+		msg := "Their changes are discarded"
+		if n := p.tree.TotalCount(); n > 0 {
+			msg += fmt.Sprintf(", along with uncommitted changes in %d file(s)", n)
+		}
+		sb.WriteString(styles.StatusDeleted.Render(msg + ". This cannot be undone."))
+	}
+}
+
+// writeCommitLines writes one "hash subject" line per commit, capped at
+// commitActionMaxFiles lines.
+func writeCommitLines(sb *strings.Builder, commits []*Commit, width int) {
+	for i, c := range commits {
+		if i >= commitActionMaxFiles {
+			sb.WriteString(styles.Muted.Render(fmt.Sprintf("  ... and %d more", len(commits)-i)) + "\n")
+			break
+		}
+		line := ansi.Truncate(c.Subject, max(width-len(c.ShortHash)-3, 10), "…")
+		sb.WriteString("  " + styles.Code.Render(c.ShortHash) + " " + line + "\n")
+	}
+}
+
+// writeCommitFiles writes the combined file list of commits with stats.
+func writeCommitFiles(sb *strings.Builder, commits []*Commit, width int) {
+	type fileStat struct{ adds, dels int }
+	var order []string
+	stats := make(map[string]*fileStat)
+	for _, c := range commits {
+		for _, f := range c.Files {
+			s, ok := stats[f.Path]
+			if !ok {
+				s = &fileStat{}
+				stats[f.Path] = s
+				order = append(order, f.Path)
+			}
+			s.adds += f.Additions
+			s.dels += f.Deletions
+		}
+	}
+	if len(order) == 0 {
+		sb.WriteString(styles.Muted.Render("No file changes.") + "\n")
+		return
+	}
+
+	fmt.Fprintf(sb, "%s\n", styles.Muted.Render(fmt.Sprintf("Files (%d):", len(order))))
+	for i, path := range order {
+		if i >= commitActionMaxFiles {
+			sb.WriteString(styles.Muted.Render(fmt.Sprintf("  ... and %d more", len(order)-i)) + "\n")
+			break
+		}
+		s := stats[path]
+		stat := fmt.Sprintf("+%d -%d", s.adds, s.dels)
+		path = ansi.Truncate(path, max(width-len(stat)-3, 10), "…")
+		sb.WriteString("  " + path + " " + styles.DiffAdd.Render(fmt.Sprintf("+%d", s.adds)) + " " + styles.DiffRemove.Render(fmt.Sprintf("-%d", s.dels)) + "\n")
+	}
+}
+
+// renderCommitAction renders the commit action modal.
+func (p *Plugin) renderCommitAction() string {
+	background := p.renderThreePaneView()
+
+	p.ensureCommitActionModal()
+	if p.commitActionModal == nil {
+		return background
+	}
+
+	modalContent := p.commitActionModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
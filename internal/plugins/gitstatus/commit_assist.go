@@ -0,0 +1,372 @@
+package gitstatus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxSubjectLength is the longest commit subject the linter accepts.
+const maxSubjectLength = 72
+
+// isConventionalType reports whether t is a known conventional commit type.
+// The known types are the ones grouped into release note sections.
+func isConventionalType(t string) bool {
+	for _, section := range releaseSections {
+		for _, known := range section.Types {
+			if t == known {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func conventionalTypes() []string {
+	var types []string
+	for _, section := range releaseSections {
+		types = append(types, section.Types...)
+	}
+	return types
+}
+
+var scopeRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+// LintCommitMessage checks a commit message against the Conventional Commits
+// format and returns one line per problem, or nil when the message is clean.
+func LintCommitMessage(message string) []string {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil
+	}
+	lines := strings.Split(message, "\n")
+	subject := strings.TrimSpace(lines[0])
+
+	var issues []string
+	cc, ok := ParseConventionalCommit(subject)
+	switch {
+	case !ok:
+		issues = append(issues, "Subject should be \"type(scope): description\"")
+	default:
+		if !isConventionalType(cc.Type) {
+			issues = append(issues, fmt.Sprintf("Unknown type %q (use %s)", cc.Type, strings.Join(conventionalTypes(), ", ")))
+		}
+		if strings.Contains(subject, "()") {
+			issues = append(issues, "Scope is empty; drop the parentheses")
+		} else if cc.Scope != "" && !scopeRe.MatchString(cc.Scope) {
+			issues = append(issues, fmt.Sprintf("Scope %q should be lowercase without spaces", cc.Scope))
+		}
+		if r := []rune(cc.Description); len(r) > 0 && unicode.IsUpper(r[0]) {
+			issues = append(issues, "Description should start with a lowercase letter")
+		}
+		if strings.HasSuffix(cc.Description, ".") {
+			issues = append(issues, "Description should not end with a period")
+		}
+	}
+	if n := len([]rune(subject)); n > maxSubjectLength {
+		issues = append(issues, fmt.Sprintf("Subject is %d characters (max %d)", n, maxSubjectLength))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		issues = append(issues, "Separate the subject from the body with a blank line")
+	}
+	return issues
+}
+
+// GetCommitTemplate returns the contents of the repository's commit.template,
+// or "" when none is configured or the file can't be read.
+func GetCommitTemplate(workDir string) string {
+	cmd := exec.Command("git", "config", "--path", "commit.template")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(output))
+	if path == "" {
+		return ""
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(data), "\n")
+}
+
+// stripCommentLines removes the "#" lines git strips from an edited message,
+// so guidance comments in a commit template don't end up in the commit.
+func stripCommentLines(message string) string {
+	lines := strings.Split(message, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+var trailerRe = regexp.MustCompile(`^[A-Za-z0-9-]+: `)
+
+// trailerBlockStart returns the index of the first line of a message's
+// trailer block: its last paragraph, when that isn't the subject and every
+// line in it is a "Key: value" trailer. It returns len(lines) when there is
+// no trailer block.
+func trailerBlockStart(lines []string) int {
+	start := len(lines)
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == 0 || start == len(lines) {
+		return len(lines)
+	}
+	for _, line := range lines[start:] {
+		if !trailerRe.MatchString(line) {
+			return len(lines)
+		}
+	}
+	return start
+}
+
+// messageTrailers returns the trailer block of a commit message, or "".
+func messageTrailers(message string) string {
+	lines := strings.Split(strings.TrimRight(message, "\n "), "\n")
+	return strings.Join(lines[trailerBlockStart(lines):], "\n")
+}
+
+// AddTrailer appends "key: value" to a commit message's trailer block,
+// starting the block after a blank line. A trailer already present is not
+// repeated.
+func AddTrailer(message, key, value string) string {
+	trailer := key + ": " + value
+	message = strings.TrimRight(message, "\n ")
+	if message == "" {
+		return "\n\n" + trailer
+	}
+
+	lines := strings.Split(message, "\n")
+	for _, line := range lines {
+		if strings.EqualFold(strings.TrimSpace(line), trailer) {
+			return message
+		}
+	}
+	if trailerBlockStart(lines) < len(lines) {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+// LinkedTaskID returns the td task linked to a workspace worktree, or "".
+func LinkedTaskID(workDir string) string {
+	data, err := os.ReadFile(filepath.Join(workDir, ".sidecar-task"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// GetRecentCoAuthors returns up to limit "Name <email>" identities of other
+// recent authors, most active first, as Co-authored-by candidates.
+func GetRecentCoAuthors(workDir string, limit int) []string {
+	cmd := exec.Command("git", "log", "-n", "300", "--format=%an <%ae>")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	self := ""
+	cmd = exec.Command("git", "config", "user.email")
+	cmd.Dir = workDir
+	if out, err := cmd.Output(); err == nil && strings.TrimSpace(string(out)) != "" {
+		self = "<" + strings.TrimSpace(string(out)) + ">"
+	}
+
+	counts := make(map[string]int)
+	var order []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || (self != "" && strings.HasSuffix(line, self)) {
+			continue
+		}
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	return order
+}
+
+// PrintModeAgent is an agent CLI that can answer a prompt non-interactively.
+type PrintModeAgent struct {
+	Type    string // Agent type as stored in .sidecar-agent (e.g. "claude")
+	Command string
+	Flag    string
+}
+
+var printModeAgents = make(map[string]PrintModeAgent)
+
+// RegisterPrintModeAgent registers an agent that can draft commit messages.
+// The workspace plugin registers the agents in its PrintModeFlags.
+func RegisterPrintModeAgent(agentType, command, flag string) {
+	if command == "" || flag == "" {
+		return
+	}
+	printModeAgents[agentType] = PrintModeAgent{Type: agentType, Command: command, Flag: flag}
+}
+
+// findPrintModeAgent picks the agent for commit message generation: the one
+// chosen for this workspace worktree, else the first installed agent.
+func findPrintModeAgent(workDir string) (PrintModeAgent, bool) {
+	if data, err := os.ReadFile(filepath.Join(workDir, ".sidecar-agent")); err == nil {
+		if agent, ok := printModeAgents[strings.TrimSpace(string(data))]; ok {
+			if _, err := exec.LookPath(agent.Command); err == nil {
+				return agent, true
+			}
+		}
+	}
+
+	types := make([]string, 0, len(printModeAgents))
+	for t := range printModeAgents {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		agent := printModeAgents[t]
+		if _, err := exec.LookPath(agent.Command); err == nil {
+			return agent, true
+		}
+	}
+	return PrintModeAgent{}, false
+}
+
+// GenerateCommitMessage drafts a commit message for the staged changes with
+// an agent CLI in print mode. Without an installed agent, or when the agent
+// fails, it returns a basic message built from the staged file names; agent
+// is "" in that case and err explains an agent failure.
+func GenerateCommitMessage(ctx context.Context, workDir string, conventional bool) (message, agent string, err error) {
+	files := stagedFileNames(workDir)
+	fallback := buildFallbackCommitMessage(files, conventional)
+
+	a, ok := findPrintModeAgent(workDir)
+	if !ok {
+		return fallback, "", nil
+	}
+
+	prompt := buildCommitPrompt(stagedDiff(workDir, "--stat"), stagedDiff(workDir), conventional)
+
+	// Pipe the prompt via stdin to avoid argument length limits on large diffs
+	cmd := exec.CommandContext(ctx, a.Command, a.Flag)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(prompt)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fallback, "", fmt.Errorf("agent %s failed: %w", a.Command, err)
+	}
+
+	message = parseCommitGenerationOutput(stdout.String())
+	if message == "" {
+		return fallback, "", fmt.Errorf("agent %s returned no message", a.Command)
+	}
+	return message, a.Type, nil
+}
+
+func stagedFileNames(workDir string) []string {
+	out := stagedDiff(workDir, "--name-only")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+func stagedDiff(workDir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"diff", "--cached"}, args...)...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// buildCommitPrompt constructs the prompt sent to the agent for commit
+// message generation.
+func buildCommitPrompt(diffStat, diff string, conventional bool) string {
+	// Truncate diff if too large to avoid token limits
+	const maxDiffLen = 30000
+	if len(diff) > maxDiffLen {
+		diff = diff[:maxDiffLen] + "\n\n... (diff truncated)"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Write a git commit message for the following staged changes. ")
+	sb.WriteString("Use an imperative subject line of at most ")
+	sb.WriteString(fmt.Sprint(maxSubjectLength))
+	sb.WriteString(" characters, then a blank line and a short body explaining why, if the change needs one.\n")
+	if conventional {
+		sb.WriteString("The subject must follow Conventional Commits: \"type(scope): description\" with a lowercase description and one of these types: ")
+		sb.WriteString(strings.Join(conventionalTypes(), ", "))
+		sb.WriteString(".\n")
+	}
+	sb.WriteString("\n## Files Changed\n")
+	sb.WriteString(diffStat)
+	sb.WriteString("\n\n## Diff\n")
+	sb.WriteString(diff)
+	sb.WriteString("\n\n---\n")
+	sb.WriteString("Output EXACTLY in this format with no extra text before it:\n\n")
+	sb.WriteString("COMMIT_MESSAGE:\n")
+	sb.WriteString("<subject>\n\n<body>\n")
+	return sb.String()
+}
+
+// parseCommitGenerationOutput extracts the message after the COMMIT_MESSAGE
+// marker, or the whole output when the agent left the marker out.
+func parseCommitGenerationOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if idx := strings.Index(output, "COMMIT_MESSAGE:"); idx >= 0 {
+		output = strings.TrimSpace(output[idx+len("COMMIT_MESSAGE:"):])
+	}
+	// Agents sometimes wrap the message in a code fence
+	if strings.HasPrefix(output, "```") {
+		output = strings.TrimPrefix(output, "```")
+		if nl := strings.Index(output, "\n"); nl >= 0 {
+			output = output[nl+1:]
+		}
+		output = strings.TrimSuffix(strings.TrimSpace(output), "```")
+	}
+	return strings.TrimSpace(output)
+}
+
+// buildFallbackCommitMessage drafts a subject from the staged file names.
+func buildFallbackCommitMessage(files []string, conventional bool) string {
+	var subject string
+	switch len(files) {
+	case 0:
+		subject = "update files"
+	case 1:
+		subject = "update " + filepath.Base(files[0])
+	case 2:
+		subject = "update " + filepath.Base(files[0]) + " and " + filepath.Base(files[1])
+	default:
+		subject = fmt.Sprintf("update %s and %d other files", filepath.Base(files[0]), len(files)-1)
+	}
+	if conventional {
+		return "chore: " + subject
+	}
+	return strings.ToUpper(subject[:1]) + subject[1:]
+}
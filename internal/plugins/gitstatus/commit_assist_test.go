@@ -0,0 +1,130 @@
+package gitstatus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintCommitMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string // Substring of the first issue; "" for a clean message
+	}{
+		{"feat(parser): add streaming mode", ""},
+		{"fix: handle empty input\n\nThe parser panicked on empty files.", ""},
+		{"Add streaming mode", "type(scope): description"},
+		{"feature: add streaming mode", "Unknown type"},
+		{"feat(): add streaming mode", "Scope is empty"},
+		{"feat(Parser Core): add streaming mode", "lowercase without spaces"},
+		{"feat: Add streaming mode", "lowercase letter"},
+		{"feat: add streaming mode.", "period"},
+		{"feat: " + strings.Repeat("x", 80), "characters (max 72)"},
+		{"feat: add streaming mode\nno blank line", "blank line"},
+	}
+	for _, tt := range tests {
+		issues := LintCommitMessage(tt.message)
+		if tt.want == "" {
+			if len(issues) != 0 {
+				t.Errorf("LintCommitMessage(%q) = %v, want none", tt.message, issues)
+			}
+			continue
+		}
+		if len(issues) == 0 || !strings.Contains(issues[0], tt.want) {
+			t.Errorf("LintCommitMessage(%q) = %v, want %q", tt.message, issues, tt.want)
+		}
+	}
+}
+
+func TestAddTrailer(t *testing.T) {
+	tests := []struct {
+		message, want string
+	}{
+		{"fix: bug", "fix: bug\n\nRefs: td-1"},
+		{"fix: bug\n\nLonger body.", "fix: bug\n\nLonger body.\n\nRefs: td-1"},
+		{"fix: bug\n\nCo-authored-by: A <a@x>", "fix: bug\n\nCo-authored-by: A <a@x>\nRefs: td-1"},
+		{"fix: bug\n\nRefs: td-1\n", "fix: bug\n\nRefs: td-1"},
+		{"", "\n\nRefs: td-1"},
+	}
+	for _, tt := range tests {
+		if got := AddTrailer(tt.message, "Refs", "td-1"); got != tt.want {
+			t.Errorf("AddTrailer(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+	if got := messageTrailers("fix: bug\n\nbody\n\nRefs: td-1\nCo-authored-by: A <a@x>"); got != "Refs: td-1\nCo-authored-by: A <a@x>" {
+		t.Errorf("messageTrailers = %q", got)
+	}
+	if got := messageTrailers("Refs: not a trailer block"); got != "" {
+		t.Errorf("messageTrailers(subject) = %q, want empty", got)
+	}
+}
+
+func TestGetCommitTemplate(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	if got := GetCommitTemplate(dir); got != "" {
+		t.Fatalf("template without config = %q", got)
+	}
+
+	tmpl := "\n\n# Explain why\nRefs: \n"
+	if err := os.WriteFile(filepath.Join(dir, ".gitmessage"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "config", "commit.template", ".gitmessage")
+	got := GetCommitTemplate(dir)
+	if got != strings.TrimRight(tmpl, "\n") {
+		t.Fatalf("template = %q", got)
+	}
+	if stripped := stripCommentLines("fix: bug\n" + got); stripped != "fix: bug\n\n\nRefs:" {
+		t.Fatalf("stripped = %q", stripped)
+	}
+}
+
+func TestGetRecentCoAuthors(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	gitRun(t, dir, "-c", "user.name=Ada", "-c", "user.email=ada@example.com", "commit", "--allow-empty", "-m", "one")
+	gitRun(t, dir, "-c", "user.name=Bob", "-c", "user.email=bob@example.com", "commit", "--allow-empty", "-m", "two")
+	gitRun(t, dir, "-c", "user.name=Bob", "-c", "user.email=bob@example.com", "commit", "--allow-empty", "-m", "three")
+
+	got := GetRecentCoAuthors(dir, 5)
+	want := []string{"Bob <bob@example.com>", "Ada <ada@example.com>"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("co-authors = %v, want %v (the current user excluded)", got, want)
+	}
+}
+
+func TestGenerateCommitMessageFallback(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "file.txt")
+
+	// No agents are registered without the workspace plugin
+	message, agent, err := GenerateCommitMessage(context.Background(), dir, true)
+	if err != nil || agent != "" {
+		t.Fatalf("agent = %q, err = %v; want fallback", agent, err)
+	}
+	if message != "chore: update file.txt" {
+		t.Fatalf("message = %q", message)
+	}
+	if got := buildFallbackCommitMessage([]string{"a/x.go", "b.go", "c.go"}, false); got != "Update x.go and 2 other files" {
+		t.Fatalf("fallback = %q", got)
+	}
+}
+
+func TestParseCommitGenerationOutput(t *testing.T) {
+	tests := []struct {
+		output, want string
+	}{
+		{"COMMIT_MESSAGE:\nfix: bug\n\nBody.", "fix: bug\n\nBody."},
+		{"Sure!\nCOMMIT_MESSAGE:\n```\nfix: bug\n```", "fix: bug"},
+		{"fix: bug", "fix: bug"},
+	}
+	for _, tt := range tests {
+		if got := parseCommitGenerationOutput(tt.output); got != tt.want {
+			t.Errorf("parseCommitGenerationOutput(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package gitstatus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const commitTrailerPrefix = "commit-trailer-"
+
+func commitTrailerID(idx int) string {
+	return fmt.Sprintf("%s%d", commitTrailerPrefix, idx)
+}
+
+func parseCommitTrailer(id string) (int, bool) {
+	if !strings.HasPrefix(id, commitTrailerPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, commitTrailerPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// commitTrailer is a trailer offered by the trailer helper.
+type commitTrailer struct {
+	Key   string
+	Value string
+}
+
+// CommitMessageGeneratedMsg carries a drafted commit message.
+type CommitMessageGeneratedMsg struct {
+	Epoch   uint64
+	Message string
+	Agent   string // Agent that drafted the message; "" for the fallback
+	Err     error  // Agent failure; Message holds the fallback
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m CommitMessageGeneratedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// resetCommitAssist clears the assistant state for a new commit modal.
+func (p *Plugin) resetCommitAssist() {
+	p.cancelCommitGeneration()
+	p.commitTemplate = ""
+	p.commitNotice = ""
+	p.commitLintOverride = ""
+}
+
+// prefillCommitTemplate fills an empty commit message with commit.template.
+func (p *Plugin) prefillCommitTemplate() {
+	tmpl := GetCommitTemplate(p.repoRoot)
+	if tmpl == "" {
+		return
+	}
+	p.commitTemplate = tmpl
+	p.commitMessage.SetValue(tmpl)
+	// Put the cursor on the first line, where the subject goes
+	for p.commitMessage.Line() > 0 {
+		p.commitMessage.CursorUp()
+	}
+	p.commitMessage.CursorStart()
+}
+
+// commitMessageText returns the message as it will be committed, without
+// the comment lines of a commit template.
+func (p *Plugin) commitMessageText() string {
+	message := p.commitMessage.Value()
+	if p.commitTemplate != "" {
+		return stripCommentLines(message)
+	}
+	return strings.TrimSpace(message)
+}
+
+// checkCommitMessage returns why a message can't be committed yet, or "".
+// Lint problems block the first attempt only, so a second ctrl+s commits
+// anyway.
+func (p *Plugin) checkCommitMessage(message string) string {
+	if message == "" {
+		return "Commit message cannot be empty"
+	}
+	if p.commitTemplate != "" && message == stripCommentLines(p.commitTemplate) {
+		return "Commit message is unchanged from the template"
+	}
+	if !p.commitLint || p.commitLintOverride == message {
+		return ""
+	}
+	if issues := LintCommitMessage(message); len(issues) > 0 {
+		p.commitLintOverride = message
+		return issues[0] + " (commit again to ignore)"
+	}
+	return ""
+}
+
+// toggleCommitLint turns conventional commit linting on or off.
+func (p *Plugin) toggleCommitLint() {
+	p.commitLint = !p.commitLint
+	p.commitLintOverride = ""
+	if !p.commitLint {
+		p.commitError = ""
+	}
+}
+
+// generateCommitMessage drafts a message for the staged changes.
+func (p *Plugin) generateCommitMessage() tea.Cmd {
+	if p.commitGenerating {
+		return nil
+	}
+	p.commitGenerating = true
+	p.commitNotice = ""
+	p.commitError = ""
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	p.commitGenerateCancel = cancel
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	conventional := p.commitLint
+	return func() tea.Msg {
+		defer cancel()
+		message, agent, err := GenerateCommitMessage(ctx, workDir, conventional)
+		return CommitMessageGeneratedMsg{Epoch: epoch, Message: message, Agent: agent, Err: err}
+	}
+}
+
+// cancelCommitGeneration stops a running agent.
+func (p *Plugin) cancelCommitGeneration() {
+	if p.commitGenerateCancel != nil {
+		p.commitGenerateCancel()
+		p.commitGenerateCancel = nil
+	}
+	p.commitGenerating = false
+}
+
+// handleCommitMessageGenerated replaces the message with the draft, keeping
+// trailers already added.
+func (p *Plugin) handleCommitMessageGenerated(m CommitMessageGeneratedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeCommit || !p.commitGenerating {
+		return nil
+	}
+	p.commitGenerating = false
+	p.commitGenerateCancel = nil
+
+	message := m.Message
+	if trailers := messageTrailers(p.commitMessageText()); trailers != "" {
+		for _, line := range strings.Split(trailers, "\n") {
+			key, value, _ := strings.Cut(line, ": ")
+			message = AddTrailer(message, key, value)
+		}
+	}
+	p.commitMessage.SetValue(message)
+	p.commitTemplate = ""
+	p.commitLintOverride = ""
+
+	switch {
+	case m.Agent != "":
+		p.commitNotice = "Drafted by " + m.Agent + "; review before committing"
+	case m.Err != nil:
+		p.commitNotice = m.Err.Error() + "; drafted from the staged file names"
+	default:
+		p.commitNotice = "No agent CLI installed; drafted from the staged file names"
+	}
+	return nil
+}
+
+// commitLintSection shows lint results for the message as it is typed.
+func (p *Plugin) commitLintSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if !p.commitLint {
+			return modal.RenderedSection{}
+		}
+		message := p.commitMessageText()
+		if message == "" {
+			return modal.RenderedSection{Content: styles.Muted.Render("type(scope): description")}
+		}
+
+		subject := strings.SplitN(message, "\n", 2)[0]
+		count := fmt.Sprintf("%d/%d", len([]rune(subject)), maxSubjectLength)
+		issues := LintCommitMessage(message)
+		if len(issues) == 0 {
+			return modal.RenderedSection{Content: styles.StatusStaged.Render("✓ Conventional commit") + " " + styles.Muted.Render(count)}
+		}
+
+		lines := make([]string, 0, 3)
+		for i, issue := range issues {
+			if i == 2 {
+				lines = append(lines, styles.Muted.Render(fmt.Sprintf("  +%d more", len(issues)-i)))
+				break
+			}
+			line := "! " + issue
+			if i == 0 {
+				line += "  " + count
+			}
+			lines = append(lines, styles.StatusModified.Render(ansi.Truncate(line, contentWidth, "…")))
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+// commitHintsSection lists the assistant shortcuts.
+func (p *Plugin) commitHintsSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		lint := "off"
+		if p.commitLint {
+			lint = "on"
+		}
+		hints := "ctrl+g draft  ctrl+t trailer  ctrl+l lint: " + lint
+		return modal.RenderedSection{Content: styles.Muted.Render(ansi.Truncate(hints, contentWidth, "…"))}
+	}, nil)
+}
+
+// openCommitTrailers opens the trailer helper: an issue reference for the
+// linked td task and Co-authored-by lines for recent authors.
+func (p *Plugin) openCommitTrailers() tea.Cmd {
+	var trailers []commitTrailer
+	if task := LinkedTaskID(p.repoRoot); task != "" {
+		trailers = append(trailers, commitTrailer{Key: "Refs", Value: task})
+	}
+	for _, author := range GetRecentCoAuthors(p.repoRoot, 8) {
+		trailers = append(trailers, commitTrailer{Key: "Co-authored-by", Value: author})
+	}
+	if len(trailers) == 0 {
+		return msg.ShowToast("No linked task or recent co-authors", 2*time.Second)
+	}
+
+	p.commitTrailers = trailers
+	p.commitTrailerCursor = 0
+	p.clearCommitTrailerModal()
+	p.viewMode = ViewModeCommitTrailers
+	return nil
+}
+
+// updateCommitTrailers handles key events in the trailer helper.
+func (p *Plugin) updateCommitTrailers(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureCommitTrailerModal()
+	if p.commitTrailerModal == nil {
+		return p, nil
+	}
+
+	switch m.String() {
+	case "j", "down":
+		p.commitTrailerCursor = min(p.commitTrailerCursor+1, len(p.commitTrailers)-1)
+		return p, nil
+	case "k", "up":
+		p.commitTrailerCursor = max(p.commitTrailerCursor-1, 0)
+		return p, nil
+	case "enter":
+		return p, p.handleCommitTrailerAction(commitTrailerID(p.commitTrailerCursor))
+	}
+
+	action, cmd := p.commitTrailerModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleCommitTrailerAction(action))
+}
+
+// handleCommitTrailerAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleCommitTrailerAction(action string) tea.Cmd {
+	if action == "cancel" {
+		p.closeCommitTrailers()
+		return nil
+	}
+	if idx, ok := parseCommitTrailer(action); ok && idx < len(p.commitTrailers) {
+		t := p.commitTrailers[idx]
+		p.commitMessage.SetValue(AddTrailer(p.commitMessage.Value(), t.Key, t.Value))
+		p.closeCommitTrailers()
+	}
+	return nil
+}
+
+func (p *Plugin) closeCommitTrailers() {
+	p.viewMode = ViewModeCommit
+	p.commitTrailers = nil
+	p.clearCommitTrailerModal()
+}
+
+func (p *Plugin) clearCommitTrailerModal() {
+	p.commitTrailerModal = nil
+	p.commitTrailerModalWidth = 0
+}
+
+// ensureCommitTrailerModal builds/rebuilds the trailer helper.
+func (p *Plugin) ensureCommitTrailerModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.commitTrailerModal != nil && p.commitTrailerModalWidth == modalW {
+		return
+	}
+	p.commitTrailerModalWidth = modalW
+
+	p.commitTrailerModal = modal.New("Add Trailer",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			var sb strings.Builder
+			focusables := make([]modal.FocusableInfo, 0, len(p.commitTrailers))
+			for i, t := range p.commitTrailers {
+				itemID := commitTrailerID(i)
+				text := ansi.Truncate(t.Key+": "+t.Value, contentWidth-2, "…")
+				line := styles.ListItemNormal.Render("  " + styles.Muted.Render(t.Key+":") + strings.TrimPrefix(text, t.Key+":"))
+				if i == p.commitTrailerCursor || itemID == hoverID {
+					line = styles.ListItemSelected.Render("  " + text)
+				}
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				sb.WriteString(line)
+				focusables = append(focusables, modal.FocusableInfo{
+					ID:      itemID,
+					OffsetY: i,
+					Width:   ansi.StringWidth(line),
+					Height:  1,
+				})
+			}
+			return modal.RenderedSection{Content: sb.String(), Focusables: focusables}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text(styles.Muted.Render("Enter add, Esc cancel")))
+}
+
+// renderCommitTrailers renders the trailer helper over the commit modal.
+func (p *Plugin) renderCommitTrailers() string {
+	background := p.renderCommitModal()
+
+	p.ensureCommitTrailerModal()
+	if p.commitTrailerModal == nil {
+		return background
+	}
+
+	modalContent := p.commitTrailerModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
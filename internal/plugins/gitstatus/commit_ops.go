@@ -0,0 +1,154 @@
+package gitstatus
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ResetMode is the mode passed to git reset.
+type ResetMode string
+
+const (
+	ResetSoft  ResetMode = "soft"
+	ResetMixed ResetMode = "mixed"
+	ResetHard  ResetMode = "hard"
+)
+
+// ResolveCherryPickCommits resolves a commit, branch or A..B range into the
+// commits to cherry-pick, oldest first. A single ref resolves to the commit it
+// points at, which may be a merge; ranges skip merge commits.
+func ResolveCherryPickCommits(workDir, spec string) ([]*Commit, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("no commit given")
+	}
+
+	var hashes []string
+	if strings.Contains(spec, "..") {
+		cmd := exec.Command("git", "rev-list", "--reverse", "--no-merges", spec)
+		cmd.Dir = workDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q: %s", spec, strings.TrimSpace(string(output)))
+		}
+		hashes = strings.Fields(string(output))
+		if len(hashes) == 0 {
+			return nil, fmt.Errorf("range %q contains no commits", spec)
+		}
+	} else {
+		cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", spec+"^{commit}")
+		cmd.Dir = workDir
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("unknown commit %q", spec)
+		}
+		hash := strings.TrimSpace(string(output))
+		if isAncestorOfHead(workDir, hash) {
+			return nil, fmt.Errorf("%s is already on the current branch", spec)
+		}
+		hashes = []string{hash}
+	}
+
+	commits := make([]*Commit, 0, len(hashes))
+	for _, hash := range hashes {
+		c, err := GetCommitDetail(workDir, hash)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			return nil, fmt.Errorf("could not read commit %s", hash)
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// isAncestorOfHead reports whether hash is reachable from HEAD.
+func isAncestorOfHead(workDir, hash string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", hash, "HEAD")
+	cmd.Dir = workDir
+	return cmd.Run() == nil
+}
+
+// ExecuteCherryPick applies the given commits onto HEAD in order. A merge
+// commit, which is only ever picked on its own, is applied relative to its
+// first parent.
+func ExecuteCherryPick(workDir string, hashes []string, isMerge bool) (string, error) {
+	args := []string{"cherry-pick"}
+	if isMerge {
+		args = append(args, "-m", "1")
+	}
+	args = append(args, hashes...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// ExecuteRevert creates a commit undoing hash. Merge commits are reverted
+// relative to their first parent.
+func ExecuteRevert(workDir, hash string, isMerge bool) (string, error) {
+	args := []string{"revert", "--no-edit"}
+	if isMerge {
+		args = append(args, "-m", "1")
+	}
+	args = append(args, hash)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// ExecuteReset moves the current branch to hash with the given mode.
+func ExecuteReset(workDir, hash string, mode ResetMode) (string, error) {
+	cmd := exec.Command("git", "reset", "--"+string(mode), hash)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// SequencerInProgress returns "cherry-pick" or "revert" when one of them
+// stopped on conflicts, or "" otherwise.
+func SequencerInProgress(workDir string) string {
+	switch {
+	case gitPathExists(workDir, "CHERRY_PICK_HEAD"):
+		return "cherry-pick"
+	case gitPathExists(workDir, "REVERT_HEAD"):
+		return "revert"
+	}
+	return ""
+}
+
+// ContinueSequencer runs git cherry-pick/revert --continue without an editor.
+func ContinueSequencer(workDir, op string) (string, error) {
+	cmd := exec.Command("git", op, "--continue")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// AbortSequencer runs git cherry-pick/revert --abort.
+func AbortSequencer(workDir, op string) error {
+	cmd := exec.Command("git", op, "--abort")
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
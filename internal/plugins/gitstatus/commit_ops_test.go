@@ -0,0 +1,192 @@
+package gitstatus
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRun runs a git command in dir and returns its trimmed output.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v (%s)", args, err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitFile writes content to name and commits it.
+func commitFile(t *testing.T, dir, name, content, subject string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", name)
+	gitRun(t, dir, "commit", "-m", subject)
+}
+
+// initBranchRepo creates a repo with an "agent" branch two commits ahead of
+// the current branch.
+func initBranchRepo(t *testing.T) (dir, trunk string) {
+	t.Helper()
+	dir = initPartialRepo(t, "one\n")
+	trunk = gitRun(t, dir, "branch", "--show-current")
+	gitRun(t, dir, "checkout", "-q", "-b", "agent")
+	commitFile(t, dir, "a.txt", "a\n", "add a")
+	commitFile(t, dir, "b.txt", "b\n", "add b")
+	gitRun(t, dir, "checkout", "-q", trunk)
+	return dir, trunk
+}
+
+func TestResolveCherryPickCommits(t *testing.T) {
+	dir, trunk := initBranchRepo(t)
+
+	commits, err := ResolveCherryPickCommits(dir, "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "add b" {
+		t.Fatalf("single ref resolved to %+v", commits)
+	}
+	if len(commits[0].Files) != 1 || commits[0].Files[0].Path != "b.txt" {
+		t.Errorf("expected file details, got %+v", commits[0].Files)
+	}
+
+	commits, err = ResolveCherryPickCommits(dir, trunk+"..agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "add a" || commits[1].Subject != "add b" {
+		t.Fatalf("range should resolve oldest first, got %+v", commits)
+	}
+
+	if _, err := ResolveCherryPickCommits(dir, "HEAD"); err == nil || !strings.Contains(err.Error(), "already on the current branch") {
+		t.Errorf("expected already-on-branch error, got %v", err)
+	}
+	if _, err := ResolveCherryPickCommits(dir, "no-such-branch"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+	if _, err := ResolveCherryPickCommits(dir, "agent.."+trunk); err == nil {
+		t.Error("expected error for empty range")
+	}
+}
+
+func TestExecuteCherryPickAndRevert(t *testing.T) {
+	dir, trunk := initBranchRepo(t)
+	commits, err := ResolveCherryPickCommits(dir, trunk+"..agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExecuteCherryPick(dir, []string{commits[0].Hash, commits[1].Hash}, false); err != nil {
+		t.Fatalf("ExecuteCherryPick: %v", err)
+	}
+	if got := gitRun(t, dir, "log", "--format=%s", "-2"); got != "add b\nadd a" {
+		t.Errorf("log after cherry-pick = %q", got)
+	}
+
+	head := gitRun(t, dir, "rev-parse", "HEAD")
+	if _, err := ExecuteRevert(dir, head, false); err != nil {
+		t.Fatalf("ExecuteRevert: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); !os.IsNotExist(err) {
+		t.Error("revert should remove b.txt")
+	}
+	if got := gitRun(t, dir, "log", "--format=%s", "-1"); !strings.HasPrefix(got, "Revert \"add b\"") {
+		t.Errorf("revert subject = %q", got)
+	}
+}
+
+func TestCherryPickMerge(t *testing.T) {
+	dir, trunk := initBranchRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", "feature")
+	commitFile(t, dir, "c.txt", "c\n", "add c")
+	gitRun(t, dir, "checkout", "-q", "agent")
+	gitRun(t, dir, "merge", "-q", "--no-ff", "-m", "merge feature", "feature")
+	gitRun(t, dir, "checkout", "-q", trunk)
+
+	commits, err := ResolveCherryPickCommits(dir, "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || !commits[0].IsMerge {
+		t.Fatalf("expected the merge commit, got %+v", commits)
+	}
+	if _, err := ExecuteCherryPick(dir, []string{commits[0].Hash}, commits[0].IsMerge); err != nil {
+		t.Fatalf("ExecuteCherryPick: %v", err)
+	}
+	// Only what the merge brought in relative to its first parent
+	if _, err := os.Stat(filepath.Join(dir, "c.txt")); err != nil {
+		t.Error("merge changes not applied")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); !os.IsNotExist(err) {
+		t.Error("first parent's changes applied")
+	}
+}
+
+func TestCherryPickConflictAbort(t *testing.T) {
+	dir, _ := initBranchRepo(t)
+	gitRun(t, dir, "checkout", "-q", "agent")
+	commitFile(t, dir, "file.txt", "agent\n", "agent edit")
+	gitRun(t, dir, "checkout", "-q", "-")
+	commitFile(t, dir, "file.txt", "trunk\n", "trunk edit")
+
+	if _, err := ExecuteCherryPick(dir, []string{"agent"}, false); err == nil {
+		t.Fatal("expected conflict")
+	}
+	if op := SequencerInProgress(dir); op != "cherry-pick" {
+		t.Fatalf("SequencerInProgress = %q, want cherry-pick", op)
+	}
+	if files := GetConflictedFiles(dir); len(files) != 1 || files[0] != "file.txt" {
+		t.Errorf("conflicted files = %v", files)
+	}
+
+	if err := AbortSequencer(dir, "cherry-pick"); err != nil {
+		t.Fatalf("AbortSequencer: %v", err)
+	}
+	if op := SequencerInProgress(dir); op != "" {
+		t.Errorf("SequencerInProgress after abort = %q", op)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if string(data) != "trunk\n" {
+		t.Errorf("file after abort = %q", data)
+	}
+}
+
+func TestExecuteReset(t *testing.T) {
+	tests := []struct {
+		mode       ResetMode
+		wantStaged bool
+		wantFile   bool
+	}{
+		{ResetSoft, true, true},
+		{ResetMixed, false, true},
+		{ResetHard, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			dir := initPartialRepo(t, "one\n")
+			base := gitRun(t, dir, "rev-parse", "HEAD")
+			commitFile(t, dir, "new.txt", "new\n", "add new")
+
+			if _, err := ExecuteReset(dir, base, tt.mode); err != nil {
+				t.Fatalf("ExecuteReset: %v", err)
+			}
+			if head := gitRun(t, dir, "rev-parse", "HEAD"); head != base {
+				t.Errorf("HEAD = %s, want %s", head, base)
+			}
+			staged := gitRun(t, dir, "diff", "--cached", "--name-only") == "new.txt"
+			if staged != tt.wantStaged {
+				t.Errorf("staged = %v, want %v", staged, tt.wantStaged)
+			}
+			_, err := os.Stat(filepath.Join(dir, "new.txt"))
+			if (err == nil) != tt.wantFile {
+				t.Errorf("new.txt exists = %v, want %v", err == nil, tt.wantFile)
+			}
+		})
+	}
+}
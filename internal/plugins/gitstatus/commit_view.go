@@ -42,8 +42,12 @@ func (p *Plugin) ensureCommitModal() {
 		AddSection(p.commitStagedSection()).
 		AddSection(modal.Spacer()).
 		AddSection(modal.Textarea(commitMessageID, &p.commitMessage, 4)).
+		AddSection(p.commitLintSection()).
 		AddSection(modal.When(p.showCommitAmendToggle, modal.CheckboxDisplay("Amend last commit", &p.commitAmend, "ctrl+a"))).
+		AddSection(modal.CheckboxDisplay("Sign off (Signed-off-by trailer)", &p.commitSignOff, "ctrl+o")).
+		AddSection(p.commitSigningSection()).
 		AddSection(p.commitStatusSection()).
+		AddSection(p.commitHintsSection()).
 		AddSection(modal.Buttons(
 			modal.Btn(p.commitButtonLabel(), commitActionID),
 			modal.Btn(" Cancel ", "cancel"),
@@ -155,7 +159,12 @@ func (p *Plugin) commitStagedSection() modal.Section {
 
 func (p *Plugin) commitStatusSection() modal.Section {
 	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
-		lines := make([]string, 0, 2)
+		lines := make([]string, 0, 3)
+		if p.commitGenerating {
+			lines = append(lines, styles.Muted.Render("Drafting message..."))
+		} else if p.commitNotice != "" {
+			lines = append(lines, styles.Muted.Render(p.commitNotice))
+		}
 		if p.commitError != "" {
 			lines = append(lines, styles.StatusDeleted.Render("✗ "+p.commitError))
 		}
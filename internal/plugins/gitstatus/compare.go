@@ -0,0 +1,193 @@
+package gitstatus
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// compareCommitLimit caps each side of a branch comparison's commit lists.
+const compareCommitLimit = 500
+
+// BranchComparison describes how two refs have diverged.
+type BranchComparison struct {
+	Base        string
+	Ref         string
+	MergeBase   string    // Short hash; "" for unrelated histories
+	Ahead       []*Commit // Commits in Ref but not Base, newest first
+	Behind      []*Commit // Commits in Base but not Ref, newest first
+	AheadCount  int       // Totals; the lists stop at compareCommitLimit
+	BehindCount int
+}
+
+// RangeDiffEntry is one line of git range-diff: a commit of the old range
+// matched against a commit of the new range.
+type RangeDiffEntry struct {
+	OldIndex int // Position in the old range, 1-based; 0 if only in the new range
+	OldHash  string
+	NewIndex int // Position in the new range, 1-based; 0 if only in the old range
+	NewHash  string
+	Status   byte // '=' same patch, '!' patch changed, '<' only old, '>' only new
+	Subject  string
+	Diff     []string // Diff between the two patches when Status is '!'
+}
+
+// VerifyRef checks that ref names a commit.
+func VerifyRef(workDir, ref string) error {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = workDir
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unknown ref %q", ref)
+	}
+	return nil
+}
+
+// DefaultBranch returns the repository's default branch: origin's HEAD if
+// known, else main or master, else "main".
+func DefaultBranch(workDir string) string {
+	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+	cmd.Dir = workDir
+	if output, err := cmd.Output(); err == nil {
+		// Output is like "refs/remotes/origin/main"
+		ref := strings.TrimSpace(string(output))
+		if branch, found := strings.CutPrefix(ref, "refs/remotes/origin/"); found {
+			return branch
+		}
+	}
+
+	for _, branch := range []string{"main", "master"} {
+		cmd := exec.Command("git", "rev-parse", "--verify", branch)
+		cmd.Dir = workDir
+		if err := cmd.Run(); err == nil {
+			return branch
+		}
+	}
+	return "main"
+}
+
+// CountAheadBehind counts the commits in ref but not base (ahead) and in
+// base but not ref (behind).
+func CountAheadBehind(workDir, base, ref string) (ahead, behind int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", base+"..."+ref)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	parts := strings.Fields(string(output))
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", strings.TrimSpace(string(output)))
+	}
+	if behind, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if ahead, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// CompareRefs compares ref against base: the commits unique to each side
+// and their merge base.
+func CompareRefs(workDir, base, ref string) (*BranchComparison, error) {
+	for _, r := range []string{base, ref} {
+		if err := VerifyRef(workDir, r); err != nil {
+			return nil, err
+		}
+	}
+
+	ahead, behind, err := CountAheadBehind(workDir, base, ref)
+	if err != nil {
+		return nil, err
+	}
+	cmp := &BranchComparison{Base: base, Ref: ref, AheadCount: ahead, BehindCount: behind}
+
+	if cmp.Ahead, err = logRange(workDir, base+".."+ref); err != nil {
+		return nil, err
+	}
+	if cmp.Behind, err = logRange(workDir, ref+".."+base); err != nil {
+		return nil, err
+	}
+
+	// merge-base fails for unrelated histories; the comparison still holds
+	cmd := exec.Command("git", "merge-base", base, ref)
+	cmd.Dir = workDir
+	if output, err := cmd.Output(); err == nil {
+		hash := strings.TrimSpace(string(output))
+		cmp.MergeBase = hash[:min(len(hash), 7)]
+	}
+	return cmp, nil
+}
+
+// logRange lists the commits of a revision range, newest first.
+func logRange(workDir, revRange string) ([]*Commit, error) {
+	cmd := exec.Command("git", "log", "--format="+commitLogFormat, "-n", strconv.Itoa(compareCommitLimit), revRange)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(output), nil
+}
+
+// GetCompareDiff returns the changes ref brings over base: the diff from
+// their merge base to ref, as a pull request would show it. With direct,
+// it is the diff between the two trees instead.
+func GetCompareDiff(workDir, base, ref string, direct bool) (string, error) {
+	args := []string{"diff", "-M", base + "..." + ref}
+	if direct {
+		args = []string{"diff", "-M", base, ref}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRangeDiff matches the commits only in base against those only in ref
+// with git range-diff, which shows how a rebased branch's patches changed.
+func GetRangeDiff(workDir, base, ref string) ([]*RangeDiffEntry, error) {
+	cmd := exec.Command("git", "range-diff", "--no-color", base+"..."+ref)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return parseRangeDiff(string(output)), nil
+}
+
+// rangeDiffHeaderRe matches a range-diff commit pair line such as
+// "1:  c67ba73 ! 1:  085e518 add feature".
+var rangeDiffHeaderRe = regexp.MustCompile(`^\s*(-|\d+):\s+(-+|[0-9a-f]+) ([=!<>]) \s*(-|\d+):\s+(-+|[0-9a-f]+) (.*)$`)
+
+// parseRangeDiff parses git range-diff --no-color output. The indented
+// lines after a pair hold the diff between its two patches.
+func parseRangeDiff(output string) []*RangeDiffEntry {
+	var entries []*RangeDiffEntry
+	var current *RangeDiffEntry
+	for _, line := range strings.Split(output, "\n") {
+		if m := rangeDiffHeaderRe.FindStringSubmatch(line); m != nil {
+			current = &RangeDiffEntry{Status: m[3][0], Subject: m[6]}
+			current.OldIndex, _ = strconv.Atoi(m[1])
+			current.NewIndex, _ = strconv.Atoi(m[4])
+			if !strings.HasPrefix(m[2], "-") {
+				current.OldHash = m[2]
+			}
+			if !strings.HasPrefix(m[5], "-") {
+				current.NewHash = m[5]
+			}
+			entries = append(entries, current)
+			continue
+		}
+		if current != nil && strings.HasPrefix(line, "    ") {
+			current.Diff = append(current.Diff, line[4:])
+		}
+	}
+	return entries
+}
@@ -0,0 +1,144 @@
+package gitstatus
+
+import (
+	"strings"
+	"testing"
+)
+
+// initDivergedRepo creates a repo whose "agent" branch is two commits ahead
+// of trunk and one behind.
+func initDivergedRepo(t *testing.T) (dir, trunk string) {
+	t.Helper()
+	dir, trunk = initBranchRepo(t)
+	commitFile(t, dir, "c.txt", "c\n", "add c")
+	return dir, trunk
+}
+
+func TestCountAheadBehind(t *testing.T) {
+	dir, trunk := initDivergedRepo(t)
+
+	ahead, behind, err := CountAheadBehind(dir, trunk, "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ahead != 2 || behind != 1 {
+		t.Errorf("ahead/behind = %d/%d, want 2/1", ahead, behind)
+	}
+
+	ahead, behind, err = CountAheadBehind(dir, "agent", trunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ahead != 1 || behind != 2 {
+		t.Errorf("swapped ahead/behind = %d/%d, want 1/2", ahead, behind)
+	}
+}
+
+func TestCompareRefs(t *testing.T) {
+	dir, trunk := initDivergedRepo(t)
+
+	cmp, err := CompareRefs(dir, trunk, "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.AheadCount != 2 || cmp.BehindCount != 1 {
+		t.Errorf("counts = %d/%d, want 2/1", cmp.AheadCount, cmp.BehindCount)
+	}
+	if len(cmp.Ahead) != 2 || cmp.Ahead[0].Subject != "add b" || cmp.Ahead[1].Subject != "add a" {
+		t.Errorf("ahead = %+v", cmp.Ahead)
+	}
+	if len(cmp.Behind) != 1 || cmp.Behind[0].Subject != "add c" {
+		t.Errorf("behind = %+v", cmp.Behind)
+	}
+	if base := gitRun(t, dir, "merge-base", trunk, "agent"); !strings.HasPrefix(base, cmp.MergeBase) || cmp.MergeBase == "" {
+		t.Errorf("merge base = %q, want prefix of %q", cmp.MergeBase, base)
+	}
+
+	if _, err := CompareRefs(dir, trunk, "missing"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
+
+func TestGetCompareDiff(t *testing.T) {
+	dir, trunk := initDivergedRepo(t)
+
+	// From the merge base: only the branch's own files
+	diff, err := GetCompareDiff(dir, trunk, "agent", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfd := ParseMultiFileDiff(diff)
+	if len(mfd.Files) != 2 || strings.Contains(diff, "c.txt") {
+		t.Errorf("merge base diff has %d files:\n%s", len(mfd.Files), diff)
+	}
+
+	// Between the tips: trunk's c.txt shows as removed
+	diff, err = GetCompareDiff(dir, trunk, "agent", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "deleted file mode") || !strings.Contains(diff, "c.txt") {
+		t.Errorf("direct diff missing c.txt deletion:\n%s", diff)
+	}
+}
+
+func TestGetRangeDiff(t *testing.T) {
+	dir, trunk := initDivergedRepo(t)
+
+	// Rebase a copy of agent onto trunk, rewording its second commit
+	gitRun(t, dir, "checkout", "-q", "-b", "rebased", trunk)
+	gitRun(t, dir, "cherry-pick", "agent~1", "agent")
+	gitRun(t, dir, "commit", "--amend", "-m", "add b, reworded")
+
+	entries, err := GetRangeDiff(dir, "agent", "rebased")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries: %+v", len(entries), entries)
+	}
+
+	// Entries follow the new range: trunk's commit, then the two rebased ones
+	var statuses []byte
+	for _, e := range entries {
+		statuses = append(statuses, e.Status)
+	}
+	if string(statuses) != ">=!" {
+		t.Fatalf("statuses = %q, want \">=!\"", statuses)
+	}
+	if e := entries[2]; e.OldHash == "" || e.NewHash == "" || !strings.Contains(strings.Join(e.Diff, "\n"), "+    add b, reworded") {
+		t.Errorf("changed entry = %+v", e)
+	}
+}
+
+func TestParseRangeDiff(t *testing.T) {
+	output := strings.Join([]string{
+		"1:  c67ba73 < -:  ------- add b",
+		"-:  ------- > 1:  201b1a6 add b again",
+		"2:  e8e9786 ! 2:  085e518 add n",
+		"    @@ n (new)",
+		"     +29",
+		"    -+30",
+		"    ++31",
+		"10:  74a28cd = 10:  a2588dd add g",
+	}, "\n")
+
+	entries := parseRangeDiff(output)
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+
+	if e := entries[0]; e.Status != '<' || e.OldIndex != 1 || e.OldHash != "c67ba73" || e.NewIndex != 0 || e.NewHash != "" {
+		t.Errorf("old-only entry = %+v", e)
+	}
+	if e := entries[1]; e.Status != '>' || e.OldHash != "" || e.NewHash != "201b1a6" || e.Subject != "add b again" {
+		t.Errorf("new-only entry = %+v", e)
+	}
+	want := []string{"@@ n (new)", " +29", "-+30", "++31"}
+	if e := entries[2]; e.Status != '!' || strings.Join(e.Diff, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changed entry diff = %q", e.Diff)
+	}
+	if e := entries[3]; e.Status != '=' || e.OldIndex != 10 || e.NewIndex != 10 || len(e.Diff) != 0 {
+		t.Errorf("unchanged entry = %+v", e)
+	}
+}
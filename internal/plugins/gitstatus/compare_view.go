@@ -0,0 +1,840 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	compareBaseInputID = "compare-base"
+	compareRefInputID  = "compare-ref"
+	compareSubmitID    = "compare-submit"
+)
+
+// compareTab is a tab of the branch comparison view.
+type compareTab int
+
+const (
+	compareTabCommits   compareTab = iota // Ahead/behind commits
+	compareTabDiff                        // Combined diff
+	compareTabRangeDiff                   // git range-diff of the two sides
+)
+
+// CompareLoadedMsg carries the commits of a branch comparison.
+type CompareLoadedMsg struct {
+	Epoch      uint64
+	Base       string
+	Ref        string
+	Comparison *BranchComparison
+	Err        error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m CompareLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// CompareDiffLoadedMsg carries the combined diff of a branch comparison.
+type CompareDiffLoadedMsg struct {
+	Epoch  uint64
+	Base   string
+	Ref    string
+	Direct bool
+	Diff   string
+	Err    error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m CompareDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// CompareCommitDiffLoadedMsg carries the diff of one commit in a comparison.
+type CompareCommitDiffLoadedMsg struct {
+	Epoch uint64
+	Hash  string
+	Diff  string
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m CompareCommitDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// RangeDiffLoadedMsg carries the range-diff of a branch comparison.
+type RangeDiffLoadedMsg struct {
+	Epoch   uint64
+	Base    string
+	Ref     string
+	Entries []*RangeDiffEntry
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m RangeDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openComparePrompt opens the prompt for the two refs to compare. Empty
+// refs default to the default branch and the current branch.
+func (p *Plugin) openComparePrompt(base, ref string) {
+	if base == "" {
+		base = DefaultBranch(p.repoRoot)
+	}
+	if ref == "" {
+		ref = currentHead(p.repoRoot).Branch
+		if ref == "" {
+			ref = "HEAD"
+		}
+	}
+
+	newInput := func(value, placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.Prompt = ""
+		input.CharLimit = 200
+		input.Width = 40
+		input.SetValue(value)
+		return input
+	}
+	p.compareBaseInput = newInput(base, "branch, tag or commit")
+	p.compareRefInput = newInput(ref, "branch, tag or commit")
+	p.compareBaseInput.Focus()
+	p.compareError = ""
+
+	p.compareReturnMode = p.viewMode
+	if p.viewMode != ViewModeCompare {
+		p.compareTab = compareTabCommits
+		p.compareDirect = false
+	}
+	p.clearComparePromptModal()
+	p.viewMode = ViewModeComparePrompt
+}
+
+// updateComparePrompt handles key events in the compare prompt.
+func (p *Plugin) updateComparePrompt(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureComparePromptModal()
+	if p.comparePromptModal == nil {
+		return p, nil
+	}
+	action, cmd := p.comparePromptModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleComparePromptAction(action))
+}
+
+// handleComparePromptAction runs a modal action returned by key or mouse
+// input. The prompt stays open until both refs resolve.
+func (p *Plugin) handleComparePromptAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeComparePrompt()
+	case compareSubmitID, compareBaseInputID, compareRefInputID:
+		base := strings.TrimSpace(p.compareBaseInput.Value())
+		ref := strings.TrimSpace(p.compareRefInput.Value())
+		if base == "" || ref == "" {
+			p.compareError = "Both refs are required"
+			return nil
+		}
+		for _, r := range []string{base, ref} {
+			if err := VerifyRef(p.repoRoot, r); err != nil {
+				p.compareError = err.Error()
+				return nil
+			}
+		}
+		p.clearComparePromptModal()
+		return p.openCompare(base, ref)
+	}
+	return nil
+}
+
+func (p *Plugin) closeComparePrompt() {
+	p.viewMode = p.compareReturnMode
+	p.compareError = ""
+	p.clearComparePromptModal()
+}
+
+func (p *Plugin) clearComparePromptModal() {
+	p.comparePromptModal = nil
+	p.comparePromptModalWidth = 0
+}
+
+// ensureComparePromptModal builds/rebuilds the compare prompt.
+func (p *Plugin) ensureComparePromptModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.comparePromptModal != nil && p.comparePromptModalWidth == modalW {
+		return
+	}
+	p.comparePromptModalWidth = modalW
+
+	p.comparePromptModal = modal.New("Compare Refs",
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(compareSubmitID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.InputWithLabel(compareBaseInputID, "Base:", &p.compareBaseInput, modal.WithSubmitAction(compareSubmitID))).
+		AddSection(modal.InputWithLabel(compareRefInputID, "Compare:", &p.compareRefInput, modal.WithSubmitAction(compareSubmitID))).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := styles.Muted.Render("Shows what Compare has that Base doesn't, and what it's missing.")
+			if p.compareError != "" {
+				content += "\n\n" + styles.StatusDeleted.Render(ansi.Truncate(p.compareError, contentWidth, "…"))
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Compare ", compareSubmitID),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// renderComparePrompt renders the compare prompt over the view it was
+// opened from.
+func (p *Plugin) renderComparePrompt() string {
+	background := p.renderThreePaneView()
+	if p.compareReturnMode == ViewModeCompare {
+		background = p.renderCompare()
+	}
+
+	p.ensureComparePromptModal()
+	if p.comparePromptModal == nil {
+		return background
+	}
+
+	modalContent := p.comparePromptModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openCompare opens the comparison of ref against base and loads its
+// commits and combined diff. The range-diff loads when its tab is shown.
+func (p *Plugin) openCompare(base, ref string) tea.Cmd {
+	p.compareBase = base
+	p.compareRef = ref
+	p.compare = nil
+	p.compareCursor = 0
+	p.compareListScroll = 0
+	p.compareDiff = nil
+	p.compareDiffRaw = ""
+	p.compareDiffLoaded = false
+	p.compareRangeDiff = nil
+	p.compareRangeDiffLoaded = false
+	p.compareRangeDiffErr = ""
+	p.clearCompareCommitDiff()
+	p.viewMode = ViewModeCompare
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	load := func() tea.Msg {
+		cmp, err := CompareRefs(workDir, base, ref)
+		return CompareLoadedMsg{Epoch: epoch, Base: base, Ref: ref, Comparison: cmp, Err: err}
+	}
+	cmds := []tea.Cmd{load, p.loadCompareDiff()}
+	if p.compareTab == compareTabRangeDiff {
+		cmds = append(cmds, p.loadRangeDiff())
+	}
+	return tea.Batch(cmds...)
+}
+
+// compareOpen reports whether the comparison is shown, possibly under the
+// prompt for new refs.
+func (p *Plugin) compareOpen() bool {
+	return p.viewMode == ViewModeCompare ||
+		(p.viewMode == ViewModeComparePrompt && p.compareReturnMode == ViewModeCompare)
+}
+
+// compareStale reports whether a loaded result is for another comparison.
+func (p *Plugin) compareStale(m plugin.EpochMessage, base, ref string) bool {
+	return plugin.IsStale(p.ctx, m) || !p.compareOpen() ||
+		p.compareBase != base || p.compareRef != ref
+}
+
+// handleCompareLoaded stores the comparison and loads the first commit's diff.
+func (p *Plugin) handleCompareLoaded(m CompareLoadedMsg) tea.Cmd {
+	if p.compareStale(m, m.Base, m.Ref) {
+		return nil
+	}
+	if m.Err != nil {
+		p.closeCompare()
+		p.showErrorModal("Compare Failed", m.Err)
+		return nil
+	}
+	p.compare = m.Comparison
+	if p.compareTab == compareTabCommits {
+		return p.loadCompareCommitDiff()
+	}
+	return nil
+}
+
+// loadCompareDiff loads the combined diff.
+func (p *Plugin) loadCompareDiff() tea.Cmd {
+	p.compareDiff = nil
+	p.compareDiffRaw = ""
+	p.compareDiffLoaded = false
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	base, ref, direct := p.compareBase, p.compareRef, p.compareDirect
+	return func() tea.Msg {
+		diff, err := GetCompareDiff(workDir, base, ref, direct)
+		return CompareDiffLoadedMsg{Epoch: epoch, Base: base, Ref: ref, Direct: direct, Diff: diff, Err: err}
+	}
+}
+
+// handleCompareDiffLoaded stores the combined diff.
+func (p *Plugin) handleCompareDiffLoaded(m CompareDiffLoadedMsg) tea.Cmd {
+	if p.compareStale(m, m.Base, m.Ref) || m.Direct != p.compareDirect {
+		return nil
+	}
+	p.compareDiffLoaded = true
+	if m.Err != nil {
+		p.compareDiffRaw = "Error: " + m.Err.Error()
+		return nil
+	}
+	p.compareDiffRaw = m.Diff
+	p.compareDiff = ParseMultiFileDiff(m.Diff)
+	if p.compareTab == compareTabDiff {
+		p.compareCursor = min(p.compareCursor, max(len(p.compareDiff.Files)-1, 0))
+	}
+	return nil
+}
+
+// loadRangeDiff loads the range-diff.
+func (p *Plugin) loadRangeDiff() tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	base, ref := p.compareBase, p.compareRef
+	return func() tea.Msg {
+		entries, err := GetRangeDiff(workDir, base, ref)
+		return RangeDiffLoadedMsg{Epoch: epoch, Base: base, Ref: ref, Entries: entries, Err: err}
+	}
+}
+
+// handleRangeDiffLoaded stores the range-diff and loads the selected
+// commit's diff when it has no patch diff of its own.
+func (p *Plugin) handleRangeDiffLoaded(m RangeDiffLoadedMsg) tea.Cmd {
+	if p.compareStale(m, m.Base, m.Ref) {
+		return nil
+	}
+	p.compareRangeDiffLoaded = true
+	if m.Err != nil {
+		p.compareRangeDiffErr = m.Err.Error()
+		return nil
+	}
+	p.compareRangeDiff = m.Entries
+	if p.compareTab == compareTabRangeDiff {
+		p.compareCursor = min(p.compareCursor, max(len(p.compareRangeDiff)-1, 0))
+		return p.loadCompareCommitDiff()
+	}
+	return nil
+}
+
+func (p *Plugin) clearCompareCommitDiff() {
+	p.compareCommitDiff = nil
+	p.compareCommitDiffRaw = ""
+	p.compareCommitDiffHash = ""
+	p.compareDiffScroll = 0
+}
+
+// compareCommits returns the commits of the commits tab: ahead, then behind.
+func (p *Plugin) compareCommits() []*Commit {
+	if p.compare == nil {
+		return nil
+	}
+	commits := make([]*Commit, 0, len(p.compare.Ahead)+len(p.compare.Behind))
+	commits = append(commits, p.compare.Ahead...)
+	return append(commits, p.compare.Behind...)
+}
+
+// selectedRangeDiffEntry returns the range-diff entry under the cursor, if any.
+func (p *Plugin) selectedRangeDiffEntry() *RangeDiffEntry {
+	if p.compareTab != compareTabRangeDiff || p.compareCursor < 0 || p.compareCursor >= len(p.compareRangeDiff) {
+		return nil
+	}
+	return p.compareRangeDiff[p.compareCursor]
+}
+
+// selectedCompareCommit returns the commit whose diff the right column
+// shows: the selected commit, or the selected range-diff commit unless its
+// patch changed.
+func (p *Plugin) selectedCompareCommit() *Commit {
+	switch p.compareTab {
+	case compareTabCommits:
+		commits := p.compareCommits()
+		if p.compareCursor >= 0 && p.compareCursor < len(commits) {
+			return commits[p.compareCursor]
+		}
+	case compareTabRangeDiff:
+		e := p.selectedRangeDiffEntry()
+		if e == nil || e.Status == '!' {
+			return nil
+		}
+		hash := e.NewHash
+		if hash == "" {
+			hash = e.OldHash
+		}
+		return &Commit{Hash: hash, ShortHash: hash}
+	}
+	return nil
+}
+
+// loadCompareCommitDiff loads the diff of the selected commit.
+func (p *Plugin) loadCompareCommitDiff() tea.Cmd {
+	c := p.selectedCompareCommit()
+	if c == nil {
+		return nil
+	}
+	p.clearCompareCommitDiff()
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		diff, err := GetHistoryDiff(workDir, &FileHistoryEntry{Commit: c}, nil)
+		return CompareCommitDiffLoadedMsg{Epoch: epoch, Hash: c.Hash, Diff: diff, Err: err}
+	}
+}
+
+// handleCompareCommitDiffLoaded shows a loaded diff if its commit is still
+// selected.
+func (p *Plugin) handleCompareCommitDiffLoaded(m CompareCommitDiffLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || !p.compareOpen() {
+		return nil
+	}
+	c := p.selectedCompareCommit()
+	if c == nil || c.Hash != m.Hash {
+		return nil
+	}
+	p.compareCommitDiffHash = m.Hash
+	if m.Err != nil {
+		p.compareCommitDiffRaw = "Error: " + m.Err.Error()
+		return nil
+	}
+	p.compareCommitDiffRaw = m.Diff
+	p.compareCommitDiff = ParseMultiFileDiff(m.Diff)
+	return nil
+}
+
+// compareListLen returns the number of rows in the current tab's list.
+func (p *Plugin) compareListLen() int {
+	switch p.compareTab {
+	case compareTabCommits:
+		return len(p.compareCommits())
+	case compareTabDiff:
+		if p.compareDiff != nil {
+			return len(p.compareDiff.Files)
+		}
+	case compareTabRangeDiff:
+		return len(p.compareRangeDiff)
+	}
+	return 0
+}
+
+// moveCompareCursor selects another row. In the diff tab the combined diff
+// jumps to the file; elsewhere the row's own diff is loaded.
+func (p *Plugin) moveCompareCursor(idx int) tea.Cmd {
+	idx = max(min(idx, p.compareListLen()-1), 0)
+	if idx == p.compareCursor {
+		return nil
+	}
+	p.compareCursor = idx
+	if p.compareTab == compareTabDiff {
+		p.compareDiffScroll = p.compareDiff.Files[idx].StartLine
+		return nil
+	}
+	p.compareDiffScroll = 0
+	return p.loadCompareCommitDiff()
+}
+
+// setCompareTab switches tabs, loading the range-diff on first use.
+func (p *Plugin) setCompareTab(tab compareTab) tea.Cmd {
+	if tab == p.compareTab {
+		return nil
+	}
+	p.compareTab = tab
+	p.compareCursor = 0
+	p.compareListScroll = 0
+	p.clearCompareCommitDiff()
+	if tab == compareTabRangeDiff && !p.compareRangeDiffLoaded {
+		return p.loadRangeDiff()
+	}
+	return p.loadCompareCommitDiff()
+}
+
+// closeCompare returns to the status view.
+func (p *Plugin) closeCompare() {
+	p.viewMode = ViewModeStatus
+	p.compare = nil
+	p.compareDiff = nil
+	p.compareDiffRaw = ""
+	p.compareRangeDiff = nil
+	p.clearCompareCommitDiff()
+}
+
+// updateCompare handles key events in the comparison view.
+func (p *Plugin) updateCompare(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	page := max((p.height-4)/2, 1)
+	switch m.String() {
+	case "esc", "q":
+		p.closeCompare()
+	case "tab":
+		return p, p.setCompareTab((p.compareTab + 1) % 3)
+	case "shift+tab":
+		return p, p.setCompareTab((p.compareTab + 2) % 3)
+	case "1":
+		return p, p.setCompareTab(compareTabCommits)
+	case "2":
+		return p, p.setCompareTab(compareTabDiff)
+	case "3":
+		return p, p.setCompareTab(compareTabRangeDiff)
+	case "j", "down":
+		return p, p.moveCompareCursor(p.compareCursor + 1)
+	case "k", "up":
+		return p, p.moveCompareCursor(p.compareCursor - 1)
+	case "g":
+		return p, p.moveCompareCursor(0)
+	case "G":
+		return p, p.moveCompareCursor(p.compareListLen() - 1)
+	case "ctrl+d", "J":
+		p.compareDiffScroll += page
+	case "ctrl+u", "K":
+		p.compareDiffScroll = max(p.compareDiffScroll-page, 0)
+	case "v":
+		if p.diffViewMode == DiffViewUnified {
+			p.diffViewMode = DiffViewSideBySide
+		} else {
+			p.diffViewMode = DiffViewUnified
+		}
+	case "s":
+		return p, p.openCompare(p.compareRef, p.compareBase)
+	case "t":
+		// Diff between the tips instead of from the merge base, or back
+		p.compareDirect = !p.compareDirect
+		if p.compareTab == compareTabDiff {
+			p.compareCursor = 0
+			p.compareDiffScroll = 0
+		}
+		return p, p.loadCompareDiff()
+	case "e":
+		p.openComparePrompt(p.compareBase, p.compareRef)
+	case "y":
+		if c := p.selectedCompareCommit(); c != nil {
+			if err := clipboard.WriteAll(c.Hash); err != nil {
+				return p, msg.ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+			}
+			return p, msg.ShowToast("Yanked "+c.ShortHash, 2*time.Second)
+		}
+	}
+	return p, nil
+}
+
+// renderCompare renders the full-screen comparison: a tab's list on the
+// left, the selected row's diff on the right.
+func (p *Plugin) renderCompare() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+	listWidth := p.fileHistoryListWidth(contentWidth)
+	diffWidth := max(contentWidth-listWidth-3, 10)
+	bodyHeight := max(paneHeight-4, 1) // header, tabs, separator, footer
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionCompareDiff, 2+listWidth+3, 4, diffWidth, bodyHeight, nil)
+	p.mouseHandler.HitMap.AddRect(regionCompareList, 2, 4, listWidth, bodyHeight, nil)
+
+	lines := []string{
+		ansi.Truncate(p.renderCompareHeader(), contentWidth, "…"),
+		ansi.Truncate(p.renderCompareTabs(), contentWidth, ""),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+
+	var listLines, diffLines []string
+	switch p.compareTab {
+	case compareTabCommits:
+		listLines = p.renderCompareCommits(listWidth, bodyHeight)
+		diffLines = p.renderCompareCommitDiff(diffWidth, bodyHeight)
+	case compareTabDiff:
+		listLines = p.renderCompareFiles(listWidth, bodyHeight)
+		diffLines = p.renderCompareDiff(diffWidth, bodyHeight)
+	case compareTabRangeDiff:
+		listLines = p.renderRangeDiffList(listWidth, bodyHeight)
+		diffLines = p.renderRangeDiffDetail(diffWidth, bodyHeight)
+	}
+	sep := styles.Muted.Render(" │ ")
+	for i := range bodyHeight {
+		left := ""
+		if i < len(listLines) {
+			left = listLines[i]
+		}
+		right := ""
+		if i < len(diffLines) {
+			right = diffLines[i]
+		}
+		lines = append(lines, padToWidth(left, listWidth)+sep+right)
+	}
+
+	footer := "tab view  j/k commit  ctrl+d/u scroll diff  v split  s swap  e refs  y copy hash  esc close"
+	switch p.compareTab {
+	case compareTabDiff:
+		footer = "tab view  j/k file  ctrl+d/u scroll diff  v split  t merge base/tips  s swap  e refs  esc close"
+	case compareTabRangeDiff:
+		footer = "tab view  j/k commit  ctrl+d/u scroll diff  s swap  e refs  y copy hash  esc close"
+	}
+	lines = append(lines, styles.Muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
+
+// renderCompareHeader renders the compared refs and how far they diverged.
+func (p *Plugin) renderCompareHeader() string {
+	header := styles.Title.Render("Compare ") + styles.Code.Render(p.compareBase) +
+		styles.Muted.Render("...") + styles.Code.Render(p.compareRef)
+	cmp := p.compare
+	if cmp == nil {
+		return header
+	}
+	header += " " + styles.StatusStaged.Render(fmt.Sprintf("↑%d", cmp.AheadCount)) +
+		" " + styles.StatusDeleted.Render(fmt.Sprintf("↓%d", cmp.BehindCount))
+	if cmp.MergeBase != "" {
+		header += styles.Muted.Render(" · merge base " + cmp.MergeBase)
+	} else {
+		header += styles.Muted.Render(" · unrelated histories")
+	}
+	return header
+}
+
+// renderCompareTabs renders the tab chips and registers their click areas.
+func (p *Plugin) renderCompareTabs() string {
+	commits := "Commits"
+	if p.compare != nil {
+		commits = fmt.Sprintf("Commits ↑%d ↓%d", p.compare.AheadCount, p.compare.BehindCount)
+	}
+	diff := "Diff"
+	if p.compareDiff != nil {
+		diff = fmt.Sprintf("Diff %d files", len(p.compareDiff.Files))
+	}
+	if p.compareDirect {
+		diff += " (tips)"
+	}
+	names := []string{commits, diff, "Range-diff"}
+
+	var chips []string
+	x := 2
+	for i, name := range names {
+		style := styles.BarChip
+		if compareTab(i) == p.compareTab {
+			style = styles.BarChipActive
+		}
+		chip := style.Render(fmt.Sprintf("%d %s", i+1, name))
+		p.mouseHandler.HitMap.AddRect(regionCompareTab, x, 2, ansi.StringWidth(chip), 1, i)
+		x += ansi.StringWidth(chip) + 1
+		chips = append(chips, chip)
+	}
+	return strings.Join(chips, " ")
+}
+
+// renderCompareCommits renders the visible rows of the commit column:
+// commits ahead of base, then commits behind it.
+func (p *Plugin) renderCompareCommits(width, height int) []string {
+	if p.compare == nil {
+		return []string{styles.Muted.Render("Loading commits...")}
+	}
+	commits := p.compareCommits()
+	if len(commits) == 0 {
+		return []string{styles.Muted.Render("Both refs point at the same history")}
+	}
+
+	p.scrollCompareList(len(commits), height)
+	var lines []string
+	end := min(p.compareListScroll+height, len(commits))
+	for i := p.compareListScroll; i < end; i++ {
+		lines = append(lines, p.renderCompareCommitRow(commits[i], i < len(p.compare.Ahead), width, i == p.compareCursor))
+	}
+	return lines
+}
+
+// scrollCompareList keeps the cursor visible in a list of n rows.
+func (p *Plugin) scrollCompareList(n, height int) {
+	if p.compareCursor < p.compareListScroll {
+		p.compareListScroll = p.compareCursor
+	}
+	if p.compareCursor >= p.compareListScroll+height {
+		p.compareListScroll = p.compareCursor - height + 1
+	}
+	p.compareListScroll = min(p.compareListScroll, max(n-height, 0))
+}
+
+// renderCompareCommitRow renders one commit: side, hash, subject and age.
+func (p *Plugin) renderCompareCommitRow(c *Commit, ahead bool, width int, selected bool) string {
+	arrow, arrowStyle := "↓", styles.StatusDeleted
+	if ahead {
+		arrow, arrowStyle = "↑", styles.StatusStaged
+	}
+	prefix := arrow + " " + c.ShortHash + " "
+	suffix := " " + RelativeTime(c.Date)
+	textW := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(suffix), 5)
+	subject := ansi.Truncate(c.Subject, textW, "…")
+	pad := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(subject)-ansi.StringWidth(suffix), 0)
+
+	if selected {
+		return styles.ListItemSelected.Render(prefix + subject + strings.Repeat(" ", pad) + suffix)
+	}
+	return arrowStyle.Render(arrow) + " " + styles.Code.Render(c.ShortHash) + " " + subject +
+		strings.Repeat(" ", pad) + styles.Muted.Render(suffix)
+}
+
+// renderCompareCommitDiff renders the selected commit's diff.
+func (p *Plugin) renderCompareCommitDiff(width, height int) []string {
+	c := p.selectedCompareCommit()
+	if c == nil {
+		return nil
+	}
+	switch {
+	case p.compareCommitDiffHash != c.Hash:
+		return []string{styles.Muted.Render("Loading diff...")}
+	case p.compareCommitDiff == nil || len(p.compareCommitDiff.Files) == 0:
+		text := "No changes"
+		if strings.HasPrefix(p.compareCommitDiffRaw, "Error: ") {
+			text = p.compareCommitDiffRaw
+		}
+		return []string{styles.Muted.Render(ansi.Truncate(text, width, "…"))}
+	}
+	return p.renderCommitDiffLines(p.compareCommitDiff, &p.compareDiffScroll, width, height)
+}
+
+// renderCompareFiles renders the visible rows of the file column.
+func (p *Plugin) renderCompareFiles(width, height int) []string {
+	if !p.compareDiffLoaded {
+		return []string{styles.Muted.Render("Loading diff...")}
+	}
+	if p.compareDiff == nil || len(p.compareDiff.Files) == 0 {
+		return []string{styles.Muted.Render("No changes")}
+	}
+
+	files := p.compareDiff.Files
+	p.scrollCompareList(len(files), height)
+	var lines []string
+	end := min(p.compareListScroll+height, len(files))
+	for i := p.compareListScroll; i < end; i++ {
+		f := &files[i]
+		stats := " " + f.ChangeStats()
+		name := ansi.Truncate(f.FileName(), max(width-ansi.StringWidth(stats), 5), "…")
+		pad := max(width-ansi.StringWidth(name)-ansi.StringWidth(stats), 0)
+		if i == p.compareCursor {
+			lines = append(lines, styles.ListItemSelected.Render(name+strings.Repeat(" ", pad)+stats))
+			continue
+		}
+		lines = append(lines, name+strings.Repeat(" ", pad)+styles.Muted.Render(stats))
+	}
+	return lines
+}
+
+// renderCompareDiff renders the combined diff.
+func (p *Plugin) renderCompareDiff(width, height int) []string {
+	switch {
+	case !p.compareDiffLoaded:
+		return []string{styles.Muted.Render("Loading diff...")}
+	case p.compareDiff == nil || len(p.compareDiff.Files) == 0:
+		text := "No changes"
+		if strings.HasPrefix(p.compareDiffRaw, "Error: ") {
+			text = p.compareDiffRaw
+		}
+		return []string{styles.Muted.Render(ansi.Truncate(text, width, "…"))}
+	}
+	return p.renderCommitDiffLines(p.compareDiff, &p.compareDiffScroll, width, height)
+}
+
+// renderRangeDiffList renders the visible rows of the range-diff column.
+func (p *Plugin) renderRangeDiffList(width, height int) []string {
+	switch {
+	case !p.compareRangeDiffLoaded:
+		return []string{styles.Muted.Render("Running range-diff...")}
+	case p.compareRangeDiffErr != "":
+		return []string{styles.StatusDeleted.Render(ansi.Truncate(p.compareRangeDiffErr, width, "…"))}
+	case len(p.compareRangeDiff) == 0:
+		return []string{styles.Muted.Render("Both refs point at the same history")}
+	}
+
+	p.scrollCompareList(len(p.compareRangeDiff), height)
+	var lines []string
+	end := min(p.compareListScroll+height, len(p.compareRangeDiff))
+	for i := p.compareListScroll; i < end; i++ {
+		lines = append(lines, p.renderRangeDiffRow(p.compareRangeDiff[i], width, i == p.compareCursor))
+	}
+	return lines
+}
+
+// rangeDiffStatusStyle colors a range-diff status: unchanged, changed,
+// dropped from base or new in compare.
+func rangeDiffStatusStyle(status byte) func(...string) string {
+	switch status {
+	case '!':
+		return styles.StatusModified.Render
+	case '<':
+		return styles.StatusDeleted.Render
+	case '>':
+		return styles.StatusStaged.Render
+	}
+	return styles.Muted.Render
+}
+
+// renderRangeDiffRow renders one range-diff pair: status, hashes, subject.
+func (p *Plugin) renderRangeDiffRow(e *RangeDiffEntry, width int, selected bool) string {
+	hash := func(h string) string {
+		if h == "" {
+			return strings.Repeat("-", 7)
+		}
+		return fmt.Sprintf("%-7s", h[:min(len(h), 7)])
+	}
+	hashes := hash(e.OldHash) + " " + hash(e.NewHash)
+	subject := ansi.Truncate(e.Subject, max(width-ansi.StringWidth(hashes)-3, 5), "…")
+	status := string(e.Status)
+
+	if selected {
+		return styles.ListItemSelected.Render(padToWidth(status+" "+hashes+" "+subject, width))
+	}
+	return rangeDiffStatusStyle(e.Status)(status) + " " + styles.Code.Render(hashes) + " " + subject
+}
+
+// renderRangeDiffDetail renders the selected pair: the diff between its two
+// patches when it changed, else the commit's own diff.
+func (p *Plugin) renderRangeDiffDetail(width, height int) []string {
+	e := p.selectedRangeDiffEntry()
+	if e == nil {
+		return nil
+	}
+	if e.Status != '!' {
+		var note string
+		switch e.Status {
+		case '=':
+			note = "Patch unchanged"
+		case '<':
+			note = "Only in " + p.compareBase
+		case '>':
+			note = "Only in " + p.compareRef
+		}
+		lines := []string{rangeDiffStatusStyle(e.Status)(ansi.Truncate(note, width, "…"))}
+		return append(lines, p.renderCompareCommitDiff(width, max(height-1, 1))...)
+	}
+
+	lines := []string{styles.StatusModified.Render(ansi.Truncate("Patch changed: "+e.OldHash+" → "+e.NewHash, width, "…"))}
+	bodyHeight := max(height-1, 1)
+	p.compareDiffScroll = min(p.compareDiffScroll, max(len(e.Diff)-bodyHeight, 0))
+	end := min(p.compareDiffScroll+bodyHeight, len(e.Diff))
+	for _, line := range e.Diff[p.compareDiffScroll:end] {
+		lines = append(lines, renderRangeDiffLine(ansi.Truncate(line, width, "")))
+	}
+	return lines
+}
+
+// renderRangeDiffLine colors a line of a diff between patches by its outer
+// marker: what the rebase removed or added.
+func renderRangeDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		return styles.Muted.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return styles.DiffRemove.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return styles.DiffAdd.Render(line)
+	}
+	return line
+}
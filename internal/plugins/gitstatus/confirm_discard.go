@@ -29,7 +29,11 @@ func (p *Plugin) buildDiscardModal() {
 
 	// Determine warning message
 	var warningMsg string
-	if entry.Status == StatusUntracked {
+	prompt := fmt.Sprintf("Discard %s changes to:", statusLabel)
+	if p.discardPatch != "" {
+		prompt = "Discard selected changes in:"
+		warningMsg = styles.Muted.Render("The selected lines will be reverted in the working tree.")
+	} else if entry.Status == StatusUntracked {
 		warningMsg = styles.StatusDeleted.Render("This will permanently delete the file!")
 	} else {
 		warningMsg = styles.Muted.Render("This will revert to the last committed state.")
@@ -48,7 +52,7 @@ func (p *Plugin) buildDiscardModal() {
 		modal.WithVariant(modal.VariantDanger),
 		modal.WithWidth(modalWidth),
 	).
-		AddSection(modal.Text(fmt.Sprintf("Discard %s changes to:", statusLabel))).
+		AddSection(modal.Text(prompt)).
 		AddSection(modal.Text(styles.Subtitle.Render(entry.Path))).
 		AddSection(modal.Spacer()).
 		AddSection(modal.Text(warningMsg)).
@@ -98,9 +98,9 @@ func (p *Plugin) handleStashPopMouse(msg tea.MouseMsg) (plugin.Plugin, tea.Cmd)
 func (p *Plugin) executeStashPop() (plugin.Plugin, tea.Cmd) {
 	var cmd tea.Cmd
 	if p.stashPopItem != nil {
-		cmd = p.doStashPop()
+		cmd = p.doStashPop(p.stashPopItem.Ref)
 	}
-	p.viewMode = ViewModeStatus
+	p.viewMode = p.stashPopReturnMode
 	p.stashPopItem = nil
 	p.stashPopModal = nil
 	return p, cmd
@@ -108,7 +108,7 @@ func (p *Plugin) executeStashPop() (plugin.Plugin, tea.Cmd) {
 
 // cancelStashPop closes the modal without popping.
 func (p *Plugin) cancelStashPop() (plugin.Plugin, tea.Cmd) {
-	p.viewMode = ViewModeStatus
+	p.viewMode = p.stashPopReturnMode
 	p.stashPopItem = nil
 	p.stashPopModal = nil
 	return p, nil
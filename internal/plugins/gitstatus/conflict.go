@@ -0,0 +1,461 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictChoice is how a conflict block is resolved.
+type ConflictChoice int
+
+const (
+	ConflictUnresolved ConflictChoice = iota
+	ConflictOurs
+	ConflictTheirs
+	ConflictBoth // Ours followed by theirs
+)
+
+// String returns a short label for the choice.
+func (c ConflictChoice) String() string {
+	switch c {
+	case ConflictOurs:
+		return "ours"
+	case ConflictTheirs:
+		return "theirs"
+	case ConflictBoth:
+		return "both"
+	default:
+		return "unresolved"
+	}
+}
+
+// ConflictHunk is one block between <<<<<<< and >>>>>>> markers.
+type ConflictHunk struct {
+	Ours          []string
+	Base          []string
+	Theirs        []string
+	OursLabel     string // Text after <<<<<<<, e.g. "HEAD"
+	TheirsLabel   string // Text after >>>>>>>, e.g. "origin/main"
+	BaseAvailable bool   // Base came from a diff3 marker or the merge base blob
+	Line          int    // 1-based line of the <<<<<<< marker
+	Choice        ConflictChoice
+
+	// Raw marker lines, kept so unresolved blocks are written back unchanged
+	startMarker string
+	baseMarker  string // Empty when the file has no diff3 base section
+	sepMarker   string
+	endMarker   string
+}
+
+// Result returns the lines written for the hunk's current choice.
+func (h *ConflictHunk) Result() []string {
+	switch h.Choice {
+	case ConflictOurs:
+		return h.Ours
+	case ConflictTheirs:
+		return h.Theirs
+	case ConflictBoth:
+		return append(append([]string{}, h.Ours...), h.Theirs...)
+	}
+	return nil
+}
+
+// conflictSegment is either unconflicted lines or a conflict hunk.
+type conflictSegment struct {
+	lines []string
+	hunk  *ConflictHunk
+}
+
+// ConflictFile is a conflicted file split into clean text and conflict hunks.
+type ConflictFile struct {
+	Path     string
+	Hunks    []*ConflictHunk
+	segments []conflictSegment
+	noEOL    bool // Content did not end with a newline
+}
+
+// conflictMarker returns the marker kind if line is a conflict marker
+// ('<', '|', '=', '>') and the text following it.
+func conflictMarker(line string) (byte, string, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	if len(line) < 7 {
+		return 0, "", false
+	}
+	c := line[0]
+	if c != '<' && c != '|' && c != '=' && c != '>' {
+		return 0, "", false
+	}
+	if line[:7] != strings.Repeat(string(c), 7) {
+		return 0, "", false
+	}
+	rest := line[7:]
+	if c == '=' {
+		return c, "", rest == ""
+	}
+	if rest != "" && rest[0] != ' ' {
+		return 0, "", false
+	}
+	return c, strings.TrimPrefix(rest, " "), true
+}
+
+// ParseConflicts splits content into clean segments and conflict hunks.
+func ParseConflicts(path string, content []byte) (*ConflictFile, error) {
+	text := string(content)
+	cf := &ConflictFile{Path: path}
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		cf.noEOL = true
+	}
+	text = strings.TrimSuffix(text, "\n")
+	var lines []string
+	if text != "" || len(content) > 0 {
+		lines = strings.Split(text, "\n")
+	}
+
+	const (
+		inText = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	stateNow := inText
+	var clean []string
+	var hunk *ConflictHunk
+
+	for i, line := range lines {
+		kind, label, isMarker := conflictMarker(line)
+		switch stateNow {
+		case inText:
+			if isMarker && kind == '<' {
+				if len(clean) > 0 {
+					cf.segments = append(cf.segments, conflictSegment{lines: clean})
+					clean = nil
+				}
+				hunk = &ConflictHunk{OursLabel: label, Line: i + 1, startMarker: line}
+				stateNow = inOurs
+				continue
+			}
+			clean = append(clean, line)
+		case inOurs:
+			switch {
+			case isMarker && kind == '|':
+				hunk.baseMarker = line
+				hunk.BaseAvailable = true
+				stateNow = inBase
+			case isMarker && kind == '=':
+				hunk.sepMarker = line
+				stateNow = inTheirs
+			default:
+				hunk.Ours = append(hunk.Ours, line)
+			}
+		case inBase:
+			if isMarker && kind == '=' {
+				hunk.sepMarker = line
+				stateNow = inTheirs
+				continue
+			}
+			hunk.Base = append(hunk.Base, line)
+		case inTheirs:
+			if isMarker && kind == '>' {
+				hunk.TheirsLabel = label
+				hunk.endMarker = line
+				cf.Hunks = append(cf.Hunks, hunk)
+				cf.segments = append(cf.segments, conflictSegment{hunk: hunk})
+				hunk = nil
+				stateNow = inText
+				continue
+			}
+			hunk.Theirs = append(hunk.Theirs, line)
+		}
+	}
+	if stateNow != inText {
+		return nil, fmt.Errorf("%s: unterminated conflict starting at line %d", path, hunk.Line)
+	}
+	if len(clean) > 0 {
+		cf.segments = append(cf.segments, conflictSegment{lines: clean})
+	}
+	return cf, nil
+}
+
+// Remaining returns the number of unresolved hunks.
+func (cf *ConflictFile) Remaining() int {
+	n := 0
+	for _, h := range cf.Hunks {
+		if h.Choice == ConflictUnresolved {
+			n++
+		}
+	}
+	return n
+}
+
+// Changed reports whether any hunk has been resolved in memory.
+func (cf *ConflictFile) Changed() bool {
+	return cf.Remaining() < len(cf.Hunks)
+}
+
+// Render returns the file content with resolved hunks replaced by their
+// result and unresolved hunks written back with their markers.
+func (cf *ConflictFile) Render() []byte {
+	var lines []string
+	for _, seg := range cf.segments {
+		h := seg.hunk
+		switch {
+		case h == nil:
+			lines = append(lines, seg.lines...)
+		case h.Choice != ConflictUnresolved:
+			lines = append(lines, h.Result()...)
+		default:
+			lines = append(lines, h.startMarker)
+			lines = append(lines, h.Ours...)
+			if h.baseMarker != "" {
+				lines = append(lines, h.baseMarker)
+				lines = append(lines, h.Base...)
+			}
+			lines = append(lines, h.sepMarker)
+			lines = append(lines, h.Theirs...)
+			lines = append(lines, h.endMarker)
+		}
+	}
+	out := strings.Join(lines, "\n")
+	if len(lines) > 0 && !cf.noEOL {
+		out += "\n"
+	}
+	return []byte(out)
+}
+
+// RenderedLine returns the 1-based line where h starts in Render's output.
+func (cf *ConflictFile) RenderedLine(h *ConflictHunk) int {
+	line := 1
+	for _, seg := range cf.segments {
+		switch {
+		case seg.hunk == h:
+			return line
+		case seg.hunk == nil:
+			line += len(seg.lines)
+		case seg.hunk.Choice != ConflictUnresolved:
+			line += len(seg.hunk.Result())
+		default:
+			line += len(seg.hunk.Ours) + len(seg.hunk.Theirs) + 3
+			if seg.hunk.baseMarker != "" {
+				line += len(seg.hunk.Base) + 1
+			}
+		}
+	}
+	return line
+}
+
+// LoadConflictFile reads and parses a conflicted file. When the markers have
+// no base section (git's default "merge" conflict style), the base of each
+// hunk is recovered from the index stages.
+func LoadConflictFile(workDir, path string) (*ConflictFile, error) {
+	data, err := os.ReadFile(filepath.Join(workDir, path))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	cf, err := ParseConflicts(path, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range cf.Hunks {
+		if !h.BaseAvailable {
+			fillConflictBase(workDir, cf)
+			break
+		}
+	}
+	return cf, nil
+}
+
+// fillConflictBase re-merges the index stages with diff3 markers and copies
+// the base of each matching hunk. Hunks without a match keep no base.
+func fillConflictBase(workDir string, cf *ConflictFile) {
+	tmpDir, err := os.MkdirTemp("", "sidecar-conflict-")
+	if err != nil {
+		return
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	var files []string
+	for _, stage := range []string{"2", "1", "3"} {
+		cmd := exec.Command("git", "show", ":"+stage+":"+cf.Path)
+		cmd.Dir = workDir
+		out, err := cmd.Output()
+		if err != nil {
+			if stage != "1" {
+				return // Ours or theirs missing: a delete conflict
+			}
+			out = nil // Added on both sides: empty base
+		}
+		f := filepath.Join(tmpDir, stage)
+		if err := os.WriteFile(f, out, 0600); err != nil {
+			return
+		}
+		files = append(files, f)
+	}
+
+	args := append([]string{"merge-file", "-p", "--diff3", "-L", "ours", "-L", "base", "-L", "theirs"}, files...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return // merge-file exits with the number of conflicts
+	}
+	merged, err := ParseConflicts(cf.Path, out)
+	if err != nil {
+		return
+	}
+
+	used := make([]bool, len(merged.Hunks))
+	for _, h := range cf.Hunks {
+		if h.BaseAvailable {
+			continue
+		}
+		for i, m := range merged.Hunks {
+			if used[i] || !sameLines(h.Ours, m.Ours) || !sameLines(h.Theirs, m.Theirs) {
+				continue
+			}
+			h.Base = m.Base
+			h.BaseAvailable = true
+			used[i] = true
+			break
+		}
+	}
+}
+
+func sameLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimSuffix(a[i], "\r") != strings.TrimSuffix(b[i], "\r") {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteConflictFile writes rendered resolution content (see
+// ConflictFile.Render) to the working tree, keeping the file's permissions.
+func WriteConflictFile(workDir, path string, content []byte) error {
+	full := filepath.Join(workDir, path)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(full); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(full, content, mode)
+}
+
+// MarkConflictResolved stages a conflicted file once no markers remain. A
+// file missing from the working tree is staged as deleted.
+func MarkConflictResolved(workDir, path string) error {
+	full := filepath.Join(workDir, path)
+	data, err := os.ReadFile(full)
+	if os.IsNotExist(err) {
+		return runGit(workDir, "rm", "--quiet", "--cached", "--", path)
+	}
+	if err != nil {
+		return err
+	}
+	cf, err := ParseConflicts(path, data)
+	if err != nil {
+		return err
+	}
+	if n := len(cf.Hunks); n > 0 {
+		return fmt.Errorf("%s still has %d conflict(s)", path, n)
+	}
+	return runGit(workDir, "add", "--", path)
+}
+
+// ResolveWholeFile resolves a file to one side's version and stages it. When
+// that side deleted the file, the deletion is staged.
+func ResolveWholeFile(workDir, path string, ours bool) error {
+	stage, flag := "3", "--theirs"
+	if ours {
+		stage, flag = "2", "--ours"
+	}
+	cmd := exec.Command("git", "cat-file", "-e", ":"+stage+":"+path)
+	cmd.Dir = workDir
+	if cmd.Run() != nil {
+		return runGit(workDir, "rm", "--quiet", "--force", "--", path)
+	}
+	if err := runGit(workDir, "checkout", flag, "--", path); err != nil {
+		return err
+	}
+	return runGit(workDir, "add", "--", path)
+}
+
+// runGit runs a git command, returning its output in the error on failure.
+func runGit(workDir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ConflictOperation returns the operation waiting on conflict resolution:
+// "am", "rebase", "cherry-pick", "revert" or "merge", or "" if none.
+func ConflictOperation(workDir string) string {
+	// git am shares rebase-apply with rebase, so check it first
+	if AmInProgress(workDir) {
+		return "am"
+	}
+	if IsRebaseInProgress(workDir) {
+		return "rebase"
+	}
+	if op := SequencerInProgress(workDir); op != "" {
+		return op
+	}
+	if gitPathExists(workDir, "MERGE_HEAD") {
+		return "merge"
+	}
+	return ""
+}
+
+// ContinueOperation continues op once its conflicts are resolved. A merge is
+// concluded by committing with the prepared message.
+func ContinueOperation(workDir, op string) (string, error) {
+	switch op {
+	case "rebase":
+		return ContinueRebase(workDir)
+	case "cherry-pick", "revert":
+		return ContinueSequencer(workDir, op)
+	case "am":
+		cmd := exec.Command("git", "am", "--continue")
+		cmd.Dir = workDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", &RemoteError{Output: string(output), Err: err}
+		}
+		return string(output), nil
+	case "merge":
+		cmd := exec.Command("git", "commit", "--no-edit")
+		cmd.Dir = workDir
+		cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", &RemoteError{Output: string(output), Err: err}
+		}
+		return string(output), nil
+	}
+	return "", fmt.Errorf("no operation in progress")
+}
+
+// AbortOperation aborts op and restores the state before it started.
+func AbortOperation(workDir, op string) error {
+	switch op {
+	case "rebase":
+		return AbortRebase(workDir)
+	case "cherry-pick", "revert":
+		return AbortSequencer(workDir, op)
+	case "am":
+		return runGit(workDir, "am", "--abort")
+	case "merge":
+		return AbortMerge(workDir)
+	}
+	return fmt.Errorf("no operation in progress")
+}
@@ -0,0 +1,170 @@
+package gitstatus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConflictsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"merge style", "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nb\n"},
+		{"diff3 style", "<<<<<<< HEAD\nours\n||||||| base\nbase\n=======\ntheirs\n>>>>>>> feature\n"},
+		{"no trailing newline", "a\n<<<<<<< HEAD\n=======\ntheirs\n>>>>>>> feature\nb"},
+		{"no conflicts", "plain\ntext\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cf, err := ParseConflicts("f.txt", []byte(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(cf.Render()); got != tt.content {
+				t.Errorf("Render() = %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestParseConflictsHunks(t *testing.T) {
+	content := "a\n<<<<<<< HEAD\nours\n||||||| base\nbase\n=======\ntheirs 1\ntheirs 2\n>>>>>>> feature\nb\n"
+	cf, err := ParseConflicts("f.txt", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cf.Hunks) != 1 {
+		t.Fatalf("got %d hunks, want 1", len(cf.Hunks))
+	}
+	h := cf.Hunks[0]
+	if h.Line != 2 || h.OursLabel != "HEAD" || h.TheirsLabel != "feature" || !h.BaseAvailable {
+		t.Errorf("hunk = %+v", h)
+	}
+	if !sameLines(h.Ours, []string{"ours"}) || !sameLines(h.Base, []string{"base"}) ||
+		!sameLines(h.Theirs, []string{"theirs 1", "theirs 2"}) {
+		t.Errorf("sides = %q / %q / %q", h.Ours, h.Base, h.Theirs)
+	}
+
+	// A line of seven '=' followed by text is content, not a separator
+	if _, err := ParseConflicts("g.txt", []byte("<<<<<<< HEAD\n======= x\n")); err == nil {
+		t.Error("expected unterminated conflict error")
+	}
+}
+
+func TestConflictChoicesRender(t *testing.T) {
+	content := "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nb\n<<<<<<< HEAD\nx\n=======\ny\n>>>>>>> feature\n"
+	tests := []struct {
+		first, second ConflictChoice
+		want          string
+		secondLine    int // Line of the second block in the output
+	}{
+		{ConflictOurs, ConflictTheirs, "a\nours\nb\ny\n", 4},
+		{ConflictTheirs, ConflictOurs, "a\ntheirs\nb\nx\n", 4},
+		{ConflictBoth, ConflictBoth, "a\nours\ntheirs\nb\nx\ny\n", 5},
+		{ConflictUnresolved, ConflictOurs, "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nb\nx\n", 8},
+	}
+	for _, tt := range tests {
+		cf, err := ParseConflicts("f.txt", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cf.Hunks[0].Choice = tt.first
+		cf.Hunks[1].Choice = tt.second
+		if got := string(cf.Render()); got != tt.want {
+			t.Errorf("%s/%s: Render() = %q, want %q", tt.first, tt.second, got, tt.want)
+		}
+		if line := cf.RenderedLine(cf.Hunks[1]); line != tt.secondLine {
+			t.Errorf("%s/%s: RenderedLine = %d, want %d", tt.first, tt.second, line, tt.secondLine)
+		}
+	}
+}
+
+// initConflictRepo creates a merge conflict in file.txt between the trunk
+// and a "feature" branch, leaving the merge stopped.
+func initConflictRepo(t *testing.T) string {
+	t.Helper()
+	dir := initPartialRepo(t, "one\ntwo\nthree\n")
+	trunk := gitRun(t, dir, "branch", "--show-current")
+	gitRun(t, dir, "checkout", "-q", "-b", "feature")
+	commitFile(t, dir, "file.txt", "one\ntheirs\nthree\n", "theirs")
+	gitRun(t, dir, "checkout", "-q", trunk)
+	commitFile(t, dir, "file.txt", "one\nours\nthree\n", "ours")
+
+	// Default marker style, so the base has to come from the index
+	if err := runGit(dir, "-c", "merge.conflictStyle=merge", "merge", "feature"); err == nil {
+		t.Fatal("expected merge conflict")
+	}
+	return dir
+}
+
+func TestResolveMergeConflict(t *testing.T) {
+	dir := initConflictRepo(t)
+
+	if op := ConflictOperation(dir); op != "merge" {
+		t.Fatalf("ConflictOperation = %q, want merge", op)
+	}
+	cf, err := LoadConflictFile(dir, "file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cf.Hunks) != 1 {
+		t.Fatalf("got %d hunks, want 1", len(cf.Hunks))
+	}
+	h := cf.Hunks[0]
+	if !h.BaseAvailable || !sameLines(h.Base, []string{"two"}) {
+		t.Errorf("base = %q (available %v), want [two]", h.Base, h.BaseAvailable)
+	}
+
+	if err := MarkConflictResolved(dir, "file.txt"); err == nil {
+		t.Error("MarkConflictResolved should refuse while markers remain")
+	}
+
+	h.Choice = ConflictBoth
+	if err := WriteConflictFile(dir, cf.Path, cf.Render()); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarkConflictResolved(dir, "file.txt"); err != nil {
+		t.Fatalf("MarkConflictResolved: %v", err)
+	}
+	if files := GetConflictedFiles(dir); len(files) != 0 {
+		t.Fatalf("still conflicted: %v", files)
+	}
+
+	if _, err := ContinueOperation(dir, "merge"); err != nil {
+		t.Fatalf("ContinueOperation: %v", err)
+	}
+	if op := ConflictOperation(dir); op != "" {
+		t.Errorf("operation still in progress: %q", op)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "one\nours\ntheirs\nthree\n" {
+		t.Errorf("merged content = %q", data)
+	}
+	if parents := gitRun(t, dir, "log", "-1", "--format=%P"); len(strings.Fields(parents)) != 2 {
+		t.Errorf("expected a merge commit, parents = %q", parents)
+	}
+}
+
+func TestResolveWholeFile(t *testing.T) {
+	dir := initConflictRepo(t)
+
+	if err := ResolveWholeFile(dir, "file.txt", false); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "one\ntheirs\nthree\n" {
+		t.Errorf("content = %q", data)
+	}
+	if files := GetConflictedFiles(dir); len(files) != 0 {
+		t.Errorf("still conflicted: %v", files)
+	}
+}
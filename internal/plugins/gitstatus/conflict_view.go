@@ -0,0 +1,756 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/plugins/filebrowser"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// conflictMaxFileRows caps the file list at the top of the resolver.
+const conflictMaxFileRows = 5
+
+// conflictMaxResultRows caps the result preview below the three panes.
+const conflictMaxResultRows = 5
+
+// OpenConflictResolverMsg asks the git plugin to open the conflict resolver,
+// e.g. after a workspace merge stopped on conflicts. It is ignored when
+// WorkDir belongs to a different repository.
+type OpenConflictResolverMsg struct {
+	WorkDir string
+}
+
+// ConflictsLoadedMsg carries the operation in progress and its conflicted files.
+type ConflictsLoadedMsg struct {
+	Epoch uint64
+	Op    string
+	Files []string
+	Focus string // File to select, if still conflicted
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ConflictsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// ConflictFileLoadedMsg carries a parsed conflicted file.
+type ConflictFileLoadedMsg struct {
+	Epoch uint64
+	Path  string
+	File  *ConflictFile
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ConflictFileLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// ConflictSavedMsg is sent when a file's resolution has been written, and
+// staged once no conflicts remain in it.
+type ConflictSavedMsg struct {
+	Path      string
+	Staged    bool
+	Remaining int // Unresolved blocks left in the file
+	Err       error
+}
+
+// ConflictOpDoneMsg is sent when the stopped operation has been continued
+// or aborted.
+type ConflictOpDoneMsg struct {
+	Op      string
+	Aborted bool
+	Err     error
+}
+
+// openConflictResolver opens the resolver for the conflicted files, selecting
+// focus if it is one of them.
+func (p *Plugin) openConflictResolver(focus string) tea.Cmd {
+	p.clearConflictState()
+	p.conflictDone = make(map[string]bool)
+	p.viewMode = ViewModeConflicts
+	return p.loadConflicts(focus)
+}
+
+// handleOpenConflictResolver opens the resolver on request from another
+// plugin when the request targets this repository.
+func (p *Plugin) handleOpenConflictResolver(msg OpenConflictResolverMsg) tea.Cmd {
+	if msg.WorkDir != "" {
+		root, err := resolveGitRoot(msg.WorkDir)
+		if err != nil || root != p.repoRoot {
+			return nil
+		}
+	}
+	return p.openConflictResolver("")
+}
+
+// loadConflicts reads the operation in progress and the unmerged files.
+func (p *Plugin) loadConflicts(focus string) tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		return ConflictsLoadedMsg{
+			Epoch: epoch,
+			Op:    ConflictOperation(workDir),
+			Files: GetConflictedFiles(workDir),
+			Focus: focus,
+		}
+	}
+}
+
+// handleConflictsLoaded updates the file list. Files that are no longer
+// unmerged are kept in the list and marked resolved.
+func (p *Plugin) handleConflictsLoaded(msg ConflictsLoadedMsg) tea.Cmd {
+	if p.viewMode != ViewModeConflicts {
+		return nil
+	}
+	first := !p.conflictLoaded
+	p.conflictLoaded = true
+	p.conflictOp = msg.Op
+	if first && len(msg.Files) == 0 {
+		p.closeConflictView()
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "No conflicted files", Duration: 2 * time.Second}
+		}
+	}
+
+	unmerged := make(map[string]bool, len(msg.Files))
+	for _, f := range msg.Files {
+		unmerged[f] = true
+	}
+	known := make(map[string]bool, len(p.conflictFiles))
+	for _, f := range p.conflictFiles {
+		known[f] = true
+		p.conflictDone[f] = !unmerged[f]
+	}
+	for _, f := range msg.Files {
+		if !known[f] {
+			p.conflictFiles = append(p.conflictFiles, f)
+			p.conflictDone[f] = false
+		}
+	}
+
+	idx := -1
+	if msg.Focus != "" {
+		for i, f := range p.conflictFiles {
+			if f == msg.Focus && !p.conflictDone[f] {
+				idx = i
+			}
+		}
+	}
+	if idx < 0 {
+		idx = p.nextConflictFile(p.conflictFileIdx, 0)
+	}
+	if idx < 0 {
+		// Everything is resolved; the view offers to continue
+		p.conflictFile = nil
+		return nil
+	}
+	if idx == p.conflictFileIdx && p.conflictFile != nil {
+		return nil // Keep in-memory choices for the current file
+	}
+	return p.selectConflictFile(idx)
+}
+
+// nextConflictFile returns the first unresolved file at or after from+step,
+// searching in the direction of step (0 searches forward from from itself)
+// and wrapping around, or -1 if every file is resolved.
+func (p *Plugin) nextConflictFile(from, step int) int {
+	n := len(p.conflictFiles)
+	if n == 0 {
+		return -1
+	}
+	dir := step
+	if dir == 0 {
+		dir = 1
+	}
+	for i := range n {
+		idx := ((from+step+i*dir)%n + n) % n
+		if !p.conflictDone[p.conflictFiles[idx]] {
+			return idx
+		}
+	}
+	return -1
+}
+
+// unresolvedConflictFiles returns the number of files still unmerged.
+func (p *Plugin) unresolvedConflictFiles() int {
+	n := 0
+	for _, f := range p.conflictFiles {
+		if !p.conflictDone[f] {
+			n++
+		}
+	}
+	return n
+}
+
+// selectConflictFile writes pending choices for the current file and loads
+// the file at idx.
+func (p *Plugin) selectConflictFile(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(p.conflictFiles) {
+		return nil
+	}
+	write := p.writeConflictChanges()
+	p.conflictFileIdx = idx
+	p.conflictFile = nil
+	p.conflictHunkIdx = 0
+	p.conflictScroll = 0
+	p.conflictError = ""
+
+	path := p.conflictFiles[idx]
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return tea.Sequence(write, func() tea.Msg {
+		cf, err := LoadConflictFile(workDir, path)
+		return ConflictFileLoadedMsg{Epoch: epoch, Path: path, File: cf, Err: err}
+	})
+}
+
+// handleConflictFileLoaded shows a loaded file, if it is still selected.
+func (p *Plugin) handleConflictFileLoaded(msg ConflictFileLoadedMsg) tea.Cmd {
+	if p.viewMode != ViewModeConflicts || p.conflictFileIdx >= len(p.conflictFiles) ||
+		p.conflictFiles[p.conflictFileIdx] != msg.Path {
+		return nil
+	}
+	if msg.Err != nil {
+		p.conflictError = msg.Err.Error()
+		return nil
+	}
+	p.conflictFile = msg.File
+	p.conflictHunkIdx = 0
+	p.conflictScroll = 0
+	return nil
+}
+
+// writeConflictChanges writes in-memory choices for the current file to the
+// working tree without staging it. Returns nil if nothing was chosen.
+func (p *Plugin) writeConflictChanges() tea.Cmd {
+	cf := p.conflictFile
+	if cf == nil || !cf.Changed() {
+		return nil
+	}
+	path, content := cf.Path, cf.Render()
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		if err := WriteConflictFile(workDir, path, content); err != nil {
+			return ConflictSavedMsg{Path: path, Err: err}
+		}
+		return nil
+	}
+}
+
+// updateConflicts handles key events in the conflict resolver.
+func (p *Plugin) updateConflicts(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return p, p.closeConflicts()
+
+	case "n":
+		p.moveConflictHunk(1)
+	case "N":
+		p.moveConflictHunk(-1)
+	case "]":
+		return p, p.selectConflictFile(p.nextConflictFile(p.conflictFileIdx, 1))
+	case "[":
+		return p, p.selectConflictFile(p.nextConflictFile(p.conflictFileIdx, -1))
+
+	case "j", "down":
+		p.conflictScroll++
+	case "k", "up":
+		p.conflictScroll = max(p.conflictScroll-1, 0)
+	case "ctrl+d":
+		p.conflictScroll += max(p.height/4, 1)
+	case "ctrl+u":
+		p.conflictScroll = max(p.conflictScroll-max(p.height/4, 1), 0)
+
+	case "o":
+		return p, p.chooseConflict(ConflictOurs)
+	case "t":
+		return p, p.chooseConflict(ConflictTheirs)
+	case "b":
+		return p, p.chooseConflict(ConflictBoth)
+	case "x":
+		return p, p.chooseConflict(ConflictUnresolved)
+	case "O":
+		return p, p.chooseAllConflicts(ConflictOurs)
+	case "T":
+		return p, p.chooseAllConflicts(ConflictTheirs)
+
+	case "e":
+		return p, p.editConflictFile()
+	case "s":
+		return p, p.saveConflictFile()
+	case "c":
+		return p, p.continueConflictOp()
+	case "a":
+		return p, p.abortConflictOp()
+	}
+	return p, nil
+}
+
+// moveConflictHunk moves to the next or previous conflict block.
+func (p *Plugin) moveConflictHunk(delta int) {
+	cf := p.conflictFile
+	if cf == nil || len(cf.Hunks) == 0 {
+		return
+	}
+	p.conflictHunkIdx = min(max(p.conflictHunkIdx+delta, 0), len(cf.Hunks)-1)
+	p.conflictScroll = 0
+}
+
+// chooseConflict resolves the current block and moves to the next
+// unresolved one. Files without blocks (modify/delete conflicts) are
+// resolved as a whole.
+func (p *Plugin) chooseConflict(choice ConflictChoice) tea.Cmd {
+	cf := p.conflictFile
+	if cf == nil {
+		return nil
+	}
+	if len(cf.Hunks) == 0 {
+		if choice == ConflictOurs || choice == ConflictTheirs {
+			return p.resolveConflictWholeFile(choice == ConflictOurs)
+		}
+		return nil
+	}
+	cf.Hunks[p.conflictHunkIdx].Choice = choice
+	if choice == ConflictUnresolved {
+		return nil
+	}
+	for i := 1; i < len(cf.Hunks); i++ {
+		idx := (p.conflictHunkIdx + i) % len(cf.Hunks)
+		if cf.Hunks[idx].Choice == ConflictUnresolved {
+			p.conflictHunkIdx = idx
+			p.conflictScroll = 0
+			break
+		}
+	}
+	return nil
+}
+
+// chooseAllConflicts resolves every block in the current file to one side.
+func (p *Plugin) chooseAllConflicts(choice ConflictChoice) tea.Cmd {
+	cf := p.conflictFile
+	if cf == nil {
+		return nil
+	}
+	if len(cf.Hunks) == 0 {
+		return p.resolveConflictWholeFile(choice == ConflictOurs)
+	}
+	for _, h := range cf.Hunks {
+		h.Choice = choice
+	}
+	return nil
+}
+
+// resolveConflictWholeFile takes one side's version of the current file and
+// stages it.
+func (p *Plugin) resolveConflictWholeFile(ours bool) tea.Cmd {
+	path := p.conflictFile.Path
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := ResolveWholeFile(workDir, path, ours)
+		return ConflictSavedMsg{Path: path, Staged: err == nil, Err: err}
+	}
+}
+
+// saveConflictFile writes the current file and stages it if no conflict
+// blocks remain.
+func (p *Plugin) saveConflictFile() tea.Cmd {
+	cf := p.conflictFile
+	if cf == nil {
+		return nil
+	}
+	path, content := cf.Path, cf.Render()
+	changed, remaining := cf.Changed(), cf.Remaining()
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		if changed {
+			if err := WriteConflictFile(workDir, path, content); err != nil {
+				return ConflictSavedMsg{Path: path, Err: err}
+			}
+		}
+		if remaining > 0 {
+			return ConflictSavedMsg{Path: path, Remaining: remaining}
+		}
+		err := MarkConflictResolved(workDir, path)
+		return ConflictSavedMsg{Path: path, Staged: err == nil, Err: err}
+	}
+}
+
+// handleConflictSaved reports a save and moves on once the file is staged.
+func (p *Plugin) handleConflictSaved(msg ConflictSavedMsg) tea.Cmd {
+	if p.viewMode != ViewModeConflicts {
+		if msg.Err != nil {
+			return func() tea.Msg {
+				return app.ToastMsg{Message: "Saving " + msg.Path + " failed: " + msg.Err.Error(), Duration: 3 * time.Second, IsError: true}
+			}
+		}
+		return nil
+	}
+	if msg.Err != nil {
+		p.conflictError = msg.Err.Error()
+		return nil
+	}
+	p.conflictError = ""
+	if !msg.Staged {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: fmt.Sprintf("Saved %s, %d conflict(s) left", msg.Path, msg.Remaining), Duration: 2 * time.Second}
+		}
+	}
+	if p.conflictFile != nil && p.conflictFile.Path == msg.Path {
+		p.conflictFile = nil // Staged; don't write it again
+	}
+	return tea.Batch(p.loadConflicts(""), func() tea.Msg {
+		return app.ToastMsg{Message: "Marked " + msg.Path + " resolved", Duration: 2 * time.Second}
+	})
+}
+
+// editConflictFile writes pending choices and opens the current file in the
+// file browser's inline editor at the current block. The file is reloaded
+// when the git plugin regains focus.
+func (p *Plugin) editConflictFile() tea.Cmd {
+	cf := p.conflictFile
+	if cf == nil {
+		return nil
+	}
+	lineNo := 0
+	if len(cf.Hunks) > 0 {
+		lineNo = cf.RenderedLine(cf.Hunks[p.conflictHunkIdx]) - 1
+	}
+	path := cf.Path
+	write := p.writeConflictChanges()
+	p.conflictEditing = true
+	return tea.Sequence(write, tea.Batch(
+		app.FocusPlugin("file-browser"),
+		func() tea.Msg {
+			return filebrowser.EditFileMsg{Path: path, LineNo: lineNo}
+		},
+	))
+}
+
+// reloadEditedConflict reloads the file list and the current file after it
+// was edited in the file browser.
+func (p *Plugin) reloadEditedConflict() tea.Cmd {
+	p.conflictEditing = false
+	focus := ""
+	if p.conflictFile != nil {
+		focus = p.conflictFile.Path
+	}
+	p.conflictFile = nil
+	return p.loadConflicts(focus)
+}
+
+// continueConflictOp continues the stopped operation once every file is
+// resolved.
+func (p *Plugin) continueConflictOp() tea.Cmd {
+	op := p.conflictOp
+	if op == "" {
+		return nil
+	}
+	if n := p.unresolvedConflictFiles(); n > 0 {
+		p.conflictError = fmt.Sprintf("%d file(s) still conflicted", n)
+		return nil
+	}
+	p.conflictError = ""
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		before := pendingOrigHead(workDir)
+		_, err := ContinueOperation(workDir, op)
+		logOperation(workDir, op, before)
+		return ConflictOpDoneMsg{Op: op, Err: err}
+	}
+}
+
+// abortConflictOp aborts the stopped operation.
+func (p *Plugin) abortConflictOp() tea.Cmd {
+	op := p.conflictOp
+	if op == "" {
+		return nil
+	}
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := AbortOperation(workDir, op)
+		return ConflictOpDoneMsg{Op: op, Aborted: true, Err: err}
+	}
+}
+
+// handleConflictOpDone closes the resolver, or reopens it when continuing
+// stopped on the next commit's conflicts.
+func (p *Plugin) handleConflictOpDone(msg ConflictOpDoneMsg) tea.Cmd {
+	refresh := tea.Batch(p.refresh(), p.loadRecentCommits())
+	if msg.Err != nil && !msg.Aborted && len(GetConflictedFiles(p.repoRoot)) > 0 {
+		return tea.Batch(refresh, p.openConflictResolver(""), func() tea.Msg {
+			return app.ToastMsg{Message: "Next commit has conflicts", Duration: 3 * time.Second}
+		})
+	}
+	if p.viewMode == ViewModeConflicts {
+		p.closeConflictView()
+	}
+	title := strings.ToUpper(msg.Op[:1]) + msg.Op[1:]
+	if msg.Op == "am" {
+		title = "Patch"
+	}
+	if msg.Err != nil {
+		if msg.Aborted {
+			p.showErrorModal(title+" Abort Failed", msg.Err)
+		} else {
+			p.showErrorModal(title+" Failed", msg.Err)
+		}
+		return refresh
+	}
+	toast := title + " completed"
+	if msg.Aborted {
+		toast = title + " aborted"
+	}
+	return tea.Batch(refresh, func() tea.Msg {
+		return app.ToastMsg{Message: toast, Duration: 3 * time.Second}
+	})
+}
+
+// closeConflicts writes pending choices and leaves the resolver.
+func (p *Plugin) closeConflicts() tea.Cmd {
+	write := p.writeConflictChanges()
+	p.closeConflictView()
+	return tea.Sequence(write, p.refresh())
+}
+
+// closeConflictView leaves the resolver, discarding in-memory state.
+func (p *Plugin) closeConflictView() {
+	p.viewMode = ViewModeStatus
+	p.clearConflictState()
+}
+
+func (p *Plugin) clearConflictState() {
+	p.conflictOp = ""
+	p.conflictFiles = nil
+	p.conflictDone = nil
+	p.conflictLoaded = false
+	p.conflictFileIdx = 0
+	p.conflictFile = nil
+	p.conflictHunkIdx = 0
+	p.conflictScroll = 0
+	p.conflictError = ""
+	p.conflictEditing = false
+}
+
+// renderConflicts renders the full-screen conflict resolver: the file list,
+// the current block as ours | base | theirs, and a preview of the result.
+func (p *Plugin) renderConflicts() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionConflicts, 0, 0, p.width, p.height, nil)
+
+	lines := []string{
+		p.renderConflictHeader(contentWidth),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+	if len(p.conflictFiles) > 0 {
+		lines = append(lines, p.renderConflictFileList(contentWidth, len(lines))...)
+		lines = append(lines, "")
+	}
+
+	footer := p.renderConflictFooter(contentWidth)
+	bodyHeight := max(paneHeight-len(lines)-len(footer), 1)
+	body := p.renderConflictBody(contentWidth, bodyHeight)
+	for len(body) < bodyHeight {
+		body = append(body, "")
+	}
+	lines = append(lines, body[:bodyHeight]...)
+	lines = append(lines, footer...)
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
+
+func (p *Plugin) renderConflictHeader(width int) string {
+	header := styles.Title.Render("Resolve Conflicts")
+	if p.conflictOp != "" {
+		header += styles.Muted.Render(" · " + p.conflictOp)
+	}
+	if n := len(p.conflictFiles); n > 0 {
+		header += styles.Muted.Render(fmt.Sprintf(" · %d/%d files resolved", n-p.unresolvedConflictFiles(), n))
+	}
+	if p.conflictOp == "rebase" {
+		// Rebases replay your commits onto upstream, so the sides are swapped
+		header += styles.Muted.Render(" · ours is upstream, theirs is your commit")
+	}
+	return ansi.Truncate(header, width, "…")
+}
+
+// renderConflictFileList renders a window of the conflicted files around the
+// current one. top is the line index of the first row within the panel.
+func (p *Plugin) renderConflictFileList(width, top int) []string {
+	n := len(p.conflictFiles)
+	rows := min(n, conflictMaxFileRows)
+	start := min(max(p.conflictFileIdx-rows/2, 0), n-rows)
+
+	var lines []string
+	for i := start; i < start+rows; i++ {
+		f := p.conflictFiles[i]
+		mark, markStyle := "U", styles.StatusModified
+		if p.conflictDone[f] {
+			mark, markStyle = "✓", styles.StatusStaged
+		}
+		text := ansi.Truncate(fmt.Sprintf(" %s %s", mark, f), width, "…")
+		var line string
+		if i == p.conflictFileIdx {
+			line = styles.ListItemSelected.Render(text + strings.Repeat(" ", max(width-ansi.StringWidth(text), 0)))
+		} else {
+			line = " " + markStyle.Render(mark) + " " + ansi.Truncate(f, max(width-3, 1), "…")
+		}
+		// Y offset 1 for the panel border, X offset 2 for border + padding
+		p.mouseHandler.HitMap.AddRect(regionConflictFile, 2, 1+top+len(lines), width, 1, i)
+		lines = append(lines, line)
+	}
+	if n > rows {
+		lines = append(lines, styles.Muted.Render(fmt.Sprintf("   %d files, [ and ] to switch", n)))
+	}
+	return lines
+}
+
+// renderConflictBody renders the current file's state in height lines.
+func (p *Plugin) renderConflictBody(width, height int) []string {
+	if !p.conflictLoaded {
+		return []string{styles.Muted.Render("Loading conflicts...")}
+	}
+	if p.unresolvedConflictFiles() == 0 {
+		lines := []string{styles.StatusStaged.Render("All conflicts resolved."), ""}
+		if p.conflictOp != "" {
+			lines = append(lines, styles.Muted.Render(fmt.Sprintf("Press c to continue the %s, or a to abort it.", p.conflictOp)))
+		} else {
+			lines = append(lines, styles.Muted.Render("Commit the staged changes to finish."))
+		}
+		return lines
+	}
+
+	cf := p.conflictFile
+	if cf == nil {
+		if p.conflictError != "" {
+			return nil // Shown in the footer
+		}
+		return []string{styles.Muted.Render("Loading file...")}
+	}
+	if len(cf.Hunks) == 0 {
+		return []string{
+			styles.Muted.Render("No conflict markers in this file."),
+			styles.Muted.Render("It may have been deleted on one side, or already fixed by hand."),
+			"",
+			styles.Muted.Render("O keeps our version, T keeps theirs, s marks it resolved as it is, e edits it."),
+		}
+	}
+
+	h := cf.Hunks[p.conflictHunkIdx]
+	choiceStyle := styles.StatusModified
+	if h.Choice != ConflictUnresolved {
+		choiceStyle = styles.StatusStaged
+	}
+	lines := []string{
+		fmt.Sprintf("Conflict %d/%d", p.conflictHunkIdx+1, len(cf.Hunks)) +
+			styles.Muted.Render(fmt.Sprintf(" · line %d · ", h.Line)) + choiceStyle.Render(h.Choice.String()) +
+			styles.Muted.Render(fmt.Sprintf(" · %d left", cf.Remaining())),
+	}
+
+	result := p.renderConflictResult(h, width)
+	paneRows := max(height-len(lines)-1-len(result), 1)
+	lines = append(lines, p.renderConflictPanes(h, width, paneRows)...)
+	lines = append(lines, result...)
+	return lines
+}
+
+// renderConflictPanes renders the ours, base and theirs sides of a block in
+// three columns, starting at the scroll offset.
+func (p *Plugin) renderConflictPanes(h *ConflictHunk, width, rows int) []string {
+	colW := max((width-6)/3, 4)
+	sep := styles.Muted.Render(" │ ")
+
+	total := max(len(h.Ours), len(h.Base), len(h.Theirs))
+	p.conflictScroll = min(p.conflictScroll, max(total-rows, 0))
+
+	oursTitle, theirsTitle := "Ours", "Theirs"
+	if h.OursLabel != "" {
+		oursTitle += ": " + h.OursLabel
+	}
+	if h.TheirsLabel != "" {
+		theirsTitle += ": " + h.TheirsLabel
+	}
+	title := func(text string, chosen bool) string {
+		text = ansi.Truncate(text, colW, "…")
+		style := styles.Muted
+		if chosen {
+			style = styles.StatusStaged
+		}
+		return style.Render(text) + strings.Repeat(" ", colW-ansi.StringWidth(text))
+	}
+	c := h.Choice
+	lines := []string{
+		title(oursTitle, c == ConflictOurs || c == ConflictBoth) + sep +
+			title("Base", false) + sep +
+			title(theirsTitle, c == ConflictTheirs || c == ConflictBoth),
+	}
+
+	cell := func(side []string, row int) string {
+		if row >= len(side) {
+			return strings.Repeat(" ", colW)
+		}
+		text := ansi.Truncate(conflictDisplayLine(side[row]), colW, "…")
+		return text + strings.Repeat(" ", colW-ansi.StringWidth(text))
+	}
+	for r := range rows {
+		row := p.conflictScroll + r
+		if row >= total && r > 0 {
+			break
+		}
+		base := styles.Muted.Render(cell(h.Base, row))
+		if !h.BaseAvailable {
+			base = strings.Repeat(" ", colW)
+			if r == 0 {
+				base = styles.Muted.Render(cell([]string{"(base not available)"}, 0))
+			}
+		}
+		lines = append(lines, cell(h.Ours, row)+sep+base+sep+cell(h.Theirs, row))
+	}
+	return lines
+}
+
+// renderConflictResult renders a preview of the lines the block resolves to.
+func (p *Plugin) renderConflictResult(h *ConflictHunk, width int) []string {
+	lines := []string{styles.Muted.Render("Result")}
+	if h.Choice == ConflictUnresolved {
+		return append(lines, styles.Muted.Render("  unresolved: o ours, t theirs, b both"))
+	}
+	result := h.Result()
+	if len(result) == 0 {
+		return append(lines, styles.Muted.Render("  (empty)"))
+	}
+	for i, line := range result {
+		if i == conflictMaxResultRows {
+			lines = append(lines, styles.Muted.Render(fmt.Sprintf("  … %d more line(s)", len(result)-i)))
+			break
+		}
+		lines = append(lines, styles.DiffAdd.Render(ansi.Truncate("  "+conflictDisplayLine(line), width, "…")))
+	}
+	return lines
+}
+
+// renderConflictFooter renders the error line, if any, and key hints.
+func (p *Plugin) renderConflictFooter(width int) []string {
+	var lines []string
+	if p.conflictError != "" {
+		lines = append(lines, styles.StatusDeleted.Render(ansi.Truncate(p.conflictError, width, "…")))
+	}
+	hints := "o ours  t theirs  b both  x reset  O/T all  n/N block  [/] file  e edit  s mark resolved"
+	if p.conflictOp != "" {
+		hints += "  c continue  a abort"
+	}
+	hints += "  esc close"
+	return append(lines, styles.Muted.Render(ansi.Truncate(hints, width, "…")))
+}
+
+// conflictDisplayLine expands tabs and drops carriage returns for display.
+func conflictDisplayLine(line string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(line, "\r"), "\t", "    ")
+}
@@ -1,7 +1,10 @@
 package gitstatus
 
 import (
+	"maps"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/plugin"
 )
 
 // loadDiff loads the diff for a file.
@@ -81,6 +84,66 @@ func (p *Plugin) loadMoreCommits() tea.Cmd {
 }
 
 
+// loadVisibleSignatures checks the signatures of the commits shown in the
+// sidebar that haven't been checked yet.
+func (p *Plugin) loadVisibleSignatures() tea.Cmd {
+	commits := p.activeCommits()
+	start := min(max(p.commitScrollOff, 0), len(commits))
+	end := min(start+max(p.visibleCommitCount(), 1), len(commits))
+	var hashes []string
+	for _, c := range commits[start:end] {
+		if _, ok := p.signatures[c.Hash]; !ok && !p.signaturesPending[c.Hash] {
+			hashes = append(hashes, c.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	if p.signaturesPending == nil {
+		p.signaturesPending = make(map[string]bool)
+	}
+	for _, hash := range hashes {
+		p.signaturesPending[hash] = true
+	}
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		statuses, err := GetSignatureStatus(workDir, hashes)
+		return SignaturesLoadedMsg{Epoch: epoch, Hashes: hashes, Statuses: statuses, Err: err}
+	}
+}
+
+// handleSignaturesLoaded caches checked signatures and shows them.
+func (p *Plugin) handleSignaturesLoaded(m SignaturesLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) {
+		return nil
+	}
+	for _, hash := range m.Hashes {
+		delete(p.signaturesPending, hash)
+	}
+	if m.Err != nil {
+		return nil
+	}
+	if p.signatures == nil {
+		p.signatures = make(map[string]SignatureStatus)
+	}
+	maps.Copy(p.signatures, m.Statuses)
+	p.applySignatures()
+	return nil
+}
+
+// applySignatures sets the checked signature status on loaded commits.
+func (p *Plugin) applySignatures() {
+	for _, commits := range [][]*Commit{p.recentCommits, p.filteredCommits} {
+		for _, c := range commits {
+			if status, ok := p.signatures[c.Hash]; ok {
+				c.Signature = status
+			}
+		}
+	}
+}
+
 // loadFilteredCommits fetches commits with current filter options.
 func (p *Plugin) loadFilteredCommits() tea.Cmd {
 	epoch := p.ctx.Epoch
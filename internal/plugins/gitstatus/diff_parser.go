@@ -28,6 +28,7 @@ type DiffLine struct {
 	NewLineNo int // 0 means not applicable
 	Content   string
 	WordDiff  []WordSegment
+	NoNewline bool // Followed by "\ No newline at end of file"
 }
 
 // Hunk represents a diff hunk.
@@ -265,7 +266,10 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 				newLineNo++
 
 			case '\\':
-				// "\ No newline at end of file" - skip
+				// "\ No newline at end of file" applies to the line before it
+				if n := len(currentHunk.Lines); n > 0 {
+					currentHunk.Lines[n-1].NoNewline = true
+				}
 
 			default:
 				// Treat as context if unrecognized
@@ -47,6 +47,12 @@ var (
 // highlighter is optional - if nil, no syntax highlighting is applied.
 // wrapEnabled wraps long lines instead of truncating them.
 func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffset int, highlighter *SyntaxHighlighter, wrapEnabled bool) string {
+	return renderLineDiff(diff, width, startLine, maxLines, horizontalOffset, highlighter, wrapEnabled, nil)
+}
+
+// renderLineDiff renders a unified diff, highlighting the gutter of lines
+// covered by sel (nil for no selection).
+func renderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffset int, highlighter *SyntaxHighlighter, wrapEnabled bool, sel *DiffSelection) string {
 	if diff == nil || diff.Binary {
 		if diff != nil && diff.Binary {
 			return styles.Muted.Render(" Binary file differs")
@@ -72,8 +78,12 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 
 	contentWidth := width - (lineNoWidth*2 + 4) // Two line numbers + separators
 	isFirstHunk := true
+	flat := 0 // Flat line index (hunk headers + lines) for selection lookup
 
 	for _, hunk := range diff.Hunks {
+		headerFlat := flat
+		flat++
+		hStyle := selectionHeaderStyle(hunkHeaderStyle, sel, headerFlat)
 		// Skip until we reach the start line
 		if lineNum < startLine {
 			lineNum++
@@ -86,7 +96,7 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 				// Render hunk header
 				header := truncateLine(fmt.Sprintf("@@ -%d,%d +%d,%d @@%s",
 					hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount, hunk.Header), contentWidth)
-				sb.WriteString(hunkHeaderStyle.Render(header))
+				sb.WriteString(hStyle.Render(header))
 				sb.WriteString("\n")
 				rendered++
 				isFirstHunk = false
@@ -100,7 +110,7 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 			// Render hunk header
 			header := truncateLine(fmt.Sprintf("@@ -%d,%d +%d,%d @@%s",
 				hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount, hunk.Header), contentWidth)
-			sb.WriteString(hunkHeaderStyle.Render(header))
+			sb.WriteString(hStyle.Render(header))
 			sb.WriteString("\n")
 			rendered++
 			isFirstHunk = false
@@ -111,6 +121,8 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 		}
 
 		for _, line := range hunk.Lines {
+			lineFlat := flat
+			flat++
 			lineNum++
 			if lineNum <= startLine {
 				continue
@@ -130,9 +142,10 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 				newNo = fmt.Sprintf("%d", line.NewLineNo)
 			}
 
+			gutterStyle := selectionGutterStyle(lineNoStyle, sel, lineFlat)
 			lineNos := fmt.Sprintf("%s %s │ ",
-				lineNoStyle.Render(oldNo),
-				lineNoStyle.Render(newNo))
+				gutterStyle.Render(oldNo),
+				gutterStyle.Render(newNo))
 
 			// Render content with appropriate style
 			var content string
@@ -177,6 +190,12 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 // highlighter is optional - if nil, no syntax highlighting is applied.
 // wrapEnabled wraps long lines instead of truncating them.
 func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOffset int, highlighter *SyntaxHighlighter, wrapEnabled bool) string {
+	return renderSideBySide(diff, width, startLine, maxLines, horizontalOffset, highlighter, wrapEnabled, nil)
+}
+
+// renderSideBySide renders a side-by-side diff, highlighting the gutters of
+// rows that contain lines covered by sel (nil for no selection).
+func renderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOffset int, highlighter *SyntaxHighlighter, wrapEnabled bool, sel *DiffSelection) string {
 	if diff == nil || diff.Binary {
 		if diff != nil && diff.Binary {
 			return styles.Muted.Render(" Binary file differs")
@@ -199,10 +218,13 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 		Align(lipgloss.Right)
 
 	isFirstHunk := true
+	flat := 0 // Flat line index of the current hunk header for selection lookup
 	for _, hunk := range diff.Hunks {
 		if rendered >= maxLines {
 			break
 		}
+		headerFlat := flat
+		flat += len(hunk.Lines) + 1
 
 		// Render hunk header across both panels
 		if lineNum >= startLine {
@@ -213,7 +235,7 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 			}
 			header := fmt.Sprintf("@@ -%d,%d +%d,%d @@",
 				hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount)
-			sb.WriteString(hunkHeaderStyle.Render(padRight(header, width-1)))
+			sb.WriteString(selectionHeaderStyle(hunkHeaderStyle, sel, headerFlat).Render(padRight(header, width-1)))
 			sb.WriteString("\n")
 			rendered++
 			isFirstHunk = false
@@ -232,6 +254,10 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 				continue
 			}
 
+			// Highlight the row's gutters if either side is selected
+			rowFlat := pairSelectionIndex(sel, headerFlat, pair)
+			gutterStyle := selectionGutterStyle(lineNoStyle, sel, rowFlat)
+
 			// Left side (old)
 			leftLineNo := " "
 			leftRendered := ""
@@ -298,9 +324,9 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 					lLine = padToWidth(lLine, contentWidth)
 					rLine = padToWidth(rLine, contentWidth)
 					if vi == 0 {
-						sb.WriteString(fmt.Sprintf("%s │%s", lineNoStyle.Render(leftLineNo), lLine))
+						sb.WriteString(fmt.Sprintf("%s │%s", gutterStyle.Render(leftLineNo), lLine))
 						sb.WriteString(sep)
-						sb.WriteString(fmt.Sprintf("%s │%s", lineNoStyle.Render(rightLineNo), rLine))
+						sb.WriteString(fmt.Sprintf("%s │%s", gutterStyle.Render(rightLineNo), rLine))
 					} else {
 						sb.WriteString(fmt.Sprintf("%s │%s", lineNoPad, lLine))
 						sb.WriteString(sep)
@@ -314,11 +340,11 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 				rightRendered = padToWidth(rightRendered, contentWidth)
 
 				leftPanel := fmt.Sprintf("%s │%s",
-					lineNoStyle.Render(leftLineNo),
+					gutterStyle.Render(leftLineNo),
 					leftRendered)
 
 				rightPanel := fmt.Sprintf("%s │%s",
-					lineNoStyle.Render(rightLineNo),
+					gutterStyle.Render(rightLineNo),
 					rightRendered)
 
 				sb.WriteString(leftPanel)
@@ -334,10 +360,62 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 	return sb.String()
 }
 
+// selectionGutterStyle returns the line number style for a flat diff line,
+// marking the cursor and any selected range.
+func selectionGutterStyle(base lipgloss.Style, sel *DiffSelection, idx int) lipgloss.Style {
+	if sel == nil || idx < 0 {
+		return base
+	}
+	if idx == sel.Cursor {
+		return base.Foreground(styles.TextInverse).Background(styles.Primary)
+	}
+	if sel.HasRange() && sel.Contains(idx) {
+		return base.Foreground(styles.TextSelectionColor).Background(styles.BgTertiary)
+	}
+	return base
+}
+
+// selectionHeaderStyle returns the hunk header style, marking the header when
+// the cursor is on it.
+func selectionHeaderStyle(base lipgloss.Style, sel *DiffSelection, idx int) lipgloss.Style {
+	if sel != nil && idx == sel.Cursor {
+		return base.Foreground(styles.TextInverse).Background(styles.Primary)
+	}
+	return base
+}
+
+// pairSelectionIndex picks which flat index of a side-by-side row to use for
+// gutter highlighting: the cursor if either side has it, otherwise whichever
+// side is selected.
+func pairSelectionIndex(sel *DiffSelection, headerFlat int, pair linePair) int {
+	left, right := -1, -1
+	if pair.left != nil {
+		left = headerFlat + 1 + pair.leftIdx
+	}
+	if pair.right != nil {
+		right = headerFlat + 1 + pair.rightIdx
+	}
+	switch {
+	case sel == nil:
+	case left == sel.Cursor || right == sel.Cursor:
+		return sel.Cursor
+	case left >= 0 && sel.HasRange() && sel.Contains(left):
+		return left
+	case right >= 0 && sel.HasRange() && sel.Contains(right):
+		return right
+	}
+	if left < 0 {
+		return right
+	}
+	return left
+}
+
 // linePair represents a pair of lines for side-by-side view.
 type linePair struct {
-	left  *DiffLine
-	right *DiffLine
+	left     *DiffLine
+	right    *DiffLine
+	leftIdx  int // Index of left within the hunk's lines
+	rightIdx int // Index of right within the hunk's lines
 }
 
 // groupLinesForSideBySide groups diff lines into pairs for side-by-side display.
@@ -351,7 +429,7 @@ func groupLinesForSideBySide(lines []DiffLine) []linePair {
 		switch line.Type {
 		case LineContext:
 			// Context lines appear on both sides
-			pairs = append(pairs, linePair{left: line, right: line})
+			pairs = append(pairs, linePair{left: line, right: line, leftIdx: i, rightIdx: i})
 			i++
 
 		case LineRemove:
@@ -377,19 +455,21 @@ func groupLinesForSideBySide(lines []DiffLine) []linePair {
 			}
 
 			for j := 0; j < maxPairs; j++ {
-				var left, right *DiffLine
+				pair := linePair{}
 				if j < removeCount {
-					left = &lines[removeStart+j]
+					pair.left = &lines[removeStart+j]
+					pair.leftIdx = removeStart + j
 				}
 				if j < addCount {
-					right = &lines[addStart+j]
+					pair.right = &lines[addStart+j]
+					pair.rightIdx = addStart + j
 				}
-				pairs = append(pairs, linePair{left: left, right: right})
+				pairs = append(pairs, pair)
 			}
 
 		case LineAdd:
 			// Orphan add (shouldn't happen if grouping is correct)
-			pairs = append(pairs, linePair{left: nil, right: line})
+			pairs = append(pairs, linePair{left: nil, right: line, rightIdx: i})
 			i++
 		}
 	}
@@ -24,6 +24,7 @@ func (p *Plugin) showErrorModal(title string, err error) {
 	}
 	p.errorTitle = title
 	p.errorDetail = detail
+	p.errorSequencerOp = ""
 	p.clearErrorModal()
 	p.viewMode = ViewModeError
 }
@@ -50,6 +51,13 @@ func (p *Plugin) ensureErrorModal() {
 	if p.errorOfferPull {
 		btns = append(btns, modal.Btn(" Pull ", "pull"))
 	}
+	if p.errorSequencerOp != "" {
+		btns = append(btns,
+			modal.Btn(" Resolve ", "resolve"),
+			modal.Btn(" Continue ", "continue"),
+			modal.Btn(" Abort ", "abort", modal.BtnDanger()),
+		)
+	}
 	btns = append(btns, modal.Btn(" Dismiss ", "dismiss"))
 
 	p.errorModal = modal.New(p.errorTitle,
@@ -86,6 +94,18 @@ func (p *Plugin) updateErrorModal(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		return p.errorModalToPullMenu()
 	}
 
+	// Continue/abort shortcuts for a stopped cherry-pick or revert
+	if p.errorSequencerOp != "" {
+		switch msg.String() {
+		case "r":
+			return p.errorModalResolve()
+		case "c":
+			return p.errorModalContinue()
+		case "a":
+			return p.errorModalAbort()
+		}
+	}
+
 	// Intercept yank before delegating to modal key handler
 	if msg.String() == "y" {
 		return p, p.yankErrorToClipboard()
@@ -95,6 +115,12 @@ func (p *Plugin) updateErrorModal(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	switch action {
 	case "pull":
 		return p.errorModalToPullMenu()
+	case "resolve":
+		return p.errorModalResolve()
+	case "continue":
+		return p.errorModalContinue()
+	case "abort":
+		return p.errorModalAbort()
 	case "dismiss", "cancel":
 		return p.dismissErrorModal()
 	}
@@ -111,6 +137,12 @@ func (p *Plugin) handleErrorModalMouse(m tea.MouseMsg) (plugin.Plugin, tea.Cmd)
 	switch action {
 	case "pull":
 		return p.errorModalToPullMenu()
+	case "resolve":
+		return p.errorModalResolve()
+	case "continue":
+		return p.errorModalContinue()
+	case "abort":
+		return p.errorModalAbort()
 	case "dismiss", "cancel":
 		return p.dismissErrorModal()
 	}
@@ -126,12 +158,36 @@ func (p *Plugin) dismissErrorModal() (plugin.Plugin, tea.Cmd) {
 	p.errorModalWidth = 0
 	p.errorModalHeight = 0
 	p.errorOfferPull = false
+	p.errorSequencerOp = ""
 	p.pushError = ""
 	p.fetchError = ""
 	p.pullError = ""
 	return p, nil
 }
 
+// errorModalContinue dismisses the error modal and continues the stopped
+// cherry-pick or revert.
+func (p *Plugin) errorModalContinue() (plugin.Plugin, tea.Cmd) {
+	op := p.errorSequencerOp
+	p.dismissErrorModal()
+	return p, p.doContinueSequencer(op)
+}
+
+// errorModalResolve dismisses the error modal and opens the conflict
+// resolver for the stopped cherry-pick or revert.
+func (p *Plugin) errorModalResolve() (plugin.Plugin, tea.Cmd) {
+	p.dismissErrorModal()
+	return p, p.openConflictResolver("")
+}
+
+// errorModalAbort dismisses the error modal and aborts the stopped
+// cherry-pick or revert.
+func (p *Plugin) errorModalAbort() (plugin.Plugin, tea.Cmd) {
+	op := p.errorSequencerOp
+	p.dismissErrorModal()
+	return p, p.doAbortSequencer(op)
+}
+
 // errorModalToPullMenu dismisses the error modal and opens the pull menu.
 func (p *Plugin) errorModalToPullMenu() (plugin.Plugin, tea.Cmd) {
 	// Clear error state
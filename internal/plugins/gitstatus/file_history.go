@@ -0,0 +1,167 @@
+package gitstatus
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// fileHistoryLimit caps how many commits a file history or pickaxe search loads.
+const fileHistoryLimit = 500
+
+// FileHistoryEntry is a commit in a file history or pickaxe search, with the
+// files it changed that the query is about.
+type FileHistoryEntry struct {
+	Commit *Commit
+	Files  []CommitFile
+}
+
+// PickaxeOpts configures a pickaxe search.
+type PickaxeOpts struct {
+	Query string
+	Regex bool   // Query is a POSIX extended regex (git's dialect, not Go's) rather than a literal string
+	Path  string // Limit the search to one file, "" for the whole repository
+	Limit int
+}
+
+// args returns the git options selecting commits that add or remove a match.
+func (o PickaxeOpts) args() []string {
+	args := []string{"-S" + o.Query}
+	if o.Regex {
+		args = append(args, "--pickaxe-regex")
+	}
+	return args
+}
+
+// GetFileHistory returns the commits that changed path, newest first,
+// following the file across renames.
+func GetFileHistory(workDir, path string, limit int) ([]*FileHistoryEntry, error) {
+	args := []string{"log", "--follow", "-M", "--name-status", "--format=%x1e" + commitLogFormat}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	args = append(args, "--", path)
+	return runHistoryLog(workDir, args)
+}
+
+// SearchPickaxe returns the commits whose diffs add or remove a match for the
+// query, newest first. Each entry lists only the files where the match changed.
+func SearchPickaxe(workDir string, opts PickaxeOpts) ([]*FileHistoryEntry, error) {
+	if opts.Query == "" {
+		return nil, errors.New("search text is required")
+	}
+	args := []string{"log", "-M", "--name-status", "--format=%x1e" + commitLogFormat}
+	args = append(args, opts.args()...)
+	if opts.Limit > 0 {
+		args = append(args, "-n", strconv.Itoa(opts.Limit))
+	}
+	if opts.Path != "" {
+		args = append(args, "--", opts.Path)
+	}
+	return runHistoryLog(workDir, args)
+}
+
+// runHistoryLog runs git log with a \x1e-prefixed commitLogFormat and
+// --name-status, and parses the output. Errors carry git's message, such as
+// an invalid pickaxe regex.
+func runHistoryLog(workDir string, args []string) ([]*FileHistoryEntry, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return parseHistoryEntries(string(output)), nil
+}
+
+// parseHistoryEntries parses git log records of a commit line followed by
+// --name-status lines.
+func parseHistoryEntries(output string) []*FileHistoryEntry {
+	var entries []*FileHistoryEntry
+	for _, record := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		commits := parseCommitLog([]byte(lines[0]))
+		if len(commits) == 0 {
+			continue
+		}
+		entry := &FileHistoryEntry{Commit: commits[0]}
+		for _, line := range lines[1:] {
+			if file, ok := parseNameStatus(line); ok {
+				entry.Files = append(entry.Files, file)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseNameStatus parses a --name-status line such as "M\tpath" or
+// "R087\told\tnew".
+func parseNameStatus(line string) (CommitFile, bool) {
+	parts := strings.Split(line, "\t")
+	if len(parts) < 2 || parts[0] == "" {
+		return CommitFile{}, false
+	}
+	file := CommitFile{Status: FileStatus(parts[0][:1]), Path: parts[len(parts)-1]}
+	if len(parts) >= 3 {
+		file.OldPath = parts[1]
+	}
+	return file, true
+}
+
+// GetHistoryDiff returns the diff of the entry's files in its commit, with
+// renames shown as such. With a pickaxe, the diff is limited to the files
+// where the match changed.
+func GetHistoryDiff(workDir string, entry *FileHistoryEntry, pickaxe *PickaxeOpts) (string, error) {
+	c := entry.Commit
+	var args []string
+	if c.IsMerge && len(c.ParentHashes) > 0 {
+		// Diff against the first parent; git show's combined diff is empty for clean merges
+		args = []string{"diff", "-M", c.ParentHashes[0], c.Hash}
+	} else {
+		args = []string{"show", "-M", "--format=", c.Hash}
+	}
+	if pickaxe != nil {
+		args = append(args, pickaxe.args()...)
+	}
+	if len(entry.Files) > 0 {
+		args = append(args, "--")
+		for _, f := range entry.Files {
+			if f.OldPath != "" {
+				args = append(args, f.OldPath)
+			}
+			args = append(args, f.Path)
+		}
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CountPickaxeMatches counts the occurrences of a string query on added and
+// removed lines of a diff. Regex queries aren't counted (ok is false): git
+// matches them as POSIX regexes, which Go can't reproduce.
+func CountPickaxeMatches(diff string, opts PickaxeOpts) (added, removed int, ok bool) {
+	if opts.Regex {
+		return 0, 0, false
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added += strings.Count(line[1:], opts.Query)
+		case strings.HasPrefix(line, "-"):
+			removed += strings.Count(line[1:], opts.Query)
+		}
+	}
+	return added, removed, true
+}
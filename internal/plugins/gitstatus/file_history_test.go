@@ -0,0 +1,128 @@
+package gitstatus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	tests := []struct {
+		line string
+		want CommitFile
+		ok   bool
+	}{
+		{"M\tmain.go", CommitFile{Status: StatusModified, Path: "main.go"}, true},
+		{"A\tdir/new.go", CommitFile{Status: StatusAdded, Path: "dir/new.go"}, true},
+		{"R087\told.go\tnew.go", CommitFile{Status: StatusRenamed, Path: "new.go", OldPath: "old.go"}, true},
+		{"", CommitFile{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseNameStatus(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseNameStatus(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetFileHistoryFollowsRenames(t *testing.T) {
+	dir := initPartialRepo(t, "func helper() {}\n")
+	commitFile(t, dir, "other.txt", "unrelated\n", "touch another file")
+	gitRun(t, dir, "mv", "file.txt", "renamed.txt")
+	gitRun(t, dir, "commit", "-q", "-m", "rename file")
+	commitFile(t, dir, "renamed.txt", "func helper() { return }\n", "change helper")
+
+	entries, err := GetFileHistory(dir, "renamed.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, e := range entries {
+		subjects = append(subjects, e.Commit.Subject)
+	}
+	if len(entries) != 3 || subjects[0] != "change helper" || subjects[1] != "rename file" {
+		t.Fatalf("subjects = %q", subjects)
+	}
+	rename := entries[1].Files
+	if len(rename) != 1 || rename[0].Status != StatusRenamed || rename[0].OldPath != "file.txt" {
+		t.Errorf("rename entry files = %+v", rename)
+	}
+	if files := entries[2].Files; len(files) != 1 || files[0].Path != "file.txt" {
+		t.Errorf("oldest entry files = %+v", files)
+	}
+
+	diff, err := GetHistoryDiff(dir, entries[0], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+func helper() { return }") {
+		t.Errorf("diff = %q", diff)
+	}
+}
+
+func TestSearchPickaxe(t *testing.T) {
+	dir := initPartialRepo(t, "start\n")
+	commitFile(t, dir, "a.go", "func oldName() {}\n", "add a")
+	commitFile(t, dir, "b.go", "// calls oldName\n", "add b")
+	commitFile(t, dir, "a.go", "func newName() {}\n", "rename func")
+	commitFile(t, dir, "c.go", "unrelated\n", "add c")
+
+	subjects := func(entries []*FileHistoryEntry) string {
+		var s []string
+		for _, e := range entries {
+			s = append(s, e.Commit.Subject)
+		}
+		return strings.Join(s, ", ")
+	}
+
+	entries, err := SearchPickaxe(dir, PickaxeOpts{Query: "oldName"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := subjects(entries); got != "rename func, add b, add a" {
+		t.Errorf("string search = %q", got)
+	}
+	if files := entries[0].Files; len(files) != 1 || files[0].Path != "a.go" {
+		t.Errorf("rename func files = %+v", files)
+	}
+
+	entries, err = SearchPickaxe(dir, PickaxeOpts{Query: "oldName", Path: "a.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := subjects(entries); got != "rename func, add a" {
+		t.Errorf("scoped search = %q", got)
+	}
+
+	opts := PickaxeOpts{Query: `func [a-z]+Name`, Regex: true}
+	entries, err = SearchPickaxe(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := subjects(entries); got != "add a" {
+		// The rename keeps one match, so it doesn't change the count
+		t.Errorf("regex search = %q", got)
+	}
+
+	diff, err := GetHistoryDiff(dir, entries[0], &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+func oldName") {
+		t.Errorf("diff = %q", diff)
+	}
+
+	// git validates the regex, in its own (POSIX) dialect
+	if _, err := SearchPickaxe(dir, PickaxeOpts{Query: "(", Regex: true}); err == nil || !strings.Contains(err.Error(), "invalid regex") {
+		t.Errorf("expected git's invalid regex error, got %v", err)
+	}
+}
+
+func TestCountPickaxeMatches(t *testing.T) {
+	diff := "--- a/f.go\n+++ b/f.go\n@@ -1 +1 @@\n-foo foo\n+bar foo\n context foo\n"
+	if added, removed, ok := CountPickaxeMatches(diff, PickaxeOpts{Query: "foo"}); !ok || added != 1 || removed != 2 {
+		t.Errorf("string = +%d -%d, want +1 -2", added, removed)
+	}
+	if _, _, ok := CountPickaxeMatches(diff, PickaxeOpts{Query: "ba?r", Regex: true}); ok {
+		t.Error("regex queries counted")
+	}
+}
@@ -0,0 +1,540 @@
+package gitstatus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	pickaxeInputID  = "pickaxe-input"
+	pickaxeRegexID  = "pickaxe-regex"
+	pickaxeSearchID = "pickaxe-search"
+)
+
+// FileHistoryLoadedMsg carries a file history or pickaxe search result.
+type FileHistoryLoadedMsg struct {
+	Epoch   uint64
+	Entries []*FileHistoryEntry
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m FileHistoryLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// HistoryDiffLoadedMsg carries the diff of one file history entry.
+type HistoryDiffLoadedMsg struct {
+	Epoch uint64
+	Hash  string
+	Diff  string
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m HistoryDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// handleOpenFileHistory opens a file history requested by another plugin.
+// The path is relative to the project directory, which may be below the
+// repository root.
+func (p *Plugin) handleOpenFileHistory(m app.OpenFileHistoryMsg) tea.Cmd {
+	path := m.Path
+	if p.ctx != nil && p.ctx.WorkDir != "" {
+		if rel, err := filepath.Rel(p.repoRoot, filepath.Join(p.ctx.WorkDir, path)); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+	}
+	p.viewMode = ViewModeStatus
+	return p.openFileHistory(path)
+}
+
+// openFileHistory opens the history of path, following renames.
+func (p *Plugin) openFileHistory(path string) tea.Cmd {
+	p.resetFileHistory()
+	p.fileHistoryPath = path
+	p.fileHistoryPickaxe = nil
+	if p.viewMode != ViewModeFileHistory {
+		p.fileHistoryReturnMode = p.viewMode
+	}
+	p.viewMode = ViewModeFileHistory
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		entries, err := GetFileHistory(workDir, path, fileHistoryLimit)
+		return FileHistoryLoadedMsg{Epoch: epoch, Entries: entries, Err: err}
+	}
+}
+
+// runPickaxe lists the commits that added or removed a match.
+func (p *Plugin) runPickaxe(opts PickaxeOpts) tea.Cmd {
+	p.resetFileHistory()
+	p.fileHistoryPath = opts.Path
+	p.fileHistoryPickaxe = &opts
+	if p.viewMode != ViewModeFileHistory {
+		p.fileHistoryReturnMode = p.viewMode
+	}
+	p.viewMode = ViewModeFileHistory
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		entries, err := SearchPickaxe(workDir, opts)
+		return FileHistoryLoadedMsg{Epoch: epoch, Entries: entries, Err: err}
+	}
+}
+
+// resetFileHistory clears loaded history state.
+func (p *Plugin) resetFileHistory() {
+	p.fileHistoryEntries = nil
+	p.fileHistoryLoaded = false
+	p.fileHistoryCursor = 0
+	p.fileHistoryListScroll = 0
+	p.clearHistoryDiff()
+}
+
+func (p *Plugin) clearHistoryDiff() {
+	p.fileHistoryDiff = nil
+	p.fileHistoryDiffRaw = ""
+	p.fileHistoryDiffHash = ""
+	p.fileHistoryDiffScroll = 0
+}
+
+// handleFileHistoryLoaded shows loaded history and the newest commit's diff.
+func (p *Plugin) handleFileHistoryLoaded(m FileHistoryLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeFileHistory {
+		return nil
+	}
+	if m.Err != nil {
+		p.closeFileHistory()
+		p.showErrorModal("History Failed", m.Err)
+		return nil
+	}
+	p.fileHistoryEntries = m.Entries
+	p.fileHistoryLoaded = true
+	return p.loadHistoryDiff()
+}
+
+// selectedHistoryEntry returns the entry under the cursor, if any.
+func (p *Plugin) selectedHistoryEntry() *FileHistoryEntry {
+	if p.fileHistoryCursor < 0 || p.fileHistoryCursor >= len(p.fileHistoryEntries) {
+		return nil
+	}
+	return p.fileHistoryEntries[p.fileHistoryCursor]
+}
+
+// loadHistoryDiff loads the diff of the selected entry.
+func (p *Plugin) loadHistoryDiff() tea.Cmd {
+	entry := p.selectedHistoryEntry()
+	if entry == nil {
+		return nil
+	}
+	p.clearHistoryDiff()
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	pickaxe := p.fileHistoryPickaxe
+	return func() tea.Msg {
+		diff, err := GetHistoryDiff(workDir, entry, pickaxe)
+		return HistoryDiffLoadedMsg{Epoch: epoch, Hash: entry.Commit.Hash, Diff: diff, Err: err}
+	}
+}
+
+// handleHistoryDiffLoaded shows a loaded diff if its commit is still selected.
+func (p *Plugin) handleHistoryDiffLoaded(m HistoryDiffLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeFileHistory {
+		return nil
+	}
+	entry := p.selectedHistoryEntry()
+	if entry == nil || entry.Commit.Hash != m.Hash {
+		return nil
+	}
+	p.fileHistoryDiffHash = m.Hash
+	if m.Err != nil {
+		p.fileHistoryDiffRaw = "Error: " + m.Err.Error()
+		return nil
+	}
+	p.fileHistoryDiffRaw = m.Diff
+	p.fileHistoryDiff = ParseMultiFileDiff(m.Diff)
+	return nil
+}
+
+// moveHistoryCursor selects another commit and loads its diff.
+func (p *Plugin) moveHistoryCursor(idx int) tea.Cmd {
+	idx = max(min(idx, len(p.fileHistoryEntries)-1), 0)
+	if idx == p.fileHistoryCursor {
+		return nil
+	}
+	p.fileHistoryCursor = idx
+	return p.loadHistoryDiff()
+}
+
+// closeFileHistory returns to the view the history was opened from.
+func (p *Plugin) closeFileHistory() {
+	p.viewMode = p.fileHistoryReturnMode
+	if p.viewMode == ViewModeFileHistory || p.viewMode == ViewModePickaxe {
+		p.viewMode = ViewModeStatus
+	}
+	p.resetFileHistory()
+	p.fileHistoryPath = ""
+	p.fileHistoryPickaxe = nil
+}
+
+// updateFileHistory handles key events in the file history view.
+func (p *Plugin) updateFileHistory(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	page := max((p.height-4)/2, 1)
+	switch m.String() {
+	case "esc", "q":
+		p.closeFileHistory()
+	case "j", "down":
+		return p, p.moveHistoryCursor(p.fileHistoryCursor + 1)
+	case "k", "up":
+		return p, p.moveHistoryCursor(p.fileHistoryCursor - 1)
+	case "g":
+		return p, p.moveHistoryCursor(0)
+	case "G":
+		return p, p.moveHistoryCursor(len(p.fileHistoryEntries) - 1)
+	case "ctrl+d", "J":
+		p.fileHistoryDiffScroll += page
+	case "ctrl+u", "K":
+		p.fileHistoryDiffScroll = max(p.fileHistoryDiffScroll-page, 0)
+	case "v":
+		if p.diffViewMode == DiffViewUnified {
+			p.diffViewMode = DiffViewSideBySide
+		} else {
+			p.diffViewMode = DiffViewUnified
+		}
+	case "y":
+		if entry := p.selectedHistoryEntry(); entry != nil {
+			if err := clipboard.WriteAll(entry.Commit.Hash); err != nil {
+				return p, msg.ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+			}
+			return p, msg.ShowToast("Yanked "+entry.Commit.ShortHash, 2*time.Second)
+		}
+	case "s":
+		// Pickaxe search, scoped to the file in file history mode
+		query, regex := "", false
+		if p.fileHistoryPickaxe != nil {
+			query, regex = p.fileHistoryPickaxe.Query, p.fileHistoryPickaxe.Regex
+		}
+		return p, p.openPickaxe(query, regex, p.fileHistoryPath)
+	case "S":
+		// Widen a scoped pickaxe search to the whole repository
+		if p.fileHistoryPickaxe != nil && p.fileHistoryPickaxe.Path != "" {
+			opts := *p.fileHistoryPickaxe
+			opts.Path = ""
+			return p, p.runPickaxe(opts)
+		}
+	}
+	return p, nil
+}
+
+// openPickaxe opens the pickaxe prompt. path scopes the search to a file.
+func (p *Plugin) openPickaxe(query string, regex bool, path string) tea.Cmd {
+	p.pickaxeInput = textinput.New()
+	p.pickaxeInput.Placeholder = "string added or removed"
+	p.pickaxeInput.Prompt = ""
+	p.pickaxeInput.CharLimit = 200
+	p.pickaxeInput.Width = 40
+	p.pickaxeInput.SetValue(query)
+	p.pickaxeInput.CursorEnd()
+	p.pickaxeInput.Focus()
+	p.pickaxeRegex = regex
+	p.pickaxePath = path
+	p.pickaxeError = ""
+	p.pickaxeReturnMode = p.viewMode
+	p.clearPickaxeModal()
+	p.viewMode = ViewModePickaxe
+	return nil
+}
+
+// updatePickaxe handles key events in the pickaxe prompt.
+func (p *Plugin) updatePickaxe(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensurePickaxeModal()
+	if p.pickaxeModal == nil {
+		return p, nil
+	}
+	if m.String() == "alt+r" {
+		p.pickaxeRegex = !p.pickaxeRegex
+		return p, nil
+	}
+
+	action, cmd := p.pickaxeModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handlePickaxeAction(action))
+}
+
+// handlePickaxeAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handlePickaxeAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closePickaxe()
+	case pickaxeRegexID:
+		// Clicks on the checkbox; keyboard toggles are handled by the section
+		p.pickaxeRegex = !p.pickaxeRegex
+	case pickaxeSearchID, pickaxeInputID:
+		query := p.pickaxeInput.Value()
+		if strings.TrimSpace(query) == "" {
+			p.pickaxeError = "Enter a string to search for"
+			return nil
+		}
+		opts := PickaxeOpts{Query: query, Regex: p.pickaxeRegex, Path: p.pickaxePath, Limit: fileHistoryLimit}
+		p.closePickaxe()
+		return p.runPickaxe(opts)
+	}
+	return nil
+}
+
+func (p *Plugin) closePickaxe() {
+	p.viewMode = p.pickaxeReturnMode
+	p.pickaxeError = ""
+	p.clearPickaxeModal()
+}
+
+func (p *Plugin) clearPickaxeModal() {
+	p.pickaxeModal = nil
+	p.pickaxeModalWidth = 0
+}
+
+// ensurePickaxeModal builds/rebuilds the pickaxe prompt.
+func (p *Plugin) ensurePickaxeModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.pickaxeModal != nil && p.pickaxeModalWidth == modalW {
+		return
+	}
+	p.pickaxeModalWidth = modalW
+
+	p.pickaxeModal = modal.New("Find commits that add or remove",
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(pickaxeSearchID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.InputWithLabel(pickaxeInputID, "Search:", &p.pickaxeInput, modal.WithSubmitAction(pickaxeSearchID))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Checkbox(pickaxeRegexID, "Regex, POSIX extended (alt+r)", &p.pickaxeRegex)).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			scope := "Searching all files"
+			if p.pickaxePath != "" {
+				scope = "Searching " + p.pickaxePath
+			}
+			content := styles.Muted.Render(ansi.Truncate(scope, contentWidth, "…"))
+			if p.pickaxeError != "" {
+				content += "\n\n" + styles.StatusDeleted.Render(p.pickaxeError)
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Search ", pickaxeSearchID),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// renderPickaxe renders the pickaxe prompt.
+func (p *Plugin) renderPickaxe() string {
+	background := p.renderThreePaneView()
+	if p.pickaxeReturnMode == ViewModeFileHistory {
+		background = p.renderFileHistory()
+	}
+
+	p.ensurePickaxeModal()
+	if p.pickaxeModal == nil {
+		return background
+	}
+
+	modalContent := p.pickaxeModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// fileHistoryListWidth returns the width of the commit column.
+func (p *Plugin) fileHistoryListWidth(contentWidth int) int {
+	return min(max(contentWidth/3, 30), 60, contentWidth/2)
+}
+
+// renderFileHistory renders the full-screen file history or pickaxe results:
+// commits on the left, the selected commit's diff on the right.
+func (p *Plugin) renderFileHistory() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+	listWidth := p.fileHistoryListWidth(contentWidth)
+	diffWidth := max(contentWidth-listWidth-3, 10)
+	bodyHeight := max(paneHeight-3, 1) // header, separator, footer
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionFileHistoryDiff, 2+listWidth+3, 3, diffWidth, bodyHeight, nil)
+	p.mouseHandler.HitMap.AddRect(regionFileHistoryList, 2, 3, listWidth, bodyHeight, nil)
+
+	var header string
+	if pk := p.fileHistoryPickaxe; pk != nil {
+		kind := "string"
+		if pk.Regex {
+			kind = "regex"
+		}
+		header = styles.Title.Render("Pickaxe ") + styles.Code.Render(pk.Query) + styles.Muted.Render(" "+kind)
+		if pk.Path != "" {
+			header += styles.Muted.Render(" in " + pk.Path)
+		}
+	} else {
+		header = styles.Title.Render("History ") + styles.Body.Render(p.fileHistoryPath)
+	}
+	if p.fileHistoryLoaded {
+		count := fmt.Sprintf(" · %d commit(s)", len(p.fileHistoryEntries))
+		if len(p.fileHistoryEntries) >= fileHistoryLimit {
+			count = fmt.Sprintf(" · latest %d commits", fileHistoryLimit)
+		}
+		header += styles.Muted.Render(count)
+	}
+
+	lines := []string{
+		ansi.Truncate(header, contentWidth, "…"),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+
+	listLines := p.renderFileHistoryList(listWidth, bodyHeight)
+	diffLines := p.renderFileHistoryDiff(diffWidth, bodyHeight)
+	sep := styles.Muted.Render(" │ ")
+	for i := range bodyHeight {
+		left := ""
+		if i < len(listLines) {
+			left = listLines[i]
+		}
+		right := ""
+		if i < len(diffLines) {
+			right = diffLines[i]
+		}
+		lines = append(lines, padToWidth(left, listWidth)+sep+right)
+	}
+
+	footer := "j/k commit  ctrl+d/u scroll diff  v view  s pickaxe  y copy hash  esc close"
+	if p.fileHistoryPickaxe != nil && p.fileHistoryPickaxe.Path != "" {
+		footer = "j/k commit  ctrl+d/u scroll diff  s search again  S search all files  esc close"
+	}
+	lines = append(lines, styles.Muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
+
+// renderFileHistoryList renders the visible rows of the commit column.
+func (p *Plugin) renderFileHistoryList(width, height int) []string {
+	if !p.fileHistoryLoaded {
+		return []string{styles.Muted.Render("Loading history...")}
+	}
+	if len(p.fileHistoryEntries) == 0 {
+		if p.fileHistoryPickaxe != nil {
+			return []string{styles.Muted.Render("No commits add or remove a match")}
+		}
+		return []string{styles.Muted.Render("No commits touch this file")}
+	}
+
+	// Keep the cursor visible
+	if p.fileHistoryCursor < p.fileHistoryListScroll {
+		p.fileHistoryListScroll = p.fileHistoryCursor
+	}
+	if p.fileHistoryCursor >= p.fileHistoryListScroll+height {
+		p.fileHistoryListScroll = p.fileHistoryCursor - height + 1
+	}
+	p.fileHistoryListScroll = min(p.fileHistoryListScroll, max(len(p.fileHistoryEntries)-height, 0))
+
+	var lines []string
+	end := min(p.fileHistoryListScroll+height, len(p.fileHistoryEntries))
+	for i := p.fileHistoryListScroll; i < end; i++ {
+		lines = append(lines, p.renderFileHistoryRow(p.fileHistoryEntries[i], width, i == p.fileHistoryCursor))
+	}
+	return lines
+}
+
+// renderFileHistoryRow renders one commit: hash, subject and age. Renames
+// and pickaxe files are noted after the subject.
+func (p *Plugin) renderFileHistoryRow(entry *FileHistoryEntry, width int, selected bool) string {
+	c := entry.Commit
+	age := RelativeTime(c.Date)
+	note := ""
+	if len(entry.Files) > 0 {
+		f := entry.Files[0]
+		switch {
+		case p.fileHistoryPickaxe != nil && p.fileHistoryPickaxe.Path == "":
+			note = f.Path
+			if len(entry.Files) > 1 {
+				note += fmt.Sprintf(" +%d", len(entry.Files)-1)
+			}
+		case f.Status == StatusRenamed && f.OldPath != "":
+			note = "renamed from " + filepath.Base(f.OldPath)
+		case f.Path != p.fileHistoryPath && f.Path != "":
+			note = f.Path
+		}
+	}
+
+	prefix := c.ShortHash + " "
+	suffix := " " + age
+	textW := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(suffix), 5)
+	subject := c.Subject
+	if note != "" {
+		subject += " · " + note
+	}
+	subject = ansi.Truncate(subject, textW, "…")
+	pad := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(subject)-ansi.StringWidth(suffix), 0)
+
+	if selected {
+		return styles.ListItemSelected.Render(prefix + subject + strings.Repeat(" ", pad) + suffix)
+	}
+	return styles.Code.Render(c.ShortHash) + " " + subject + strings.Repeat(" ", pad) + styles.Muted.Render(suffix)
+}
+
+// renderFileHistoryDiff renders the selected commit's header and diff.
+func (p *Plugin) renderFileHistoryDiff(width, height int) []string {
+	entry := p.selectedHistoryEntry()
+	if entry == nil {
+		return nil
+	}
+	c := entry.Commit
+	header := styles.Code.Render(c.ShortHash) + " " + styles.Body.Render(c.Subject)
+	meta := styles.Muted.Render(c.Author + " · " + RelativeTime(c.Date))
+	if pk := p.fileHistoryPickaxe; pk != nil && p.fileHistoryDiffHash == c.Hash {
+		if added, removed, ok := CountPickaxeMatches(p.fileHistoryDiffRaw, *pk); ok {
+			meta += styles.Muted.Render(" · ") + styles.DiffAdd.Render(fmt.Sprintf("+%d", added)) +
+				styles.Muted.Render(" / ") + styles.DiffRemove.Render(fmt.Sprintf("-%d", removed)) +
+				styles.Muted.Render(" matches")
+		}
+	}
+	lines := []string{ansi.Truncate(header, width, "…"), ansi.Truncate(meta, width, "…"), ""}
+	bodyHeight := max(height-len(lines), 1)
+
+	switch {
+	case p.fileHistoryDiffHash != c.Hash:
+		lines = append(lines, styles.Muted.Render("Loading diff..."))
+	case p.fileHistoryDiff == nil || len(p.fileHistoryDiff.Files) == 0:
+		text := "No changes"
+		if strings.HasPrefix(p.fileHistoryDiffRaw, "Error: ") {
+			text = p.fileHistoryDiffRaw
+		}
+		lines = append(lines, styles.Muted.Render(ansi.Truncate(text, width, "…")))
+	default:
+		lines = append(lines, p.renderCommitDiffLines(p.fileHistoryDiff, &p.fileHistoryDiffScroll, width, bodyHeight)...)
+	}
+	return lines
+}
+
+// renderCommitDiffLines renders the visible lines of a parsed commit diff,
+// clamping scroll to its rendered length.
+func (p *Plugin) renderCommitDiffLines(mfd *MultiFileDiff, scroll *int, width, height int) []string {
+	total := mfd.Files[len(mfd.Files)-1].EndLine
+	if total > 0 {
+		*scroll = min(*scroll, max(total-height, 0))
+	}
+	rendered := RenderMultiFileDiff(mfd, p.diffViewMode, width, *scroll, height, 0, false)
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(rendered, "\n"), "\n") {
+		lines = append(lines, ansi.Truncate(line, width, ""))
+	}
+	return lines
+}
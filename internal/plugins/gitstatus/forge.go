@@ -0,0 +1,60 @@
+package gitstatus
+
+import (
+	"os/exec"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/forge"
+	"github.com/marcus/sidecar/internal/msg"
+)
+
+// openInBrowser opens the URL in the default browser.
+func openInBrowser(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("cmd", "/c", "start", url)
+		case "linux":
+			cmd = exec.Command("xdg-open", url)
+		default:
+			return app.ToastMsg{Message: "Unsupported platform", Duration: 3 * time.Second, IsError: true}
+		}
+		if err := cmd.Start(); err != nil {
+			return app.ToastMsg{Message: "Failed to open browser: " + err.Error(), Duration: 3 * time.Second, IsError: true}
+		}
+		return nil
+	}
+}
+
+// openCommitOnForge opens the current commit on the forge hosting origin.
+func (p *Plugin) openCommitOnForge() tea.Cmd {
+	commit := p.getCurrentCommit()
+	if commit == nil {
+		return nil
+	}
+
+	remoteURL := forge.RemoteURL(p.repoRoot)
+	if remoteURL == "" {
+		return msg.ShowToast("No remote configured", 2*time.Second)
+	}
+
+	var hosts map[string]string
+	if p.ctx != nil && p.ctx.Config != nil {
+		hosts = p.ctx.Config.Forges.Hosts
+	}
+	remote := forge.ParseRemote(remoteURL, hosts)
+	if remote == nil {
+		return msg.ShowToast("Origin is not a known GitHub, GitLab or Gitea host", 2*time.Second)
+	}
+
+	return tea.Batch(
+		openInBrowser(remote.CommitURL(commit.Hash)),
+		msg.ShowToast("Opening in "+remote.Name()+"...", 2*time.Second),
+	)
+}
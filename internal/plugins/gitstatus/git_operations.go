@@ -9,8 +9,11 @@ import (
 // doCommit executes the git commit asynchronously.
 func (p *Plugin) doCommit(message string) tea.Cmd {
 	workDir := p.repoRoot
+	opts := p.commitOptions()
 	return func() tea.Msg {
-		hash, err := ExecuteCommit(workDir, message)
+		before := currentHead(workDir)
+		hash, err := ExecuteCommit(workDir, message, opts)
+		logOperation(workDir, "commit", before)
 		if err != nil {
 			return CommitErrorMsg{Err: err}
 		}
@@ -23,8 +26,11 @@ func (p *Plugin) doCommit(message string) tea.Cmd {
 // doAmend executes git commit --amend asynchronously.
 func (p *Plugin) doAmend(message string) tea.Cmd {
 	workDir := p.repoRoot
+	opts := p.commitOptions()
 	return func() tea.Msg {
-		hash, err := ExecuteAmend(workDir, message)
+		before := currentHead(workDir)
+		hash, err := ExecuteAmend(workDir, message, opts)
+		logOperation(workDir, "amend", before)
 		if err != nil {
 			return CommitErrorMsg{Err: err}
 		}
@@ -83,12 +89,16 @@ func (p *Plugin) doStashPush() tea.Cmd {
 	}
 }
 
-// doStashPop pops the latest stash.
-func (p *Plugin) doStashPop() tea.Cmd {
+// doStashPop pops a stash, journaling it so it can be undone.
+func (p *Plugin) doStashPop(ref string) tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
-		err := StashPop(workDir)
-		return StashResultMsg{Operation: "pop", Ref: "stash@{0}", Err: err}
+		rec := beginStashPop(workDir, ref)
+		err := StashPopRef(workDir, ref)
+		if err == nil && rec != nil {
+			logFileOp(workDir, rec)
+		}
+		return StashResultMsg{Operation: "pop", Ref: ref, Err: err}
 	}
 }
 
@@ -117,7 +127,9 @@ func (p *Plugin) doFetch() tea.Cmd {
 func (p *Plugin) doPull() tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		before := currentHead(workDir)
 		output, err := ExecutePull(workDir)
+		logOperation(workDir, "pull", before)
 		if err != nil {
 			return PullErrorMsg{Err: err, Strategy: "merge"}
 		}
@@ -129,7 +141,9 @@ func (p *Plugin) doPull() tea.Cmd {
 func (p *Plugin) doPullRebase() tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		before := currentHead(workDir)
 		output, err := ExecutePullRebase(workDir)
+		logOperation(workDir, "pull --rebase", before)
 		if err != nil {
 			return PullErrorMsg{Err: err, Strategy: "rebase"}
 		}
@@ -141,7 +155,9 @@ func (p *Plugin) doPullRebase() tea.Cmd {
 func (p *Plugin) doPullFFOnly() tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		before := currentHead(workDir)
 		output, err := ExecutePullFFOnly(workDir)
+		logOperation(workDir, "pull --ff-only", before)
 		if err != nil {
 			return PullErrorMsg{Err: err, Strategy: "ff-only"}
 		}
@@ -153,7 +169,9 @@ func (p *Plugin) doPullFFOnly() tea.Cmd {
 func (p *Plugin) doPullAutostash() tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		before := currentHead(workDir)
 		output, err := ExecutePullAutostash(workDir)
+		logOperation(workDir, "pull --rebase --autostash", before)
 		if err != nil {
 			return PullErrorMsg{Err: err, Strategy: "autostash"}
 		}
@@ -187,10 +205,12 @@ func (p *Plugin) canPull() bool {
 	return HasRemote(p.repoRoot)
 }
 
-// doDiscard executes the git discard operation.
+// doDiscard executes the git discard operation, journaling it so it can be
+// undone.
 func (p *Plugin) doDiscard(entry *FileEntry) tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		rec := beginFileOp(workDir, opKindDiscard, "discard "+entry.Path, []string{entry.Path})
 		var err error
 		if entry.Status == StatusUntracked {
 			// Remove untracked file
@@ -205,6 +225,7 @@ func (p *Plugin) doDiscard(entry *FileEntry) tea.Cmd {
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
+		logFileOp(workDir, rec)
 		return RefreshDoneMsg{}
 	}
 }
@@ -21,6 +21,10 @@ type Commit struct {
 	Pushed       bool     // Whether this commit has been pushed to upstream
 	ParentHashes []string // Parent commit hashes (empty for root commits)
 	IsMerge      bool     // True if commit has multiple parents
+	Tags         []string // Tags pointing at this commit
+	Signature    SignatureStatus
+	Signer       string // Signer identity, if signed (detail only)
+	SigningKey   string // Key ID or fingerprint, if signed (detail only)
 }
 
 // CommitFile represents a file changed in a commit.
@@ -39,19 +43,24 @@ type CommitStats struct {
 	Deletions    int
 }
 
-// GetCommitHistory fetches recent commits.
-func GetCommitHistory(workDir string, limit int) ([]*Commit, error) {
-	// Format: hash\x00shorthash\x00author\x00email\x00timestamp\x00subject\x00parents
-	format := "%H%x00%h%x00%an%x00%ae%x00%at%x00%s%x00%P"
-	args := []string{"log", "--format=" + format, "-n", strconv.Itoa(limit)}
-
-	cmd := exec.Command("git", args...)
-	cmd.Dir = workDir
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err
+// commitLogFormat is the git log format parsed by parseCommitLog:
+// hash\x00shorthash\x00author\x00email\x00timestamp\x00subject\x00parents\x00refs
+const commitLogFormat = "%H%x00%h%x00%an%x00%ae%x00%at%x00%s%x00%P%x00%D"
+
+// parseRefTags extracts tag names from a %D ref list such as
+// "HEAD -> main, tag: v1.0.0, origin/main".
+func parseRefTags(refs string) []string {
+	var tags []string
+	for _, ref := range strings.Split(refs, ", ") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(ref), "tag: "); ok {
+			tags = append(tags, strings.TrimPrefix(name, "refs/tags/")) // log.decorate=full
+		}
 	}
+	return tags
+}
 
+// parseCommitLog parses git log output produced with commitLogFormat.
+func parseCommitLog(output []byte) []*Commit {
 	var commits []*Commit
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	for _, line := range lines {
@@ -71,6 +80,11 @@ func GetCommitHistory(workDir string, limit int) ([]*Commit, error) {
 			parents = strings.Split(parts[6], " ")
 		}
 
+		var tags []string
+		if len(parts) >= 8 {
+			tags = parseRefTags(parts[7])
+		}
+
 		commits = append(commits, &Commit{
 			Hash:         parts[0],
 			ShortHash:    parts[1],
@@ -80,16 +94,51 @@ func GetCommitHistory(workDir string, limit int) ([]*Commit, error) {
 			Subject:      parts[5],
 			ParentHashes: parents,
 			IsMerge:      len(parents) > 1,
+			Tags:         tags,
 		})
 	}
 
-	return commits, nil
+	return commits
+}
+
+// GetCommitHistory fetches recent commits.
+func GetCommitHistory(workDir string, limit int) ([]*Commit, error) {
+	format := commitLogFormat
+	args := []string{"log", "--format=" + format, "-n", strconv.Itoa(limit)}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitLog(output), nil
+}
+
+// GetCommitRange fetches the commits reachable from HEAD but not from base,
+// newest first, following only first parents.
+func GetCommitRange(workDir, base string) ([]*Commit, error) {
+	return GetCommitsBetween(workDir, base, "HEAD")
+}
+
+// GetCommitsBetween fetches the commits reachable from to but not from from,
+// newest first, following only first parents.
+func GetCommitsBetween(workDir, from, to string) ([]*Commit, error) {
+	cmd := exec.Command("git", "log", "--format="+commitLogFormat, "--first-parent", from+".."+to)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(output), nil
 }
 
 // GetCommitDetail fetches full commit info including file list.
 func GetCommitDetail(workDir, hash string) (*Commit, error) {
-	// Get commit metadata (%P = parent hashes for merge detection)
-	format := "%H%n%h%n%an%n%ae%n%at%n%P%n%s%n%b"
+	// Get commit metadata (%P = parent hashes for merge detection, %D = refs,
+	// %G?/%GS/%GK = signature status, signer and key)
+	format := "%H%n%h%n%an%n%ae%n%at%n%P%n%D%n%G?%n%GS%n%GK%n%s%n%b"
 	cmd := exec.Command("git", "show", "--format="+format, "-s", hash)
 	cmd.Dir = workDir
 	output, err := cmd.Output()
@@ -97,8 +146,8 @@ func GetCommitDetail(workDir, hash string) (*Commit, error) {
 		return nil, err
 	}
 
-	lines := strings.SplitN(string(output), "\n", 8)
-	if len(lines) < 7 {
+	lines := strings.SplitN(string(output), "\n", 12)
+	if len(lines) < 11 {
 		return nil, nil
 	}
 
@@ -116,12 +165,16 @@ func GetCommitDetail(workDir, hash string) (*Commit, error) {
 		Author:       strings.TrimSpace(lines[2]),
 		AuthorEmail:  strings.TrimSpace(lines[3]),
 		Date:         time.Unix(timestamp, 0),
-		Subject:      strings.TrimSpace(lines[6]),
+		Subject:      strings.TrimSpace(lines[10]),
 		ParentHashes: parents,
 		IsMerge:      len(parents) > 1,
+		Tags:         parseRefTags(strings.TrimSpace(lines[6])),
+		Signature:    SignatureStatus(strings.TrimSpace(lines[7])),
+		Signer:       strings.TrimSpace(lines[8]),
+		SigningKey:   strings.TrimSpace(lines[9]),
 	}
-	if len(lines) > 7 {
-		commit.Body = strings.TrimSpace(lines[7])
+	if len(lines) > 11 {
+		commit.Body = strings.TrimSpace(lines[11])
 	}
 
 	// Get file stats — for merge commits, diff against first parent to avoid empty combined diff
@@ -255,7 +308,7 @@ func GetCommitHistoryWithPushStatus(workDir string, limit int) ([]*Commit, *Push
 // GetCommitHistoryWithOffset fetches commits starting from skip, up to limit.
 // Uses git log --skip=N to paginate through history.
 func GetCommitHistoryWithOffset(workDir string, limit, skip int) ([]*Commit, error) {
-	format := "%H%x00%h%x00%an%x00%ae%x00%at%x00%s%x00%P"
+	format := commitLogFormat
 	args := []string{"log", "--format=" + format, "-n", strconv.Itoa(limit), "--skip", strconv.Itoa(skip)}
 
 	cmd := exec.Command("git", args...)
@@ -265,38 +318,7 @@ func GetCommitHistoryWithOffset(workDir string, limit, skip int) ([]*Commit, err
 		return nil, err
 	}
 
-	var commits []*Commit
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, "\x00")
-		if len(parts) < 6 {
-			continue
-		}
-
-		timestamp, _ := strconv.ParseInt(parts[4], 10, 64)
-
-		// Parse parent hashes (space-separated in parts[6])
-		var parents []string
-		if len(parts) >= 7 && parts[6] != "" {
-			parents = strings.Split(parts[6], " ")
-		}
-
-		commits = append(commits, &Commit{
-			Hash:         parts[0],
-			ShortHash:    parts[1],
-			Author:       parts[2],
-			AuthorEmail:  parts[3],
-			Date:         time.Unix(timestamp, 0),
-			Subject:      parts[5],
-			ParentHashes: parents,
-			IsMerge:      len(parents) > 1,
-		})
-	}
-
-	return commits, nil
+	return parseCommitLog(output), nil
 }
 
 // GetCommitHistoryWithPushStatusOffset fetches commits with offset and populates push status.
@@ -322,7 +344,7 @@ type HistoryFilterOpts struct {
 
 // GetCommitHistoryFiltered fetches commits with filters applied.
 func GetCommitHistoryFiltered(workDir string, opts HistoryFilterOpts) ([]*Commit, error) {
-	format := "%H%x00%h%x00%an%x00%ae%x00%at%x00%s%x00%P"
+	format := commitLogFormat
 	args := []string{"log", "--format=" + format}
 
 	if opts.Author != "" {
@@ -347,38 +369,7 @@ func GetCommitHistoryFiltered(workDir string, opts HistoryFilterOpts) ([]*Commit
 		return nil, err
 	}
 
-	var commits []*Commit
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, "\x00")
-		if len(parts) < 6 {
-			continue
-		}
-
-		timestamp, _ := strconv.ParseInt(parts[4], 10, 64)
-
-		// Parse parent hashes (space-separated in parts[6])
-		var parents []string
-		if len(parts) >= 7 && parts[6] != "" {
-			parents = strings.Split(parts[6], " ")
-		}
-
-		commits = append(commits, &Commit{
-			Hash:         parts[0],
-			ShortHash:    parts[1],
-			Author:       parts[2],
-			AuthorEmail:  parts[3],
-			Date:         time.Unix(timestamp, 0),
-			Subject:      parts[5],
-			ParentHashes: parents,
-			IsMerge:      len(parents) > 1,
-		})
-	}
-
-	return commits, nil
+	return parseCommitLog(output), nil
 }
 
 // GetCommitHistoryFilteredWithPushStatus fetches filtered commits and populates push status.
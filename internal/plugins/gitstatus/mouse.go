@@ -8,15 +8,29 @@ import (
 
 // Hit region IDs
 const (
-	regionSidebar      = "sidebar"
-	regionDiffPane     = "diff-pane"
-	regionPaneDivider  = "pane-divider"
-	regionFile         = "file"
-	regionCommit       = "commit"
-	regionCommitFile   = "commit-file"   // Files in commit preview pane
-	regionDiffModal    = "diff-modal"    // Full-screen diff view
-	regionDiffBack     = "diff-back"     // Back button in diff breadcrumb
-	regionCommitButton = "commit-button" // Commit modal button
+	regionSidebar         = "sidebar"
+	regionDiffPane        = "diff-pane"
+	regionPaneDivider     = "pane-divider"
+	regionFile            = "file"
+	regionCommit          = "commit"
+	regionCommitFile      = "commit-file"       // Files in commit preview pane
+	regionDiffModal       = "diff-modal"        // Full-screen diff view
+	regionDiffBack        = "diff-back"         // Back button in diff breadcrumb
+	regionCommitButton    = "commit-button"     // Commit modal button
+	regionConflicts       = "conflicts"         // Full-screen conflict resolver
+	regionConflictFile    = "conflict-file"     // File row in conflict resolver
+	regionRelease         = "release"           // Full-screen changes since the last tag
+	regionFileHistoryList = "file-history-list" // Commit column in file history
+	regionFileHistoryDiff = "file-history-diff" // Diff column in file history
+	regionBisectList      = "bisect-list"       // Commit range in the bisect view
+	regionBisectDiff      = "bisect-diff"       // Diff column in the bisect view
+	regionStashFiles      = "stash-files"       // File column in the stash view
+	regionStashDiff       = "stash-diff"        // Diff column in the stash view
+	regionCompareTab      = "compare-tab"       // Tab chip in the branch comparison
+	regionCompareList     = "compare-list"      // List column in the branch comparison
+	regionCompareDiff     = "compare-diff"      // Diff column in the branch comparison
+	regionPatchList       = "patch-list"        // Patch column in the apply preview
+	regionPatchDiff       = "patch-diff"        // Diff column in the apply preview
 )
 
 // handleMouse processes mouse events in the status view.
@@ -380,7 +394,9 @@ func (p *Plugin) handleCommitMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 	case "cancel":
 		p.viewMode = ViewModeStatus
 		p.commitAmend = false
+		p.commitSignOff = false
 		p.commitError = ""
+		p.resetCommitAssist()
 		p.commitModal = nil
 		p.commitModalWidthCache = 0
 		return p, nil
@@ -477,9 +493,18 @@ func (p *Plugin) handlePullConflictMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 
 	action := p.pullConflictModal.HandleMouse(msg, p.mouseHandler)
 	switch action {
+	case pullConflictResolveID:
+		plug, cmd := p.resolvePullConflict()
+		return plug.(*Plugin), cmd
 	case pullConflictAbortID:
 		plug, cmd := p.abortPullConflict()
 		return plug.(*Plugin), cmd
+	case pullConflictContinueID:
+		plug, cmd := p.continueRebaseConflict()
+		return plug.(*Plugin), cmd
+	case pullConflictSkipID:
+		plug, cmd := p.skipRebaseConflict()
+		return plug.(*Plugin), cmd
 	case "cancel", pullConflictDismissID:
 		plug, cmd := p.dismissPullConflict()
 		return plug.(*Plugin), cmd
@@ -487,6 +512,46 @@ func (p *Plugin) handlePullConflictMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 	return p, nil
 }
 
+// handleRebaseMouse processes mouse events in the rebase editor.
+func (p *Plugin) handleRebaseMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureRebaseModal()
+	if p.rebaseModal == nil || p.rebasePlan == nil {
+		return p, nil
+	}
+
+	action := p.rebaseModal.HandleMouse(msg, p.mouseHandler)
+	if idx, ok := parseRebaseItem(action); ok {
+		p.rebaseCursor = idx
+		return p, nil
+	}
+	switch action {
+	case rebaseRunID:
+		return p, p.runRebase()
+	case rebaseSaveReword:
+		p.saveRebaseReword()
+	case rebaseBackID:
+		p.setRebaseStage(rebaseStagePlan)
+	case "cancel":
+		if p.rebaseStage == rebaseStagePlan {
+			p.closeRebaseEditor()
+		} else {
+			p.setRebaseStage(rebaseStagePlan)
+		}
+	}
+	return p, nil
+}
+
+// handleCommitActionMouse processes mouse events in the cherry-pick/revert/reset modal.
+func (p *Plugin) handleCommitActionMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureCommitActionModal()
+	if p.commitActionModal == nil {
+		return p, nil
+	}
+
+	action := p.commitActionModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleCommitActionAction(action)
+}
+
 // handleDiffMouse processes mouse events in the full-screen diff view.
 func (p *Plugin) handleDiffMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 	action := p.mouseHandler.HandleMouse(msg)
@@ -545,3 +610,334 @@ func (p *Plugin) handleDiffMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 
 	return p, nil
 }
+
+// handleReflogMouse processes mouse events in the reflog browser.
+func (p *Plugin) handleReflogMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureReflogModal()
+	if p.reflogModal == nil {
+		return p, nil
+	}
+
+	action := p.reflogModal.HandleMouse(msg, p.mouseHandler)
+	if action == "cancel" {
+		p.closeReflog()
+		return p, nil
+	}
+	if idx, ok := parseReflogItem(action); ok {
+		return p, p.planRestore(idx)
+	}
+	return p, nil
+}
+
+// handleConfirmUndoMouse processes mouse events in the undo confirmation modal.
+func (p *Plugin) handleConfirmUndoMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureUndoModal()
+	if p.undoModal == nil {
+		return p, nil
+	}
+
+	action := p.undoModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleUndoAction(action)
+}
+
+// handleConflictsMouse processes mouse events in the conflict resolver.
+func (p *Plugin) handleConflictsMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+
+	switch action.Type {
+	case mouse.ActionClick:
+		if action.Region != nil && action.Region.ID == regionConflictFile {
+			if idx, ok := action.Region.Data.(int); ok && idx != p.conflictFileIdx {
+				return p, p.selectConflictFile(idx)
+			}
+		}
+
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		p.conflictScroll = max(p.conflictScroll+action.Delta, 0)
+	}
+	return p, nil
+}
+
+// handleTagCreateMouse processes mouse events in the tag prompt.
+func (p *Plugin) handleTagCreateMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureTagCreateModal()
+	if p.tagCreateModal == nil {
+		return p, nil
+	}
+
+	action := p.tagCreateModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleTagCreateAction(action)
+}
+
+// handleTagsMouse processes mouse events in the tag list.
+func (p *Plugin) handleTagsMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureTagsModal()
+	if p.tagsModal == nil {
+		return p, nil
+	}
+
+	action := p.tagsModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleTagsAction(action)
+}
+
+// handleConfirmDeleteTagMouse processes mouse events in the tag delete confirmation.
+func (p *Plugin) handleConfirmDeleteTagMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureTagDeleteModal()
+	if p.tagDeleteModal == nil {
+		return p, nil
+	}
+
+	action := p.tagDeleteModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleTagDeleteAction(action)
+}
+
+// handleReleaseMouse processes mouse events in the release view.
+func (p *Plugin) handleReleaseMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+
+	switch action.Type {
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		p.releaseScroll = max(p.releaseScroll+action.Delta, 0)
+	}
+	return p, nil
+}
+
+// handleFileHistoryMouse processes mouse events in the file history view.
+func (p *Plugin) handleFileHistoryMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+	if action.Region == nil {
+		return p, nil
+	}
+
+	switch action.Type {
+	case mouse.ActionClick:
+		if action.Region.ID == regionFileHistoryList {
+			row := action.Y - action.Region.Rect.Y
+			return p, p.moveHistoryCursor(p.fileHistoryListScroll + row)
+		}
+
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		switch action.Region.ID {
+		case regionFileHistoryList:
+			return p, p.moveHistoryCursor(p.fileHistoryCursor + action.Delta)
+		case regionFileHistoryDiff:
+			p.fileHistoryDiffScroll = max(p.fileHistoryDiffScroll+action.Delta, 0)
+		}
+	}
+	return p, nil
+}
+
+// handlePickaxeMouse processes mouse events in the pickaxe prompt.
+func (p *Plugin) handlePickaxeMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensurePickaxeModal()
+	if p.pickaxeModal == nil {
+		return p, nil
+	}
+
+	action := p.pickaxeModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handlePickaxeAction(action)
+}
+
+// handleBisectMenuMouse processes mouse events in the bisect prompt.
+func (p *Plugin) handleBisectMenuMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureBisectMenuModal()
+	if p.bisectMenuModal == nil {
+		return p, nil
+	}
+
+	action := p.bisectMenuModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleBisectMenuAction(action)
+}
+
+// handleBisectMouse processes mouse events in the bisect view.
+func (p *Plugin) handleBisectMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+	if action.Region == nil {
+		return p, nil
+	}
+
+	switch action.Type {
+	case mouse.ActionClick:
+		if action.Region.ID == regionBisectList {
+			row := action.Y - action.Region.Rect.Y
+			return p, p.moveBisectCursor(p.bisectListScroll + row)
+		}
+
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		switch action.Region.ID {
+		case regionBisectList:
+			return p, p.moveBisectCursor(p.bisectCursor + action.Delta)
+		case regionBisectDiff:
+			p.bisectDiffScroll = max(p.bisectDiffScroll+action.Delta, 0)
+		}
+	}
+	return p, nil
+}
+
+// handleBisectRunMouse processes mouse events in the test command prompt.
+func (p *Plugin) handleBisectRunMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureBisectRunModal()
+	if p.bisectRunModal == nil {
+		return p, nil
+	}
+
+	action := p.bisectRunModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleBisectRunAction(action)
+}
+
+// handleStashesMouse processes mouse events in the stash list.
+func (p *Plugin) handleStashesMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureStashesModal()
+	if p.stashesModal == nil {
+		return p, nil
+	}
+
+	action := p.stashesModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleStashesAction(action)
+}
+
+// handleStashMouse processes mouse events in the stash detail view.
+func (p *Plugin) handleStashMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+	if action.Region == nil {
+		return p, nil
+	}
+
+	switch action.Type {
+	case mouse.ActionClick:
+		if action.Region.ID == regionStashFiles {
+			row := action.Y - action.Region.Rect.Y
+			return p, p.moveStashFileCursor(p.stashFileScroll + row)
+		}
+
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		switch action.Region.ID {
+		case regionStashFiles:
+			return p, p.moveStashFileCursor(p.stashFileCursor + action.Delta)
+		case regionStashDiff:
+			p.stashDiffScroll = max(p.stashDiffScroll+action.Delta, 0)
+		}
+	}
+	return p, nil
+}
+
+// handleStashBranchMouse processes mouse events in the branch from stash prompt.
+func (p *Plugin) handleStashBranchMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureStashBranchModal()
+	if p.stashBranchModal == nil {
+		return p, nil
+	}
+
+	action := p.stashBranchModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleStashBranchAction(action)
+}
+
+// handleStashDropMouse processes mouse events in the stash drop confirmation.
+func (p *Plugin) handleStashDropMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureStashDropModal()
+	if p.stashDropModal == nil {
+		return p, nil
+	}
+
+	action := p.stashDropModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleStashDropAction(action)
+}
+
+// handleCommitTrailersMouse processes mouse events in the trailer helper.
+func (p *Plugin) handleCommitTrailersMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureCommitTrailerModal()
+	if p.commitTrailerModal == nil {
+		return p, nil
+	}
+
+	action := p.commitTrailerModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleCommitTrailerAction(action)
+}
+
+// handleComparePromptMouse processes mouse events in the compare prompt.
+func (p *Plugin) handleComparePromptMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureComparePromptModal()
+	if p.comparePromptModal == nil {
+		return p, nil
+	}
+
+	action := p.comparePromptModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleComparePromptAction(action)
+}
+
+// handleCompareMouse processes mouse events in the branch comparison.
+func (p *Plugin) handleCompareMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+	if action.Region == nil {
+		return p, nil
+	}
+
+	switch action.Type {
+	case mouse.ActionClick:
+		switch action.Region.ID {
+		case regionCompareTab:
+			if tab, ok := action.Region.Data.(int); ok {
+				return p, p.setCompareTab(compareTab(tab))
+			}
+		case regionCompareList:
+			row := action.Y - action.Region.Rect.Y
+			return p, p.moveCompareCursor(p.compareListScroll + row)
+		}
+
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		switch action.Region.ID {
+		case regionCompareList:
+			return p, p.moveCompareCursor(p.compareCursor + action.Delta)
+		case regionCompareDiff:
+			p.compareDiffScroll = max(p.compareDiffScroll+action.Delta, 0)
+		}
+	}
+	return p, nil
+}
+
+// handlePatchExportMouse processes mouse events in the patch export prompt.
+func (p *Plugin) handlePatchExportMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensurePatchExportModal()
+	if p.patchExportModal == nil {
+		return p, nil
+	}
+
+	action := p.patchExportModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handlePatchExportAction(action)
+}
+
+// handlePatchApplyMouse processes mouse events in the patch apply preview.
+func (p *Plugin) handlePatchApplyMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+	if action.Region == nil {
+		return p, nil
+	}
+
+	switch action.Type {
+	case mouse.ActionClick:
+		if action.Region.ID == regionPatchList {
+			row := action.Y - action.Region.Rect.Y
+			p.selectPatch(p.patchListScroll + row)
+		}
+
+	case mouse.ActionScrollUp, mouse.ActionScrollDown:
+		switch action.Region.ID {
+		case regionPatchList:
+			p.selectPatch(p.patchCursor + action.Delta)
+		case regionPatchDiff:
+			p.patchDiffScroll = max(p.patchDiffScroll+action.Delta, 0)
+		}
+	}
+	return p, nil
+}
+
+// handleSubmodulesMouse processes mouse events in the submodule list.
+func (p *Plugin) handleSubmodulesMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureSubmodulesModal()
+	if p.submodulesModal == nil {
+		return p, nil
+	}
+
+	action := p.submodulesModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.handleSubmodulesAction(action)
+}
@@ -0,0 +1,482 @@
+package gitstatus
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
+)
+
+// diffPos locates a flat diff line index within a parsed diff.
+// Line is -1 for the hunk header.
+type diffPos struct {
+	Hunk int
+	Line int
+}
+
+// flattenDiff maps flat line indexes (hunk header followed by its lines, as
+// counted by countParsedDiffLines) to hunk/line positions.
+func flattenDiff(diff *ParsedDiff) []diffPos {
+	if diff == nil {
+		return nil
+	}
+	positions := make([]diffPos, 0, countParsedDiffLines(diff))
+	for h, hunk := range diff.Hunks {
+		positions = append(positions, diffPos{Hunk: h, Line: -1})
+		for l := range hunk.Lines {
+			positions = append(positions, diffPos{Hunk: h, Line: l})
+		}
+	}
+	return positions
+}
+
+// DiffSelection tracks the line cursor and optional range anchor used for
+// partial staging in the inline diff pane. Values are flat line indexes.
+type DiffSelection struct {
+	Cursor    int
+	Anchor    int  // Start of the range when Selecting
+	Selecting bool // True while a line range is being selected
+}
+
+// HasRange returns true when a line range is being selected.
+func (s DiffSelection) HasRange() bool {
+	return s.Selecting
+}
+
+// Bounds returns the inclusive flat index range covered by the selection.
+func (s DiffSelection) Bounds() (int, int) {
+	if !s.HasRange() {
+		return s.Cursor, s.Cursor
+	}
+	if s.Anchor < s.Cursor {
+		return s.Anchor, s.Cursor
+	}
+	return s.Cursor, s.Anchor
+}
+
+// Contains reports whether the flat index falls within the selection.
+func (s DiffSelection) Contains(idx int) bool {
+	start, end := s.Bounds()
+	return idx >= start && idx <= end
+}
+
+// lineSelector returns a predicate for BuildPartialPatch from a selection.
+// Without a range the whole hunk under the cursor is selected; with a range
+// only the changed lines inside it are.
+func (s DiffSelection) lineSelector(diff *ParsedDiff) func(hunk, line int) bool {
+	positions := flattenDiff(diff)
+	if len(positions) == 0 {
+		return func(int, int) bool { return false }
+	}
+	if !s.HasRange() {
+		cursor := s.Cursor
+		if cursor >= len(positions) {
+			cursor = len(positions) - 1
+		}
+		target := positions[cursor].Hunk
+		return func(hunk, _ int) bool { return hunk == target }
+	}
+	selected := make(map[diffPos]bool)
+	start, end := s.Bounds()
+	for i := start; i <= end && i < len(positions); i++ {
+		if positions[i].Line >= 0 {
+			selected[positions[i]] = true
+		}
+	}
+	return func(hunk, line int) bool { return selected[diffPos{Hunk: hunk, Line: line}] }
+}
+
+// BuildPartialPatch builds a patch for path containing only the changed lines
+// accepted by selected. Unselected changes are dropped or turned into context
+// so the patch still applies cleanly.
+//
+// reverse must match how the patch will be applied: false for `git apply`
+// (staging an unstaged diff), true for `git apply --reverse` (unstaging a
+// staged diff or discarding worktree changes). Returns "" when no changed
+// line is selected.
+func BuildPartialPatch(diff *ParsedDiff, path string, selected func(hunk, line int) bool, reverse bool) string {
+	if diff == nil || diff.Binary {
+		return ""
+	}
+
+	var body strings.Builder
+	delta := 0 // Cumulative line count shift from previously emitted hunks
+
+	for h, hunk := range diff.Hunks {
+		var lines []patchLine
+		oldCount, newCount := 0, 0
+		changed := false
+		seenOld, seenNew := 0, 0
+
+		for l, line := range hunk.Lines {
+			// Stop once the hunk's declared counts are consumed; the parser can
+			// append a trailing empty context line from the diff's final newline.
+			if seenOld >= hunk.OldCount && seenNew >= hunk.NewCount {
+				break
+			}
+			switch line.Type {
+			case LineContext:
+				seenOld++
+				seenNew++
+				lines = append(lines, patchLine{' ', line.Content, line.NoNewline})
+				oldCount++
+				newCount++
+			case LineAdd:
+				seenNew++
+				if selected(h, l) {
+					lines = append(lines, patchLine{'+', line.Content, line.NoNewline})
+					newCount++
+					changed = true
+				} else if reverse {
+					// Line exists in the target; keep it as context
+					lines = append(lines, patchLine{' ', line.Content, line.NoNewline})
+					oldCount++
+					newCount++
+				}
+			case LineRemove:
+				seenOld++
+				if selected(h, l) {
+					lines = append(lines, patchLine{'-', line.Content, line.NoNewline})
+					oldCount++
+					changed = true
+				} else if !reverse {
+					// Line exists in the target; keep it as context
+					lines = append(lines, patchLine{' ', line.Content, line.NoNewline})
+					oldCount++
+					newCount++
+				}
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		oldStart, newStart := hunk.OldStart, hunk.OldStart+delta
+		if reverse {
+			newStart = hunk.NewStart
+			oldStart = hunk.NewStart - delta
+		}
+		fmt.Fprintf(&body, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		writePatchHunk(&body, lines)
+		delta += newCount - oldCount
+	}
+
+	if body.Len() == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", path, path)
+	fmt.Fprintf(&sb, "--- a/%s\n", path)
+	fmt.Fprintf(&sb, "+++ b/%s\n", path)
+	sb.WriteString(body.String())
+	return sb.String()
+}
+
+// patchLine is a line of a partial patch hunk.
+type patchLine struct {
+	op        byte // ' ', '+' or '-'
+	text      string
+	noNewline bool
+}
+
+// writePatchHunk writes the lines of a hunk. A side of the hunk ends
+// without a newline when its last line did in the diff, and only that line
+// gets git's marker. A context line that ends one side but not the other is
+// written as a removal and an addition so each side gets its own ending.
+func writePatchHunk(sb *strings.Builder, lines []patchLine) {
+	lastOld, lastNew := -1, -1
+	for i, line := range lines {
+		if line.op != '+' {
+			lastOld = i
+		}
+		if line.op != '-' {
+			lastNew = i
+		}
+	}
+	write := func(op byte, text string, noNewline bool) {
+		sb.WriteByte(op)
+		sb.WriteString(text)
+		sb.WriteString("\n")
+		if noNewline {
+			sb.WriteString("\\ No newline at end of file\n")
+		}
+	}
+	for i, line := range lines {
+		oldEnd := line.noNewline && i == lastOld
+		newEnd := line.noNewline && i == lastNew
+		switch {
+		case line.op == '-':
+			write('-', line.text, oldEnd)
+		case line.op == '+':
+			write('+', line.text, newEnd)
+		case oldEnd == newEnd:
+			write(' ', line.text, oldEnd)
+		default:
+			write('-', line.text, oldEnd)
+			write('+', line.text, newEnd)
+		}
+	}
+}
+
+// applyPatch feeds a patch to `git apply` with the given extra arguments.
+func applyPatch(workDir, patch string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"apply"}, args...)...)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(patch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DiscardPatch reverts the changes in patch from the working tree.
+func DiscardPatch(workDir, patch string) error {
+	return applyPatch(workDir, patch, "--reverse")
+}
+
+// partialOp identifies a partial staging operation.
+type partialOp int
+
+const (
+	partialStage partialOp = iota
+	partialUnstage
+	partialDiscard
+)
+
+// PartialStageDoneMsg is sent when a partial stage, unstage or discard finishes.
+type PartialStageDoneMsg struct {
+	Op   partialOp
+	Path string
+	Err  error
+}
+
+// selectedDiffEntry returns the file entry shown in the inline diff pane if it
+// supports partial staging.
+func (p *Plugin) selectedDiffEntry() *FileEntry {
+	if p.cursorOnCommit() {
+		return nil
+	}
+	entries := p.tree.AllEntries()
+	if p.cursor >= len(entries) {
+		return nil
+	}
+	entry := entries[p.cursor]
+	if entry.IsFolder || entry.Path != p.selectedDiffFile {
+		return nil
+	}
+	return entry
+}
+
+// partialPatch builds the patch for the current diff pane selection.
+func (p *Plugin) partialPatch(entry *FileEntry, reverse bool) string {
+	diff := p.diffPaneParsedDiff
+	return BuildPartialPatch(diff, entry.Path, p.diffSelection.lineSelector(diff), reverse)
+}
+
+// stageSelection stages, unstages or discards the selected hunk or lines.
+// Discards go through the confirm modal first. A diff with no lines to
+// select, such as a binary file's, is staged or unstaged as a whole file.
+func (p *Plugin) stageSelection(op partialOp) tea.Cmd {
+	entry := p.selectedDiffEntry()
+	if entry == nil {
+		return nil
+	}
+	if entry.Status == StatusUntracked {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "Stage the whole file first (untracked)", Duration: 2 * time.Second}
+		}
+	}
+	// Staging and discarding work on the unstaged diff, unstaging on the staged one
+	if (op == partialUnstage) != entry.Staged {
+		toast := "Already staged: u unstages"
+		if !entry.Staged {
+			toast = "Not staged: s stages"
+		} else if op == partialDiscard {
+			toast = "Unstage first to discard"
+		}
+		return func() tea.Msg {
+			return app.ToastMsg{Message: toast, Duration: 2 * time.Second}
+		}
+	}
+
+	if p.diffPaneParsedDiff == nil {
+		return nil // Still loading
+	}
+	if op != partialDiscard && len(flattenDiff(p.diffPaneParsedDiff)) == 0 {
+		return p.stageWholeFile(op, entry)
+	}
+
+	patch := p.partialPatch(entry, op != partialStage)
+	if patch == "" {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "No changed lines selected", Duration: 2 * time.Second}
+		}
+	}
+
+	if op == partialDiscard {
+		p.discardFile = entry
+		p.discardPatch = patch
+		p.discardReturnMode = p.viewMode
+		p.viewMode = ViewModeConfirmDiscard
+		p.buildDiscardModal()
+		return nil
+	}
+	return p.doApplyPartial(op, entry, patch)
+}
+
+// stageWholeFile stages or unstages entry as a whole, as the sidebar does.
+func (p *Plugin) stageWholeFile(op partialOp, entry *FileEntry) tea.Cmd {
+	var err error
+	action := "Stage"
+	if op == partialStage {
+		err = p.tree.StageFile(entry.Path)
+	} else {
+		action = "Unstage"
+		err = p.tree.UnstageFile(entry.Path)
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: action + " failed: " + err.Error(), Duration: 3 * time.Second, IsError: true}
+		}
+	}
+	return tea.Batch(p.refresh(), p.loadRecentCommits())
+}
+
+// doApplyPartial applies a partial patch asynchronously.
+func (p *Plugin) doApplyPartial(op partialOp, entry *FileEntry, patch string) tea.Cmd {
+	tree := p.tree
+	workDir := p.repoRoot
+	path := entry.Path
+	p.partialTarget = entry
+	return func() tea.Msg {
+		var err error
+		switch op {
+		case partialStage:
+			err = tree.StagePatch(patch)
+		case partialUnstage:
+			err = tree.UnstagePatch(patch)
+		case partialDiscard:
+			rec := beginFileOp(workDir, opKindDiscard, "discard lines in "+path, []string{path})
+			if err = DiscardPatch(workDir, patch); err == nil {
+				logFileOp(workDir, rec)
+			}
+		}
+		return PartialStageDoneMsg{Op: op, Path: path, Err: err}
+	}
+}
+
+// restorePartialTarget moves the sidebar cursor back to the file that was
+// partially staged so the diff pane refreshes in place. Prefers the entry on
+// the same side (staged/unstaged) and falls back to the other side once it is
+// fully staged or unstaged.
+func (p *Plugin) restorePartialTarget() {
+	target := p.partialTarget
+	p.partialTarget = nil
+	if target == nil {
+		return
+	}
+	entries := p.tree.AllEntries()
+	fallback := -1
+	for i, e := range entries {
+		if e.Path != target.Path {
+			continue
+		}
+		if e.Staged == target.Staged {
+			p.cursor = i
+			return
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback >= 0 {
+		p.cursor = fallback
+	}
+}
+
+// moveDiffCursor moves the diff pane line cursor by delta and keeps it visible.
+func (p *Plugin) moveDiffCursor(delta int) {
+	total := countParsedDiffLines(p.diffPaneParsedDiff)
+	if total == 0 {
+		return
+	}
+	p.diffSelection.Cursor += delta
+	if p.diffSelection.Cursor < 0 {
+		p.diffSelection.Cursor = 0
+	}
+	if p.diffSelection.Cursor > total-1 {
+		p.diffSelection.Cursor = total - 1
+	}
+	p.ensureDiffCursorVisible()
+}
+
+// jumpDiffHunk moves the diff pane cursor to the next (dir > 0) or previous hunk header.
+func (p *Plugin) jumpDiffHunk(dir int) {
+	positions := flattenDiff(p.diffPaneParsedDiff)
+	for i := p.diffSelection.Cursor + dir; i >= 0 && i < len(positions); i += dir {
+		if positions[i].Line == -1 {
+			p.diffSelection.Cursor = i
+			p.ensureDiffCursorVisible()
+			return
+		}
+	}
+}
+
+// ensureDiffCursorVisible scrolls the diff pane so the line cursor is on screen.
+func (p *Plugin) ensureDiffCursorVisible() {
+	row := p.diffSelection.Cursor
+	if p.diffPaneViewMode == DiffViewSideBySide {
+		row = sideBySideRow(p.diffPaneParsedDiff, row)
+	}
+	// Blank separators between hunks and wrapped lines take extra rows,
+	// so keep a small margin at the bottom.
+	visible := p.height - 10
+	if visible < 1 {
+		visible = 1
+	}
+	if row < p.diffPaneScroll {
+		p.diffPaneScroll = row
+	} else if row >= p.diffPaneScroll+visible {
+		p.diffPaneScroll = row - visible + 1
+	}
+}
+
+// toggleDiffRange starts or cancels a line range anchored at the cursor.
+func (p *Plugin) toggleDiffRange() {
+	if p.diffSelection.Selecting {
+		p.diffSelection.Selecting = false
+		return
+	}
+	p.diffSelection.Anchor = p.diffSelection.Cursor
+	p.diffSelection.Selecting = true
+}
+
+// sideBySideRow converts a flat diff line index into the row index used by
+// RenderSideBySide, where paired remove/add lines share one row.
+func sideBySideRow(diff *ParsedDiff, flat int) int {
+	if diff == nil {
+		return flat
+	}
+	row, idx := 0, 0
+	for _, hunk := range diff.Hunks {
+		if idx == flat {
+			return row
+		}
+		row++
+		idx++
+		for _, pair := range groupLinesForSideBySide(hunk.Lines) {
+			if (pair.left != nil && idx+pair.leftIdx == flat) || (pair.right != nil && idx+pair.rightIdx == flat) {
+				return row
+			}
+			row++
+		}
+		idx += len(hunk.Lines)
+	}
+	return row
+}
@@ -0,0 +1,311 @@
+package gitstatus
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+const partialTestDiff = `diff --git a/file.txt b/file.txt
+--- a/file.txt
++++ b/file.txt
+@@ -1,4 +1,4 @@
+ one
+-two
++TWO
+ three
+ four
+@@ -8,3 +8,4 @@
+ eight
+ nine
++nine-and-a-half
+ ten
+`
+
+func TestBuildPartialPatch_WholeHunk(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(partialTestDiff)
+	sel := DiffSelection{Cursor: 0} // First hunk header
+	patch := BuildPartialPatch(diff, "file.txt", sel.lineSelector(diff), false)
+
+	want := "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n" +
+		"@@ -1,4 +1,4 @@\n one\n-two\n+TWO\n three\n four\n"
+	if patch != want {
+		t.Errorf("patch =\n%s\nwant\n%s", patch, want)
+	}
+}
+
+func TestBuildPartialPatch_LineRangeForward(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(partialTestDiff)
+	// Select only "+TWO" (flat index 3: header, one, -two, +TWO)
+	sel := DiffSelection{Cursor: 3, Anchor: 3, Selecting: true}
+	patch := BuildPartialPatch(diff, "file.txt", sel.lineSelector(diff), false)
+
+	// Unselected removal becomes context
+	if !strings.Contains(patch, "@@ -1,4 +1,5 @@\n one\n two\n+TWO\n three\n four\n") {
+		t.Errorf("unexpected patch:\n%s", patch)
+	}
+	if strings.Contains(patch, "nine-and-a-half") {
+		t.Errorf("patch should not include second hunk:\n%s", patch)
+	}
+}
+
+func TestBuildPartialPatch_LineRangeReverse(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(partialTestDiff)
+	// Select only "-two" (flat index 2)
+	sel := DiffSelection{Cursor: 2, Anchor: 2, Selecting: true}
+	patch := BuildPartialPatch(diff, "file.txt", sel.lineSelector(diff), true)
+
+	// Unselected addition stays as context, since it exists in the target
+	if !strings.Contains(patch, "@@ -1,5 +1,4 @@\n one\n-two\n TWO\n three\n four\n") {
+		t.Errorf("unexpected patch:\n%s", patch)
+	}
+}
+
+func TestBuildPartialPatch_AdjustsLaterHunkStart(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(partialTestDiff)
+	// Select "-two" and everything in the second hunk
+	selected := func(hunk, line int) bool {
+		return hunk == 1 || (hunk == 0 && line == 1)
+	}
+	patch := BuildPartialPatch(diff, "file.txt", selected, false)
+
+	// Dropping the first hunk's "+TWO" shifts the second hunk up by one line
+	if !strings.Contains(patch, "@@ -8,3 +7,4 @@") {
+		t.Errorf("expected adjusted second hunk header:\n%s", patch)
+	}
+}
+
+func TestBuildPartialPatch_NoChangesSelected(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(partialTestDiff)
+	// Range covering only context line "one"
+	sel := DiffSelection{Cursor: 1, Anchor: 1, Selecting: true}
+	if patch := BuildPartialPatch(diff, "file.txt", sel.lineSelector(diff), false); patch != "" {
+		t.Errorf("expected empty patch, got:\n%s", patch)
+	}
+}
+
+func TestDiffSelectionBounds(t *testing.T) {
+	sel := DiffSelection{Cursor: 2, Anchor: 5, Selecting: true}
+	if start, end := sel.Bounds(); start != 2 || end != 5 {
+		t.Errorf("Bounds() = %d,%d, want 2,5", start, end)
+	}
+	sel.Selecting = false
+	if sel.Contains(3) {
+		t.Error("Contains(3) should be false without a range")
+	}
+	if !sel.Contains(2) {
+		t.Error("Contains(cursor) should be true")
+	}
+}
+
+func TestSideBySideRow(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(partialTestDiff)
+	// Hunk 0 rows: header(0), one(1), -two/+TWO(2), three(3), four(4)
+	tests := []struct {
+		flat, row int
+	}{
+		{0, 0},
+		{2, 2}, // -two
+		{3, 2}, // +TWO shares the row with -two
+		{4, 3}, // three
+		{6, 5}, // second hunk header
+	}
+	for _, tt := range tests {
+		if got := sideBySideRow(diff, tt.flat); got != tt.row {
+			t.Errorf("sideBySideRow(%d) = %d, want %d", tt.flat, got, tt.row)
+		}
+	}
+}
+
+// initPartialRepo creates a repo with a committed file and returns its dir.
+func initPartialRepo(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, strings.TrimSpace(string(out)))
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "file.txt")
+	run("commit", "-m", "initial")
+	return dir
+}
+
+func TestPartialStageUnstageDiscard(t *testing.T) {
+	original := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	dir := initPartialRepo(t, original)
+	modified := "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nnine-and-a-half\nten\n"
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(modified), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewFileTree(dir)
+
+	// Stage the second hunk only
+	raw, err := GetDiff(dir, "file.txt", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, _ := ParseUnifiedDiff(raw)
+	if len(diff.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(diff.Hunks))
+	}
+	second := DiffSelection{Cursor: len(diff.Hunks[0].Lines) + 1}
+	if err := tree.StagePatch(BuildPartialPatch(diff, "file.txt", second.lineSelector(diff), false)); err != nil {
+		t.Fatalf("StagePatch: %v", err)
+	}
+	staged, _ := GetDiff(dir, "file.txt", true)
+	if !strings.Contains(staged, "+nine-and-a-half") || strings.Contains(staged, "+TWO") {
+		t.Fatalf("unexpected staged diff:\n%s", staged)
+	}
+
+	// Unstage it again
+	raw, _ = GetDiff(dir, "file.txt", true)
+	diff, _ = ParseUnifiedDiff(raw)
+	first := DiffSelection{}
+	if err := tree.UnstagePatch(BuildPartialPatch(diff, "file.txt", first.lineSelector(diff), true)); err != nil {
+		t.Fatalf("UnstagePatch: %v", err)
+	}
+	if staged, _ := GetDiff(dir, "file.txt", true); strings.TrimSpace(staged) != "" {
+		t.Fatalf("expected empty staged diff, got:\n%s", staged)
+	}
+
+	// Discard only the "+TWO" line; "-two" stays removed
+	raw, _ = GetDiff(dir, "file.txt", false)
+	diff, _ = ParseUnifiedDiff(raw)
+	plus := DiffSelection{Cursor: 3, Anchor: 3, Selecting: true}
+	if err := DiscardPatch(dir, BuildPartialPatch(diff, "file.txt", plus.lineSelector(diff), true)); err != nil {
+		t.Fatalf("DiscardPatch: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+	want := "one\nthree\nfour\nfive\nsix\nseven\neight\nnine\nnine-and-a-half\nten\n"
+	if string(data) != want {
+		t.Errorf("worktree after discard =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestPartialStage_NoTrailingNewline(t *testing.T) {
+	dir := initPartialRepo(t, "one\ntwo\nthree")
+	tree := NewFileTree(dir)
+	path := filepath.Join(dir, "file.txt")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parse := func(staged bool) *ParsedDiff {
+		t.Helper()
+		raw, err := GetDiff(dir, "file.txt", staged)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, _ := ParseUnifiedDiff(raw)
+		return diff
+	}
+	only := func(diff *ParsedDiff, content string) func(int, int) bool {
+		return func(h, l int) bool { return diff.Hunks[h].Lines[l].Content == content }
+	}
+	index := func() string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", dir, "show", ":file.txt").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+	unstageAll := func() {
+		t.Helper()
+		diff := parse(true)
+		if err := tree.UnstagePatch(BuildPartialPatch(diff, "file.txt", DiffSelection{}.lineSelector(diff), true)); err != nil {
+			t.Fatalf("UnstagePatch: %v", err)
+		}
+		if got := index(); got != "one\ntwo\nthree" {
+			t.Fatalf("index after unstage = %q", got)
+		}
+	}
+
+	// The unchanged last line keeps its marker as context
+	write("one\nTWO\nthree")
+	diff := parse(false)
+	if last := diff.Hunks[0].Lines[len(diff.Hunks[0].Lines)-1]; last.Content != "three" || !last.NoNewline {
+		t.Fatalf("last line = %+v, want three without newline", last)
+	}
+	if err := tree.StagePatch(BuildPartialPatch(diff, "file.txt", only(diff, "TWO"), false)); err != nil {
+		t.Fatalf("StagePatch: %v", err)
+	}
+	if got := index(); got != "one\ntwo\nTWO\nthree" {
+		t.Errorf("index = %q", got)
+	}
+	unstageAll()
+
+	// Adding after the last line: the old last line stays as context but
+	// gains a newline in the staged file
+	write("one\nTWO\nthree\nfour")
+	diff = parse(false)
+	if err := tree.StagePatch(BuildPartialPatch(diff, "file.txt", only(diff, "four"), false)); err != nil {
+		t.Fatalf("StagePatch: %v", err)
+	}
+	if got := index(); got != "one\ntwo\nthree\nfour" {
+		t.Errorf("index = %q", got)
+	}
+	unstageAll()
+
+	// Discarding the new last line leaves the line before it ended by a newline
+	diff = parse(false)
+	if err := DiscardPatch(dir, BuildPartialPatch(diff, "file.txt", only(diff, "four"), true)); err != nil {
+		t.Fatalf("DiscardPatch: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "one\nTWO\nthree\n" {
+		t.Errorf("worktree after discard = %q", data)
+	}
+}
+
+func TestStageSelectionWholeFileAndWrongSide(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "file.txt")
+	tree := NewFileTree(dir)
+	if err := tree.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	p := &Plugin{ctx: &plugin.Context{}, tree: tree, repoRoot: dir, hasRepo: true, selectedDiffFile: "file.txt"}
+	entries := tree.AllEntries()
+	if len(entries) != 1 || !entries[0].Staged {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	// s on a staged entry says why nothing happens
+	p.diffPaneParsedDiff = &ParsedDiff{}
+	cmd := p.stageSelection(partialStage)
+	if cmd == nil {
+		t.Fatal("no feedback for s on a staged file")
+	}
+	if toast, ok := cmd().(app.ToastMsg); !ok || !strings.Contains(toast.Message, "staged") {
+		t.Fatalf("msg = %+v", toast)
+	}
+
+	// Nothing to select (e.g. a binary diff): u unstages the whole file
+	p.diffPaneParsedDiff = &ParsedDiff{Binary: true}
+	if cmd := p.stageSelection(partialUnstage); cmd == nil {
+		t.Fatal("no command for u on a staged file")
+	}
+	if staged := gitRun(t, dir, "diff", "--cached", "--name-only"); staged != "" {
+		t.Fatalf("still staged: %q", staged)
+	}
+}
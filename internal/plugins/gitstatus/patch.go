@@ -0,0 +1,325 @@
+package gitstatus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MailPatch is one patch of a patch file. Plain diffs have a single patch
+// without commit metadata.
+type MailPatch struct {
+	Subject string
+	Author  string
+	Diff    string
+}
+
+// PatchCheck is the dry-run result of applying a patch file.
+type PatchCheck struct {
+	Clean     bool     // Applies without conflicts
+	Conflicts []string // Files a three-way merge would leave conflicted
+	Failed    []string // Files the patch can't be applied to
+	Output    string   // git apply output
+}
+
+// Applies reports whether the patch applies, possibly with conflicts.
+func (c PatchCheck) Applies() bool {
+	return c.Clean || len(c.Conflicts) > 0
+}
+
+// PatchFile is a patch or mailbox file with its dry-run result.
+type PatchFile struct {
+	Path    string
+	Mailbox bool // format-patch output, applied with git am
+	Patches []MailPatch
+	Check   PatchCheck
+}
+
+// patchFromLineRe matches the line that starts each message of a
+// format-patch mailbox.
+var patchFromLineRe = regexp.MustCompile(`^From [0-9a-f]{40} `)
+
+// patchSubjectPrefixRe matches the [PATCH n/m] prefix of a subject.
+var patchSubjectPrefixRe = regexp.MustCompile(`^\[[^\]]*PATCH[^\]]*\]\s*`)
+
+// patchCountRe matches a -N commit count spec.
+var patchCountRe = regexp.MustCompile(`^-\d+$`)
+
+// patchNameRe matches runs of characters not kept in a mailbox file name.
+var patchNameRe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// patchRevArgs turns a commit spec into format-patch revision arguments: a
+// range as is, -N for the last N commits, otherwise the single commit.
+func patchRevArgs(spec string) []string {
+	if strings.Contains(spec, "..") || patchCountRe.MatchString(spec) {
+		return []string{spec}
+	}
+	return []string{"-1", spec}
+}
+
+// mailboxName returns the file name of a mailbox exported for spec.
+func mailboxName(spec string) string {
+	name := patchNameRe.ReplaceAllString(spec, "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = "patches"
+	}
+	return name + ".mbox"
+}
+
+// resolvePatchPath makes path absolute, relative to workDir.
+func resolvePatchPath(workDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(workDir, path)
+}
+
+// ExportPatches writes the commits of spec with git format-patch: one file
+// per commit in outDir, or a single mailbox when mbox is set. It returns the
+// paths written.
+func ExportPatches(workDir, spec, outDir string, mbox bool) ([]string, error) {
+	outDir = resolvePatchPath(workDir, outDir)
+	if mbox {
+		args := append([]string{"format-patch", "--stdout"}, patchRevArgs(spec)...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		if len(output) == 0 {
+			return nil, fmt.Errorf("no commits in %s", spec)
+		}
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(outDir, mailboxName(spec))
+		if err := os.WriteFile(path, output, 0644); err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	args := append([]string{"format-patch", "-o", outDir}, patchRevArgs(spec)...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			paths = append(paths, resolvePatchPath(workDir, line))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no commits in %s", spec)
+	}
+	return paths, nil
+}
+
+// ExportStagedPatch writes the staged changes to staged.patch in outDir.
+func ExportStagedPatch(workDir, outDir string) (string, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--binary")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	if len(output) == 0 {
+		return "", fmt.Errorf("nothing staged")
+	}
+	outDir = resolvePatchPath(workDir, outDir)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(outDir, "staged.patch")
+	if err := os.WriteFile(path, output, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// LoadPatchFile reads a patch or mailbox file and checks whether it applies
+// to the working tree.
+func LoadPatchFile(workDir, path string) (*PatchFile, error) {
+	path = resolvePatchPath(workDir, path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	patches, mailbox := parsePatchFile(string(data))
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("%s contains no patches", filepath.Base(path))
+	}
+	return &PatchFile{
+		Path:    path,
+		Mailbox: mailbox,
+		Patches: patches,
+		Check:   CheckPatch(workDir, path),
+	}, nil
+}
+
+// parsePatchFile splits a format-patch mailbox into its patches. Any other
+// content is treated as a single plain diff.
+func parsePatchFile(content string) ([]MailPatch, bool) {
+	if !patchFromLineRe.MatchString(content) {
+		if !strings.Contains(content, "\n@@ ") && !strings.HasPrefix(content, "@@ ") {
+			return nil, false
+		}
+		return []MailPatch{{Diff: strings.TrimSpace(content)}}, false
+	}
+
+	var patches []MailPatch
+	var current *MailPatch
+	var diff strings.Builder
+	inHeaders, inDiff, lastHeader := false, false, ""
+	flush := func() {
+		if current != nil {
+			current.Diff = strings.TrimSpace(diff.String())
+			patches = append(patches, *current)
+		}
+		diff.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case patchFromLineRe.MatchString(line):
+			flush()
+			current = &MailPatch{}
+			inHeaders, inDiff, lastHeader = true, false, ""
+		case current == nil:
+		case inHeaders:
+			switch {
+			case line == "":
+				inHeaders = false
+			case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+				// Folded header line
+				if lastHeader == "Subject" {
+					current.Subject += " " + strings.TrimSpace(line)
+				}
+			default:
+				key, value, _ := strings.Cut(line, ": ")
+				lastHeader = key
+				switch key {
+				case "From":
+					current.Author = value
+				case "Subject":
+					current.Subject = value
+				}
+			}
+		case line == "-- ":
+			// Signature after the last hunk
+			inDiff = false
+		case strings.HasPrefix(line, "diff --git "):
+			inDiff = true
+			diff.WriteString(line + "\n")
+		case inDiff:
+			diff.WriteString(line + "\n")
+		}
+	}
+	flush()
+
+	for i := range patches {
+		patches[i].Subject = patchSubjectPrefixRe.ReplaceAllString(patches[i].Subject, "")
+	}
+	return patches, true
+}
+
+// patchErrorFileRe extracts the file from git apply errors such as
+// "error: patch failed: a.txt:1" and "error: a.txt: patch does not apply".
+var patchErrorFileRe = regexp.MustCompile(`^error: (?:patch failed: (.+):\d+|(.+?): (?:patch does not apply|does not exist in index|already exists in (?:index|working directory)|No such file or directory))$`)
+
+// patchConflictFileRe extracts the file from "Applied patch to 'a.txt' with
+// conflicts."
+var patchConflictFileRe = regexp.MustCompile(`^Applied patch to '(.+)' with conflicts\.$`)
+
+// CheckPatch dry-runs git apply. A patch that doesn't apply cleanly is
+// checked again with a three-way merge to tell conflicts from failures.
+func CheckPatch(workDir, path string) PatchCheck {
+	cmd := exec.Command("git", "apply", "--check", path)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return PatchCheck{Clean: true}
+	}
+
+	check := PatchCheck{Output: strings.TrimSpace(string(output))}
+	cmd = exec.Command("git", "apply", "--check", "--3way", path)
+	cmd.Dir = workDir
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		check.Output = strings.TrimSpace(string(output))
+		check.Failed = patchErrorFiles(check.Output)
+		return check
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if m := patchConflictFileRe.FindStringSubmatch(line); m != nil && !slices.Contains(check.Conflicts, m[1]) {
+			check.Conflicts = append(check.Conflicts, m[1])
+		}
+	}
+	check.Clean = len(check.Conflicts) == 0
+	return check
+}
+
+// patchErrorFiles lists the files named in git apply errors.
+func patchErrorFiles(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		m := patchErrorFileRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		file := m[1]
+		if file == "" {
+			file = m[2]
+		}
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// ApplyPatchFile applies a patch file. Mailboxes are applied with git am,
+// creating their commits; plain diffs are applied to the index and working
+// tree. Both fall back to a three-way merge, so conflicts are left for the
+// resolver. An am that stops without conflicts is aborted, leaving the
+// branch as it was.
+func ApplyPatchFile(workDir string, pf *PatchFile) error {
+	args := []string{"apply", "--3way", pf.Path}
+	if pf.Mailbox {
+		args = []string{"am", "--3way", pf.Path}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	if pf.Mailbox && len(GetConflictedFiles(workDir)) == 0 && AmInProgress(workDir) {
+		_ = AbortOperation(workDir, "am")
+	}
+	return &RemoteError{Output: strings.TrimSpace(string(output)), Err: err}
+}
+
+// AmInProgress reports whether git am is stopped on a patch.
+func AmInProgress(workDir string) bool {
+	return gitPathExists(workDir, "rebase-apply/applying")
+}
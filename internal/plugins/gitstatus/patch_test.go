@@ -0,0 +1,196 @@
+package gitstatus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportPatches(t *testing.T) {
+	dir, _ := initBranchRepo(t)
+
+	paths, err := ExportPatches(dir, "agent~2..agent", "out", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || !strings.HasSuffix(paths[0], "0001-add-a.patch") || filepath.Dir(paths[0]) != filepath.Join(dir, "out") {
+		t.Fatalf("paths = %v", paths)
+	}
+
+	paths, err = ExportPatches(dir, "agent", "out", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	patches, mailbox := parsePatchFile(string(data))
+	if filepath.Base(paths[0]) != "agent.mbox" || !mailbox || len(patches) != 1 || patches[0].Subject != "add b" {
+		t.Errorf("single commit mailbox %s = %+v", paths[0], patches)
+	}
+
+	if _, err := ExportPatches(dir, "agent..agent", "out", false); err == nil {
+		t.Error("expected error for empty range")
+	}
+}
+
+func TestExportStagedPatch(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	if _, err := ExportStagedPatch(dir, "out"); err == nil {
+		t.Error("expected error with nothing staged")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "file.txt")
+	path, err := ExportStagedPatch(dir, "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "-one") || !strings.Contains(string(data), "+two") {
+		t.Errorf("staged patch:\n%s", data)
+	}
+}
+
+func TestParsePatchFile(t *testing.T) {
+	mbox := strings.Join([]string{
+		"From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001",
+		"From: Test <test@example.com>",
+		"Date: Mon, 1 Jan 2024 00:00:00 +0000",
+		"Subject: [PATCH 1/2] A subject that was long enough to be",
+		" folded",
+		"",
+		"Body text.",
+		"---",
+		" a.txt | 1 +",
+		"",
+		"diff --git a/a.txt b/a.txt",
+		"new file mode 100644",
+		"--- /dev/null",
+		"+++ b/a.txt",
+		"@@ -0,0 +1 @@",
+		"+a",
+		"-- ",
+		"2.39.5",
+		"",
+		"From 2222222222222222222222222222222222222222 Mon Sep 17 00:00:00 2001",
+		"From: Other <other@example.com>",
+		"Subject: [PATCH 2/2] add b",
+		"",
+		"diff --git a/b.txt b/b.txt",
+		"@@ -1 +1 @@",
+		"-b",
+		"+B",
+		"-- ",
+		"2.39.5",
+	}, "\n")
+
+	patches, mailbox := parsePatchFile(mbox)
+	if !mailbox || len(patches) != 2 {
+		t.Fatalf("got %d patches, mailbox %v", len(patches), mailbox)
+	}
+	if p := patches[0]; p.Subject != "A subject that was long enough to be folded" || p.Author != "Test <test@example.com>" ||
+		!strings.HasPrefix(p.Diff, "diff --git a/a.txt") || !strings.HasSuffix(p.Diff, "+a") {
+		t.Errorf("first patch = %+v", p)
+	}
+	if p := patches[1]; p.Subject != "add b" || len(ParseMultiFileDiff(p.Diff).Files) != 1 {
+		t.Errorf("second patch = %+v", p)
+	}
+
+	plain := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n+b\n"
+	patches, mailbox = parsePatchFile(plain)
+	if mailbox || len(patches) != 1 || patches[0].Subject != "" {
+		t.Errorf("plain diff = %+v, mailbox %v", patches, mailbox)
+	}
+
+	if patches, _ := parsePatchFile("just some notes\n"); len(patches) != 0 {
+		t.Errorf("non-patch parsed as %+v", patches)
+	}
+}
+
+// writePatch writes a patch changing file.txt from one line to another.
+func writePatch(t *testing.T, dir, from, to string) string {
+	t.Helper()
+	patch := "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1 +1 @@\n-" + from + "\n+" + to + "\n"
+	path := filepath.Join(t.TempDir(), "change.patch")
+	if err := os.WriteFile(path, []byte(patch), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckPatch(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+
+	if check := CheckPatch(dir, writePatch(t, dir, "one", "two")); !check.Clean || !check.Applies() {
+		t.Errorf("clean patch = %+v", check)
+	}
+
+	check := CheckPatch(dir, writePatch(t, dir, "other", "two"))
+	if check.Clean || check.Applies() || len(check.Failed) != 1 || check.Failed[0] != "file.txt" {
+		t.Errorf("failing patch = %+v", check)
+	}
+}
+
+func TestApplyPatchFileMailbox(t *testing.T) {
+	dir, trunk := initBranchRepo(t)
+	paths, err := ExportPatches(dir, trunk+"..agent", t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pf, err := LoadPatchFile(dir, paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pf.Mailbox || len(pf.Patches) != 2 || !pf.Check.Clean {
+		t.Fatalf("loaded %+v", pf)
+	}
+	if err := ApplyPatchFile(dir, pf); err != nil {
+		t.Fatal(err)
+	}
+	if log := gitRun(t, dir, "log", "--format=%s", "-2"); log != "add b\nadd a" {
+		t.Errorf("log after am = %q", log)
+	}
+}
+
+func TestApplyPatchFileConflict(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	gitRun(t, dir, "checkout", "-q", "-b", "agent")
+	commitFile(t, dir, "file.txt", "agent\n", "agent change")
+	paths, err := ExportPatches(dir, "-1", t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "checkout", "-q", "-")
+	commitFile(t, dir, "file.txt", "trunk\n", "trunk change")
+
+	pf, err := LoadPatchFile(dir, paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pf.Check.Conflicts) != 1 || pf.Check.Conflicts[0] != "file.txt" || !pf.Check.Applies() {
+		t.Fatalf("check = %+v", pf.Check)
+	}
+
+	if err := ApplyPatchFile(dir, pf); err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if op := ConflictOperation(dir); op != "am" {
+		t.Errorf("operation = %q, want am", op)
+	}
+	if files := GetConflictedFiles(dir); len(files) != 1 || files[0] != "file.txt" {
+		t.Errorf("conflicted files = %v", files)
+	}
+
+	if err := AbortOperation(dir, "am"); err != nil {
+		t.Fatal(err)
+	}
+	if AmInProgress(dir) || gitRun(t, dir, "log", "--format=%s", "-1") != "trunk change" {
+		t.Error("abort did not restore the branch")
+	}
+}
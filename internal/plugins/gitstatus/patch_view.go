@@ -0,0 +1,510 @@
+package gitstatus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	patchSpecInputID = "patch-spec"
+	patchDirInputID  = "patch-dir"
+	patchStagedID    = "patch-staged"
+	patchMailboxID   = "patch-mailbox"
+	patchExportID    = "patch-export"
+
+	patchDefaultDir = "patches"
+)
+
+// PatchExportedMsg reports the files written by a patch export.
+type PatchExportedMsg struct {
+	Paths []string
+	Err   error
+}
+
+// PatchLoadedMsg carries a patch file opened for applying.
+type PatchLoadedMsg struct {
+	Epoch uint64
+	Path  string
+	File  *PatchFile
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m PatchLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// PatchAppliedMsg reports the outcome of applying a patch file.
+type PatchAppliedMsg struct {
+	Epoch   uint64
+	Mailbox bool
+	Count   int // Patches in the file
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m PatchAppliedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openPatchExport opens the export prompt for the selected commit, or for
+// the staged changes when the cursor is on a file and something is staged.
+func (p *Plugin) openPatchExport() {
+	spec := ""
+	if c := p.selectedCommit(); c != nil {
+		spec = c.ShortHash
+	}
+
+	p.patchSpecInput = textinput.New()
+	p.patchSpecInput.Placeholder = "commit, range (main..HEAD) or -N"
+	p.patchSpecInput.Prompt = ""
+	p.patchSpecInput.CharLimit = 200
+	p.patchSpecInput.Width = 40
+	p.patchSpecInput.SetValue(spec)
+	p.patchSpecInput.Focus()
+
+	p.patchDirInput = textinput.New()
+	p.patchDirInput.Placeholder = "directory, relative to the repository"
+	p.patchDirInput.Prompt = ""
+	p.patchDirInput.CharLimit = 500
+	p.patchDirInput.Width = 40
+	p.patchDirInput.SetValue(patchDefaultDir)
+
+	p.patchStaged = spec == "" && p.tree.HasStagedFiles()
+	p.patchMailbox = false
+	p.patchError = ""
+	p.patchReturnMode = p.viewMode
+	p.clearPatchExportModal()
+	p.viewMode = ViewModePatchExport
+}
+
+// updatePatchExport handles key events in the export prompt.
+func (p *Plugin) updatePatchExport(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensurePatchExportModal()
+	if p.patchExportModal == nil {
+		return p, nil
+	}
+	switch m.String() {
+	case "alt+s":
+		p.patchStaged = !p.patchStaged
+		return p, nil
+	case "alt+m":
+		p.patchMailbox = !p.patchMailbox
+		return p, nil
+	}
+
+	action, cmd := p.patchExportModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handlePatchExportAction(action))
+}
+
+// handlePatchExportAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handlePatchExportAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closePatchExport()
+	case patchStagedID:
+		// Clicks on the checkboxes; keyboard toggles are handled by the section
+		p.patchStaged = !p.patchStaged
+	case patchMailboxID:
+		p.patchMailbox = !p.patchMailbox
+	case patchExportID, patchSpecInputID, patchDirInputID:
+		spec := strings.TrimSpace(p.patchSpecInput.Value())
+		dir := strings.TrimSpace(p.patchDirInput.Value())
+		if dir == "" {
+			dir = patchDefaultDir
+		}
+		if !p.patchStaged && spec == "" {
+			p.patchError = "Enter the commits to export"
+			return nil
+		}
+		staged, mbox := p.patchStaged, p.patchMailbox
+		p.closePatchExport()
+		return p.doExportPatches(spec, dir, staged, mbox)
+	}
+	return nil
+}
+
+// doExportPatches writes the patches in the background.
+func (p *Plugin) doExportPatches(spec, dir string, staged, mbox bool) tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		if staged {
+			path, err := ExportStagedPatch(workDir, dir)
+			return PatchExportedMsg{Paths: []string{path}, Err: err}
+		}
+		paths, err := ExportPatches(workDir, spec, dir, mbox)
+		return PatchExportedMsg{Paths: paths, Err: err}
+	}
+}
+
+// handlePatchExported reports where the patches were written.
+func (p *Plugin) handlePatchExported(m PatchExportedMsg) tea.Cmd {
+	if m.Err != nil {
+		p.showErrorModal("Export Failed", m.Err)
+		return nil
+	}
+	var toast string
+	if len(m.Paths) == 1 {
+		toast = "Wrote " + p.repoRelPath(m.Paths[0])
+	} else {
+		toast = fmt.Sprintf("Exported %d patches to %s", len(m.Paths), p.repoRelPath(filepath.Dir(m.Paths[0])))
+	}
+	return func() tea.Msg {
+		return app.ToastMsg{Message: toast, Duration: 3 * time.Second}
+	}
+}
+
+// repoRelPath shortens a path inside the repository for display.
+func (p *Plugin) repoRelPath(path string) string {
+	if rel, err := filepath.Rel(p.repoRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func (p *Plugin) closePatchExport() {
+	p.viewMode = p.patchReturnMode
+	p.patchError = ""
+	p.clearPatchExportModal()
+}
+
+func (p *Plugin) clearPatchExportModal() {
+	p.patchExportModal = nil
+	p.patchExportModalWidth = 0
+}
+
+// ensurePatchExportModal builds/rebuilds the export prompt.
+func (p *Plugin) ensurePatchExportModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.patchExportModal != nil && p.patchExportModalWidth == modalW {
+		return
+	}
+	p.patchExportModalWidth = modalW
+
+	p.patchExportModal = modal.New("Export Patches",
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(patchExportID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.InputWithLabel(patchSpecInputID, "Commits:", &p.patchSpecInput, modal.WithSubmitAction(patchExportID))).
+		AddSection(modal.InputWithLabel(patchDirInputID, "Output:", &p.patchDirInput, modal.WithSubmitAction(patchExportID))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Checkbox(patchStagedID, "Staged changes (alt+s)", &p.patchStaged)).
+		AddSection(modal.Checkbox(patchMailboxID, "Single mailbox file (alt+m)", &p.patchMailbox)).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			text := "One .patch file per commit, for git am"
+			switch {
+			case p.patchStaged:
+				text = "The staged diff as staged.patch, for git apply"
+			case p.patchMailbox:
+				text = "All commits in one .mbox file, for git am"
+			}
+			content := styles.Muted.Render(ansi.Truncate(text, contentWidth, "…"))
+			if p.patchError != "" {
+				content += "\n\n" + styles.StatusDeleted.Render(p.patchError)
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Export ", patchExportID),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// renderPatchExport renders the export prompt over the status view.
+func (p *Plugin) renderPatchExport() string {
+	background := p.renderThreePaneView()
+
+	p.ensurePatchExportModal()
+	if p.patchExportModal == nil {
+		return background
+	}
+
+	modalContent := p.patchExportModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// handleApplyPatchRequest opens the apply preview for a file picked in
+// another plugin. The path is relative to the project directory.
+func (p *Plugin) handleApplyPatchRequest(m app.ApplyPatchMsg) tea.Cmd {
+	path := m.Path
+	if p.ctx != nil && p.ctx.WorkDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(p.ctx.WorkDir, path)
+	}
+	p.viewMode = ViewModeStatus
+	return p.openPatchApply(path)
+}
+
+// openPatchApply opens the preview of a patch file and dry-runs it.
+func (p *Plugin) openPatchApply(path string) tea.Cmd {
+	p.resetPatchApply()
+	p.patchPath = path
+	p.viewMode = ViewModePatchApply
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		pf, err := LoadPatchFile(workDir, path)
+		return PatchLoadedMsg{Epoch: epoch, Path: path, File: pf, Err: err}
+	}
+}
+
+func (p *Plugin) resetPatchApply() {
+	p.patchFile = nil
+	p.patchPath = ""
+	p.patchCursor = 0
+	p.patchListScroll = 0
+	p.patchApplying = false
+	p.clearPatchDiff()
+}
+
+func (p *Plugin) clearPatchDiff() {
+	p.patchDiff = nil
+	p.patchDiffScroll = 0
+}
+
+// handlePatchLoaded shows the loaded patch file.
+func (p *Plugin) handlePatchLoaded(m PatchLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModePatchApply || m.Path != p.patchPath {
+		return nil
+	}
+	if m.Err != nil {
+		p.closePatchApply()
+		p.showErrorModal("Open Patch Failed", m.Err)
+		return nil
+	}
+	p.patchFile = m.File
+	p.selectPatch(0)
+	return nil
+}
+
+// selectPatch selects a patch of the file and parses its diff.
+func (p *Plugin) selectPatch(idx int) {
+	if p.patchFile == nil || len(p.patchFile.Patches) == 0 {
+		return
+	}
+	p.patchCursor = max(min(idx, len(p.patchFile.Patches)-1), 0)
+	p.clearPatchDiff()
+	p.patchDiff = ParseMultiFileDiff(p.patchFile.Patches[p.patchCursor].Diff)
+}
+
+func (p *Plugin) closePatchApply() {
+	p.viewMode = ViewModeStatus
+	p.resetPatchApply()
+}
+
+// doApplyPatch applies the previewed patch file.
+func (p *Plugin) doApplyPatch() tea.Cmd {
+	pf := p.patchFile
+	if pf == nil || p.patchApplying {
+		return nil
+	}
+	if !pf.Check.Applies() {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "Patch does not apply", Duration: 2 * time.Second, IsError: true}
+		}
+	}
+	p.patchApplying = true
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		before := currentHead(workDir)
+		err := ApplyPatchFile(workDir, pf)
+		if pf.Mailbox {
+			logOperation(workDir, "am "+filepath.Base(pf.Path), before)
+		}
+		return PatchAppliedMsg{Epoch: epoch, Mailbox: pf.Mailbox, Count: len(pf.Patches), Err: err}
+	}
+}
+
+// handlePatchApplied reports the outcome. Conflicts open the resolver, where
+// a stopped git am can be continued or aborted.
+func (p *Plugin) handlePatchApplied(m PatchAppliedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) {
+		return nil
+	}
+	refresh := tea.Batch(p.refresh(), p.loadRecentCommits())
+	if p.viewMode == ViewModePatchApply {
+		p.closePatchApply()
+	}
+	if m.Err != nil {
+		if files := GetConflictedFiles(p.repoRoot); len(files) > 0 {
+			return tea.Batch(refresh, p.openConflictResolver(""), func() tea.Msg {
+				return app.ToastMsg{Message: fmt.Sprintf("Patch has conflicts in %d file(s)", len(files)), Duration: 3 * time.Second}
+			})
+		}
+		p.showErrorModal("Apply Failed", m.Err)
+		return refresh
+	}
+
+	toast := "Patch applied to working tree"
+	if m.Mailbox {
+		toast = fmt.Sprintf("Applied %d commit(s)", m.Count)
+	}
+	return tea.Batch(refresh, func() tea.Msg {
+		return app.ToastMsg{Message: toast, Duration: 2 * time.Second}
+	})
+}
+
+// updatePatchApply handles key events in the apply preview.
+func (p *Plugin) updatePatchApply(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	page := max((p.height-4)/2, 1)
+	switch m.String() {
+	case "esc", "q":
+		p.closePatchApply()
+	case "j", "down":
+		p.selectPatch(p.patchCursor + 1)
+	case "k", "up":
+		p.selectPatch(p.patchCursor - 1)
+	case "g":
+		p.selectPatch(0)
+	case "G":
+		if p.patchFile != nil {
+			p.selectPatch(len(p.patchFile.Patches) - 1)
+		}
+	case "ctrl+d", "J":
+		p.patchDiffScroll += page
+	case "ctrl+u", "K":
+		p.patchDiffScroll = max(p.patchDiffScroll-page, 0)
+	case "v":
+		if p.diffViewMode == DiffViewUnified {
+			p.diffViewMode = DiffViewSideBySide
+		} else {
+			p.diffViewMode = DiffViewUnified
+		}
+	case "a", "enter":
+		return p, p.doApplyPatch()
+	}
+	return p, nil
+}
+
+// renderPatchApply renders the apply preview: the file's patches on the
+// left, the selected patch's diff on the right.
+func (p *Plugin) renderPatchApply() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+	listWidth := p.fileHistoryListWidth(contentWidth)
+	diffWidth := max(contentWidth-listWidth-3, 10)
+	bodyHeight := max(paneHeight-4, 1) // header, check, separator, footer
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionPatchDiff, 2+listWidth+3, 4, diffWidth, bodyHeight, nil)
+	p.mouseHandler.HitMap.AddRect(regionPatchList, 2, 4, listWidth, bodyHeight, nil)
+
+	header := styles.Title.Render("Apply Patch ") + styles.Code.Render(p.repoRelPath(p.patchPath))
+	if pf := p.patchFile; pf != nil {
+		if pf.Mailbox {
+			header += styles.Muted.Render(fmt.Sprintf(" · %d commit(s) via git am", len(pf.Patches)))
+		} else {
+			header += styles.Muted.Render(" · diff via git apply")
+		}
+	}
+	lines := []string{
+		ansi.Truncate(header, contentWidth, "…"),
+		ansi.Truncate(p.renderPatchCheck(), contentWidth, "…"),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+
+	var listLines, diffLines []string
+	if p.patchFile == nil {
+		listLines = []string{styles.Muted.Render("Checking patch...")}
+	} else {
+		listLines = p.renderPatchList(listWidth, bodyHeight)
+		diffLines = p.renderPatchDiff(diffWidth, bodyHeight)
+	}
+	sep := styles.Muted.Render(" │ ")
+	for i := range bodyHeight {
+		left := ""
+		if i < len(listLines) {
+			left = listLines[i]
+		}
+		right := ""
+		if i < len(diffLines) {
+			right = diffLines[i]
+		}
+		lines = append(lines, padToWidth(left, listWidth)+sep+right)
+	}
+
+	footer := "a apply  j/k patch  ctrl+d/u scroll diff  v split  esc close"
+	lines = append(lines, styles.Muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
+
+// renderPatchCheck renders the dry-run result.
+func (p *Plugin) renderPatchCheck() string {
+	pf := p.patchFile
+	switch {
+	case p.patchApplying:
+		return styles.Muted.Render("Applying...")
+	case pf == nil:
+		return ""
+	case len(pf.Check.Conflicts) > 0:
+		return styles.StatusModified.Render("! Applies with conflicts in " + strings.Join(pf.Check.Conflicts, ", "))
+	case len(pf.Check.Failed) > 0:
+		return styles.StatusDeleted.Render("✗ Does not apply: " + strings.Join(pf.Check.Failed, ", "))
+	case !pf.Check.Clean:
+		return styles.StatusDeleted.Render("✗ Does not apply: " + firstLine(pf.Check.Output))
+	}
+	return styles.StatusStaged.Render("✓ Applies cleanly")
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// renderPatchList renders the visible rows of the patch column.
+func (p *Plugin) renderPatchList(width, height int) []string {
+	patches := p.patchFile.Patches
+	if p.patchCursor < p.patchListScroll {
+		p.patchListScroll = p.patchCursor
+	}
+	if p.patchCursor >= p.patchListScroll+height {
+		p.patchListScroll = p.patchCursor - height + 1
+	}
+	p.patchListScroll = min(p.patchListScroll, max(len(patches)-height, 0))
+
+	var lines []string
+	end := min(p.patchListScroll+height, len(patches))
+	for i := p.patchListScroll; i < end; i++ {
+		mp := patches[i]
+		subject := mp.Subject
+		if subject == "" {
+			subject = filepath.Base(p.patchPath)
+		}
+		prefix := fmt.Sprintf("%d ", i+1)
+		text := ansi.Truncate(subject, max(width-len(prefix), 5), "…")
+		if i == p.patchCursor {
+			lines = append(lines, styles.ListItemSelected.Render(padToWidth(prefix+text, width)))
+			continue
+		}
+		lines = append(lines, styles.Muted.Render(prefix)+text)
+	}
+	return lines
+}
+
+// renderPatchDiff renders the selected patch's author and diff.
+func (p *Plugin) renderPatchDiff(width, height int) []string {
+	mp := p.patchFile.Patches[p.patchCursor]
+	var lines []string
+	if mp.Author != "" {
+		lines = append(lines, styles.Muted.Render(ansi.Truncate(mp.Author, width, "…")))
+		height = max(height-1, 1)
+	}
+	if p.patchDiff == nil || len(p.patchDiff.Files) == 0 {
+		return append(lines, styles.Muted.Render("No changes"))
+	}
+	return append(lines, p.renderCommitDiffLines(p.patchDiff, &p.patchDiffScroll, width, height)...)
+}
@@ -1,6 +1,7 @@
 package gitstatus
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/marcus/sidecar/internal/app"
@@ -31,16 +33,40 @@ const (
 type ViewMode int
 
 const (
-	ViewModeStatus          ViewMode = iota // Current file list (three-pane layout)
-	ViewModeDiff                            // Full-screen diff view
-	ViewModeCommit                          // Commit message editor
-	ViewModePushMenu                        // Push options popup menu
-	ViewModePullMenu                        // Pull options popup menu
-	ViewModeConfirmDiscard                  // Confirm discard changes modal
-	ViewModeBranchPicker                    // Branch selection modal
-	ViewModeConfirmStashPop                 // Confirm stash pop modal
-	ViewModePullConflict                    // Pull conflict resolution modal
-	ViewModeError                           // Generic error modal for git operation failures
+	ViewModeStatus           ViewMode = iota // Current file list (three-pane layout)
+	ViewModeDiff                             // Full-screen diff view
+	ViewModeCommit                           // Commit message editor
+	ViewModePushMenu                         // Push options popup menu
+	ViewModePullMenu                         // Pull options popup menu
+	ViewModeConfirmDiscard                   // Confirm discard changes modal
+	ViewModeBranchPicker                     // Branch selection modal
+	ViewModeConfirmStashPop                  // Confirm stash pop modal
+	ViewModePullConflict                     // Pull conflict resolution modal
+	ViewModeError                            // Generic error modal for git operation failures
+	ViewModeRebase                           // Interactive rebase editor modal
+	ViewModeCommitAction                     // Cherry-pick/revert/reset prompt and confirmation
+	ViewModeReflog                           // Reflog browser modal
+	ViewModeConfirmUndo                      // Confirm undo/restore modal
+	ViewModeConflicts                        // Full-screen merge conflict resolver
+	ViewModeTagCreate                        // Create tag prompt
+	ViewModeTags                             // Tag list modal
+	ViewModeConfirmDeleteTag                 // Confirm tag delete modal
+	ViewModeRelease                          // Full-screen changes since the last tag
+	ViewModeFileHistory                      // Full-screen file history or pickaxe results
+	ViewModePickaxe                          // Pickaxe search prompt
+	ViewModeBisectMenu                       // Mark a commit good/bad/skip for bisect
+	ViewModeBisect                           // Full-screen bisect progress
+	ViewModeBisectRun                        // Bisect test command prompt
+	ViewModeStashes                          // Stash list modal
+	ViewModeStash                            // Full-screen stash files and diffs
+	ViewModeStashBranch                      // Branch from stash prompt
+	ViewModeConfirmStashDrop                 // Confirm stash drop modal
+	ViewModeCommitTrailers                   // Trailer helper over the commit modal
+	ViewModeComparePrompt                    // Pick two refs to compare
+	ViewModeCompare                          // Full-screen branch comparison
+	ViewModePatchExport                      // Export commits or staged changes as patches
+	ViewModePatchApply                       // Full-screen patch apply preview
+	ViewModeSubmodules                       // Submodule list with update and sync actions
 )
 
 // FocusPane represents which pane is active in the three-pane view.
@@ -78,13 +104,19 @@ type Plugin struct {
 	loadingMoreCommits   bool      // Prevents duplicate load-more requests
 	moreCommitsAvailable bool      // Whether more commits are available to load
 
+	// Signature status of commits, checked only for the rows shown
+	signatures        map[string]SignatureStatus // By commit hash
+	signaturesPending map[string]bool            // Hashes being checked
+
 	// Inline diff state (for three-pane view)
-	selectedDiffFile    string       // File being previewed in diff pane
-	forceNextDiffReload bool         // Bypass dedup on next autoLoadDiff call
-	diffPaneScroll      int          // Vertical scroll for inline diff
-	diffPaneHorizScroll int          // Horizontal scroll for inline diff
-	diffPaneParsedDiff  *ParsedDiff  // Parsed diff for inline view
-	diffPaneViewMode    DiffViewMode // Unified or side-by-side for inline diff
+	selectedDiffFile    string        // File being previewed in diff pane
+	forceNextDiffReload bool          // Bypass dedup on next autoLoadDiff call
+	diffPaneScroll      int           // Vertical scroll for inline diff
+	diffPaneHorizScroll int           // Horizontal scroll for inline diff
+	diffPaneParsedDiff  *ParsedDiff   // Parsed diff for inline view
+	diffPaneViewMode    DiffViewMode  // Unified or side-by-side for inline diff
+	diffSelection       DiffSelection // Line cursor/range for partial staging in inline diff
+	partialTarget       *FileEntry    // File being partially staged; cursor restored to it after refresh
 
 	// Commit preview state (for three-pane view when on commit)
 	previewCommit       *Commit // Commit being previewed in right pane
@@ -131,6 +163,195 @@ type Plugin struct {
 	pullConflictModal *modal.Modal
 	pullConflictWidth int
 
+	// Interactive rebase editor state
+	rebasePlan       *RebasePlan
+	rebaseOriginal   []*Commit // Commits as loaded, to detect an unchanged plan
+	rebaseBaseShort  string
+	rebaseCursor     int
+	rebaseStage      rebaseStage
+	rebaseMessage    textarea.Model // Message editor for reword
+	rebaseError      string         // Validation error shown under the plan
+	rebaseModal      *modal.Modal
+	rebaseModalWidth int
+
+	// Cherry-pick/revert/reset state
+	commitActionKind       commitActionKind
+	commitActionInputStage bool            // True while the cherry-pick prompt is shown
+	commitActionInput      textinput.Model // Cherry-pick commit or range
+	commitActionCommits    []*Commit       // Commits applied, reverted or removed by reset
+	commitActionTarget     *Commit         // Reset target
+	commitActionError      string
+	commitActionModal      *modal.Modal
+	commitActionModalWidth int
+	cherryPickSpec         string // Last cherry-pick input, reused as the default
+	resetModeIdx           int    // Index into resetModes
+
+	// Reflog browser and undo state
+	reflogEntries    []*ReflogEntry
+	reflogLoaded     bool
+	reflogCursor     int
+	reflogModal      *modal.Modal
+	reflogModalWidth int
+	undoPlan         *UndoPlan
+	undoReturnMode   ViewMode // Mode to return to when the undo modal is cancelled
+	undoModal        *modal.Modal
+	undoModalWidth   int
+
+	// Conflict resolver state
+	conflictOp      string          // Operation waiting on the resolution, "" for a plain merge result
+	conflictFiles   []string        // Files conflicted since the resolver opened
+	conflictDone    map[string]bool // Files resolved and staged
+	conflictLoaded  bool
+	conflictFileIdx int
+	conflictFile    *ConflictFile // Current file, nil while loading
+	conflictHunkIdx int
+	conflictScroll  int
+	conflictError   string
+	conflictEditing bool // Current file was opened in the file browser's editor
+
+	// Tag and release state
+	tagTarget           string // Commit the new tag points at
+	tagTargetLabel      string
+	tagNameInput        textinput.Model
+	tagMessageInput     textinput.Model
+	tagError            string
+	tagCreateReturnMode ViewMode // Mode to return to when the tag prompt closes
+	tagCreateModal      *modal.Modal
+	tagCreateModalWidth int
+	tags                []*Tag
+	tagsLoaded          bool
+	tagCursor           int
+	tagsModal           *modal.Modal
+	tagsModalWidth      int
+	tagDelete           *Tag
+	tagDeleteHasRemote  bool
+	tagDeleteModal      *modal.Modal
+	tagDeleteModalWidth int
+	release             *ReleaseChanges // nil while loading
+	releaseScroll       int
+	releaseReturnMode   ViewMode
+
+	// File history and pickaxe search state
+	fileHistoryPath       string       // File being followed, or the pickaxe scope
+	fileHistoryPickaxe    *PickaxeOpts // nil for a file history
+	fileHistoryEntries    []*FileHistoryEntry
+	fileHistoryLoaded     bool
+	fileHistoryCursor     int
+	fileHistoryListScroll int
+	fileHistoryDiff       *MultiFileDiff
+	fileHistoryDiffRaw    string
+	fileHistoryDiffHash   string // Commit the loaded diff belongs to
+	fileHistoryDiffScroll int
+	fileHistoryReturnMode ViewMode
+	pickaxeInput          textinput.Model
+	pickaxeRegex          bool
+	pickaxePath           string
+	pickaxeError          string
+	pickaxeReturnMode     ViewMode
+	pickaxeModal          *modal.Modal
+	pickaxeModalWidth     int
+
+	// Bisect state
+	bisect               *BisectState // nil until loaded
+	bisectGraph          []GraphLine  // Graph of bisect.Candidates
+	bisectTarget         *Commit      // Commit the bisect prompt marks
+	bisectMenuModal      *modal.Modal
+	bisectMenuModalWidth int
+	bisectCursor         int
+	bisectListScroll     int
+	bisectDiff           *MultiFileDiff
+	bisectDiffRaw        string
+	bisectDiffHash       string // Commit the loaded diff belongs to
+	bisectDiffScroll     int
+	bisectOutput         string // Output of the last bisect command
+	bisectRunning        bool
+	bisectRunCancel      context.CancelFunc // Stops the test command run, nil unless one runs
+	bisectRunStart       time.Time
+	bisectReturnMode     ViewMode
+	bisectCommand        string // Last test command, prefilled in the run prompt
+	bisectCommandInput   textinput.Model
+	bisectRunError       string
+	bisectRunModal       *modal.Modal
+	bisectRunModalWidth  int
+
+	// Stash list and detail state
+	stashes               []*Stash
+	stashesLoaded         bool
+	stashCursor           int
+	stashesModal          *modal.Modal
+	stashesModalWidth     int
+	stashDetail           *Stash // Stash open in the detail view
+	stashFiles            []CommitFile
+	stashFilesLoaded      bool
+	stashFileCursor       int
+	stashFileScroll       int
+	stashDiff             *MultiFileDiff
+	stashDiffRaw          string
+	stashDiffPath         string // File the loaded diff belongs to
+	stashDiffScroll       int
+	stashBranchTarget     *Stash
+	stashBranchInput      textinput.Model
+	stashBranchError      string
+	stashBranchReturnMode ViewMode
+	stashBranchModal      *modal.Modal
+	stashBranchModalWidth int
+	stashDrop             *Stash // Stash being confirmed for drop
+	stashDropModal        *modal.Modal
+	stashDropModalWidth   int
+
+	// Branch comparison state
+	compareBase             string
+	compareRef              string
+	compare                 *BranchComparison
+	compareTab              compareTab
+	compareCursor           int
+	compareListScroll       int
+	compareDirect           bool // Diff the two trees instead of from the merge base
+	compareDiff             *MultiFileDiff
+	compareDiffRaw          string
+	compareDiffLoaded       bool
+	compareCommitDiff       *MultiFileDiff
+	compareCommitDiffRaw    string
+	compareCommitDiffHash   string // Commit the loaded commit diff belongs to
+	compareDiffScroll       int
+	compareRangeDiff        []*RangeDiffEntry
+	compareRangeDiffLoaded  bool
+	compareRangeDiffErr     string
+	compareBaseInput        textinput.Model
+	compareRefInput         textinput.Model
+	compareError            string
+	compareReturnMode       ViewMode
+	comparePromptModal      *modal.Modal
+	comparePromptModalWidth int
+
+	// Patch export and apply state
+	patchSpecInput        textinput.Model
+	patchDirInput         textinput.Model
+	patchStaged           bool // Export the staged diff instead of commits
+	patchMailbox          bool // Export commits as one mailbox file
+	patchError            string
+	patchReturnMode       ViewMode
+	patchExportModal      *modal.Modal
+	patchExportModalWidth int
+	patchPath             string
+	patchFile             *PatchFile
+	patchCursor           int
+	patchListScroll       int
+	patchDiff             *MultiFileDiff
+	patchDiffScroll       int
+	patchApplying         bool
+
+	// Submodule state
+	submoduleStack       []string        // Superproject roots above the repo being shown
+	submoduleDrift       *SubmoduleDrift // Drift of the submodule selected in the sidebar
+	submoduleDriftErr    string
+	submodules           []*Submodule
+	submodulesLoaded     bool
+	submoduleCursor      int
+	submoduleBusy        string // Action running from the submodule list
+	submodulesModal      *modal.Modal
+	submodulesModalWidth int
+
 	// View dimensions
 	width  int
 	height int
@@ -143,12 +364,26 @@ type Plugin struct {
 	commitMessage         textarea.Model
 	commitError           string
 	commitInProgress      bool
-	commitAmend           bool // true when amending last commit
-	commitButtonFocus     bool // true when button is focused instead of textarea
-	commitButtonHover     bool // true when mouse is hovering over button
+	commitAmend           bool           // true when amending last commit
+	commitSignOff         bool           // Add a Signed-off-by trailer to this commit
+	commitSigning         *SigningConfig // nil until checked for this modal
+	commitButtonFocus     bool           // true when button is focused instead of textarea
+	commitButtonHover     bool           // true when mouse is hovering over button
 	commitModal           *modal.Modal
 	commitModalWidthCache int
 
+	// Commit message assistant state
+	commitLint              bool   // Lint against Conventional Commits
+	commitLintOverride      string // Message committed despite lint problems on the next attempt
+	commitTemplate          string // commit.template the message was prefilled with
+	commitGenerating        bool
+	commitGenerateCancel    context.CancelFunc
+	commitNotice            string // Result of the last message draft
+	commitTrailers          []commitTrailer
+	commitTrailerCursor     int
+	commitTrailerModal      *modal.Modal
+	commitTrailerModalWidth int
+
 	// Mouse support
 	mouseHandler *mouse.Handler
 
@@ -159,15 +394,18 @@ type Plugin struct {
 	errorTitle       string // e.g. "Push Failed", "Fetch Failed"
 	errorDetail      string // full git command output
 	errorOfferPull   bool   // true when push was rejected due to remote ahead
+	errorSequencerOp string // "cherry-pick" or "revert" when offering continue/abort
 
 	// Discard confirm state
 	discardFile       *FileEntry   // File being confirmed for discard
+	discardPatch      string       // Partial patch to discard instead of the whole file
 	discardReturnMode ViewMode     // Mode to return to when modal closes
 	discardModal      *modal.Modal // Modal instance for discard confirmation
 
 	// Stash pop confirm state
-	stashPopItem  *Stash       // Stash being confirmed for pop
-	stashPopModal *modal.Modal // Modal instance for stash pop confirmation
+	stashPopItem       *Stash       // Stash being confirmed for pop
+	stashPopModal      *modal.Modal // Modal instance for stash pop confirmation
+	stashPopReturnMode ViewMode
 
 	// Syntax highlighting
 	syntaxHighlighter     *SyntaxHighlighter // Cached highlighter for current file
@@ -288,6 +526,9 @@ func (p *Plugin) Init(ctx *plugin.Context) error {
 		p.sidebarWidth = saved
 	}
 	p.showCommitGraph = state.GetGitGraphEnabled()
+	if ctx.Config != nil {
+		p.commitLint = ctx.Config.Plugins.GitStatus.ConventionalCommits
+	}
 	p.diffWrapEnabled = state.GetLineWrapEnabled()
 
 	// Resolve git repo root (works from any subdirectory).
@@ -320,6 +561,9 @@ func (p *Plugin) Start() tea.Cmd {
 
 // Stop cleans up plugin resources.
 func (p *Plugin) Stop() {
+	if p.bisectRunCancel != nil {
+		p.bisectRunCancel()
+	}
 	if p.watcher != nil {
 		p.watcher.Stop()
 	}
@@ -360,6 +604,54 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			return p.updateBranchPicker(msg)
 		case ViewModeError:
 			return p.updateErrorModal(msg)
+		case ViewModeRebase:
+			return p.updateRebase(msg)
+		case ViewModeCommitAction:
+			return p.updateCommitAction(msg)
+		case ViewModeReflog:
+			return p.updateReflog(msg)
+		case ViewModeConfirmUndo:
+			return p.updateConfirmUndo(msg)
+		case ViewModeConflicts:
+			return p.updateConflicts(msg)
+		case ViewModeTagCreate:
+			return p.updateTagCreate(msg)
+		case ViewModeTags:
+			return p.updateTags(msg)
+		case ViewModeConfirmDeleteTag:
+			return p.updateConfirmDeleteTag(msg)
+		case ViewModeRelease:
+			return p.updateRelease(msg)
+		case ViewModeFileHistory:
+			return p.updateFileHistory(msg)
+		case ViewModePickaxe:
+			return p.updatePickaxe(msg)
+		case ViewModeBisectMenu:
+			return p.updateBisectMenu(msg)
+		case ViewModeBisect:
+			return p.updateBisect(msg)
+		case ViewModeBisectRun:
+			return p.updateBisectRun(msg)
+		case ViewModeStashes:
+			return p.updateStashes(msg)
+		case ViewModeStash:
+			return p.updateStash(msg)
+		case ViewModeStashBranch:
+			return p.updateStashBranch(msg)
+		case ViewModeConfirmStashDrop:
+			return p.updateConfirmStashDrop(msg)
+		case ViewModeCommitTrailers:
+			return p.updateCommitTrailers(msg)
+		case ViewModeComparePrompt:
+			return p.updateComparePrompt(msg)
+		case ViewModeCompare:
+			return p.updateCompare(msg)
+		case ViewModePatchExport:
+			return p.updatePatchExport(msg)
+		case ViewModePatchApply:
+			return p.updatePatchApply(msg)
+		case ViewModeSubmodules:
+			return p.updateSubmodules(msg)
 		}
 
 	case tea.MouseMsg:
@@ -388,6 +680,54 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			return p.handleStashPopMouse(msg)
 		case ViewModeError:
 			return p.handleErrorModalMouse(msg)
+		case ViewModeRebase:
+			return p.handleRebaseMouse(msg)
+		case ViewModeCommitAction:
+			return p.handleCommitActionMouse(msg)
+		case ViewModeReflog:
+			return p.handleReflogMouse(msg)
+		case ViewModeConfirmUndo:
+			return p.handleConfirmUndoMouse(msg)
+		case ViewModeConflicts:
+			return p.handleConflictsMouse(msg)
+		case ViewModeTagCreate:
+			return p.handleTagCreateMouse(msg)
+		case ViewModeTags:
+			return p.handleTagsMouse(msg)
+		case ViewModeConfirmDeleteTag:
+			return p.handleConfirmDeleteTagMouse(msg)
+		case ViewModeRelease:
+			return p.handleReleaseMouse(msg)
+		case ViewModeFileHistory:
+			return p.handleFileHistoryMouse(msg)
+		case ViewModePickaxe:
+			return p.handlePickaxeMouse(msg)
+		case ViewModeBisectMenu:
+			return p.handleBisectMenuMouse(msg)
+		case ViewModeBisect:
+			return p.handleBisectMouse(msg)
+		case ViewModeBisectRun:
+			return p.handleBisectRunMouse(msg)
+		case ViewModeStashes:
+			return p.handleStashesMouse(msg)
+		case ViewModeStash:
+			return p.handleStashMouse(msg)
+		case ViewModeStashBranch:
+			return p.handleStashBranchMouse(msg)
+		case ViewModeConfirmStashDrop:
+			return p.handleStashDropMouse(msg)
+		case ViewModeCommitTrailers:
+			return p.handleCommitTrailersMouse(msg)
+		case ViewModeComparePrompt:
+			return p.handleComparePromptMouse(msg)
+		case ViewModeCompare:
+			return p.handleCompareMouse(msg)
+		case ViewModePatchExport:
+			return p.handlePatchExportMouse(msg)
+		case ViewModePatchApply:
+			return p.handlePatchApplyMouse(msg)
+		case ViewModeSubmodules:
+			return p.handleSubmodulesMouse(msg)
 		}
 
 	case app.RefreshMsg:
@@ -402,6 +742,10 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 		// Refresh data when navigating to this plugin
 		p.lastRefresh = time.Now()
+		if p.viewMode == ViewModeConflicts && p.conflictEditing {
+			// Back from editing a conflicted file in the file browser
+			return p, tea.Batch(p.refresh(), p.loadRecentCommits(), p.reloadEditedConflict())
+		}
 		return p, tea.Batch(p.refresh(), p.loadRecentCommits())
 
 	case WatchStartedMsg:
@@ -411,6 +755,16 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 			return p, nil
 		}
+		// A watcher started before switching into or out of a submodule
+		if msg.Dir != p.repoRoot {
+			if msg.Watcher != nil {
+				msg.Watcher.Stop()
+			}
+			return p, nil
+		}
+		if p.watcher != nil {
+			p.watcher.Stop()
+		}
 		p.watcher = msg.Watcher
 		return p, p.listenForWatchEvents()
 
@@ -429,6 +783,8 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if p.inNoRepoMode() {
 			return p, nil
 		}
+		// Keep the partially staged file selected so its diff refreshes in place
+		p.restorePartialTarget()
 		// Clamp cursor to valid range if files changed
 		maxCursor := p.totalSelectableItems() - 1
 		if maxCursor < 0 {
@@ -461,6 +817,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.commitMessage.Reset()
 		p.commitInProgress = false
 		p.commitAmend = false
+		p.commitSignOff = false
 		p.commitError = ""
 		return p, p.refresh()
 
@@ -477,6 +834,10 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		// Only update if this is still the selected file
 		if msg.File == p.selectedDiffFile {
 			p.diffPaneParsedDiff = msg.Parsed
+			// Clamp line cursor (diff may have shrunk after partial staging)
+			if lines := countParsedDiffLines(p.diffPaneParsedDiff); p.diffSelection.Cursor >= lines {
+				p.diffSelection.Cursor = max(lines-1, 0)
+			}
 			// Clamp scroll to new content length (diff may have shrunk after stage/unstage)
 			if p.diffPaneParsedDiff != nil {
 				lines := countParsedDiffLines(p.diffPaneParsedDiff)
@@ -521,6 +882,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.recentCommits = mergeRecentCommits(p.recentCommits, msg.Commits)
 		p.pushStatus = msg.PushStatus
 		PopulatePushStatus(p.recentCommits, p.pushStatus)
+		p.applySignatures()
 		// Recompute graph for new commits
 		if p.showCommitGraph && len(p.recentCommits) > 0 {
 			p.commitGraphLines = ComputeGraphForCommits(p.recentCommits)
@@ -541,7 +903,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if p.cursor > maxCursor {
 			p.cursor = maxCursor
 		}
-		return p, p.ensureCommitListFilled()
+		return p, tea.Batch(p.ensureCommitListFilled(), p.loadBisectState(), p.loadVisibleSignatures())
 
 	case MoreCommitsLoadedMsg:
 		if plugin.IsStale(p.ctx, msg) {
@@ -553,12 +915,13 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				p.moreCommitsAvailable = false
 			}
 			p.recentCommits = append(p.recentCommits, msg.Commits...)
+			p.applySignatures()
 			// Recompute entire graph when commits are added
 			if p.showCommitGraph {
 				commits := p.activeCommits()
 				p.commitGraphLines = ComputeGraphForCommits(commits)
 			}
-			return p, p.ensureCommitListFilled()
+			return p, tea.Batch(p.ensureCommitListFilled(), p.loadVisibleSignatures())
 		}
 		p.moreCommitsAvailable = false
 		return p, nil
@@ -570,6 +933,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if msg.Commits != nil {
 			p.filteredCommits = msg.Commits
 			p.pushStatus = msg.PushStatus
+			p.applySignatures()
 			// Recompute graph for filtered commits
 			if p.showCommitGraph && len(p.filteredCommits) > 0 {
 				p.commitGraphLines = ComputeGraphForCommits(p.filteredCommits)
@@ -582,9 +946,13 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				p.cursor = len(entries)
 				p.commitScrollOff = 0
 			}
+			return p, p.loadVisibleSignatures()
 		}
 		return p, nil
 
+	case SignaturesLoadedMsg:
+		return p, p.handleSignaturesLoaded(msg)
+
 	case CommitStatsLoadedMsg:
 		if plugin.IsStale(p.ctx, msg) {
 			return p, nil // Ignore stale message from previous project
@@ -630,6 +998,22 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 		return p, nil
 
+	case PartialStageDoneMsg:
+		if msg.Err != nil {
+			p.partialTarget = nil
+			title := "Stage Failed"
+			switch msg.Op {
+			case partialUnstage:
+				title = "Unstage Failed"
+			case partialDiscard:
+				title = "Discard Failed"
+			}
+			p.showErrorModal(title, msg.Err)
+			return p, nil
+		}
+		p.diffSelection.Selecting = false
+		return p, tea.Batch(p.refresh(), p.loadRecentCommits())
+
 	case PushSuccessMsg:
 		p.pushInProgress = false
 		p.pushError = ""
@@ -655,6 +1039,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 
 	case StashResultMsg:
 		if msg.Err != nil {
+			if msg.Operation == "branch" && p.viewMode == ViewModeStashBranch {
+				// Keep the prompt open so the name can be corrected
+				p.stashBranchError = msg.Err.Error()
+				return p, nil
+			}
 			// Show error toast
 			toastMsg := "Stash failed: " + msg.Err.Error()
 			return p, func() tea.Msg {
@@ -668,12 +1057,19 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			toastMsg = "Stashed changes"
 		case "apply":
 			toastMsg = "Stash applied"
+		case "apply-file":
+			toastMsg = "Applied " + msg.Detail + " from " + msg.Ref
+		case "branch":
+			toastMsg = "Created branch " + msg.Detail + " from " + msg.Ref
+		case "drop":
+			toastMsg = "Dropped " + msg.Ref
 		default:
 			toastMsg = "Stash popped"
 		}
 		return p, tea.Batch(
 			p.refresh(),
 			p.loadRecentCommits(),
+			p.handleStashResult(msg),
 			func() tea.Msg {
 				return app.ToastMsg{Message: toastMsg, Duration: 2 * time.Second}
 			},
@@ -747,6 +1143,148 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.showErrorModal("Stash Failed", msg.Err)
 		return p, nil
 
+	case RebasePlanLoadedMsg:
+		return p, p.handleRebasePlanLoaded(msg)
+
+	case RebaseDoneMsg:
+		return p, p.handleRebaseDone(msg)
+
+	case CommitActionPreviewMsg:
+		return p, p.handleCommitActionPreview(msg)
+
+	case CommitActionDoneMsg:
+		return p, p.handleCommitActionDone(msg)
+
+	case ReflogLoadedMsg:
+		return p, p.handleReflogLoaded(msg)
+
+	case UndoPlanMsg:
+		return p, p.handleUndoPlan(msg)
+
+	case UndoDoneMsg:
+		return p, p.handleUndoDone(msg)
+
+	case OpenConflictResolverMsg:
+		if p.inNoRepoMode() {
+			return p, nil
+		}
+		return p, p.handleOpenConflictResolver(msg)
+
+	case ConflictsLoadedMsg:
+		return p, p.handleConflictsLoaded(msg)
+
+	case ConflictFileLoadedMsg:
+		return p, p.handleConflictFileLoaded(msg)
+
+	case ConflictSavedMsg:
+		return p, p.handleConflictSaved(msg)
+
+	case ConflictOpDoneMsg:
+		return p, p.handleConflictOpDone(msg)
+
+	case TagsLoadedMsg:
+		return p, p.handleTagsLoaded(msg)
+
+	case TagDoneMsg:
+		return p, p.handleTagDone(msg)
+
+	case ReleaseLoadedMsg:
+		return p, p.handleReleaseLoaded(msg)
+
+	case app.OpenFileHistoryMsg:
+		if p.inNoRepoMode() {
+			return p, nil
+		}
+		return p, p.handleOpenFileHistory(msg)
+
+	case FileHistoryLoadedMsg:
+		return p, p.handleFileHistoryLoaded(msg)
+
+	case HistoryDiffLoadedMsg:
+		return p, p.handleHistoryDiffLoaded(msg)
+
+	case BisectStateLoadedMsg:
+		return p, p.handleBisectStateLoaded(msg)
+
+	case BisectDoneMsg:
+		return p, p.handleBisectDone(msg)
+
+	case BisectRunTickMsg:
+		if p.bisectRunCancel != nil {
+			return p, bisectRunTick()
+		}
+		return p, nil
+
+	case BisectDiffLoadedMsg:
+		return p, p.handleBisectDiffLoaded(msg)
+
+	case StashesLoadedMsg:
+		return p, p.handleStashesLoaded(msg)
+
+	case StashFilesLoadedMsg:
+		return p, p.handleStashFilesLoaded(msg)
+
+	case StashDiffLoadedMsg:
+		return p, p.handleStashDiffLoaded(msg)
+
+	case SigningCheckedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.commitSigning = &msg.Config
+		return p, nil
+
+	case CommitMessageGeneratedMsg:
+		return p, p.handleCommitMessageGenerated(msg)
+
+	case CompareLoadedMsg:
+		return p, p.handleCompareLoaded(msg)
+
+	case CompareDiffLoadedMsg:
+		return p, p.handleCompareDiffLoaded(msg)
+
+	case CompareCommitDiffLoadedMsg:
+		return p, p.handleCompareCommitDiffLoaded(msg)
+
+	case RangeDiffLoadedMsg:
+		return p, p.handleRangeDiffLoaded(msg)
+
+	case PatchExportedMsg:
+		return p, p.handlePatchExported(msg)
+
+	case app.ApplyPatchMsg:
+		if p.inNoRepoMode() {
+			return p, nil
+		}
+		return p, p.handleApplyPatchRequest(msg)
+
+	case PatchLoadedMsg:
+		return p, p.handlePatchLoaded(msg)
+
+	case PatchAppliedMsg:
+		return p, p.handlePatchApplied(msg)
+
+	case SubmoduleDriftLoadedMsg:
+		return p, p.handleSubmoduleDriftLoaded(msg)
+
+	case SubmodulesLoadedMsg:
+		return p, p.handleSubmodulesLoaded(msg)
+
+	case SubmoduleActionDoneMsg:
+		return p, p.handleSubmoduleActionDone(msg)
+
+	case app.OpenSubmoduleMsg:
+		if p.inNoRepoMode() {
+			return p, nil
+		}
+		return p, p.handleOpenSubmoduleRequest(msg)
+
+	case SequencerAbortedMsg:
+		if msg.Err != nil {
+			p.showErrorModal("Abort Failed", msg.Err)
+		}
+		return p, tea.Batch(p.refresh(), p.loadRecentCommits())
+
 	case PullAbortedMsg:
 		p.pullConflictFiles = nil
 		p.pullConflictType = ""
@@ -819,6 +1357,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		// Show stash pop confirmation modal
 		p.stashPopItem = msg.Stash
 		p.stashPopModal = nil // Force rebuild with new stash item
+		p.stashPopReturnMode = ViewModeStatus
 		p.viewMode = ViewModeConfirmStashPop
 		return p, nil
 
@@ -889,6 +1428,54 @@ func (p *Plugin) View(width, height int) string {
 			content = p.renderBranchPicker()
 		case ViewModeError:
 			content = p.renderErrorModal()
+		case ViewModeRebase:
+			content = p.renderRebase()
+		case ViewModeCommitAction:
+			content = p.renderCommitAction()
+		case ViewModeReflog:
+			content = p.renderReflog()
+		case ViewModeConfirmUndo:
+			content = p.renderConfirmUndo()
+		case ViewModeConflicts:
+			content = p.renderConflicts()
+		case ViewModeTagCreate:
+			content = p.renderTagCreate()
+		case ViewModeTags:
+			content = p.renderTags()
+		case ViewModeConfirmDeleteTag:
+			content = p.renderConfirmDeleteTag()
+		case ViewModeRelease:
+			content = p.renderRelease()
+		case ViewModeFileHistory:
+			content = p.renderFileHistory()
+		case ViewModePickaxe:
+			content = p.renderPickaxe()
+		case ViewModeBisectMenu:
+			content = p.renderBisectMenu()
+		case ViewModeBisect:
+			content = p.renderBisect()
+		case ViewModeBisectRun:
+			content = p.renderBisectRun()
+		case ViewModeStashes:
+			content = p.renderStashes()
+		case ViewModeStash:
+			content = p.renderStash()
+		case ViewModeStashBranch:
+			content = p.renderStashBranch()
+		case ViewModeConfirmStashDrop:
+			content = p.renderConfirmStashDrop()
+		case ViewModeCommitTrailers:
+			content = p.renderCommitTrailers()
+		case ViewModeComparePrompt:
+			content = p.renderComparePrompt()
+		case ViewModeCompare:
+			content = p.renderCompare()
+		case ViewModePatchExport:
+			content = p.renderPatchExport()
+		case ViewModePatchApply:
+			content = p.renderPatchApply()
+		case ViewModeSubmodules:
+			content = p.renderSubmodules()
 		default:
 			// Use three-pane layout for status view
 			content = p.renderThreePaneView()
@@ -941,7 +1528,18 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "stash-pop", Name: "Pop", Description: "Pop latest stash", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
 		{ID: "stash-apply", Name: "Apply", Description: "Apply latest stash", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
 		{ID: "open-in-file-browser", Name: "Browse", Description: "Open file in file browser", Category: plugin.CategoryNavigation, Context: "git-status", Priority: 4},
-		{ID: "open-in-github", Name: "GitHub", Description: "Open commit in GitHub", Category: plugin.CategoryActions, Context: "git-status", Priority: 4},
+		{ID: "open-in-github", Name: "Web", Description: "Open commit on GitHub, GitLab or Gitea", Category: plugin.CategoryActions, Context: "git-status", Priority: 4},
+		{ID: "show-reflog", Name: "Reflog", Description: "Browse HEAD history and undo operations", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "resolve-conflicts", Name: "Resolve", Description: "Resolve merge conflicts", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "show-tags", Name: "Tags", Description: "Browse, push and delete tags", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "show-release", Name: "Changes", Description: "Changes since the last tag", Category: plugin.CategoryView, Context: "git-status", Priority: 4},
+		{ID: "file-history", Name: "History", Description: "History of this file", Category: plugin.CategoryView, Context: "git-status", Priority: 3},
+		{ID: "show-bisect", Name: "Bisect", Description: "Show the bisect in progress", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "show-stashes", Name: "Stashes", Description: "Browse stashes and their files", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "compare-branches", Name: "Compare", Description: "Compare two branches or refs", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "export-patches", Name: "Patch", Description: "Export commits or staged changes as patches", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "show-submodules", Name: "Submodules", Description: "Submodule drift, update and sync", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "leave-submodule", Name: "Back", Description: "Return to the superproject", Category: plugin.CategoryNavigation, Context: "git-status", Priority: 5},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-status", Priority: 5},
 		// git-status-commits context (recent commits in sidebar)
 		{ID: "view-commit", Name: "View", Description: "View commit details", Category: plugin.CategoryView, Context: "git-status-commits", Priority: 1},
@@ -956,8 +1554,19 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "prev-match", Name: "Prev", Description: "Previous search match", Category: plugin.CategoryNavigation, Context: "git-status-commits", Priority: 4},
 		{ID: "yank-commit", Name: "Yank", Description: "Copy commit as markdown", Category: plugin.CategoryActions, Context: "git-status-commits", Priority: 3},
 		{ID: "yank-id", Name: "YankID", Description: "Copy commit ID", Category: plugin.CategoryActions, Context: "git-status-commits", Priority: 3},
-		{ID: "open-in-github", Name: "GitHub", Description: "Open commit in GitHub", Category: plugin.CategoryActions, Context: "git-status-commits", Priority: 3},
+		{ID: "open-in-github", Name: "Web", Description: "Open commit on GitHub, GitLab or Gitea", Category: plugin.CategoryActions, Context: "git-status-commits", Priority: 3},
 		{ID: "toggle-graph", Name: "Graph", Description: "Toggle commit graph display", Category: plugin.CategoryView, Context: "git-status-commits", Priority: 2},
+		{ID: "interactive-rebase", Name: "Rebase", Description: "Interactive rebase onto this commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "cherry-pick", Name: "Pick", Description: "Cherry-pick commits onto this branch", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "revert-commit", Name: "Revert", Description: "Revert this commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "reset-to-commit", Name: "Reset", Description: "Reset branch to this commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
+		{ID: "show-reflog", Name: "Reflog", Description: "Browse HEAD history and undo operations", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
+		{ID: "create-tag", Name: "Tag", Description: "Tag this commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "show-tags", Name: "Tags", Description: "Browse, push and delete tags", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
+		{ID: "show-release", Name: "Changes", Description: "Changes since the last tag", Category: plugin.CategoryView, Context: "git-status-commits", Priority: 4},
+		{ID: "pickaxe-search", Name: "Pickaxe", Description: "Find commits that add or remove a string", Category: plugin.CategorySearch, Context: "git-status-commits", Priority: 3},
+		{ID: "bisect", Name: "Bisect", Description: "Mark this commit good or bad for bisect", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
+		{ID: "export-patches", Name: "Patch", Description: "Export commits as patch files", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-status-commits", Priority: 5},
 		// git-history-search context (commit search modal)
 		{ID: "select", Name: "Select", Description: "Jump to selected match", Category: plugin.CategoryActions, Context: "git-history-search", Priority: 1},
@@ -973,10 +1582,17 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "back", Name: "Back", Description: "Return to sidebar", Category: plugin.CategoryNavigation, Context: "git-commit-preview", Priority: 1},
 		{ID: "yank-commit", Name: "Yank", Description: "Copy commit as markdown", Category: plugin.CategoryActions, Context: "git-commit-preview", Priority: 3},
 		{ID: "yank-id", Name: "YankID", Description: "Copy commit ID", Category: plugin.CategoryActions, Context: "git-commit-preview", Priority: 3},
-		{ID: "open-in-github", Name: "GitHub", Description: "Open commit in GitHub", Category: plugin.CategoryActions, Context: "git-commit-preview", Priority: 3},
+		{ID: "open-in-github", Name: "Web", Description: "Open commit on GitHub, GitLab or Gitea", Category: plugin.CategoryActions, Context: "git-commit-preview", Priority: 3},
 		{ID: "open-in-file-browser", Name: "Browse", Description: "Open file in file browser", Category: plugin.CategoryNavigation, Context: "git-commit-preview", Priority: 3},
+		{ID: "file-history", Name: "History", Description: "History of this file", Category: plugin.CategoryView, Context: "git-commit-preview", Priority: 3},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-commit-preview", Priority: 4},
 		// git-status-diff context (inline diff pane)
+		{ID: "stage-selection", Name: "Stage", Description: "Stage selected lines or hunk", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 1},
+		{ID: "unstage-selection", Name: "Unstage", Description: "Unstage selected lines or hunk", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 1},
+		{ID: "select-lines", Name: "Select", Description: "Start or cancel line selection", Category: plugin.CategoryActions, Context: "git-status-diff", Priority: 2},
+		{ID: "discard-selection", Name: "Discard", Description: "Discard selected lines or hunk", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 3},
+		{ID: "next-hunk", Name: "Next", Description: "Jump to next hunk", Category: plugin.CategoryNavigation, Context: "git-status-diff", Priority: 4},
+		{ID: "prev-hunk", Name: "Prev", Description: "Jump to previous hunk", Category: plugin.CategoryNavigation, Context: "git-status-diff", Priority: 4},
 		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff view", Category: plugin.CategoryView, Context: "git-status-diff", Priority: 2},
 		{ID: "toggle-wrap", Name: "Wrap", Description: "Toggle line wrapping", Category: plugin.CategoryView, Context: "git-status-diff", Priority: 3},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-status-diff", Priority: 3},
@@ -990,6 +1606,12 @@ func (p *Plugin) Commands() []plugin.Command {
 		// git-commit context
 		{ID: "execute-commit", Name: "Commit", Description: "Create commit with message", Category: plugin.CategoryGit, Context: "git-commit", Priority: 1},
 		{ID: "cancel", Name: "Cancel", Description: "Cancel commit", Category: plugin.CategoryActions, Context: "git-commit", Priority: 1},
+		{ID: "generate-commit-message", Name: "Draft", Description: "Draft a message from the staged diff", Category: plugin.CategoryActions, Context: "git-commit", Priority: 2},
+		{ID: "add-trailer", Name: "Trailer", Description: "Add Co-authored-by or task reference", Category: plugin.CategoryActions, Context: "git-commit", Priority: 2},
+		{ID: "toggle-commit-lint", Name: "Lint", Description: "Toggle conventional commit linting", Category: plugin.CategoryView, Context: "git-commit", Priority: 3},
+		{ID: "toggle-signoff", Name: "Sign-off", Description: "Toggle Signed-off-by trailer for this commit", Category: plugin.CategoryActions, Context: "git-commit", Priority: 3},
+		{ID: "add-trailer", Name: "Add", Description: "Add the selected trailer", Category: plugin.CategoryActions, Context: "git-commit-trailers", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Back to the commit message", Category: plugin.CategoryNavigation, Context: "git-commit-trailers", Priority: 1},
 		// git-push-menu context
 		{ID: "push", Name: "Push", Description: "Push to remote", Category: plugin.CategoryGit, Context: "git-push-menu", Priority: 1},
 		{ID: "force-push", Name: "Force", Description: "Force push", Category: plugin.CategoryGit, Context: "git-push-menu", Priority: 1},
@@ -1002,12 +1624,125 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "pull-autostash", Name: "Autostash", Description: "Pull rebase + autostash", Category: plugin.CategoryGit, Context: "git-pull-menu", Priority: 1},
 		{ID: "cancel", Name: "Cancel", Description: "Cancel", Category: plugin.CategoryNavigation, Context: "git-pull-menu", Priority: 2},
 		// git-pull-conflict context
+		{ID: "resolve-conflicts", Name: "Resolve", Description: "Open the conflict resolver", Category: plugin.CategoryGit, Context: "git-pull-conflict", Priority: 1},
 		{ID: "abort-pull", Name: "Abort", Description: "Abort merge/rebase", Category: plugin.CategoryGit, Context: "git-pull-conflict", Priority: 1},
 		{ID: "dismiss", Name: "Dismiss", Description: "Dismiss and resolve manually", Category: plugin.CategoryNavigation, Context: "git-pull-conflict", Priority: 2},
+		{ID: "continue-rebase", Name: "Continue", Description: "Continue rebase after resolving", Category: plugin.CategoryGit, Context: "git-pull-conflict", Priority: 1},
+		{ID: "skip-rebase", Name: "Skip", Description: "Skip the conflicting commit", Category: plugin.CategoryGit, Context: "git-pull-conflict", Priority: 3},
+		// git-cherry-pick-input and git-commit-action contexts
+		{ID: "preview-cherry-pick", Name: "Preview", Description: "Preview commits to cherry-pick", Category: plugin.CategoryGit, Context: "git-cherry-pick-input", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel cherry-pick", Category: plugin.CategoryNavigation, Context: "git-cherry-pick-input", Priority: 1},
+		{ID: "confirm-commit-action", Name: "Confirm", Description: "Run cherry-pick, revert or reset", Category: plugin.CategoryGit, Context: "git-commit-action", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel", Category: plugin.CategoryNavigation, Context: "git-commit-action", Priority: 1},
+		// git-rebase context (interactive rebase editor)
+		{ID: "preview-rebase", Name: "Preview", Description: "Preview rebase result", Category: plugin.CategoryGit, Context: "git-rebase", Priority: 1},
+		{ID: "rebase-pick", Name: "Pick", Description: "Keep commit", Category: plugin.CategoryActions, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-reword", Name: "Reword", Description: "Edit commit message", Category: plugin.CategoryActions, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-squash", Name: "Squash", Description: "Meld into previous commit", Category: plugin.CategoryActions, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-fixup", Name: "Fixup", Description: "Meld and discard message", Category: plugin.CategoryActions, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-drop", Name: "Drop", Description: "Remove commit", Category: plugin.CategoryActions, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-move-down", Name: "Down", Description: "Move commit down (earlier)", Category: plugin.CategoryNavigation, Context: "git-rebase", Priority: 3},
+		{ID: "rebase-move-up", Name: "Up", Description: "Move commit up (later)", Category: plugin.CategoryNavigation, Context: "git-rebase", Priority: 3},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel rebase", Category: plugin.CategoryNavigation, Context: "git-rebase", Priority: 1},
+		// git-rebase-reword context
+		{ID: "save-reword", Name: "Save", Description: "Save commit message", Category: plugin.CategoryGit, Context: "git-rebase-reword", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Discard message changes", Category: plugin.CategoryNavigation, Context: "git-rebase-reword", Priority: 1},
+		// git-reflog context (reflog browser)
+		{ID: "undo-last-operation", Name: "Undo", Description: "Undo the last operation", Category: plugin.CategoryGit, Context: "git-reflog", Priority: 1},
+		{ID: "restore-reflog-entry", Name: "Restore", Description: "Move HEAD back to this entry", Category: plugin.CategoryGit, Context: "git-reflog", Priority: 1},
+		{ID: "cancel", Name: "Close", Description: "Close reflog", Category: plugin.CategoryNavigation, Context: "git-reflog", Priority: 2},
+		// git-undo context (undo confirmation modal)
+		{ID: "confirm-undo", Name: "Undo", Description: "Confirm undo", Category: plugin.CategoryGit, Context: "git-undo", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel undo", Category: plugin.CategoryNavigation, Context: "git-undo", Priority: 1},
+		// git-conflicts context (conflict resolver)
+		{ID: "take-ours", Name: "Ours", Description: "Keep our side of the conflict", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 1},
+		{ID: "take-theirs", Name: "Theirs", Description: "Keep their side of the conflict", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 1},
+		{ID: "take-both", Name: "Both", Description: "Keep ours followed by theirs", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 1},
+		{ID: "mark-resolved", Name: "Resolved", Description: "Save and stage the file", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 1},
+		{ID: "next-conflict", Name: "Next", Description: "Next conflict", Category: plugin.CategoryNavigation, Context: "git-conflicts", Priority: 2},
+		{ID: "prev-conflict", Name: "Prev", Description: "Previous conflict", Category: plugin.CategoryNavigation, Context: "git-conflicts", Priority: 2},
+		{ID: "edit-conflict", Name: "Edit", Description: "Edit the file in the file browser", Category: plugin.CategoryEdit, Context: "git-conflicts", Priority: 2},
+		{ID: "continue-operation", Name: "Continue", Description: "Continue the merge, rebase or cherry-pick", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 2},
+		{ID: "abort-operation", Name: "Abort", Description: "Abort the merge, rebase or cherry-pick", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close the resolver", Category: plugin.CategoryNavigation, Context: "git-conflicts", Priority: 3},
+		// git-tag-create, git-tags, git-tag-delete and git-release contexts
+		{ID: "create-tag", Name: "Create", Description: "Create the tag", Category: plugin.CategoryGit, Context: "git-tag-create", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Cancel", Category: plugin.CategoryNavigation, Context: "git-tag-create", Priority: 1},
+		{ID: "show-release", Name: "Changes", Description: "Changes since this tag", Category: plugin.CategoryView, Context: "git-tags", Priority: 1},
+		{ID: "create-tag", Name: "New", Description: "Tag HEAD", Category: plugin.CategoryGit, Context: "git-tags", Priority: 1},
+		{ID: "push-tag", Name: "Push", Description: "Push this tag", Category: plugin.CategoryGit, Context: "git-tags", Priority: 2},
+		{ID: "push-all-tags", Name: "Push All", Description: "Push all tags", Category: plugin.CategoryGit, Context: "git-tags", Priority: 3},
+		{ID: "delete-tag", Name: "Delete", Description: "Delete this tag", Category: plugin.CategoryGit, Context: "git-tags", Priority: 2},
+		{ID: "cancel", Name: "Close", Description: "Close tags", Category: plugin.CategoryNavigation, Context: "git-tags", Priority: 3},
+		{ID: "delete-tag", Name: "Delete", Description: "Delete the local tag", Category: plugin.CategoryGit, Context: "git-tag-delete", Priority: 1},
+		{ID: "delete-remote-tag", Name: "Everywhere", Description: "Delete locally and on the remote", Category: plugin.CategoryGit, Context: "git-tag-delete", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Keep the tag", Category: plugin.CategoryNavigation, Context: "git-tag-delete", Priority: 2},
+		{ID: "yank-release-notes", Name: "Yank", Description: "Copy as release notes", Category: plugin.CategoryActions, Context: "git-release", Priority: 1},
+		{ID: "create-tag", Name: "Tag", Description: "Tag HEAD with the next version", Category: plugin.CategoryGit, Context: "git-release", Priority: 1},
+		{ID: "cancel", Name: "Close", Description: "Close changes", Category: plugin.CategoryNavigation, Context: "git-release", Priority: 2},
+		// git-file-history and git-pickaxe contexts
+		{ID: "pickaxe-search", Name: "Pickaxe", Description: "Find commits that add or remove a string", Category: plugin.CategorySearch, Context: "git-file-history", Priority: 1},
+		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff view", Category: plugin.CategoryView, Context: "git-file-history", Priority: 2},
+		{ID: "yank-id", Name: "YankID", Description: "Copy commit ID", Category: plugin.CategoryActions, Context: "git-file-history", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close history", Category: plugin.CategoryNavigation, Context: "git-file-history", Priority: 2},
+		{ID: "pickaxe-search", Name: "Search", Description: "Run the search", Category: plugin.CategorySearch, Context: "git-pickaxe", Priority: 1},
+		{ID: "toggle-regex", Name: "Regex", Description: "Toggle regex mode", Category: plugin.CategoryView, Context: "git-pickaxe", Priority: 2},
+		{ID: "cancel", Name: "Cancel", Description: "Close search", Category: plugin.CategoryNavigation, Context: "git-pickaxe", Priority: 1},
+		// git-bisect-menu, git-bisect, git-bisect-running and git-bisect-run contexts
+		{ID: "bisect-bad", Name: "Bad", Description: "Mark this commit bad", Category: plugin.CategoryGit, Context: "git-bisect-menu", Priority: 1},
+		{ID: "bisect-good", Name: "Good", Description: "Mark this commit good", Category: plugin.CategoryGit, Context: "git-bisect-menu", Priority: 1},
+		{ID: "bisect-skip", Name: "Skip", Description: "Skip this commit", Category: plugin.CategoryGit, Context: "git-bisect-menu", Priority: 2},
+		{ID: "cancel", Name: "Cancel", Description: "Close bisect", Category: plugin.CategoryNavigation, Context: "git-bisect-menu", Priority: 2},
+		{ID: "bisect-good", Name: "Good", Description: "Mark the tested commit good", Category: plugin.CategoryGit, Context: "git-bisect", Priority: 1},
+		{ID: "bisect-bad", Name: "Bad", Description: "Mark the tested commit bad", Category: plugin.CategoryGit, Context: "git-bisect", Priority: 1},
+		{ID: "bisect-skip", Name: "Skip", Description: "Skip the tested commit", Category: plugin.CategoryGit, Context: "git-bisect", Priority: 2},
+		{ID: "bisect-run", Name: "Run", Description: "Test each step with a command", Category: plugin.CategoryGit, Context: "git-bisect", Priority: 2},
+		{ID: "bisect-reset", Name: "Reset", Description: "End the bisect", Category: plugin.CategoryGit, Context: "git-bisect", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close bisect", Category: plugin.CategoryNavigation, Context: "git-bisect", Priority: 3},
+		{ID: "cancel-bisect-run", Name: "Cancel", Description: "Stop the test command and reset", Category: plugin.CategoryGit, Context: "git-bisect-running", Priority: 1},
+		{ID: "bisect-run", Name: "Run", Description: "Run the bisect", Category: plugin.CategoryGit, Context: "git-bisect-run", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close prompt", Category: plugin.CategoryNavigation, Context: "git-bisect-run", Priority: 1},
+		// git-stashes, git-stash, git-stash-branch and git-stash-drop contexts
+		{ID: "open-stash", Name: "Open", Description: "Show the stash's files", Category: plugin.CategoryView, Context: "git-stashes", Priority: 1},
+		{ID: "stash-apply", Name: "Apply", Description: "Apply stash (keep it)", Category: plugin.CategoryGit, Context: "git-stashes", Priority: 1},
+		{ID: "stash-pop", Name: "Pop", Description: "Apply and remove stash", Category: plugin.CategoryGit, Context: "git-stashes", Priority: 2},
+		{ID: "stash-branch", Name: "Branch", Description: "Create a branch from the stash", Category: plugin.CategoryGit, Context: "git-stashes", Priority: 2},
+		{ID: "stash-drop", Name: "Drop", Description: "Delete the stash", Category: plugin.CategoryGit, Context: "git-stashes", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close stashes", Category: plugin.CategoryNavigation, Context: "git-stashes", Priority: 3},
+		{ID: "stash-apply-file", Name: "Apply file", Description: "Apply the selected file only", Category: plugin.CategoryGit, Context: "git-stash", Priority: 1},
+		{ID: "stash-apply", Name: "Apply all", Description: "Apply the whole stash", Category: plugin.CategoryGit, Context: "git-stash", Priority: 2},
+		{ID: "stash-branch", Name: "Branch", Description: "Create a branch from the stash", Category: plugin.CategoryGit, Context: "git-stash", Priority: 2},
+		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff", Category: plugin.CategoryView, Context: "git-stash", Priority: 3},
+		{ID: "cancel", Name: "Back", Description: "Back to stashes", Category: plugin.CategoryNavigation, Context: "git-stash", Priority: 3},
+		{ID: "create-branch", Name: "Create", Description: "Create the branch", Category: plugin.CategoryGit, Context: "git-stash-branch", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close prompt", Category: plugin.CategoryNavigation, Context: "git-stash-branch", Priority: 1},
+		{ID: "stash-drop", Name: "Drop", Description: "Drop the stash", Category: plugin.CategoryGit, Context: "git-stash-drop", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Keep the stash", Category: plugin.CategoryNavigation, Context: "git-stash-drop", Priority: 1},
+		{ID: "compare", Name: "Compare", Description: "Compare the refs", Category: plugin.CategoryGit, Context: "git-compare-prompt", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close prompt", Category: plugin.CategoryNavigation, Context: "git-compare-prompt", Priority: 1},
+		{ID: "compare-next-tab", Name: "Tab", Description: "Commits, diff or range-diff", Category: plugin.CategoryView, Context: "git-compare", Priority: 1},
+		{ID: "compare-swap", Name: "Swap", Description: "Swap base and compare refs", Category: plugin.CategoryGit, Context: "git-compare", Priority: 2},
+		{ID: "compare-direct", Name: "Direct", Description: "Diff from merge base or between tips", Category: plugin.CategoryView, Context: "git-compare", Priority: 3},
+		{ID: "compare-edit", Name: "Refs", Description: "Change the compared refs", Category: plugin.CategoryGit, Context: "git-compare", Priority: 2},
+		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff", Category: plugin.CategoryView, Context: "git-compare", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close comparison", Category: plugin.CategoryNavigation, Context: "git-compare", Priority: 3},
+		{ID: "export-patches", Name: "Export", Description: "Write the patches", Category: plugin.CategoryGit, Context: "git-patch-export", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close prompt", Category: plugin.CategoryNavigation, Context: "git-patch-export", Priority: 1},
+		{ID: "apply-patch", Name: "Apply", Description: "Apply the patch file", Category: plugin.CategoryGit, Context: "git-patch-apply", Priority: 1},
+		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff", Category: plugin.CategoryView, Context: "git-patch-apply", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close preview", Category: plugin.CategoryNavigation, Context: "git-patch-apply", Priority: 3},
+		{ID: "open-submodule", Name: "Open", Description: "Open the submodule as a repository", Category: plugin.CategoryNavigation, Context: "git-submodules", Priority: 1},
+		{ID: "update-submodule", Name: "Update", Description: "Check out the recorded commit", Category: plugin.CategoryGit, Context: "git-submodules", Priority: 1},
+		{ID: "sync-submodule", Name: "Sync", Description: "Copy the URL from .gitmodules", Category: plugin.CategoryGit, Context: "git-submodules", Priority: 2},
+		{ID: "update-all-submodules", Name: "All", Description: "Update every submodule", Category: plugin.CategoryGit, Context: "git-submodules", Priority: 3},
+		{ID: "cancel", Name: "Close", Description: "Close submodules", Category: plugin.CategoryNavigation, Context: "git-submodules", Priority: 3},
 		// git-error context (error modal)
 		{ID: "pull-from-error", Name: "Pull", Description: "Pull from remote", Category: plugin.CategoryGit, Context: "git-error", Priority: 1},
 		{ID: "dismiss", Name: "Dismiss", Description: "Dismiss error", Category: plugin.CategoryNavigation, Context: "git-error", Priority: 1},
 		{ID: "yank-error", Name: "Yank", Description: "Copy error to clipboard", Category: plugin.CategoryActions, Context: "git-error", Priority: 2},
+		{ID: "resolve-conflicts", Name: "Resolve", Description: "Open the conflict resolver", Category: plugin.CategoryGit, Context: "git-error", Priority: 1},
+		{ID: "continue-sequencer", Name: "Continue", Description: "Continue cherry-pick or revert", Category: plugin.CategoryGit, Context: "git-error", Priority: 1},
+		{ID: "abort-sequencer", Name: "Abort", Description: "Abort cherry-pick or revert", Category: plugin.CategoryGit, Context: "git-error", Priority: 2},
 		// git-stash-pop context (stash pop confirmation modal)
 		{ID: "confirm-pop", Name: "Pop", Description: "Confirm stash pop", Category: plugin.CategoryGit, Context: "git-stash-pop", Priority: 1},
 		{ID: "dismiss", Name: "Cancel", Description: "Cancel stash pop", Category: plugin.CategoryNavigation, Context: "git-stash-pop", Priority: 2},
@@ -1041,6 +1776,63 @@ func (p *Plugin) FocusContext() string {
 		return "git-error"
 	case ViewModeConfirmStashPop:
 		return "git-stash-pop"
+	case ViewModeRebase:
+		if p.rebaseStage == rebaseStageReword {
+			return "git-rebase-reword"
+		}
+		return "git-rebase"
+	case ViewModeCommitAction:
+		if p.commitActionInputStage {
+			return "git-cherry-pick-input"
+		}
+		return "git-commit-action"
+	case ViewModeReflog:
+		return "git-reflog"
+	case ViewModeConfirmUndo:
+		return "git-undo"
+	case ViewModeConflicts:
+		return "git-conflicts"
+	case ViewModeTagCreate:
+		return "git-tag-create"
+	case ViewModeTags:
+		return "git-tags"
+	case ViewModeConfirmDeleteTag:
+		return "git-tag-delete"
+	case ViewModeRelease:
+		return "git-release"
+	case ViewModeFileHistory:
+		return "git-file-history"
+	case ViewModePickaxe:
+		return "git-pickaxe"
+	case ViewModeBisectMenu:
+		return "git-bisect-menu"
+	case ViewModeBisect:
+		if p.bisectRunCancel != nil {
+			return "git-bisect-running"
+		}
+		return "git-bisect"
+	case ViewModeBisectRun:
+		return "git-bisect-run"
+	case ViewModeStashes:
+		return "git-stashes"
+	case ViewModeStash:
+		return "git-stash"
+	case ViewModeStashBranch:
+		return "git-stash-branch"
+	case ViewModeConfirmStashDrop:
+		return "git-stash-drop"
+	case ViewModeCommitTrailers:
+		return "git-commit-trailers"
+	case ViewModeComparePrompt:
+		return "git-compare-prompt"
+	case ViewModeCompare:
+		return "git-compare"
+	case ViewModePatchExport:
+		return "git-patch-export"
+	case ViewModePatchApply:
+		return "git-patch-apply"
+	case ViewModeSubmodules:
+		return "git-submodules"
 	default:
 		if p.activePane == PaneDiff {
 			// Commit preview pane has different context than file diff pane
@@ -1060,7 +1852,12 @@ func (p *Plugin) FocusContext() string {
 // ConsumesTextInput reports whether the plugin is currently in a mode where
 // printable keys should be treated as text input.
 func (p *Plugin) ConsumesTextInput() bool {
-	return p.viewMode == ViewModeCommit || p.historySearchMode || p.pathFilterMode
+	return p.viewMode == ViewModeCommit || p.historySearchMode || p.pathFilterMode ||
+		(p.viewMode == ViewModeRebase && p.rebaseStage == rebaseStageReword) ||
+		(p.viewMode == ViewModeCommitAction && p.commitActionInputStage) ||
+		p.viewMode == ViewModeTagCreate || p.viewMode == ViewModePickaxe ||
+		p.viewMode == ViewModeBisectRun || p.viewMode == ViewModeStashBranch ||
+		p.viewMode == ViewModeComparePrompt || p.viewMode == ViewModePatchExport
 }
 
 // Diagnostics returns plugin health info.
@@ -1098,12 +1895,13 @@ func (p *Plugin) startWatcher() tea.Cmd {
 	if !p.hasRepo || p.repoRoot == "" {
 		return nil
 	}
+	workDir := p.repoRoot
 	return func() tea.Msg {
-		watcher, err := NewWatcher(p.repoRoot)
+		watcher, err := NewWatcher(workDir)
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
-		return WatchStartedMsg{Watcher: watcher}
+		return WatchStartedMsg{Watcher: watcher, Dir: workDir}
 	}
 }
 
@@ -1115,8 +1913,11 @@ func (p *Plugin) listenForWatchEvents() tea.Cmd {
 		return nil
 	}
 	return func() tea.Msg {
-		// When watcher is stopped, Events() channel is closed and this returns
-		<-w.Events()
+		// When watcher is stopped, Events() channel is closed and this
+		// returns without a message, so a replaced watcher stops listening
+		if _, ok := <-w.Events(); !ok {
+			return nil
+		}
 		return WatchEventMsg{}
 	}
 }
@@ -1195,7 +1996,10 @@ func countLines(s string) int {
 // Message types
 type RefreshDoneMsg struct{}
 type WatchEventMsg struct{}
-type WatchStartedMsg struct{ Watcher *Watcher }
+type WatchStartedMsg struct {
+	Watcher *Watcher
+	Dir     string // Repo root the watcher was started for
+}
 type ErrorMsg struct{ Err error }
 type DiffLoadedMsg struct {
 	Epoch   uint64 // Epoch when request was issued (for stale detection)
@@ -1255,6 +2059,18 @@ type FilteredCommitsLoadedMsg struct {
 // GetEpoch implements plugin.EpochMessage.
 func (m FilteredCommitsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
 
+// SignaturesLoadedMsg carries the signature status of commits in the
+// history list.
+type SignaturesLoadedMsg struct {
+	Epoch    uint64
+	Hashes   []string // Commits that were checked
+	Statuses map[string]SignatureStatus
+	Err      error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SignaturesLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
 // CommitStatsLoadedMsg is sent when commit stats are loaded.
 type CommitStatsLoadedMsg struct {
 	Epoch uint64 // Epoch when request was issued (for stale detection)
@@ -1285,8 +2101,9 @@ type PushSuccessClearMsg struct{}
 
 // StashResultMsg is sent when a stash operation completes.
 type StashResultMsg struct {
-	Operation string // "push", "pop", or "apply"
+	Operation string // "push", "pop", "apply", "apply-file", "branch" or "drop"
 	Ref       string // stash ref for display (e.g. "stash@{0}")
+	Detail    string // File applied or branch created
 	Err       error
 }
 
@@ -1365,6 +2182,7 @@ func (p *Plugin) initCommitTextarea() {
 	p.commitButtonHover = false
 	p.commitModal = nil
 	p.commitModalWidthCache = 0
+	p.resetCommitAssist()
 }
 
 // clearPushSuccessAfterDelay returns a command that clears the push success indicator after 3 seconds.
@@ -66,8 +66,9 @@ func (p *Plugin) autoLoadDiff() tea.Cmd {
 	p.selectedDiffFile = entry.Path
 	p.forceNextDiffReload = false
 	if isNewFile {
-		// Only reset scroll when switching to a different file
+		// Only reset scroll and line cursor when switching to a different file
 		p.diffPaneScroll = 0
+		p.diffSelection = DiffSelection{}
 	}
 	// Clear commit preview when switching to file
 	p.previewCommit = nil
@@ -77,6 +78,16 @@ func (p *Plugin) autoLoadDiff() tea.Cmd {
 		return p.loadFolderDiff(entry)
 	}
 
+	// Submodules show their drift instead of the gitlink diff
+	if entry.Submodule != nil {
+		p.diffPaneParsedDiff = nil
+		if isNewFile {
+			p.submoduleDrift = nil
+			p.submoduleDriftErr = ""
+		}
+		return p.loadSubmoduleDrift(entry.Path)
+	}
+
 	return p.loadInlineDiff(entry.Path, entry.Staged, entry.Status)
 }
 
@@ -97,7 +108,7 @@ func (p *Plugin) autoLoadCommitPreview() tea.Cmd {
 	commit := commits[commitIdx]
 	// Already loaded this commit?
 	if p.previewCommit != nil && p.previewCommit.Hash == commit.Hash {
-		return nil
+		return p.loadVisibleSignatures()
 	}
 
 	// Clear file diff when switching to commit
@@ -106,7 +117,7 @@ func (p *Plugin) autoLoadCommitPreview() tea.Cmd {
 	p.previewCommitCursor = 0
 	p.previewCommitScroll = 0
 
-	return p.loadCommitDetailForPreview(commit.Hash)
+	return tea.Batch(p.loadCommitDetailForPreview(commit.Hash), p.loadVisibleSignatures())
 }
 
 // autoLoadPreview loads the appropriate preview for the current cursor position.
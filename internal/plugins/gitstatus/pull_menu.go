@@ -19,8 +19,11 @@ const (
 	pullMenuModalWidth = 50 // Default modal width
 	pullMenuMinWidth   = 20 // Minimum modal width
 
-	pullConflictAbortID   = "pull-conflict-abort"
-	pullConflictDismissID = "pull-conflict-dismiss"
+	pullConflictAbortID    = "pull-conflict-abort"
+	pullConflictDismissID  = "pull-conflict-dismiss"
+	pullConflictContinueID = "pull-conflict-continue"
+	pullConflictSkipID     = "pull-conflict-skip"
+	pullConflictResolveID  = "pull-conflict-resolve"
 )
 
 // ensurePullModal builds/rebuilds the pull menu modal.
@@ -85,7 +88,7 @@ func (p *Plugin) ensurePullConflictModal() {
 		modal.WithWidth(modalW),
 		modal.WithVariant(modal.VariantDanger),
 		modal.WithHints(false),
-		modal.WithPrimaryAction(pullConflictAbortID),
+		modal.WithPrimaryAction(pullConflictResolveID),
 	).
 		AddSection(p.pullConflictSummarySection()).
 		AddSection(modal.Spacer()).
@@ -93,10 +96,26 @@ func (p *Plugin) ensurePullConflictModal() {
 		AddSection(modal.Spacer()).
 		AddSection(p.pullConflictResolutionSection()).
 		AddSection(modal.Spacer()).
-		AddSection(modal.Buttons(
+		AddSection(modal.Buttons(p.pullConflictButtons()...))
+}
+
+// pullConflictButtons returns the modal buttons; rebases can also be
+// continued or skipped.
+func (p *Plugin) pullConflictButtons() []modal.ButtonDef {
+	if p.pullConflictType == "rebase" {
+		return []modal.ButtonDef{
+			modal.Btn(" Resolve ", pullConflictResolveID),
+			modal.Btn(" Continue ", pullConflictContinueID),
+			modal.Btn(" Skip ", pullConflictSkipID),
 			modal.Btn(" Abort ", pullConflictAbortID, modal.BtnDanger()),
 			modal.Btn(" Dismiss ", pullConflictDismissID),
-		))
+		}
+	}
+	return []modal.ButtonDef{
+		modal.Btn(" Resolve ", pullConflictResolveID),
+		modal.Btn(" Abort ", pullConflictAbortID, modal.BtnDanger()),
+		modal.Btn(" Dismiss ", pullConflictDismissID),
+	}
 }
 
 // renderPullConflict renders the pull conflict resolution modal.
@@ -147,7 +166,11 @@ func (p *Plugin) pullConflictFilesSection() modal.Section {
 
 func (p *Plugin) pullConflictResolutionSection() modal.Section {
 	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
-		content := styles.Muted.Render("Resolve conflicts in your editor, then commit.")
+		hint := "Resolve conflicts (r) or abort the merge (a)."
+		if p.pullConflictType == "rebase" {
+			hint = "Resolve conflicts (r), then continue (c), skip (s) or abort (a)."
+		}
+		content := styles.Muted.Render(hint)
 		return modal.RenderedSection{Content: content}
 	}, nil)
 }
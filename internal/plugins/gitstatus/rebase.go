@@ -0,0 +1,250 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseAction is the todo action applied to a commit in an interactive rebase.
+type RebaseAction int
+
+const (
+	RebasePick RebaseAction = iota
+	RebaseReword
+	RebaseSquash
+	RebaseFixup
+	RebaseDrop
+)
+
+// String returns the git todo keyword for the action.
+func (a RebaseAction) String() string {
+	switch a {
+	case RebaseReword:
+		return "reword"
+	case RebaseSquash:
+		return "squash"
+	case RebaseFixup:
+		return "fixup"
+	case RebaseDrop:
+		return "drop"
+	default:
+		return "pick"
+	}
+}
+
+// RebaseStep is one commit in a rebase plan.
+type RebaseStep struct {
+	Commit  *Commit
+	Action  RebaseAction
+	Message string // New full message for RebaseReword
+}
+
+// RebasePlan describes an interactive rebase of the commits after Base.
+// Steps are ordered newest first, matching the commit list.
+type RebasePlan struct {
+	Base  string
+	Steps []RebaseStep
+}
+
+// NewRebasePlan builds a plan that picks every commit unchanged.
+func NewRebasePlan(base string, commits []*Commit) *RebasePlan {
+	plan := &RebasePlan{Base: base}
+	for _, c := range commits {
+		plan.Steps = append(plan.Steps, RebaseStep{Commit: c, Action: RebasePick})
+	}
+	return plan
+}
+
+// Changed returns true if the plan differs from the current history.
+func (rp *RebasePlan) Changed(original []*Commit) bool {
+	if len(rp.Steps) != len(original) {
+		return true
+	}
+	for i, step := range rp.Steps {
+		if step.Action != RebasePick || step.Commit.Hash != original[i].Hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the plan can be executed.
+func (rp *RebasePlan) Validate() error {
+	kept := 0
+	for i := len(rp.Steps) - 1; i >= 0; i-- {
+		step := rp.Steps[i]
+		if step.Commit.Pushed {
+			return fmt.Errorf("commit %s has already been pushed", step.Commit.ShortHash)
+		}
+		if step.Commit.IsMerge {
+			return fmt.Errorf("commit %s is a merge commit", step.Commit.ShortHash)
+		}
+		if step.Action == RebaseDrop {
+			continue
+		}
+		if kept == 0 && (step.Action == RebaseSquash || step.Action == RebaseFixup) {
+			return fmt.Errorf("cannot %s %s: no earlier commit to meld into", step.Action, step.Commit.ShortHash)
+		}
+		if step.Action == RebaseReword && strings.TrimSpace(step.Message) == "" {
+			return fmt.Errorf("empty message for %s", step.Commit.ShortHash)
+		}
+		kept++
+	}
+	if kept == 0 {
+		return errors.New("plan drops every commit")
+	}
+	return nil
+}
+
+// Todo renders the plan as a git rebase todo list, oldest first. msgFile
+// returns the path of the message file for a reworded step.
+func (rp *RebasePlan) Todo(msgFile func(idx int) string) string {
+	var sb strings.Builder
+	for i := len(rp.Steps) - 1; i >= 0; i-- {
+		step := rp.Steps[i]
+		action := step.Action
+		if action == RebaseReword {
+			// Rewording is done by amending after the pick so no editor is needed
+			action = RebasePick
+		}
+		fmt.Fprintf(&sb, "%s %s %s\n", action, step.Commit.Hash, step.Commit.Subject)
+		if step.Action == RebaseReword {
+			fmt.Fprintf(&sb, "exec git commit --amend --only --no-verify -F %s\n", shellQuote(msgFile(i)))
+		}
+	}
+	return sb.String()
+}
+
+// RebaseResultCommit is a commit in the predicted outcome of a rebase plan.
+type RebaseResultCommit struct {
+	Subject string
+	Hashes  []string // Short hashes of the original commits melded into it
+}
+
+// Result predicts the commits produced by the plan, newest first.
+func (rp *RebasePlan) Result() []RebaseResultCommit {
+	var result []RebaseResultCommit // Built oldest first
+	for i := len(rp.Steps) - 1; i >= 0; i-- {
+		step := rp.Steps[i]
+		switch step.Action {
+		case RebaseDrop:
+			continue
+		case RebaseSquash, RebaseFixup:
+			if n := len(result); n > 0 {
+				result[n-1].Hashes = append(result[n-1].Hashes, step.Commit.ShortHash)
+				continue
+			}
+		}
+		subject := step.Commit.Subject
+		if step.Action == RebaseReword {
+			subject = strings.SplitN(strings.TrimSpace(step.Message), "\n", 2)[0]
+		}
+		result = append(result, RebaseResultCommit{Subject: subject, Hashes: []string{step.Commit.ShortHash}})
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// shellQuote quotes s for use as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// rebaseFilesDir returns the directory used for rebase todo and message files.
+// It lives inside the git dir so message files survive a conflict stop.
+func rebaseFilesDir(workDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(strings.TrimSpace(string(output)), "sidecar-rebase"), nil
+}
+
+// cleanupRebaseFiles removes the plan files once no rebase is in progress.
+func cleanupRebaseFiles(workDir string) {
+	if IsRebaseInProgress(workDir) {
+		return
+	}
+	if dir, err := rebaseFilesDir(workDir); err == nil {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// ExecuteRebase runs the plan with git rebase -i, feeding the todo list
+// through GIT_SEQUENCE_EDITOR. Uncommitted changes are autostashed.
+func ExecuteRebase(workDir string, plan *RebasePlan) (string, error) {
+	if err := plan.Validate(); err != nil {
+		return "", err
+	}
+	dir, err := rebaseFilesDir(workDir)
+	if err != nil {
+		return "", err
+	}
+	_ = os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	var writeErr error
+	todo := plan.Todo(func(idx int) string {
+		path := filepath.Join(dir, fmt.Sprintf("msg-%d", idx))
+		if err := os.WriteFile(path, []byte(plan.Steps[idx].Message), 0644); err != nil && writeErr == nil {
+			writeErr = err
+		}
+		return path
+	})
+	if writeErr != nil {
+		return "", writeErr
+	}
+	todoPath := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoPath, []byte(todo), 0644); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "rebase", "-i", "--autostash", plan.Base)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(),
+		"GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoPath),
+		"GIT_EDITOR=true",
+	)
+	output, err := cmd.CombinedOutput()
+	cleanupRebaseFiles(workDir)
+	if err != nil {
+		return "", &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// ContinueRebase runs git rebase --continue without opening an editor.
+func ContinueRebase(workDir string) (string, error) {
+	cmd := exec.Command("git", "rebase", "--continue")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	output, err := cmd.CombinedOutput()
+	cleanupRebaseFiles(workDir)
+	if err != nil {
+		return "", &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// SkipRebase runs git rebase --skip, dropping the conflicting commit.
+func SkipRebase(workDir string) (string, error) {
+	cmd := exec.Command("git", "rebase", "--skip")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	output, err := cmd.CombinedOutput()
+	cleanupRebaseFiles(workDir)
+	if err != nil {
+		return "", &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
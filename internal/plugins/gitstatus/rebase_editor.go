@@ -0,0 +1,588 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	rebaseItemPrefix   = "rebase-step-"
+	rebaseMessageID    = "rebase-message"
+	rebaseRunID        = "rebase-run"
+	rebaseBackID       = "rebase-back"
+	rebaseSaveReword   = "rebase-save-reword"
+	rebaseModalMinW    = 50
+	rebaseModalDefault = 80
+)
+
+// rebaseStage is the current screen of the rebase editor.
+type rebaseStage int
+
+const (
+	rebaseStagePlan    rebaseStage = iota // Editing the todo list
+	rebaseStageReword                     // Editing a commit message
+	rebaseStagePreview                    // Reviewing the result before running
+)
+
+// RebasePlanLoadedMsg is sent when the commits after a rebase base are loaded.
+type RebasePlanLoadedMsg struct {
+	Base      string
+	BaseShort string
+	Commits   []*Commit
+	Err       error
+}
+
+// RebaseDoneMsg is sent when a rebase, continue or skip finishes.
+type RebaseDoneMsg struct {
+	Op     string // "rebase", "continue" or "skip"
+	Output string
+	Err    error
+}
+
+func rebaseItemID(idx int) string {
+	return fmt.Sprintf("%s%d", rebaseItemPrefix, idx)
+}
+
+func parseRebaseItem(id string) (int, bool) {
+	if !strings.HasPrefix(id, rebaseItemPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, rebaseItemPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// openRebaseEditor starts an interactive rebase onto the selected commit.
+// If a rebase is already stopped, the conflict modal is shown instead.
+func (p *Plugin) openRebaseEditor() tea.Cmd {
+	if IsRebaseInProgress(p.repoRoot) {
+		p.showRebaseConflict()
+		return nil
+	}
+	if p.historyFilterActive {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "Clear history filters before rebasing", Duration: 2 * time.Second}
+		}
+	}
+	commits := p.activeCommits()
+	idx := p.selectedCommitIndex()
+	if idx < 0 || idx >= len(commits) {
+		return nil
+	}
+	if idx == 0 {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "Select the commit to rebase onto (below HEAD)", Duration: 2 * time.Second}
+		}
+	}
+	base := commits[idx]
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		commits, err := GetCommitRange(workDir, base.Hash)
+		if err == nil {
+			PopulatePushStatus(commits, GetPushStatus(workDir))
+		}
+		return RebasePlanLoadedMsg{Base: base.Hash, BaseShort: base.ShortHash, Commits: commits, Err: err}
+	}
+}
+
+// handleRebasePlanLoaded opens the editor once the commit range is known.
+func (p *Plugin) handleRebasePlanLoaded(msg RebasePlanLoadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		p.showErrorModal("Rebase Failed", msg.Err)
+		return nil
+	}
+	if len(msg.Commits) == 0 {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "No commits to rebase", Duration: 2 * time.Second}
+		}
+	}
+	plan := NewRebasePlan(msg.Base, msg.Commits)
+	if err := plan.Validate(); err != nil {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "Cannot rebase: " + err.Error(), Duration: 3 * time.Second, IsError: true}
+		}
+	}
+	p.rebasePlan = plan
+	p.rebaseBaseShort = msg.BaseShort
+	p.rebaseOriginal = msg.Commits
+	p.rebaseCursor = 0
+	p.rebaseError = ""
+	p.setRebaseStage(rebaseStagePlan)
+	p.viewMode = ViewModeRebase
+	return nil
+}
+
+// setRebaseStage switches editor screens and forces the modal to rebuild.
+func (p *Plugin) setRebaseStage(stage rebaseStage) {
+	p.rebaseStage = stage
+	p.clearRebaseModal()
+}
+
+// closeRebaseEditor discards the plan and returns to the status view.
+func (p *Plugin) closeRebaseEditor() {
+	p.viewMode = ViewModeStatus
+	p.rebasePlan = nil
+	p.rebaseOriginal = nil
+	p.rebaseError = ""
+	p.clearRebaseModal()
+}
+
+func (p *Plugin) clearRebaseModal() {
+	p.rebaseModal = nil
+	p.rebaseModalWidth = 0
+}
+
+// updateRebase handles key events in the rebase editor.
+func (p *Plugin) updateRebase(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureRebaseModal()
+	if p.rebaseModal == nil || p.rebasePlan == nil {
+		return p, nil
+	}
+
+	switch p.rebaseStage {
+	case rebaseStageReword:
+		return p.updateRebaseReword(msg)
+	case rebaseStagePreview:
+		return p.updateRebasePreview(msg)
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		p.closeRebaseEditor()
+		return p, nil
+	case "j", "down":
+		p.moveRebaseCursor(1)
+		return p, nil
+	case "k", "up":
+		p.moveRebaseCursor(-1)
+		return p, nil
+	case "g":
+		p.rebaseCursor = 0
+		return p, nil
+	case "G":
+		p.rebaseCursor = len(p.rebasePlan.Steps) - 1
+		return p, nil
+	case "J", "shift+down":
+		p.moveRebaseStep(1)
+		return p, nil
+	case "K", "shift+up":
+		p.moveRebaseStep(-1)
+		return p, nil
+	case "p":
+		p.setRebaseAction(RebasePick)
+		return p, nil
+	case "s":
+		p.setRebaseAction(RebaseSquash)
+		return p, nil
+	case "f":
+		p.setRebaseAction(RebaseFixup)
+		return p, nil
+	case "d":
+		p.setRebaseAction(RebaseDrop)
+		return p, nil
+	case "r":
+		p.startRebaseReword()
+		return p, nil
+	case "enter":
+		p.previewRebase()
+		return p, nil
+	}
+
+	action, cmd := p.rebaseModal.HandleKey(msg)
+	if action == "cancel" {
+		p.closeRebaseEditor()
+		return p, nil
+	}
+	if idx, ok := parseRebaseItem(action); ok {
+		p.rebaseCursor = idx
+	}
+	return p, cmd
+}
+
+// updateRebaseReword handles key events while editing a commit message.
+func (p *Plugin) updateRebaseReword(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.setRebaseStage(rebaseStagePlan)
+		return p, nil
+	case "ctrl+s":
+		p.saveRebaseReword()
+		return p, nil
+	}
+
+	action, cmd := p.rebaseModal.HandleKey(msg)
+	switch action {
+	case rebaseSaveReword:
+		p.saveRebaseReword()
+		return p, nil
+	case "cancel":
+		p.setRebaseStage(rebaseStagePlan)
+		return p, nil
+	}
+	return p, cmd
+}
+
+// updateRebasePreview handles key events on the preview screen.
+func (p *Plugin) updateRebasePreview(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		p.setRebaseStage(rebaseStagePlan)
+		return p, nil
+	case "y":
+		return p, p.runRebase()
+	}
+
+	action, cmd := p.rebaseModal.HandleKey(msg)
+	switch action {
+	case rebaseRunID:
+		return p, p.runRebase()
+	case "cancel", rebaseBackID:
+		p.setRebaseStage(rebaseStagePlan)
+		return p, nil
+	}
+	return p, cmd
+}
+
+func (p *Plugin) moveRebaseCursor(delta int) {
+	n := len(p.rebasePlan.Steps)
+	p.rebaseCursor = max(0, min(n-1, p.rebaseCursor+delta))
+}
+
+// moveRebaseStep moves the selected step down (delta > 0, older) or up.
+func (p *Plugin) moveRebaseStep(delta int) {
+	steps := p.rebasePlan.Steps
+	to := p.rebaseCursor + delta
+	if to < 0 || to >= len(steps) {
+		return
+	}
+	steps[p.rebaseCursor], steps[to] = steps[to], steps[p.rebaseCursor]
+	p.rebaseCursor = to
+	p.rebaseError = ""
+}
+
+func (p *Plugin) setRebaseAction(action RebaseAction) {
+	step := &p.rebasePlan.Steps[p.rebaseCursor]
+	step.Action = action
+	step.Message = ""
+	p.rebaseError = ""
+}
+
+// startRebaseReword opens the message editor for the selected step.
+func (p *Plugin) startRebaseReword() {
+	step := p.rebasePlan.Steps[p.rebaseCursor]
+	message := step.Message
+	if step.Action != RebaseReword {
+		message = strings.TrimRight(getCommitMessage(p.repoRoot, step.Commit.Hash), "\n")
+	}
+
+	p.rebaseMessage = textarea.New()
+	p.rebaseMessage.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(styles.TextSecondary)
+	p.rebaseMessage.CharLimit = 0
+	p.rebaseMessage.SetWidth(max(p.rebaseModalWidthForContent()-8, 20))
+	p.rebaseMessage.SetHeight(6)
+	p.rebaseMessage.SetValue(message)
+	p.rebaseMessage.Focus()
+	p.setRebaseStage(rebaseStageReword)
+}
+
+func (p *Plugin) saveRebaseReword() {
+	message := strings.TrimSpace(p.rebaseMessage.Value())
+	if message == "" {
+		return
+	}
+	step := &p.rebasePlan.Steps[p.rebaseCursor]
+	original := strings.TrimSpace(getCommitMessage(p.repoRoot, step.Commit.Hash))
+	if message == original {
+		step.Action = RebasePick
+		step.Message = ""
+	} else {
+		step.Action = RebaseReword
+		step.Message = message + "\n"
+	}
+	p.rebaseError = ""
+	p.setRebaseStage(rebaseStagePlan)
+}
+
+// previewRebase validates the plan and shows the predicted result.
+func (p *Plugin) previewRebase() {
+	if !p.rebasePlan.Changed(p.rebaseOriginal) {
+		p.rebaseError = "Plan is unchanged"
+		return
+	}
+	if err := p.rebasePlan.Validate(); err != nil {
+		p.rebaseError = err.Error()
+		return
+	}
+	p.rebaseError = ""
+	p.setRebaseStage(rebaseStagePreview)
+}
+
+// runRebase executes the plan and closes the editor.
+func (p *Plugin) runRebase() tea.Cmd {
+	plan := p.rebasePlan
+	workDir := p.repoRoot
+	p.closeRebaseEditor()
+	return func() tea.Msg {
+		before := currentHead(workDir)
+		output, err := ExecuteRebase(workDir, plan)
+		logOperation(workDir, "rebase", before)
+		return RebaseDoneMsg{Op: "rebase", Output: output, Err: err}
+	}
+}
+
+// doContinueRebase continues a stopped rebase.
+func (p *Plugin) doContinueRebase() tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		before := pendingOrigHead(workDir)
+		output, err := ContinueRebase(workDir)
+		logOperation(workDir, "rebase", before)
+		return RebaseDoneMsg{Op: "continue", Output: output, Err: err}
+	}
+}
+
+// doSkipRebase skips the commit a rebase stopped on.
+func (p *Plugin) doSkipRebase() tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		before := pendingOrigHead(workDir)
+		output, err := SkipRebase(workDir)
+		logOperation(workDir, "rebase", before)
+		return RebaseDoneMsg{Op: "skip", Output: output, Err: err}
+	}
+}
+
+// handleRebaseDone reports the outcome of a rebase step. A rebase that
+// stopped on conflicts opens the conflict modal.
+func (p *Plugin) handleRebaseDone(msg RebaseDoneMsg) tea.Cmd {
+	refresh := tea.Batch(p.refresh(), p.loadRecentCommits())
+	if msg.Err != nil {
+		if IsRebaseInProgress(p.repoRoot) {
+			p.showRebaseConflict()
+			return refresh
+		}
+		p.showErrorModal("Rebase Failed", msg.Err)
+		return refresh
+	}
+	p.pullConflictFiles = nil
+	p.pullConflictType = ""
+	toast := func() tea.Msg {
+		return app.ToastMsg{Message: "Rebase complete", Duration: 2 * time.Second}
+	}
+	return tea.Batch(refresh, toast)
+}
+
+// showRebaseConflict opens the conflict modal for a stopped rebase.
+func (p *Plugin) showRebaseConflict() {
+	p.pullConflictType = "rebase"
+	p.pullConflictFiles = GetConflictedFiles(p.repoRoot)
+	p.viewMode = ViewModePullConflict
+	p.clearPullConflictModal()
+}
+
+// rebaseModalWidthForContent returns the editor width for the screen size.
+func (p *Plugin) rebaseModalWidthForContent() int {
+	modalW := rebaseModalDefault
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < rebaseModalMinW {
+		modalW = rebaseModalMinW
+	}
+	return modalW
+}
+
+// ensureRebaseModal builds/rebuilds the modal for the current stage.
+func (p *Plugin) ensureRebaseModal() {
+	modalW := p.rebaseModalWidthForContent()
+	if p.rebaseModal != nil && p.rebaseModalWidth == modalW {
+		return
+	}
+	p.rebaseModalWidth = modalW
+
+	title := "Rebase onto " + p.rebaseBaseShort
+	switch p.rebaseStage {
+	case rebaseStageReword:
+		p.rebaseModal = modal.New("Reword",
+			modal.WithWidth(modalW),
+			modal.WithPrimaryAction(rebaseSaveReword),
+			modal.WithHints(false),
+		).
+			AddSection(p.rebaseRewordHeaderSection()).
+			AddSection(modal.Spacer()).
+			AddSection(modal.Textarea(rebaseMessageID, &p.rebaseMessage, 6)).
+			AddSection(modal.Spacer()).
+			AddSection(modal.Buttons(
+				modal.Btn(" Save ", rebaseSaveReword),
+				modal.Btn(" Cancel ", "cancel"),
+			))
+	case rebaseStagePreview:
+		p.rebaseModal = modal.New(title,
+			modal.WithWidth(modalW),
+			modal.WithVariant(modal.VariantDanger),
+			modal.WithPrimaryAction(rebaseRunID),
+			modal.WithHints(false),
+		).
+			AddSection(p.rebasePreviewSection()).
+			AddSection(modal.Spacer()).
+			AddSection(modal.Buttons(
+				modal.Btn(" Rebase ", rebaseRunID, modal.BtnDanger()),
+				modal.Btn(" Back ", rebaseBackID),
+			))
+	default:
+		p.rebaseModal = modal.New(title,
+			modal.WithWidth(modalW),
+			modal.WithHints(false),
+		).
+			AddSection(p.rebaseStepsSection()).
+			AddSection(modal.Spacer()).
+			AddSection(p.rebaseHintsSection())
+	}
+}
+
+// renderRebase renders the rebase editor modal.
+func (p *Plugin) renderRebase() string {
+	background := p.renderThreePaneView()
+
+	p.ensureRebaseModal()
+	if p.rebaseModal == nil {
+		return background
+	}
+
+	modalContent := p.rebaseModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// rebaseActionStyle returns the style used for an action keyword.
+func rebaseActionStyle(action RebaseAction) lipgloss.Style {
+	switch action {
+	case RebaseReword:
+		return styles.StatusModified
+	case RebaseSquash, RebaseFixup:
+		return styles.StatusInProgress
+	case RebaseDrop:
+		return styles.StatusDeleted
+	default:
+		return styles.StatusStaged
+	}
+}
+
+func (p *Plugin) rebaseStepsSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		steps := p.rebasePlan.Steps
+		maxVisible := max(p.height-14, 5)
+		start := 0
+		if p.rebaseCursor >= maxVisible {
+			start = p.rebaseCursor - maxVisible + 1
+		}
+		end := min(start+maxVisible, len(steps))
+
+		var sb strings.Builder
+		sb.WriteString(styles.Muted.Render("Newest first. Commits are replayed bottom to top."))
+		sb.WriteString("\n")
+		focusables := make([]modal.FocusableInfo, 0, end-start)
+
+		for i := start; i < end; i++ {
+			step := steps[i]
+			itemID := rebaseItemID(i)
+			subject := step.Commit.Subject
+			if step.Action == RebaseReword {
+				subject = strings.SplitN(step.Message, "\n", 2)[0]
+			}
+
+			plain := fmt.Sprintf(" %-6s %s %s", step.Action, step.Commit.ShortHash, subject)
+			plain = ansi.Truncate(plain, contentWidth, "…")
+
+			var line string
+			if i == p.rebaseCursor || itemID == hoverID {
+				line = styles.ListItemSelected.Render(plain + strings.Repeat(" ", max(contentWidth-ansi.StringWidth(plain), 0)))
+			} else {
+				rest := ansi.Truncate(step.Commit.ShortHash+" "+subject, max(contentWidth-8, 0), "…")
+				if step.Action == RebaseDrop {
+					rest = styles.Muted.Render(rest)
+				}
+				line = " " + rebaseActionStyle(step.Action).Render(fmt.Sprintf("%-6s", step.Action)) + " " + rest
+			}
+			sb.WriteString("\n")
+			sb.WriteString(line)
+
+			focusables = append(focusables, modal.FocusableInfo{
+				ID:      itemID,
+				OffsetX: 0,
+				OffsetY: i - start + 1,
+				Width:   ansi.StringWidth(line),
+				Height:  1,
+			})
+		}
+
+		if len(steps) > maxVisible {
+			sb.WriteString("\n\n" + styles.Muted.Render(fmt.Sprintf(" %d/%d commits", p.rebaseCursor+1, len(steps))))
+		}
+		if p.rebaseError != "" {
+			sb.WriteString("\n\n" + styles.StatusDeleted.Render(p.rebaseError))
+		}
+
+		return modal.RenderedSection{Content: sb.String(), Focusables: focusables}
+	}, nil)
+}
+
+func (p *Plugin) rebaseHintsSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		hints := []string{
+			"p pick  r reword  s squash  f fixup  d drop",
+			"J/K move  Enter preview  Esc cancel",
+		}
+		return modal.RenderedSection{Content: styles.Muted.Render(strings.Join(hints, "\n"))}
+	}, nil)
+}
+
+func (p *Plugin) rebaseRewordHeaderSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		step := p.rebasePlan.Steps[p.rebaseCursor]
+		line := fmt.Sprintf("%s %s", step.Commit.ShortHash, step.Commit.Subject)
+		content := styles.Muted.Render(ansi.Truncate(line, contentWidth, "…")) + "\n" +
+			styles.Muted.Render("Ctrl+S to save, Esc to cancel")
+		return modal.RenderedSection{Content: content}
+	}, nil)
+}
+
+func (p *Plugin) rebasePreviewSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		result := p.rebasePlan.Result()
+		dropped := 0
+		for _, step := range p.rebasePlan.Steps {
+			if step.Action == RebaseDrop {
+				dropped++
+			}
+		}
+
+		var sb strings.Builder
+		summary := fmt.Sprintf("%d commits will become %d", len(p.rebasePlan.Steps), len(result))
+		if dropped > 0 {
+			summary += fmt.Sprintf(" (%d dropped)", dropped)
+		}
+		sb.WriteString(styles.Muted.Render(summary + ":"))
+		for _, c := range result {
+			hashes := styles.Muted.Render(strings.Join(c.Hashes, "+"))
+			subject := ansi.Truncate(c.Subject, max(contentWidth-ansi.StringWidth(hashes)-4, 10), "…")
+			sb.WriteString("\n  " + styles.Body.Render(subject) + " " + hashes)
+		}
+		sb.WriteString("\n\n")
+		sb.WriteString(styles.Muted.Render("Uncommitted changes are stashed and restored automatically."))
+		return modal.RenderedSection{Content: sb.String()}
+	}, nil)
+}
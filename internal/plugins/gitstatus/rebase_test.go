@@ -0,0 +1,228 @@
+package gitstatus
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func rebaseTestCommits() []*Commit {
+	// Newest first, like the commit list
+	return []*Commit{
+		{Hash: "ccc", ShortHash: "ccc", Subject: "third"},
+		{Hash: "bbb", ShortHash: "bbb", Subject: "second"},
+		{Hash: "aaa", ShortHash: "aaa", Subject: "first"},
+	}
+}
+
+func TestRebasePlanTodo(t *testing.T) {
+	plan := NewRebasePlan("base", rebaseTestCommits())
+	plan.Steps[0].Action = RebaseFixup
+	plan.Steps[2].Action = RebaseReword
+	plan.Steps[2].Message = "renamed\n"
+
+	todo := plan.Todo(func(idx int) string { return "/tmp/msg" })
+	want := "pick aaa first\n" +
+		"exec git commit --amend --only --no-verify -F '/tmp/msg'\n" +
+		"pick bbb second\n" +
+		"fixup ccc third\n"
+	if todo != want {
+		t.Errorf("Todo() =\n%s\nwant\n%s", todo, want)
+	}
+}
+
+func TestRebasePlanValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(plan *RebasePlan)
+		wantErr string
+	}{
+		{"all picks", func(*RebasePlan) {}, ""},
+		{"squash oldest", func(p *RebasePlan) { p.Steps[2].Action = RebaseSquash }, "no earlier commit"},
+		{"squash after dropped oldest", func(p *RebasePlan) {
+			p.Steps[2].Action = RebaseDrop
+			p.Steps[1].Action = RebaseFixup
+		}, "no earlier commit"},
+		{"drop all", func(p *RebasePlan) {
+			for i := range p.Steps {
+				p.Steps[i].Action = RebaseDrop
+			}
+		}, "drops every commit"},
+		{"pushed", func(p *RebasePlan) { p.Steps[1].Commit.Pushed = true }, "already been pushed"},
+		{"merge", func(p *RebasePlan) { p.Steps[0].Commit.IsMerge = true }, "merge commit"},
+		{"empty reword", func(p *RebasePlan) { p.Steps[0].Action = RebaseReword }, "empty message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewRebasePlan("base", rebaseTestCommits())
+			tt.setup(plan)
+			err := plan.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRebasePlanResult(t *testing.T) {
+	plan := NewRebasePlan("base", rebaseTestCommits())
+	plan.Steps[0].Action = RebaseSquash
+	plan.Steps[1].Action = RebaseDrop
+	plan.Steps[2].Action = RebaseReword
+	plan.Steps[2].Message = "renamed\n\nbody"
+
+	result := plan.Result()
+	if len(result) != 1 {
+		t.Fatalf("expected 1 resulting commit, got %d", len(result))
+	}
+	if result[0].Subject != "renamed" {
+		t.Errorf("subject = %q, want %q", result[0].Subject, "renamed")
+	}
+	if got := strings.Join(result[0].Hashes, ","); got != "aaa,ccc" {
+		t.Errorf("hashes = %q, want %q", got, "aaa,ccc")
+	}
+}
+
+func TestRebasePlanChanged(t *testing.T) {
+	commits := rebaseTestCommits()
+	plan := NewRebasePlan("base", commits)
+	if plan.Changed(commits) {
+		t.Error("fresh plan should be unchanged")
+	}
+	plan.Steps[0], plan.Steps[1] = plan.Steps[1], plan.Steps[0]
+	if !plan.Changed(commits) {
+		t.Error("reordered plan should be changed")
+	}
+}
+
+func TestExecuteRebase(t *testing.T) {
+	dir := initPartialRepo(t, "base\n")
+	commit := func(name, subject string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(subject+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", name}, {"commit", "-m", subject}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v (%s)", args, err, out)
+			}
+		}
+	}
+	commit("a.txt", "add a")
+	commit("b.txt", "add b")
+	commit("c.txt", "fix a")
+	commit("d.txt", "add d")
+
+	history, err := GetCommitHistory(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := history[len(history)-1].Hash
+	commits, err := GetCommitRange(dir, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 4 {
+		t.Fatalf("expected 4 commits in range, got %d", len(commits))
+	}
+
+	// Newest first: add d, fix a, add b, add a.
+	// Move "fix a" below "add b", squash it into "add a", reword "add b", drop "add d".
+	plan := NewRebasePlan(base, commits)
+	plan.Steps[1], plan.Steps[2] = plan.Steps[2], plan.Steps[1]
+	plan.Steps[0].Action = RebaseDrop
+	plan.Steps[1].Action = RebaseReword
+	plan.Steps[1].Message = "add b (reworded)\n"
+	plan.Steps[2].Action = RebaseFixup
+
+	if _, err := ExecuteRebase(dir, plan); err != nil {
+		t.Fatalf("ExecuteRebase: %v", err)
+	}
+
+	after, err := GetCommitRange(dir, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, c := range after {
+		subjects = append(subjects, c.Subject)
+	}
+	if got := strings.Join(subjects, "|"); got != "add b (reworded)|add a" {
+		t.Errorf("subjects after rebase = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "d.txt")); !os.IsNotExist(err) {
+		t.Error("dropped commit's file should be gone")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.txt")); err != nil {
+		t.Error("fixup commit's file should be kept")
+	}
+	if dirPath, err := rebaseFilesDir(dir); err != nil {
+		t.Fatal(err)
+	} else if _, err := os.Stat(dirPath); !os.IsNotExist(err) {
+		t.Error("rebase plan files should be cleaned up")
+	}
+}
+
+func TestExecuteRebaseConflictContinue(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	write := func(content, subject string) {
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("git", "commit", "-am", subject)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v (%s)", err, out)
+		}
+	}
+	write("two\n", "second")
+	write("three\n", "third")
+
+	history, _ := GetCommitHistory(dir, 10)
+	base := history[len(history)-1].Hash
+	commits, _ := GetCommitRange(dir, base)
+
+	// Swapping the two edits to the same line conflicts
+	plan := NewRebasePlan(base, commits)
+	plan.Steps[0], plan.Steps[1] = plan.Steps[1], plan.Steps[0]
+	if _, err := ExecuteRebase(dir, plan); err == nil {
+		t.Fatal("expected conflict")
+	}
+	if !IsRebaseInProgress(dir) {
+		t.Fatal("expected rebase in progress")
+	}
+	if files := GetConflictedFiles(dir); len(files) != 1 || files[0] != "file.txt" {
+		t.Fatalf("conflicted files = %v", files)
+	}
+
+	// Resolve and continue through the remaining commit
+	for _, content := range []string{"three\n", "two\n"} {
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("git", "add", "file.txt")
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ContinueRebase(dir); err == nil {
+			break
+		}
+	}
+	if IsRebaseInProgress(dir) {
+		t.Fatal("rebase should be finished")
+	}
+	after, _ := GetCommitRange(dir, base)
+	if len(after) != 2 || after[0].Subject != "second" {
+		t.Errorf("unexpected history after continue: %+v", after)
+	}
+}
@@ -0,0 +1,419 @@
+package gitstatus
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// oplogName is the per-worktree file (inside the git dir) recording the HEAD
+// movements caused by sidecar operations, and the discards and stash pops
+// that can be undone.
+const oplogName = "sidecar-oplog.jsonl"
+
+// oplogMaxEntries caps the operation log; older entries are dropped.
+const oplogMaxEntries = 200
+
+// ReflogEntry is one HEAD movement from git reflog.
+type ReflogEntry struct {
+	Hash      string
+	ShortHash string
+	Selector  string // e.g. "HEAD@{2}"
+	Action    string // e.g. "commit (amend)", "checkout", "pull"
+	Message   string
+	Date      time.Time
+	SidecarOp string // Sidecar operation that caused the movement, if known
+}
+
+// OpRecord is a sidecar operation that moved HEAD, or with Kind set, one
+// that changed files in the working tree.
+type OpRecord struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind,omitempty"` // "", opKindUndo, opKindDiscard or opKindStashPop
+	Op           string    `json:"op"`
+	Before       string    `json:"before"`
+	BeforeBranch string    `json:"before_branch,omitempty"`
+	After        string    `json:"after"`
+	AfterBranch  string    `json:"after_branch,omitempty"`
+
+	Snapshot     string          `json:"snapshot,omitempty"` // `git stash create` before the operation
+	Files        []JournaledFile `json:"files,omitempty"`
+	Stash        string          `json:"stash,omitempty"` // Popped stash commit
+	StashMessage string          `json:"stash_message,omitempty"`
+}
+
+// opKindUndo marks the record of an undo. Undos move HEAD but aren't
+// undoable themselves: the next undo steps back past the operation undone.
+const opKindUndo = "undo"
+
+// headState is the commit and branch HEAD points at.
+type headState struct {
+	Hash   string
+	Branch string // Empty when detached
+}
+
+// currentHead reads the current HEAD commit and branch.
+func currentHead(workDir string) headState {
+	var state headState
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = workDir
+	if out, err := cmd.Output(); err == nil {
+		state.Hash = strings.TrimSpace(string(out))
+	}
+	cmd = exec.Command("git", "branch", "--show-current")
+	cmd.Dir = workDir
+	if out, err := cmd.Output(); err == nil {
+		state.Branch = strings.TrimSpace(string(out))
+	}
+	return state
+}
+
+// oplogPath returns the absolute path of the operation log.
+func oplogPath(workDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", oplogName)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	return path, nil
+}
+
+// pendingOrigHead returns the HEAD state from before a stopped rebase,
+// cherry-pick or revert, falling back to the current HEAD.
+func pendingOrigHead(workDir string) headState {
+	head := currentHead(workDir)
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if hash := readGitPath(workDir, dir+"/orig-head"); hash != "" {
+			head.Hash = hash
+			head.Branch = strings.TrimPrefix(readGitPath(workDir, dir+"/head-name"), "refs/heads/")
+			return head
+		}
+	}
+	if hash := readGitPath(workDir, "sequencer/head"); hash != "" {
+		head.Hash = hash
+	}
+	return head
+}
+
+// readGitPath returns the trimmed contents of a file inside the git dir, or
+// "" if it can't be read.
+func readGitPath(workDir, name string) string {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// logOperation records op in the operation log if HEAD moved since before.
+// Operations that stopped part way (rebase or cherry-pick conflicts) are
+// recorded when they are continued to completion. Failures are ignored; the
+// log only annotates the reflog.
+func logOperation(workDir, op string, before headState) {
+	logOperationKind(workDir, "", op, before)
+}
+
+// logOperationKind is logOperation for records of the given kind.
+func logOperationKind(workDir, kind, op string, before headState) {
+	if IsRebaseInProgress(workDir) || SequencerInProgress(workDir) != "" {
+		return
+	}
+	after := currentHead(workDir)
+	if after == before || after.Hash == "" {
+		return
+	}
+	rec := OpRecord{
+		Time:         time.Now(),
+		Kind:         kind,
+		Op:           op,
+		Before:       before.Hash,
+		BeforeBranch: before.Branch,
+		After:        after.Hash,
+		AfterBranch:  after.Branch,
+	}
+	records, _ := ReadOpLog(workDir)
+	records = append(records, rec)
+	if len(records) > oplogMaxEntries {
+		records = records[len(records)-oplogMaxEntries:]
+	}
+	_ = writeOpLog(workDir, records)
+}
+
+// ReadOpLog returns recorded operations, oldest first.
+func ReadOpLog(workDir string) ([]OpRecord, error) {
+	path, err := oplogPath(workDir)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []OpRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec OpRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err == nil {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func writeOpLog(workDir string, records []OpRecord) error {
+	path, err := oplogPath(workDir)
+	if err != nil {
+		return err
+	}
+	var sb strings.Builder
+	for _, rec := range records {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// GetReflog returns the most recent HEAD reflog entries, newest first,
+// annotated with the sidecar operation that produced them where known.
+func GetReflog(workDir string, limit int) ([]*ReflogEntry, error) {
+	format := "%H%x00%h%x00%gd%x00%gs%x00%ct"
+	cmd := exec.Command("git", "reflog", "show", "--format="+format, "-n", strconv.Itoa(limit), "HEAD")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	// Latest operation per resulting commit
+	records, _ := ReadOpLog(workDir)
+	opsByHash := make(map[string]string, len(records))
+	for _, rec := range records {
+		if rec.Kind == "" || rec.Kind == opKindUndo {
+			opsByHash[rec.After] = rec.Op
+		}
+	}
+
+	var entries []*ReflogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Split(line, "\x00")
+		if len(parts) < 5 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(parts[4], 10, 64)
+		action, message := parts[3], ""
+		if idx := strings.Index(action, ": "); idx >= 0 {
+			action, message = action[:idx], action[idx+2:]
+		}
+		entry := &ReflogEntry{
+			Hash:      parts[0],
+			ShortHash: parts[1],
+			Selector:  parts[2],
+			Action:    action,
+			Message:   message,
+			Date:      time.Unix(timestamp, 0),
+		}
+		entries = append(entries, entry)
+	}
+
+	// Only the newest reflog entry for a commit gets the annotation, so a
+	// commit revisited later isn't attributed twice.
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if op, ok := opsByHash[e.Hash]; ok && !seen[e.Hash] {
+			e.SidecarOp = op
+		}
+		seen[e.Hash] = true
+	}
+	return entries, nil
+}
+
+// UndoPlan describes how HEAD will be restored by an undo.
+type UndoPlan struct {
+	Label    string    // What is being undone, e.g. "commit (amend)"
+	Target   string    // Commit HEAD will point at
+	Branch   string    // Branch to check out first, when the operation switched branches
+	Removed  []*Commit // Commits that leave the branch
+	Restored []*Commit // Commits that come back
+	Dirty    bool      // Working tree has uncommitted changes to tracked files
+	Files    []string  // Files put back, when undoing a discard or stash pop
+	Stash    string    // Stash commit put back in the stash list
+
+	record  *OpRecord // The discard or stash pop being undone
+	restore bool      // Moves to a chosen reflog entry; undoable itself
+}
+
+// ErrNothingToUndo is returned when there is no earlier HEAD state.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// PlanUndo plans undoing the most recent operation. A discard or stash pop
+// made since HEAD last moved puts the files back. A sidecar operation whose
+// result is still HEAD is undone as a whole (covering multi-step
+// operations like rebases); otherwise HEAD returns to HEAD@{1}. Operations
+// already undone are stepped over, so repeated undos keep going back.
+func PlanUndo(workDir string) (*UndoPlan, error) {
+	head := currentHead(workDir)
+	records, _ := ReadOpLog(workDir)
+
+	// Walk back from the record whose result is HEAD; each undo record
+	// cancels the operation before it
+	at, undone, stepped := head, 0, false
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if rec.After != at.Hash || rec.AfterBranch != at.Branch {
+			break
+		}
+		switch {
+		case rec.Kind == opKindUndo:
+			undone++
+		case undone > 0:
+			undone--
+		case rec.Kind != "":
+			return planFileUndo(rec), nil
+		default:
+			plan := &UndoPlan{Label: rec.Op, Target: rec.Before}
+			if rec.BeforeBranch != "" && rec.BeforeBranch != head.Branch {
+				plan.Branch = rec.BeforeBranch
+			}
+			return fillUndoPlan(workDir, plan, head)
+		}
+		at = headState{Hash: rec.Before, Branch: rec.BeforeBranch}
+		stepped = true
+	}
+	if stepped {
+		// HEAD@{1} would be the state just undone
+		return nil, ErrNothingToUndo
+	}
+
+	entries, err := GetReflog(workDir, 2)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) < 2 {
+		return nil, ErrNothingToUndo
+	}
+	plan := &UndoPlan{Label: entries[0].Action, Target: entries[1].Hash}
+	if entries[0].Action == "checkout" {
+		// "checkout: moving from A to B"
+		msg := strings.TrimPrefix(entries[0].Message, "moving from ")
+		if from, _, ok := strings.Cut(msg, " to "); ok && from != head.Branch && branchExists(workDir, from) {
+			plan.Branch = from
+		}
+	}
+	return fillUndoPlan(workDir, plan, head)
+}
+
+// PlanRestore plans moving HEAD back to the given reflog entry.
+func PlanRestore(workDir string, entry *ReflogEntry) (*UndoPlan, error) {
+	plan := &UndoPlan{Label: "restore " + entry.Selector, Target: entry.Hash, restore: true}
+	return fillUndoPlan(workDir, plan, currentHead(workDir))
+}
+
+// fillUndoPlan adds the commit lists and dirty state to a plan.
+func fillUndoPlan(workDir string, plan *UndoPlan, head headState) (*UndoPlan, error) {
+	if plan.Target == "" {
+		return nil, ErrNothingToUndo
+	}
+	// Commits are compared against the branch being restored
+	from := head.Hash
+	if plan.Branch != "" {
+		cmd := exec.Command("git", "rev-parse", plan.Branch)
+		cmd.Dir = workDir
+		if out, err := cmd.Output(); err == nil {
+			from = strings.TrimSpace(string(out))
+		}
+	}
+	var err error
+	if plan.Removed, err = GetCommitsBetween(workDir, plan.Target, from); err != nil {
+		return nil, err
+	}
+	if plan.Restored, err = GetCommitsBetween(workDir, from, plan.Target); err != nil {
+		return nil, err
+	}
+	plan.Dirty = hasTrackedChanges(workDir)
+	return plan, nil
+}
+
+// branchExists reports whether a local branch exists.
+func branchExists(workDir, name string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	cmd.Dir = workDir
+	return cmd.Run() == nil
+}
+
+// hasTrackedChanges reports whether tracked files have uncommitted changes.
+func hasTrackedChanges(workDir string) bool {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(out)) != ""
+}
+
+// ExecuteUndo applies an undo plan. It refuses to move HEAD on a dirty
+// working tree unless stash is set, in which case changes are stashed first
+// and left in the stash list.
+func ExecuteUndo(workDir string, plan *UndoPlan, stash bool) error {
+	if IsRebaseInProgress(workDir) || SequencerInProgress(workDir) != "" {
+		return errors.New("finish or abort the operation in progress first")
+	}
+	if plan.record != nil {
+		return executeFileUndo(workDir, plan)
+	}
+	if hasTrackedChanges(workDir) {
+		if !stash {
+			return errors.New("working tree has uncommitted changes; stash them first")
+		}
+		if err := StashPushWithMessage(workDir, "sidecar: before undo "+plan.Label); err != nil {
+			return fmt.Errorf("stash failed: %w", err)
+		}
+	}
+
+	before := currentHead(workDir)
+	if plan.Branch != "" {
+		if err := CheckoutBranch(workDir, plan.Branch); err != nil {
+			return err
+		}
+	}
+	// --keep refuses instead of overwriting untracked files in the way
+	cmd := exec.Command("git", "reset", "--keep", plan.Target)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	if plan.restore {
+		logOperation(workDir, plan.Label, before)
+	} else {
+		logOperationKind(workDir, opKindUndo, "undo "+plan.Label, before)
+	}
+	return nil
+}
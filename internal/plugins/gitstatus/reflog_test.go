@@ -0,0 +1,275 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogOperationAnnotatesReflog(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+
+	before := currentHead(dir)
+	logOperation(dir, "commit", before) // HEAD didn't move
+	if records, _ := ReadOpLog(dir); len(records) != 0 {
+		t.Fatalf("expected no record without a HEAD move, got %+v", records)
+	}
+
+	commitFile(t, dir, "a.txt", "a\n", "add a")
+	logOperation(dir, "commit", before)
+	records, err := ReadOpLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Op != "commit" || records[0].Before != before.Hash {
+		t.Fatalf("unexpected op log: %+v", records)
+	}
+
+	entries, err := GetReflog(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("expected at least 2 reflog entries, got %d", len(entries))
+	}
+	if entries[0].Action != "commit" || entries[0].Message != "add a" {
+		t.Errorf("entry = %q / %q", entries[0].Action, entries[0].Message)
+	}
+	if entries[0].SidecarOp != "commit" {
+		t.Errorf("SidecarOp = %q, want commit", entries[0].SidecarOp)
+	}
+	if entries[1].SidecarOp != "" {
+		t.Errorf("older entry should not be annotated, got %q", entries[1].SidecarOp)
+	}
+}
+
+func TestUndoAmend(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	original := gitRun(t, dir, "rev-parse", "HEAD")
+
+	before := currentHead(dir)
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "commit", "-q", "-a", "--amend", "-m", "amended")
+	logOperation(dir, "amend", before)
+
+	plan, err := PlanUndo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Label != "amend" || plan.Target != original {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if len(plan.Removed) != 1 || plan.Removed[0].Subject != "amended" {
+		t.Errorf("removed = %+v", plan.Removed)
+	}
+	if len(plan.Restored) != 1 {
+		t.Errorf("restored = %+v", plan.Restored)
+	}
+
+	if err := ExecuteUndo(dir, plan, false); err != nil {
+		t.Fatalf("ExecuteUndo: %v", err)
+	}
+	if head := gitRun(t, dir, "rev-parse", "HEAD"); head != original {
+		t.Errorf("HEAD = %s, want %s", head, original)
+	}
+
+	// The undo isn't undone in turn, which would redo the amend
+	if plan, err := PlanUndo(dir); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("plan after undo = %+v, %v; want nothing to undo", plan, err)
+	}
+}
+
+func TestUndoTwice(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	original := gitRun(t, dir, "rev-parse", "HEAD")
+	before := currentHead(dir)
+	commitFile(t, dir, "a.txt", "a\n", "add a")
+	logOperation(dir, "commit", before)
+	first := gitRun(t, dir, "rev-parse", "HEAD")
+	before = currentHead(dir)
+	commitFile(t, dir, "b.txt", "b\n", "add b")
+	logOperation(dir, "commit", before)
+
+	for _, want := range []string{first, original} {
+		plan, err := PlanUndo(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Label != "commit" || plan.Target != want {
+			t.Fatalf("plan = %+v, want target %s", plan, want)
+		}
+		if err := ExecuteUndo(dir, plan, false); err != nil {
+			t.Fatalf("ExecuteUndo: %v", err)
+		}
+		if head := gitRun(t, dir, "rev-parse", "HEAD"); head != want {
+			t.Fatalf("HEAD = %s, want %s", head, want)
+		}
+	}
+
+	if _, err := PlanUndo(dir); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("third undo: %v, want nothing to undo", err)
+	}
+	// The undos still annotate the reflog
+	if entries, _ := GetReflog(dir, 1); len(entries) != 1 || entries[0].SidecarOp != "undo commit" {
+		t.Errorf("reflog = %+v", entries)
+	}
+}
+
+func TestUndoRefusesDirtyTree(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	before := currentHead(dir)
+	commitFile(t, dir, "a.txt", "a\n", "add a")
+	logOperation(dir, "commit", before)
+
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("dirty\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := PlanUndo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Dirty {
+		t.Error("plan should report a dirty tree")
+	}
+	if err := ExecuteUndo(dir, plan, false); err == nil || !strings.Contains(err.Error(), "uncommitted") {
+		t.Fatalf("expected dirty tree refusal, got %v", err)
+	}
+	if head := gitRun(t, dir, "rev-parse", "HEAD"); head == before.Hash {
+		t.Fatal("HEAD should not move when undo is refused")
+	}
+
+	if err := ExecuteUndo(dir, plan, true); err != nil {
+		t.Fatalf("ExecuteUndo with stash: %v", err)
+	}
+	if head := gitRun(t, dir, "rev-parse", "HEAD"); head != before.Hash {
+		t.Errorf("HEAD = %s, want %s", head, before.Hash)
+	}
+	if stashes := gitRun(t, dir, "stash", "list"); !strings.Contains(stashes, "before undo commit") {
+		t.Errorf("expected stash entry, got %q", stashes)
+	}
+}
+
+func TestUndoCheckout(t *testing.T) {
+	dir, trunk := initBranchRepo(t)
+	gitRun(t, dir, "checkout", "-q", "agent")
+
+	plan, err := PlanUndo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Branch != trunk {
+		t.Fatalf("plan branch = %q, want %q", plan.Branch, trunk)
+	}
+	if err := ExecuteUndo(dir, plan, false); err != nil {
+		t.Fatalf("ExecuteUndo: %v", err)
+	}
+	if branch := gitRun(t, dir, "branch", "--show-current"); branch != trunk {
+		t.Errorf("branch = %q, want %q", branch, trunk)
+	}
+	if got := gitRun(t, dir, "log", "-1", "--format=%s", "agent"); got != "add b" {
+		t.Errorf("agent branch should be untouched, tip = %q", got)
+	}
+}
+
+func TestPlanUndoNothing(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	if _, err := PlanUndo(dir); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+}
+
+func TestUndoDiscard(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return string(data)
+	}
+	p := &Plugin{repoRoot: dir}
+
+	write("file.txt", "staged\n")
+	gitRun(t, dir, "add", "file.txt")
+	write("file.txt", "staged\nunstaged\n")
+	write("new.txt", "untracked\n")
+	p.doDiscard(&FileEntry{Path: "file.txt", Status: StatusModified, Staged: true})()
+	p.doDiscard(&FileEntry{Path: "new.txt", Status: StatusUntracked})()
+	if read("file.txt") != "one\n" || read("new.txt") != "" {
+		t.Fatalf("discard left file.txt = %q, new.txt = %q", read("file.txt"), read("new.txt"))
+	}
+
+	// The untracked file comes back first, then the staged and unstaged changes
+	plan, err := PlanUndo(dir)
+	if err != nil || plan.Label != "discard new.txt" || len(plan.Files) != 1 || plan.Dirty {
+		t.Fatalf("plan = %+v, %v", plan, err)
+	}
+	if err := ExecuteUndo(dir, plan, false); err != nil {
+		t.Fatal(err)
+	}
+	if read("new.txt") != "untracked\n" {
+		t.Errorf("new.txt = %q", read("new.txt"))
+	}
+	plan, err = PlanUndo(dir)
+	if err != nil || plan.Label != "discard file.txt" {
+		t.Fatalf("plan = %+v, %v", plan, err)
+	}
+	if err := ExecuteUndo(dir, plan, false); err != nil {
+		t.Fatal(err)
+	}
+	if read("file.txt") != "staged\nunstaged\n" || gitRun(t, dir, "show", ":file.txt") != "staged" {
+		t.Errorf("file.txt = %q, index = %q", read("file.txt"), gitRun(t, dir, "show", ":file.txt"))
+	}
+	if _, err := PlanUndo(dir); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("undone discards should leave nothing to undo, got %v", err)
+	}
+
+	// A file edited after the discard is not overwritten
+	p.doDiscard(&FileEntry{Path: "file.txt", Status: StatusModified, Staged: true})()
+	write("file.txt", "edited\n")
+	if plan, err = PlanUndo(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExecuteUndo(dir, plan, false); err == nil || !strings.Contains(err.Error(), "file.txt has changed since the discard") {
+		t.Errorf("err = %v", err)
+	}
+	if read("file.txt") != "edited\n" {
+		t.Errorf("file.txt = %q", read("file.txt"))
+	}
+}
+
+func TestUndoStashPop(t *testing.T) {
+	dir := initStashRepo(t)
+	stash := gitRun(t, dir, "rev-parse", "stash@{0}")
+	p := &Plugin{repoRoot: dir}
+	if msg := p.doStashPop("stash@{0}")().(StashResultMsg); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	if gitRun(t, dir, "stash", "list") != "" {
+		t.Fatal("pop should drop the stash")
+	}
+
+	plan, err := PlanUndo(dir)
+	if err != nil || plan.Label != "stash pop stash@{0}" || plan.Stash != stash || len(plan.Files) != 2 {
+		t.Fatalf("plan = %+v, %v", plan, err)
+	}
+	if err := ExecuteUndo(dir, plan, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitRun(t, dir, "rev-parse", "stash@{0}"); got != stash {
+		t.Errorf("stash@{0} = %s, want %s", got, stash)
+	}
+	if list, _ := GetStashList(dir); list.Count() != 1 || list.Stashes[0].Message != "wip" {
+		t.Errorf("stashes = %+v", list.Stashes)
+	}
+	if status := gitRun(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("undo should remove the popped changes, status = %q", status)
+	}
+}
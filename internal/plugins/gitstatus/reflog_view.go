@@ -0,0 +1,529 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	reflogItemPrefix = "reflog-item-"
+	reflogLimit      = 100
+	undoConfirmID    = "undo-confirm"
+	undoStashID      = "undo-stash"
+)
+
+func reflogItemID(idx int) string {
+	return fmt.Sprintf("%s%d", reflogItemPrefix, idx)
+}
+
+func parseReflogItem(id string) (int, bool) {
+	if !strings.HasPrefix(id, reflogItemPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, reflogItemPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// ReflogLoadedMsg is sent when the reflog has been read.
+type ReflogLoadedMsg struct {
+	Epoch   uint64
+	Entries []*ReflogEntry
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ReflogLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// UndoPlanMsg carries a planned undo or restore for confirmation.
+type UndoPlanMsg struct {
+	Plan *UndoPlan
+	Err  error
+}
+
+// UndoDoneMsg is sent when an undo or restore finishes.
+type UndoDoneMsg struct {
+	Label   string
+	Stashed bool
+	Err     error
+}
+
+// openReflog opens the reflog browser.
+func (p *Plugin) openReflog() tea.Cmd {
+	p.reflogEntries = nil
+	p.reflogLoaded = false
+	p.reflogCursor = 0
+	p.clearReflogModal()
+	p.viewMode = ViewModeReflog
+	return p.loadReflog()
+}
+
+// loadReflog reads recent HEAD movements.
+func (p *Plugin) loadReflog() tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		entries, err := GetReflog(workDir, reflogLimit)
+		return ReflogLoadedMsg{Epoch: epoch, Entries: entries, Err: err}
+	}
+}
+
+// handleReflogLoaded stores loaded reflog entries.
+func (p *Plugin) handleReflogLoaded(msg ReflogLoadedMsg) tea.Cmd {
+	if p.viewMode != ViewModeReflog {
+		return nil
+	}
+	if msg.Err != nil {
+		p.closeReflog()
+		p.showErrorModal("Reflog Failed", msg.Err)
+		return nil
+	}
+	p.reflogEntries = msg.Entries
+	p.reflogLoaded = true
+	if p.reflogCursor >= len(p.reflogEntries) {
+		p.reflogCursor = max(len(p.reflogEntries)-1, 0)
+	}
+	return nil
+}
+
+// updateReflog handles key events in the reflog browser.
+func (p *Plugin) updateReflog(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureReflogModal()
+	if p.reflogModal == nil {
+		return p, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		p.closeReflog()
+		return p, nil
+
+	case "j", "down":
+		p.moveReflogCursor(1)
+		return p, nil
+
+	case "k", "up":
+		p.moveReflogCursor(-1)
+		return p, nil
+
+	case "g":
+		p.reflogCursor = 0
+		return p, nil
+
+	case "G":
+		if len(p.reflogEntries) > 0 {
+			p.reflogCursor = len(p.reflogEntries) - 1
+		}
+		return p, nil
+
+	case "u":
+		// Undo the last operation
+		return p, p.planUndo()
+
+	case "enter":
+		// Restore HEAD to the selected entry
+		return p, p.planRestore(p.reflogCursor)
+	}
+
+	action, cmd := p.reflogModal.HandleKey(msg)
+	if action == "cancel" {
+		p.closeReflog()
+		return p, nil
+	}
+	if idx, ok := parseReflogItem(action); ok {
+		return p, p.planRestore(idx)
+	}
+	return p, cmd
+}
+
+func (p *Plugin) moveReflogCursor(delta int) {
+	if len(p.reflogEntries) == 0 {
+		return
+	}
+	p.reflogCursor = min(max(p.reflogCursor+delta, 0), len(p.reflogEntries)-1)
+}
+
+func (p *Plugin) closeReflog() {
+	p.viewMode = ViewModeStatus
+	p.reflogEntries = nil
+	p.reflogLoaded = false
+	p.clearReflogModal()
+}
+
+func (p *Plugin) clearReflogModal() {
+	p.reflogModal = nil
+	p.reflogModalWidth = 0
+}
+
+// planUndo plans undoing the last operation.
+func (p *Plugin) planUndo() tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		plan, err := PlanUndo(workDir)
+		return UndoPlanMsg{Plan: plan, Err: err}
+	}
+}
+
+// planRestore plans moving HEAD back to a reflog entry.
+func (p *Plugin) planRestore(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(p.reflogEntries) {
+		return nil
+	}
+	p.reflogCursor = idx
+	if idx == 0 {
+		return nil // Already at this state
+	}
+	entry := p.reflogEntries[idx]
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		plan, err := PlanRestore(workDir, entry)
+		return UndoPlanMsg{Plan: plan, Err: err}
+	}
+}
+
+// handleUndoPlan shows the undo confirmation for a planned undo.
+func (p *Plugin) handleUndoPlan(msg UndoPlanMsg) tea.Cmd {
+	if msg.Err != nil {
+		if errors.Is(msg.Err, ErrNothingToUndo) {
+			return func() tea.Msg {
+				return app.ToastMsg{Message: "Nothing to undo", Duration: 2 * time.Second}
+			}
+		}
+		p.showErrorModal("Undo Failed", msg.Err)
+		return nil
+	}
+	p.undoPlan = msg.Plan
+	p.undoReturnMode = p.viewMode
+	p.clearUndoModal()
+	p.viewMode = ViewModeConfirmUndo
+	return nil
+}
+
+// updateConfirmUndo handles key events in the undo confirmation modal.
+func (p *Plugin) updateConfirmUndo(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureUndoModal()
+	if p.undoModal == nil {
+		return p, nil
+	}
+
+	switch msg.String() {
+	case "y":
+		return p, p.handleUndoAction(p.undoPrimaryAction())
+	case "q":
+		p.closeConfirmUndo()
+		return p, nil
+	}
+
+	action, cmd := p.undoModal.HandleKey(msg)
+	return p, tea.Batch(cmd, p.handleUndoAction(action))
+}
+
+// undoPrimaryAction returns the confirm action, which stashes first when the
+// working tree is dirty.
+func (p *Plugin) undoPrimaryAction() string {
+	if p.undoPlan != nil && p.undoPlan.Dirty {
+		return undoStashID
+	}
+	return undoConfirmID
+}
+
+// handleUndoAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleUndoAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeConfirmUndo()
+	case undoConfirmID:
+		return p.doUndo(false)
+	case undoStashID:
+		return p.doUndo(true)
+	}
+	return nil
+}
+
+// closeConfirmUndo returns to the view the undo was started from.
+func (p *Plugin) closeConfirmUndo() {
+	p.viewMode = p.undoReturnMode
+	p.undoPlan = nil
+	p.clearUndoModal()
+}
+
+func (p *Plugin) clearUndoModal() {
+	p.undoModal = nil
+	p.undoModalWidth = 0
+}
+
+// doUndo executes the confirmed undo.
+func (p *Plugin) doUndo(stash bool) tea.Cmd {
+	plan := p.undoPlan
+	if plan == nil {
+		return nil
+	}
+	workDir := p.repoRoot
+	p.undoPlan = nil
+	p.clearUndoModal()
+	p.closeReflog()
+
+	return func() tea.Msg {
+		err := ExecuteUndo(workDir, plan, stash)
+		return UndoDoneMsg{Label: plan.Label, Stashed: stash && plan.Dirty, Err: err}
+	}
+}
+
+// handleUndoDone reports the outcome of an undo and refreshes.
+func (p *Plugin) handleUndoDone(msg UndoDoneMsg) tea.Cmd {
+	refresh := tea.Batch(p.refresh(), p.loadRecentCommits())
+	if msg.Err != nil {
+		p.showErrorModal("Undo Failed", msg.Err)
+		return refresh
+	}
+	toast := "Undid " + msg.Label
+	if rest, ok := strings.CutPrefix(msg.Label, "restore "); ok {
+		toast = "Restored " + rest
+	}
+	if msg.Stashed {
+		toast += " (changes stashed)"
+	}
+	return tea.Batch(refresh, func() tea.Msg {
+		return app.ToastMsg{Message: toast, Duration: 3 * time.Second}
+	})
+}
+
+// reflogModalWidthForContent returns the modal width for the screen size.
+func (p *Plugin) reflogModalWidthForContent() int {
+	modalW := ui.ModalWidthLarge
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < 30 {
+		modalW = 30
+	}
+	return modalW
+}
+
+// ensureReflogModal builds/rebuilds the reflog browser modal.
+func (p *Plugin) ensureReflogModal() {
+	modalW := p.reflogModalWidthForContent()
+	if p.reflogModal != nil && p.reflogModalWidth == modalW {
+		return
+	}
+	p.reflogModalWidth = modalW
+
+	p.reflogModal = modal.New("Reflog",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.reflogListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			return modal.RenderedSection{Content: styles.Muted.Render("  u undo last operation, Enter restore to entry, Esc close")}
+		}, nil))
+}
+
+func (p *Plugin) reflogMaxVisible() int {
+	return max(min(15, p.height-10), 5)
+}
+
+func (p *Plugin) reflogListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if !p.reflogLoaded {
+			return modal.RenderedSection{Content: styles.Muted.Render("  Loading reflog...")}
+		}
+		if len(p.reflogEntries) == 0 {
+			return modal.RenderedSection{Content: styles.Muted.Render("  No reflog entries")}
+		}
+
+		maxVisible := p.reflogMaxVisible()
+		start := 0
+		if p.reflogCursor >= maxVisible {
+			start = p.reflogCursor - maxVisible + 1
+		}
+		end := min(start+maxVisible, len(p.reflogEntries))
+
+		var sb strings.Builder
+		focusables := make([]modal.FocusableInfo, 0, end-start)
+		for i := start; i < end; i++ {
+			itemID := reflogItemID(i)
+			line := p.renderReflogLine(p.reflogEntries[i], contentWidth, i == p.reflogCursor || itemID == hoverID)
+			if i > start {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(line)
+			focusables = append(focusables, modal.FocusableInfo{
+				ID:      itemID,
+				OffsetY: i - start,
+				Width:   ansi.StringWidth(line),
+				Height:  1,
+			})
+		}
+
+		content := sb.String()
+		if len(p.reflogEntries) > maxVisible {
+			content += "\n\n" + styles.Muted.Render(fmt.Sprintf("  %d/%d entries", p.reflogCursor+1, len(p.reflogEntries)))
+		}
+		return modal.RenderedSection{Content: content, Focusables: focusables}
+	}, nil)
+}
+
+// renderReflogLine renders one reflog entry: selector, hash, action, message,
+// the sidecar operation that caused it and its age.
+func (p *Plugin) renderReflogLine(e *ReflogEntry, width int, selected bool) string {
+	age := RelativeTime(e.Date)
+	op := ""
+	if e.SidecarOp != "" {
+		op = "sidecar: " + e.SidecarOp
+	}
+	prefix := fmt.Sprintf("  %-10s %s ", e.Selector, e.ShortHash)
+	text := e.Action
+	if e.Message != "" {
+		text += ": " + e.Message
+	}
+	// Reserve room for the op tag and age on the right
+	suffix := age
+	if op != "" {
+		suffix = op + "  " + age
+	}
+	textW := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(suffix)-2, 10)
+	text = ansi.Truncate(text, textW, "…")
+	pad := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(text)-ansi.StringWidth(suffix)-1, 1)
+
+	if selected {
+		return styles.ListItemSelected.Render(prefix + text + strings.Repeat(" ", pad) + suffix)
+	}
+	styledSuffix := styles.Muted.Render(age)
+	if op != "" {
+		styledSuffix = styles.StatusStaged.Render(op) + "  " + styledSuffix
+	}
+	return styles.ListItemNormal.Render(
+		styles.Muted.Render(fmt.Sprintf("  %-10s ", e.Selector)) + styles.Code.Render(e.ShortHash) + " " +
+			text + strings.Repeat(" ", pad) + styledSuffix)
+}
+
+// renderReflog renders the reflog browser.
+func (p *Plugin) renderReflog() string {
+	background := p.renderThreePaneView()
+
+	p.ensureReflogModal()
+	if p.reflogModal == nil {
+		return background
+	}
+
+	modalContent := p.reflogModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// ensureUndoModal builds/rebuilds the undo confirmation modal.
+func (p *Plugin) ensureUndoModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.undoModal != nil && p.undoModalWidth == modalW {
+		return
+	}
+	p.undoModalWidth = modalW
+	if p.undoPlan == nil {
+		return
+	}
+
+	title := "Undo " + p.undoPlan.Label
+	if strings.HasPrefix(p.undoPlan.Label, "restore ") {
+		title = strings.ToUpper(p.undoPlan.Label[:1]) + p.undoPlan.Label[1:]
+	}
+	confirmBtn := modal.Btn(" Undo ", undoConfirmID, modal.BtnDanger())
+	if p.undoPlan.Dirty {
+		confirmBtn = modal.Btn(" Stash & Undo ", undoStashID, modal.BtnDanger())
+	}
+
+	p.undoModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantDanger),
+		modal.WithPrimaryAction(p.undoPrimaryAction()),
+		modal.WithHints(false),
+	).
+		AddSection(p.undoSummarySection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(confirmBtn, modal.Btn(" Cancel ", "cancel")))
+}
+
+// undoSummarySection describes how HEAD and the branch will change.
+func (p *Plugin) undoSummarySection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		plan := p.undoPlan
+		if plan == nil {
+			return modal.RenderedSection{}
+		}
+		var sb strings.Builder
+		if plan.record != nil {
+			writeFileUndoSummary(&sb, plan, contentWidth)
+			return modal.RenderedSection{Content: strings.TrimRight(sb.String(), "\n")}
+		}
+		target := plan.Target
+		if len(target) > 7 {
+			target = target[:7]
+		}
+		if plan.Branch != "" {
+			fmt.Fprintf(&sb, "Switch back to %s and move it to %s.\n", styles.Code.Render(plan.Branch), styles.Code.Render(target))
+		} else {
+			fmt.Fprintf(&sb, "Move HEAD to %s.\n", styles.Code.Render(target))
+		}
+
+		if len(plan.Removed) > 0 {
+			sb.WriteString("\n" + styles.Muted.Render(fmt.Sprintf("%d commit(s) leave the branch:", len(plan.Removed))) + "\n")
+			writeCommitLines(&sb, plan.Removed, contentWidth)
+		}
+		if len(plan.Restored) > 0 {
+			sb.WriteString("\n" + styles.Muted.Render(fmt.Sprintf("%d commit(s) come back:", len(plan.Restored))) + "\n")
+			writeCommitLines(&sb, plan.Restored, contentWidth)
+		}
+		if len(plan.Removed) > 0 {
+			sb.WriteString("\n" + styles.Muted.Render("Removed commits stay in the reflog and can be restored.") + "\n")
+		}
+
+		if plan.Dirty {
+			sb.WriteString("\n" + styles.StatusModified.Render("The working tree has uncommitted changes. They will be stashed first.") + "\n")
+		}
+		return modal.RenderedSection{Content: strings.TrimRight(sb.String(), "\n")}
+	}, nil)
+}
+
+// writeFileUndoSummary lists the files an undone discard or stash pop
+// puts back.
+func writeFileUndoSummary(sb *strings.Builder, plan *UndoPlan, width int) {
+	if plan.Stash != "" {
+		fmt.Fprintf(sb, "Put stash %s back in the stash list and remove its changes.\n", styles.Code.Render(plan.Stash[:min(7, len(plan.Stash))]))
+	} else {
+		sb.WriteString("Put back the discarded changes.\n")
+	}
+	sb.WriteString("\n" + styles.Muted.Render(fmt.Sprintf("%d file(s) restored:", len(plan.Files))) + "\n")
+	const maxFiles = 10
+	for i, path := range plan.Files {
+		if i == maxFiles {
+			sb.WriteString(styles.Muted.Render(fmt.Sprintf("  … and %d more", len(plan.Files)-maxFiles)) + "\n")
+			break
+		}
+		sb.WriteString("  " + ansi.Truncate(path, max(width-2, 10), "…") + "\n")
+	}
+}
+
+// renderConfirmUndo renders the undo confirmation modal.
+func (p *Plugin) renderConfirmUndo() string {
+	background := p.renderThreePaneView()
+
+	p.ensureUndoModal()
+	if p.undoModal == nil {
+		return background
+	}
+
+	modalContent := p.undoModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
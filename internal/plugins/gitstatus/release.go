@@ -0,0 +1,230 @@
+package gitstatus
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ConventionalCommit is a commit subject in the "type(scope)!: description"
+// form of the Conventional Commits spec.
+type ConventionalCommit struct {
+	Type        string // Lower-cased, e.g. "feat"
+	Scope       string
+	Breaking    bool // "!" after the type, or a BREAKING CHANGE footer
+	Description string
+}
+
+var conventionalRe = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+
+// ParseConventionalCommit parses a commit subject. ok is false when the
+// subject doesn't follow the convention.
+func ParseConventionalCommit(subject string) (cc ConventionalCommit, ok bool) {
+	m := conventionalRe.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return cc, false
+	}
+	return ConventionalCommit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       m[2],
+		Breaking:    m[3] == "!",
+		Description: m[4],
+	}, true
+}
+
+// hasBreakingFooter reports whether a commit body has a BREAKING CHANGE footer.
+func hasBreakingFooter(body string) bool {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseSections maps commit types to release note sections, in display
+// order. Types not listed fall under "Other".
+var releaseSections = []struct {
+	Title string
+	Types []string
+}{
+	{"Features", []string{"feat"}},
+	{"Fixes", []string{"fix"}},
+	{"Performance", []string{"perf"}},
+	{"Refactoring", []string{"refactor"}},
+	{"Documentation", []string{"docs"}},
+	{"Tests", []string{"test"}},
+	{"Build & CI", []string{"build", "ci"}},
+	{"Chores", []string{"chore", "style"}},
+	{"Reverts", []string{"revert"}},
+}
+
+// ReleaseCommit is a commit in a release, with its parsed subject.
+type ReleaseCommit struct {
+	Hash         string
+	ShortHash    string
+	Subject      string
+	Conventional ConventionalCommit
+	IsConv       bool // Subject follows the conventional format
+}
+
+// ReleaseGroup is the commits of one release note section.
+type ReleaseGroup struct {
+	Title   string
+	Commits []*ReleaseCommit
+}
+
+// ReleaseChanges is the history since a tag, grouped by change type.
+type ReleaseChanges struct {
+	Since  string // Tag the changes are counted from, "" for the whole history
+	Total  int
+	Groups []ReleaseGroup
+	Bump   string // "major", "minor" or "patch"
+}
+
+// GetReleaseChanges returns the non-merge commits on HEAD since the tag
+// since, or since the latest tag when since is empty.
+func GetReleaseChanges(workDir, since string) (*ReleaseChanges, error) {
+	if since == "" {
+		since = LatestTag(workDir)
+	}
+	args := []string{"log", "--no-merges", "--format=%H%x00%h%x00%s%x00%b%x1e"}
+	if since != "" {
+		args = append(args, since+"..HEAD")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*ReleaseCommit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		rc := &ReleaseCommit{Hash: parts[0], ShortHash: parts[1], Subject: parts[2]}
+		rc.Conventional, rc.IsConv = ParseConventionalCommit(rc.Subject)
+		if rc.IsConv && hasBreakingFooter(parts[3]) {
+			rc.Conventional.Breaking = true
+		}
+		commits = append(commits, rc)
+	}
+
+	groups, bump := GroupReleaseCommits(commits)
+	return &ReleaseChanges{Since: since, Total: len(commits), Groups: groups, Bump: bump}, nil
+}
+
+// GroupReleaseCommits groups commits into release note sections, with
+// breaking changes first, and returns the semver bump they call for.
+func GroupReleaseCommits(commits []*ReleaseCommit) ([]ReleaseGroup, string) {
+	sectionOf := make(map[string]int)
+	for i, s := range releaseSections {
+		for _, t := range s.Types {
+			sectionOf[t] = i
+		}
+	}
+
+	var breaking, other []*ReleaseCommit
+	bySection := make([][]*ReleaseCommit, len(releaseSections))
+	bump := "patch"
+	for _, c := range commits {
+		if !c.IsConv {
+			other = append(other, c)
+			continue
+		}
+		if c.Conventional.Breaking {
+			breaking = append(breaking, c)
+			bump = "major"
+			continue
+		}
+		if c.Conventional.Type == "feat" && bump == "patch" {
+			bump = "minor"
+		}
+		if i, ok := sectionOf[c.Conventional.Type]; ok {
+			bySection[i] = append(bySection[i], c)
+		} else {
+			other = append(other, c)
+		}
+	}
+
+	var groups []ReleaseGroup
+	if len(breaking) > 0 {
+		groups = append(groups, ReleaseGroup{Title: "Breaking Changes", Commits: breaking})
+	}
+	for i, s := range releaseSections {
+		if len(bySection[i]) > 0 {
+			groups = append(groups, ReleaseGroup{Title: s.Title, Commits: bySection[i]})
+		}
+	}
+	if len(other) > 0 {
+		groups = append(groups, ReleaseGroup{Title: "Other", Commits: other})
+	}
+	return groups, bump
+}
+
+// NextVersion bumps a semver tag such as "v1.4.2". Returns "" when tag isn't
+// a semver version.
+func NextVersion(tag, bump string) string {
+	prefix := ""
+	version := tag
+	if strings.HasPrefix(version, "v") {
+		prefix, version = "v", version[1:]
+	}
+	// Drop pre-release and build suffixes
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return ""
+		}
+		nums[i] = n
+	}
+	switch bump {
+	case "major":
+		nums = []int{nums[0] + 1, 0, 0}
+	case "minor":
+		nums = []int{nums[0], nums[1] + 1, 0}
+	default:
+		nums[2]++
+	}
+	return fmt.Sprintf("%s%d.%d.%d", prefix, nums[0], nums[1], nums[2])
+}
+
+// Markdown renders the changes as release notes.
+func (rc *ReleaseChanges) Markdown() string {
+	var sb strings.Builder
+	for i, g := range rc.Groups {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "### %s\n\n", g.Title)
+		for _, c := range g.Commits {
+			fmt.Fprintf(&sb, "- %s (%s)\n", c.releaseLine(), c.ShortHash)
+		}
+	}
+	return sb.String()
+}
+
+// releaseLine returns the commit's description with its scope, or the raw
+// subject for commits that aren't conventional.
+func (c *ReleaseCommit) releaseLine() string {
+	if !c.IsConv {
+		return c.Subject
+	}
+	if c.Conventional.Scope != "" {
+		return "**" + c.Conventional.Scope + ":** " + c.Conventional.Description
+	}
+	return c.Conventional.Description
+}
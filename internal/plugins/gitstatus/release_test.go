@@ -0,0 +1,119 @@
+package gitstatus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		subject string
+		ok      bool
+		want    ConventionalCommit
+	}{
+		{"feat: add tags", true, ConventionalCommit{Type: "feat", Description: "add tags"}},
+		{"fix(git): handle detached HEAD", true, ConventionalCommit{Type: "fix", Scope: "git", Description: "handle detached HEAD"}},
+		{"Feat(api)!: drop v1", true, ConventionalCommit{Type: "feat", Scope: "api", Breaking: true, Description: "drop v1"}},
+		{"refactor!: rename", true, ConventionalCommit{Type: "refactor", Breaking: true, Description: "rename"}},
+		{"Update README", false, ConventionalCommit{}},
+		{"feat:missing space", false, ConventionalCommit{}},
+		{"fix(a(b)): nested", false, ConventionalCommit{}},
+	}
+	for _, tt := range tests {
+		got, ok := ParseConventionalCommit(tt.subject)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseConventionalCommit(%q) = %+v, %v; want %+v, %v", tt.subject, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func releaseCommits(subjects ...string) []*ReleaseCommit {
+	commits := make([]*ReleaseCommit, len(subjects))
+	for i, s := range subjects {
+		c := &ReleaseCommit{Subject: s, ShortHash: "abc123" + string(rune('0'+i))}
+		c.Conventional, c.IsConv = ParseConventionalCommit(s)
+		commits[i] = c
+	}
+	return commits
+}
+
+func TestGroupReleaseCommits(t *testing.T) {
+	tests := []struct {
+		name     string
+		subjects []string
+		titles   []string
+		bump     string
+	}{
+		{"fixes only", []string{"fix: a", "chore: b"}, []string{"Fixes", "Chores"}, "patch"},
+		{"feature", []string{"docs: a", "feat: b", "fix: c"}, []string{"Features", "Fixes", "Documentation"}, "minor"},
+		{"breaking", []string{"feat!: a", "feat: b"}, []string{"Breaking Changes", "Features"}, "major"},
+		{"other", []string{"Merge stuff", "wip: x", "ci: y"}, []string{"Build & CI", "Other"}, "patch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, bump := GroupReleaseCommits(releaseCommits(tt.subjects...))
+			var titles []string
+			for _, g := range groups {
+				titles = append(titles, g.Title)
+			}
+			if !sameLines(titles, tt.titles) || bump != tt.bump {
+				t.Errorf("groups %q bump %q, want %q bump %q", titles, bump, tt.titles, tt.bump)
+			}
+		})
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		tag, bump, want string
+	}{
+		{"v1.4.2", "patch", "v1.4.3"},
+		{"v1.4.2", "minor", "v1.5.0"},
+		{"v1.4.2", "major", "v2.0.0"},
+		{"0.9.1", "minor", "0.10.0"},
+		{"v2.0.0-rc.1", "patch", "v2.0.1"},
+		{"release-7", "patch", ""},
+		{"v1.2", "patch", ""},
+	}
+	for _, tt := range tests {
+		if got := NextVersion(tt.tag, tt.bump); got != tt.want {
+			t.Errorf("NextVersion(%q, %q) = %q, want %q", tt.tag, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestGetReleaseChanges(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	gitRun(t, dir, "tag", "v0.1.0")
+	commitFile(t, dir, "file.txt", "two\n", "fix(ui): wrap long lines")
+	commitFile(t, dir, "file.txt", "three\n", "feat: add tag badges")
+	commitFile(t, dir, "file.txt", "four\n", "Tidy up")
+
+	changes, err := GetReleaseChanges(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes.Since != "v0.1.0" || changes.Total != 3 || changes.Bump != "minor" {
+		t.Fatalf("changes = since %q, total %d, bump %q", changes.Since, changes.Total, changes.Bump)
+	}
+	if next := NextVersion(changes.Since, changes.Bump); next != "v0.2.0" {
+		t.Errorf("next version = %q, want v0.2.0", next)
+	}
+
+	notes := changes.Markdown()
+	for _, want := range []string{"### Features\n\n- add tag badges (", "### Fixes\n\n- **ui:** wrap long lines (", "### Other\n\n- Tidy up ("} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("release notes missing %q:\n%s", want, notes)
+		}
+	}
+
+	// A body footer marks the change as breaking
+	gitRun(t, dir, "commit", "-q", "--allow-empty", "-m", "refactor: drop old config", "-m", "BREAKING CHANGE: config v1 is gone")
+	changes, err = GetReleaseChanges(dir, "v0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes.Bump != "major" || changes.Groups[0].Title != "Breaking Changes" {
+		t.Errorf("bump %q, first group %q", changes.Bump, changes.Groups[0].Title)
+	}
+}
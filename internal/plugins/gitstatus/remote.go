@@ -3,6 +3,7 @@ package gitstatus
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -104,28 +105,28 @@ func AbortRebase(workDir string) error {
 	cmd := exec.Command("git", "rebase", "--abort")
 	cmd.Dir = workDir
 	_, err := cmd.CombinedOutput()
+	cleanupRebaseFiles(workDir)
 	return err
 }
 
 // IsRebaseInProgress checks if a rebase is currently in progress.
 func IsRebaseInProgress(workDir string) bool {
-	cmd := exec.Command("git", "rev-parse", "--git-path", "rebase-merge")
+	return gitPathExists(workDir, "rebase-merge") || gitPathExists(workDir, "rebase-apply")
+}
+
+// gitPathExists reports whether a path inside the git dir exists.
+func gitPathExists(workDir, name string) bool {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
 	cmd.Dir = workDir
 	output, err := cmd.Output()
 	if err != nil {
 		return false
 	}
+	// --git-path is relative to workDir unless the git dir is elsewhere
 	path := strings.TrimSpace(string(output))
-	if _, err := os.Stat(path); err == nil {
-		return true
-	}
-	cmd = exec.Command("git", "rev-parse", "--git-path", "rebase-apply")
-	cmd.Dir = workDir
-	output, err = cmd.Output()
-	if err != nil {
-		return false
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
 	}
-	path = strings.TrimSpace(string(output))
 	_, err = os.Stat(path)
 	return err == nil
 }
@@ -121,11 +121,19 @@ func (p *Plugin) renderSidebar(visibleHeight int) string {
 
 	// Header with branch name (truncated to fit sidebar)
 	header := styles.Title.Render("Git")
+	// Inside a submodule, show its path below the top-level repo
+	subPath := p.submodulePath()
+	if subPath != "" {
+		header += " " + styles.Subtitle.Render(subPath)
+	}
 	if p.pushStatus != nil {
 		if p.pushStatus.CurrentBranch != "" {
 			branch := p.pushStatus.CurrentBranch
 			// "Git " = 4 chars, leave 4 for padding = max branch length is sidebarWidth - 8
 			maxLen := p.sidebarWidth - 8
+			if subPath != "" {
+				maxLen -= len(subPath) + 1
+			}
 			if maxLen > 0 && len(branch) > maxLen {
 				branch = branch[:maxLen-1] + "…"
 			}
@@ -364,22 +372,28 @@ func (p *Plugin) renderSidebarEntry(entry *FileEntry, selected bool, maxWidth in
 		return styles.ListItemNormal.Render(fmt.Sprintf("%s %s%s %s", status, indicator, displayName, styles.Muted.Render(countStr)))
 	}
 
+	// Submodules are marked with their drift and dirty state
+	marker := ""
+	if entry.Submodule != nil {
+		marker = " " + submoduleMarker(entry.Submodule)
+	}
+
 	// Path - truncate if needed
 	path := entry.Path
-	availableWidth := maxWidth - 2 // status + space
+	availableWidth := maxWidth - 2 - len(marker) // status + space + marker
 	if len(path) > availableWidth && availableWidth > 3 {
 		path = "…" + path[len(path)-availableWidth+1:]
 	}
 
 	if selected {
-		plainLine := fmt.Sprintf("%s %s", string(entry.Status), path)
+		plainLine := fmt.Sprintf("%s %s%s", string(entry.Status), path, marker)
 		if len(plainLine) < maxWidth {
 			plainLine += strings.Repeat(" ", maxWidth-len(plainLine))
 		}
 		return styles.ListItemSelected.Render(plainLine)
 	}
 
-	return styles.ListItemNormal.Render(fmt.Sprintf("%s %s", status, path))
+	return styles.ListItemNormal.Render(fmt.Sprintf("%s %s%s", status, path, styles.Muted.Render(marker)))
 }
 
 // renderRecentCommits renders the recent commits section in the sidebar.
@@ -482,9 +496,12 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 			graphVisualWidth = graphWidth
 		}
 
-		// Push indicator: ↑ for unpushed, nothing for pushed
+		// Push indicator: ↑ for unpushed, nothing for pushed; bisect marks take its place
 		var indicator string
-		if !commit.Pushed {
+		bisectPlain, bisectMark := p.bisectMarker(commit.Hash)
+		if bisectMark != "" {
+			indicator = bisectMark + " "
+		} else if !commit.Pushed {
 			indicator = styles.StatusModified.Render("↑") + " "
 		} else {
 			indicator = "  " // Two spaces to align with indicator
@@ -492,7 +509,9 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 
 		// Format: "[graph] ↑ abc1234 commit message..."
 		hash := styles.Code.Render(commit.Hash[:7])
-		msgWidth := maxWidth - 12 - graphVisualWidth // indicator + hash + space + graph
+		badgePlain := renderTagBadge(commit.Tags, false)
+		sigPlain := signatureMark(commit.Signature, false)
+		msgWidth := maxWidth - 12 - graphVisualWidth - lipgloss.Width(badgePlain) - lipgloss.Width(sigPlain) // indicator + hash + space + graph + tags + signature
 		if msgWidth < 10 {
 			msgWidth = 10
 		}
@@ -507,7 +526,9 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 
 		if selected {
 			plainIndicator := "  "
-			if !commit.Pushed {
+			if bisectPlain != "" {
+				plainIndicator = bisectPlain + " "
+			} else if !commit.Pushed {
 				plainIndicator = "↑ "
 			}
 			// For selected lines, include graph prefix without styling (will be styled by selection)
@@ -515,7 +536,7 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 			if graphStr != "" {
 				graphPlain = p.renderGraphLinePlain(p.commitGraphLines[i], graphWidth)
 			}
-			plainLine := fmt.Sprintf("%s%s%s %s", graphPlain, plainIndicator, commit.Hash[:7], msg)
+			plainLine := fmt.Sprintf("%s%s%s %s%s%s", graphPlain, plainIndicator, commit.Hash[:7], sigPlain, badgePlain, msg)
 			// Pad to full width
 			lineWidth := lipgloss.Width(plainLine)
 			if lineWidth < maxWidth {
@@ -523,7 +544,7 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 			}
 			commitsSB.WriteString(styles.ListItemSelected.Render(plainLine))
 		} else {
-			line := fmt.Sprintf("%s%s%s %s", graphStr, indicator, hash, msg)
+			line := fmt.Sprintf("%s%s%s %s%s%s", graphStr, indicator, hash, signatureMark(commit.Signature, true), renderTagBadge(commit.Tags, true), msg)
 			lineWidth := lipgloss.Width(line)
 			if lineWidth < maxWidth {
 				line += strings.Repeat(" ", maxWidth-lineWidth)
@@ -612,6 +633,9 @@ func (p *Plugin) renderDiffPane(visibleHeight int) string {
 	if p.previewCommit != nil && p.cursorOnCommit() {
 		return p.renderCommitPreview(visibleHeight)
 	}
+	if entry := p.selectedSubmoduleEntry(); entry != nil {
+		return p.renderSubmoduleDrift(entry, visibleHeight)
+	}
 
 	var sb strings.Builder
 
@@ -656,6 +680,9 @@ func (p *Plugin) renderDiffPane(visibleHeight int) string {
 
 	header = fmt.Sprintf("%s [%s]%s", header, viewModeStr, scrollIndicator)
 	sb.WriteString(styles.Title.Render(header))
+	if p.activePane == PaneDiff && p.diffSelection.Selecting {
+		sb.WriteString(styles.Muted.Render(" -- select lines --"))
+	}
 	sb.WriteString("\n\n")
 
 	if p.selectedDiffFile == "" {
@@ -677,10 +704,15 @@ func (p *Plugin) renderDiffPane(visibleHeight int) string {
 	// Render diff based on view mode
 	highlighter := p.getHighlighter(p.selectedDiffFile)
 	var diffContent string
+	// Show the partial staging cursor only while the diff pane is focused
+	var sel *DiffSelection
+	if p.activePane == PaneDiff && p.selectedDiffEntry() != nil {
+		sel = &p.diffSelection
+	}
 	if p.diffPaneViewMode == DiffViewSideBySide {
-		diffContent = RenderSideBySide(p.diffPaneParsedDiff, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled)
+		diffContent = renderSideBySide(p.diffPaneParsedDiff, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled, sel)
 	} else {
-		diffContent = RenderLineDiff(p.diffPaneParsedDiff, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled)
+		diffContent = renderLineDiff(p.diffPaneParsedDiff, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled, sel)
 	}
 	// Force truncate each line to prevent wrapping (skip when wrap is enabled)
 	if !p.diffWrapEnabled {
@@ -732,6 +764,10 @@ func (p *Plugin) renderCommitPreview(visibleHeight int) string {
 	// Header with styled commit hash
 	sb.WriteString(styles.Title.Render("Commit "))
 	sb.WriteString(hashBadge.Render(c.ShortHash))
+	for _, tag := range c.Tags {
+		sb.WriteString(" ")
+		sb.WriteString(tagBadgeStyle.Render(tag))
+	}
 	sb.WriteString("\n\n")
 	currentY += 2 // header line + blank line from \n\n
 
@@ -748,8 +784,18 @@ func (p *Plugin) renderCommitPreview(visibleHeight int) string {
 	// Date with icon-like prefix
 	sb.WriteString(labelStyle.Render("󰃰 ")) // Calendar icon
 	sb.WriteString(styles.Muted.Render(RelativeTime(c.Date)))
+	sb.WriteString("\n")
+	currentY++
+
+	// Signature verification status
+	sigStr := c.Signature.Describe(c.Signer, c.SigningKey)
+	if len(sigStr) > maxWidth-2 {
+		sigStr = sigStr[:maxWidth-5] + "..."
+	}
+	sb.WriteString(labelStyle.Render("󰌾 ")) // Lock icon
+	sb.WriteString(signatureStyle(c.Signature).Render(sigStr))
 	sb.WriteString("\n\n")
-	currentY += 2 // date + blank line
+	currentY += 2 // signature + blank line
 
 	// Subject in bold
 	subject := c.Subject
@@ -0,0 +1,222 @@
+package gitstatus
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SignatureStatus is git's verification result for a commit signature, as
+// reported by the %G? log placeholder.
+type SignatureStatus string
+
+const (
+	SignatureNone       SignatureStatus = "N" // Unsigned
+	SignatureGood       SignatureStatus = "G"
+	SignatureUntrusted  SignatureStatus = "U" // Good signature from a key of unknown validity
+	SignatureBad        SignatureStatus = "B"
+	SignatureExpired    SignatureStatus = "X" // Good signature that has expired
+	SignatureExpiredKey SignatureStatus = "Y" // Good signature made by an expired key
+	SignatureRevokedKey SignatureStatus = "R" // Good signature made by a revoked key
+	SignatureUnchecked  SignatureStatus = "E" // Can't be checked, e.g. the key is missing
+)
+
+// Signed reports whether the commit carries a signature at all.
+func (s SignatureStatus) Signed() bool {
+	return s != "" && s != SignatureNone
+}
+
+// Verified reports whether the signature is good and trusted.
+func (s SignatureStatus) Verified() bool {
+	return s == SignatureGood
+}
+
+// Describe returns a one-line description of the signature for the commit
+// detail view. signer and key come from %GS and %GK and may be empty.
+func (s SignatureStatus) Describe(signer, key string) string {
+	from := ""
+	if signer != "" {
+		from = " from " + signer
+	} else if key != "" {
+		from = " from key " + key
+	}
+	switch s {
+	case SignatureGood:
+		return "Good signature" + from
+	case SignatureUntrusted:
+		return "Good signature" + from + " (untrusted key)"
+	case SignatureBad:
+		return "Bad signature" + from
+	case SignatureExpired:
+		return "Expired signature" + from
+	case SignatureExpiredKey:
+		return "Signed" + from + " with an expired key"
+	case SignatureRevokedKey:
+		return "Signed" + from + " with a revoked key"
+	case SignatureUnchecked:
+		if key != "" {
+			return "Signed, can't verify (key " + key + " unavailable)"
+		}
+		return "Signed, can't verify"
+	default:
+		return "Not signed"
+	}
+}
+
+// GetSignatureStatus returns the signature status of the given commits.
+// Checking a signature runs gpg or ssh-keygen once per signed commit, so
+// the history list asks only for the rows it shows.
+func GetSignatureStatus(workDir string, hashes []string) (map[string]SignatureStatus, error) {
+	statuses := make(map[string]SignatureStatus, len(hashes))
+	if len(hashes) == 0 {
+		return statuses, nil
+	}
+	args := append([]string{"log", "--no-walk=unsorted", "--format=%H%x00%G?"}, hashes...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if hash, status, ok := strings.Cut(line, "\x00"); ok {
+			statuses[hash] = SignatureStatus(status)
+		}
+	}
+	return statuses, nil
+}
+
+// Signing formats, as configured by gpg.format.
+const (
+	SigningOpenPGP = "openpgp"
+	SigningSSH     = "ssh"
+	SigningX509    = "x509"
+)
+
+// SigningConfig describes how git will sign new commits in a repository.
+type SigningConfig struct {
+	Enabled bool   // commit.gpgsign is on
+	Format  string // gpg.format: SigningOpenPGP, SigningSSH or SigningX509
+	Key     string // user.signingkey; for OpenPGP, the committer email if unset
+	Problem string // Why signing would fail; "" if it looks usable
+}
+
+// signingCheckTimeout bounds the key lookup, which can stall on a
+// misconfigured gpg agent.
+const signingCheckTimeout = 5 * time.Second
+
+// gitConfigValue returns a git config value, or "" if it isn't set.
+func gitConfigValue(workDir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"config"}, args...)...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// CheckSigning reads the repository's commit signing configuration and,
+// when signing is on, checks that a commit could be signed with it. Nothing
+// is signed, so no passphrase prompt is triggered.
+func CheckSigning(workDir string) SigningConfig {
+	cfg := SigningConfig{
+		Enabled: gitConfigValue(workDir, "--bool", "commit.gpgsign") == "true",
+		Format:  strings.ToLower(gitConfigValue(workDir, "gpg.format")),
+		Key:     gitConfigValue(workDir, "user.signingkey"),
+	}
+	if cfg.Format == "" {
+		cfg.Format = SigningOpenPGP
+	}
+	if !cfg.Enabled {
+		return cfg
+	}
+
+	program := gitConfigValue(workDir, "gpg."+cfg.Format+".program")
+	switch cfg.Format {
+	case SigningOpenPGP:
+		if program == "" {
+			program = gitConfigValue(workDir, "gpg.program")
+		}
+		if program == "" {
+			program = "gpg"
+		}
+	case SigningSSH:
+		if program == "" {
+			program = "ssh-keygen"
+		}
+	case SigningX509:
+		if program == "" {
+			program = "gpgsm"
+		}
+	default:
+		cfg.Problem = "unsupported gpg.format " + cfg.Format
+		return cfg
+	}
+	if _, err := exec.LookPath(program); err != nil {
+		cfg.Problem = program + " is not installed"
+		return cfg
+	}
+
+	switch cfg.Format {
+	case SigningSSH:
+		cfg.Problem = checkSSHSigningKey(workDir, cfg.Key)
+	case SigningOpenPGP:
+		if cfg.Key == "" {
+			// gpg picks a key for the committer identity
+			cfg.Key = gitConfigValue(workDir, "user.email")
+		}
+		if cfg.Key == "" {
+			cfg.Problem = "user.signingkey is not set"
+			return cfg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), signingCheckTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, program, "--batch", "--list-secret-keys", cfg.Key)
+		cmd.Dir = workDir
+		if err := cmd.Run(); err != nil {
+			cfg.Problem = "no secret key for " + cfg.Key
+		}
+	}
+	return cfg
+}
+
+// checkSSHSigningKey checks an SSH user.signingkey, which is either a key
+// file or a literal public key.
+func checkSSHSigningKey(workDir, key string) string {
+	if key == "" {
+		if gitConfigValue(workDir, "gpg.ssh.defaultKeyCommand") != "" {
+			return ""
+		}
+		return "user.signingkey is not set"
+	}
+	if strings.HasPrefix(key, "key::") || strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "ecdsa-") {
+		return ""
+	}
+	path := key
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "can't resolve " + key
+		}
+		path = filepath.Join(home, rest)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "signing key " + key + " not found"
+	}
+	return ""
+}
+
+// isSigningFailure reports whether git commit output shows that signing
+// failed, as opposed to a hook or an empty commit.
+func isSigningFailure(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "gpg failed to sign") ||
+		strings.Contains(lower, "failed to sign the data") ||
+		strings.Contains(lower, "cannot run gpg") ||
+		strings.Contains(lower, "couldn't load public key") ||
+		(strings.Contains(lower, "ssh-keygen") && strings.Contains(lower, "sign"))
+}
@@ -0,0 +1,165 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestSignatureStatusDescribe(t *testing.T) {
+	tests := []struct {
+		status      SignatureStatus
+		signer, key string
+		want        string
+	}{
+		{SignatureGood, "Ann <ann@example.com>", "ABC", "Good signature from Ann <ann@example.com>"},
+		{SignatureUntrusted, "", "ABC", "Good signature from key ABC (untrusted key)"},
+		{SignatureBad, "", "", "Bad signature"},
+		{SignatureUnchecked, "", "ABC", "Signed, can't verify (key ABC unavailable)"},
+		{SignatureNone, "", "", "Not signed"},
+		{"", "", "", "Not signed"},
+	}
+	for _, tt := range tests {
+		if got := tt.status.Describe(tt.signer, tt.key); got != tt.want {
+			t.Errorf("%q.Describe() = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSigning(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	gitRun(t, dir, "config", "commit.gpgsign", "false")
+
+	if cfg := CheckSigning(dir); cfg.Enabled || cfg.Format != SigningOpenPGP {
+		t.Errorf("signing off: %+v", cfg)
+	}
+
+	gitRun(t, dir, "config", "commit.gpgsign", "true")
+	gitRun(t, dir, "config", "gpg.program", filepath.Join(dir, "no-such-gpg"))
+	if cfg := CheckSigning(dir); !cfg.Enabled || !strings.Contains(cfg.Problem, "not installed") {
+		t.Errorf("missing program: %+v", cfg)
+	}
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	gitRun(t, dir, "config", "gpg.format", "ssh")
+	if cfg := CheckSigning(dir); cfg.Problem != "user.signingkey is not set" {
+		t.Errorf("no key: %+v", cfg)
+	}
+	gitRun(t, dir, "config", "user.signingkey", filepath.Join(dir, "missing.pub"))
+	if cfg := CheckSigning(dir); !strings.Contains(cfg.Problem, "not found") {
+		t.Errorf("missing key file: %+v", cfg)
+	}
+	gitRun(t, dir, "config", "user.signingkey", "key::ssh-ed25519 AAAA")
+	if cfg := CheckSigning(dir); cfg.Problem != "" || cfg.Format != SigningSSH {
+		t.Errorf("literal key: %+v", cfg)
+	}
+}
+
+// configureSSHSigning generates an SSH key and configures dir to sign
+// commits with it and to trust it when verifying.
+func configureSSHSigning(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	keyDir := t.TempDir()
+	key := filepath.Join(keyDir, "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "test", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v (%s)", err, out)
+	}
+	pub, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := filepath.Join(keyDir, "allowed_signers")
+	if err := os.WriteFile(signers, []byte("test@example.com "+string(pub)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "config", "gpg.format", "ssh")
+	gitRun(t, dir, "config", "user.signingkey", key+".pub")
+	gitRun(t, dir, "config", "gpg.ssh.allowedSignersFile", signers)
+}
+
+func TestCommitSignatureStatus(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	configureSSHSigning(t, dir)
+	gitRun(t, dir, "config", "commit.gpgsign", "true")
+
+	if cfg := CheckSigning(dir); cfg.Problem != "" {
+		t.Fatalf("signing config problem: %s", cfg.Problem)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "file.txt")
+	if _, err := ExecuteCommit(dir, "signed change", CommitOptions{SignOff: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := GetCommitHistory(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The history itself doesn't check signatures
+	if len(commits) != 2 || commits[0].Signature != "" {
+		t.Fatalf("history commits = %d, signature %q", len(commits), commits[0].Signature)
+	}
+	statuses, err := GetSignatureStatus(dir, []string{commits[1].Hash, commits[0].Hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statuses[commits[0].Hash] != SignatureGood || statuses[commits[1].Hash] != SignatureNone {
+		t.Fatalf("signatures = %v", statuses)
+	}
+
+	// The sidebar checks the rows it shows, once
+	p := &Plugin{ctx: &plugin.Context{}, tree: NewFileTree(dir), repoRoot: dir, height: 40, recentCommits: commits}
+	cmd := p.loadVisibleSignatures()
+	if cmd == nil {
+		t.Fatal("no signature check for the visible rows")
+	}
+	if again := p.loadVisibleSignatures(); again != nil {
+		t.Error("pending rows checked again")
+	}
+	p.handleSignaturesLoaded(cmd().(SignaturesLoadedMsg))
+	if commits[0].Signature != SignatureGood || commits[1].Signature != SignatureNone {
+		t.Errorf("row signatures = %q, %q", commits[0].Signature, commits[1].Signature)
+	}
+	if again := p.loadVisibleSignatures(); again != nil {
+		t.Error("checked rows checked again")
+	}
+
+	detail, err := GetCommitDetail(dir, commits[0].Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !detail.Signature.Verified() || detail.Signer != "test@example.com" || detail.SigningKey == "" {
+		t.Errorf("detail = %q signer=%q key=%q", detail.Signature, detail.Signer, detail.SigningKey)
+	}
+	if detail.Subject != "signed change" || !strings.Contains(detail.Body, "Signed-off-by: Test <test@example.com>") {
+		t.Errorf("subject=%q body=%q", detail.Subject, detail.Body)
+	}
+}
+
+func TestCommitSigningFailure(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	gitRun(t, dir, "config", "commit.gpgsign", "true")
+	gitRun(t, dir, "config", "gpg.program", filepath.Join(dir, "no-such-gpg"))
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "file.txt")
+
+	_, err := ExecuteCommit(dir, "change", CommitOptions{})
+	var commitErr *CommitError
+	if !errors.As(err, &commitErr) || !strings.HasPrefix(err.Error(), "Signing failed") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package gitstatus
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// SigningCheckedMsg carries the signing configuration read for the commit
+// modal.
+type SigningCheckedMsg struct {
+	Epoch  uint64
+	Config SigningConfig
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SigningCheckedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// checkCommitSigning reads the signing configuration in the background so
+// the commit modal can warn before a commit fails to sign.
+func (p *Plugin) checkCommitSigning() tea.Cmd {
+	p.commitSigning = nil
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		return SigningCheckedMsg{Epoch: epoch, Config: CheckSigning(workDir)}
+	}
+}
+
+// commitOptions returns the per-commit flags chosen in the commit modal.
+func (p *Plugin) commitOptions() CommitOptions {
+	return CommitOptions{SignOff: p.commitSignOff}
+}
+
+// commitSigningSection shows whether the commit will be signed, and why
+// signing would fail when commit.gpgsign is on.
+func (p *Plugin) commitSigningSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		cfg := p.commitSigning
+		if cfg == nil || !cfg.Enabled {
+			return modal.RenderedSection{}
+		}
+		if cfg.Problem != "" {
+			line := "! commit.gpgsign is on but signing will fail: " + cfg.Problem
+			return modal.RenderedSection{Content: styles.StatusModified.Render(ansi.Truncate(line, contentWidth, "…"))}
+		}
+		line := "Will be signed (" + cfg.Format
+		if cfg.Key != "" {
+			line += ", " + cfg.Key
+		}
+		line += ")"
+		return modal.RenderedSection{Content: styles.Muted.Render(ansi.Truncate(line, contentWidth, "…"))}
+	}, nil)
+}
+
+// signatureMark returns the history list marker for a commit's signature:
+// a check for verified signatures, a cross for bad ones and a question mark
+// for signatures that can't be trusted. Unsigned commits get no marker.
+func signatureMark(status SignatureStatus, styled bool) string {
+	var mark string
+	var color lipgloss.Color
+	switch status {
+	case SignatureGood:
+		mark, color = "✓", styles.Success
+	case SignatureBad, SignatureRevokedKey:
+		mark, color = "✗", styles.Error
+	case SignatureUntrusted, SignatureExpired, SignatureExpiredKey, SignatureUnchecked:
+		mark, color = "?", styles.Warning
+	default:
+		return ""
+	}
+	if !styled {
+		return mark + " "
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(mark) + " "
+}
+
+// signatureStyle returns the style for a signature description in the
+// commit detail view.
+func signatureStyle(status SignatureStatus) lipgloss.Style {
+	switch status {
+	case SignatureGood:
+		return lipgloss.NewStyle().Foreground(styles.Success)
+	case SignatureBad, SignatureRevokedKey:
+		return lipgloss.NewStyle().Foreground(styles.Error)
+	case SignatureNone, "":
+		return styles.Muted
+	default:
+		return lipgloss.NewStyle().Foreground(styles.Warning)
+	}
+}
@@ -3,19 +3,33 @@ package gitstatus
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Stash represents a single stash entry.
 type Stash struct {
-	Index   int    // stash index (0 = most recent)
-	Ref     string // stash@{0}, stash@{1}, etc.
-	Branch  string // Branch the stash was created on
-	Message string // Stash message
+	Index    int       // stash index (0 = most recent)
+	Ref      string    // stash@{0}, stash@{1}, etc.
+	Hash     string    // Stash commit
+	Branch   string    // Branch the stash was created on
+	Message  string    // Stash message
+	Date     time.Time // When the stash was created
+	Worktree string    // Worktree the stash was created in, "" if not created by sidecar
 }
 
+// stashWorktreesName is the file (in the git common dir, shared by all
+// worktrees like the stash list) mapping stashes sidecar created to the
+// worktree they were created in.
+const stashWorktreesName = "sidecar-stash-worktrees.json"
+
 // StashList represents the list of stashes.
 type StashList struct {
 	Stashes []*Stash
@@ -23,7 +37,7 @@ type StashList struct {
 
 // GetStashList retrieves the list of stashes.
 func GetStashList(workDir string) (*StashList, error) {
-	cmd := exec.Command("git", "stash", "list", "--format=%gd|%gs")
+	cmd := exec.Command("git", "stash", "list", "--format=%gd|%H|%ct|%gs")
 	cmd.Dir = workDir
 	output, err := cmd.Output()
 	if err != nil {
@@ -34,15 +48,15 @@ func GetStashList(workDir string) (*StashList, error) {
 	list := &StashList{}
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 
-	// Pattern: stash@{n}|message
+	// Pattern: stash@{n}|hash|timestamp|message
 	// Message format: "WIP on branch: hash message" or "On branch: message"
-	re := regexp.MustCompile(`^stash@\{(\d+)\}\|(.+)$`)
+	re := regexp.MustCompile(`^stash@\{(\d+)\}\|([0-9a-f]+)\|(\d+)\|(.+)$`)
 	branchRe := regexp.MustCompile(`^(?:WIP )?[Oo]n ([^:]+): (.+)$`)
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		matches := re.FindStringSubmatch(line)
-		if len(matches) != 3 {
+		if len(matches) != 5 {
 			continue
 		}
 
@@ -50,13 +64,16 @@ func GetStashList(workDir string) (*StashList, error) {
 		_, _ = exec.Command("echo").Output() // dummy to avoid import error
 		idx = len(list.Stashes)
 
+		timestamp, _ := strconv.ParseInt(matches[3], 10, 64)
 		stash := &Stash{
 			Index: idx,
 			Ref:   "stash@{" + matches[1] + "}",
+			Hash:  matches[2],
+			Date:  time.Unix(timestamp, 0),
 		}
 
 		// Parse the message for branch name
-		msgPart := matches[2]
+		msgPart := matches[4]
 		branchMatches := branchRe.FindStringSubmatch(msgPart)
 		if len(branchMatches) == 3 {
 			stash.Branch = branchMatches[1]
@@ -68,28 +85,112 @@ func GetStashList(workDir string) (*StashList, error) {
 		list.Stashes = append(list.Stashes, stash)
 	}
 
+	// Worktrees share one stash list; only stashes made in sidecar are
+	// known to come from a worktree
+	worktrees := readStashWorktrees(workDir)
+	for _, stash := range list.Stashes {
+		stash.Worktree = worktrees[stash.Hash]
+	}
+
 	return list, nil
 }
 
 // StashPush creates a new stash with all changes.
 func StashPush(workDir string) error {
-	cmd := exec.Command("git", "stash", "push")
-	cmd.Dir = workDir
-	return cmd.Run()
+	return stashPush(workDir, "push")
 }
 
 // StashPushWithMessage creates a new stash with a custom message.
 func StashPushWithMessage(workDir, message string) error {
-	cmd := exec.Command("git", "stash", "push", "-m", message)
-	cmd.Dir = workDir
-	return cmd.Run()
+	return stashPush(workDir, "push", "-m", message)
 }
 
 // StashPushIncludeUntracked creates a stash including untracked files.
 func StashPushIncludeUntracked(workDir string) error {
-	cmd := exec.Command("git", "stash", "push", "--include-untracked")
+	return stashPush(workDir, "push", "--include-untracked")
+}
+
+// stashPush runs git stash with args and records the worktree the new
+// stash was created in.
+func stashPush(workDir string, args ...string) error {
+	before := stashHead(workDir)
+	cmd := exec.Command("git", append([]string{"stash"}, args...)...)
+	cmd.Dir = workDir
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	if hash := stashHead(workDir); hash != "" && hash != before {
+		recordStashWorktree(workDir, hash)
+	}
+	return nil
+}
+
+// stashHead returns the commit of stash@{0}, "" if there are no stashes.
+func stashHead(workDir string) string {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/stash")
+	cmd.Dir = workDir
+	out, _ := cmd.Output()
+	return strings.TrimSpace(string(out))
+}
+
+// stashWorktreesPath returns the path of the stash worktree map.
+func stashWorktreesPath(workDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workDir, dir)
+	}
+	return filepath.Join(dir, stashWorktreesName), nil
+}
+
+// readStashWorktrees returns the worktree of each stash sidecar created,
+// by stash commit.
+func readStashWorktrees(workDir string) map[string]string {
+	worktrees := make(map[string]string)
+	path, err := stashWorktreesPath(workDir)
+	if err != nil {
+		return worktrees
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &worktrees)
+	}
+	return worktrees
+}
+
+// recordStashWorktree maps a new stash to the worktree of workDir. Entries
+// of stashes no longer in the stash list are dropped. Failures are ignored;
+// the stash is then shown without a worktree.
+func recordStashWorktree(workDir, hash string) {
+	path, err := stashWorktreesPath(workDir)
+	if err != nil {
+		return
+	}
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	cmd.Dir = workDir
-	return cmd.Run()
+	out, err := cmd.Output()
+	if err != nil {
+		return
+	}
+	cmd = exec.Command("git", "stash", "list", "--format=%H")
+	cmd.Dir = workDir
+	listed, _ := cmd.Output()
+
+	worktrees := readStashWorktrees(workDir)
+	kept := make(map[string]string, len(worktrees)+1)
+	for _, h := range strings.Fields(string(listed)) {
+		if wt, ok := worktrees[h]; ok {
+			kept[h] = wt
+		}
+	}
+	kept[hash] = strings.TrimSpace(string(out))
+	if data, err := json.Marshal(kept); err == nil {
+		_ = os.WriteFile(path, data, 0644)
+	}
 }
 
 // StashPop pops the most recent stash.
@@ -153,3 +254,120 @@ func (l *StashList) Count() int {
 	}
 	return len(l.Stashes)
 }
+
+// GetStashFiles returns the files a stash changes. Untracked files it saved
+// are listed last with StatusUntracked.
+func GetStashFiles(workDir, ref string) ([]CommitFile, error) {
+	cmd := exec.Command("git", "diff", "-M", "--name-status", ref+"^1", ref)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []CommitFile
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if file, ok := parseNameStatus(line); ok {
+			files = append(files, file)
+		}
+	}
+
+	if !stashHasUntracked(workDir, ref) {
+		return files, nil
+	}
+	cmd = exec.Command("git", "ls-tree", "-r", "--name-only", ref+"^3")
+	cmd.Dir = workDir
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path != "" {
+			files = append(files, CommitFile{Path: path, Status: StatusUntracked})
+		}
+	}
+	return files, nil
+}
+
+// stashHasUntracked reports whether a stash saved untracked files, which git
+// keeps in a third parent commit.
+func stashHasUntracked(workDir, ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^3")
+	cmd.Dir = workDir
+	return cmd.Run() == nil
+}
+
+// GetStashFileDiff returns the diff of one file in a stash against the
+// commit the stash was made on.
+func GetStashFileDiff(workDir, ref string, file CommitFile) (string, error) {
+	var args []string
+	if file.Status == StatusUntracked {
+		// The untracked files commit has no parent, so show lists them as added
+		args = []string{"show", "--format=", ref + "^3", "--", file.Path}
+	} else {
+		args = []string{"diff", "-M", ref + "^1", ref, "--"}
+		if file.OldPath != "" {
+			args = append(args, file.OldPath)
+		}
+		args = append(args, file.Path)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// StashApplyFile applies one file's changes from a stash to the working tree,
+// leaving the index and the stash untouched.
+func StashApplyFile(workDir, ref string, file CommitFile) error {
+	if file.Status == StatusUntracked {
+		if _, err := os.Stat(filepath.Join(workDir, file.Path)); err == nil {
+			return errors.New(file.Path + " already exists")
+		}
+		if err := runGit(workDir, "checkout", ref+"^3", "--", file.Path); err != nil {
+			return err
+		}
+		// checkout also stages the file; keep it untracked as it was
+		return runGit(workDir, "rm", "--cached", "--quiet", "--", file.Path)
+	}
+
+	args := []string{"diff", "--binary", ref + "^1", ref, "--"}
+	if file.OldPath != "" {
+		args = append(args, file.OldPath)
+	}
+	args = append(args, file.Path)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	patch, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		return errors.New("no changes to apply for " + file.Path)
+	}
+
+	cmd = exec.Command("git", "apply")
+	cmd.Dir = workDir
+	cmd.Stdin = bytes.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &StashError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// StashBranch creates a branch at the commit a stash was made on, checks it
+// out and pops the stash onto it.
+func StashBranch(workDir, name, ref string) error {
+	if name == "" {
+		return errors.New("branch name is required")
+	}
+	cmd := exec.Command("git", "stash", "branch", name, ref)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return &StashError{Output: string(output), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package gitstatus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initStashRepo creates a repo with one stash that modifies file.txt and
+// saves the untracked file new.txt.
+func initStashRepo(t *testing.T) string {
+	t.Helper()
+	dir := initPartialRepo(t, "one\ntwo\n")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\ntwo changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "stash", "push", "--include-untracked", "-m", "wip")
+	return dir
+}
+
+func TestGetStashList(t *testing.T) {
+	dir := initStashRepo(t)
+	branch := gitRun(t, dir, "branch", "--show-current")
+
+	list, err := GetStashList(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count() != 1 {
+		t.Fatalf("stash count = %d, want 1", list.Count())
+	}
+	s := list.Stashes[0]
+	if s.Ref != "stash@{0}" || s.Branch != branch || s.Message != "wip" {
+		t.Fatalf("stash = %+v", s)
+	}
+	if s.Hash != gitRun(t, dir, "rev-parse", "stash@{0}") {
+		t.Fatalf("hash = %q", s.Hash)
+	}
+	if s.Date.IsZero() {
+		t.Fatal("date not parsed")
+	}
+	if s.Worktree != "" {
+		t.Fatalf("worktree = %q for a stash made outside sidecar", s.Worktree)
+	}
+	p := &Plugin{repoRoot: dir}
+	if label := p.stashWorktreeLabel(s); label != "worktree unknown" {
+		t.Errorf("label = %q", label)
+	}
+}
+
+func TestStashRecordsWorktree(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	wt := filepath.Join(t.TempDir(), "feature")
+	gitRun(t, dir, "worktree", "add", "-b", "feature", wt)
+	if err := os.WriteFile(filepath.Join(wt, "file.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := StashPush(wt); err != nil {
+		t.Fatal(err)
+	}
+	head := gitRun(t, wt, "log", "-1", "--format=%h %s")
+
+	// The branch moves on; the stash still names where it was made
+	gitRun(t, wt, "checkout", "--detach")
+	list, err := GetStashList(dir)
+	if err != nil || list.Count() != 1 {
+		t.Fatalf("stashes = %+v, %v", list, err)
+	}
+	s := list.Stashes[0]
+	want, _ := filepath.EvalSymlinks(wt)
+	if got, _ := filepath.EvalSymlinks(s.Worktree); got != want {
+		t.Errorf("worktree = %q, want %q", s.Worktree, wt)
+	}
+	// git's own message is kept
+	if s.Branch != "feature" || s.Message != head {
+		t.Errorf("branch = %q, message = %q, want %q", s.Branch, s.Message, head)
+	}
+	if raw := gitRun(t, dir, "log", "-1", "--format=%s", "stash@{0}"); raw != "WIP on feature: "+head {
+		t.Errorf("stash message = %q", raw)
+	}
+
+	p := &Plugin{repoRoot: dir}
+	if label := p.stashWorktreeLabel(s); label != "created in feature" {
+		t.Errorf("label = %q", label)
+	}
+
+	// Entries of stashes that are gone are dropped on the next push
+	gitRun(t, dir, "stash", "drop")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := StashPushWithMessage(dir, "mine"); err != nil {
+		t.Fatal(err)
+	}
+	worktrees := readStashWorktrees(dir)
+	if len(worktrees) != 1 || worktrees[gitRun(t, dir, "rev-parse", "stash@{0}")] == "" {
+		t.Errorf("worktrees = %v", worktrees)
+	}
+}
+
+func TestGetStashFiles(t *testing.T) {
+	dir := initStashRepo(t)
+
+	files, err := GetStashFiles(dir, "stash@{0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("files = %+v, want 2", files)
+	}
+	if files[0].Path != "file.txt" || files[0].Status != StatusModified {
+		t.Errorf("files[0] = %+v", files[0])
+	}
+	if files[1].Path != "new.txt" || files[1].Status != StatusUntracked {
+		t.Errorf("files[1] = %+v", files[1])
+	}
+
+	diff, err := GetStashFileDiff(dir, "stash@{0}", files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+two changed") {
+		t.Errorf("tracked diff = %q", diff)
+	}
+	diff, err = GetStashFileDiff(dir, "stash@{0}", files[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+new") {
+		t.Errorf("untracked diff = %q", diff)
+	}
+}
+
+func TestStashApplyFile(t *testing.T) {
+	dir := initStashRepo(t)
+	files, err := GetStashFiles(dir, "stash@{0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range files {
+		if err := StashApplyFile(dir, "stash@{0}", f); err != nil {
+			t.Fatalf("apply %s: %v", f.Path, err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if string(data) != "one\ntwo changed\n" {
+		t.Errorf("file.txt = %q", data)
+	}
+	if status := gitRun(t, dir, "status", "--porcelain"); status != "M file.txt\n?? new.txt" {
+		t.Errorf("status = %q", status)
+	}
+	if list, _ := GetStashList(dir); list.Count() != 1 {
+		t.Error("applying a file dropped the stash")
+	}
+
+	// Applying the untracked file again would overwrite it
+	if err := StashApplyFile(dir, "stash@{0}", files[1]); err == nil {
+		t.Error("expected error applying an untracked file that exists")
+	}
+}
+
+func TestStashBranch(t *testing.T) {
+	dir := initStashRepo(t)
+
+	if err := StashBranch(dir, "from-stash", "stash@{0}"); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitRun(t, dir, "branch", "--show-current"); got != "from-stash" {
+		t.Fatalf("branch = %q, want from-stash", got)
+	}
+	if list, _ := GetStashList(dir); list.Count() != 0 {
+		t.Error("stash not dropped after branching")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); err != nil {
+		t.Error("untracked file not restored")
+	}
+}
@@ -0,0 +1,800 @@
+package gitstatus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	stashItemPrefix     = "stash-item-"
+	stashBranchInputID  = "stash-branch-name"
+	stashBranchCreateID = "stash-branch-create"
+	stashDropID         = "stash-drop"
+)
+
+func stashItemID(idx int) string {
+	return fmt.Sprintf("%s%d", stashItemPrefix, idx)
+}
+
+func parseStashItem(id string) (int, bool) {
+	if !strings.HasPrefix(id, stashItemPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, stashItemPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// StashesLoadedMsg carries the stash list.
+type StashesLoadedMsg struct {
+	Epoch   uint64
+	Stashes []*Stash
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m StashesLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// StashFilesLoadedMsg carries the files of the stash in the detail view.
+type StashFilesLoadedMsg struct {
+	Epoch uint64
+	Hash  string
+	Files []CommitFile
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m StashFilesLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// StashDiffLoadedMsg carries the diff of one file in a stash.
+type StashDiffLoadedMsg struct {
+	Epoch uint64
+	Hash  string
+	Path  string
+	Diff  string
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m StashDiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openStashes opens the stash list.
+func (p *Plugin) openStashes() tea.Cmd {
+	p.stashes = nil
+	p.stashesLoaded = false
+	p.stashCursor = 0
+	p.clearStashesModal()
+	p.viewMode = ViewModeStashes
+	return p.loadStashes()
+}
+
+// loadStashes reads the stash list.
+func (p *Plugin) loadStashes() tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		list, err := GetStashList(workDir)
+		if err != nil {
+			return StashesLoadedMsg{Epoch: epoch, Err: err}
+		}
+		return StashesLoadedMsg{Epoch: epoch, Stashes: list.Stashes}
+	}
+}
+
+// handleStashesLoaded stores the loaded stash list.
+func (p *Plugin) handleStashesLoaded(m StashesLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeStashes {
+		return nil
+	}
+	if m.Err != nil {
+		p.closeStashes()
+		p.showErrorModal("Stashes Failed", m.Err)
+		return nil
+	}
+	p.stashes = m.Stashes
+	p.stashesLoaded = true
+	p.stashCursor = min(p.stashCursor, max(len(p.stashes)-1, 0))
+	return nil
+}
+
+// handleStashResult updates the stash views after a stash operation
+// succeeded. Refs shift when a stash is removed, so the list is reloaded.
+func (p *Plugin) handleStashResult(m StashResultMsg) tea.Cmd {
+	if m.Err != nil {
+		return nil
+	}
+	switch m.Operation {
+	case "branch":
+		// The stash was popped onto the new branch
+		if p.viewMode == ViewModeStashBranch {
+			p.closeStashBranch()
+		}
+		if p.viewMode == ViewModeStash {
+			p.closeStash()
+		}
+		if p.viewMode == ViewModeStashes {
+			p.closeStashes()
+		}
+	case "pop", "drop":
+		if p.viewMode == ViewModeStash {
+			p.closeStash()
+		}
+	}
+	if p.viewMode == ViewModeStashes {
+		return p.loadStashes()
+	}
+	return nil
+}
+
+// selectedStash returns the stash under the cursor, if any.
+func (p *Plugin) selectedStash() *Stash {
+	if p.stashCursor < 0 || p.stashCursor >= len(p.stashes) {
+		return nil
+	}
+	return p.stashes[p.stashCursor]
+}
+
+// updateStashes handles key events in the stash list.
+func (p *Plugin) updateStashes(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureStashesModal()
+	if p.stashesModal == nil {
+		return p, nil
+	}
+
+	switch m.String() {
+	case "esc", "q":
+		p.closeStashes()
+		return p, nil
+	case "j", "down":
+		p.stashCursor = min(p.stashCursor+1, max(len(p.stashes)-1, 0))
+		return p, nil
+	case "k", "up":
+		p.stashCursor = max(p.stashCursor-1, 0)
+		return p, nil
+	case "g":
+		p.stashCursor = 0
+		return p, nil
+	case "G":
+		p.stashCursor = max(len(p.stashes)-1, 0)
+		return p, nil
+	case "enter":
+		if s := p.selectedStash(); s != nil {
+			return p, p.openStash(s)
+		}
+		return p, nil
+	case "a":
+		if s := p.selectedStash(); s != nil {
+			return p, p.doStashApplyRef(s.Ref)
+		}
+		return p, nil
+	case "p":
+		if s := p.selectedStash(); s != nil {
+			p.openStashPopConfirm(s)
+		}
+		return p, nil
+	case "b":
+		if s := p.selectedStash(); s != nil {
+			p.openStashBranch(s)
+		}
+		return p, nil
+	case "d":
+		if s := p.selectedStash(); s != nil {
+			p.openStashDrop(s)
+		}
+		return p, nil
+	}
+
+	action, cmd := p.stashesModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleStashesAction(action))
+}
+
+// handleStashesAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleStashesAction(action string) tea.Cmd {
+	if action == "cancel" {
+		p.closeStashes()
+		return nil
+	}
+	if idx, ok := parseStashItem(action); ok && idx < len(p.stashes) {
+		p.stashCursor = idx
+		return p.openStash(p.stashes[idx])
+	}
+	return nil
+}
+
+func (p *Plugin) closeStashes() {
+	p.viewMode = ViewModeStatus
+	p.stashes = nil
+	p.stashesLoaded = false
+	p.clearStashesModal()
+}
+
+func (p *Plugin) clearStashesModal() {
+	p.stashesModal = nil
+	p.stashesModalWidth = 0
+}
+
+// doStashApplyRef applies a stash without removing it.
+func (p *Plugin) doStashApplyRef(ref string) tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := StashApply(workDir, ref)
+		return StashResultMsg{Operation: "apply", Ref: ref, Err: err}
+	}
+}
+
+// openStashPopConfirm asks before popping a stash from the list.
+func (p *Plugin) openStashPopConfirm(s *Stash) {
+	p.stashPopItem = s
+	p.stashPopModal = nil
+	p.stashPopReturnMode = p.viewMode
+	p.viewMode = ViewModeConfirmStashPop
+}
+
+// ensureStashesModal builds/rebuilds the stash list modal.
+func (p *Plugin) ensureStashesModal() {
+	modalW := p.reflogModalWidthForContent()
+	if p.stashesModal != nil && p.stashesModalWidth == modalW {
+		return
+	}
+	p.stashesModalWidth = modalW
+
+	p.stashesModal = modal.New("Stashes",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.stashesListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			return modal.RenderedSection{Content: styles.Muted.Render("  Enter open  a apply  p pop  b branch  d drop")}
+		}, nil))
+}
+
+func (p *Plugin) stashesListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if !p.stashesLoaded {
+			return modal.RenderedSection{Content: styles.Muted.Render("  Loading stashes...")}
+		}
+		if len(p.stashes) == 0 {
+			return modal.RenderedSection{Content: styles.Muted.Render("  No stashes")}
+		}
+
+		maxVisible := p.reflogMaxVisible()
+		start := 0
+		if p.stashCursor >= maxVisible {
+			start = p.stashCursor - maxVisible + 1
+		}
+		end := min(start+maxVisible, len(p.stashes))
+
+		var sb strings.Builder
+		focusables := make([]modal.FocusableInfo, 0, end-start)
+		for i := start; i < end; i++ {
+			itemID := stashItemID(i)
+			line := p.renderStashLine(p.stashes[i], contentWidth, i == p.stashCursor || itemID == hoverID)
+			if i > start {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(line)
+			focusables = append(focusables, modal.FocusableInfo{
+				ID:      itemID,
+				OffsetY: i - start,
+				Width:   ansi.StringWidth(line),
+				Height:  1,
+			})
+		}
+
+		content := sb.String()
+		if len(p.stashes) > maxVisible {
+			content += "\n\n" + styles.Muted.Render(fmt.Sprintf("  %d/%d stashes", p.stashCursor+1, len(p.stashes)))
+		}
+		return modal.RenderedSection{Content: content, Focusables: focusables}
+	}, nil)
+}
+
+// stashWorktreeLabel names the worktree a stash was created in, "here" for
+// this one. Stashes not created in sidecar don't record it.
+func (p *Plugin) stashWorktreeLabel(s *Stash) string {
+	switch {
+	case s.Worktree == "":
+		return "worktree unknown"
+	case filepath.Clean(s.Worktree) == filepath.Clean(p.repoRoot):
+		return "created here"
+	}
+	return "created in " + filepath.Base(s.Worktree)
+}
+
+// renderStashLine renders one stash: ref, branch, message, worktree and age.
+func (p *Plugin) renderStashLine(s *Stash, width int, selected bool) string {
+	branch := ansi.Truncate(s.Branch, 16, "…")
+	prefix := fmt.Sprintf("  %-10s %-16s ", s.Ref, branch)
+	suffix := RelativeTime(s.Date)
+	suffix = ansi.Truncate(p.stashWorktreeLabel(s), 28, "…") + "  " + suffix
+	textW := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(suffix)-2, 10)
+	text := ansi.Truncate(s.Message, textW, "…")
+	pad := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(text)-ansi.StringWidth(suffix)-1, 1)
+
+	if selected {
+		return styles.ListItemSelected.Render(prefix + text + strings.Repeat(" ", pad) + suffix)
+	}
+	return styles.ListItemNormal.Render(
+		"  " + styles.Code.Render(fmt.Sprintf("%-10s", s.Ref)) + " " + styles.Subtitle.Render(fmt.Sprintf("%-16s", branch)) + " " +
+			text + strings.Repeat(" ", pad) + styles.Muted.Render(suffix))
+}
+
+// renderStashes renders the stash list.
+func (p *Plugin) renderStashes() string {
+	background := p.renderThreePaneView()
+
+	p.ensureStashesModal()
+	if p.stashesModal == nil {
+		return background
+	}
+
+	modalContent := p.stashesModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openStash opens the detail view of a stash: its files and their diffs.
+func (p *Plugin) openStash(s *Stash) tea.Cmd {
+	p.stashDetail = s
+	p.stashFiles = nil
+	p.stashFilesLoaded = false
+	p.stashFileCursor = 0
+	p.stashFileScroll = 0
+	p.clearStashDiff()
+	p.viewMode = ViewModeStash
+
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		files, err := GetStashFiles(workDir, s.Hash)
+		return StashFilesLoadedMsg{Epoch: epoch, Hash: s.Hash, Files: files, Err: err}
+	}
+}
+
+// handleStashFilesLoaded shows a stash's files and the first file's diff.
+func (p *Plugin) handleStashFilesLoaded(m StashFilesLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeStash || p.stashDetail == nil || p.stashDetail.Hash != m.Hash {
+		return nil
+	}
+	if m.Err != nil {
+		p.closeStash()
+		p.showErrorModal("Stash Failed", m.Err)
+		return nil
+	}
+	p.stashFiles = m.Files
+	p.stashFilesLoaded = true
+	return p.loadStashDiff()
+}
+
+// closeStash returns to the stash list.
+func (p *Plugin) closeStash() {
+	p.viewMode = ViewModeStashes
+	p.stashDetail = nil
+	p.stashFiles = nil
+	p.clearStashDiff()
+}
+
+func (p *Plugin) clearStashDiff() {
+	p.stashDiff = nil
+	p.stashDiffRaw = ""
+	p.stashDiffPath = ""
+	p.stashDiffScroll = 0
+}
+
+// selectedStashFile returns the file under the cursor, if any.
+func (p *Plugin) selectedStashFile() *CommitFile {
+	if p.stashFileCursor < 0 || p.stashFileCursor >= len(p.stashFiles) {
+		return nil
+	}
+	return &p.stashFiles[p.stashFileCursor]
+}
+
+// loadStashDiff loads the diff of the selected file.
+func (p *Plugin) loadStashDiff() tea.Cmd {
+	file := p.selectedStashFile()
+	if file == nil || p.stashDetail == nil {
+		return nil
+	}
+	p.clearStashDiff()
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	hash := p.stashDetail.Hash
+	f := *file
+	return func() tea.Msg {
+		diff, err := GetStashFileDiff(workDir, hash, f)
+		return StashDiffLoadedMsg{Epoch: epoch, Hash: hash, Path: f.Path, Diff: diff, Err: err}
+	}
+}
+
+// handleStashDiffLoaded shows a loaded diff if its file is still selected.
+func (p *Plugin) handleStashDiffLoaded(m StashDiffLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeStash || p.stashDetail == nil || p.stashDetail.Hash != m.Hash {
+		return nil
+	}
+	file := p.selectedStashFile()
+	if file == nil || file.Path != m.Path {
+		return nil
+	}
+	p.stashDiffPath = m.Path
+	if m.Err != nil {
+		p.stashDiffRaw = "Error: " + m.Err.Error()
+		return nil
+	}
+	p.stashDiffRaw = m.Diff
+	p.stashDiff = ParseMultiFileDiff(m.Diff)
+	return nil
+}
+
+// moveStashFileCursor selects another file and loads its diff.
+func (p *Plugin) moveStashFileCursor(idx int) tea.Cmd {
+	idx = max(min(idx, len(p.stashFiles)-1), 0)
+	if idx == p.stashFileCursor {
+		return nil
+	}
+	p.stashFileCursor = idx
+	return p.loadStashDiff()
+}
+
+// updateStash handles key events in the stash detail view.
+func (p *Plugin) updateStash(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	page := max((p.height-4)/2, 1)
+	s := p.stashDetail
+	switch m.String() {
+	case "esc", "q":
+		p.closeStash()
+	case "j", "down":
+		return p, p.moveStashFileCursor(p.stashFileCursor + 1)
+	case "k", "up":
+		return p, p.moveStashFileCursor(p.stashFileCursor - 1)
+	case "g":
+		return p, p.moveStashFileCursor(0)
+	case "G":
+		return p, p.moveStashFileCursor(len(p.stashFiles) - 1)
+	case "ctrl+d", "J":
+		p.stashDiffScroll += page
+	case "ctrl+u", "K":
+		p.stashDiffScroll = max(p.stashDiffScroll-page, 0)
+	case "v":
+		if p.diffViewMode == DiffViewUnified {
+			p.diffViewMode = DiffViewSideBySide
+		} else {
+			p.diffViewMode = DiffViewUnified
+		}
+	case "a":
+		// Apply just the selected file
+		if file := p.selectedStashFile(); file != nil && s != nil {
+			return p, p.doStashApplyFile(s, *file)
+		}
+	case "A":
+		if s != nil {
+			return p, p.doStashApplyRef(s.Ref)
+		}
+	case "b":
+		if s != nil {
+			p.openStashBranch(s)
+		}
+	}
+	return p, nil
+}
+
+// doStashApplyFile applies one file from a stash to the working tree.
+func (p *Plugin) doStashApplyFile(s *Stash, file CommitFile) tea.Cmd {
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := StashApplyFile(workDir, s.Hash, file)
+		return StashResultMsg{Operation: "apply-file", Ref: s.Ref, Detail: file.Path, Err: err}
+	}
+}
+
+// renderStash renders the full-screen stash detail: files on the left, the
+// selected file's diff on the right.
+func (p *Plugin) renderStash() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+	listWidth := p.fileHistoryListWidth(contentWidth)
+	diffWidth := max(contentWidth-listWidth-3, 10)
+	bodyHeight := max(paneHeight-3, 1) // header, separator, footer
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionStashDiff, 2+listWidth+3, 3, diffWidth, bodyHeight, nil)
+	p.mouseHandler.HitMap.AddRect(regionStashFiles, 2, 3, listWidth, bodyHeight, nil)
+
+	var header string
+	if s := p.stashDetail; s != nil {
+		header = styles.Title.Render("Stash ") + styles.Code.Render(s.Ref) + " " + styles.Body.Render(s.Message)
+		meta := " · " + s.Branch
+		meta += " · " + p.stashWorktreeLabel(s)
+		meta += " · " + RelativeTime(s.Date)
+		header += styles.Muted.Render(meta)
+	}
+	lines := []string{
+		ansi.Truncate(header, contentWidth, "…"),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+
+	listLines := p.renderStashFiles(listWidth, bodyHeight)
+	diffLines := p.renderStashDiff(diffWidth, bodyHeight)
+	sep := styles.Muted.Render(" │ ")
+	for i := range bodyHeight {
+		left := ""
+		if i < len(listLines) {
+			left = listLines[i]
+		}
+		right := ""
+		if i < len(diffLines) {
+			right = diffLines[i]
+		}
+		lines = append(lines, padToWidth(left, listWidth)+sep+right)
+	}
+
+	footer := "j/k file  ctrl+d/u scroll diff  v view  a apply file  A apply stash  b branch  esc back"
+	lines = append(lines, styles.Muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
+
+// renderStashFiles renders the visible rows of the file column.
+func (p *Plugin) renderStashFiles(width, height int) []string {
+	if !p.stashFilesLoaded {
+		return []string{styles.Muted.Render("Loading files...")}
+	}
+	if len(p.stashFiles) == 0 {
+		return []string{styles.Muted.Render("No changes")}
+	}
+
+	// Keep the cursor visible
+	if p.stashFileCursor < p.stashFileScroll {
+		p.stashFileScroll = p.stashFileCursor
+	}
+	if p.stashFileCursor >= p.stashFileScroll+height {
+		p.stashFileScroll = p.stashFileCursor - height + 1
+	}
+	p.stashFileScroll = min(p.stashFileScroll, max(len(p.stashFiles)-height, 0))
+
+	var lines []string
+	end := min(p.stashFileScroll+height, len(p.stashFiles))
+	for i := p.stashFileScroll; i < end; i++ {
+		lines = append(lines, p.renderCommitPreviewFile(p.stashFiles[i], i == p.stashFileCursor, width))
+	}
+	return lines
+}
+
+// renderStashDiff renders the selected file's diff.
+func (p *Plugin) renderStashDiff(width, height int) []string {
+	file := p.selectedStashFile()
+	if file == nil {
+		return nil
+	}
+	switch {
+	case p.stashDiffPath != file.Path:
+		return []string{styles.Muted.Render("Loading diff...")}
+	case p.stashDiff == nil || len(p.stashDiff.Files) == 0:
+		text := "No changes"
+		if strings.HasPrefix(p.stashDiffRaw, "Error: ") {
+			text = p.stashDiffRaw
+		}
+		return []string{styles.Muted.Render(ansi.Truncate(text, width, "…"))}
+	}
+	return p.renderCommitDiffLines(p.stashDiff, &p.stashDiffScroll, width, height)
+}
+
+// openStashBranch opens the prompt for a branch created from a stash.
+func (p *Plugin) openStashBranch(s *Stash) {
+	p.stashBranchTarget = s
+	p.stashBranchInput = textinput.New()
+	p.stashBranchInput.Placeholder = "branch name"
+	p.stashBranchInput.Prompt = ""
+	p.stashBranchInput.CharLimit = 100
+	p.stashBranchInput.Width = 40
+	p.stashBranchInput.Focus()
+	p.stashBranchError = ""
+	p.stashBranchReturnMode = p.viewMode
+	p.clearStashBranchModal()
+	p.viewMode = ViewModeStashBranch
+}
+
+// updateStashBranch handles key events in the branch prompt.
+func (p *Plugin) updateStashBranch(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureStashBranchModal()
+	if p.stashBranchModal == nil {
+		return p, nil
+	}
+	action, cmd := p.stashBranchModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleStashBranchAction(action))
+}
+
+// handleStashBranchAction runs a modal action returned by key or mouse input.
+// The prompt stays open until the branch exists so errors can be corrected.
+func (p *Plugin) handleStashBranchAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeStashBranch()
+	case stashBranchCreateID, stashBranchInputID:
+		name := strings.TrimSpace(p.stashBranchInput.Value())
+		if name == "" {
+			p.stashBranchError = "Branch name is required"
+			return nil
+		}
+		s := p.stashBranchTarget
+		if s == nil {
+			return nil
+		}
+		workDir := p.repoRoot
+		return func() tea.Msg {
+			err := StashBranch(workDir, name, s.Ref)
+			return StashResultMsg{Operation: "branch", Ref: s.Ref, Detail: name, Err: err}
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) closeStashBranch() {
+	p.viewMode = p.stashBranchReturnMode
+	p.stashBranchTarget = nil
+	p.stashBranchError = ""
+	p.clearStashBranchModal()
+}
+
+func (p *Plugin) clearStashBranchModal() {
+	p.stashBranchModal = nil
+	p.stashBranchModalWidth = 0
+}
+
+// ensureStashBranchModal builds/rebuilds the branch prompt.
+func (p *Plugin) ensureStashBranchModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.stashBranchModal != nil && p.stashBranchModalWidth == modalW {
+		return
+	}
+	p.stashBranchModalWidth = modalW
+	if p.stashBranchTarget == nil {
+		return
+	}
+
+	p.stashBranchModal = modal.New("Branch from "+p.stashBranchTarget.Ref,
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(stashBranchCreateID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.InputWithLabel(stashBranchInputID, "Name:", &p.stashBranchInput, modal.WithSubmitAction(stashBranchCreateID))).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := styles.Muted.Render("Checks out a new branch at the stash's base commit and pops the stash onto it.")
+			if p.stashBranchError != "" {
+				content += "\n\n" + styles.StatusDeleted.Render(ansi.Truncate(p.stashBranchError, contentWidth, "…"))
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Create ", stashBranchCreateID),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// renderStashBranch renders the branch prompt over the view it was opened from.
+func (p *Plugin) renderStashBranch() string {
+	background := p.renderStashes()
+	if p.stashBranchReturnMode == ViewModeStash {
+		background = p.renderStash()
+	}
+
+	p.ensureStashBranchModal()
+	if p.stashBranchModal == nil {
+		return background
+	}
+
+	modalContent := p.stashBranchModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openStashDrop opens the drop confirmation for a stash.
+func (p *Plugin) openStashDrop(s *Stash) {
+	p.stashDrop = s
+	p.clearStashDropModal()
+	p.viewMode = ViewModeConfirmStashDrop
+}
+
+// updateConfirmStashDrop handles key events in the drop confirmation.
+func (p *Plugin) updateConfirmStashDrop(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureStashDropModal()
+	if p.stashDropModal == nil {
+		return p, nil
+	}
+	switch m.String() {
+	case "y":
+		return p, p.handleStashDropAction(stashDropID)
+	case "q":
+		p.closeStashDrop()
+		return p, nil
+	}
+	action, cmd := p.stashDropModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleStashDropAction(action))
+}
+
+// handleStashDropAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleStashDropAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeStashDrop()
+	case stashDropID:
+		s := p.stashDrop
+		p.closeStashDrop()
+		if s == nil {
+			return nil
+		}
+		workDir := p.repoRoot
+		return func() tea.Msg {
+			err := StashDrop(workDir, s.Ref)
+			return StashResultMsg{Operation: "drop", Ref: s.Ref, Err: err}
+		}
+	}
+	return nil
+}
+
+// closeStashDrop returns to the stash list.
+func (p *Plugin) closeStashDrop() {
+	p.viewMode = ViewModeStashes
+	p.stashDrop = nil
+	p.clearStashDropModal()
+}
+
+func (p *Plugin) clearStashDropModal() {
+	p.stashDropModal = nil
+	p.stashDropModalWidth = 0
+}
+
+// ensureStashDropModal builds/rebuilds the drop confirmation.
+func (p *Plugin) ensureStashDropModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.stashDropModal != nil && p.stashDropModalWidth == modalW {
+		return
+	}
+	p.stashDropModalWidth = modalW
+	if p.stashDrop == nil {
+		return
+	}
+
+	s := p.stashDrop
+	p.stashDropModal = modal.New("Drop "+s.Ref,
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantDanger),
+		modal.WithPrimaryAction(stashDropID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := styles.Subtitle.Render(s.Branch) + " " + ansi.Truncate(s.Message, max(contentWidth-ansi.StringWidth(s.Branch)-1, 10), "…")
+			content += "\n\n" + styles.Muted.Render("The stash commit stays in the reflog until it expires.")
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Drop ", stashDropID, modal.BtnDanger()),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// renderConfirmStashDrop renders the drop confirmation over the stash list.
+func (p *Plugin) renderConfirmStashDrop() string {
+	background := p.renderStashes()
+
+	p.ensureStashDropModal()
+	if p.stashDropModal == nil {
+		return background
+	}
+
+	modalContent := p.stashDropModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
@@ -0,0 +1,244 @@
+package gitstatus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SubmoduleState is the state git status reports for a submodule entry.
+type SubmoduleState struct {
+	CommitChanged bool // Checked-out commit differs from the recorded one
+	Modified      bool // Tracked files changed inside the submodule
+	Untracked     bool // Untracked files inside the submodule
+}
+
+// Dirty reports whether the submodule has uncommitted changes of its own.
+func (s *SubmoduleState) Dirty() bool {
+	return s.Modified || s.Untracked
+}
+
+// parseSubmoduleState parses the <sub> field of a porcelain v2 entry:
+// "N..." for ordinary files, "S<c><m><u>" for submodules.
+func parseSubmoduleState(field string) *SubmoduleState {
+	if len(field) != 4 || field[0] != 'S' {
+		return nil
+	}
+	return &SubmoduleState{
+		CommitChanged: field[1] == 'C',
+		Modified:      field[2] == 'M',
+		Untracked:     field[3] == 'U',
+	}
+}
+
+// Submodule is a submodule of the repository with its drift from the
+// commit the superproject records.
+type Submodule struct {
+	Path        string
+	URL         string
+	Recorded    string // Commit recorded in the index
+	Checkout    string // Commit checked out, "" if not initialized
+	Initialized bool
+	Conflict    bool
+	Dirty       bool // Uncommitted changes inside the submodule
+	Ahead       int  // Checked-out commits the recorded commit lacks
+	Behind      int  // Recorded commits the checkout lacks
+	DriftKnown  bool // Ahead and Behind are set; false if a commit isn't fetched
+}
+
+// Drifted reports whether the checkout is at another commit than recorded.
+func (s *Submodule) Drifted() bool {
+	return s.Initialized && s.Checkout != s.Recorded
+}
+
+// SubmoduleDrift is a submodule with the commits between its recorded and
+// checked-out commits and its uncommitted changes.
+type SubmoduleDrift struct {
+	*Submodule
+	New     []*Commit // In the checkout, not in the recorded commit
+	Missing []*Commit // In the recorded commit, not in the checkout
+	Changes []string  // git status --short lines inside the submodule
+}
+
+// GetSubmodules lists the submodules, optionally limited to paths.
+func GetSubmodules(workDir string, paths ...string) ([]*Submodule, error) {
+	urls := submoduleURLs(workDir)
+
+	args := append([]string{"submodule", "status", "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, &RemoteError{Output: strings.TrimSpace(stderr.String()), Err: err}
+	}
+
+	var subs []*Submodule
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		s := parseSubmoduleStatusLine(scanner.Text())
+		if s == nil {
+			continue
+		}
+		s.URL = urls[s.Path]
+		loadSubmoduleDetails(workDir, s)
+		subs = append(subs, s)
+	}
+	return subs, nil
+}
+
+// parseSubmoduleStatusLine parses a git submodule status line:
+// "<flag><sha> <path>[ (<describe>)]". The flag is ' ' when the checkout
+// matches the index, '+' when it differs, '-' when not initialized and 'U'
+// when conflicted.
+func parseSubmoduleStatusLine(line string) *Submodule {
+	if len(line) < 42 {
+		return nil
+	}
+	flag, hash := line[0], line[1:41]
+	path := line[42:]
+	if i := strings.LastIndex(path, " ("); i >= 0 && strings.HasSuffix(path, ")") {
+		path = path[:i]
+	}
+
+	s := &Submodule{Path: path, Recorded: hash, Checkout: hash, Initialized: true}
+	switch flag {
+	case '-':
+		s.Checkout = ""
+		s.Initialized = false
+	case '+':
+		s.Recorded = ""
+	case 'U':
+		s.Conflict = true
+	}
+	return s
+}
+
+// loadSubmoduleDetails fills in the recorded commit of a drifted submodule,
+// its ahead/behind counts and whether it has uncommitted changes.
+func loadSubmoduleDetails(workDir string, s *Submodule) {
+	if s.Recorded == "" {
+		cmd := exec.Command("git", "rev-parse", ":"+s.Path)
+		cmd.Dir = workDir
+		if output, err := cmd.Output(); err == nil {
+			s.Recorded = strings.TrimSpace(string(output))
+		}
+	}
+	if !s.Initialized || s.Conflict {
+		return
+	}
+
+	subDir := filepath.Join(workDir, s.Path)
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = subDir
+	if output, err := cmd.Output(); err == nil {
+		s.Dirty = len(bytes.TrimSpace(output)) > 0
+	}
+
+	if s.Recorded == s.Checkout {
+		s.DriftKnown = true
+		return
+	}
+	if s.Recorded == "" {
+		return
+	}
+	// Fails when the recorded commit hasn't been fetched into the submodule
+	if ahead, behind, err := CountAheadBehind(subDir, s.Recorded, s.Checkout); err == nil {
+		s.Ahead, s.Behind, s.DriftKnown = ahead, behind, true
+	}
+}
+
+// submoduleURLs maps submodule paths to their URLs from .gitmodules.
+func submoduleURLs(workDir string) map[string]string {
+	cmd := exec.Command("git", "config", "-f", ".gitmodules", "--get-regexp", `^submodule\..*\.(path|url)$`)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	paths := make(map[string]string)
+	urls := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(key, ".path"):
+			paths[strings.TrimSuffix(key, ".path")] = value
+		case strings.HasSuffix(key, ".url"):
+			urls[strings.TrimSuffix(key, ".url")] = value
+		}
+	}
+
+	byPath := make(map[string]string, len(paths))
+	for name, path := range paths {
+		byPath[path] = urls[name]
+	}
+	return byPath
+}
+
+// GetSubmoduleDrift loads a submodule with the commits it drifted by and
+// its uncommitted changes.
+func GetSubmoduleDrift(workDir, path string) (*SubmoduleDrift, error) {
+	subs, err := GetSubmodules(workDir, path)
+	if err != nil {
+		return nil, err
+	}
+	var sub *Submodule
+	for _, s := range subs {
+		if s.Path == path {
+			sub = s
+		}
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("%s is not a submodule", path)
+	}
+
+	drift := &SubmoduleDrift{Submodule: sub}
+	if !sub.Initialized || sub.Conflict {
+		return drift, nil
+	}
+	subDir := filepath.Join(workDir, path)
+	if sub.Drifted() && sub.DriftKnown {
+		drift.New, _ = logRange(subDir, sub.Recorded+".."+sub.Checkout)
+		drift.Missing, _ = logRange(subDir, sub.Checkout+".."+sub.Recorded)
+	}
+	if sub.Dirty {
+		cmd := exec.Command("git", "status", "--short")
+		cmd.Dir = subDir
+		if output, err := cmd.Output(); err == nil {
+			for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+				if line != "" {
+					drift.Changes = append(drift.Changes, line)
+				}
+			}
+		}
+	}
+	return drift, nil
+}
+
+// UpdateSubmodule checks out the recorded commit of the submodule at path,
+// or of every submodule when path is empty, initializing them as needed.
+func UpdateSubmodule(workDir, path string) error {
+	args := []string{"submodule", "update", "--init", "--recursive"}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	return runGit(workDir, args...)
+}
+
+// SyncSubmodule copies the submodule URL from .gitmodules into the
+// submodule's config, for path or every submodule when path is empty.
+func SyncSubmodule(workDir, path string) error {
+	args := []string{"submodule", "sync", "--recursive"}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	return runGit(workDir, args...)
+}
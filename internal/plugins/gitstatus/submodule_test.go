@@ -0,0 +1,181 @@
+package gitstatus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+// initSubmoduleRepo creates a repo with the repo lib added as a submodule
+// at "lib".
+func initSubmoduleRepo(t *testing.T) (dir, lib string) {
+	t.Helper()
+	// Cloning a local submodule needs the file protocol; the clone has no
+	// user config of its own.
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	lib = initPartialRepo(t, "lib\n")
+	dir = initPartialRepo(t, "one\n")
+	gitRun(t, dir, "submodule", "add", "-q", lib, "lib")
+	gitRun(t, dir, "commit", "-m", "add lib")
+	return dir, lib
+}
+
+func TestParseSubmoduleState(t *testing.T) {
+	if s := parseSubmoduleState("N..."); s != nil {
+		t.Errorf("ordinary file parsed as %+v", s)
+	}
+	s := parseSubmoduleState("SC.U")
+	if s == nil || !s.CommitChanged || s.Modified || !s.Untracked || !s.Dirty() {
+		t.Errorf("SC.U = %+v", s)
+	}
+	if s := parseSubmoduleState("S..."); s == nil || s.CommitChanged || s.Dirty() {
+		t.Errorf("S... = %+v", s)
+	}
+}
+
+func TestParseSubmoduleStatusLine(t *testing.T) {
+	hash := strings.Repeat("a", 40)
+	tests := []struct {
+		line        string
+		path        string
+		initialized bool
+		conflict    bool
+		drifted     bool
+	}{
+		{" " + hash + " lib (heads/main)", "lib", true, false, false},
+		{"+" + hash + " deps/my lib (v1.0-2-gabcdef0)", "deps/my lib", true, false, true},
+		{"-" + hash + " vendor", "vendor", false, false, false},
+		{"U" + hash + " lib", "lib", true, true, false},
+	}
+	for _, tt := range tests {
+		s := parseSubmoduleStatusLine(tt.line)
+		if s == nil {
+			t.Fatalf("%q not parsed", tt.line)
+		}
+		if s.Path != tt.path || s.Initialized != tt.initialized || s.Conflict != tt.conflict || s.Drifted() != tt.drifted {
+			t.Errorf("%q = %+v", tt.line, s)
+		}
+	}
+	if s := parseSubmoduleStatusLine("short"); s != nil {
+		t.Errorf("short line parsed as %+v", s)
+	}
+}
+
+func TestGetSubmodulesDrift(t *testing.T) {
+	dir, lib := initSubmoduleRepo(t)
+	subDir := filepath.Join(dir, "lib")
+
+	subs, err := GetSubmodules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].Path != "lib" || subs[0].URL != lib || subs[0].Drifted() || subs[0].Dirty {
+		t.Fatalf("in sync submodules = %+v", subs)
+	}
+
+	// Move the checkout one commit ahead and leave an untracked file
+	commitFile(t, subDir, "new.txt", "new\n", "new in lib")
+	if err := os.WriteFile(filepath.Join(subDir, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := GetSubmoduleDrift(dir, "lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !drift.Drifted() || !drift.DriftKnown || drift.Ahead != 1 || drift.Behind != 0 || !drift.Dirty {
+		t.Errorf("drifted submodule = %+v", drift.Submodule)
+	}
+	if len(drift.New) != 1 || drift.New[0].Subject != "new in lib" || len(drift.Missing) != 0 {
+		t.Errorf("new/missing = %+v / %+v", drift.New, drift.Missing)
+	}
+	if len(drift.Changes) != 1 || drift.Changes[0] != "?? scratch.txt" {
+		t.Errorf("changes = %q", drift.Changes)
+	}
+
+	tree := NewFileTree(dir)
+	if err := tree.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Modified) != 1 || tree.Modified[0].Path != "lib" || tree.Modified[0].Submodule == nil ||
+		!tree.Modified[0].Submodule.CommitChanged || !tree.Modified[0].Submodule.Untracked {
+		t.Errorf("status entries = %+v", tree.Modified)
+	}
+
+	if _, err := GetSubmoduleDrift(dir, "file.txt"); err == nil {
+		t.Error("expected error for a path that is not a submodule")
+	}
+}
+
+func TestUpdateAndSyncSubmodule(t *testing.T) {
+	dir, _ := initSubmoduleRepo(t)
+	subDir := filepath.Join(dir, "lib")
+	commitFile(t, subDir, "new.txt", "new\n", "new in lib")
+
+	if err := UpdateSubmodule(dir, "lib"); err != nil {
+		t.Fatal(err)
+	}
+	subs, err := GetSubmodules(dir, "lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].Drifted() {
+		t.Errorf("after update = %+v", subs)
+	}
+	if err := SyncSubmodule(dir, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deinitialized submodules are initialized again by update
+	gitRun(t, dir, "submodule", "deinit", "-q", "-f", "lib")
+	subs, _ = GetSubmodules(dir)
+	if len(subs) != 1 || subs[0].Initialized || subs[0].Checkout != "" || subs[0].Recorded == "" {
+		t.Fatalf("deinitialized = %+v", subs)
+	}
+	if isRepoRoot(subDir) {
+		t.Error("uninitialized submodule reported as a repo")
+	}
+	if err := UpdateSubmodule(dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !isRepoRoot(subDir) {
+		t.Error("submodule not checked out after update")
+	}
+}
+
+func TestEnterAndLeaveSubmodule(t *testing.T) {
+	dir, _ := initSubmoduleRepo(t)
+	p := &Plugin{ctx: &plugin.Context{WorkDir: dir}, repoRoot: dir, tree: NewFileTree(dir), hasRepo: true}
+	p.cursor = 3
+
+	if cmd := p.enterSubmodule("lib"); cmd == nil {
+		t.Fatal("expected reload commands")
+	}
+	if p.repoRoot != filepath.Join(dir, "lib") || len(p.submoduleStack) != 1 || p.cursor != 0 {
+		t.Errorf("after enter: root %s, stack %v, cursor %d", p.repoRoot, p.submoduleStack, p.cursor)
+	}
+	if got := p.submodulePath(); got != "lib" {
+		t.Errorf("submodule path = %q", got)
+	}
+
+	p.leaveSubmodule()
+	if p.repoRoot != dir || len(p.submoduleStack) != 0 || p.submodulePath() != "" {
+		t.Errorf("after leave: root %s, stack %v", p.repoRoot, p.submoduleStack)
+	}
+
+	// Directories that aren't checked out stay in the superproject
+	p.enterSubmodule("missing")
+	if p.repoRoot != dir || len(p.submoduleStack) != 0 {
+		t.Errorf("entered a missing submodule: root %s", p.repoRoot)
+	}
+}
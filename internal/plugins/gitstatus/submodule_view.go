@@ -0,0 +1,643 @@
+package gitstatus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const submoduleItemPrefix = "submodule-item-"
+
+func submoduleItemID(idx int) string {
+	return fmt.Sprintf("%s%d", submoduleItemPrefix, idx)
+}
+
+func parseSubmoduleItem(id string) (int, bool) {
+	if !strings.HasPrefix(id, submoduleItemPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, submoduleItemPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// SubmoduleDriftLoadedMsg carries the drift of the submodule selected in the
+// sidebar.
+type SubmoduleDriftLoadedMsg struct {
+	Epoch uint64
+	Path  string
+	Drift *SubmoduleDrift
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SubmoduleDriftLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// SubmodulesLoadedMsg carries the submodule list.
+type SubmodulesLoadedMsg struct {
+	Epoch      uint64
+	Submodules []*Submodule
+	Err        error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SubmodulesLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// SubmoduleActionDoneMsg reports the result of a submodule update or sync.
+// Path is empty when the action ran on every submodule.
+type SubmoduleActionDoneMsg struct {
+	Epoch  uint64
+	Action string // "update" or "sync"
+	Path   string
+	Err    error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SubmoduleActionDoneMsg) GetEpoch() uint64 { return m.Epoch }
+
+// submoduleMarker labels a submodule entry in the sidebar.
+func submoduleMarker(s *SubmoduleState) string {
+	var flags []string
+	if s.CommitChanged {
+		flags = append(flags, "drifted")
+	}
+	if s.Dirty() {
+		flags = append(flags, "dirty")
+	}
+	if len(flags) == 0 {
+		return "[sub]"
+	}
+	return "[sub: " + strings.Join(flags, ", ") + "]"
+}
+
+// submoduleDriftLabel describes where a submodule's checkout is relative to
+// the recorded commit.
+func submoduleDriftLabel(s *Submodule) string {
+	var label string
+	switch {
+	case !s.Initialized:
+		return "not initialized"
+	case s.Conflict:
+		return "conflicted"
+	case !s.Drifted():
+		label = "at recorded commit"
+	case !s.DriftKnown:
+		label = "moved, recorded commit not fetched"
+	default:
+		var parts []string
+		if s.Ahead > 0 {
+			parts = append(parts, fmt.Sprintf("%d new", s.Ahead))
+		}
+		if s.Behind > 0 {
+			parts = append(parts, fmt.Sprintf("%d missing", s.Behind))
+		}
+		label = strings.Join(parts, ", ")
+	}
+	if s.Dirty {
+		label += ", dirty"
+	}
+	return label
+}
+
+// shortSHA abbreviates a commit hash for display.
+func shortSHA(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// submodulePath returns the path of the repo being shown below the
+// top-level repo, or "" when not inside a submodule.
+func (p *Plugin) submodulePath() string {
+	if len(p.submoduleStack) == 0 {
+		return ""
+	}
+	rel, err := filepath.Rel(p.submoduleStack[0], p.repoRoot)
+	if err != nil {
+		return filepath.Base(p.repoRoot)
+	}
+	return rel
+}
+
+// selectedSubmoduleEntry returns the sidebar entry shown in the diff pane
+// when it is a submodule.
+func (p *Plugin) selectedSubmoduleEntry() *FileEntry {
+	if p.cursorOnCommit() {
+		return nil
+	}
+	entries := p.tree.AllEntries()
+	if p.cursor >= len(entries) {
+		return nil
+	}
+	entry := entries[p.cursor]
+	if entry.Submodule == nil || entry.Path != p.selectedDiffFile {
+		return nil
+	}
+	return entry
+}
+
+// loadSubmoduleDrift loads the drift of a submodule for the diff pane.
+func (p *Plugin) loadSubmoduleDrift(path string) tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		drift, err := GetSubmoduleDrift(workDir, path)
+		return SubmoduleDriftLoadedMsg{Epoch: epoch, Path: path, Drift: drift, Err: err}
+	}
+}
+
+// handleSubmoduleDriftLoaded shows the drift if its submodule is still
+// selected.
+func (p *Plugin) handleSubmoduleDriftLoaded(m SubmoduleDriftLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || m.Path != p.selectedDiffFile {
+		return nil
+	}
+	p.submoduleDrift = m.Drift
+	p.submoduleDriftErr = ""
+	if m.Err != nil {
+		p.submoduleDriftErr = m.Err.Error()
+	}
+	return nil
+}
+
+// renderSubmoduleDrift renders the diff pane for a submodule entry: its
+// recorded and checked-out commits, the commits between them and its
+// uncommitted changes.
+func (p *Plugin) renderSubmoduleDrift(entry *FileEntry, visibleHeight int) string {
+	width := max(p.diffPaneWidth-4, 20)
+	header := truncateDiffPath(entry.Path, p.diffPaneWidth-20) + " [submodule]"
+
+	lines := []string{styles.Title.Render(header), ""}
+	d := p.submoduleDrift
+	switch {
+	case p.submoduleDriftErr != "":
+		lines = append(lines, styles.StatusDeleted.Render(p.submoduleDriftErr))
+	case d == nil || d.Path != entry.Path:
+		lines = append(lines, styles.Muted.Render("Loading submodule..."))
+	default:
+		lines = append(lines, submoduleDriftLines(d, width)...)
+	}
+
+	lines = append(lines, "", styles.Muted.Render("enter open submodule  M update or sync"))
+	if len(lines) > visibleHeight {
+		lines = lines[:max(visibleHeight, 1)]
+	}
+	for i, line := range lines {
+		if ansi.StringWidth(line) > width {
+			lines[i] = ansi.Truncate(line, width, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// submoduleDriftLines renders the body of the submodule drift pane.
+func submoduleDriftLines(d *SubmoduleDrift, width int) []string {
+	label := func(s string) string { return styles.Muted.Render(fmt.Sprintf("%-10s", s)) }
+
+	var lines []string
+	if d.URL != "" {
+		lines = append(lines, label("URL")+d.URL)
+	}
+	lines = append(lines, label("Recorded")+styles.Code.Render(shortSHA(d.Recorded)))
+	checkout := label("Checkout") + styles.Code.Render(shortSHA(d.Checkout))
+	state := submoduleDriftLabel(d.Submodule)
+	switch {
+	case !d.Initialized || d.Conflict:
+		checkout += "  " + styles.StatusDeleted.Render(state)
+	case d.Drifted() || d.Dirty:
+		checkout += "  " + styles.StatusModified.Render(state)
+	default:
+		checkout += "  " + styles.StatusStaged.Render(state)
+	}
+	lines = append(lines, checkout)
+
+	if !d.Initialized {
+		lines = append(lines, "", styles.Muted.Render("Update the submodule to check out the recorded commit."))
+		return lines
+	}
+
+	commitLine := func(c *Commit) string {
+		subject := ansi.Truncate(c.Subject, max(width-12, 10), "…")
+		return "  " + styles.Code.Render(c.ShortHash) + " " + subject
+	}
+	if len(d.New) > 0 {
+		lines = append(lines, "", styles.Subtitle.Render(fmt.Sprintf("New in checkout (%d)", len(d.New))))
+		for _, c := range d.New {
+			lines = append(lines, commitLine(c))
+		}
+	}
+	if len(d.Missing) > 0 {
+		lines = append(lines, "", styles.Subtitle.Render(fmt.Sprintf("Recorded but not checked out (%d)", len(d.Missing))))
+		for _, c := range d.Missing {
+			lines = append(lines, commitLine(c))
+		}
+	}
+	if len(d.Changes) > 0 {
+		lines = append(lines, "", styles.Subtitle.Render(fmt.Sprintf("Uncommitted changes (%d)", len(d.Changes))))
+		for _, change := range d.Changes {
+			lines = append(lines, "  "+change)
+		}
+	}
+	return lines
+}
+
+// isRepoRoot reports whether dir is the top level of its own repository,
+// which an uninitialized submodule directory is not.
+func isRepoRoot(dir string) bool {
+	root, err := resolveGitRoot(dir)
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	return filepath.Clean(root) == filepath.Clean(dir)
+}
+
+// enterSubmodule shows the submodule at path, relative to the repo being
+// shown, as a repository of its own. esc returns to the superproject.
+func (p *Plugin) enterSubmodule(path string) tea.Cmd {
+	dir := filepath.Join(p.repoRoot, path)
+	if !isRepoRoot(dir) {
+		return notInitializedToast(path)
+	}
+	p.submoduleStack = append(p.submoduleStack, p.repoRoot)
+	return p.switchRepoRoot(dir)
+}
+
+// leaveSubmodule returns to the superproject of the submodule being shown.
+func (p *Plugin) leaveSubmodule() tea.Cmd {
+	if len(p.submoduleStack) == 0 {
+		return nil
+	}
+	last := len(p.submoduleStack) - 1
+	root := p.submoduleStack[last]
+	p.submoduleStack = p.submoduleStack[:last]
+	return p.switchRepoRoot(root)
+}
+
+// handleOpenSubmoduleRequest opens a submodule picked in another plugin.
+// The path is relative to the project directory, so the submodule is
+// entered from the top-level repo.
+func (p *Plugin) handleOpenSubmoduleRequest(m app.OpenSubmoduleMsg) tea.Cmd {
+	dir := m.Path
+	if p.ctx != nil && p.ctx.WorkDir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(p.ctx.WorkDir, dir)
+	}
+	if !isRepoRoot(dir) {
+		return notInitializedToast(m.Path)
+	}
+	top := p.repoRoot
+	if len(p.submoduleStack) > 0 {
+		top = p.submoduleStack[0]
+	}
+	p.submoduleStack = []string{top}
+	p.viewMode = ViewModeStatus
+	return p.switchRepoRoot(dir)
+}
+
+func notInitializedToast(path string) tea.Cmd {
+	return func() tea.Msg {
+		return app.ToastMsg{
+			Message:  path + " is not checked out; update it from the submodule list (M)",
+			Duration: 3 * time.Second,
+			IsError:  true,
+		}
+	}
+}
+
+// switchRepoRoot shows another repository: the file tree, commits and
+// watcher are reset and reloaded for root.
+func (p *Plugin) switchRepoRoot(root string) tea.Cmd {
+	if p.watcher != nil {
+		p.watcher.Stop()
+		p.watcher = nil
+	}
+	p.repoRoot = root
+	p.tree = NewFileTree(root)
+
+	p.cursor = 0
+	p.scrollOff = 0
+	p.commitScrollOff = 0
+	p.recentCommits = nil
+	p.moreCommitsAvailable = false
+	p.loadingMoreCommits = false
+	p.pushStatus = nil
+	p.commitGraphLines = nil
+	p.historyFilterActive = false
+	p.historyFilterAuthor = ""
+	p.historyFilterPath = ""
+	p.filteredCommits = nil
+	p.clearSearchState()
+
+	p.selectedDiffFile = ""
+	p.diffPaneScroll = 0
+	p.diffPaneParsedDiff = nil
+	p.diffSelection = DiffSelection{}
+	p.previewCommit = nil
+	p.submoduleDrift = nil
+	p.submoduleDriftErr = ""
+	p.activePane = PaneSidebar
+
+	return tea.Batch(p.refresh(), p.startWatcher(), p.loadRecentCommits())
+}
+
+// openSubmodules opens the submodule list.
+func (p *Plugin) openSubmodules() tea.Cmd {
+	p.submodules = nil
+	p.submodulesLoaded = false
+	p.submoduleCursor = 0
+	p.submoduleBusy = ""
+	p.clearSubmodulesModal()
+	p.viewMode = ViewModeSubmodules
+	return p.loadSubmodules()
+}
+
+// loadSubmodules reads the submodules with their drift.
+func (p *Plugin) loadSubmodules() tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		subs, err := GetSubmodules(workDir)
+		return SubmodulesLoadedMsg{Epoch: epoch, Submodules: subs, Err: err}
+	}
+}
+
+// handleSubmodulesLoaded stores the loaded submodule list.
+func (p *Plugin) handleSubmodulesLoaded(m SubmodulesLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) || p.viewMode != ViewModeSubmodules {
+		return nil
+	}
+	if m.Err != nil {
+		p.closeSubmodules()
+		p.showErrorModal("Submodules Failed", m.Err)
+		return nil
+	}
+	p.submodules = m.Submodules
+	p.submodulesLoaded = true
+	p.submoduleCursor = min(p.submoduleCursor, max(len(p.submodules)-1, 0))
+	return nil
+}
+
+// selectedSubmodule returns the submodule under the cursor, if any.
+func (p *Plugin) selectedSubmodule() *Submodule {
+	if p.submoduleCursor < 0 || p.submoduleCursor >= len(p.submodules) {
+		return nil
+	}
+	return p.submodules[p.submoduleCursor]
+}
+
+// updateSubmodules handles key events in the submodule list.
+func (p *Plugin) updateSubmodules(m tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureSubmodulesModal()
+	if p.submodulesModal == nil {
+		return p, nil
+	}
+
+	switch m.String() {
+	case "esc", "q":
+		p.closeSubmodules()
+		// Updates may have moved the submodule shown in the diff pane
+		return p, p.autoLoadPreview(true)
+	case "j", "down":
+		p.submoduleCursor = min(p.submoduleCursor+1, max(len(p.submodules)-1, 0))
+		return p, nil
+	case "k", "up":
+		p.submoduleCursor = max(p.submoduleCursor-1, 0)
+		return p, nil
+	case "g":
+		p.submoduleCursor = 0
+		return p, nil
+	case "G":
+		p.submoduleCursor = max(len(p.submodules)-1, 0)
+		return p, nil
+	case "enter":
+		if s := p.selectedSubmodule(); s != nil {
+			p.closeSubmodules()
+			return p, p.enterSubmodule(s.Path)
+		}
+		return p, nil
+	case "u":
+		if s := p.selectedSubmodule(); s != nil {
+			return p, p.runSubmoduleAction("update", s.Path)
+		}
+		return p, nil
+	case "s":
+		if s := p.selectedSubmodule(); s != nil {
+			return p, p.runSubmoduleAction("sync", s.Path)
+		}
+		return p, nil
+	case "U":
+		if len(p.submodules) > 0 {
+			return p, p.runSubmoduleAction("update", "")
+		}
+		return p, nil
+	case "S":
+		if len(p.submodules) > 0 {
+			return p, p.runSubmoduleAction("sync", "")
+		}
+		return p, nil
+	}
+
+	action, cmd := p.submodulesModal.HandleKey(m)
+	return p, tea.Batch(cmd, p.handleSubmodulesAction(action))
+}
+
+// handleSubmodulesAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleSubmodulesAction(action string) tea.Cmd {
+	if action == "cancel" {
+		p.closeSubmodules()
+		return p.autoLoadPreview(true)
+	}
+	if idx, ok := parseSubmoduleItem(action); ok && idx < len(p.submodules) {
+		p.submoduleCursor = idx
+	}
+	return nil
+}
+
+func (p *Plugin) closeSubmodules() {
+	p.viewMode = ViewModeStatus
+	p.submodules = nil
+	p.submodulesLoaded = false
+	p.submoduleBusy = ""
+	p.clearSubmodulesModal()
+}
+
+func (p *Plugin) clearSubmodulesModal() {
+	p.submodulesModal = nil
+	p.submodulesModalWidth = 0
+}
+
+// runSubmoduleAction updates or syncs the submodule at path, or every
+// submodule when path is empty.
+func (p *Plugin) runSubmoduleAction(action, path string) tea.Cmd {
+	if p.submoduleBusy != "" {
+		return nil
+	}
+	p.submoduleBusy = action
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		var err error
+		switch action {
+		case "update":
+			err = UpdateSubmodule(workDir, path)
+		case "sync":
+			err = SyncSubmodule(workDir, path)
+		}
+		return SubmoduleActionDoneMsg{Epoch: epoch, Action: action, Path: path, Err: err}
+	}
+}
+
+// handleSubmoduleActionDone reports a finished update or sync and reloads
+// the submodule list and file tree.
+func (p *Plugin) handleSubmoduleActionDone(m SubmoduleActionDoneMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, m) {
+		return nil
+	}
+	p.submoduleBusy = ""
+	if m.Err != nil {
+		if p.viewMode == ViewModeSubmodules {
+			p.closeSubmodules()
+		}
+		title := "Submodule Update Failed"
+		if m.Action == "sync" {
+			title = "Submodule Sync Failed"
+		}
+		p.showErrorModal(title, m.Err)
+		return nil
+	}
+
+	target := m.Path
+	if target == "" {
+		target = "all submodules"
+	}
+	verb := "Updated "
+	if m.Action == "sync" {
+		verb = "Synced "
+	}
+	cmds := []tea.Cmd{msg.ShowToast(verb+target, 2*time.Second), p.refresh()}
+	if p.viewMode == ViewModeSubmodules {
+		cmds = append(cmds, p.loadSubmodules())
+	}
+	return tea.Batch(cmds...)
+}
+
+// ensureSubmodulesModal builds/rebuilds the submodule list modal.
+func (p *Plugin) ensureSubmodulesModal() {
+	modalW := p.reflogModalWidthForContent()
+	if p.submodulesModal != nil && p.submodulesModalWidth == modalW {
+		return
+	}
+	p.submodulesModalWidth = modalW
+
+	p.submodulesModal = modal.New("Submodules",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.submodulesListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			if p.submoduleBusy != "" {
+				return modal.RenderedSection{Content: styles.StatusInProgress.Render("  Running submodule " + p.submoduleBusy + "...")}
+			}
+			return modal.RenderedSection{Content: styles.Muted.Render("  Enter open  u update  s sync  U update all  S sync all")}
+		}, nil))
+}
+
+func (p *Plugin) submodulesListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if !p.submodulesLoaded {
+			return modal.RenderedSection{Content: styles.Muted.Render("  Loading submodules...")}
+		}
+		if len(p.submodules) == 0 {
+			return modal.RenderedSection{Content: styles.Muted.Render("  No submodules")}
+		}
+
+		maxVisible := p.reflogMaxVisible()
+		start := 0
+		if p.submoduleCursor >= maxVisible {
+			start = p.submoduleCursor - maxVisible + 1
+		}
+		end := min(start+maxVisible, len(p.submodules))
+
+		var sb strings.Builder
+		focusables := make([]modal.FocusableInfo, 0, end-start)
+		for i := start; i < end; i++ {
+			itemID := submoduleItemID(i)
+			line := renderSubmoduleLine(p.submodules[i], contentWidth, i == p.submoduleCursor || itemID == hoverID)
+			if i > start {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(line)
+			focusables = append(focusables, modal.FocusableInfo{
+				ID:      itemID,
+				OffsetY: i - start,
+				Width:   ansi.StringWidth(line),
+				Height:  1,
+			})
+		}
+
+		content := sb.String()
+		if len(p.submodules) > maxVisible {
+			content += "\n\n" + styles.Muted.Render(fmt.Sprintf("  %d/%d submodules", p.submoduleCursor+1, len(p.submodules)))
+		}
+		return modal.RenderedSection{Content: content, Focusables: focusables}
+	}, nil)
+}
+
+// renderSubmoduleLine renders one submodule: path, checked-out commit and
+// drift.
+func renderSubmoduleLine(s *Submodule, width int, selected bool) string {
+	hash := shortSHA(s.Checkout)
+	state := submoduleDriftLabel(s)
+	pathW := max(width-len(hash)-ansi.StringWidth(state)-6, 10)
+	path := ansi.Truncate(s.Path, pathW, "…")
+	pad := max(width-ansi.StringWidth(path)-len(hash)-ansi.StringWidth(state)-5, 1)
+
+	if selected {
+		return styles.ListItemSelected.Render("  " + path + strings.Repeat(" ", pad) + hash + "  " + state)
+	}
+	stateStyle := styles.Muted
+	switch {
+	case !s.Initialized || s.Conflict:
+		stateStyle = styles.StatusDeleted
+	case s.Drifted() || s.Dirty:
+		stateStyle = styles.StatusModified
+	}
+	return styles.ListItemNormal.Render(
+		"  " + path + strings.Repeat(" ", pad) + styles.Code.Render(hash) + "  " + stateStyle.Render(state))
+}
+
+// renderSubmodules renders the submodule list.
+func (p *Plugin) renderSubmodules() string {
+	background := p.renderThreePaneView()
+
+	p.ensureSubmodulesModal()
+	if p.submodulesModal == nil {
+		return background
+	}
+
+	modalContent := p.submodulesModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
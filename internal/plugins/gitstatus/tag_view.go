@@ -0,0 +1,849 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	tagNameInputID     = "tag-name"
+	tagMessageInputID  = "tag-message"
+	tagCreateID        = "tag-create"
+	tagCreatePushID    = "tag-create-push"
+	tagItemPrefix      = "tag-item-"
+	tagDeleteID        = "tag-delete"
+	tagDeleteRemoteID  = "tag-delete-remote"
+	tagBadgeMaxWidth   = 20
+	releaseNotesIndent = "  "
+)
+
+func tagItemID(idx int) string {
+	return fmt.Sprintf("%s%d", tagItemPrefix, idx)
+}
+
+func parseTagItem(id string) (int, bool) {
+	if !strings.HasPrefix(id, tagItemPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, tagItemPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// tagBadgeStyle renders tag names next to commits.
+var tagBadgeStyle = lipgloss.NewStyle().Foreground(styles.Success)
+
+// renderTagBadge returns "(v1.0.0, latest) " for a commit's tags, or "".
+func renderTagBadge(tags []string, styled bool) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	badge := ansi.Truncate("("+strings.Join(tags, ", ")+")", tagBadgeMaxWidth, "…)")
+	if styled {
+		badge = tagBadgeStyle.Render(badge)
+	}
+	return badge + " "
+}
+
+// TagsLoadedMsg is sent when the tag list has been read.
+type TagsLoadedMsg struct {
+	Epoch uint64
+	Tags  []*Tag
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m TagsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// TagDoneMsg is sent when a tag has been created, deleted or pushed.
+type TagDoneMsg struct {
+	Action  string // "create", "delete" or "push"
+	Name    string // Empty when all tags were pushed
+	Hash    string // Commit the tag pointed at
+	Remote  bool   // Also pushed, or also deleted on the remote
+	Err     error
+	PushErr error // Push after a successful create failed
+}
+
+// ReleaseLoadedMsg carries the changes since a tag.
+type ReleaseLoadedMsg struct {
+	Epoch   uint64
+	Changes *ReleaseChanges
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ReleaseLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openTagCreate opens the tag prompt for target. name and message prefill
+// the inputs.
+func (p *Plugin) openTagCreate(target, label, name, message string) tea.Cmd {
+	p.tagTarget = target
+	p.tagTargetLabel = label
+	p.tagNameInput = textinput.New()
+	p.tagNameInput.Placeholder = "v1.2.3"
+	p.tagNameInput.Prompt = ""
+	p.tagNameInput.CharLimit = 100
+	p.tagNameInput.Width = 40
+	p.tagNameInput.SetValue(name)
+	p.tagNameInput.CursorEnd()
+	p.tagNameInput.Focus()
+	p.tagMessageInput = textinput.New()
+	p.tagMessageInput.Placeholder = "empty for a lightweight tag"
+	p.tagMessageInput.Prompt = ""
+	p.tagMessageInput.CharLimit = 500
+	p.tagMessageInput.Width = 40
+	p.tagMessageInput.SetValue(message)
+	p.tagError = ""
+	p.tagCreateReturnMode = p.viewMode
+	p.clearTagCreateModal()
+	p.viewMode = ViewModeTagCreate
+	return nil
+}
+
+// updateTagCreate handles key events in the tag prompt.
+func (p *Plugin) updateTagCreate(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureTagCreateModal()
+	if p.tagCreateModal == nil {
+		return p, nil
+	}
+	action, cmd := p.tagCreateModal.HandleKey(msg)
+	return p, tea.Batch(cmd, p.handleTagCreateAction(action))
+}
+
+// handleTagCreateAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleTagCreateAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeTagCreate()
+	case tagCreateID, tagNameInputID, tagMessageInputID:
+		return p.doCreateTag(false)
+	case tagCreatePushID:
+		return p.doCreateTag(true)
+	}
+	return nil
+}
+
+func (p *Plugin) closeTagCreate() {
+	p.viewMode = p.tagCreateReturnMode
+	p.tagError = ""
+	p.clearTagCreateModal()
+}
+
+func (p *Plugin) clearTagCreateModal() {
+	p.tagCreateModal = nil
+	p.tagCreateModalWidth = 0
+}
+
+// doCreateTag creates the tag from the prompt, pushing it if requested.
+// The prompt stays open until the tag exists so errors can be corrected.
+func (p *Plugin) doCreateTag(push bool) tea.Cmd {
+	name := strings.TrimSpace(p.tagNameInput.Value())
+	message := strings.TrimSpace(p.tagMessageInput.Value())
+	if name == "" {
+		p.tagError = "Tag name is required"
+		return nil
+	}
+	target := p.tagTarget
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		if err := CreateTag(workDir, name, target, message); err != nil {
+			return TagDoneMsg{Action: "create", Name: name, Err: err}
+		}
+		hash := target
+		if target == "HEAD" {
+			hash = currentHead(workDir).Hash
+		}
+		done := TagDoneMsg{Action: "create", Name: name, Hash: hash, Remote: push}
+		if push {
+			_, done.PushErr = PushTags(workDir, name)
+		}
+		return done
+	}
+}
+
+// handleTagDone reports a tag operation and updates the loaded history.
+func (p *Plugin) handleTagDone(msg TagDoneMsg) tea.Cmd {
+	if msg.Action == "create" && msg.Err != nil && p.viewMode == ViewModeTagCreate {
+		// Keep the prompt open so the name can be corrected
+		p.tagError = msg.Err.Error()
+		return nil
+	}
+
+	var toast string
+	switch msg.Action {
+	case "create":
+		if p.viewMode == ViewModeTagCreate {
+			p.closeTagCreate()
+			if p.viewMode == ViewModeRelease {
+				p.closeRelease() // The release was tagged; nothing left to show
+			}
+		}
+		if msg.Err == nil {
+			p.applyTagChange(msg.Name, msg.Hash, true)
+			toast = "Created tag " + msg.Name
+			if msg.Remote && msg.PushErr == nil {
+				toast += " and pushed it"
+			}
+		}
+	case "delete":
+		if msg.Err == nil {
+			p.applyTagChange(msg.Name, msg.Hash, false)
+			toast = "Deleted tag " + msg.Name
+			if msg.Remote {
+				toast += " locally and on the remote"
+			}
+		}
+	case "push":
+		if msg.Err == nil {
+			toast = "Pushed all tags"
+			if msg.Name != "" {
+				toast = "Pushed tag " + msg.Name
+			}
+		}
+	}
+
+	var cmds []tea.Cmd
+	if p.viewMode == ViewModeTags {
+		cmds = append(cmds, p.loadTags())
+	}
+	switch {
+	case msg.Err != nil:
+		p.showTagError(msg.Action, msg.Err)
+	case msg.PushErr != nil:
+		p.showErrorModal("Tag Push Failed", msg.PushErr)
+	}
+	if toast != "" {
+		cmds = append(cmds, func() tea.Msg {
+			return app.ToastMsg{Message: toast, Duration: 2 * time.Second}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// showTagError shows a failed tag operation in the error modal.
+func (p *Plugin) showTagError(action string, err error) {
+	title := "Tag " + strings.ToUpper(action[:1]) + action[1:] + " Failed"
+	p.showErrorModal(title, err)
+}
+
+// applyTagChange adds or removes a tag on the loaded commits so badges update
+// without reloading the whole history.
+func (p *Plugin) applyTagChange(name, hash string, add bool) {
+	update := func(c *Commit) {
+		if c == nil || c.Hash != hash {
+			return
+		}
+		tags := c.Tags[:0:0]
+		for _, t := range c.Tags {
+			if t != name {
+				tags = append(tags, t)
+			}
+		}
+		if add {
+			tags = append(tags, name)
+		}
+		c.Tags = tags
+	}
+	for _, c := range p.recentCommits {
+		update(c)
+	}
+	for _, c := range p.filteredCommits {
+		update(c)
+	}
+	update(p.previewCommit)
+}
+
+// ensureTagCreateModal builds/rebuilds the tag prompt.
+func (p *Plugin) ensureTagCreateModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.tagCreateModal != nil && p.tagCreateModalWidth == modalW {
+		return
+	}
+	p.tagCreateModalWidth = modalW
+
+	buttons := []modal.ButtonDef{modal.Btn(" Create ", tagCreateID)}
+	if HasRemote(p.repoRoot) {
+		buttons = append(buttons, modal.Btn(" Create & Push ", tagCreatePushID))
+	}
+	buttons = append(buttons, modal.Btn(" Cancel ", "cancel"))
+
+	p.tagCreateModal = modal.New("Tag "+p.tagTargetLabel,
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(tagCreateID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.InputWithLabel(tagNameInputID, "Name:", &p.tagNameInput, modal.WithSubmitAction(tagCreateID))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.InputWithLabel(tagMessageInputID, "Message:", &p.tagMessageInput, modal.WithSubmitAction(tagCreateID))).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := styles.Muted.Render("A message makes an annotated tag.")
+			if p.tagError != "" {
+				content += "\n\n" + styles.StatusDeleted.Render(ansi.Truncate(p.tagError, contentWidth, "…"))
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(buttons...))
+}
+
+// renderTagCreate renders the tag prompt.
+func (p *Plugin) renderTagCreate() string {
+	var background string
+	switch p.tagCreateReturnMode {
+	case ViewModeRelease:
+		background = p.renderRelease()
+	case ViewModeTags:
+		background = p.renderTags()
+	default:
+		background = p.renderThreePaneView()
+	}
+
+	p.ensureTagCreateModal()
+	if p.tagCreateModal == nil {
+		return background
+	}
+
+	modalContent := p.tagCreateModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openTags opens the tag list.
+func (p *Plugin) openTags() tea.Cmd {
+	p.tags = nil
+	p.tagsLoaded = false
+	p.tagCursor = 0
+	p.clearTagsModal()
+	p.viewMode = ViewModeTags
+	return p.loadTags()
+}
+
+// loadTags reads the tag list.
+func (p *Plugin) loadTags() tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		tags, err := GetTags(workDir)
+		return TagsLoadedMsg{Epoch: epoch, Tags: tags, Err: err}
+	}
+}
+
+// handleTagsLoaded stores loaded tags.
+func (p *Plugin) handleTagsLoaded(msg TagsLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, msg) || p.viewMode != ViewModeTags {
+		return nil
+	}
+	if msg.Err != nil {
+		p.closeTags()
+		p.showErrorModal("Tags Failed", msg.Err)
+		return nil
+	}
+	p.tags = msg.Tags
+	p.tagsLoaded = true
+	p.tagCursor = min(p.tagCursor, max(len(p.tags)-1, 0))
+	return nil
+}
+
+// selectedTag returns the tag under the cursor, if any.
+func (p *Plugin) selectedTag() *Tag {
+	if p.tagCursor < 0 || p.tagCursor >= len(p.tags) {
+		return nil
+	}
+	return p.tags[p.tagCursor]
+}
+
+// updateTags handles key events in the tag list.
+func (p *Plugin) updateTags(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureTagsModal()
+	if p.tagsModal == nil {
+		return p, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		p.closeTags()
+		return p, nil
+	case "j", "down":
+		p.tagCursor = min(p.tagCursor+1, max(len(p.tags)-1, 0))
+		return p, nil
+	case "k", "up":
+		p.tagCursor = max(p.tagCursor-1, 0)
+		return p, nil
+	case "g":
+		p.tagCursor = 0
+		return p, nil
+	case "G":
+		p.tagCursor = max(len(p.tags)-1, 0)
+		return p, nil
+	case "enter":
+		// Changes since the selected tag
+		if tag := p.selectedTag(); tag != nil {
+			return p, p.openRelease(tag.Name)
+		}
+		return p, nil
+	case "n":
+		// New tag on HEAD
+		return p, p.openTagCreate("HEAD", "HEAD", "", "")
+	case "p":
+		if tag := p.selectedTag(); tag != nil {
+			return p, p.doPushTags(tag.Name)
+		}
+		return p, nil
+	case "P":
+		return p, p.doPushTags("")
+	case "d":
+		if tag := p.selectedTag(); tag != nil {
+			p.openDeleteTag(tag)
+		}
+		return p, nil
+	}
+
+	action, cmd := p.tagsModal.HandleKey(msg)
+	return p, tea.Batch(cmd, p.handleTagsAction(action))
+}
+
+// handleTagsAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleTagsAction(action string) tea.Cmd {
+	if action == "cancel" {
+		p.closeTags()
+		return nil
+	}
+	if idx, ok := parseTagItem(action); ok && idx < len(p.tags) {
+		p.tagCursor = idx
+		return p.openRelease(p.tags[idx].Name)
+	}
+	return nil
+}
+
+func (p *Plugin) closeTags() {
+	p.viewMode = ViewModeStatus
+	p.tags = nil
+	p.tagsLoaded = false
+	p.clearTagsModal()
+}
+
+func (p *Plugin) clearTagsModal() {
+	p.tagsModal = nil
+	p.tagsModalWidth = 0
+}
+
+// doPushTags pushes one tag, or all tags when name is empty.
+func (p *Plugin) doPushTags(name string) tea.Cmd {
+	workDir := p.repoRoot
+	return tea.Batch(
+		msg.ShowToast("Pushing tags...", 2*time.Second),
+		func() tea.Msg {
+			_, err := PushTags(workDir, name)
+			return TagDoneMsg{Action: "push", Name: name, Remote: true, Err: err}
+		},
+	)
+}
+
+// ensureTagsModal builds/rebuilds the tag list modal.
+func (p *Plugin) ensureTagsModal() {
+	modalW := p.reflogModalWidthForContent()
+	if p.tagsModal != nil && p.tagsModalWidth == modalW {
+		return
+	}
+	p.tagsModalWidth = modalW
+
+	p.tagsModal = modal.New("Tags",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.tagsListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			return modal.RenderedSection{Content: styles.Muted.Render("  Enter changes since tag, n new tag on HEAD, p push, P push all, d delete, Esc close")}
+		}, nil))
+}
+
+func (p *Plugin) tagsListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if !p.tagsLoaded {
+			return modal.RenderedSection{Content: styles.Muted.Render("  Loading tags...")}
+		}
+		if len(p.tags) == 0 {
+			return modal.RenderedSection{Content: styles.Muted.Render("  No tags")}
+		}
+
+		maxVisible := p.reflogMaxVisible()
+		start := 0
+		if p.tagCursor >= maxVisible {
+			start = p.tagCursor - maxVisible + 1
+		}
+		end := min(start+maxVisible, len(p.tags))
+
+		var sb strings.Builder
+		focusables := make([]modal.FocusableInfo, 0, end-start)
+		for i := start; i < end; i++ {
+			itemID := tagItemID(i)
+			line := p.renderTagLine(p.tags[i], contentWidth, i == p.tagCursor || itemID == hoverID)
+			if i > start {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(line)
+			focusables = append(focusables, modal.FocusableInfo{
+				ID:      itemID,
+				OffsetY: i - start,
+				Width:   ansi.StringWidth(line),
+				Height:  1,
+			})
+		}
+
+		content := sb.String()
+		if len(p.tags) > maxVisible {
+			content += "\n\n" + styles.Muted.Render(fmt.Sprintf("  %d/%d tags", p.tagCursor+1, len(p.tags)))
+		}
+		return modal.RenderedSection{Content: content, Focusables: focusables}
+	}, nil)
+}
+
+// renderTagLine renders one tag: name, commit, subject and age.
+func (p *Plugin) renderTagLine(t *Tag, width int, selected bool) string {
+	short := t.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	name := ansi.Truncate(t.Name, 20, "…")
+	prefix := fmt.Sprintf("  %-20s %s ", name, short)
+	suffix := RelativeTime(t.Date)
+	if t.Annotated {
+		suffix = "annotated  " + suffix
+	}
+	textW := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(suffix)-2, 10)
+	text := ansi.Truncate(t.Subject, textW, "…")
+	pad := max(width-ansi.StringWidth(prefix)-ansi.StringWidth(text)-ansi.StringWidth(suffix)-1, 1)
+
+	if selected {
+		return styles.ListItemSelected.Render(prefix + text + strings.Repeat(" ", pad) + suffix)
+	}
+	return styles.ListItemNormal.Render(
+		"  " + tagBadgeStyle.Render(fmt.Sprintf("%-20s", name)) + " " + styles.Code.Render(short) + " " +
+			text + strings.Repeat(" ", pad) + styles.Muted.Render(suffix))
+}
+
+// renderTags renders the tag list.
+func (p *Plugin) renderTags() string {
+	background := p.renderThreePaneView()
+
+	p.ensureTagsModal()
+	if p.tagsModal == nil {
+		return background
+	}
+
+	modalContent := p.tagsModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openDeleteTag opens the delete confirmation for a tag.
+func (p *Plugin) openDeleteTag(tag *Tag) {
+	p.tagDelete = tag
+	p.tagDeleteHasRemote = HasRemote(p.repoRoot)
+	p.clearTagDeleteModal()
+	p.viewMode = ViewModeConfirmDeleteTag
+}
+
+// updateConfirmDeleteTag handles key events in the delete confirmation.
+func (p *Plugin) updateConfirmDeleteTag(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureTagDeleteModal()
+	if p.tagDeleteModal == nil {
+		return p, nil
+	}
+
+	switch msg.String() {
+	case "y":
+		return p, p.handleTagDeleteAction(tagDeleteID)
+	case "r":
+		if p.tagDeleteHasRemote {
+			return p, p.handleTagDeleteAction(tagDeleteRemoteID)
+		}
+	case "q":
+		p.closeDeleteTag()
+		return p, nil
+	}
+
+	action, cmd := p.tagDeleteModal.HandleKey(msg)
+	return p, tea.Batch(cmd, p.handleTagDeleteAction(action))
+}
+
+// handleTagDeleteAction runs a modal action returned by key or mouse input.
+func (p *Plugin) handleTagDeleteAction(action string) tea.Cmd {
+	switch action {
+	case "cancel":
+		p.closeDeleteTag()
+	case tagDeleteID:
+		return p.doDeleteTag(false)
+	case tagDeleteRemoteID:
+		return p.doDeleteTag(true)
+	}
+	return nil
+}
+
+// closeDeleteTag returns to the tag list.
+func (p *Plugin) closeDeleteTag() {
+	p.viewMode = ViewModeTags
+	p.tagDelete = nil
+	p.clearTagDeleteModal()
+}
+
+func (p *Plugin) clearTagDeleteModal() {
+	p.tagDeleteModal = nil
+	p.tagDeleteModalWidth = 0
+}
+
+// doDeleteTag deletes the tag locally, and on the remote if requested.
+func (p *Plugin) doDeleteTag(remote bool) tea.Cmd {
+	tag := p.tagDelete
+	if tag == nil {
+		return nil
+	}
+	workDir := p.repoRoot
+	p.closeDeleteTag()
+	return func() tea.Msg {
+		if remote {
+			// Remote first, so a failure leaves the local tag to retry with
+			if err := DeleteRemoteTag(workDir, tag.Name); err != nil {
+				return TagDoneMsg{Action: "delete", Name: tag.Name, Hash: tag.Hash, Remote: true, Err: err}
+			}
+		}
+		err := DeleteTag(workDir, tag.Name)
+		return TagDoneMsg{Action: "delete", Name: tag.Name, Hash: tag.Hash, Remote: remote, Err: err}
+	}
+}
+
+// ensureTagDeleteModal builds/rebuilds the delete confirmation.
+func (p *Plugin) ensureTagDeleteModal() {
+	modalW := p.commitActionModalWidthForContent()
+	if p.tagDeleteModal != nil && p.tagDeleteModalWidth == modalW {
+		return
+	}
+	p.tagDeleteModalWidth = modalW
+	if p.tagDelete == nil {
+		return
+	}
+
+	buttons := []modal.ButtonDef{modal.Btn(" Delete ", tagDeleteID, modal.BtnDanger())}
+	if p.tagDeleteHasRemote {
+		buttons = append(buttons, modal.Btn(" Delete Everywhere ", tagDeleteRemoteID, modal.BtnDanger()))
+	}
+	buttons = append(buttons, modal.Btn(" Cancel ", "cancel"))
+
+	tag := p.tagDelete
+	short := tag.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	p.tagDeleteModal = modal.New("Delete tag "+tag.Name,
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantDanger),
+		modal.WithPrimaryAction(tagDeleteID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+			content := "Points at " + styles.Code.Render(short) + " " + ansi.Truncate(tag.Subject, max(contentWidth-18, 10), "…")
+			if p.tagDeleteHasRemote {
+				content += "\n\n" + styles.Muted.Render("Delete Everywhere (r) also removes it from the remote.")
+			}
+			return modal.RenderedSection{Content: content}
+		}, nil)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(buttons...))
+}
+
+// renderConfirmDeleteTag renders the delete confirmation over the tag list.
+func (p *Plugin) renderConfirmDeleteTag() string {
+	background := p.renderTags()
+
+	p.ensureTagDeleteModal()
+	if p.tagDeleteModal == nil {
+		return background
+	}
+
+	modalContent := p.tagDeleteModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openRelease opens the changes since tag, or since the latest tag when tag
+// is empty.
+func (p *Plugin) openRelease(tag string) tea.Cmd {
+	p.release = nil
+	p.releaseScroll = 0
+	p.releaseReturnMode = p.viewMode
+	p.viewMode = ViewModeRelease
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		changes, err := GetReleaseChanges(workDir, tag)
+		return ReleaseLoadedMsg{Epoch: epoch, Changes: changes, Err: err}
+	}
+}
+
+// handleReleaseLoaded shows loaded release changes.
+func (p *Plugin) handleReleaseLoaded(msg ReleaseLoadedMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, msg) || p.viewMode != ViewModeRelease {
+		return nil
+	}
+	if msg.Err != nil {
+		p.closeRelease()
+		p.showErrorModal("Changes Failed", msg.Err)
+		return nil
+	}
+	p.release = msg.Changes
+	return nil
+}
+
+// closeRelease returns to the view the release view was opened from.
+func (p *Plugin) closeRelease() {
+	p.viewMode = p.releaseReturnMode
+	if p.viewMode == ViewModeRelease || p.viewMode == ViewModeTagCreate {
+		p.viewMode = ViewModeStatus
+	}
+	p.release = nil
+}
+
+// nextReleaseVersion suggests the tag for the changes shown.
+func (p *Plugin) nextReleaseVersion() string {
+	if p.release == nil {
+		return ""
+	}
+	if p.release.Since == "" {
+		return "v0.1.0"
+	}
+	return NextVersion(p.release.Since, p.release.Bump)
+}
+
+// updateRelease handles key events in the release view.
+func (p *Plugin) updateRelease(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		p.closeRelease()
+	case "j", "down":
+		p.releaseScroll++
+	case "k", "up":
+		p.releaseScroll = max(p.releaseScroll-1, 0)
+	case "ctrl+d":
+		p.releaseScroll += max(p.height/2, 1)
+	case "ctrl+u":
+		p.releaseScroll = max(p.releaseScroll-max(p.height/2, 1), 0)
+	case "g":
+		p.releaseScroll = 0
+	case "G":
+		p.releaseScroll = len(p.releaseLines(p.width))
+	case "y":
+		return p, p.yankReleaseNotes()
+	case "t":
+		// Tag HEAD with the suggested version
+		if p.release != nil && p.release.Total > 0 {
+			next := p.nextReleaseVersion()
+			message := ""
+			if next != "" {
+				message = "Release " + next
+			}
+			return p, p.openTagCreate("HEAD", "HEAD", next, message)
+		}
+	}
+	return p, nil
+}
+
+// yankReleaseNotes copies the changes as markdown release notes.
+func (p *Plugin) yankReleaseNotes() tea.Cmd {
+	if p.release == nil || p.release.Total == 0 {
+		return nil
+	}
+	if err := clipboard.WriteAll(p.release.Markdown()); err != nil {
+		return msg.ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+	}
+	return msg.ShowToast("Yanked release notes", 2*time.Second)
+}
+
+// releaseLines renders the grouped changes, one entry per line.
+func (p *Plugin) releaseLines(width int) []string {
+	var lines []string
+	for _, g := range p.release.Groups {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		title := fmt.Sprintf("%s (%d)", g.Title, len(g.Commits))
+		if g.Title == "Breaking Changes" {
+			lines = append(lines, styles.StatusDeleted.Render(title))
+		} else {
+			lines = append(lines, styles.Title.Render(title))
+		}
+		for _, c := range g.Commits {
+			text := c.Subject
+			if c.IsConv {
+				text = c.Conventional.Description
+				if c.Conventional.Scope != "" {
+					text = styles.Muted.Render(c.Conventional.Scope+": ") + text
+				}
+			}
+			line := releaseNotesIndent + styles.Code.Render(c.ShortHash) + " " + text
+			lines = append(lines, ansi.Truncate(line, width, "…"))
+		}
+	}
+	return lines
+}
+
+// renderRelease renders the full-screen changes since the last tag.
+func (p *Plugin) renderRelease() string {
+	// Dimensions account for panel border (2) + padding (2)
+	paneHeight := p.height - 2
+	contentWidth := max(p.width-4, 20)
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionRelease, 0, 0, p.width, p.height, nil)
+
+	header := styles.Title.Render("Changes")
+	if p.release != nil {
+		since := "since " + p.release.Since
+		if p.release.Since == "" {
+			since = "(no tags yet)"
+		}
+		header += styles.Muted.Render(fmt.Sprintf(" %s · %d commit(s)", since, p.release.Total))
+		if next := p.nextReleaseVersion(); next != "" && p.release.Total > 0 {
+			header += styles.Muted.Render(" · next ") + tagBadgeStyle.Render(next) + styles.Muted.Render(" ("+p.release.Bump+")")
+		}
+	}
+	lines := []string{
+		ansi.Truncate(header, contentWidth, "…"),
+		styles.Muted.Render(strings.Repeat("━", contentWidth)),
+	}
+
+	footer := "j/k scroll  y copy as release notes  t tag HEAD  esc close"
+	bodyHeight := max(paneHeight-len(lines)-1, 1)
+	var body []string
+	switch {
+	case p.release == nil:
+		body = []string{styles.Muted.Render("Loading changes...")}
+	case p.release.Total == 0:
+		body = []string{styles.Muted.Render("No commits since " + p.release.Since + ".")}
+	default:
+		body = p.releaseLines(contentWidth)
+		p.releaseScroll = min(p.releaseScroll, max(len(body)-bodyHeight, 0))
+		body = body[p.releaseScroll:]
+	}
+	for len(body) < bodyHeight {
+		body = append(body, "")
+	}
+	lines = append(lines, body[:bodyHeight]...)
+	lines = append(lines, styles.Muted.Render(ansi.Truncate(footer, contentWidth, "…")))
+
+	return p.wrapDiffContent(strings.Join(lines, "\n"), paneHeight)
+}
@@ -0,0 +1,160 @@
+package gitstatus
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag is a git tag and the commit it points at.
+type Tag struct {
+	Name      string
+	Hash      string // Commit the tag points at (peeled for annotated tags)
+	Annotated bool
+	Subject   string    // Annotation subject, or the commit subject for lightweight tags
+	Date      time.Time // Tagger date, or the commit date for lightweight tags
+}
+
+// TagError wraps a git tag error with its output.
+type TagError struct {
+	Output string
+	Err    error
+}
+
+func (e *TagError) Error() string {
+	return strings.TrimSpace(e.Output)
+}
+
+// GetTags returns all tags, newest first.
+func GetTags(workDir string) ([]*Tag, error) {
+	format := "%(refname:short)%00%(objecttype)%00%(objectname)%00%(*objectname)%00%(contents:subject)%00%(creatordate:unix)"
+	cmd := exec.Command("git", "for-each-ref", "--sort=-creatordate", "--format="+format, "refs/tags")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*Tag
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Split(line, "\x00")
+		if len(parts) < 6 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(parts[5], 10, 64)
+		tag := &Tag{
+			Name:      parts[0],
+			Hash:      parts[2],
+			Annotated: parts[1] == "tag",
+			Subject:   parts[4],
+			Date:      time.Unix(timestamp, 0),
+		}
+		if tag.Annotated && parts[3] != "" {
+			tag.Hash = parts[3]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// ValidateTagName checks that name is a valid, unused tag name. Names
+// starting with "-" pass check-ref-format but read as options to git tag.
+func ValidateTagName(workDir, name string) error {
+	if name == "" {
+		return errors.New("tag name is required")
+	}
+	if strings.HasPrefix(name, "-") {
+		return errors.New("tag name can't start with '-': " + name)
+	}
+	cmd := exec.Command("git", "check-ref-format", "refs/tags/"+name)
+	cmd.Dir = workDir
+	if cmd.Run() != nil {
+		return errors.New("invalid tag name: " + name)
+	}
+	cmd = exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	cmd.Dir = workDir
+	if cmd.Run() == nil {
+		return errors.New("tag already exists: " + name)
+	}
+	return nil
+}
+
+// CreateTag tags target. A non-empty message creates an annotated tag,
+// otherwise the tag is lightweight.
+func CreateTag(workDir, name, target, message string) error {
+	if err := ValidateTagName(workDir, name); err != nil {
+		return err
+	}
+	args := []string{"tag"}
+	if message != "" {
+		args = append(args, "-a", "-m", message)
+	}
+	args = append(args, "--", name, target)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return &TagError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// DeleteTag deletes a local tag.
+func DeleteTag(workDir, name string) error {
+	cmd := exec.Command("git", "tag", "-d", "--", name)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return &TagError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// DeleteRemoteTag deletes a tag from the primary remote.
+func DeleteRemoteTag(workDir, name string) error {
+	remote := GetRemoteName(workDir)
+	if remote == "" {
+		return &RemoteError{Output: "No remote configured", Err: errors.New("no remote configured")}
+	}
+	cmd := exec.Command("git", "push", remote, "--delete", "refs/tags/"+name)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// PushTags pushes the named tag to the primary remote, or all tags when
+// name is empty.
+func PushTags(workDir, name string) (string, error) {
+	remote := GetRemoteName(workDir)
+	if remote == "" {
+		return "", &RemoteError{Output: "No remote configured", Err: errors.New("no remote configured")}
+	}
+	ref := "--tags"
+	if name != "" {
+		ref = "refs/tags/" + name
+	}
+	cmd := exec.Command("git", "push", remote, ref)
+	cmd.Dir = workDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), &RemoteError{Output: string(output), Err: err}
+	}
+	return string(output), nil
+}
+
+// LatestTag returns the most recent tag reachable from HEAD, or "" if there
+// is none.
+func LatestTag(workDir string) string {
+	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0", "HEAD")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,93 @@
+package gitstatus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRefTags(t *testing.T) {
+	tests := []struct {
+		refs string
+		want []string
+	}{
+		{"", nil},
+		{"HEAD -> main, origin/main", nil},
+		{"HEAD -> main, tag: v1.0.0, origin/main", []string{"v1.0.0"}},
+		{"tag: v1.0.0, tag: latest", []string{"v1.0.0", "latest"}},
+		{"tag: refs/tags/v2.0.0", []string{"v2.0.0"}},
+	}
+	for _, tt := range tests {
+		if got := parseRefTags(tt.refs); !sameLines(got, tt.want) {
+			t.Errorf("parseRefTags(%q) = %q, want %q", tt.refs, got, tt.want)
+		}
+	}
+}
+
+func TestTagLifecycle(t *testing.T) {
+	dir := initPartialRepo(t, "one\n")
+	first := gitRun(t, dir, "rev-parse", "HEAD")
+	commitFile(t, dir, "file.txt", "two\n", "second")
+
+	if err := CreateTag(dir, "v1.0.0", first, ""); err != nil {
+		t.Fatalf("lightweight tag: %v", err)
+	}
+	if err := CreateTag(dir, "v1.1.0", "HEAD", "Release v1.1.0"); err != nil {
+		t.Fatalf("annotated tag: %v", err)
+	}
+	if err := CreateTag(dir, "v1.1.0", "HEAD", ""); err == nil {
+		t.Error("expected error for an existing tag")
+	}
+	if err := CreateTag(dir, "bad..name", "HEAD", ""); err == nil {
+		t.Error("expected error for an invalid tag name")
+	}
+	if err := CreateTag(dir, "-d", "HEAD", ""); err == nil || !strings.Contains(err.Error(), "start with") {
+		t.Errorf("expected error for a tag name starting with '-', got %v", err)
+	}
+	// One made outside sidecar is still deleted, not read as an option
+	gitRun(t, dir, "update-ref", "refs/tags/-x", "HEAD")
+	if err := DeleteTag(dir, "-x"); err != nil {
+		t.Errorf("delete -x: %v", err)
+	}
+
+	tags, err := GetTags(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*Tag)
+	for _, tag := range tags {
+		byName[tag.Name] = tag
+	}
+	if tag := byName["v1.0.0"]; tag == nil || tag.Annotated || tag.Hash != first {
+		t.Errorf("v1.0.0 = %+v", tag)
+	}
+	head := gitRun(t, dir, "rev-parse", "HEAD")
+	if tag := byName["v1.1.0"]; tag == nil || !tag.Annotated || tag.Hash != head || tag.Subject != "Release v1.1.0" {
+		t.Errorf("v1.1.0 = %+v", tag)
+	}
+	if latest := LatestTag(dir); latest != "v1.1.0" {
+		t.Errorf("LatestTag = %q, want v1.1.0", latest)
+	}
+
+	// Tags show up on the loaded history
+	commits, err := GetCommitHistory(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || !sameLines(commits[0].Tags, []string{"v1.1.0"}) || !sameLines(commits[1].Tags, []string{"v1.0.0"}) {
+		t.Errorf("commit tags = %q / %q", commits[0].Tags, commits[1].Tags)
+	}
+	detail, err := GetCommitDetail(dir, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Subject != "second" || !sameLines(detail.Tags, []string{"v1.1.0"}) {
+		t.Errorf("detail subject %q, tags %q", detail.Subject, detail.Tags)
+	}
+
+	if err := DeleteTag(dir, "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if latest := LatestTag(dir); latest != "v1.0.0" {
+		t.Errorf("LatestTag after delete = %q, want v1.0.0", latest)
+	}
+}
@@ -36,8 +36,9 @@ type FileEntry struct {
 	OldPath    string // For renames
 	DiffStats  DiffStats
 	IsExpanded bool
-	IsFolder   bool         // True if this represents an untracked folder
-	Children   []*FileEntry // Files within this folder (when IsFolder is true)
+	IsFolder   bool            // True if this represents an untracked folder
+	Children   []*FileEntry    // Files within this folder (when IsFolder is true)
+	Submodule  *SubmoduleState // Set when the entry is a submodule
 }
 
 // DiffStats holds addition/deletion counts.
@@ -162,7 +163,8 @@ func (t *FileTree) parseOrdinaryEntry(line string) *FileEntry {
 	path := fields[8]
 
 	entry := &FileEntry{
-		Path: path,
+		Path:      path,
+		Submodule: parseSubmoduleState(fields[2]),
 	}
 
 	// X = index status, Y = worktree status
@@ -222,9 +224,10 @@ func (t *FileTree) parseUnmergedEntry(line string) *FileEntry {
 	}
 
 	return &FileEntry{
-		Path:     fields[10],
-		Status:   StatusUnmerged,
-		Unstaged: true,
+		Path:      fields[10],
+		Status:    StatusUnmerged,
+		Unstaged:  true,
+		Submodule: parseSubmoduleState(fields[2]),
 	}
 }
 
@@ -239,9 +242,10 @@ func (t *FileTree) addEntry(entry *FileEntry) {
 		// File has both staged and unstaged changes
 		// Add a copy to modified list
 		modEntry := &FileEntry{
-			Path:     entry.Path,
-			Status:   entry.Status,
-			Unstaged: true,
+			Path:      entry.Path,
+			Status:    entry.Status,
+			Unstaged:  true,
+			Submodule: entry.Submodule,
 		}
 		t.Modified = append(t.Modified, modEntry)
 	}
@@ -434,6 +438,16 @@ func (t *FileTree) UnstageFile(path string) error {
 	return nil
 }
 
+// StagePatch applies a partial patch to the index.
+func (t *FileTree) StagePatch(patch string) error {
+	return applyPatch(t.workDir, patch, "--cached")
+}
+
+// UnstagePatch reverts a partial patch from the index.
+func (t *FileTree) UnstagePatch(patch string) error {
+	return applyPatch(t.workDir, patch, "--cached", "--reverse")
+}
+
 // StageAll stages all modified and untracked files.
 func (t *FileTree) StageAll() error {
 	cmd := exec.Command("git", "add", "-A")
@@ -484,10 +498,24 @@ func parseCommitHash(output string) string {
 	return ""
 }
 
+// CommitOptions are per-commit flags for ExecuteCommit and ExecuteAmend.
+// Signing follows the repository's commit.gpgsign setting.
+type CommitOptions struct {
+	SignOff bool // Add a Signed-off-by trailer (git commit --signoff)
+}
+
+// args returns the git arguments for a commit command with the options applied.
+func (o CommitOptions) args(args ...string) []string {
+	if o.SignOff {
+		args = append(args, "--signoff")
+	}
+	return args
+}
+
 // ExecuteCommit executes a git commit with the given message.
 // Returns the commit hash on success or an error with git output on failure.
-func ExecuteCommit(workDir, message string) (string, error) {
-	cmd := exec.Command("git", "commit", "-m", message)
+func ExecuteCommit(workDir, message string, opts CommitOptions) (string, error) {
+	cmd := exec.Command("git", opts.args("commit", "-m", message)...)
 	cmd.Dir = workDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -497,8 +525,8 @@ func ExecuteCommit(workDir, message string) (string, error) {
 }
 
 // ExecuteAmend executes a git commit --amend with the given message.
-func ExecuteAmend(workDir, message string) (string, error) {
-	cmd := exec.Command("git", "commit", "--amend", "-m", message)
+func ExecuteAmend(workDir, message string, opts CommitOptions) (string, error) {
+	cmd := exec.Command("git", opts.args("commit", "--amend", "-m", message)...)
 	cmd.Dir = workDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -509,7 +537,12 @@ func ExecuteAmend(workDir, message string) (string, error) {
 
 // getLastCommitMessage returns the message of the most recent commit.
 func getLastCommitMessage(workDir string) string {
-	cmd := exec.Command("git", "log", "-1", "--format=%B")
+	return getCommitMessage(workDir, "HEAD")
+}
+
+// getCommitMessage returns the full message of the given commit.
+func getCommitMessage(workDir, rev string) string {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", rev)
 	cmd.Dir = workDir
 	output, err := cmd.Output()
 	if err != nil {
@@ -525,7 +558,11 @@ type CommitError struct {
 }
 
 func (e *CommitError) Error() string {
-	return strings.TrimSpace(e.Output)
+	out := strings.TrimSpace(e.Output)
+	if isSigningFailure(out) {
+		return "Signing failed (commit.gpgsign is on): " + out
+	}
+	return out
 }
 
 // DiscardModified discards unstaged changes to a modified file.
@@ -0,0 +1,234 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Kinds of operation log records that change files rather than HEAD.
+const (
+	opKindDiscard  = "discard"
+	opKindStashPop = "stash pop"
+)
+
+// JournaledFile is a file changed by a discard or stash pop, with what is
+// needed to put it back.
+type JournaledFile struct {
+	Path  string      `json:"path"`
+	Blob  string      `json:"blob,omitempty"`  // Saved content of an untracked file
+	Mode  os.FileMode `json:"mode,omitempty"`  // Permissions of an untracked file
+	After string      `json:"after,omitempty"` // Content hash after the operation, "" if the file was gone
+	Index string      `json:"index,omitempty"` // Index entry after the operation, "" if none
+}
+
+// beginFileOp snapshots paths before an operation that changes them in
+// the working tree. Tracked changes are saved with `git stash create`,
+// which leaves the working tree and stash list alone; untracked files are
+// saved as blobs.
+func beginFileOp(workDir, kind, op string, paths []string) *OpRecord {
+	head := currentHead(workDir)
+	rec := &OpRecord{
+		Time:         time.Now(),
+		Kind:         kind,
+		Op:           op,
+		Before:       head.Hash,
+		BeforeBranch: head.Branch,
+	}
+	cmd := exec.Command("git", "stash", "create")
+	cmd.Dir = workDir
+	if out, err := cmd.Output(); err == nil {
+		rec.Snapshot = strings.TrimSpace(string(out)) // Empty when nothing is modified
+	}
+	for _, path := range paths {
+		f := JournaledFile{Path: path}
+		if info, err := os.Lstat(filepath.Join(workDir, path)); err == nil && info.Mode().IsRegular() && !isTracked(workDir, path) {
+			cmd := exec.Command("git", "hash-object", "-w", "--", path)
+			cmd.Dir = workDir
+			if out, err := cmd.Output(); err == nil {
+				f.Blob = strings.TrimSpace(string(out))
+				f.Mode = info.Mode().Perm()
+			}
+		}
+		rec.Files = append(rec.Files, f)
+	}
+	return rec
+}
+
+// logFileOp records a finished file operation begun with beginFileOp.
+// Failures are ignored, as in logOperation.
+func logFileOp(workDir string, rec *OpRecord) {
+	if rec.Before == "" {
+		return // No commit to restore tracked files against
+	}
+	rec.After, rec.AfterBranch = rec.Before, rec.BeforeBranch
+	for i := range rec.Files {
+		rec.Files[i].After = worktreeHash(workDir, rec.Files[i].Path)
+		rec.Files[i].Index = indexEntry(workDir, rec.Files[i].Path)
+	}
+	records, _ := ReadOpLog(workDir)
+	records = append(records, *rec)
+	if len(records) > oplogMaxEntries {
+		records = records[len(records)-oplogMaxEntries:]
+	}
+	_ = writeOpLog(workDir, records)
+}
+
+// beginStashPop snapshots the files a stash pop will change, or returns
+// nil if the stash can't be read.
+func beginStashPop(workDir, ref string) *OpRecord {
+	cmd := exec.Command("git", "log", "-1", "--format=%H%x00%s", ref)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	hash, message, _ := strings.Cut(strings.TrimSpace(string(out)), "\x00")
+	rec := beginFileOp(workDir, opKindStashPop, "stash pop "+ref, stashPaths(workDir, hash))
+	rec.Stash, rec.StashMessage = hash, message
+	return rec
+}
+
+// stashPaths returns the files a stash changes, including its untracked
+// files.
+func stashPaths(workDir, stash string) []string {
+	cmd := exec.Command("git", "diff", "--name-only", "--no-renames", stash+"^1", stash)
+	cmd.Dir = workDir
+	out, _ := cmd.Output()
+	paths := strings.Fields(string(out))
+	cmd = exec.Command("git", "ls-tree", "-r", "--name-only", stash+"^3")
+	cmd.Dir = workDir
+	if out, err := cmd.Output(); err == nil {
+		paths = append(paths, strings.Fields(string(out))...)
+	}
+	return paths
+}
+
+// isTracked reports whether path is in the index.
+func isTracked(workDir, path string) bool {
+	cmd := exec.Command("git", "ls-files", "--error-unmatch", "--", path)
+	cmd.Dir = workDir
+	return cmd.Run() == nil
+}
+
+// worktreeHash returns the object hash of a working tree file, "" if it
+// doesn't exist.
+func worktreeHash(workDir, path string) string {
+	if _, err := os.Lstat(filepath.Join(workDir, path)); err != nil {
+		return ""
+	}
+	cmd := exec.Command("git", "hash-object", "--", path)
+	cmd.Dir = workDir
+	out, _ := cmd.Output()
+	return strings.TrimSpace(string(out))
+}
+
+// indexEntry returns the index entry of path, "" if it has none.
+func indexEntry(workDir, path string) string {
+	cmd := exec.Command("git", "ls-files", "--stage", "--", path)
+	cmd.Dir = workDir
+	out, _ := cmd.Output()
+	return strings.TrimSpace(string(out))
+}
+
+// inTree reports whether path exists in the given commit or tree.
+func inTree(workDir, rev, path string) bool {
+	cmd := exec.Command("git", "cat-file", "-e", rev+":"+path)
+	cmd.Dir = workDir
+	return cmd.Run() == nil
+}
+
+// planFileUndo plans undoing a discard or stash pop.
+func planFileUndo(rec OpRecord) *UndoPlan {
+	plan := &UndoPlan{Label: rec.Op, Target: rec.Before, Stash: rec.Stash, record: &rec}
+	for _, f := range rec.Files {
+		plan.Files = append(plan.Files, f.Path)
+	}
+	return plan
+}
+
+// executeFileUndo restores the files of a discard or stash pop and puts a
+// popped stash back in the stash list. The record is then dropped from the
+// operation log, so the next undo reaches the operation before it.
+func executeFileUndo(workDir string, plan *UndoPlan) error {
+	rec := plan.record
+	for _, f := range rec.Files {
+		if worktreeHash(workDir, f.Path) != f.After || indexEntry(workDir, f.Path) != f.Index {
+			return fmt.Errorf("%s has changed since the %s", f.Path, rec.Kind)
+		}
+	}
+
+	// The snapshot holds the working tree and, as its second parent, the
+	// index; without one both matched HEAD
+	worktree, index := rec.Before, rec.Before
+	if rec.Snapshot != "" {
+		worktree, index = rec.Snapshot, rec.Snapshot+"^2"
+	}
+	for _, f := range rec.Files {
+		if err := restoreJournaledFile(workDir, f, worktree, index); err != nil {
+			return fmt.Errorf("restore %s: %w", f.Path, err)
+		}
+	}
+
+	if rec.Stash != "" {
+		cmd := exec.Command("git", "stash", "store", "-m", rec.StashMessage, rec.Stash)
+		cmd.Dir = workDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("stash store: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	records, err := ReadOpLog(workDir)
+	if err != nil {
+		return err
+	}
+	if n := len(records); n > 0 && records[n-1].Time.Equal(rec.Time) && records[n-1].Op == rec.Op {
+		return writeOpLog(workDir, records[:n-1])
+	}
+	return nil
+}
+
+// restoreJournaledFile puts one file back in the index and working tree
+// as they were in the given revisions.
+func restoreJournaledFile(workDir string, f JournaledFile, worktree, index string) error {
+	fullPath := filepath.Join(workDir, f.Path)
+	if f.Blob != "" {
+		cmd := exec.Command("git", "cat-file", "blob", f.Blob)
+		cmd.Dir = workDir
+		data, err := cmd.Output()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(fullPath, data, f.Mode)
+	}
+
+	args := []string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--", f.Path}
+	if inTree(workDir, index, f.Path) {
+		args = []string{"restore", "--source=" + index, "--staged", "--", f.Path}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.New(strings.TrimSpace(string(output)))
+	}
+
+	if !inTree(workDir, worktree, f.Path) {
+		if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	cmd = exec.Command("git", "restore", "--source="+worktree, "--worktree", "--", f.Path)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.New(strings.TrimSpace(string(output)))
+	}
+	return nil
+}
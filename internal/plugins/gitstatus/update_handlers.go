@@ -130,6 +130,10 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case "s":
+		if p.cursorOnCommit() {
+			// Pickaxe search: commits that add or remove a string
+			return p, p.openPickaxe("", false, "")
+		}
 		if len(entries) > 0 && p.cursor < len(entries) {
 			entry := entries[p.cursor]
 			if !entry.Staged {
@@ -184,6 +188,10 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		// Open full-screen diff view for files
 		if !p.cursorOnCommit() && len(entries) > 0 && p.cursor < len(entries) {
 			entry := entries[p.cursor]
+			if entry.Submodule != nil {
+				// A gitlink diff says nothing useful; open the submodule instead
+				return p, p.enterSubmodule(entry.Path)
+			}
 			p.diffReturnMode = p.viewMode
 			p.viewMode = ViewModeDiff
 			p.diffFile = entry.Path
@@ -218,6 +226,9 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 				// Reload diff for this folder
 				return p, p.autoLoadDiff()
 			}
+			if entry.Submodule != nil {
+				return p, p.enterSubmodule(entry.Path)
+			}
 			return p, p.openFile(entry.Path)
 		}
 
@@ -265,7 +276,8 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		if p.tree.HasStagedFiles() {
 			p.viewMode = ViewModeCommit
 			p.initCommitTextarea()
-			return p, nil
+			p.prefillCommitTemplate()
+			return p, p.checkCommitSigning()
 		}
 
 	case "A":
@@ -276,7 +288,7 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 			p.initCommitTextarea()
 			msg := getLastCommitMessage(p.repoRoot)
 			p.commitMessage.SetValue(msg)
-			return p, nil
+			return p, p.checkCommitSigning()
 		}
 
 	case "P":
@@ -303,7 +315,7 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	case "o":
 		// Open commit in GitHub (when on commit in sidebar)
 		if p.cursorOnCommit() {
-			return p, p.openCommitInGitHub()
+			return p, p.openCommitOnForge()
 		}
 
 	case "D":
@@ -334,6 +346,20 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		// Apply latest stash (non-destructive, stash entry preserved)
 		return p, p.doStashApply()
 
+	case "alt+z":
+		// Browse stashes, their files and diffs
+		return p, p.openStashes()
+
+	case "=":
+		// Compare two branches or refs
+		p.openComparePrompt("", "")
+		return p, nil
+
+	case "E":
+		// Export commits or the staged changes as patch files
+		p.openPatchExport()
+		return p, nil
+
 	case "b":
 		// Open branch picker
 		p.branchReturnMode = p.viewMode
@@ -421,6 +447,83 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 			p.clearSearchState()
 			return p, nil
 		}
+		// Otherwise return from a submodule to its superproject
+		if len(p.submoduleStack) > 0 {
+			return p, p.leaveSubmodule()
+		}
+
+	case "M":
+		// Submodules: drift, update and sync
+		return p, p.openSubmodules()
+
+	case "i":
+		// Interactive rebase onto the selected commit
+		if p.cursorOnCommit() {
+			return p, p.openRebaseEditor()
+		}
+
+	case "C":
+		// Cherry-pick commits from another branch or worktree
+		if p.cursorOnCommit() {
+			return p, p.openCherryPick()
+		}
+
+	case "R":
+		// Revert the selected commit
+		if p.cursorOnCommit() {
+			return p, p.openRevert()
+		}
+
+	case "X":
+		// Reset the branch to the selected commit
+		if p.cursorOnCommit() {
+			return p, p.openReset()
+		}
+
+	case "H":
+		// Browse the reflog and undo recent operations
+		return p, p.openReflog()
+
+	case "m":
+		// Resolve merge conflicts, starting with the selected file if it is unmerged
+		focus := ""
+		if !p.cursorOnCommit() && p.cursor < len(entries) && entries[p.cursor].Status == StatusUnmerged {
+			focus = entries[p.cursor].Path
+		}
+		return p, p.openConflictResolver(focus)
+
+	case "e":
+		// History of the selected file, following renames
+		if !p.cursorOnCommit() && p.cursor < len(entries) && !entries[p.cursor].IsFolder {
+			return p, p.openFileHistory(entries[p.cursor].Path)
+		}
+
+	case "t":
+		// Tag the selected commit
+		if c := p.selectedCommit(); c != nil {
+			return p, p.openTagCreate(c.Hash, c.ShortHash, "", "")
+		}
+
+	case "T":
+		// Browse, push and delete tags
+		return p, p.openTags()
+
+	case "W":
+		// Changes since the last tag, grouped for release notes
+		return p, p.openRelease("")
+
+	case "B":
+		// Mark the selected commit for bisect, or show the bisect in progress
+		if p.cursorOnCommit() {
+			if commit := p.selectedCommit(); commit != nil {
+				p.openBisectMenu(commit)
+			}
+			return p, nil
+		}
+		if p.bisectActive() {
+			return p, p.openBisect()
+		}
+		return p, appmsg.ShowToast("No bisect in progress; press B on a commit to start", 2*time.Second)
 
 	case "v":
 		// Toggle commit graph display (only when on commits)
@@ -447,6 +550,11 @@ func (p *Plugin) updateStatusDiffPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 
 	switch msg.String() {
 	case "esc":
+		// Cancel an active line range first
+		if p.diffSelection.Selecting {
+			p.diffSelection.Selecting = false
+			return p, nil
+		}
 		// Restore sidebar if hidden, then return to it
 		if !p.sidebarVisible {
 			p.sidebarVisible = true
@@ -468,20 +576,21 @@ func (p *Plugin) updateStatusDiffPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		p.clampDiffPaneHorizScroll()
 
 	case "j", "down":
-		p.diffPaneScroll++
+		// Move the line cursor; scroll follows it
+		p.moveDiffCursor(1)
 
 	case "k", "up":
-		if p.diffPaneScroll > 0 {
-			p.diffPaneScroll--
-		}
+		p.moveDiffCursor(-1)
 
 	case "g":
+		p.diffSelection.Cursor = 0
 		p.diffPaneScroll = 0
 		p.diffPaneHorizScroll = 0
 
 	case "G":
 		if p.diffPaneParsedDiff != nil {
 			lines := countParsedDiffLines(p.diffPaneParsedDiff)
+			p.moveDiffCursor(lines)
 			maxScroll := lines - (p.height - 6)
 			if maxScroll > 0 {
 				p.diffPaneScroll = maxScroll
@@ -489,24 +598,34 @@ func (p *Plugin) updateStatusDiffPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case "ctrl+d":
-		p.diffPaneScroll += 10
-		// Clamp to max
-		if p.diffPaneParsedDiff != nil {
-			lines := countParsedDiffLines(p.diffPaneParsedDiff)
-			maxScroll := lines - (p.height - 6)
-			if maxScroll < 0 {
-				maxScroll = 0
-			}
-			if p.diffPaneScroll > maxScroll {
-				p.diffPaneScroll = maxScroll
-			}
-		}
+		p.moveDiffCursor(10)
 
 	case "ctrl+u":
-		p.diffPaneScroll -= 10
-		if p.diffPaneScroll < 0 {
-			p.diffPaneScroll = 0
-		}
+		p.moveDiffCursor(-10)
+
+	case "]":
+		// Jump to next hunk
+		p.jumpDiffHunk(1)
+
+	case "[":
+		// Jump to previous hunk
+		p.jumpDiffHunk(-1)
+
+	case "V":
+		// Start/cancel a line range anchored at the cursor
+		p.toggleDiffRange()
+
+	case "s":
+		// Stage the selected lines, or the hunk under the cursor
+		return p, p.stageSelection(partialStage)
+
+	case "u":
+		// Unstage the selected lines, or the hunk under the cursor
+		return p, p.stageSelection(partialUnstage)
+
+	case "D":
+		// Discard the selected lines, or the hunk under the cursor (confirm modal)
+		return p, p.stageSelection(partialDiscard)
 
 	case "0":
 		// Reset horizontal scroll
@@ -545,6 +664,9 @@ func (p *Plugin) updateStatusDiffPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		entries := p.tree.AllEntries()
 		if len(entries) > 0 && p.cursor < len(entries) {
 			entry := entries[p.cursor]
+			if entry.Submodule != nil {
+				return p, p.enterSubmodule(entry.Path)
+			}
 			p.diffReturnMode = p.viewMode
 			p.viewMode = ViewModeDiff
 			p.diffFile = entry.Path
@@ -634,7 +756,7 @@ func (p *Plugin) updateCommitPreviewPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd
 
 	case "o":
 		// Open commit in GitHub
-		return p, p.openCommitInGitHub()
+		return p, p.openCommitOnForge()
 
 	case "b":
 		// Open selected file in file browser
@@ -642,6 +764,12 @@ func (p *Plugin) updateCommitPreviewPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd
 			file := c.Files[p.previewCommitCursor]
 			return p, p.openInFileBrowser(file.Path)
 		}
+
+	case "e":
+		// History of the selected file
+		if p.previewCommitCursor < len(c.Files) {
+			return p, p.openFileHistory(c.Files[p.previewCommitCursor].Path)
+		}
 	}
 
 	return p, nil
@@ -767,6 +895,22 @@ func (p *Plugin) updateCommit(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	case "ctrl+s", "ctrl+enter":
 		return p, p.tryCommit()
 
+	case "ctrl+g":
+		// Draft a message from the staged diff
+		return p, p.generateCommitMessage()
+
+	case "ctrl+t":
+		// Add a Co-authored-by or task reference trailer
+		return p, p.openCommitTrailers()
+
+	case "ctrl+l":
+		p.toggleCommitLint()
+		return p, nil
+
+	case "ctrl+o":
+		p.commitSignOff = !p.commitSignOff
+		return p, nil
+
 	case "ctrl+a":
 		// Toggle amend mode (only if there are commits to amend and staged files)
 		if len(p.recentCommits) > 0 && p.tree.HasStagedFiles() {
@@ -802,7 +946,9 @@ func (p *Plugin) updateCommit(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	case "cancel":
 		p.viewMode = ViewModeStatus
 		p.commitAmend = false
+		p.commitSignOff = false
 		p.commitError = ""
+		p.resetCommitAssist()
 		p.commitModal = nil
 		p.commitModalWidthCache = 0
 		return p, nil
@@ -813,11 +959,12 @@ func (p *Plugin) updateCommit(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 
 // tryCommit attempts to execute the commit (or amend) if message is valid.
 func (p *Plugin) tryCommit() tea.Cmd {
-	message := strings.TrimSpace(p.commitMessage.Value())
-	if message == "" {
-		p.commitError = "Commit message cannot be empty"
+	message := p.commitMessageText()
+	if problem := p.checkCommitMessage(message); problem != "" {
+		p.commitError = problem
 		return nil
 	}
+	p.commitError = ""
 	p.commitInProgress = true
 	if p.commitAmend {
 		return p.doAmend(message)
@@ -952,9 +1099,22 @@ func (p *Plugin) updatePullConflict(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	}
 
 	switch msg.String() {
+	case "r":
+		// Open the conflict resolver
+		return p.resolvePullConflict()
 	case "a":
 		// Abort merge/rebase
 		return p.abortPullConflict()
+	case "c":
+		// Continue rebase once conflicts are resolved
+		if p.pullConflictType == "rebase" {
+			return p.continueRebaseConflict()
+		}
+	case "s":
+		// Skip the commit the rebase stopped on
+		if p.pullConflictType == "rebase" {
+			return p.skipRebaseConflict()
+		}
 	case "esc", "q":
 		// Dismiss modal (conflicts remain, user resolves manually)
 		return p.dismissPullConflict()
@@ -962,20 +1122,45 @@ func (p *Plugin) updatePullConflict(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 
 	action, cmd := p.pullConflictModal.HandleKey(msg)
 	switch action {
+	case pullConflictResolveID:
+		return p.resolvePullConflict()
 	case pullConflictAbortID:
 		return p.abortPullConflict()
+	case pullConflictContinueID:
+		return p.continueRebaseConflict()
+	case pullConflictSkipID:
+		return p.skipRebaseConflict()
 	case "cancel", pullConflictDismissID:
 		return p.dismissPullConflict()
 	}
 	return p, cmd
 }
 
+func (p *Plugin) continueRebaseConflict() (plugin.Plugin, tea.Cmd) {
+	p.viewMode = ViewModeStatus
+	p.clearPullConflictModal()
+	return p, p.doContinueRebase()
+}
+
+func (p *Plugin) skipRebaseConflict() (plugin.Plugin, tea.Cmd) {
+	p.viewMode = ViewModeStatus
+	p.clearPullConflictModal()
+	return p, p.doSkipRebase()
+}
+
 func (p *Plugin) abortPullConflict() (plugin.Plugin, tea.Cmd) {
 	p.viewMode = ViewModeStatus
 	p.clearPullConflictModal()
 	return p, p.doAbortPull()
 }
 
+// resolvePullConflict closes the pull conflict modal and opens the resolver.
+func (p *Plugin) resolvePullConflict() (plugin.Plugin, tea.Cmd) {
+	p.pullConflictFiles = nil
+	p.clearPullConflictModal()
+	return p, p.openConflictResolver("")
+}
+
 func (p *Plugin) dismissPullConflict() (plugin.Plugin, tea.Cmd) {
 	p.viewMode = ViewModeStatus
 	p.pullConflictFiles = nil
@@ -1043,10 +1228,15 @@ func (p *Plugin) updateConfirmDiscard(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 func (p *Plugin) confirmDiscard() (plugin.Plugin, tea.Cmd) {
 	var cmd tea.Cmd
 	if p.discardFile != nil {
-		cmd = p.doDiscard(p.discardFile)
+		if p.discardPatch != "" {
+			cmd = p.doApplyPartial(partialDiscard, p.discardFile, p.discardPatch)
+		} else {
+			cmd = p.doDiscard(p.discardFile)
+		}
 	}
 	p.viewMode = p.discardReturnMode
 	p.discardFile = nil
+	p.discardPatch = ""
 	p.discardModal = nil
 	return p, cmd
 }
@@ -1055,6 +1245,7 @@ func (p *Plugin) confirmDiscard() (plugin.Plugin, tea.Cmd) {
 func (p *Plugin) cancelDiscard() (plugin.Plugin, tea.Cmd) {
 	p.viewMode = p.discardReturnMode
 	p.discardFile = nil
+	p.discardPatch = ""
 	p.discardModal = nil
 	return p, nil
 }
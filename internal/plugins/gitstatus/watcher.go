@@ -2,6 +2,7 @@ package gitstatus
 
 import (
 	"log/slog"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -33,7 +34,7 @@ func NewWatcher(workDir string) (*Watcher, error) {
 	}
 
 	// Watch .git/index for staging changes
-	gitDir := filepath.Join(workDir, ".git")
+	gitDir := resolveGitDir(workDir)
 	indexPath := filepath.Join(gitDir, "index")
 	headPath := filepath.Join(gitDir, "HEAD")
 	refsDir := filepath.Join(gitDir, "refs")
@@ -59,6 +60,20 @@ func NewWatcher(workDir string) (*Watcher, error) {
 	return w, nil
 }
 
+// resolveGitDir returns the git directory of workDir. Submodules and linked
+// worktrees have a .git file pointing elsewhere, so ask git, falling back to
+// workDir/.git.
+func resolveGitDir(workDir string) string {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = workDir
+	if output, err := cmd.Output(); err == nil {
+		if dir := strings.TrimSpace(string(output)); dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(workDir, ".git")
+}
+
 // Events returns the channel that receives change notifications.
 func (w *Watcher) Events() <-chan struct{} {
 	return w.events
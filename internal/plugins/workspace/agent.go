@@ -1288,21 +1288,28 @@ func (p *Plugin) ApproveAll() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-// SendText sends arbitrary text to an agent.
+// SendText sends arbitrary text to an agent. Multi-line text is sent as a
+// bracketed paste so its newlines don't submit it line by line.
 func (p *Plugin) SendText(wt *Worktree, text string) tea.Cmd {
 	return func() tea.Msg {
 		if wt.Agent == nil {
 			return SendTextResultMsg{Err: fmt.Errorf("no agent running")}
 		}
 
-		// Use -l to send literal text (no key name lookup)
-		cmd := exec.Command("tmux", "send-keys", "-l", "-t", wt.Agent.TmuxSession, text)
-		if err := cmd.Run(); err != nil {
-			return SendTextResultMsg{Err: err}
+		if strings.Contains(text, "\n") {
+			if err := sendBracketedPasteToTmux(wt.Agent.TmuxSession, text); err != nil {
+				return SendTextResultMsg{Err: err}
+			}
+		} else {
+			// Use -l to send literal text (no key name lookup)
+			cmd := exec.Command("tmux", "send-keys", "-l", "-t", wt.Agent.TmuxSession, text)
+			if err := cmd.Run(); err != nil {
+				return SendTextResultMsg{Err: err}
+			}
 		}
 
 		// Send Enter separately
-		cmd = exec.Command("tmux", "send-keys", "-t", wt.Agent.TmuxSession, "Enter")
+		cmd := exec.Command("tmux", "send-keys", "-t", wt.Agent.TmuxSession, "Enter")
 		err := cmd.Run()
 
 		return SendTextResultMsg{
@@ -75,6 +75,14 @@ func (p *Plugin) Commands() []plugin.Command {
 			{ID: "cancel", Name: "Cancel", Description: "Cancel PR fetch", Context: "workspace-fetch-pr", Priority: 1},
 			{ID: "fetch", Name: "Fetch", Description: "Fetch selected PR", Context: "workspace-fetch-pr", Priority: 2},
 		}
+	case ViewModePRReview:
+		return []plugin.Command{
+			{ID: "cancel", Name: "Close", Description: "Close PR review", Context: "workspace-pr-review", Priority: 1},
+			{ID: "send-review", Name: "Send", Description: "Send selected comments to the agent", Context: "workspace-pr-review", Priority: 2},
+			{ID: "toggle-comment", Name: "Select", Description: "Select comment thread", Context: "workspace-pr-review", Priority: 3},
+			{ID: "open-pr", Name: "Open", Description: "Open PR in browser", Context: "workspace-pr-review", Priority: 4},
+			{ID: "refresh", Name: "Refresh", Description: "Reload review and checks", Context: "workspace-pr-review", Priority: 5},
+		}
 	case ViewModeFilePicker:
 		return []plugin.Command{
 			{ID: "cancel", Name: "Cancel", Description: "Close file picker", Context: "workspace-file-picker", Priority: 1},
@@ -217,6 +225,11 @@ func (p *Plugin) Commands() []plugin.Command {
 				plugin.Command{ID: "merge-workflow", Name: "Merge", Description: "Start merge workflow", Context: "workspace-list", Priority: 7},
 				plugin.Command{ID: "open-in-git", Name: "Git", Description: "Open in Git tab", Context: "workspace-list", Priority: 16},
 			)
+			if !wt.IsMain {
+				cmds = append(cmds,
+					plugin.Command{ID: "pr-review", Name: "Review", Description: "PR review comments and checks", Context: "workspace-list", Priority: 17},
+				)
+			}
 			// Task linking
 			if wt.TaskID != "" {
 				cmds = append(cmds,
@@ -262,6 +275,8 @@ func (p *Plugin) FocusContext() string {
 		return "workspace-type-selector"
 	case ViewModeFetchPR:
 		return "workspace-fetch-pr"
+	case ViewModePRReview:
+		return "workspace-pr-review"
 	case ViewModeFilePicker:
 		return "workspace-file-picker"
 	default:
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/plugins/gitstatus"
 )
 
 // loadSelectedDiff returns a command to load diff for the selected worktree.
@@ -205,29 +206,7 @@ func tryGitLog(workdir, baseRef string) ([]byte, error) {
 // detectDefaultBranch detects the default branch for a repository.
 // Checks remote HEAD first, then falls back to common names.
 func detectDefaultBranch(workdir string) string {
-	// Try to get the remote HEAD (most reliable)
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
-	cmd.Dir = workdir
-	output, err := cmd.Output()
-	if err == nil {
-		// Output is like "refs/remotes/origin/main"
-		ref := strings.TrimSpace(string(output))
-		if branch, found := strings.CutPrefix(ref, "refs/remotes/origin/"); found {
-			return branch
-		}
-	}
-
-	// Fallback: check which common branch exists
-	for _, branch := range []string{"main", "master"} {
-		cmd := exec.Command("git", "rev-parse", "--verify", branch)
-		cmd.Dir = workdir
-		if err := cmd.Run(); err == nil {
-			return branch
-		}
-	}
-
-	// Last resort default
-	return "main"
+	return gitstatus.DefaultBranch(workdir)
 }
 
 // resolveBaseBranch returns the worktree's BaseBranch if set,
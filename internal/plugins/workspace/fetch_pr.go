@@ -1,8 +1,6 @@
 package workspace
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path/filepath"
@@ -13,32 +11,32 @@ import (
 	"github.com/marcus/sidecar/internal/app"
 )
 
-// fetchPRList runs gh pr list and returns open PRs.
+// fetchPRList lists open PRs (MRs on GitLab) with the forge's CLI.
 func (p *Plugin) fetchPRList() tea.Cmd {
 	workDir := p.ctx.WorkDir
+	hosts := p.forgeHosts()
 	return func() tea.Msg {
-		cmd := exec.Command("gh", "pr", "list",
-			"--json", "number,title,headRefName,url,isDraft,createdAt,author",
-			"--limit", "30",
-		)
-		cmd.Dir = workDir
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-		output, err := cmd.Output()
+		prs, err := detectForge(workDir, hosts).ListPullRequests(workDir, 30)
 		if err != nil {
-			errMsg := strings.TrimSpace(stderr.String())
-			if errMsg == "" {
-				errMsg = err.Error()
-			}
-			return FetchPRListMsg{Err: fmt.Errorf("gh pr list: %s", errMsg)}
+			return FetchPRListMsg{Err: err}
 		}
 
-		var prs []PRListItem
-		if err := json.Unmarshal(output, &prs); err != nil {
-			return FetchPRListMsg{Err: fmt.Errorf("parse pr list: %w", err)}
+		items := make([]PRListItem, 0, len(prs))
+		for _, pr := range prs {
+			item := PRListItem{
+				Number:  pr.Number,
+				Title:   pr.Title,
+				Branch:  pr.Branch,
+				Author:  prAuthor{Login: pr.Author},
+				URL:     pr.URL,
+				IsDraft: pr.Draft,
+			}
+			if !pr.CreatedAt.IsZero() {
+				item.CreatedAt = pr.CreatedAt.Format(time.RFC3339)
+			}
+			items = append(items, item)
 		}
-
-		return FetchPRListMsg{PRs: prs}
+		return FetchPRListMsg{PRs: items}
 	}
 }
 
@@ -0,0 +1,20 @@
+package workspace
+
+import "github.com/marcus/sidecar/internal/forge"
+
+// forgeHosts returns the configured self-hosted forge hosts.
+func (p *Plugin) forgeHosts() map[string]string {
+	if p.ctx == nil || p.ctx.Config == nil {
+		return nil
+	}
+	return p.ctx.Config.Forges.Hosts
+}
+
+// detectForge returns the forge hosting dir's origin. Unrecognized hosts
+// fall back to GitHub so gh can still resolve them (e.g. via GH_HOST).
+func detectForge(dir string, hosts map[string]string) *forge.Remote {
+	if remote := forge.Detect(dir, hosts); remote != nil {
+		return remote
+	}
+	return &forge.Remote{Kind: forge.GitHub}
+}
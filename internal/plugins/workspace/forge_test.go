@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/forge"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+// initRemoteRepo creates a repo whose origin is remoteURL.
+func initRemoteRepo(t *testing.T, remoteURL string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", remoteURL},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	return dir
+}
+
+func TestDetectForge(t *testing.T) {
+	hosts := map[string]string{"git.example.com": "gitlab"}
+
+	dir := initRemoteRepo(t, "git@git.example.com:team/app.git")
+	if r := detectForge(dir, hosts); r.Kind != forge.GitLab {
+		t.Errorf("self-hosted kind = %s", r.Kind)
+	}
+	// Unrecognized hosts keep using gh
+	dir = initRemoteRepo(t, "git@git.example.net:team/app.git")
+	if r := detectForge(dir, hosts); r.Kind != forge.GitHub {
+		t.Errorf("fallback kind = %s", r.Kind)
+	}
+}
+
+func TestFetchPRListSelfHostedGitLab(t *testing.T) {
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+echo '[{"iid":12,"title":"Add API","source_branch":"api","web_url":"https://git.example.com/team/app/-/merge_requests/12","created_at":"2024-05-02T08:30:00.000Z","author":{"username":"bo"}}]'
+`
+	if err := os.WriteFile(filepath.Join(binDir, "glab"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.Default()
+	cfg.Forges.Hosts["git.example.com"] = "gitlab"
+	dir := initRemoteRepo(t, "https://git.example.com/team/app.git")
+	p := &Plugin{ctx: &plugin.Context{WorkDir: dir, Config: cfg}}
+
+	msg, ok := p.fetchPRList()().(FetchPRListMsg)
+	if !ok || msg.Err != nil {
+		t.Fatalf("got %+v", msg)
+	}
+	if len(msg.PRs) != 1 {
+		t.Fatalf("prs = %+v", msg.PRs)
+	}
+	pr := msg.PRs[0]
+	if pr.Number != 12 || pr.Branch != "api" || pr.Author.Login != "bo" || pr.CreatedAt != "2024-05-02T08:30:00Z" {
+		t.Errorf("pr = %+v", pr)
+	}
+}
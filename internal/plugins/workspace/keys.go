@@ -40,6 +40,8 @@ func (p *Plugin) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 		return p.handleRenameShellKeys(msg)
 	case ViewModeFetchPR:
 		return p.handleFetchPRKeys(msg)
+	case ViewModePRReview:
+		return p.handlePRReviewKeys(msg)
 	case ViewModeFilePicker:
 		return p.handleFilePickerKeys(msg)
 	case ViewModeInteractive:
@@ -900,6 +902,11 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 		if wt != nil {
 			return p.startMergeWorkflow(wt)
 		}
+	case "P":
+		// Review comments and checks of the selected worktree's PR
+		if wt := p.selectedWorktree(); wt != nil && !wt.IsMain {
+			return p.openPRReview(wt)
+		}
 	case "O":
 		// Open selected worktree in git tab - switch to worktree and focus git plugin
 		wt := p.selectedWorktree()
@@ -3,7 +3,6 @@ package workspace
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -258,7 +257,7 @@ func (p *Plugin) stageAllAndCommit(wt *Worktree, message string) tea.Cmd {
 		}
 
 		// Execute commit
-		hash, err := gitstatus.ExecuteCommit(wt.Path, message)
+		hash, err := gitstatus.ExecuteCommit(wt.Path, message, gitstatus.CommitOptions{})
 		if err != nil {
 			return MergeCommitDoneMsg{
 				WorkspaceName: wt.Name,
@@ -353,80 +352,26 @@ func (p *Plugin) pushForMerge(wt *Worktree) tea.Cmd {
 	}
 }
 
-// parseExistingPRURL extracts the PR URL from a "PR already exists" error message.
-// Returns the URL and true if found, empty string and false otherwise.
-func parseExistingPRURL(output string) (string, bool) {
-	// Error format: "a pull request for branch X into branch Y already exists: <URL>: exit status 1"
-	const marker = "already exists:"
-	idx := strings.Index(output, marker)
-	if idx == -1 {
-		return "", false
-	}
-
-	// Extract URL after marker
-	rest := strings.TrimSpace(output[idx+len(marker):])
-
-	// Find the URL - it starts with http and ends before ": exit" or end of string
-	if !strings.HasPrefix(rest, "http") {
-		return "", false
-	}
-
-	// Find where URL ends - look for ": exit" pattern which follows the URL
-	endIdx := strings.Index(rest, ": exit")
-	if endIdx == -1 {
-		// No ": exit" suffix, URL goes to end (trim whitespace)
-		endIdx = strings.IndexAny(rest, " \t\n")
-		if endIdx == -1 {
-			endIdx = len(rest)
-		}
-	}
-
-	url := strings.TrimSpace(rest[:endIdx])
-	if url == "" {
-		return "", false
-	}
-	return url, true
-}
-
-// createPR creates a pull request using gh CLI.
+// createPR creates a pull request (merge request on GitLab) with the
+// forge's CLI.
 func (p *Plugin) createPR(wt *Worktree, title, body, targetBranch string) tea.Cmd {
+	hosts := p.forgeHosts()
 	return func() tea.Msg {
-		// Build gh pr create command
-		args := []string{"pr", "create",
-			"--title", title,
-			"--body", body,
-			"--base", targetBranch,
-		}
-
-		cmd := exec.Command("gh", args...)
-		cmd.Dir = wt.Path
-		output, err := cmd.CombinedOutput()
-
+		remote := detectForge(wt.Path, hosts)
+		prURL, existing, err := remote.CreatePullRequest(wt.Path, title, body, targetBranch)
 		if err != nil {
-			// Check if PR already exists
-			outputStr := string(output)
-			if existingURL, found := parseExistingPRURL(outputStr); found {
-				return MergeStepCompleteMsg{
-					WorkspaceName:    wt.Name,
-					Step:            MergeStepCreatePR,
-					Data:            existingURL,
-					ExistingPRFound: true,
-				}
-			}
 			return MergeStepCompleteMsg{
 				WorkspaceName: wt.Name,
-				Step:         MergeStepCreatePR,
-				Err:          fmt.Errorf("gh pr create: %s: %w", strings.TrimSpace(outputStr), err),
+				Step:          MergeStepCreatePR,
+				Err:           err,
 			}
 		}
 
-		// Output should contain the PR URL
-		prURL := strings.TrimSpace(string(output))
-
 		return MergeStepCompleteMsg{
-			WorkspaceName: wt.Name,
-			Step:         MergeStepCreatePR,
-			Data:         prURL,
+			WorkspaceName:   wt.Name,
+			Step:            MergeStepCreatePR,
+			Data:            prURL,
+			ExistingPRFound: existing,
 		}
 	}
 }
@@ -715,36 +660,15 @@ func (p *Plugin) schedulePRGenerationTick(wtName string) tea.Cmd {
 	})
 }
 
-// checkPRMerged checks if a PR has been merged using gh CLI.
+// checkPRMerged checks if the worktree branch's PR has been merged on its forge.
 func (p *Plugin) checkPRMerged(wt *Worktree) tea.Cmd {
+	hosts := p.forgeHosts()
 	return func() tea.Msg {
-		// Use gh pr view to check status
-		cmd := exec.Command("gh", "pr", "view", "--json", "state,mergedAt")
-		cmd.Dir = wt.Path
-		output, err := cmd.Output()
-
-		if err != nil {
-			return CheckPRMergedMsg{
-				WorkspaceName: wt.Name,
-				Merged:       false,
-				Err:          err,
-			}
-		}
-
-		// Parse JSON response
-		var prStatus struct {
-			State    string `json:"state"`
-			MergedAt string `json:"mergedAt"`
-		}
-
-		merged := false
-		if err := json.Unmarshal(output, &prStatus); err == nil {
-			merged = prStatus.MergedAt != "" || prStatus.State == "MERGED"
-		}
-
+		merged, err := detectForge(wt.Path, hosts).PullRequestMerged(wt.Path)
 		return CheckPRMergedMsg{
 			WorkspaceName: wt.Name,
-			Merged:       merged,
+			Merged:        merged,
+			Err:           err,
 		}
 	}
 }
@@ -961,6 +885,7 @@ type RebaseResolutionMsg struct {
 	Branch       string
 	Success      bool
 	Err          error
+	Conflicts    []string // Files left unmerged when the pull stopped on conflicts
 }
 
 // MergeResolutionMsg signals result of merge resolution attempt.
@@ -969,6 +894,7 @@ type MergeResolutionMsg struct {
 	Branch       string
 	Success      bool
 	Err          error
+	Conflicts    []string // Files left unmerged when the pull stopped on conflicts
 }
 
 // executeRebaseResolution performs git pull --rebase to resolve diverged branches.
@@ -992,6 +918,7 @@ func (p *Plugin) executeRebaseResolution() tea.Cmd {
 				Branch:       branch,
 				Success:      false,
 				Err:          fmt.Errorf("rebase failed: %s", strings.TrimSpace(string(output))),
+				Conflicts:    gitstatus.GetConflictedFiles(workDir),
 			}
 		}
 
@@ -1024,6 +951,7 @@ func (p *Plugin) executeMergeResolution() tea.Cmd {
 				Branch:       branch,
 				Success:      false,
 				Err:          fmt.Errorf("merge failed: %s", strings.TrimSpace(string(output))),
+				Conflicts:    gitstatus.GetConflictedFiles(workDir),
 			}
 		}
 
@@ -1035,6 +963,21 @@ func (p *Plugin) executeMergeResolution() tea.Cmd {
 	}
 }
 
+// resolveConflictsInGitTab hands a pull that stopped on conflicts to the
+// git plugin's conflict resolver.
+func (p *Plugin) resolveConflictsInGitTab(conflicts []string) tea.Cmd {
+	summary := fmt.Sprintf("Conflicts in %d file(s) - resolving in git tab", len(conflicts))
+	p.mergeState.CleanupResults.PullErrorSummary = summary
+	p.mergeState.CleanupResults.BranchDiverged = false
+	workDir := p.ctx.WorkDir
+	return tea.Batch(
+		app.FocusPlugin("git-status"),
+		func() tea.Msg {
+			return gitstatus.OpenConflictResolverMsg{WorkDir: workDir}
+		},
+	)
+}
+
 // deleteRemoteBranch deletes the remote branch from origin.
 func (p *Plugin) deleteRemoteBranch(wt *Worktree) tea.Cmd {
 	return func() tea.Msg {
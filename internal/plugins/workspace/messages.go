@@ -257,7 +257,7 @@ type PRListItem struct {
 	IsDraft   bool      `json:"isDraft"`
 }
 
-// prAuthor represents the author field from gh pr list --json.
+// prAuthor is the author of a listed PR.
 type prAuthor struct {
 	Login string `json:"login"`
 }
@@ -72,6 +72,10 @@ func (p *Plugin) handleMouse(msg tea.MouseMsg) tea.Cmd {
 		return p.handleFetchPRModalMouse(msg)
 	}
 
+	if p.viewMode == ViewModePRReview {
+		return p.handlePRReviewModalMouse(msg)
+	}
+
 	if p.viewMode == ViewModeMerge {
 		return p.handleMergeModalMouse(msg)
 	}
@@ -331,6 +335,18 @@ func (p *Plugin) handleFetchPRModalMouse(msg tea.MouseMsg) tea.Cmd {
 	return nil
 }
 
+func (p *Plugin) handlePRReviewModalMouse(msg tea.MouseMsg) tea.Cmd {
+	p.ensurePRReviewModal()
+	if p.prReviewModal == nil {
+		return nil
+	}
+
+	if action := p.prReviewModal.HandleMouse(msg, p.mouseHandler); action == "cancel" {
+		p.closePRReview()
+	}
+	return nil
+}
+
 func (p *Plugin) handleMergeModalMouse(msg tea.MouseMsg) tea.Cmd {
 	p.ensureMergeModal()
 	if p.mergeModal == nil {
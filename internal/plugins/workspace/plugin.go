@@ -9,6 +9,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/forge"
 	"github.com/marcus/sidecar/internal/markdown"
 	"github.com/marcus/sidecar/internal/modal"
 	"github.com/marcus/sidecar/internal/mouse"
@@ -306,15 +307,25 @@ type Plugin struct {
 	pendingResumeWorktree string // Worktree name to enter interactive mode after agent starts
 
 	// Fetch PR modal state
-	fetchPRItems        []PRListItem // PRs from gh pr list
+	fetchPRItems        []PRListItem // Open PRs from the forge CLI
 	fetchPRFilter       string       // Filter text
 	fetchPRCursor       int          // Selected index in filtered list
 	fetchPRScrollOffset int          // Scroll offset for PR list
-	fetchPRLoading      bool         // True while gh pr list is running
+	fetchPRLoading      bool         // True while the PR list is loading
 	fetchPRError        string       // Error message from gh CLI
 	fetchPRModal        *modal.Modal // Modal instance
 	fetchPRModalWidth   int          // Cached width for rebuild detection
 
+	// PR review modal state
+	prReviewWorktree   *Worktree     // Worktree whose PR is shown
+	prReview           *forge.Review // Loaded review, nil until loaded
+	prReviewLoading    bool          // True while the review is loading
+	prReviewError      string        // Error message from the forge CLI
+	prReviewCursor     int           // Thread under the cursor
+	prReviewSelected   map[int]bool  // Thread indexes selected for sending
+	prReviewModal      *modal.Modal  // Modal instance
+	prReviewModalWidth int           // Cached width for rebuild detection
+
 	// Shell manifest for persistence and cross-instance sync (td-f88fdd)
 	shellManifest *ShellManifest
 	shellWatcher  *ShellWatcher
@@ -0,0 +1,215 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/forge"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+)
+
+// PRReviewLoadedMsg delivers the review state of a worktree's PR.
+type PRReviewLoadedMsg struct {
+	WorkspaceName string
+	Review        *forge.Review
+	Err           error
+}
+
+// openPRReview opens the PR review modal for a worktree and loads its
+// review comments, checks and decision.
+func (p *Plugin) openPRReview(wt *Worktree) tea.Cmd {
+	if wt == nil {
+		return nil
+	}
+	p.prReviewWorktree = wt
+	p.prReview = nil
+	p.prReviewError = ""
+	p.prReviewCursor = 0
+	p.prReviewSelected = make(map[int]bool)
+	p.viewMode = ViewModePRReview
+	return p.loadPRReview()
+}
+
+// loadPRReview fetches the review of the PR for the modal's worktree.
+func (p *Plugin) loadPRReview() tea.Cmd {
+	wt := p.prReviewWorktree
+	if wt == nil {
+		return nil
+	}
+	p.prReviewLoading = true
+	p.clearPRReviewModal()
+	hosts := p.forgeHosts()
+	return func() tea.Msg {
+		review, err := detectForge(wt.Path, hosts).PullRequestReview(wt.Path)
+		return PRReviewLoadedMsg{WorkspaceName: wt.Name, Review: review, Err: err}
+	}
+}
+
+// handlePRReviewLoaded stores a loaded review if the modal still shows its
+// worktree.
+func (p *Plugin) handlePRReviewLoaded(msg PRReviewLoadedMsg) {
+	if p.viewMode != ViewModePRReview || p.prReviewWorktree == nil || p.prReviewWorktree.Name != msg.WorkspaceName {
+		return
+	}
+	p.prReviewLoading = false
+	if msg.Err != nil {
+		p.prReviewError = msg.Err.Error()
+		p.prReview = nil
+	} else {
+		p.prReviewError = ""
+		p.prReview = msg.Review
+		// Keep the selection across refreshes only while it still fits
+		if p.prReviewCursor >= len(msg.Review.Threads) {
+			p.prReviewCursor = max(len(msg.Review.Threads)-1, 0)
+		}
+		for i := range p.prReviewSelected {
+			if i >= len(msg.Review.Threads) {
+				delete(p.prReviewSelected, i)
+			}
+		}
+	}
+	p.clearPRReviewModal()
+}
+
+// closePRReview closes the PR review modal.
+func (p *Plugin) closePRReview() {
+	p.viewMode = ViewModeList
+	p.prReviewWorktree = nil
+	p.prReview = nil
+	p.prReviewLoading = false
+	p.prReviewError = ""
+	p.prReviewSelected = nil
+	p.clearPRReviewModal()
+}
+
+// handlePRReviewKeys handles keys in the PR review modal.
+func (p *Plugin) handlePRReviewKeys(msg tea.KeyMsg) tea.Cmd {
+	var threads []forge.ReviewThread
+	if p.prReview != nil {
+		threads = p.prReview.Threads
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		p.closePRReview()
+	case "j", "down":
+		if p.prReviewCursor < len(threads)-1 {
+			p.prReviewCursor++
+			p.clearPRReviewModal()
+		}
+	case "k", "up":
+		if p.prReviewCursor > 0 {
+			p.prReviewCursor--
+			p.clearPRReviewModal()
+		}
+	case "g", "home":
+		p.prReviewCursor = 0
+		p.clearPRReviewModal()
+	case "G", "end":
+		p.prReviewCursor = max(len(threads)-1, 0)
+		p.clearPRReviewModal()
+	case " ", "x":
+		if p.prReviewCursor < len(threads) {
+			if p.prReviewSelected[p.prReviewCursor] {
+				delete(p.prReviewSelected, p.prReviewCursor)
+			} else {
+				p.prReviewSelected[p.prReviewCursor] = true
+			}
+			p.clearPRReviewModal()
+		}
+	case "a":
+		// Select all unresolved threads, or clear the selection
+		if len(p.prReviewSelected) > 0 {
+			p.prReviewSelected = make(map[int]bool)
+		} else {
+			for i, t := range threads {
+				if !t.Resolved {
+					p.prReviewSelected[i] = true
+				}
+			}
+		}
+		p.clearPRReviewModal()
+	case "r":
+		return p.loadPRReview()
+	case "o":
+		if p.prReview != nil && p.prReview.URL != "" {
+			return openInBrowser(p.prReview.URL)
+		}
+	case "s":
+		return p.sendPRReviewToAgent()
+	}
+	return nil
+}
+
+// selectedReviewThreads returns the selected threads in display order, or
+// the thread under the cursor when none are selected.
+func (p *Plugin) selectedReviewThreads() []forge.ReviewThread {
+	if p.prReview == nil || len(p.prReview.Threads) == 0 {
+		return nil
+	}
+	var threads []forge.ReviewThread
+	for i, t := range p.prReview.Threads {
+		if p.prReviewSelected[i] {
+			threads = append(threads, t)
+		}
+	}
+	if len(threads) == 0 && p.prReviewCursor < len(p.prReview.Threads) {
+		threads = append(threads, p.prReview.Threads[p.prReviewCursor])
+	}
+	return threads
+}
+
+// sendPRReviewToAgent sends the selected review comments to the worktree's
+// agent as a prompt.
+func (p *Plugin) sendPRReviewToAgent() tea.Cmd {
+	wt := p.prReviewWorktree
+	threads := p.selectedReviewThreads()
+	if wt == nil || len(threads) == 0 {
+		return nil
+	}
+	if wt.Agent == nil {
+		return appmsg.ShowToast("No agent running (start one with s)", 2*time.Second)
+	}
+	prompt := reviewPrompt(p.prReview, threads)
+	p.closePRReview()
+	return p.SendText(wt, prompt)
+}
+
+// reviewPrompt builds an agent prompt asking it to address review threads.
+func reviewPrompt(review *forge.Review, threads []forge.ReviewThread) string {
+	var sb strings.Builder
+	what := "this review comment"
+	if len(threads) > 1 {
+		what = "these review comments"
+	}
+	fmt.Fprintf(&sb, "Please address %s on PR #%d", what, review.Number)
+	if review.Title != "" {
+		fmt.Fprintf(&sb, " (%s)", review.Title)
+	}
+	sb.WriteString(". Make the changes in this worktree.\n")
+
+	for i, t := range threads {
+		fmt.Fprintf(&sb, "\n%d. %s\n", i+1, reviewThreadLocation(t))
+		for _, c := range t.Comments {
+			body := strings.TrimSpace(c.Body)
+			body = strings.ReplaceAll(body, "\n", "\n   ")
+			fmt.Fprintf(&sb, "   @%s: %s\n", c.Author, body)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// reviewThreadLocation describes where a thread was left: "path:line",
+// "path" or "General comment".
+func reviewThreadLocation(t forge.ReviewThread) string {
+	switch {
+	case t.Path == "":
+		return "General comment"
+	case t.Line > 0:
+		return fmt.Sprintf("%s:%d", t.Path, t.Line)
+	default:
+		return t.Path
+	}
+}
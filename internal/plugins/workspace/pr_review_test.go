@@ -0,0 +1,141 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/forge"
+)
+
+func testReview() *forge.Review {
+	return &forge.Review{
+		Number: 12,
+		Title:  "Add cache",
+		Threads: []forge.ReviewThread{
+			{Comments: []forge.ReviewComment{{Author: "ann", Body: "Looks close"}}},
+			{Path: "cache.go", Line: 40, Comments: []forge.ReviewComment{
+				{Author: "ann", Body: "Handle eviction\nwhen full"},
+				{Author: "bo", Body: "Agreed"},
+			}},
+			{Path: "old.go", Outdated: true, Resolved: true, Comments: []forge.ReviewComment{{Author: "cy", Body: "Nit"}}},
+		},
+	}
+}
+
+func TestReviewThreadLocation(t *testing.T) {
+	tests := []struct {
+		thread forge.ReviewThread
+		want   string
+	}{
+		{forge.ReviewThread{}, "General comment"},
+		{forge.ReviewThread{Path: "a.go", Line: 3}, "a.go:3"},
+		{forge.ReviewThread{Path: "a.go"}, "a.go"},
+	}
+	for _, tt := range tests {
+		if got := reviewThreadLocation(tt.thread); got != tt.want {
+			t.Errorf("reviewThreadLocation(%+v) = %q, want %q", tt.thread, got, tt.want)
+		}
+	}
+}
+
+func TestReviewPrompt(t *testing.T) {
+	review := testReview()
+
+	got := reviewPrompt(review, review.Threads[1:2])
+	want := "Please address this review comment on PR #12 (Add cache). Make the changes in this worktree.\n" +
+		"\n1. cache.go:40\n" +
+		"   @ann: Handle eviction\n   when full\n" +
+		"   @bo: Agreed"
+	if got != want {
+		t.Errorf("reviewPrompt =\n%s\nwant\n%s", got, want)
+	}
+
+	got = reviewPrompt(review, review.Threads)
+	if !strings.HasPrefix(got, "Please address these review comments") || !strings.Contains(got, "3. old.go\n") {
+		t.Errorf("reviewPrompt(all) =\n%s", got)
+	}
+}
+
+func TestPRReviewSelection(t *testing.T) {
+	p := &Plugin{viewMode: ViewModePRReview}
+	p.prReview = testReview()
+	p.prReviewSelected = make(map[int]bool)
+	key := func(s string) {
+		p.handlePRReviewKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	// Nothing selected: the cursor's thread is used
+	key("j")
+	if got := p.selectedReviewThreads(); len(got) != 1 || got[0].Path != "cache.go" {
+		t.Fatalf("cursor fallback = %+v", got)
+	}
+
+	// "a" selects unresolved threads only, then clears
+	key("a")
+	if got := p.selectedReviewThreads(); len(got) != 2 || got[1].Path != "cache.go" {
+		t.Errorf("select all = %+v", got)
+	}
+	key("a")
+	if len(p.prReviewSelected) != 0 {
+		t.Errorf("selection not cleared: %v", p.prReviewSelected)
+	}
+
+	key("G")
+	key("x")
+	if got := p.selectedReviewThreads(); len(got) != 1 || got[0].Path != "old.go" {
+		t.Errorf("toggle = %+v", got)
+	}
+}
+
+func TestPRReviewLoadedKeepsSelectionInRange(t *testing.T) {
+	wt := &Worktree{Name: "feat"}
+	p := &Plugin{viewMode: ViewModePRReview, prReviewWorktree: wt, prReviewLoading: true}
+	p.prReviewCursor = 5
+	p.prReviewSelected = map[int]bool{1: true, 7: true}
+
+	p.handlePRReviewLoaded(PRReviewLoadedMsg{WorkspaceName: "other", Review: testReview()})
+	if p.prReview != nil {
+		t.Fatal("review for another worktree was applied")
+	}
+
+	p.handlePRReviewLoaded(PRReviewLoadedMsg{WorkspaceName: "feat", Review: testReview()})
+	if p.prReviewLoading || p.prReviewCursor != 2 {
+		t.Errorf("loading=%v cursor=%d", p.prReviewLoading, p.prReviewCursor)
+	}
+	if !p.prReviewSelected[1] || p.prReviewSelected[7] {
+		t.Errorf("selection = %v", p.prReviewSelected)
+	}
+}
+
+func TestReviewThreadWindow(t *testing.T) {
+	blocks := [][]string{make([]string, 3), make([]string, 3), make([]string, 3), make([]string, 3)}
+	tests := []struct {
+		cursor, budget     int
+		wantStart, wantEnd int
+	}{
+		{0, 100, 0, 4},
+		{0, 6, 0, 2},
+		{3, 6, 2, 4},
+		{2, 4, 2, 3},
+		{1, 1, 1, 2}, // The cursor's block is always shown
+	}
+	for _, tt := range tests {
+		start, end := reviewThreadWindow(blocks, tt.cursor, tt.budget)
+		if start != tt.wantStart || end != tt.wantEnd {
+			t.Errorf("reviewThreadWindow(cursor=%d, budget=%d) = %d,%d, want %d,%d",
+				tt.cursor, tt.budget, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestReviewHunkTail(t *testing.T) {
+	hunk := "@@ -1,5 +1,6 @@\n a\n b\n+c\n d\n-e\n"
+	got := reviewHunkTail(hunk, 3)
+	if strings.Join(got, "|") != "+c| d|-e" {
+		t.Errorf("reviewHunkTail = %q", got)
+	}
+	if reviewHunkTail("", 3) != nil {
+		t.Error("empty hunk should give no lines")
+	}
+}
@@ -0,0 +1,270 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/forge"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// prReviewHunkLines is how many trailing diff hunk lines are shown above a
+// thread; the hunk ends at the commented line.
+const prReviewHunkLines = 4
+
+// ensurePRReviewModal builds/rebuilds the PR review modal when needed.
+func (p *Plugin) ensurePRReviewModal() {
+	modalW := min(100, max(p.width-4, 1))
+	if p.prReviewModal != nil && p.prReviewModalWidth == modalW {
+		return
+	}
+	p.prReviewModalWidth = modalW
+
+	title := "PR Review"
+	if p.prReview != nil && p.prReview.Number > 0 {
+		title = fmt.Sprintf("PR #%d Review", p.prReview.Number)
+	}
+	p.prReviewModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.prReviewContentSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text(dimText("j/k move  space select  a all  s send to agent  o open  r refresh  esc close")))
+}
+
+// clearPRReviewModal invalidates the cached modal so it rebuilds next frame.
+func (p *Plugin) clearPRReviewModal() {
+	p.prReviewModal = nil
+	p.prReviewModalWidth = 0
+}
+
+// prReviewContentSection renders the review header, checks and threads.
+func (p *Plugin) prReviewContentSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.prReviewLoading && p.prReview == nil {
+			return modal.RenderedSection{Content: dimText("Loading review...")}
+		}
+		if p.prReviewError != "" {
+			errStyle := lipgloss.NewStyle().Foreground(styles.Error)
+			return modal.RenderedSection{Content: errStyle.Render(ansi.Wrap(p.prReviewError, contentWidth, ""))}
+		}
+		review := p.prReview
+		if review == nil {
+			return modal.RenderedSection{}
+		}
+
+		lines := p.renderPRReviewHeader(review, contentWidth)
+		lines = append(lines, "")
+		lines = append(lines, renderPRReviewChecks(review.Checks, contentWidth)...)
+		lines = append(lines, "")
+
+		if len(review.Threads) == 0 {
+			lines = append(lines, dimText("No review comments"))
+			return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+		}
+		header := fmt.Sprintf("Review comments (%d)", len(review.Threads))
+		if n := len(p.prReviewSelected); n > 0 {
+			header += fmt.Sprintf(" · %d selected", n)
+		}
+		lines = append(lines, styles.Title.Render(header))
+
+		// Show a window of threads around the cursor that fits the screen
+		budget := max(p.height-len(lines)-12, 6)
+		blocks := make([][]string, len(review.Threads))
+		for i, t := range review.Threads {
+			blocks[i] = p.renderReviewThread(i, t, contentWidth)
+		}
+		start, end := reviewThreadWindow(blocks, p.prReviewCursor, budget)
+		if start > 0 {
+			lines = append(lines, dimText(fmt.Sprintf("  ... %d more above", start)))
+		}
+		for i := start; i < end; i++ {
+			lines = append(lines, blocks[i]...)
+		}
+		if end < len(blocks) {
+			lines = append(lines, dimText(fmt.Sprintf("  ... %d more below", len(blocks)-end)))
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+// reviewThreadWindow picks the range of thread blocks to show so that the
+// cursor's block is visible within budget lines.
+func reviewThreadWindow(blocks [][]string, cursor, budget int) (start, end int) {
+	if cursor >= len(blocks) {
+		cursor = len(blocks) - 1
+	}
+	start, end = cursor, cursor+1
+	used := len(blocks[cursor])
+	// Grow downward first, then upward
+	for end < len(blocks) && used+len(blocks[end]) <= budget {
+		used += len(blocks[end])
+		end++
+	}
+	for start > 0 && used+len(blocks[start-1]) <= budget {
+		start--
+		used += len(blocks[start])
+	}
+	return start, end
+}
+
+// renderPRReviewHeader renders the PR title, state and review decision.
+func (p *Plugin) renderPRReviewHeader(review *forge.Review, width int) []string {
+	title := ansi.Truncate(review.Title, width, "…")
+	parts := []string{review.State}
+	switch review.Decision {
+	case forge.DecisionApproved:
+		parts = append(parts, lipgloss.NewStyle().Foreground(styles.Success).Render("Approved"))
+	case forge.DecisionChangesRequested:
+		parts = append(parts, lipgloss.NewStyle().Foreground(styles.Error).Render("Changes requested"))
+	case forge.DecisionReviewRequired:
+		parts = append(parts, lipgloss.NewStyle().Foreground(styles.Warning).Render("Review required"))
+	}
+	if p.prReviewLoading {
+		parts = append(parts, dimText("refreshing..."))
+	}
+	lines := []string{styles.Title.Render(title), strings.Join(parts, dimText(" · "))}
+	if review.URL != "" {
+		lines = append(lines, dimText(ansi.Truncate(review.URL, width, "…")))
+	}
+	return lines
+}
+
+// checkIcon returns the status glyph and color for a check state.
+func checkIcon(state string) string {
+	switch state {
+	case forge.CheckPass:
+		return lipgloss.NewStyle().Foreground(styles.Success).Render("✓")
+	case forge.CheckFail:
+		return lipgloss.NewStyle().Foreground(styles.Error).Render("✗")
+	case forge.CheckSkipped:
+		return dimText("-")
+	default:
+		return lipgloss.NewStyle().Foreground(styles.Warning).Render("●")
+	}
+}
+
+// renderPRReviewChecks renders a summary of the checks and lists the ones
+// that didn't pass.
+func renderPRReviewChecks(checks []forge.Check, width int) []string {
+	if len(checks) == 0 {
+		return []string{dimText("Checks: none reported")}
+	}
+	counts := make(map[string]int)
+	for _, c := range checks {
+		counts[c.State]++
+	}
+	var summary []string
+	for _, s := range []struct{ state, label string }{
+		{forge.CheckPass, "passed"},
+		{forge.CheckFail, "failed"},
+		{forge.CheckPending, "pending"},
+		{forge.CheckSkipped, "skipped"},
+	} {
+		if n := counts[s.state]; n > 0 {
+			summary = append(summary, fmt.Sprintf("%s %d %s", checkIcon(s.state), n, s.label))
+		}
+	}
+	lines := []string{"Checks: " + strings.Join(summary, "  ")}
+	for _, c := range checks {
+		if c.State == forge.CheckFail || c.State == forge.CheckPending {
+			lines = append(lines, "  "+checkIcon(c.State)+" "+ansi.Truncate(c.Name, width-4, "…"))
+		}
+	}
+	return lines
+}
+
+// renderReviewThread renders one thread: a selection marker and location,
+// the diff lines it was left on, and its comments.
+func (p *Plugin) renderReviewThread(idx int, t forge.ReviewThread, width int) []string {
+	marker := "[ ]"
+	if p.prReviewSelected[idx] {
+		marker = "[x]"
+	}
+	prefix := "  "
+	if idx == p.prReviewCursor {
+		prefix = "> "
+	}
+	location := reviewThreadLocation(t)
+	var tags []string
+	if t.Resolved {
+		tags = append(tags, "resolved")
+	}
+	if t.Outdated {
+		tags = append(tags, "outdated")
+	}
+	head := prefix + marker + " " + location
+	if len(tags) > 0 {
+		head += "  " + dimText("("+strings.Join(tags, ", ")+")")
+	}
+	if idx == p.prReviewCursor {
+		head = lipgloss.NewStyle().Foreground(styles.Primary).Render(head)
+	}
+	lines := []string{head}
+
+	indent := "      "
+	bodyW := max(width-len(indent)-2, 10)
+	if hunk := reviewHunkTail(t.DiffHunk, prReviewHunkLines); len(hunk) > 0 {
+		for _, l := range hunk {
+			l = ansi.Truncate(strings.ReplaceAll(l, "\t", "    "), bodyW, "…")
+			switch {
+			case strings.HasPrefix(l, "+"):
+				l = styles.DiffAdd.Render(l)
+			case strings.HasPrefix(l, "-"):
+				l = styles.DiffRemove.Render(l)
+			default:
+				l = styles.DiffContext.Render(l)
+			}
+			lines = append(lines, indent+dimText("│ ")+l)
+		}
+	}
+	for i, c := range t.Comments {
+		author := "@" + c.Author
+		if i > 0 {
+			author = "↳ " + author
+		}
+		if age := formatRelativeTime(c.CreatedAt); age != "" {
+			author += dimText(" " + age)
+		}
+		lines = append(lines, indent+styles.Subtitle.Render(author))
+		body := ansi.Wrap(strings.TrimSpace(c.Body), bodyW, "")
+		for _, l := range strings.Split(body, "\n") {
+			lines = append(lines, indent+"  "+l)
+		}
+	}
+	return lines
+}
+
+// reviewHunkTail returns the last n lines of a diff hunk, skipping its
+// @@ header.
+func reviewHunkTail(hunk string, n int) []string {
+	if hunk == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(hunk, "\n"), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "@@") {
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// renderPRReviewModal renders the PR review modal over the list view.
+func (p *Plugin) renderPRReviewModal(width, height int) string {
+	background := p.renderListView(width, height)
+
+	p.ensurePRReviewModal()
+	if p.prReviewModal == nil {
+		return background
+	}
+
+	modalContent := p.prReviewModal.Render(width, height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, width, height)
+}
@@ -0,0 +1,11 @@
+package workspace
+
+import "github.com/marcus/sidecar/internal/plugins/gitstatus"
+
+// init shares the agents with a print mode with the git plugin, which drafts
+// commit messages with them but can't import this package.
+func init() {
+	for agentType, flag := range PrintModeFlags {
+		gitstatus.RegisterPrintModeAgent(string(agentType), AgentCommands[agentType], flag)
+	}
+}
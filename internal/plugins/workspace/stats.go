@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/plugins/gitstatus"
 )
 
 // loadStats returns a command to load git stats for a worktree.
@@ -93,22 +94,11 @@ func getDiffStats(workdir string, stats *GitStats) error {
 
 // getAheadBehind computes ahead/behind counts from upstream.
 func getAheadBehind(workdir string, stats *GitStats) error {
-	cmd := exec.Command("git", "rev-list", "--left-right", "--count", "@{upstream}...HEAD")
-	cmd.Dir = workdir
-	output, err := cmd.Output()
+	ahead, behind, err := gitstatus.CountAheadBehind(workdir, "@{upstream}", "HEAD")
 	if err != nil {
 		return err
 	}
-
-	parts := strings.Fields(strings.TrimSpace(string(output)))
-	if len(parts) == 2 {
-		if n, err := strconv.Atoi(parts[0]); err == nil {
-			stats.Behind = n
-		}
-		if n, err := strconv.Atoi(parts[1]); err == nil {
-			stats.Ahead = n
-		}
-	}
-
+	stats.Ahead = ahead
+	stats.Behind = behind
 	return nil
 }
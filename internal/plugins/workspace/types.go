@@ -38,6 +38,7 @@ const (
 	ViewModeFilePicker                     // Diff file picker modal
 	ViewModeInteractive                    // Interactive mode (tmux input passthrough)
 	ViewModeFetchPR                        // Fetch remote PR modal
+	ViewModePRReview                       // PR review comments and checks modal
 )
 
 // FocusPane represents which pane is active in the split view.
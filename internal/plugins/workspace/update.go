@@ -251,6 +251,15 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 		}
 
+	case PRReviewLoadedMsg:
+		p.handlePRReviewLoaded(msg)
+
+	case SendTextResultMsg:
+		if msg.Err != nil {
+			return p, appmsg.ShowToast("Send to agent failed: "+msg.Err.Error(), 3*time.Second)
+		}
+		return p, appmsg.ShowToast("Sent to agent", 2*time.Second)
+
 	case FetchPRListMsg:
 		p.fetchPRLoading = false
 		if msg.Err != nil {
@@ -1264,6 +1273,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				p.mergeState.CleanupResults.PullErrorSummary = summary
 				p.mergeState.CleanupResults.PullErrorFull = full
 				p.mergeState.CleanupResults.BranchDiverged = diverged
+				if len(msg.Conflicts) > 0 {
+					return p, p.resolveConflictsInGitTab(msg.Conflicts)
+				}
 			}
 		}
 
@@ -1283,6 +1295,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				p.mergeState.CleanupResults.PullErrorSummary = summary
 				p.mergeState.CleanupResults.PullErrorFull = full
 				p.mergeState.CleanupResults.BranchDiverged = diverged
+				if len(msg.Conflicts) > 0 {
+					return p, p.resolveConflictsInGitTab(msg.Conflicts)
+				}
 			}
 		}
 
@@ -79,6 +79,8 @@ func (p *Plugin) View(width, height int) string {
 		return p.renderRenameShellModal(width, height)
 	case ViewModeFetchPR:
 		return p.renderFetchPRModal(width, height)
+	case ViewModePRReview:
+		return p.renderPRReviewModal(width, height)
 	case ViewModeFilePicker:
 		background := p.renderListView(width, height)
 		return p.renderFilePickerModal(background)
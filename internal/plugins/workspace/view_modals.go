@@ -850,7 +850,7 @@ func (p *Plugin) mergeMethodHintsSection() modal.Section {
 		var sb strings.Builder
 
 		if p.mergeState.MergeMethodOption == 0 {
-			sb.WriteString(dimText("Push to origin and create a GitHub PR for review"))
+			sb.WriteString(dimText("Push to origin and create a pull request for review"))
 		} else {
 			sb.WriteString(dimText(fmt.Sprintf("Merge directly to '%s' without PR", p.mergeState.TargetBranch)))
 			sb.WriteString("\n")
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 // State holds persistent user preferences.
@@ -48,6 +49,14 @@ type FileBrowserState struct {
 	ShowIgnored   *bool                 `json:"showIgnored,omitempty"`   // Whether to show git-ignored files (nil = default true)
 	Tabs          []FileBrowserTabState `json:"tabs,omitempty"`
 	ActiveTab     int                   `json:"activeTab,omitempty"`
+	Visits        []FileVisitState      `json:"visits,omitempty"` // Opened files, for frecency ranking
+}
+
+// FileVisitState records how often and how recently a file was opened.
+type FileVisitState struct {
+	Path  string    `json:"path"`
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
 }
 
 // WorkspaceState holds persistent workspace plugin state.
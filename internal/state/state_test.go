@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestInit(t *testing.T) {
@@ -754,3 +755,37 @@ func TestSetLineWrapEnabled_InitializesNilState(t *testing.T) {
 		t.Errorf("LineWrapEnabled = %v, want true", current.LineWrapEnabled)
 	}
 }
+
+func TestFileBrowserState_VisitsRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalPath := path
+	originalCurrent := current
+	defer func() {
+		path = originalPath
+		current = originalCurrent
+	}()
+
+	path = filepath.Join(tmpDir, "state.json")
+	current = nil
+
+	last := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fbState := FileBrowserState{
+		Visits: []FileVisitState{{Path: "src/index.ts", Count: 3, Last: last}},
+	}
+	if err := SetFileBrowserState("/project", fbState); err != nil {
+		t.Fatalf("SetFileBrowserState() failed: %v", err)
+	}
+
+	current = nil
+	if err := Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	visits := GetFileBrowserState("/project").Visits
+	if len(visits) != 1 || visits[0].Path != "src/index.ts" || visits[0].Count != 3 || !visits[0].Last.Equal(last) {
+		t.Errorf("round-trip Visits = %+v", visits)
+	}
+	if got := GetFileBrowserState("/other").Visits; got != nil {
+		t.Errorf("Visits for another project = %+v, want nil", got)
+	}
+}